@@ -179,4 +179,5 @@ export interface FeatureToggles {
   expressionParser?: boolean;
   groupByVariable?: boolean;
   alertingUpgradeDryrunOnStart?: boolean;
+  dashboardJSONNormalization?: boolean;
 }
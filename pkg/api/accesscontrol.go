@@ -577,6 +577,32 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		Grants: []string{"Admin"},
 	}
 
+	dashboardsSensitiveReaderRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:dashboards.sensitive:reader",
+			DisplayName: "Sensitive fields reader",
+			Description: "Read the decrypted sensitive fields of all dashboards.",
+			Group:       "Dashboards",
+			Permissions: []ac.Permission{
+				{Action: dashboards.ActionDashboardsSensitiveRead, Scope: dashboards.ScopeDashboardsAll},
+			},
+		},
+		Grants: []string{"Admin"},
+	}
+
+	foldersReadOnlyWriterRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:folders.readonly:writer",
+			DisplayName: "Read-only writer",
+			Description: "Set the read-only flag of all folders and save the dashboards of read-only folders.",
+			Group:       "Folders",
+			Permissions: []ac.Permission{
+				{Action: dashboards.ActionFoldersReadOnlyWrite, Scope: dashboards.ScopeFoldersAll},
+			},
+		},
+		Grants: []string{"Admin"},
+	}
+
 	featuremgmtReaderRole := ac.RoleRegistration{
 		Role: ac.RoleDTO{
 			Name:        "fixed:featuremgmt:reader",
@@ -609,7 +635,7 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		annotationsReaderRole, dashboardAnnotationsWriterRole, annotationsWriterRole,
 		dashboardsCreatorRole, dashboardsReaderRole, dashboardsWriterRole,
 		foldersCreatorRole, foldersReaderRole, generalFolderReaderRole, foldersWriterRole, apikeyReaderRole, apikeyWriterRole,
-		publicDashboardsWriterRole, featuremgmtReaderRole, featuremgmtWriterRole, libraryPanelsCreatorRole,
+		publicDashboardsWriterRole, dashboardsSensitiveReaderRole, foldersReadOnlyWriterRole, featuremgmtReaderRole, featuremgmtWriterRole, libraryPanelsCreatorRole,
 		libraryPanelsReaderRole, libraryPanelsWriterRole, libraryPanelsGeneralReaderRole, libraryPanelsGeneralWriterRole}
 
 	if hs.Features.IsEnabled(context.Background(), featuremgmt.FlagAnnotationPermissionUpdate) {
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func (hs *HTTPServer) AdminGetRenderSigningKeys(c *contextmodel.ReqContext) response.Response {
+	keys, err := hs.RenderService.GetSigningKeys(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get the render signing keys", err)
+	}
+
+	return response.JSON(http.StatusOK, keys)
+}
+
+func (hs *HTTPServer) AdminRotateRenderSigningKey(c *contextmodel.ReqContext) response.Response {
+	keys, err := hs.RenderService.RotateSigningKey(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to rotate the render signing key", err)
+	}
+
+	return response.JSON(http.StatusOK, keys)
+}
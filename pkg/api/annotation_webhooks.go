@@ -0,0 +1,545 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	annotationWebhookNamespace = "annotation_webhook"
+	// maxAnnotationWebhookEventSize limits the size of the events sent to the webhooks
+	maxAnnotationWebhookEventSize = 1 << 20
+
+	AnnotationWebhookFormatGeneric = "generic"
+	AnnotationWebhookFormatGitHub  = "github"
+
+	annotationWebhookTokenHeader = "X-Grafana-Webhook-Token"
+	gitHubSignatureHeader        = "X-Hub-Signature-256"
+	gitHubEventHeader            = "X-GitHub-Event"
+)
+
+var (
+	ErrInvalidAnnotationWebhook      = errutil.BadRequest("annotations.invalid-webhook")
+	ErrAnnotationWebhookNotFound     = errutil.NotFound("annotations.webhook-not-found", errutil.WithPublicMessage("Annotation webhook not found"))
+	ErrAnnotationWebhookUnauthorized = errutil.Unauthorized("annotations.webhook-unauthorized", errutil.WithPublicMessage("Invalid or missing webhook token"))
+	ErrInvalidAnnotationWebhookEvent = errutil.BadRequest("annotations.invalid-webhook-event")
+)
+
+// AnnotationWebhook converts the events of CI/CD systems sent to its URL into region annotations of the organization,
+// or of a dashboard.
+type AnnotationWebhook struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+	// Format of the events, generic or github
+	Format string `json:"format"`
+	// DashboardUID is the dashboard of the annotations, they are organization annotations when it's empty
+	DashboardUID string `json:"dashboardUID,omitempty"`
+	// Tags are added to all the annotations
+	Tags     []string                   `json:"tags"`
+	TagRules []AnnotationWebhookTagRule `json:"tagRules"`
+	Created  time.Time                  `json:"created"`
+}
+
+// AnnotationWebhookTagRule adds a tag to the annotation of the events that have a value at Field
+type AnnotationWebhookTagRule struct {
+	// Field is the dot separated path of the value in the event, for example deployment.environment
+	Field string `json:"field"`
+	// Match is an optional regular expression the value must match
+	Match string `json:"match,omitempty"`
+	// Tag is the tag to add, ${value} is replaced by the value
+	Tag string `json:"tag"`
+}
+
+type storedAnnotationWebhook struct {
+	AnnotationWebhook
+	// Token is encrypted, the token is needed in clear to check the signature of the GitHub events
+	Token []byte `json:"token"`
+}
+
+// CreateAnnotationWebhookResponse is returned once, when the webhook is created
+type CreateAnnotationWebhookResponse struct {
+	AnnotationWebhook
+	// Token authenticates the events, it's sent in the X-Grafana-Webhook-Token header or used as
+	// the secret of the GitHub webhook
+	Token string `json:"token"`
+	// URL is the URL the events are sent to
+	URL string `json:"url"`
+}
+
+func validateAnnotationWebhook(webhook *AnnotationWebhook) error {
+	webhook.Name = strings.TrimSpace(webhook.Name)
+	if webhook.Name == "" {
+		return ErrInvalidAnnotationWebhook.Errorf("name is required")
+	}
+	if webhook.Format == "" {
+		webhook.Format = AnnotationWebhookFormatGeneric
+	}
+	if webhook.Format != AnnotationWebhookFormatGeneric && webhook.Format != AnnotationWebhookFormatGitHub {
+		return ErrInvalidAnnotationWebhook.Errorf("unsupported format %q", webhook.Format)
+	}
+	for _, rule := range webhook.TagRules {
+		if rule.Field == "" || rule.Tag == "" {
+			return ErrInvalidAnnotationWebhook.Errorf("tag rules require a field and a tag")
+		}
+		if _, err := regexp.Compile(rule.Match); err != nil {
+			return ErrInvalidAnnotationWebhook.Errorf("invalid match expression %q of the tag rule of %s: %w", rule.Match, rule.Field, err)
+		}
+	}
+	return nil
+}
+
+func (hs *HTTPServer) getAnnotationWebhook(ctx context.Context, orgID int64, uid string) (*storedAnnotationWebhook, error) {
+	value, ok, err := hs.kvStore.Get(ctx, orgID, annotationWebhookNamespace, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrAnnotationWebhookNotFound.Errorf("no annotation webhook %s", uid)
+	}
+	webhook := &storedAnnotationWebhook{}
+	if err := json.Unmarshal([]byte(value), webhook); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation webhook %s: %w", uid, err)
+	}
+	return webhook, nil
+}
+
+func (hs *HTTPServer) saveAnnotationWebhook(ctx context.Context, orgID int64, webhook *storedAnnotationWebhook) error {
+	value, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+	return hs.kvStore.Set(ctx, orgID, annotationWebhookNamespace, webhook.UID, string(value))
+}
+
+// findAnnotationWebhookOrg returns the organization of the webhook, the events aren't sent by a signed in user
+func (hs *HTTPServer) findAnnotationWebhookOrg(ctx context.Context, uid string) (int64, error) {
+	keys, err := hs.kvStore.Keys(ctx, kvstore.AllOrganizations, annotationWebhookNamespace, uid)
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		if key.Key == uid {
+			return key.OrgId, nil
+		}
+	}
+	return 0, ErrAnnotationWebhookNotFound.Errorf("no annotation webhook %s", uid)
+}
+
+// authenticateAnnotationWebhookEvent accepts the token in the X-Grafana-Webhook-Token header,
+// or a GitHub signature of the body computed with the token
+func authenticateAnnotationWebhookEvent(r *http.Request, body []byte, token string) bool {
+	if header := r.Header.Get(annotationWebhookTokenHeader); header != "" {
+		return subtle.ConstantTimeCompare([]byte(header), []byte(token)) == 1
+	}
+	signature, ok := strings.CutPrefix(r.Header.Get(gitHubSignatureHeader), "sha256=")
+	if !ok {
+		return false
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// annotationWebhookValue returns the value at the dot separated path of the event
+func annotationWebhookValue(event map[string]any, path string) (string, bool) {
+	var value any = event
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if value, ok = m[key]; !ok {
+			return "", false
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+func annotationWebhookTime(event map[string]any, path string) (int64, error) {
+	value, ok := annotationWebhookValue(event, path)
+	if !ok {
+		return 0, nil
+	}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return ms, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, ErrInvalidAnnotationWebhookEvent.Errorf("invalid time %q at %s", value, path)
+	}
+	return t.UnixMilli(), nil
+}
+
+// annotationFromWebhookEvent converts an event into an annotation, it returns nil for the events that
+// aren't deployments, such as the ping events of GitHub
+func annotationFromWebhookEvent(webhook *AnnotationWebhook, r *http.Request, event map[string]any) (*annotations.Item, error) {
+	item := &annotations.Item{}
+	var err error
+	switch webhook.Format {
+	case AnnotationWebhookFormatGitHub:
+		item, err = annotationFromGitHubEvent(r.Header.Get(gitHubEventHeader), event)
+	default:
+		item, err = annotationFromGenericEvent(event)
+	}
+	if err != nil || item == nil {
+		return nil, err
+	}
+
+	tags := append(append([]string{}, webhook.Tags...), item.Tags...)
+	for _, rule := range webhook.TagRules {
+		value, ok := annotationWebhookValue(event, rule.Field)
+		if !ok {
+			continue
+		}
+		if rule.Match != "" {
+			// the expressions are validated when the webhook is saved
+			if re, err := regexp.Compile(rule.Match); err != nil || !re.MatchString(value) {
+				continue
+			}
+		}
+		tags = append(tags, strings.ReplaceAll(rule.Tag, "${value}", value))
+	}
+	item.Tags = make([]string, 0, len(tags))
+	seen := map[string]struct{}{}
+	for _, tag := range tags {
+		if _, ok := seen[tag]; ok || tag == "" {
+			continue
+		}
+		seen[tag] = struct{}{}
+		item.Tags = append(item.Tags, tag)
+	}
+
+	if item.Epoch == 0 {
+		item.Epoch = time.Now().UnixMilli()
+	}
+	if item.EpochEnd < item.Epoch {
+		item.EpochEnd = item.Epoch
+	}
+	return item, nil
+}
+
+// annotationFromGenericEvent reads the text, time, timeEnd and tags fields of the event,
+// the times are epoch milliseconds or RFC 3339 strings
+func annotationFromGenericEvent(event map[string]any) (*annotations.Item, error) {
+	text, ok := annotationWebhookValue(event, "text")
+	if !ok {
+		return nil, ErrInvalidAnnotationWebhookEvent.Errorf("text field should not be empty")
+	}
+	item := &annotations.Item{Text: text}
+	var err error
+	if item.Epoch, err = annotationWebhookTime(event, "time"); err != nil {
+		return nil, err
+	}
+	if item.EpochEnd, err = annotationWebhookTime(event, "timeEnd"); err != nil {
+		return nil, err
+	}
+	if tags, ok := event["tags"].([]any); ok {
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				item.Tags = append(item.Tags, s)
+			}
+		}
+	}
+	return item, nil
+}
+
+// annotationFromGitHubEvent converts the deployment and deployment_status events of GitHub, the annotation of
+// a deployment status spans from the creation of the deployment to the status
+func annotationFromGitHubEvent(eventType string, event map[string]any) (*annotations.Item, error) {
+	if eventType != "deployment" && eventType != "deployment_status" {
+		return nil, nil
+	}
+
+	repository, _ := annotationWebhookValue(event, "repository.full_name")
+	ref, _ := annotationWebhookValue(event, "deployment.ref")
+	environment, _ := annotationWebhookValue(event, "deployment.environment")
+	start, err := annotationWebhookTime(event, "deployment.created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	item := &annotations.Item{
+		Epoch: start,
+		Tags:  []string{"deployment", "environment:" + environment},
+	}
+	if eventType == "deployment" {
+		item.Text = fmt.Sprintf("Deployment of %s %s to %s created", repository, ref, environment)
+		return item, nil
+	}
+
+	state, _ := annotationWebhookValue(event, "deployment_status.state")
+	if item.EpochEnd, err = annotationWebhookTime(event, "deployment_status.created_at"); err != nil {
+		return nil, err
+	}
+	item.Text = fmt.Sprintf("Deployment of %s %s to %s: %s", repository, ref, environment, state)
+	if description, ok := annotationWebhookValue(event, "deployment_status.description"); ok {
+		item.Text += "\n" + description
+	}
+	item.Tags = append(item.Tags, "state:"+state)
+	return item, nil
+}
+
+// swagger:route GET /annotations/webhooks annotations getAnnotationWebhooks
+//
+// Get the annotation webhooks of the organization.
+//
+// Responses:
+// 200: getAnnotationWebhooksResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetAnnotationWebhooks(c *contextmodel.ReqContext) response.Response {
+	orgID := c.SignedInUser.GetOrgID()
+	keys, err := hs.kvStore.Keys(c.Req.Context(), orgID, annotationWebhookNamespace, "")
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get annotation webhooks", err)
+	}
+	webhooks := make([]AnnotationWebhook, 0, len(keys))
+	for _, key := range keys {
+		webhook, err := hs.getAnnotationWebhook(c.Req.Context(), orgID, key.Key)
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotation webhooks", err)
+		}
+		webhooks = append(webhooks, webhook.AnnotationWebhook)
+	}
+	return response.JSON(http.StatusOK, webhooks)
+}
+
+// swagger:route POST /annotations/webhooks annotations createAnnotationWebhook
+//
+// Create an annotation webhook.
+//
+// The response includes the token of the webhook, it can't be retrieved later.
+//
+// Responses:
+// 200: createAnnotationWebhookResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) CreateAnnotationWebhook(c *contextmodel.ReqContext) response.Response {
+	webhook := AnnotationWebhook{}
+	if err := web.Bind(c.Req, &webhook); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if err := validateAnnotationWebhook(&webhook); err != nil {
+		return response.Err(err)
+	}
+
+	token, err := util.GetRandomString(32)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create annotation webhook", err)
+	}
+	encrypted, err := hs.SecretsService.Encrypt(c.Req.Context(), []byte(token), secrets.WithoutScope())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create annotation webhook", err)
+	}
+	webhook.UID = util.GenerateShortUID()
+	webhook.Created = time.Now()
+	if err := hs.saveAnnotationWebhook(c.Req.Context(), c.SignedInUser.GetOrgID(), &storedAnnotationWebhook{AnnotationWebhook: webhook, Token: encrypted}); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create annotation webhook", err)
+	}
+
+	return response.JSON(http.StatusOK, CreateAnnotationWebhookResponse{
+		AnnotationWebhook: webhook,
+		Token:             token,
+		URL:               strings.TrimSuffix(hs.Cfg.AppURL, "/") + "/api/annotations/webhooks/" + webhook.UID + "/events",
+	})
+}
+
+// swagger:route PUT /annotations/webhooks/{uid} annotations updateAnnotationWebhook
+//
+// Update the name, format, dashboard and tags of an annotation webhook, the token doesn't change.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateAnnotationWebhook(c *contextmodel.ReqContext) response.Response {
+	update := AnnotationWebhook{}
+	if err := web.Bind(c.Req, &update); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if err := validateAnnotationWebhook(&update); err != nil {
+		return response.Err(err)
+	}
+
+	orgID := c.SignedInUser.GetOrgID()
+	webhook, err := hs.getAnnotationWebhook(c.Req.Context(), orgID, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update annotation webhook", err)
+	}
+	update.UID, update.Created = webhook.UID, webhook.Created
+	webhook.AnnotationWebhook = update
+	if err := hs.saveAnnotationWebhook(c.Req.Context(), orgID, webhook); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to update annotation webhook", err)
+	}
+	return response.Success("Annotation webhook updated")
+}
+
+// swagger:route DELETE /annotations/webhooks/{uid} annotations deleteAnnotationWebhook
+//
+// Delete an annotation webhook, the annotations it created are kept.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteAnnotationWebhook(c *contextmodel.ReqContext) response.Response {
+	orgID := c.SignedInUser.GetOrgID()
+	uid := web.Params(c.Req)[":uid"]
+	if _, err := hs.getAnnotationWebhook(c.Req.Context(), orgID, uid); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete annotation webhook", err)
+	}
+	if err := hs.kvStore.Del(c.Req.Context(), orgID, annotationWebhookNamespace, uid); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to delete annotation webhook", err)
+	}
+	return response.Success("Annotation webhook deleted")
+}
+
+// swagger:route POST /annotations/webhooks/{uid}/events annotations postAnnotationWebhookEvent
+//
+// Create an annotation from a CI/CD event.
+//
+// The request is authenticated by the token of the webhook in the X-Grafana-Webhook-Token header,
+// or by the X-Hub-Signature-256 header of GitHub for the webhooks in the github format.
+// Events that aren't deployments are ignored.
+//
+// Responses:
+// 200: postAnnotationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) PostAnnotationWebhookEvent(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	uid := web.Params(c.Req)[":uid"]
+	orgID, err := hs.findAnnotationWebhookOrg(ctx, uid)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotation webhook", err)
+	}
+	webhook, err := hs.getAnnotationWebhook(ctx, orgID, uid)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotation webhook", err)
+	}
+	token, err := hs.SecretsService.Decrypt(ctx, webhook.Token)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get annotation webhook", err)
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Resp, c.Req.Body, maxAnnotationWebhookEventSize))
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to read event", err)
+	}
+	if !authenticateAnnotationWebhookEvent(c.Req, body, string(token)) {
+		return response.Err(ErrAnnotationWebhookUnauthorized.Errorf("invalid token for annotation webhook %s", uid))
+	}
+
+	event := map[string]any{}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return response.Err(ErrInvalidAnnotationWebhookEvent.Errorf("invalid event: %w", err))
+	}
+	item, err := annotationFromWebhookEvent(&webhook.AnnotationWebhook, c.Req, event)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Failed to convert event", err)
+	}
+	if item == nil {
+		return response.Success("Event ignored")
+	}
+
+	item.OrgID = orgID
+	if webhook.DashboardUID != "" {
+		dash, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: orgID, UID: webhook.DashboardUID})
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the dashboard of the annotation webhook", err)
+		}
+		item.DashboardID = dash.ID // nolint:staticcheck
+	}
+	if err := hs.annotationsRepo.Save(ctx, item); err != nil {
+		if errors.Is(err, annotations.ErrTimerangeMissing) {
+			return response.Error(http.StatusBadRequest, "Failed to save annotation", err)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save annotation", err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"message": "Annotation added",
+		"id":      item.ID,
+	})
+}
+
+// swagger:parameters updateAnnotationWebhook
+type UpdateAnnotationWebhookParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body AnnotationWebhook
+}
+
+// swagger:parameters createAnnotationWebhook
+type CreateAnnotationWebhookParams struct {
+	// in:body
+	// required:true
+	Body AnnotationWebhook
+}
+
+// swagger:parameters deleteAnnotationWebhook postAnnotationWebhookEvent
+type AnnotationWebhookParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response getAnnotationWebhooksResponse
+type GetAnnotationWebhooksResponse struct {
+	// in: body
+	Body []AnnotationWebhook `json:"body"`
+}
+
+// swagger:response createAnnotationWebhookResponse
+type CreateAnnotationWebhookResponseBody struct {
+	// in: body
+	Body CreateAnnotationWebhookResponse `json:"body"`
+}
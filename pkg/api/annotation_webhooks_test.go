@@ -0,0 +1,162 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations/annotationstest"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAnnotationFromWebhookEvent(t *testing.T) {
+	webhook := &AnnotationWebhook{
+		Format: AnnotationWebhookFormatGeneric,
+		Tags:   []string{"deploy"},
+		TagRules: []AnnotationWebhookTagRule{
+			{Field: "service.name", Tag: "service:${value}"},
+			{Field: "env", Match: "^prod", Tag: "production"},
+			{Field: "missing", Tag: "never"},
+		},
+	}
+
+	t.Run("should convert generic events and apply the tag rules", func(t *testing.T) {
+		event := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"text": "Deployed v1.2",
+			"time": 1000,
+			"timeEnd": "1970-01-01T00:00:05Z",
+			"tags": ["deploy", "v1.2"],
+			"service": {"name": "api"},
+			"env": "production"
+		}`), &event))
+
+		item, err := annotationFromWebhookEvent(webhook, &http.Request{}, event)
+		require.NoError(t, err)
+		assert.Equal(t, "Deployed v1.2", item.Text)
+		assert.Equal(t, int64(1000), item.Epoch)
+		assert.Equal(t, int64(5000), item.EpochEnd)
+		assert.Equal(t, []string{"deploy", "v1.2", "service:api", "production"}, item.Tags)
+	})
+
+	t.Run("should reject generic events without text", func(t *testing.T) {
+		_, err := annotationFromWebhookEvent(webhook, &http.Request{}, map[string]any{"time": 1000.0})
+		assert.ErrorIs(t, err, ErrInvalidAnnotationWebhookEvent)
+	})
+
+	t.Run("should convert GitHub deployment statuses into regions", func(t *testing.T) {
+		event := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"deployment_status": {"state": "success", "created_at": "2023-06-01T10:05:00Z"},
+			"deployment": {"ref": "main", "environment": "production", "created_at": "2023-06-01T10:00:00Z"},
+			"repository": {"full_name": "grafana/grafana"}
+		}`), &event))
+		req := &http.Request{Header: http.Header{}}
+		req.Header.Set(gitHubEventHeader, "deployment_status")
+
+		item, err := annotationFromWebhookEvent(&AnnotationWebhook{Format: AnnotationWebhookFormatGitHub}, req, event)
+		require.NoError(t, err)
+		assert.Equal(t, "Deployment of grafana/grafana main to production: success", item.Text)
+		assert.Equal(t, int64(300000), item.EpochEnd-item.Epoch)
+		assert.Equal(t, []string{"deployment", "environment:production", "state:success"}, item.Tags)
+
+		req.Header.Set(gitHubEventHeader, "ping")
+		item, err = annotationFromWebhookEvent(&AnnotationWebhook{Format: AnnotationWebhookFormatGitHub}, req, event)
+		require.NoError(t, err)
+		assert.Nil(t, item)
+	})
+}
+
+func TestHTTPServer_AnnotationWebhooks(t *testing.T) {
+	repo := annotationstest.NewFakeAnnotationsRepo()
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.Cfg.AppURL = "http://localhost:3000/"
+		hs.kvStore = kvstore.NewFakeKVStore()
+		hs.SecretsService = fakes.NewFakeSecretsService()
+		hs.annotationsRepo = repo
+	})
+
+	create := func(t *testing.T, body string) CreateAnnotationWebhookResponse {
+		t.Helper()
+		req := server.NewRequest(http.MethodPost, "/api/annotations/webhooks", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsWrite}})))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var webhook CreateAnnotationWebhookResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&webhook))
+		require.NoError(t, res.Body.Close())
+		return webhook
+	}
+	send := func(t *testing.T, url string, body string, headers map[string]string) int {
+		t.Helper()
+		req := server.NewRequest(http.MethodPost, strings.TrimPrefix(url, "http://localhost:3000"), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res.StatusCode
+	}
+
+	t.Run("should require the permission to write the organization to create webhooks", func(t *testing.T) {
+		req := server.NewRequest(http.MethodPost, "/api/annotations/webhooks", strings.NewReader(`{"name": "ci"}`))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should create annotations from the events with a valid token", func(t *testing.T) {
+		webhook := create(t, `{"name": "ci", "tags": ["deploy"]}`)
+		assert.Equal(t, "http://localhost:3000/api/annotations/webhooks/"+webhook.UID+"/events", webhook.URL)
+
+		assert.Equal(t, http.StatusUnauthorized, send(t, webhook.URL, `{"text": "Deployed"}`, nil))
+		assert.Equal(t, http.StatusUnauthorized, send(t, webhook.URL, `{"text": "Deployed"}`, map[string]string{annotationWebhookTokenHeader: "invalid"}))
+		assert.Equal(t, 0, repo.Len())
+
+		assert.Equal(t, http.StatusOK, send(t, webhook.URL, `{"text": "Deployed"}`, map[string]string{annotationWebhookTokenHeader: webhook.Token}))
+		require.Equal(t, 1, repo.Len())
+		item := repo.Items()[1]
+		assert.Equal(t, int64(1), item.OrgID)
+		assert.Equal(t, []string{"deploy"}, item.Tags)
+	})
+
+	t.Run("should verify the signature of GitHub events", func(t *testing.T) {
+		webhook := create(t, `{"name": "github", "format": "github"}`)
+		body := `{"deployment": {"ref": "main", "environment": "staging"}, "repository": {"full_name": "grafana/grafana"}}`
+		mac := hmac.New(sha256.New, []byte(webhook.Token))
+		mac.Write([]byte(body))
+
+		assert.Equal(t, http.StatusUnauthorized, send(t, webhook.URL, body, map[string]string{gitHubSignatureHeader: "sha256=00", gitHubEventHeader: "deployment"}))
+		assert.Equal(t, http.StatusOK, send(t, webhook.URL, body, map[string]string{gitHubSignatureHeader: "sha256=" + hex.EncodeToString(mac.Sum(nil)), gitHubEventHeader: "deployment"}))
+	})
+
+	t.Run("should return not found for unknown webhooks", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, send(t, "/api/annotations/webhooks/unknown/events", `{"text": "Deployed"}`, map[string]string{annotationWebhookTokenHeader: "token"}))
+	})
+
+	t.Run("should reject invalid tag rules", func(t *testing.T) {
+		req := server.NewRequest(http.MethodPost, "/api/annotations/webhooks", strings.NewReader(`{"name": "ci", "tagRules": [{"field": "env", "match": "(", "tag": "env"}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsWrite}})))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
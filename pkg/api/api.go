@@ -139,13 +139,13 @@ func (hs *HTTPServer) registerRoutes() {
 	r.Get("/a/:id/*", authorize(ac.EvalPermission(pluginaccesscontrol.ActionAppAccess, appPluginIDScope)), reqSignedIn, reqRoleForAppRoute, hs.Index)
 	r.Get("/a/:id", authorize(ac.EvalPermission(pluginaccesscontrol.ActionAppAccess, appPluginIDScope)), reqSignedIn, reqRoleForAppRoute, hs.Index)
 
-	r.Get("/d/:uid/:slug", reqSignedIn, redirectFromLegacyPanelEditURL, hs.Index)
-	r.Get("/d/:uid", reqSignedIn, redirectFromLegacyPanelEditURL, hs.Index)
+	r.Get("/d/:uid/:slug", reqSignedIn, redirectFromLegacyPanelEditURL, hs.applyDashboardEmbeddingPolicy, hs.Index)
+	r.Get("/d/:uid", reqSignedIn, redirectFromLegacyPanelEditURL, hs.applyDashboardEmbeddingPolicy, hs.Index)
 	r.Get("/dashboard/script/*", reqSignedIn, hs.Index)
 	r.Get("/dashboard/new", reqSignedIn, hs.Index)
 	r.Get("/dashboard-solo/snapshot/*", hs.Index)
-	r.Get("/d-solo/:uid/:slug", reqSignedIn, hs.Index)
-	r.Get("/d-solo/:uid", reqSignedIn, hs.Index)
+	r.Get("/d-solo/:uid/:slug", reqSignedIn, hs.applyDashboardEmbeddingPolicy, hs.Index)
+	r.Get("/d-solo/:uid", reqSignedIn, hs.applyDashboardEmbeddingPolicy, hs.Index)
 	r.Get("/dashboard-solo/script/*", reqSignedIn, hs.Index)
 	r.Get("/import/dashboard", reqSignedIn, hs.Index)
 	r.Get("/dashboards/", reqSignedIn, hs.Index)
@@ -205,6 +205,9 @@ func (hs *HTTPServer) registerRoutes() {
 	r.Post("/api/user/password/send-reset-email", routing.Wrap(hs.SendResetPasswordEmail))
 	r.Post("/api/user/password/reset", routing.Wrap(hs.ResetPassword))
 
+	// annotation webhooks, the events are authenticated by the token of the webhook
+	r.Post("/api/annotations/webhooks/:uid/events", routing.Wrap(hs.PostAnnotationWebhookEvent))
+
 	// dashboard snapshots
 	r.Get("/dashboard/snapshot/*", reqNoAuth, hs.Index)
 	r.Get("/dashboard/snapshots/", reqSignedIn, hs.Index)
@@ -212,6 +215,9 @@ func (hs *HTTPServer) registerRoutes() {
 	// api renew session based on cookie
 	r.Get("/api/login/ping", quota(string(auth.QuotaTargetSrv)), routing.Wrap(hs.LoginAPIPing))
 
+	// the asset manifest only lists files that are served without authentication
+	r.Get("/api/frontend/assets/manifest", routing.Wrap(hs.GetFrontendAssetManifest))
+
 	// expose plugin file system assets
 	r.Get("/public/plugins/:pluginId/*", hs.getPluginAssets)
 
@@ -247,6 +253,8 @@ func (hs *HTTPServer) registerRoutes() {
 			userRoute.Get("/teams", routing.Wrap(hs.GetSignedInUserTeamList))
 
 			userRoute.Get("/stars", routing.Wrap(hs.starApi.GetStars))
+			userRoute.Get("/stars/layout", routing.Wrap(hs.starApi.GetStarLayout))
+			userRoute.Put("/stars/layout", routing.Wrap(hs.starApi.UpdateStarLayout))
 			// Deprecated: use /stars/dashboard/uid/:uid API instead.
 			// nolint:staticcheck
 			userRoute.Post("/stars/dashboard/:id", routing.Wrap(hs.starApi.StarDashboard))
@@ -288,6 +296,9 @@ func (hs *HTTPServer) registerRoutes() {
 		apiRoute.Group("/org", func(orgRoute routing.RouteRegister) {
 			orgRoute.Get("/", authorize(ac.EvalPermission(ac.ActionOrgsRead)), routing.Wrap(hs.GetCurrentOrg))
 			orgRoute.Get("/quotas", authorize(ac.EvalPermission(ac.ActionOrgsQuotasRead)), routing.Wrap(hs.GetCurrentOrgQuotas))
+			orgRoute.Get("/usage", authorize(ac.EvalPermission(ac.ActionOrgsQuotasRead)), routing.Wrap(hs.GetCurrentOrgUsage))
+			orgRoute.Get("/preferences/time-range-presets", routing.Wrap(hs.GetTimeRangePresets))
+			orgRoute.Get("/preferences/time-range-presets/resolve", routing.Wrap(hs.ResolveTimeRangePreset))
 		})
 
 		if hs.Features.IsEnabledGlobally(featuremgmt.FlagStorage) {
@@ -319,6 +330,9 @@ func (hs *HTTPServer) registerRoutes() {
 			orgRoute.Get("/preferences", authorize(ac.EvalPermission(ac.ActionOrgsPreferencesRead)), routing.Wrap(hs.GetOrgPreferences))
 			orgRoute.Put("/preferences", authorize(ac.EvalPermission(ac.ActionOrgsPreferencesWrite)), routing.Wrap(hs.UpdateOrgPreferences))
 			orgRoute.Patch("/preferences", authorize(ac.EvalPermission(ac.ActionOrgsPreferencesWrite)), routing.Wrap(hs.PatchOrgPreferences))
+			orgRoute.Put("/preferences/time-range-presets", authorize(ac.EvalPermission(ac.ActionOrgsPreferencesWrite)), routing.Wrap(hs.UpdateTimeRangePresets))
+			orgRoute.Get("/frontend-features", authorize(ac.EvalPermission(ac.ActionOrgsPreferencesRead)), routing.Wrap(hs.GetOrgFrontendFeatures))
+			orgRoute.Put("/frontend-features", authorize(ac.EvalPermission(ac.ActionOrgsPreferencesWrite)), routing.Wrap(hs.UpdateOrgFrontendFeatures))
 		})
 
 		// current org without requirement of user to be org admin
@@ -357,6 +371,7 @@ func (hs *HTTPServer) registerRoutes() {
 			orgsRoute.Patch("/users/:userId", requestmeta.SetOwner(requestmeta.TeamAuth), authorizeInOrg(ac.UseOrgFromContextParams, ac.EvalPermission(ac.ActionOrgUsersWrite, userIDScope)), routing.Wrap(hs.UpdateOrgUser))
 			orgsRoute.Delete("/users/:userId", requestmeta.SetOwner(requestmeta.TeamAuth), authorizeInOrg(ac.UseOrgFromContextParams, ac.EvalPermission(ac.ActionOrgUsersRemove, userIDScope)), routing.Wrap(hs.RemoveOrgUser))
 			orgsRoute.Get("/quotas", authorizeInOrg(ac.UseOrgFromContextParams, ac.EvalPermission(ac.ActionOrgsQuotasRead)), routing.Wrap(hs.GetOrgQuotas))
+			orgsRoute.Get("/usage", authorizeInOrg(ac.UseOrgFromContextParams, ac.EvalPermission(ac.ActionOrgsQuotasRead)), routing.Wrap(hs.GetOrgUsage))
 			orgsRoute.Put("/quotas/:target", authorizeInOrg(ac.UseOrgFromContextParams, ac.EvalPermission(ac.ActionOrgsQuotasWrite)), routing.Wrap(hs.UpdateOrgQuota))
 		})
 
@@ -420,6 +435,7 @@ func (hs *HTTPServer) registerRoutes() {
 
 		apiRoute.Get("/frontend/settings/", hs.GetFrontendSettings)
 		apiRoute.Get("/frontend/assets", hs.GetFrontendAssets)
+		apiRoute.Get("/frontend/capabilities", routing.Wrap(hs.GetFrontendCapabilities))
 
 		apiRoute.Any("/datasources/proxy/:id/*", requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow), authorize(ac.EvalPermission(datasources.ActionQuery)), hs.ProxyDataSourceRequest)
 		apiRoute.Any("/datasources/proxy/uid/:uid/*", requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow), authorize(ac.EvalPermission(datasources.ActionQuery)), hs.ProxyDataSourceRequestWithUID)
@@ -449,10 +465,18 @@ func (hs *HTTPServer) registerRoutes() {
 				folderUidRoute.Post("/move", authorize(ac.EvalPermission(dashboards.ActionFoldersWrite, uidScope)), routing.Wrap(hs.MoveFolder))
 				folderUidRoute.Delete("/", authorize(ac.EvalPermission(dashboards.ActionFoldersDelete, uidScope)), routing.Wrap(hs.DeleteFolder))
 				folderUidRoute.Get("/counts", authorize(ac.EvalPermission(dashboards.ActionFoldersRead, uidScope)), routing.Wrap(hs.GetFolderDescendantCounts))
+				folderUidRoute.Get("/labels", authorize(ac.EvalPermission(dashboards.ActionFoldersRead, uidScope)), routing.Wrap(hs.GetFolderLabels))
+				folderUidRoute.Put("/labels", authorize(ac.EvalPermission(dashboards.ActionFoldersWrite, uidScope)), routing.Wrap(hs.UpdateFolderLabels))
+				folderUidRoute.Get("/read-only", authorize(ac.EvalPermission(dashboards.ActionFoldersRead, uidScope)), routing.Wrap(hs.GetFolderReadOnly))
+				folderUidRoute.Put("/read-only", authorize(ac.EvalPermission(dashboards.ActionFoldersReadOnlyWrite, uidScope)), routing.Wrap(hs.UpdateFolderReadOnly))
+				folderUidRoute.Get("/embedding-policy", authorize(ac.EvalPermission(dashboards.ActionFoldersRead, uidScope)), routing.Wrap(hs.GetFolderEmbeddingPolicy))
+				folderUidRoute.Put("/embedding-policy", authorize(ac.EvalPermission(dashboards.ActionFoldersWrite, uidScope)), routing.Wrap(hs.UpdateFolderEmbeddingPolicy))
+				folderUidRoute.Delete("/embedding-policy", authorize(ac.EvalPermission(dashboards.ActionFoldersWrite, uidScope)), routing.Wrap(hs.DeleteFolderEmbeddingPolicy))
 
 				folderUidRoute.Group("/permissions", func(folderPermissionRoute routing.RouteRegister) {
 					folderPermissionRoute.Get("/", authorize(ac.EvalPermission(dashboards.ActionFoldersPermissionsRead, uidScope)), routing.Wrap(hs.GetFolderPermissionList))
 					folderPermissionRoute.Post("/", authorize(ac.EvalPermission(dashboards.ActionFoldersPermissionsWrite, uidScope)), routing.Wrap(hs.UpdateFolderPermissions))
+					folderPermissionRoute.Get("/history", authorize(ac.EvalPermission(dashboards.ActionFoldersPermissionsRead, uidScope)), routing.Wrap(hs.GetFolderPermissionsHistory))
 				})
 			})
 		})
@@ -463,19 +487,44 @@ func (hs *HTTPServer) registerRoutes() {
 			dashboardRoute.Delete("/uid/:uid", authorize(ac.EvalPermission(dashboards.ActionDashboardsDelete)), routing.Wrap(hs.DeleteDashboardByUID))
 			dashboardRoute.Group("/uid/:uid", func(dashUidRoute routing.RouteRegister) {
 				dashUidRoute.Get("/versions", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersions))
+				dashUidRoute.Get("/checksum", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead)), routing.Wrap(hs.GetDashboardChecksum))
 				dashUidRoute.Post("/restore", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.RestoreDashboardVersion))
 				dashUidRoute.Get("/versions/:id", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersion))
+				dashUidRoute.Get("/embedding-policy", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid")))), routing.Wrap(hs.GetDashboardEmbeddingPolicy))
+				dashUidRoute.Put("/embedding-policy", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid")))), routing.Wrap(hs.UpdateDashboardEmbeddingPolicy))
+				dashUidRoute.Delete("/embedding-policy", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid")))), routing.Wrap(hs.DeleteDashboardEmbeddingPolicy))
+				dashUidRoute.Post("/panels/:panelId/embed", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid")))), routing.Wrap(hs.CreatePanelEmbed))
+				dashUidRoute.Get("/lineage", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid")))), routing.Wrap(hs.GetDashboardTemplateLineage))
+				dashUidRoute.Get("/lineage/drift", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid")))), routing.Wrap(hs.GetDashboardTemplateDrift))
+				dashUidRoute.Post("/lineage/reapply", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid")))), routing.Wrap(hs.ReapplyDashboardTemplate))
+				dashUidRoute.Get("/embeds", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid")))), routing.Wrap(hs.GetPanelEmbeds))
+				dashUidRoute.Delete("/embeds/:embedId", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid")))), routing.Wrap(hs.RevokePanelEmbed))
 				dashUidRoute.Group("/permissions", func(dashboardPermissionRoute routing.RouteRegister) {
 					dashboardPermissionRoute.Get("/", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsRead)), routing.Wrap(hs.GetDashboardPermissionList))
 					dashboardPermissionRoute.Post("/", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsWrite)), routing.Wrap(hs.UpdateDashboardPermissions))
+					dashboardPermissionRoute.Get("/history", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsRead)), routing.Wrap(hs.GetDashboardPermissionsHistory))
 				})
 			})
 
 			dashboardRoute.Post("/calculate-diff", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.CalculateDashboardDiff))
 
-			dashboardRoute.Post("/db", authorize(ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsCreate), ac.EvalPermission(dashboards.ActionDashboardsWrite))), routing.Wrap(hs.PostDashboard))
+			dashboardRoute.Post("/db", authorize(ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsCreate), ac.EvalPermission(dashboards.ActionDashboardsWrite))), middleware.LimitDashboardPayload(hs.Cfg.DashboardMaxPayloadSize), routing.Wrap(hs.PostDashboard))
+			dashboardRoute.Post("/apply", authorize(ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsCreate), ac.EvalPermission(dashboards.ActionDashboardsWrite))), middleware.LimitDashboardPayload(hs.Cfg.DashboardMaxPayloadSize), routing.Wrap(hs.ApplyDashboards))
+			dashboardRoute.Post("/from-template", authorize(ac.EvalPermission(dashboards.ActionDashboardsCreate)), routing.Wrap(hs.CreateDashboardFromTemplate))
 			dashboardRoute.Get("/home", routing.Wrap(hs.GetHomeDashboard))
+			dashboardRoute.Get("/home/resolve", routing.Wrap(hs.ResolveHomeDashboard))
 			dashboardRoute.Get("/tags", hs.GetDashboardTags)
+			dashboardRoute.Get("/panel-types", reqOrgAdmin, routing.Wrap(hs.GetDashboardPanelTypes))
+			dashboardRoute.Get("/permissions/export", authorize(ac.EvalAll(
+				ac.EvalPermission(dashboards.ActionFoldersPermissionsRead, dashboards.ScopeFoldersAll),
+				ac.EvalPermission(dashboards.ActionDashboardsPermissionsRead, dashboards.ScopeDashboardsAll),
+			)), hs.ExportDashboardPermissions)
+			dashboardRoute.Get("/export", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsAll)), hs.ExportDashboards)
+			dashboardRoute.Post("/checksums", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead)), routing.Wrap(hs.GetDashboardChecksums))
+			dashboardRoute.Get("/changes", authorize(ac.EvalAll(
+				ac.EvalPermission(dashboards.ActionFoldersRead, dashboards.ScopeFoldersAll),
+				ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsAll),
+			)), routing.Wrap(hs.GetDashboardChanges))
 
 			// Deprecated: used to convert internal IDs to UIDs
 			dashboardRoute.Get("/ids/:ids", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead)), hs.GetDashboardUIDs)
@@ -503,6 +552,7 @@ func (hs *HTTPServer) registerRoutes() {
 
 		// Search
 		apiRoute.Get("/search/sorting", routing.Wrap(hs.ListSortOptions))
+		apiRoute.Get("/search/facets", routing.Wrap(hs.SearchFacets))
 		apiRoute.Get("/search/", routing.Wrap(hs.Search))
 
 		// metrics
@@ -555,6 +605,16 @@ func (hs *HTTPServer) registerRoutes() {
 			annotationsRoute.Patch("/:annotationId", authorize(ac.EvalPermission(ac.ActionAnnotationsWrite, ac.ScopeAnnotationsID)), routing.Wrap(hs.PatchAnnotation))
 			annotationsRoute.Post("/graphite", authorize(ac.EvalPermission(ac.ActionAnnotationsCreate, ac.ScopeAnnotationsTypeOrganization)), routing.Wrap(hs.PostGraphiteAnnotation))
 			annotationsRoute.Get("/tags", authorize(ac.EvalPermission(ac.ActionAnnotationsRead)), routing.Wrap(hs.GetAnnotationTags))
+			annotationsRoute.Get("/webhooks", authorize(ac.EvalPermission(ac.ActionOrgsRead)), routing.Wrap(hs.GetAnnotationWebhooks))
+			annotationsRoute.Post("/webhooks", authorize(ac.EvalPermission(ac.ActionOrgsWrite)), routing.Wrap(hs.CreateAnnotationWebhook))
+			annotationsRoute.Put("/webhooks/:uid", authorize(ac.EvalPermission(ac.ActionOrgsWrite)), routing.Wrap(hs.UpdateAnnotationWebhook))
+			annotationsRoute.Delete("/webhooks/:uid", authorize(ac.EvalPermission(ac.ActionOrgsWrite)), routing.Wrap(hs.DeleteAnnotationWebhook))
+		})
+
+		apiRoute.Group("/tags", func(tagsRoute routing.RouteRegister) {
+			tagsRoute.Get("/", routing.Wrap(hs.GetTagUsage))
+			tagsRoute.Get("/autocomplete", routing.Wrap(hs.AutocompleteTags))
+			tagsRoute.Post("/rename", authorize(ac.EvalPermission(ac.ActionOrgsWrite)), routing.Wrap(hs.RenameTag))
 		})
 
 		apiRoute.Post("/frontend-metrics", routing.Wrap(hs.PostFrontendMetrics))
@@ -593,6 +653,9 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/encryption/migrate-secrets/from-plugin", reqGrafanaAdmin, routing.Wrap(hs.AdminMigrateSecretsFromPlugin))
 		adminRoute.Post("/encryption/delete-secretsmanagerplugin-secrets", reqGrafanaAdmin, routing.Wrap(hs.AdminDeleteAllSecretsManagerPluginSecrets))
 
+		adminRoute.Get("/rendering/signing-keys", reqGrafanaAdmin, routing.Wrap(hs.AdminGetRenderSigningKeys))
+		adminRoute.Post("/rendering/signing-keys/rotate", reqGrafanaAdmin, routing.Wrap(hs.AdminRotateRenderSigningKey))
+
 		adminRoute.Post("/provisioning/dashboards/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningReloadDashboards))
 		adminRoute.Post("/provisioning/plugins/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersPlugins)), routing.Wrap(hs.AdminProvisioningReloadPlugins))
 		adminRoute.Post("/provisioning/datasources/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
@@ -620,6 +683,8 @@ func (hs *HTTPServer) registerRoutes() {
 
 	// rendering
 	r.Get("/render/*", requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow), reqSignedIn, hs.RenderToPng)
+	// panel embeds are authorized by their signed token
+	r.Get("/api/embed/panels/:token", requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow), routing.Wrap(hs.GetPanelEmbed))
 
 	// grafana.net proxy
 	r.Any("/api/gnet/*", requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow), reqSignedIn, hs.ProxyGnetRequest)
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
@@ -15,6 +16,21 @@ import (
 
 // ToDashboardErrorResponse returns a different response status according to the dashboard error type
 func ToDashboardErrorResponse(ctx context.Context, pluginStore pluginstore.Store, err error) response.Response {
+	var limitErr dashboards.DashboardLimitErr
+	if errors.As(err, &limitErr) {
+		return response.JSON(limitErr.StatusCode, limitErr.Body())
+	}
+
+	var dependentsErr dashboards.DashboardDependentsErr
+	if errors.As(err, &dependentsErr) {
+		return response.JSON(dependentsErr.StatusCode, dependentsErr.Body())
+	}
+
+	var readOnlyErr dashboards.DashboardFolderReadOnlyErr
+	if errors.As(err, &readOnlyErr) {
+		return response.JSON(readOnlyErr.StatusCode, readOnlyErr.Body())
+	}
+
 	var dashboardErr dashboards.DashboardErr
 	if ok := errors.As(err, &dashboardErr); ok {
 		if body := dashboardErr.Body(); body != nil {
@@ -47,3 +63,15 @@ func ToDashboardErrorResponse(ctx context.Context, pluginStore pluginstore.Store
 
 	return response.Error(http.StatusInternalServerError, "Failed to save dashboard", err)
 }
+
+// ToDashboardBindErrorResponse returns the response of a dashboard save or import request with an invalid body,
+// or with a body larger than the max_payload_size setting
+func ToDashboardBindErrorResponse(err error) response.Response {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		metrics.MDashboardLimitExceeded.WithLabelValues(metrics.DashboardLimitPayloadSize).Inc()
+		limitErr := dashboards.NewDashboardLimitErr(dashboards.ErrDashboardPayloadTooLarge, "bytes", 0, maxBytesErr.Limit)
+		return response.JSON(http.StatusRequestEntityTooLarge, limitErr.Body())
+	}
+	return response.Error(http.StatusBadRequest, "bad request data", err)
+}
@@ -324,10 +324,22 @@ func SetupAPITestServer(t *testing.T, opts ...APITestServerOption) *webtest.Serv
 	return s
 }
 
-type mockSearchService struct{ ExpectedResult model.HitList }
+type mockSearchService struct {
+	ExpectedResult model.HitList
+	ExpectedFacets *model.Facets
+	ExpectedTotal  *model.Total
+}
 
 func (mss *mockSearchService) SearchHandler(_ context.Context, q *search.Query) (model.HitList, error) {
 	return mss.ExpectedResult, nil
 }
 
+func (mss *mockSearchService) FacetsHandler(_ context.Context, q *search.Query) (*model.Facets, error) {
+	return mss.ExpectedFacets, nil
+}
+
+func (mss *mockSearchService) TotalHandler(_ context.Context, q *search.Query, mode model.TotalsMode) (*model.Total, error) {
+	return mss.ExpectedTotal, nil
+}
+
 func (mss *mockSearchService) SortOptions() []model.SortOption { return nil }
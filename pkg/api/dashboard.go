@@ -128,6 +128,18 @@ func (hs *HTTPServer) GetDashboard(c *contextmodel.ReqContext) response.Response
 	canSave, _ := guardian.CanSave()
 	canAdmin, _ := guardian.CanAdmin()
 	canDelete, _ := guardian.CanDelete()
+	if !canSave && dash.Data != nil {
+		// the users who can save the dashboard get all the panels, saving it doesn't remove the hidden ones
+		dash.RemoveHiddenPanels(hs.panelVisibleTo(c.Req.Context(), c.SignedInUser))
+	}
+
+	var renderProfile *dashboards.RenderProfile
+	if name := c.Query(renderProfileParam); name != "" && dash.Data != nil {
+		if renderProfile, rsp = getRenderProfile(dash, name); rsp != nil {
+			return rsp
+		}
+		dash.ApplyRenderProfile(renderProfile)
+	}
 
 	isStarred, err := hs.isDashboardStarredByUser(c, dash.ID)
 	if err != nil {
@@ -171,6 +183,7 @@ func (hs *HTTPServer) GetDashboard(c *contextmodel.ReqContext) response.Response
 		FolderTitle:            "General",
 		AnnotationsPermissions: annotationPermissions,
 		PublicDashboardEnabled: publicDashboardEnabled,
+		RenderProfile:          renderProfile,
 	}
 	metrics.MFolderIDsAPICount.WithLabelValues(metrics.GetDashboard).Inc()
 	// lookup folder title
@@ -213,6 +226,10 @@ func (hs *HTTPServer) GetDashboard(c *contextmodel.ReqContext) response.Response
 		}
 	}
 
+	if err := hs.decryptSensitiveDashboardFields(c.Req.Context(), c.SignedInUser, dash.UID, dash.Data); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to decrypt sensitive dashboard fields", err)
+	}
+
 	// make sure db version is in sync with json model version
 	dash.Data.Set("version", dash.Version)
 
@@ -278,12 +295,15 @@ func (hs *HTTPServer) getDashboardHelper(ctx context.Context, orgID int64, id in
 // Delete dashboard by uid.
 //
 // Will delete the dashboard given the specified unique identifier (uid).
+// Dashboards that alert rules or annotations reference can only be deleted with `forceDeleteRules`,
+// which deletes the alert rules together with the dashboard.
 //
 // Responses:
 // 200: deleteDashboardResponse
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 409: conflictError
 // 500: internalServerError
 func (hs *HTTPServer) DeleteDashboardByUID(c *contextmodel.ReqContext) response.Response {
 	return hs.deleteDashboard(c)
@@ -307,27 +327,22 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 		return response.Error(http.StatusBadRequest, "Use folders endpoint for deleting folders.", nil)
 	}
 
-	namespaceID, userIDStr := c.SignedInUser.GetNamespacedID()
-
-	// disconnect all library elements for this dashboard
-	err = hs.LibraryElementService.DisconnectElementsFromDashboard(c.Req.Context(), dash.ID)
+	dependents, err := hs.DashboardService.GetDashboardDependents(c.Req.Context(), &dashboards.GetDashboardDependentsQuery{OrgID: dash.OrgID, UID: dash.UID})
 	if err != nil {
-		hs.log.Error(
-			"Failed to disconnect library elements",
-			"dashboard", dash.ID,
-			"namespaceID", namespaceID,
-			"user", userIDStr,
-			"error", err)
+		return response.Error(http.StatusInternalServerError, "Failed to get the dependents of the dashboard", err)
 	}
-
-	// deletes all related public dashboard entities
-	err = hs.PublicDashboardsApi.PublicDashboardService.DeleteByDashboard(c.Req.Context(), dash)
-	if err != nil {
-		hs.log.Error("Failed to delete public dashboard")
+	forceDeleteRules := c.QueryBool("forceDeleteRules")
+	if !forceDeleteRules && !dependents.IsEmpty() {
+		return apierrors.ToDashboardErrorResponse(c.Req.Context(), hs.pluginStore, dashboards.NewDashboardDependentsErr(dependents))
+	}
+	for _, rule := range dependents.AlertRules {
+		evaluator := accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleDelete, dashboards.ScopeFoldersProvider.GetResourceScopeUID(rule.FolderUID))
+		if canDelete, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator); err != nil || !canDelete {
+			return response.Error(http.StatusForbidden, fmt.Sprintf("Access denied to delete the alert rule %s", rule.UID), err)
+		}
 	}
 
-	err = hs.DashboardService.DeleteDashboard(c.Req.Context(), dash.ID, c.SignedInUser.GetOrgID())
-	if err != nil {
+	if err := hs.removeDashboard(c.Req.Context(), c.SignedInUser, dash, len(dependents.AlertRules) > 0); err != nil {
 		var dashboardErr dashboards.DashboardErr
 		if ok := errors.As(err, &dashboardErr); ok {
 			if errors.Is(err, dashboards.ErrDashboardCannotDeleteProvisionedDashboard) {
@@ -355,6 +370,34 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 	})
 }
 
+// removeDashboard disconnects the library elements and deletes the public dashboards of the dashboard before deleting
+// it, together with its alert rules when withRules is set.
+func (hs *HTTPServer) removeDashboard(ctx context.Context, user identity.Requester, dash *dashboards.Dashboard, withRules bool) error {
+	namespaceID, userIDStr := user.GetNamespacedID()
+
+	// disconnect all library elements for this dashboard
+	err := hs.LibraryElementService.DisconnectElementsFromDashboard(ctx, dash.ID)
+	if err != nil {
+		hs.log.Error(
+			"Failed to disconnect library elements",
+			"dashboard", dash.ID,
+			"namespaceID", namespaceID,
+			"user", userIDStr,
+			"error", err)
+	}
+
+	// deletes all related public dashboard entities
+	err = hs.PublicDashboardsApi.PublicDashboardService.DeleteByDashboard(ctx, dash)
+	if err != nil {
+		hs.log.Error("Failed to delete public dashboard")
+	}
+
+	if withRules {
+		return hs.DashboardService.DeleteDashboardAndRules(ctx, dash.ID, user.GetOrgID())
+	}
+	return hs.DashboardService.DeleteDashboard(ctx, dash.ID, user.GetOrgID())
+}
+
 // swagger:route POST /dashboards/db dashboards postDashboard
 //
 // Create / Update dashboard
@@ -369,12 +412,13 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 // 403: forbiddenError
 // 404: notFoundError
 // 412: preconditionFailedError
+// 413: payloadTooLargeError
 // 422: unprocessableEntityError
 // 500: internalServerError
 func (hs *HTTPServer) PostDashboard(c *contextmodel.ReqContext) response.Response {
 	cmd := dashboards.SaveDashboardCommand{}
 	if err := web.Bind(c.Req, &cmd); err != nil {
-		return response.Error(http.StatusBadRequest, "bad request data", err)
+		return apierrors.ToDashboardBindErrorResponse(err)
 	}
 	return hs.postDashboard(c, cmd)
 }
@@ -780,6 +824,10 @@ func (hs *HTTPServer) GetDashboardVersion(c *contextmodel.ReqContext) response.R
 		return response.Error(http.StatusInternalServerError, fmt.Sprintf("Dashboard version %d not found for dashboardId %d", query.Version, dash.ID), err)
 	}
 
+	if err := hs.decryptSensitiveDashboardFields(c.Req.Context(), c.SignedInUser, dash.UID, res.Data); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to decrypt sensitive dashboard fields", err)
+	}
+
 	creator := anonString
 	if res.CreatedBy > 0 {
 		creator = hs.getUserLogin(c.Req.Context(), res.CreatedBy)
@@ -1009,6 +1057,41 @@ func (hs *HTTPServer) GetDashboardTags(c *contextmodel.ReqContext) {
 	c.JSON(http.StatusOK, queryResult)
 }
 
+// swagger:route GET /dashboards/panel-types dashboards getDashboardPanelTypes
+//
+// Get the usage of panel plugins across the dashboards of an organisation.
+//
+// Returns, per panel plugin, the number of panels and dashboards using it.
+//
+// Responses:
+// 200: getDashboardPanelTypesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardPanelTypes(c *contextmodel.ReqContext) response.Response {
+	query := dashboards.GetPanelTypeUsageQuery{OrgID: c.SignedInUser.GetOrgID()}
+	usage, err := hs.DashboardService.GetPanelTypeUsage(c.Req.Context(), &query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get panel types from database", err)
+	}
+
+	result := make([]dtos.PanelPluginUsage, 0, len(usage))
+	for _, u := range usage {
+		item := dtos.PanelPluginUsage{
+			PanelType:  u.PanelType,
+			Panels:     u.Panels,
+			Dashboards: u.Dashboards,
+		}
+		if plugin, exists := hs.pluginStore.Plugin(c.Req.Context(), u.PanelType); exists {
+			item.Name = plugin.Name
+			item.Installed = true
+		}
+		result = append(result, item)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
 // GetDashboardUIDs converts internal ids to UIDs
 func (hs *HTTPServer) GetDashboardUIDs(c *contextmodel.ReqContext) {
 	ids := strings.Split(web.Params(c.Req)[":ids"], ",")
@@ -1106,6 +1189,12 @@ type DeleteDashboardByUIDParams struct {
 	// in:path
 	// required:true
 	UID string `json:"uid"`
+	// If `true` the alert rules linked to the panels of the dashboard will be deleted with it.
+	// Set to `false` so that the request will fail if alert rules or annotations reference the dashboard.
+	// in:query
+	// required:false
+	// default:false
+	ForceDeleteRules bool `json:"forceDeleteRules"`
 }
 
 // swagger:parameters postDashboard
@@ -1218,6 +1307,12 @@ type DashboardsTagsResponse struct {
 	Body []*dashboards.DashboardTagCloudItem `json:"body"`
 }
 
+// swagger:response getDashboardPanelTypesResponse
+type DashboardPanelTypesResponse struct {
+	// in: body
+	Body []dtos.PanelPluginUsage `json:"body"`
+}
+
 // Get home dashboard response.
 // swagger:model GetHomeDashboardResponse
 type GetHomeDashboardResponseBody struct {
@@ -0,0 +1,325 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route POST /dashboards/apply dashboards applyDashboards
+//
+// Apply a batch of dashboard manifests.
+//
+// Computes a plan (create, update, no-op and, when `prune` is set, delete) for the given
+// dashboards against the stored state. In dry-run mode the plan is returned without changes,
+// otherwise the plan is applied in a single transaction.
+//
+// Responses:
+// 200: applyDashboardsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 412: preconditionFailedError
+// 422: unprocessableEntityError
+// 500: internalServerError
+func (hs *HTTPServer) ApplyDashboards(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.ApplyDashboardsCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return apierrors.ToDashboardBindErrorResponse(err)
+	}
+
+	ctx := c.Req.Context()
+	plan, manifests, rsp := hs.planDashboardApply(ctx, c, cmd)
+	if rsp != nil {
+		return rsp
+	}
+
+	if cmd.DryRun {
+		return response.JSON(http.StatusOK, plan)
+	}
+
+	err := hs.SQLStore.InTransaction(ctx, func(ctx context.Context) error {
+		for i, step := range plan.Steps {
+			switch step.Action {
+			case dtos.DashboardApplyCreate, dtos.DashboardApplyUpdate:
+				dash, err := hs.applyDashboardManifest(ctx, c, manifests[step.UID], cmd.Message)
+				if err != nil {
+					return err
+				}
+				plan.Steps[i].Version = dash.Version
+			case dtos.DashboardApplyDelete:
+				dash, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: step.UID, OrgID: c.SignedInUser.GetOrgID()})
+				if err != nil {
+					return err
+				}
+				if err := hs.removeDashboard(ctx, c.SignedInUser, dash, false); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
+	}
+
+	// Clear the permission cache so that the caller can immediately interact with created dashboards
+	hs.accesscontrolService.ClearUserPermissionCache(c.SignedInUser)
+
+	return response.JSON(http.StatusOK, plan)
+}
+
+// planDashboardApply matches the manifests against the stored dashboards by UID and returns the
+// resulting plan together with the manifests indexed by UID. Every step is checked against the
+// permissions of the user, so the plan of a dry run doesn't fail when it is applied.
+func (hs *HTTPServer) planDashboardApply(ctx context.Context, c *contextmodel.ReqContext, cmd dtos.ApplyDashboardsCommand) (*dtos.DashboardApplyPlan, map[string]dtos.ApplyDashboardManifest, response.Response) {
+	orgID := c.SignedInUser.GetOrgID()
+	plan := &dtos.DashboardApplyPlan{DryRun: cmd.DryRun, Steps: make([]dtos.DashboardApplyStep, 0, len(cmd.Dashboards))}
+	manifests := make(map[string]dtos.ApplyDashboardManifest, len(cmd.Dashboards))
+	folderUIDs := make(map[string]struct{})
+
+	for _, m := range cmd.Dashboards {
+		if m.Dashboard == nil {
+			return nil, nil, response.Error(http.StatusBadRequest, "Dashboard manifest is missing the dashboard model", nil)
+		}
+		uid := m.Dashboard.Get("uid").MustString()
+		if uid == "" {
+			return nil, nil, response.Error(http.StatusBadRequest, "Dashboard manifests must have a uid", nil)
+		}
+		if _, ok := manifests[uid]; ok {
+			return nil, nil, response.Error(http.StatusBadRequest, fmt.Sprintf("Dashboard uid %s is present more than once", uid), nil)
+		}
+		manifests[uid] = m
+		folderUIDs[m.FolderUID] = struct{}{}
+
+		step := dtos.DashboardApplyStep{
+			UID:       uid,
+			Title:     m.Dashboard.Get("title").MustString(),
+			FolderUID: m.FolderUID,
+		}
+
+		existing, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: uid, OrgID: orgID})
+		switch {
+		case errors.Is(err, dashboards.ErrDashboardNotFound):
+			step.Action = dtos.DashboardApplyCreate
+			if canCreate, err := hs.canCreateDashboardInFolder(ctx, c, m.FolderUID); err != nil || !canCreate {
+				return nil, nil, dashboardGuardianResponse(err)
+			}
+		case err != nil:
+			return nil, nil, response.Error(http.StatusInternalServerError, "Failed to get dashboard", err)
+		case existing.IsFolder:
+			return nil, nil, response.Error(http.StatusBadRequest, fmt.Sprintf("The uid %s belongs to a folder", uid), nil)
+		default:
+			step.Version = existing.Version
+			step.Action = dtos.DashboardApplyUpdate
+			equal, err := dashboardManifestEqual(existing, m)
+			if err != nil {
+				return nil, nil, response.Error(http.StatusBadRequest, "Failed to compare dashboard manifest", err)
+			}
+			if equal {
+				step.Action = dtos.DashboardApplyNoop
+				break
+			}
+			if rsp := hs.checkDashboardApplyUpdate(ctx, c, existing, m); rsp != nil {
+				return nil, nil, rsp
+			}
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	if !cmd.Prune {
+		return plan, manifests, nil
+	}
+
+	hits, err := hs.findDashboardsInFolders(ctx, c, folderUIDs)
+	if err != nil {
+		return nil, nil, response.Error(http.StatusInternalServerError, "Failed to search dashboards to prune", err)
+	}
+
+	for _, hit := range hits {
+		if _, ok := manifests[hit.UID]; ok {
+			continue
+		}
+		dash, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: hit.UID, OrgID: orgID})
+		if err != nil {
+			return nil, nil, response.Error(http.StatusInternalServerError, "Failed to get dashboard", err)
+		}
+		g, err := guardian.NewByDashboard(ctx, dash, orgID, c.SignedInUser)
+		if err != nil {
+			return nil, nil, response.Err(err)
+		}
+		if canDelete, err := g.CanDelete(); err != nil || !canDelete {
+			return nil, nil, dashboardGuardianResponse(err)
+		}
+		dependents, err := hs.DashboardService.GetDashboardDependents(ctx, &dashboards.GetDashboardDependentsQuery{OrgID: orgID, UID: dash.UID})
+		if err != nil {
+			return nil, nil, response.Error(http.StatusInternalServerError, "Failed to get the dependents of the dashboard", err)
+		}
+		if !dependents.IsEmpty() {
+			return nil, nil, apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, dashboards.NewDashboardDependentsErr(dependents))
+		}
+		plan.Steps = append(plan.Steps, dtos.DashboardApplyStep{
+			Action:    dtos.DashboardApplyDelete,
+			UID:       hit.UID,
+			Title:     hit.Title,
+			FolderUID: hit.FolderUID,
+			Version:   dash.Version,
+		})
+	}
+
+	return plan, manifests, nil
+}
+
+// checkDashboardApplyUpdate checks that the user can save the existing dashboard and, when the manifest moves it,
+// create dashboards in the target folder.
+func (hs *HTTPServer) checkDashboardApplyUpdate(ctx context.Context, c *contextmodel.ReqContext, existing *dashboards.Dashboard, m dtos.ApplyDashboardManifest) response.Response {
+	g, err := guardian.NewByDashboard(ctx, existing, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canSave, err := g.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+	if existing.FolderUID != m.FolderUID {
+		if canCreate, err := hs.canCreateDashboardInFolder(ctx, c, m.FolderUID); err != nil || !canCreate {
+			return dashboardGuardianResponse(err)
+		}
+	}
+	return nil
+}
+
+func (hs *HTTPServer) canCreateDashboardInFolder(ctx context.Context, c *contextmodel.ReqContext, folderUID string) (bool, error) {
+	if folderUID == "" {
+		folderUID = folder.GeneralFolderUID
+	}
+	evaluator := accesscontrol.EvalPermission(dashboards.ActionDashboardsCreate, dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID))
+	return hs.AccessControl.Evaluate(ctx, c.SignedInUser, evaluator)
+}
+
+func (hs *HTTPServer) findDashboardsInFolders(ctx context.Context, c *contextmodel.ReqContext, folderUIDs map[string]struct{}) ([]dashboards.DashboardSearchProjection, error) {
+	const pageSize = 1000
+	query := &dashboards.FindPersistedDashboardsQuery{
+		OrgId:        c.SignedInUser.GetOrgID(),
+		SignedInUser: c.SignedInUser,
+		Type:         searchstore.TypeDashboard,
+		Limit:        pageSize,
+	}
+	for uid := range folderUIDs {
+		if uid == "" {
+			uid = folder.GeneralFolderUID
+		}
+		query.FolderUIDs = append(query.FolderUIDs, uid)
+	}
+
+	var result []dashboards.DashboardSearchProjection
+	for page := int64(1); ; page++ {
+		query.Page = page
+		hits, err := hs.DashboardService.FindDashboards(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, hits...)
+		if len(hits) < pageSize {
+			return result, nil
+		}
+	}
+}
+
+func (hs *HTTPServer) applyDashboardManifest(ctx context.Context, c *contextmodel.ReqContext, m dtos.ApplyDashboardManifest, message string) (*dashboards.Dashboard, error) {
+	cmd := dashboards.SaveDashboardCommand{
+		Dashboard: m.Dashboard,
+		FolderUID: m.FolderUID,
+		OrgID:     c.SignedInUser.GetOrgID(),
+		Overwrite: true,
+		Message:   message,
+	}
+	namespaceID, userIDstr := c.SignedInUser.GetNamespacedID()
+	if namespaceID == identity.NamespaceUser || namespaceID == identity.NamespaceServiceAccount {
+		if userID, err := identity.IntIdentifier(namespaceID, userIDstr); err == nil {
+			cmd.UserID = userID
+		}
+	}
+	// The manifest is the source of truth, ids from other instances must not be reused.
+	cmd.Dashboard.Del("id")
+	dash := cmd.GetDashboardModel()
+
+	dto := &dashboards.SaveDashboardDTO{
+		Dashboard: dash,
+		Message:   message,
+		OrgID:     c.SignedInUser.GetOrgID(),
+		User:      c.SignedInUser,
+		Overwrite: true,
+	}
+	saved, err := hs.DashboardService.SaveDashboard(alerting.WithUAEnabled(ctx, hs.Cfg.UnifiedAlerting.IsEnabled()), dto, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := hs.LibraryPanelService.ConnectLibraryPanelsForDashboard(ctx, c.SignedInUser, saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// dashboardManifestEqual reports whether applying the manifest would leave the stored dashboard unchanged.
+func dashboardManifestEqual(existing *dashboards.Dashboard, m dtos.ApplyDashboardManifest) (bool, error) {
+	if existing.FolderUID != m.FolderUID {
+		return false, nil
+	}
+
+	a, err := normalizedDashboardModel(existing.Data)
+	if err != nil {
+		return false, err
+	}
+	b, err := normalizedDashboardModel(m.Dashboard)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(a, b), nil
+}
+
+// normalizedDashboardModel returns a generic representation of the dashboard JSON
+// without the fields managed by the store.
+func normalizedDashboardModel(data *simplejson.Json) (map[string]any, error) {
+	b, err := data.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	delete(m, "id")
+	delete(m, "version")
+	return m, nil
+}
+
+// swagger:parameters applyDashboards
+type ApplyDashboardsParams struct {
+	// in:body
+	// required:true
+	Body dtos.ApplyDashboardsCommand
+}
+
+// swagger:response applyDashboardsResponse
+type ApplyDashboardsResponse struct {
+	// in: body
+	Body dtos.DashboardApplyPlan `json:"body"`
+}
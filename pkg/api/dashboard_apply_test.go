@@ -0,0 +1,196 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/licensing/licensingtest"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/api"
+	publicdashboardModels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_ApplyDashboards_DryRun(t *testing.T) {
+	existing := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]any{
+		"uid":   "existing",
+		"title": "Existing",
+		"id":    1,
+	}))
+	existing.ID = 1
+	existing.Version = 3
+
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+			return q.UID == "existing"
+		})).Return(existing, nil)
+		dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound)
+		hs.DashboardService = dashSvc
+
+		hs.Cfg = setting.NewCfg()
+		hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+		guardian.InitAccessControlGuardian(hs.Cfg, hs.AccessControl, hs.DashboardService)
+	})
+
+	permissions := []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsCreate, Scope: "folders:*"},
+		{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:existing"},
+	}
+	send := func(t *testing.T, body string, permissions []accesscontrol.Permission) int {
+		t.Helper()
+		req := server.NewRequest(http.MethodPost, "/api/dashboards/apply", strings.NewReader(body))
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(req, userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res.StatusCode
+	}
+	apply := func(t *testing.T, body string) dtos.DashboardApplyPlan {
+		t.Helper()
+		req := server.NewRequest(http.MethodPost, "/api/dashboards/apply", strings.NewReader(body))
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(req, userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var plan dtos.DashboardApplyPlan
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&plan))
+		return plan
+	}
+
+	t.Run("unchanged dashboards are planned as no-op and unknown ones as create", func(t *testing.T) {
+		plan := apply(t, `{"dryRun": true, "dashboards": [
+			{"dashboard": {"uid": "existing", "title": "Existing", "version": 7}},
+			{"dashboard": {"uid": "new", "title": "New"}}
+		]}`)
+
+		assert.True(t, plan.DryRun)
+		require.Len(t, plan.Steps, 2)
+		assert.Equal(t, dtos.DashboardApplyNoop, plan.Steps[0].Action)
+		assert.Equal(t, 3, plan.Steps[0].Version)
+		assert.Equal(t, dtos.DashboardApplyCreate, plan.Steps[1].Action)
+	})
+
+	t.Run("changed dashboards and folder moves are planned as update", func(t *testing.T) {
+		plan := apply(t, `{"dryRun": true, "dashboards": [
+			{"dashboard": {"uid": "existing", "title": "Renamed"}}
+		]}`)
+		require.Len(t, plan.Steps, 1)
+		assert.Equal(t, dtos.DashboardApplyUpdate, plan.Steps[0].Action)
+
+		plan = apply(t, `{"dryRun": true, "dashboards": [
+			{"dashboard": {"uid": "existing", "title": "Existing"}, "folderUid": "other"}
+		]}`)
+		require.Len(t, plan.Steps, 1)
+		assert.Equal(t, dtos.DashboardApplyUpdate, plan.Steps[0].Action)
+	})
+
+	t.Run("manifests without uid or with duplicate uids are rejected", func(t *testing.T) {
+		for _, body := range []string{
+			`{"dryRun": true, "dashboards": [{"dashboard": {"title": "No uid"}}]}`,
+			`{"dryRun": true, "dashboards": [{"dashboard": {"uid": "a"}}, {"dashboard": {"uid": "a"}}]}`,
+		} {
+			assert.Equal(t, http.StatusBadRequest, send(t, body, permissions))
+		}
+	})
+
+	t.Run("steps the user can't apply are rejected", func(t *testing.T) {
+		body := `{"dryRun": true, "dashboards": [{"dashboard": {"uid": "new", "title": "New"}, "folderUid": "other"}]}`
+		assert.Equal(t, http.StatusForbidden, send(t, body, []accesscontrol.Permission{
+			{Action: dashboards.ActionDashboardsCreate, Scope: "folders:uid:general"},
+		}))
+
+		body = `{"dryRun": true, "dashboards": [{"dashboard": {"uid": "existing", "title": "Renamed"}}]}`
+		assert.Equal(t, http.StatusForbidden, send(t, body, []accesscontrol.Permission{
+			{Action: dashboards.ActionDashboardsCreate, Scope: "folders:*"},
+		}))
+
+		body = `{"dryRun": true, "dashboards": [{"dashboard": {"uid": "existing", "title": "Existing"}, "folderUid": "other"}]}`
+		assert.Equal(t, http.StatusForbidden, send(t, body, []accesscontrol.Permission{
+			{Action: dashboards.ActionDashboardsCreate, Scope: "folders:uid:general"},
+			{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:existing"},
+		}))
+	})
+}
+
+func TestHTTPServer_ApplyDashboards_Prune(t *testing.T) {
+	existing := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]any{
+		"uid":   "existing",
+		"title": "Existing",
+	}))
+	existing.ID = 1
+	stale := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]any{
+		"uid":   "stale",
+		"title": "Stale",
+	}))
+	stale.ID = 2
+
+	libraryElementService := &mockLibraryElementService{}
+	pubDashService := publicdashboards.NewFakePublicDashboardService(t)
+	pubDashService.On("DeleteByDashboard", mock.Anything, stale).Return(nil).Once()
+
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+			return q.UID == "existing"
+		})).Return(existing, nil)
+		dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+			return q.UID == "stale"
+		})).Return(stale, nil)
+		dashSvc.On("FindDashboards", mock.Anything, mock.Anything).Return([]dashboards.DashboardSearchProjection{
+			{UID: "existing", Title: "Existing"},
+			{UID: "stale", Title: "Stale"},
+		}, nil)
+		dashSvc.On("GetDashboardDependents", mock.Anything, mock.Anything).Return(&dashboards.DashboardDependents{}, nil)
+		dashSvc.On("DeleteDashboard", mock.Anything, int64(2), int64(1)).Return(nil).Once()
+		hs.DashboardService = dashSvc
+
+		hs.Cfg = setting.NewCfg()
+		hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+		hs.accesscontrolService = actest.FakeService{}
+		hs.SQLStore = db.InitTestDB(t)
+		hs.LibraryElementService = libraryElementService
+
+		middleware := publicdashboards.NewFakePublicDashboardMiddleware(t)
+		license := licensingtest.NewFakeLicensing()
+		license.On("FeatureEnabled", publicdashboardModels.FeaturePublicDashboardsEmailSharing).Return(false)
+		hs.PublicDashboardsApi = api.ProvideApi(pubDashService, nil, hs.AccessControl, featuremgmt.WithFeatures(), middleware, hs.Cfg, license)
+
+		guardian.InitAccessControlGuardian(hs.Cfg, hs.AccessControl, hs.DashboardService)
+	})
+
+	body := `{"prune": true, "dashboards": [{"dashboard": {"uid": "existing", "title": "Existing"}}]}`
+	req := server.NewRequest(http.MethodPost, "/api/dashboards/apply", strings.NewReader(body))
+	res, err := server.SendJSON(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsCreate, Scope: "folders:*"},
+		{Action: dashboards.ActionDashboardsDelete, Scope: "dashboards:uid:stale"},
+	})))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, res.Body.Close()) }()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var plan dtos.DashboardApplyPlan
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&plan))
+	require.Len(t, plan.Steps, 2)
+	assert.Equal(t, dtos.DashboardApplyNoop, plan.Steps[0].Action)
+	assert.Equal(t, dtos.DashboardApplyDelete, plan.Steps[1].Action)
+
+	// the pruned dashboards are deleted like the ones deleted through the API
+	assert.Equal(t, []int64{2}, libraryElementService.disconnectedDashboardIDs)
+}
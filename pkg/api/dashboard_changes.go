@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+const (
+	defaultDashboardChangesLimit = 100
+	maxDashboardChangesLimit     = 1000
+
+	// dashboardChangesSettleTime holds back the most recent changes, so that a change written by a transaction
+	// that commits after a later one isn't skipped by the consumers that already read the later change
+	dashboardChangesSettleTime = 2 * time.Second
+)
+
+// DashboardChangesResponse is a batch of the change feed of the dashboards
+type DashboardChangesResponse struct {
+	Changes []*dashboards.DashboardChange `json:"changes"`
+	// Cursor is the cursor to read the next batch of the feed after this one
+	Cursor string `json:"cursor"`
+	// HasMore is set when there are more changes after the batch
+	HasMore bool `json:"hasMore"`
+}
+
+// swagger:route GET /dashboards/changes dashboards getDashboardChanges
+//
+// Read the change feed of the dashboards and folders of the organization.
+//
+// The changes are listed in the order they were written, the deleted dashboards and folders are included
+// as changes with the `delete` action. Read the feed with the cursor of the previous response to get the
+// changes after it. Without a cursor, the response only has the cursor of the last change: get it before
+// exporting all the dashboards, then read the changes after the export from it.
+//
+// The changes are kept for the retention of the feed. When the changes after the cursor were deleted,
+// the response is `410`, all the dashboards must then be read again.
+//
+// Responses:
+// 200: getDashboardChangesResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 410: genericError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardChanges(c *contextmodel.ReqContext) response.Response {
+	cursor := c.Query("cursor")
+	afterID, err := parseDashboardExportToken(cursor)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid cursor", err)
+	}
+	limit := c.QueryInt("limit")
+	if limit < 0 || limit > maxDashboardChangesLimit {
+		return response.Error(http.StatusBadRequest, "The limit must be between 1 and 1000", nil)
+	}
+	if limit == 0 {
+		limit = defaultDashboardChangesLimit
+	}
+
+	result, err := hs.DashboardService.ListDashboardChanges(c.Req.Context(), &dashboards.ListDashboardChangesQuery{
+		OrgID:   c.SignedInUser.GetOrgID(),
+		AfterID: afterID,
+		Before:  time.Now().Add(-dashboardChangesSettleTime),
+		Limit:   limit,
+		Head:    cursor == "",
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to read the dashboard changes", err)
+	}
+	if result.Expired {
+		return response.Error(http.StatusGone, "The changes after the cursor expired, all the dashboards must be read again", nil)
+	}
+	return response.JSON(http.StatusOK, DashboardChangesResponse{
+		Changes: result.Changes,
+		Cursor:  dashboardExportToken(result.LastID),
+		HasMore: len(result.Changes) == limit,
+	})
+}
+
+// swagger:parameters getDashboardChanges
+type GetDashboardChangesParams struct {
+	// The cursor of the previous response, the response only has the cursor of the last change without it
+	// in:query
+	// required:false
+	Cursor string `json:"cursor"`
+	// The maximum number of changes, between 1 and 1000
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+}
+
+// swagger:response getDashboardChangesResponse
+type GetDashboardChangesResponse struct {
+	// in:body
+	Body DashboardChangesResponse `json:"body"`
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_GetDashboardChanges(t *testing.T) {
+	stored := []*dashboards.DashboardChange{
+		{ID: 3, DashboardUID: "a", Action: dashboards.DashboardChangeUpsert, Version: 1},
+		{ID: 5, DashboardUID: "a", Action: dashboards.DashboardChangeDelete, Version: 1},
+	}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("ListDashboardChanges", mock.Anything, mock.Anything).Return(
+			func(_ context.Context, q *dashboards.ListDashboardChangesQuery) (*dashboards.ListDashboardChangesResult, error) {
+				result := &dashboards.ListDashboardChangesResult{Changes: []*dashboards.DashboardChange{}, LastID: q.AfterID}
+				switch {
+				case q.Head:
+					result.LastID = 5
+				case q.AfterID == 1:
+					result.Expired = true
+				default:
+					for _, change := range stored {
+						if change.ID > q.AfterID && len(result.Changes) < q.Limit {
+							result.Changes = append(result.Changes, change)
+							result.LastID = change.ID
+						}
+					}
+				}
+				return result, nil
+			}).Maybe()
+		hs.DashboardService = dashSvc
+	})
+
+	canRead := []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsRead, Scope: dashboards.ScopeDashboardsAll},
+		{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll},
+	}
+	get := func(t *testing.T, query string, permissions []accesscontrol.Permission) (int, DashboardChangesResponse) {
+		t.Helper()
+		req := server.NewGetRequest("/api/dashboards/changes" + query)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		var body DashboardChangesResponse
+		if res.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		}
+		require.NoError(t, res.Body.Close())
+		return res.StatusCode, body
+	}
+
+	t.Run("should require the permission to read all the dashboards and folders", func(t *testing.T) {
+		status, _ := get(t, "", canRead[:1])
+		assert.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("should only return the cursor of the last change without a cursor", func(t *testing.T) {
+		status, body := get(t, "", canRead)
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, body.Changes)
+		assert.Equal(t, dashboardExportToken(5), body.Cursor)
+	})
+
+	t.Run("should return the changes after the cursor", func(t *testing.T) {
+		status, body := get(t, "?limit=1&cursor="+dashboardExportToken(0), canRead)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, body.Changes, 1)
+		assert.Equal(t, "a", body.Changes[0].DashboardUID)
+		assert.True(t, body.HasMore)
+
+		status, body = get(t, "?limit=1&cursor="+body.Cursor, canRead)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, body.Changes, 1)
+		assert.Equal(t, dashboards.DashboardChangeDelete, body.Changes[0].Action)
+		assert.Equal(t, dashboardExportToken(5), body.Cursor)
+	})
+
+	t.Run("should reject the expired and invalid cursors", func(t *testing.T) {
+		status, _ := get(t, "?cursor="+dashboardExportToken(1), canRead)
+		assert.Equal(t, http.StatusGone, status)
+		status, _ = get(t, "?cursor=invalid!", canRead)
+		assert.Equal(t, http.StatusBadRequest, status)
+		status, _ = get(t, "?limit=5000&cursor="+dashboardExportToken(0), canRead)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const maxDashboardChecksumsBatchSize = 500
+
+// DashboardChecksum is the checksum of the JSON of a dashboard, see dashboards.DashboardChecksum
+type DashboardChecksum struct {
+	UID      string `json:"uid"`
+	Version  int    `json:"version"`
+	Checksum string `json:"checksum"`
+}
+
+type DashboardChecksumsForm struct {
+	// The UIDs of the dashboards, at most 500
+	UIDs []string `json:"uids"`
+}
+
+type DashboardChecksumsResponse struct {
+	Checksums []DashboardChecksum `json:"checksums"`
+	// NotFound are the UIDs of the dashboards that don't exist or that the user can't view
+	NotFound []string `json:"notFound"`
+}
+
+// swagger:route GET /dashboards/uid/{uid}/checksum dashboards getDashboardChecksum
+//
+// Get the checksum of a dashboard.
+//
+// The checksum is the SHA-256 hash of the dashboard JSON returned by the dashboard API, normalized and
+// without its `id` and `version`, to compare a dashboard with a copy without downloading it.
+//
+// Responses:
+// 200: getDashboardChecksumResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardChecksum(c *contextmodel.ReqContext) response.Response {
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	checksum, err := hs.dashboardChecksum(c.Req.Context(), c.SignedInUser, dash)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to compute the dashboard checksum", err)
+	}
+	if checksum == nil {
+		return dashboardGuardianResponse(nil)
+	}
+	return response.JSON(http.StatusOK, checksum)
+}
+
+// swagger:route POST /dashboards/checksums dashboards getDashboardChecksums
+//
+// Get the checksums of a batch of dashboards.
+//
+// The dashboards that don't exist or that the user can't view are listed as not found.
+//
+// Responses:
+// 200: getDashboardChecksumsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardChecksums(c *contextmodel.ReqContext) response.Response {
+	form := DashboardChecksumsForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+	if len(form.UIDs) == 0 || len(form.UIDs) > maxDashboardChecksumsBatchSize {
+		return response.Error(http.StatusBadRequest, "Between 1 and 500 dashboard UIDs are required", nil)
+	}
+
+	ctx := c.Req.Context()
+	dashs, err := hs.DashboardService.GetDashboards(ctx, &dashboards.GetDashboardsQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		DashboardUIDs: form.UIDs,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get dashboards", err)
+	}
+
+	found := make(map[string]DashboardChecksum, len(dashs))
+	for _, dash := range dashs {
+		if dash.IsFolder {
+			continue
+		}
+		checksum, err := hs.dashboardChecksum(ctx, c.SignedInUser, dash)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to compute the dashboard checksums", err)
+		}
+		if checksum != nil {
+			found[dash.UID] = *checksum
+		}
+	}
+
+	result := DashboardChecksumsResponse{Checksums: make([]DashboardChecksum, 0, len(found)), NotFound: []string{}}
+	seen := make(map[string]bool, len(form.UIDs))
+	for _, uid := range form.UIDs {
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		if checksum, ok := found[uid]; ok {
+			result.Checksums = append(result.Checksums, checksum)
+		} else {
+			result.NotFound = append(result.NotFound, uid)
+		}
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// dashboardChecksum returns the checksum of the dashboard as the user gets it from the dashboard API, or nil if
+// the user can't view the dashboard
+func (hs *HTTPServer) dashboardChecksum(ctx context.Context, user identity.Requester, dash *dashboards.Dashboard) (*DashboardChecksum, error) {
+	g, err := guardian.NewByDashboard(ctx, dash, user.GetOrgID(), user)
+	if err != nil {
+		return nil, err
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return nil, err
+	}
+	if dash.Data == nil {
+		dash.Data = simplejson.New()
+	}
+	if canSave, _ := g.CanSave(); !canSave {
+		dash.RemoveHiddenPanels(hs.panelVisibleTo(ctx, user))
+	}
+	if err := hs.decryptSensitiveDashboardFields(ctx, user, dash.UID, dash.Data); err != nil {
+		return nil, err
+	}
+
+	checksum, err := dashboards.DashboardChecksum(dash.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &DashboardChecksum{UID: dash.UID, Version: dash.Version, Checksum: checksum}, nil
+}
+
+// swagger:parameters getDashboardChecksum
+type GetDashboardChecksumParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters getDashboardChecksums
+type GetDashboardChecksumsParams struct {
+	// in:body
+	// required:true
+	Body DashboardChecksumsForm
+}
+
+// swagger:response getDashboardChecksumResponse
+type GetDashboardChecksumResponse struct {
+	// in:body
+	Body DashboardChecksum `json:"body"`
+}
+
+// swagger:response getDashboardChecksumsResponse
+type GetDashboardChecksumsResponse struct {
+	// in:body
+	Body DashboardChecksumsResponse `json:"body"`
+}
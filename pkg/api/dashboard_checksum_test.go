@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_GetDashboardChecksums(t *testing.T) {
+	newDashboard := func(id int64, uid string) *dashboards.Dashboard {
+		dash := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]any{"id": id, "uid": uid, "title": uid, "version": 2}))
+		dash.ID, dash.OrgID, dash.Version = id, 1, 2
+		return dash
+	}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+			return q.UID == "a"
+		})).Return(newDashboard(1, "a"), nil).Maybe()
+		dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
+		dashSvc.On("GetDashboards", mock.Anything, mock.Anything).Return([]*dashboards.Dashboard{newDashboard(1, "a"), newDashboard(2, "b")}, nil).Maybe()
+		hs.DashboardService = dashSvc
+
+		hs.Cfg = setting.NewCfg()
+		hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+		guardian.InitAccessControlGuardian(hs.Cfg, hs.AccessControl, hs.DashboardService)
+	})
+	canReadA := []accesscontrol.Permission{{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:a"}}
+	expected, err := dashboards.DashboardChecksum(newDashboard(1, "a").Data)
+	require.NoError(t, err)
+
+	t.Run("should return the checksum and version of a dashboard", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/dashboards/uid/a/checksum"), userWithPermissions(1, canReadA)))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var checksum DashboardChecksum
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&checksum))
+		assert.Equal(t, DashboardChecksum{UID: "a", Version: 2, Checksum: expected}, checksum)
+	})
+
+	t.Run("should not return the checksum of a dashboard the user can't view", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/dashboards/uid/a/checksum"), userWithPermissions(1, []accesscontrol.Permission{
+			{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:b"},
+		})))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		res, err = server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/dashboards/uid/missing/checksum"), userWithPermissions(1, canReadA)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should return the checksums of a batch of dashboards", func(t *testing.T) {
+		req := server.NewRequest(http.MethodPost, "/api/dashboards/checksums", strings.NewReader(`{"uids": ["a", "b", "missing", "a"]}`))
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(req, userWithPermissions(1, canReadA)))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var result DashboardChecksumsResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+		assert.Equal(t, []DashboardChecksum{{UID: "a", Version: 2, Checksum: expected}}, result.Checksums)
+		assert.Equal(t, []string{"b", "missing"}, result.NotFound)
+	})
+
+	t.Run("should reject empty batches", func(t *testing.T) {
+		req := server.NewRequest(http.MethodPost, "/api/dashboards/checksums", strings.NewReader(`{"uids": []}`))
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(req, userWithPermissions(1, canReadA)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -0,0 +1,404 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/events"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	embeddingPolicyNamespace = "embedding_policy"
+	// embeddingPolicyCacheTTL bounds how long other instances keep using the previous policy after an update
+	embeddingPolicyCacheTTL = time.Minute
+)
+
+var (
+	ErrInvalidEmbeddingPolicy  = errutil.BadRequest("dashboards.invalid-embedding-policy")
+	ErrEmbeddingPolicyNotFound = errutil.NotFound("dashboards.embedding-policy-not-found", errutil.WithPublicMessage("Embedding policy not found"))
+)
+
+// EmbeddingPolicy lists the origins that are allowed to embed a dashboard, or the dashboards of a folder,
+// in an iframe. An empty list prevents the dashboards from being embedded.
+type EmbeddingPolicy struct {
+	AllowedOrigins []string `json:"allowedOrigins"`
+}
+
+func embeddingPolicyKey(resource, uid string) string {
+	return resource + "/" + uid
+}
+
+func embeddingPolicyCacheKey(orgID int64, key string) string {
+	return fmt.Sprintf("embedding-policy-%d-%s", orgID, key)
+}
+
+func embeddingFoldersCacheKey(orgID int64, dashboardUID string) string {
+	return fmt.Sprintf("embedding-policy-folders-%d-%s", orgID, dashboardUID)
+}
+
+// validateEmbeddingOrigin accepts http(s) origins without path, for example https://example.com:8443
+func validateEmbeddingOrigin(origin string) error {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidEmbeddingPolicy.Errorf("invalid origin %q", origin)
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return ErrInvalidEmbeddingPolicy.Errorf("origin %q must not have a path, query or credentials", origin)
+	}
+	return nil
+}
+
+func (hs *HTTPServer) getEmbeddingPolicy(ctx context.Context, orgID int64, key string) (*EmbeddingPolicy, error) {
+	cacheKey := embeddingPolicyCacheKey(orgID, key)
+	if cached, ok := hs.CacheService.Get(cacheKey); ok {
+		return cached.(*EmbeddingPolicy), nil
+	}
+
+	value, ok, err := hs.kvStore.Get(ctx, orgID, embeddingPolicyNamespace, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// resources without a policy are cached as well since the policy is resolved on every page load
+	var policy *EmbeddingPolicy
+	if ok {
+		policy = &EmbeddingPolicy{}
+		if err := json.Unmarshal([]byte(value), policy); err != nil {
+			return nil, fmt.Errorf("failed to parse embedding policy %s: %w", key, err)
+		}
+	}
+
+	hs.CacheService.Set(cacheKey, policy, embeddingPolicyCacheTTL)
+	return policy, nil
+}
+
+func (hs *HTTPServer) setEmbeddingPolicy(ctx context.Context, orgID int64, key string, policy *EmbeddingPolicy) error {
+	origins := make([]string, 0, len(policy.AllowedOrigins))
+	for _, origin := range policy.AllowedOrigins {
+		origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+		if err := validateEmbeddingOrigin(origin); err != nil {
+			return err
+		}
+		origins = append(origins, origin)
+	}
+
+	value, err := json.Marshal(&EmbeddingPolicy{AllowedOrigins: origins})
+	if err != nil {
+		return err
+	}
+	if err := hs.kvStore.Set(ctx, orgID, embeddingPolicyNamespace, key, string(value)); err != nil {
+		return err
+	}
+
+	hs.CacheService.Delete(embeddingPolicyCacheKey(orgID, key))
+	return nil
+}
+
+func (hs *HTTPServer) deleteEmbeddingPolicy(ctx context.Context, orgID int64, key string) error {
+	if err := hs.kvStore.Del(ctx, orgID, embeddingPolicyNamespace, key); err != nil {
+		return err
+	}
+
+	hs.CacheService.Delete(embeddingPolicyCacheKey(orgID, key))
+	return nil
+}
+
+// resolveEmbeddingPolicy returns the policy of the dashboard, or else the policy of its closest folder.
+// It returns nil when none of them has a policy and the allow_embedding setting applies.
+func (hs *HTTPServer) resolveEmbeddingPolicy(ctx context.Context, orgID int64, dashboardUID string) (*EmbeddingPolicy, error) {
+	policy, err := hs.getEmbeddingPolicy(ctx, orgID, embeddingPolicyKey("dashboard", dashboardUID))
+	if err != nil || policy != nil {
+		return policy, err
+	}
+
+	folderUIDs, err := hs.getEmbeddingFolderUIDs(ctx, orgID, dashboardUID)
+	if err != nil {
+		return nil, err
+	}
+	for _, uid := range folderUIDs {
+		policy, err := hs.getEmbeddingPolicy(ctx, orgID, embeddingPolicyKey("folder", uid))
+		if err != nil || policy != nil {
+			return policy, err
+		}
+	}
+	return nil, nil
+}
+
+// getEmbeddingFolderUIDs returns the folder of the dashboard followed by its parents. They are cached since
+// the policy is resolved on every page load, so a moved dashboard or folder gets the policy of its new folders
+// once the cache expires.
+func (hs *HTTPServer) getEmbeddingFolderUIDs(ctx context.Context, orgID int64, dashboardUID string) ([]string, error) {
+	cacheKey := embeddingFoldersCacheKey(orgID, dashboardUID)
+	if cached, ok := hs.CacheService.Get(cacheKey); ok {
+		return cached.([]string), nil
+	}
+
+	var folderUIDs []string
+	dash, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: dashboardUID, OrgID: orgID})
+	if err != nil && !errors.Is(err, dashboards.ErrDashboardNotFound) {
+		return nil, err
+	}
+	if err == nil && dash.FolderUID != "" {
+		folderUIDs = append(folderUIDs, dash.FolderUID)
+		parents, err := hs.folderService.GetParents(ctx, folder.GetParentsQuery{UID: dash.FolderUID, OrgID: orgID})
+		if err != nil {
+			return nil, err
+		}
+		// parents are ordered from the root folder
+		for i := len(parents) - 1; i >= 0; i-- {
+			folderUIDs = append(folderUIDs, parents[i].UID)
+		}
+	}
+
+	hs.CacheService.Set(cacheKey, folderUIDs, embeddingPolicyCacheTTL)
+	return folderUIDs, nil
+}
+
+// handleEmbeddingDashboardDeleted deletes the embedding policy and the panel embeds of a deleted dashboard,
+// or the embedding policy of a deleted folder. The dashboards of a deleted organization are handled one by one.
+func (hs *HTTPServer) handleEmbeddingDashboardDeleted(ctx context.Context, evt *events.DashboardDeleted) error {
+	resource := "dashboard"
+	if evt.IsFolder {
+		resource = "folder"
+	}
+	if err := hs.deleteEmbeddingPolicy(ctx, evt.OrgID, embeddingPolicyKey(resource, evt.UID)); err != nil {
+		return err
+	}
+	if evt.IsFolder {
+		return nil
+	}
+
+	hs.CacheService.Delete(embeddingFoldersCacheKey(evt.OrgID, evt.UID))
+	keys, err := hs.kvStore.Keys(ctx, evt.OrgID, panelEmbedNamespace, evt.UID+"/")
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := hs.kvStore.Del(ctx, evt.OrgID, panelEmbedNamespace, key.Key); err != nil {
+			return err
+		}
+		hs.CacheService.Delete(panelEmbedCacheKey(strings.TrimPrefix(key.Key, evt.UID+"/")))
+	}
+	return nil
+}
+
+// frameAncestorsDirective returns the frame-ancestors CSP directive enforcing the policy
+func frameAncestorsDirective(policy *EmbeddingPolicy) string {
+	if len(policy.AllowedOrigins) == 0 {
+		return "frame-ancestors 'none'"
+	}
+	return "frame-ancestors 'self' " + strings.Join(policy.AllowedOrigins, " ")
+}
+
+// withFrameAncestors replaces the frame-ancestors directive of a CSP, or adds it
+func withFrameAncestors(csp string, directive string) string {
+	directives := []string{}
+	for _, d := range strings.Split(csp, ";") {
+		d = strings.TrimSpace(d)
+		if d == "" || strings.HasPrefix(strings.ToLower(d), "frame-ancestors") {
+			continue
+		}
+		directives = append(directives, d)
+	}
+	return strings.Join(append(directives, directive), "; ")
+}
+
+// applyDashboardEmbeddingPolicy enforces the embedding policy of the dashboard of the page, if any,
+// through the frame-ancestors directive of the Content-Security-Policy header.
+func (hs *HTTPServer) applyDashboardEmbeddingPolicy(c *contextmodel.ReqContext) {
+	uid := web.Params(c.Req)[":uid"]
+	if uid == "" || c.SignedInUser == nil {
+		return
+	}
+
+	policy, err := hs.resolveEmbeddingPolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), uid)
+	if err != nil {
+		hs.log.Warn("Failed to resolve dashboard embedding policy", "dashboardUid", uid, "error", err)
+		return
+	}
+	if policy == nil {
+		return
+	}
+
+	c.Resp.Before(func(w web.ResponseWriter) {
+		if w.Written() {
+			return
+		}
+		w.Header().Set("Content-Security-Policy", withFrameAncestors(w.Header().Get("Content-Security-Policy"), frameAncestorsDirective(policy)))
+		if len(policy.AllowedOrigins) > 0 {
+			// skips the X-Frame-Options header, which can't allow other origins
+			w.Header().Set("X-Allow-Embedding", "allow")
+		} else {
+			w.Header().Set("X-Frame-Options", "deny")
+		}
+	})
+}
+
+// swagger:route GET /dashboards/uid/{uid}/embedding-policy dashboards getDashboardEmbeddingPolicy
+//
+// Get the embedding policy of a dashboard.
+//
+// Responses:
+// 200: embeddingPolicyResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardEmbeddingPolicy(c *contextmodel.ReqContext) response.Response {
+	return hs.getEmbeddingPolicyResponse(c, embeddingPolicyKey("dashboard", web.Params(c.Req)[":uid"]))
+}
+
+// swagger:route PUT /dashboards/uid/{uid}/embedding-policy dashboards updateDashboardEmbeddingPolicy
+//
+// Update the embedding policy of a dashboard.
+//
+// The policy takes precedence over the policies of the folders of the dashboard and the allow_embedding setting.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateDashboardEmbeddingPolicy(c *contextmodel.ReqContext) response.Response {
+	return hs.updateEmbeddingPolicyResponse(c, embeddingPolicyKey("dashboard", web.Params(c.Req)[":uid"]))
+}
+
+// swagger:route DELETE /dashboards/uid/{uid}/embedding-policy dashboards deleteDashboardEmbeddingPolicy
+//
+// Delete the embedding policy of a dashboard.
+//
+// The dashboard falls back to the policies of its folders and the allow_embedding setting.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteDashboardEmbeddingPolicy(c *contextmodel.ReqContext) response.Response {
+	return hs.deleteEmbeddingPolicyResponse(c, embeddingPolicyKey("dashboard", web.Params(c.Req)[":uid"]))
+}
+
+// swagger:route GET /folders/{folder_uid}/embedding-policy folders getFolderEmbeddingPolicy
+//
+// Get the embedding policy of a folder.
+//
+// Responses:
+// 200: embeddingPolicyResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetFolderEmbeddingPolicy(c *contextmodel.ReqContext) response.Response {
+	return hs.getEmbeddingPolicyResponse(c, embeddingPolicyKey("folder", web.Params(c.Req)[":uid"]))
+}
+
+// swagger:route PUT /folders/{folder_uid}/embedding-policy folders updateFolderEmbeddingPolicy
+//
+// Update the embedding policy of a folder.
+//
+// The policy applies to the dashboards of the folder and its subfolders that don't have a policy of their own.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateFolderEmbeddingPolicy(c *contextmodel.ReqContext) response.Response {
+	return hs.updateEmbeddingPolicyResponse(c, embeddingPolicyKey("folder", web.Params(c.Req)[":uid"]))
+}
+
+// swagger:route DELETE /folders/{folder_uid}/embedding-policy folders deleteFolderEmbeddingPolicy
+//
+// Delete the embedding policy of a folder.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteFolderEmbeddingPolicy(c *contextmodel.ReqContext) response.Response {
+	return hs.deleteEmbeddingPolicyResponse(c, embeddingPolicyKey("folder", web.Params(c.Req)[":uid"]))
+}
+
+func (hs *HTTPServer) getEmbeddingPolicyResponse(c *contextmodel.ReqContext, key string) response.Response {
+	policy, err := hs.getEmbeddingPolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), key)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get embedding policy", err)
+	}
+	if policy == nil {
+		return response.Err(ErrEmbeddingPolicyNotFound.Errorf("no embedding policy for %s", key))
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
+func (hs *HTTPServer) updateEmbeddingPolicyResponse(c *contextmodel.ReqContext, key string) response.Response {
+	policy := EmbeddingPolicy{}
+	if err := web.Bind(c.Req, &policy); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if err := hs.setEmbeddingPolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), key, &policy); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update embedding policy", err)
+	}
+	return response.Success("Embedding policy updated")
+}
+
+func (hs *HTTPServer) deleteEmbeddingPolicyResponse(c *contextmodel.ReqContext, key string) response.Response {
+	if err := hs.deleteEmbeddingPolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), key); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete embedding policy", err)
+	}
+	return response.Success("Embedding policy deleted")
+}
+
+// swagger:parameters getDashboardEmbeddingPolicy deleteDashboardEmbeddingPolicy
+type DashboardEmbeddingPolicyParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters updateDashboardEmbeddingPolicy
+type UpdateDashboardEmbeddingPolicyParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body EmbeddingPolicy `json:"body"`
+}
+
+// swagger:parameters getFolderEmbeddingPolicy deleteFolderEmbeddingPolicy
+type FolderEmbeddingPolicyParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+}
+
+// swagger:parameters updateFolderEmbeddingPolicy
+type UpdateFolderEmbeddingPolicyParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+	// in:body
+	// required:true
+	Body EmbeddingPolicy `json:"body"`
+}
+
+// swagger:response embeddingPolicyResponse
+type EmbeddingPolicyResponse struct {
+	// in:body
+	Body EmbeddingPolicy `json:"body"`
+}
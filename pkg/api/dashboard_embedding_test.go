@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+)
+
+func TestDashboardEmbeddingPolicy(t *testing.T) {
+	newServer := func(t *testing.T) *HTTPServer {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "dash", FolderUID: "child"}, nil).Maybe()
+		return &HTTPServer{
+			kvStore:          kvstore.NewFakeKVStore(),
+			CacheService:     localcache.ProvideService(),
+			DashboardService: dashSvc,
+			folderService:    &foldertest.FakeService{ExpectedFolders: []*folder.Folder{{UID: "root"}, {UID: "parent"}}},
+		}
+	}
+	ctx := context.Background()
+
+	t.Run("should resolve the policy of the dashboard before the policies of its folders", func(t *testing.T) {
+		hs := newServer(t)
+		require.NoError(t, hs.setEmbeddingPolicy(ctx, 1, embeddingPolicyKey("folder", "parent"), &EmbeddingPolicy{AllowedOrigins: []string{"https://parent.example.com"}}))
+		require.NoError(t, hs.setEmbeddingPolicy(ctx, 1, embeddingPolicyKey("folder", "root"), &EmbeddingPolicy{AllowedOrigins: []string{"https://root.example.com"}}))
+
+		policy, err := hs.resolveEmbeddingPolicy(ctx, 1, "dash")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://parent.example.com"}, policy.AllowedOrigins)
+
+		require.NoError(t, hs.setEmbeddingPolicy(ctx, 1, embeddingPolicyKey("dashboard", "dash"), &EmbeddingPolicy{AllowedOrigins: []string{"https://example.com/"}}))
+		policy, err = hs.resolveEmbeddingPolicy(ctx, 1, "dash")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com"}, policy.AllowedOrigins)
+
+		require.NoError(t, hs.deleteEmbeddingPolicy(ctx, 1, embeddingPolicyKey("dashboard", "dash")))
+		policy, err = hs.resolveEmbeddingPolicy(ctx, 1, "dash")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://parent.example.com"}, policy.AllowedOrigins)
+	})
+
+	t.Run("should fall back to the allow_embedding setting without policy", func(t *testing.T) {
+		hs := newServer(t)
+		policy, err := hs.resolveEmbeddingPolicy(ctx, 1, "dash")
+		require.NoError(t, err)
+		assert.Nil(t, policy)
+	})
+
+	t.Run("should reject invalid origins", func(t *testing.T) {
+		hs := newServer(t)
+		for _, origin := range []string{"example.com", "ftp://example.com", "https://example.com/path", "https://user@example.com", "*"} {
+			err := hs.setEmbeddingPolicy(ctx, 1, embeddingPolicyKey("folder", "root"), &EmbeddingPolicy{AllowedOrigins: []string{origin}})
+			assert.ErrorIs(t, err, ErrInvalidEmbeddingPolicy, origin)
+		}
+	})
+}
+
+func TestWithFrameAncestors(t *testing.T) {
+	assert.Equal(t, "frame-ancestors 'none'", withFrameAncestors("", frameAncestorsDirective(&EmbeddingPolicy{})))
+	assert.Equal(t,
+		"script-src 'self'; frame-ancestors 'self' https://example.com",
+		withFrameAncestors("script-src 'self'; frame-ancestors 'self';", frameAncestorsDirective(&EmbeddingPolicy{AllowedOrigins: []string{"https://example.com"}})),
+	)
+}
+
+func TestDashboardEmbeddingPolicy_Cache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should resolve the folders of the dashboard once", func(t *testing.T) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "dash", FolderUID: "child"}, nil).Once()
+		hs := &HTTPServer{
+			kvStore:          kvstore.NewFakeKVStore(),
+			CacheService:     localcache.ProvideService(),
+			DashboardService: dashSvc,
+			folderService:    &foldertest.FakeService{ExpectedFolders: []*folder.Folder{{UID: "root"}}},
+		}
+
+		for i := 0; i < 3; i++ {
+			policy, err := hs.resolveEmbeddingPolicy(ctx, 1, "dash")
+			require.NoError(t, err)
+			assert.Nil(t, policy)
+		}
+
+		// a new folder policy applies right away
+		require.NoError(t, hs.setEmbeddingPolicy(ctx, 1, embeddingPolicyKey("folder", "root"), &EmbeddingPolicy{AllowedOrigins: []string{"https://root.example.com"}}))
+		policy, err := hs.resolveEmbeddingPolicy(ctx, 1, "dash")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://root.example.com"}, policy.AllowedOrigins)
+	})
+
+	t.Run("should delete the policy and the panel embeds of deleted dashboards and folders", func(t *testing.T) {
+		kvStore := kvstore.NewFakeKVStore()
+		hs := &HTTPServer{kvStore: kvStore, CacheService: localcache.ProvideService()}
+		require.NoError(t, hs.setEmbeddingPolicy(ctx, 1, embeddingPolicyKey("dashboard", "dash"), &EmbeddingPolicy{}))
+		require.NoError(t, hs.setEmbeddingPolicy(ctx, 1, embeddingPolicyKey("folder", "dash"), &EmbeddingPolicy{}))
+		require.NoError(t, kvStore.Set(ctx, 1, panelEmbedNamespace, panelEmbedKey("dash", "embed"), "{}"))
+		require.NoError(t, kvStore.Set(ctx, 1, panelEmbedNamespace, panelEmbedKey("other", "embed"), "{}"))
+		policy, err := hs.getEmbeddingPolicy(ctx, 1, embeddingPolicyKey("dashboard", "dash"))
+		require.NoError(t, err)
+		require.NotNil(t, policy)
+
+		require.NoError(t, hs.handleEmbeddingDashboardDeleted(ctx, &events.DashboardDeleted{OrgID: 1, UID: "dash"}))
+		policy, err = hs.getEmbeddingPolicy(ctx, 1, embeddingPolicyKey("dashboard", "dash"))
+		require.NoError(t, err)
+		assert.Nil(t, policy)
+		keys, err := kvStore.Keys(ctx, 1, panelEmbedNamespace, "")
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, panelEmbedKey("other", "embed"), keys[0].Key)
+
+		policy, err = hs.getEmbeddingPolicy(ctx, 1, embeddingPolicyKey("folder", "dash"))
+		require.NoError(t, err)
+		require.NotNil(t, policy)
+		require.NoError(t, hs.handleEmbeddingDashboardDeleted(ctx, &events.DashboardDeleted{OrgID: 1, UID: "dash", IsFolder: true}))
+		policy, err = hs.getEmbeddingPolicy(ctx, 1, embeddingPolicyKey("folder", "dash"))
+		require.NoError(t, err)
+		assert.Nil(t, policy)
+	})
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+const dashboardExportBatchSize = 100
+
+// DashboardExportLine is a line of the NDJSON export of the dashboards. The last line of the export
+// only has the continue token and the number of exported dashboards.
+type DashboardExportLine struct {
+	UID       string           `json:"uid,omitempty"`
+	Title     string           `json:"title,omitempty"`
+	FolderUID string           `json:"folderUid,omitempty"`
+	Version   int              `json:"version,omitempty"`
+	Updated   *time.Time       `json:"updated,omitempty"`
+	Dashboard *simplejson.Json `json:"dashboard,omitempty"`
+	// Continue is the token resuming the export after this dashboard. It's empty on the last line
+	// when all the dashboards were exported.
+	Continue string `json:"continue"`
+	// Exported is the number of dashboards in the response, it's only set on the last line
+	Exported int `json:"exported,omitempty"`
+}
+
+// swagger:route GET /dashboards/export dashboards exportDashboards
+//
+// Export all the dashboards of the organization as newline-delimited JSON.
+//
+// The dashboards are streamed in the order they were created, one JSON object per line, and read in
+// batches, so that the export doesn't need to fit in memory. Each line has a continue token resuming
+// the export after its dashboard, the last line has the token of the next export when the limit is
+// reached, or an empty token when all the dashboards were exported.
+//
+// Produces:
+// - application/x-ndjson
+//
+// Responses:
+// 200: exportDashboardsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) ExportDashboards(c *contextmodel.ReqContext) {
+	afterID, err := parseDashboardExportToken(c.Query("continue"))
+	if err != nil {
+		c.JsonApiErr(http.StatusBadRequest, "Invalid continue token", err)
+		return
+	}
+	limit := c.QueryInt("limit")
+	if limit < 0 {
+		c.JsonApiErr(http.StatusBadRequest, "The limit can't be negative", nil)
+		return
+	}
+
+	limiter := rate.NewLimiter(rate.Inf, dashboardExportBatchSize)
+	if hs.Cfg.DashboardExportRate > 0 {
+		limiter = rate.NewLimiter(rate.Limit(hs.Cfg.DashboardExportRate), dashboardExportBatchSize)
+	}
+
+	ctx := c.Req.Context()
+	encoder := json.NewEncoder(c.Resp)
+	exported := 0
+	started := false
+	for done := false; !done; {
+		batchSize := dashboardExportBatchSize
+		if limit > 0 && limit-exported < batchSize {
+			batchSize = limit - exported
+		}
+		batch, err := hs.DashboardService.ListDashboardsAfter(ctx, &dashboards.ListDashboardsAfterQuery{
+			OrgID:   c.SignedInUser.GetOrgID(),
+			AfterID: afterID,
+			Limit:   batchSize,
+		})
+		if err == nil && len(batch) > 0 {
+			err = limiter.WaitN(ctx, len(batch))
+		}
+		if err != nil {
+			if !started {
+				c.JsonApiErr(http.StatusInternalServerError, "Failed to export dashboards", err)
+				return
+			}
+			// the status is already sent, the export can be resumed with the token of the last line
+			c.Logger.Error("Failed to export dashboards", "error", err)
+			return
+		}
+
+		if !started {
+			started = true
+			c.Resp.Header().Set("Content-Type", "application/x-ndjson")
+			c.Resp.WriteHeader(http.StatusOK)
+		}
+		for _, dash := range batch {
+			if err := hs.decryptSensitiveDashboardFields(ctx, c.SignedInUser, dash.UID, dash.Data); err != nil {
+				c.Logger.Error("Failed to export dashboards", "dashboardUid", dash.UID, "error", err)
+				return
+			}
+			afterID = dash.ID
+			updated := dash.Updated
+			if err := encoder.Encode(&DashboardExportLine{
+				UID:       dash.UID,
+				Title:     dash.Title,
+				FolderUID: dash.FolderUID,
+				Version:   dash.Version,
+				Updated:   &updated,
+				Dashboard: dash.Data,
+				Continue:  dashboardExportToken(afterID),
+			}); err != nil {
+				c.Logger.Error("Failed to export dashboards", "error", err)
+				return
+			}
+		}
+		exported += len(batch)
+		c.Resp.Flush()
+
+		switch {
+		case len(batch) < batchSize:
+			// all the dashboards were exported
+			afterID, done = 0, true
+		case limit > 0 && exported >= limit:
+			done = true
+		}
+	}
+
+	last := &DashboardExportLine{Exported: exported}
+	if afterID > 0 {
+		last.Continue = dashboardExportToken(afterID)
+	}
+	if err := encoder.Encode(last); err != nil {
+		c.Logger.Error("Failed to export dashboards", "error", err)
+	}
+}
+
+func dashboardExportToken(afterID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(afterID, 10)))
+}
+
+func parseDashboardExportToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	afterID, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil || afterID < 0 {
+		return 0, fmt.Errorf("invalid dashboard ID %q", b)
+	}
+	return afterID, nil
+}
+
+// swagger:parameters exportDashboards
+type ExportDashboardsParams struct {
+	// The token of the line to resume the export after
+	// in:query
+	// required:false
+	Continue string `json:"continue"`
+	// The maximum number of exported dashboards, 0 exports all the dashboards
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+// swagger:response exportDashboardsResponse
+type ExportDashboardsResponse struct {
+	// in:body
+	Body []DashboardExportLine `json:"body"`
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_ExportDashboards(t *testing.T) {
+	stored := []*dashboards.Dashboard{
+		{ID: 1, UID: "a", Title: "A", Version: 2, Data: simplejson.NewFromAny(map[string]any{"title": "A"})},
+		{ID: 4, UID: "b", Title: "B", FolderUID: "ops", Version: 1, Data: simplejson.NewFromAny(map[string]any{"title": "B"})},
+		{ID: 7, UID: "c", Title: "C", Version: 5, Data: simplejson.NewFromAny(map[string]any{"title": "C"})},
+	}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("ListDashboardsAfter", mock.Anything, mock.Anything).Return(
+			func(_ context.Context, q *dashboards.ListDashboardsAfterQuery) ([]*dashboards.Dashboard, error) {
+				batch := []*dashboards.Dashboard{}
+				for _, dash := range stored {
+					if dash.ID > q.AfterID && len(batch) < q.Limit {
+						batch = append(batch, dash)
+					}
+				}
+				return batch, nil
+			}).Maybe()
+		hs.DashboardService = dashSvc
+	})
+
+	canExport := []accesscontrol.Permission{{Action: dashboards.ActionDashboardsRead, Scope: dashboards.ScopeDashboardsAll}}
+	export := func(t *testing.T, query string) []DashboardExportLine {
+		t.Helper()
+		req := server.NewGetRequest("/api/dashboards/export" + query)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, canExport)))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+
+		var lines []DashboardExportLine
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			var line DashboardExportLine
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+		require.NoError(t, scanner.Err())
+		require.NoError(t, res.Body.Close())
+		return lines
+	}
+
+	t.Run("should require the permission to read all the dashboards", func(t *testing.T) {
+		req := server.NewGetRequest("/api/dashboards/export")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{
+			{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:a"},
+		})))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should export all the dashboards", func(t *testing.T) {
+		lines := export(t, "")
+		require.Len(t, lines, 4)
+		assert.Equal(t, "a", lines[0].UID)
+		assert.Equal(t, "ops", lines[1].FolderUID)
+		assert.Equal(t, "C", lines[2].Dashboard.Get("title").MustString())
+		assert.Equal(t, DashboardExportLine{Exported: 3}, lines[3])
+	})
+
+	t.Run("should resume the export with the continue token", func(t *testing.T) {
+		lines := export(t, "?limit=2")
+		require.Len(t, lines, 3)
+		assert.Equal(t, []string{"a", "b"}, []string{lines[0].UID, lines[1].UID})
+		assert.Equal(t, 2, lines[2].Exported)
+		assert.Equal(t, lines[1].Continue, lines[2].Continue)
+
+		lines = export(t, "?continue="+lines[2].Continue)
+		require.Len(t, lines, 2)
+		assert.Equal(t, "c", lines[0].UID)
+		assert.Equal(t, DashboardExportLine{Exported: 1}, lines[1])
+	})
+
+	t.Run("should reject an invalid continue token", func(t *testing.T) {
+		req := server.NewGetRequest("/api/dashboards/export?continue=not-a-token")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, canExport)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
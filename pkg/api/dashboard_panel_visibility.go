@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+// panelVisibilityCacheTTL bounds how long the queries of a panel are checked against its previous visibility
+const panelVisibilityCacheTTL = 30 * time.Second
+
+func panelVisibilityCacheKey(orgID int64, dashboardUID string) string {
+	return fmt.Sprintf("panel-visibility-%d-%s", orgID, dashboardUID)
+}
+
+// panelVisibleTo returns a function telling whether the panels with a visibility are visible to the user.
+// The teams of the user are only fetched for the panels visible to teams.
+func (hs *HTTPServer) panelVisibleTo(ctx context.Context, usr identity.Requester) func(v *dashboards.PanelVisibility) bool {
+	var teamUIDs []string
+	teamsLoaded := false
+	return func(v *dashboards.PanelVisibility) bool {
+		if v.Allows(usr.GetOrgRole(), nil) {
+			return true
+		}
+		if len(v.Teams) == 0 {
+			return false
+		}
+		if !teamsLoaded {
+			teamsLoaded = true
+			teamUIDs = hs.userTeamUIDs(ctx, usr)
+		}
+		return v.Allows(usr.GetOrgRole(), teamUIDs)
+	}
+}
+
+// userTeamUIDs returns the UIDs of the teams of the user, the panels visible to teams are hidden when the
+// teams can't be fetched
+func (hs *HTTPServer) userTeamUIDs(ctx context.Context, usr identity.Requester) []string {
+	userID, err := identity.UserIdentifier(usr.GetNamespacedID())
+	if err != nil || userID == 0 {
+		return nil
+	}
+	teams, err := hs.teamService.GetTeamsByUser(ctx, &team.GetTeamsByUserQuery{
+		OrgID:        usr.GetOrgID(),
+		UserID:       userID,
+		SignedInUser: usr,
+	})
+	if err != nil {
+		hs.log.Warn("Failed to get the teams of the user for the panel visibility", "userId", userID, "error", err)
+		return nil
+	}
+	uids := make([]string, 0, len(teams))
+	for _, t := range teams {
+		uids = append(uids, t.UID)
+	}
+	return uids
+}
+
+// canSeeHiddenPanels returns whether the user can save the dashboard, the users who can save a dashboard
+// see all its panels so that saving it doesn't remove the panels they can't see
+func (hs *HTTPServer) canSeeHiddenPanels(ctx context.Context, usr identity.Requester, dashboardUID string) bool {
+	ok, err := hs.AccessControl.Evaluate(ctx, usr, accesscontrol.EvalPermission(dashboards.ActionDashboardsWrite,
+		dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashboardUID)))
+	return err == nil && ok
+}
+
+// isPanelHidden returns whether the panel of the dashboard has a visibility that hides it from the user
+func (hs *HTTPServer) isPanelHidden(ctx context.Context, usr identity.Requester, dash *dashboards.Dashboard, panelID int64) bool {
+	v := dash.GetPanelVisibilities()[panelID]
+	return v != nil && !hs.panelVisibleTo(ctx, usr)(v) && !hs.canSeeHiddenPanels(ctx, usr, dash.UID)
+}
+
+// checkQueryPanelVisibility returns ErrDashboardPanelHidden when the query is sent for a panel, with the
+// dashboard and panel headers, that is hidden from the user.
+func (hs *HTTPServer) checkQueryPanelVisibility(c *contextmodel.ReqContext) error {
+	dashboardUID := c.Req.Header.Get(query.HeaderDashboardUID)
+	panelID, err := strconv.ParseInt(c.Req.Header.Get(query.HeaderPanelID), 10, 64)
+	if dashboardUID == "" || err != nil {
+		return nil
+	}
+
+	ctx := c.Req.Context()
+	orgID := c.SignedInUser.GetOrgID()
+	cacheKey := panelVisibilityCacheKey(orgID, dashboardUID)
+	var visibilities map[int64]*dashboards.PanelVisibility
+	if cached, ok := hs.CacheService.Get(cacheKey); ok {
+		visibilities = cached.(map[int64]*dashboards.PanelVisibility)
+	} else {
+		dash, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: orgID, UID: dashboardUID})
+		if err != nil {
+			// the queries of unknown dashboards are only checked by the data source permissions
+			return nil
+		}
+		visibilities = dash.GetPanelVisibilities()
+		hs.CacheService.Set(cacheKey, visibilities, panelVisibilityCacheTTL)
+	}
+
+	v := visibilities[panelID]
+	if v == nil || hs.panelVisibleTo(ctx, c.SignedInUser)(v) || hs.canSeeHiddenPanels(ctx, c.SignedInUser, dashboardUID) {
+		return nil
+	}
+	return dashboards.ErrDashboardPanelHidden
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_PanelVisibility(t *testing.T) {
+	dash := &dashboards.Dashboard{OrgID: 1, UID: "dash", Data: simplejson.NewFromAny(map[string]any{
+		"panels": []any{
+			map[string]any{"id": 1},
+			map[string]any{"id": 2, "visibility": map[string]any{"teams": []any{"ops"}}},
+			map[string]any{"id": 3, "visibility": map[string]any{"roles": []any{"Editor"}}},
+		},
+	})}
+	teams := &teamtest.FakeService{ExpectedTeamsByUser: []*team.TeamDTO{{UID: "ops"}}}
+	setup := func(hs *HTTPServer) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(dash, nil).Maybe()
+		hs.DashboardService = dashSvc
+		hs.CacheService = localcache.ProvideService()
+		hs.teamService = teams
+	}
+
+	newViewer := func(permissions ...accesscontrol.Permission) *user.SignedInUser {
+		usr := userWithPermissions(1, append(permissions, accesscontrol.Permission{Action: datasources.ActionQuery, Scope: datasources.ScopeAll}))
+		usr.UserID, usr.IsAnonymous, usr.OrgRole = 5, false, org.RoleViewer
+		return usr
+	}
+
+	t.Run("should reject the queries of a hidden panel", func(t *testing.T) {
+		server := SetupAPITestServer(t, setup)
+		teams.ExpectedTeamsByUser = nil
+		req := server.NewPostRequest("/api/ds/query", strings.NewReader(reqValid))
+		req.Header.Set(query.HeaderDashboardUID, "dash")
+		req.Header.Set(query.HeaderPanelID, "2")
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(req, newViewer()))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	check := func(t *testing.T, usr *user.SignedInUser, panelID string) error {
+		t.Helper()
+		hs := &HTTPServer{Cfg: setting.NewCfg()}
+		setup(hs)
+		hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+		req, err := http.NewRequest(http.MethodPost, "/api/ds/query", nil)
+		require.NoError(t, err)
+		req.Header.Set(query.HeaderDashboardUID, "dash")
+		req.Header.Set(query.HeaderPanelID, panelID)
+		return hs.checkQueryPanelVisibility(&contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: usr})
+	}
+
+	t.Run("should allow the queries of the panels visible to the user", func(t *testing.T) {
+		teams.ExpectedTeamsByUser = []*team.TeamDTO{{UID: "ops"}}
+		assert.NoError(t, check(t, newViewer(), "1"))
+		assert.NoError(t, check(t, newViewer(), "2"))
+		assert.ErrorIs(t, check(t, newViewer(), "3"), dashboards.ErrDashboardPanelHidden)
+	})
+
+	t.Run("should allow the queries of the hidden panels to the users who can save the dashboard", func(t *testing.T) {
+		teams.ExpectedTeamsByUser = nil
+		usr := newViewer(accesscontrol.Permission{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:dash"})
+		assert.NoError(t, check(t, usr, "3"))
+	})
+}
@@ -168,6 +168,46 @@ func (hs *HTTPServer) UpdateDashboardPermissions(c *contextmodel.ReqContext) res
 	return response.Success("Dashboard permissions updated")
 }
 
+// swagger:route GET /dashboards/uid/{uid}/permissions/history dashboard_permissions getDashboardPermissionsHistory
+//
+// Gets the history of permission changes for the given dashboard.
+//
+// Changes are returned most recent first with the user who made them and the permissions before and after the change.
+//
+// Responses:
+// 200: getDashboardPermissionsHistoryResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardPermissionsHistory(c *contextmodel.ReqContext) response.Response {
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	return hs.getPermissionsHistory(c, dash.UID)
+}
+
+func (hs *HTTPServer) getPermissionsHistory(c *contextmodel.ReqContext, uid string) response.Response {
+	limit := c.QueryInt("limit")
+	if limit <= 0 {
+		limit = 100
+	}
+
+	entries, err := hs.DashboardService.GetDashboardACLAudit(c.Req.Context(), &dashboards.GetDashboardACLAuditQuery{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		DashboardUID: uid,
+		Limit:        limit,
+		Page:         c.QueryInt("page"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get permissions history", err)
+	}
+
+	return response.JSON(http.StatusOK, entries)
+}
+
 var dashboardPermissionMap = map[string]dashboardaccess.PermissionType{
 	"View":  dashboardaccess.PERMISSION_VIEW,
 	"Edit":  dashboardaccess.PERMISSION_EDIT,
@@ -304,12 +344,12 @@ func (hs *HTTPServer) updateDashboardAccessControl(ctx context.Context, orgID in
 		if _, err := hs.folderPermissionsService.SetPermissions(ctx, orgID, uid, commands...); err != nil {
 			return err
 		}
-		return nil
+	} else {
+		if _, err := hs.dashboardPermissionsService.SetPermissions(ctx, orgID, uid, commands...); err != nil {
+			return err
+		}
 	}
 
-	if _, err := hs.dashboardPermissionsService.SetPermissions(ctx, orgID, uid, commands...); err != nil {
-		return err
-	}
 	return nil
 }
 
@@ -359,6 +399,29 @@ type UpdateDashboardPermissionsByUIDParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters getDashboardPermissionsHistory
+type GetDashboardPermissionsHistoryParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// Maximum number of changes to return
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+	// Page index, starting at 1
+	// in:query
+	// required:false
+	// default:1
+	Page int `json:"page"`
+}
+
+// swagger:response getDashboardPermissionsHistoryResponse
+type GetDashboardPermissionsHistoryResponse struct {
+	// in: body
+	Body []*dashboards.DashboardACLAuditEntry `json:"body"`
+}
+
 // swagger:response getDashboardPermissionsListResponse
 type GetDashboardPermissionsResponse struct {
 	// in: body
@@ -0,0 +1,305 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+)
+
+const (
+	permissionExportBatchSize = 100
+
+	permissionExportFormatCSV  = "csv"
+	permissionExportFormatJSON = "json"
+
+	PermissionSubjectUser           = "user"
+	PermissionSubjectServiceAccount = "service-account"
+	PermissionSubjectTeam           = "team"
+	PermissionSubjectRole           = "role"
+
+	// PermissionSourceManaged is the source of the permissions granted on the resource itself
+	PermissionSourceManaged = "managed"
+	// PermissionSourceInherited is the source of the permissions granted on a parent folder
+	PermissionSourceInherited = "inherited"
+	// PermissionSourceRole is the source of the permissions granted by a fixed or custom role
+	PermissionSourceRole = "role"
+)
+
+var permissionExportCSVHeader = []string{"resource_type", "resource_uid", "resource_title", "folder_uid", "subject_type", "subject_id", "subject", "permission", "source", "source_scope"}
+
+// PermissionExportRow is an effective permission of a subject on a dashboard or a folder
+type PermissionExportRow struct {
+	ResourceType  string `json:"resourceType"`
+	ResourceUID   string `json:"resourceUid"`
+	ResourceTitle string `json:"resourceTitle"`
+	FolderUID     string `json:"folderUid,omitempty"`
+	SubjectType   string `json:"subjectType"`
+	SubjectID     int64  `json:"subjectId,omitempty"`
+	// Subject is the login of the user or service account, the name of the team or the basic role
+	Subject string `json:"subject"`
+	// Permission is View, Edit or Admin, or the list of actions when they don't match one of them
+	Permission string `json:"permission"`
+	Source     string `json:"source"`
+	// SourceScope is the scope of the inherited permissions, or the name of the role they are granted by
+	SourceScope string `json:"sourceScope,omitempty"`
+}
+
+func (r *PermissionExportRow) csvRecord() []string {
+	subjectID := ""
+	if r.SubjectID != 0 {
+		subjectID = strconv.FormatInt(r.SubjectID, 10)
+	}
+	return []string{r.ResourceType, r.ResourceUID, r.ResourceTitle, r.FolderUID, r.SubjectType, subjectID, r.Subject, r.Permission, r.Source, r.SourceScope}
+}
+
+// swagger:route GET /dashboards/permissions/export dashboard_permissions exportDashboardPermissions
+//
+// Export the effective permissions of all the folders and dashboards of the organization.
+//
+// The export lists, per folder and dashboard, the permission of each user, service account, team and
+// basic role, with the source of the permission: granted on the resource, inherited from a parent folder,
+// or granted by a role. The export is streamed, the folders and dashboards are read in batches.
+//
+// Produces:
+// - application/json
+// - text/csv
+//
+// Responses:
+// 200: exportDashboardPermissionsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) ExportDashboardPermissions(c *contextmodel.ReqContext) {
+	format := c.Query("format")
+	if format == "" {
+		format = permissionExportFormatJSON
+	}
+	if format != permissionExportFormatCSV && format != permissionExportFormatJSON {
+		c.JsonApiErr(http.StatusBadRequest, fmt.Sprintf("invalid format %q, expected csv or json", format), nil)
+		return
+	}
+
+	ctx := c.Req.Context()
+	writer := newPermissionExportWriter(c.Resp, format)
+	started := false
+	// the headers are only sent with the first batch, so that the errors of the first queries still
+	// get an error status
+	start := func() error {
+		started = true
+		c.Resp.Header().Set("Content-Type", writer.contentType())
+		c.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment;filename="dashboard-permissions-%s.%s"`, time.Now().UTC().Format("20060102-150405"), format))
+		c.Resp.WriteHeader(http.StatusOK)
+		return writer.begin()
+	}
+
+	err := hs.exportDashboardPermissions(ctx, c, func(rows []*PermissionExportRow) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.write(rows); err != nil {
+			return err
+		}
+		c.Resp.Flush()
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = writer.end()
+	}
+	if err != nil {
+		if !started {
+			c.JsonApiErr(http.StatusInternalServerError, "Failed to export dashboard permissions", err)
+			return
+		}
+		// the status is already sent, the export can only be truncated
+		c.Logger.Error("Failed to export dashboard permissions", "error", err)
+	}
+}
+
+// exportDashboardPermissions calls fn with the permissions of each batch of folders, then of dashboards
+func (hs *HTTPServer) exportDashboardPermissions(ctx context.Context, c *contextmodel.ReqContext, fn func([]*PermissionExportRow) error) error {
+	for _, resourceType := range []string{searchstore.TypeFolder, searchstore.TypeDashboard} {
+		for page := int64(1); ; page++ {
+			hits, err := hs.DashboardService.FindDashboards(ctx, &dashboards.FindPersistedDashboardsQuery{
+				OrgId:        c.SignedInUser.GetOrgID(),
+				SignedInUser: c.SignedInUser,
+				Type:         resourceType,
+				Limit:        permissionExportBatchSize,
+				Page:         page,
+				Permission:   dashboardaccess.PERMISSION_VIEW,
+			})
+			if err != nil {
+				return err
+			}
+
+			var rows []*PermissionExportRow
+			seen := make(map[string]struct{}, len(hits))
+			for _, hit := range hits {
+				// the hits are returned once per tag
+				if _, ok := seen[hit.UID]; ok {
+					continue
+				}
+				seen[hit.UID] = struct{}{}
+
+				resourceRows, err := hs.resourcePermissionExportRows(ctx, c, hit)
+				if err != nil {
+					return err
+				}
+				rows = append(rows, resourceRows...)
+			}
+			if err := fn(rows); err != nil {
+				return err
+			}
+			if len(seen) < permissionExportBatchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (hs *HTTPServer) resourcePermissionExportRows(ctx context.Context, c *contextmodel.ReqContext, hit dashboards.DashboardSearchProjection) ([]*PermissionExportRow, error) {
+	var service accesscontrol.PermissionsService = hs.dashboardPermissionsService
+	resourceType := "dashboard"
+	if hit.IsFolder {
+		service, resourceType = hs.folderPermissionsService, "folder"
+	}
+	permissions, err := service.GetPermissions(ctx, c.SignedInUser, hit.UID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the permissions of %s %s: %w", resourceType, hit.UID, err)
+	}
+
+	rows := make([]*PermissionExportRow, 0, len(permissions))
+	for _, p := range permissions {
+		if p.UserId > 0 && dtos.IsHiddenUser(p.UserLogin, c.SignedInUser, hs.Cfg) {
+			continue
+		}
+		row := &PermissionExportRow{
+			ResourceType:  resourceType,
+			ResourceUID:   hit.UID,
+			ResourceTitle: hit.Title,
+			FolderUID:     hit.FolderUID,
+			Permission:    service.MapActions(p),
+			Source:        PermissionSourceManaged,
+		}
+		if row.Permission == "" {
+			row.Permission = strings.Join(p.Actions, " ")
+		}
+
+		switch {
+		case p.UserId > 0 && p.IsServiceAccount:
+			row.SubjectType, row.SubjectID, row.Subject = PermissionSubjectServiceAccount, p.UserId, p.UserLogin
+		case p.UserId > 0:
+			row.SubjectType, row.SubjectID, row.Subject = PermissionSubjectUser, p.UserId, p.UserLogin
+		case p.TeamId > 0:
+			row.SubjectType, row.SubjectID, row.Subject = PermissionSubjectTeam, p.TeamId, p.Team
+		default:
+			row.SubjectType, row.Subject = PermissionSubjectRole, p.BuiltInRole
+		}
+
+		switch {
+		case !p.IsManaged:
+			row.Source, row.SourceScope = PermissionSourceRole, p.RoleName
+		case p.IsInherited:
+			row.Source, row.SourceScope = PermissionSourceInherited, p.Scope
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// permissionExportWriter writes the rows of the export as a CSV file or as a JSON array
+type permissionExportWriter struct {
+	format string
+	w      http.ResponseWriter
+	csv    *csv.Writer
+	rows   int
+}
+
+func newPermissionExportWriter(w http.ResponseWriter, format string) *permissionExportWriter {
+	return &permissionExportWriter{format: format, w: w, csv: csv.NewWriter(w)}
+}
+
+func (w *permissionExportWriter) contentType() string {
+	if w.format == permissionExportFormatCSV {
+		return "text/csv"
+	}
+	return "application/json"
+}
+
+func (w *permissionExportWriter) begin() error {
+	if w.format == permissionExportFormatCSV {
+		return w.csv.Write(permissionExportCSVHeader)
+	}
+	_, err := w.w.Write([]byte("["))
+	return err
+}
+
+func (w *permissionExportWriter) write(rows []*PermissionExportRow) error {
+	for _, row := range rows {
+		if w.format == permissionExportFormatCSV {
+			if err := w.csv.Write(row.csvRecord()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		b, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if w.rows > 0 {
+			b = append([]byte(","), b...)
+		}
+		if _, err := w.w.Write(b); err != nil {
+			return err
+		}
+		w.rows++
+	}
+	if w.format == permissionExportFormatCSV {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return nil
+}
+
+func (w *permissionExportWriter) end() error {
+	if w.format == permissionExportFormatCSV {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	_, err := w.w.Write([]byte("]"))
+	return err
+}
+
+// swagger:parameters exportDashboardPermissions
+type ExportDashboardPermissionsParams struct {
+	// The format of the export, csv or json
+	// in:query
+	// required:false
+	// default:json
+	Format string `json:"format"`
+}
+
+// swagger:response exportDashboardPermissionsResponse
+type ExportDashboardPermissionsResponse struct {
+	// in:body
+	Body []PermissionExportRow `json:"body"`
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_ExportDashboardPermissions(t *testing.T) {
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("FindDashboards", mock.Anything, mock.MatchedBy(func(q *dashboards.FindPersistedDashboardsQuery) bool {
+			return q.Type == searchstore.TypeFolder
+		})).Return([]dashboards.DashboardSearchProjection{{UID: "ops", Title: "Ops", IsFolder: true}}, nil).Maybe()
+		dashSvc.On("FindDashboards", mock.Anything, mock.MatchedBy(func(q *dashboards.FindPersistedDashboardsQuery) bool {
+			return q.Type == searchstore.TypeDashboard
+		})).Return([]dashboards.DashboardSearchProjection{
+			// the hits of the dashboards with several tags are repeated
+			{UID: "nodes", Title: "Nodes", FolderUID: "ops", Term: "linux"},
+			{UID: "nodes", Title: "Nodes", FolderUID: "ops", Term: "k8s"},
+		}, nil).Maybe()
+		hs.DashboardService = dashSvc
+
+		hs.folderPermissionsService = &actest.FakePermissionsService{
+			ExpectedMappedAction: "Edit",
+			ExpectedPermissions: []accesscontrol.ResourcePermission{
+				{TeamId: 2, Team: "SRE", IsManaged: true, Scope: "folders:uid:ops", Actions: []string{dashboards.ActionFoldersRead}},
+			},
+		}
+		hs.dashboardPermissionsService = &actest.FakePermissionsService{
+			ExpectedMappedAction: "View",
+			ExpectedPermissions: []accesscontrol.ResourcePermission{
+				{UserId: 3, UserLogin: "alice", IsManaged: true, Scope: "dashboards:uid:nodes"},
+				{TeamId: 2, Team: "SRE", IsManaged: true, IsInherited: true, Scope: "folders:uid:ops"},
+				{BuiltInRole: "Viewer", RoleName: "basic:viewer", Scope: "dashboards:*"},
+			},
+		}
+	})
+
+	canExport := []accesscontrol.Permission{
+		{Action: dashboards.ActionFoldersPermissionsRead, Scope: dashboards.ScopeFoldersAll},
+		{Action: dashboards.ActionDashboardsPermissionsRead, Scope: dashboards.ScopeDashboardsAll},
+	}
+	export := func(t *testing.T, format string, permissions []accesscontrol.Permission) *http.Response {
+		t.Helper()
+		req := server.NewGetRequest("/api/dashboards/permissions/export?format=" + format)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should require the permission to read the permissions of all folders and dashboards", func(t *testing.T) {
+		res := export(t, "json", canExport[:1])
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should export the permissions as JSON", func(t *testing.T) {
+		res := export(t, "", canExport)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+		var rows []PermissionExportRow
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&rows))
+		require.NoError(t, res.Body.Close())
+
+		assert.Equal(t, []PermissionExportRow{
+			{ResourceType: "folder", ResourceUID: "ops", ResourceTitle: "Ops", SubjectType: PermissionSubjectTeam, SubjectID: 2, Subject: "SRE", Permission: "Edit", Source: PermissionSourceManaged},
+			{ResourceType: "dashboard", ResourceUID: "nodes", ResourceTitle: "Nodes", FolderUID: "ops", SubjectType: PermissionSubjectUser, SubjectID: 3, Subject: "alice", Permission: "View", Source: PermissionSourceManaged},
+			{ResourceType: "dashboard", ResourceUID: "nodes", ResourceTitle: "Nodes", FolderUID: "ops", SubjectType: PermissionSubjectTeam, SubjectID: 2, Subject: "SRE", Permission: "View", Source: PermissionSourceInherited, SourceScope: "folders:uid:ops"},
+			{ResourceType: "dashboard", ResourceUID: "nodes", ResourceTitle: "Nodes", FolderUID: "ops", SubjectType: PermissionSubjectRole, Subject: "Viewer", Permission: "View", Source: PermissionSourceRole, SourceScope: "basic:viewer"},
+		}, rows)
+	})
+
+	t.Run("should export the permissions as CSV", func(t *testing.T) {
+		res := export(t, "csv", canExport)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/csv", res.Header.Get("Content-Type"))
+		records, err := csv.NewReader(res.Body).ReadAll()
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		require.Len(t, records, 5)
+		assert.Equal(t, permissionExportCSVHeader, records[0])
+		assert.Equal(t, []string{"dashboard", "nodes", "Nodes", "ops", "user", "3", "alice", "View", "managed", ""}, records[2])
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		res := export(t, "xml", canExport)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -157,3 +157,33 @@ func TestHTTPServer_UpdateDashboardPermissions(t *testing.T) {
 		require.NoError(t, res.Body.Close())
 	})
 }
+
+func TestHTTPServer_DashboardPermissionsHistory(t *testing.T) {
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		svc := dashboards.NewFakeDashboardService(t)
+		svc.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{ID: 1, UID: "1", OrgID: 1}, nil)
+		svc.On("GetDashboardACLAudit", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardACLAuditQuery) bool {
+			return q.DashboardUID == "1" && q.Limit == 100
+		})).Return([]*dashboards.DashboardACLAuditEntry{{ID: 1, DashboardUID: "1"}}, nil)
+
+		hs.DashboardService = svc
+	})
+
+	t.Run("should return the history with permissions read access", func(t *testing.T) {
+		req := server.NewGetRequest("/api/dashboards/uid/1/permissions/history")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{
+			{Action: dashboards.ActionDashboardsPermissionsRead, Scope: "dashboards:uid:1"},
+		})))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should not return the history without permissions read access", func(t *testing.T) {
+		req := server.NewGetRequest("/api/dashboards/uid/1/permissions/history")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
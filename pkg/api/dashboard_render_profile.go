@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// renderProfileParam is the query parameter of the dashboard and render APIs selecting the render profile of the
+// dashboard, such as its print layout
+const renderProfileParam = "renderProfile"
+
+// getRenderProfile returns the render profile of the dashboard with the name, or the error response when it
+// doesn't exist
+func getRenderProfile(dash *dashboards.Dashboard, name string) (*dashboards.RenderProfile, response.Response) {
+	profiles, err := dash.GetRenderProfiles()
+	if err != nil {
+		return nil, response.Error(http.StatusInternalServerError, "Failed to read the render profiles of the dashboard", err)
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, response.Error(http.StatusBadRequest, "Unknown render profile: "+name, nil)
+	}
+	return profile, nil
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// decryptSensitiveDashboardFields decrypts the sensitive fields of a dashboard for users with the
+// dashboards.sensitive:read permission and redacts them for everyone else. The fields are encrypted
+// by the dashboard store when the dashboard is saved.
+func (hs *HTTPServer) decryptSensitiveDashboardFields(ctx context.Context, user identity.Requester, dashboardUID string, data *simplejson.Json) error {
+	if len(hs.Cfg.SensitiveDashboardPaths) == 0 || data == nil {
+		return nil
+	}
+
+	canRead, err := hs.AccessControl.Evaluate(ctx, user, accesscontrol.EvalPermission(
+		dashboards.ActionDashboardsSensitiveRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashboardUID)))
+	if err != nil {
+		return err
+	}
+	if !canRead {
+		dashboards.RedactSensitiveFields(hs.Cfg.SensitiveDashboardPaths, data)
+		return nil
+	}
+	return dashboards.DecryptSensitiveFields(ctx, hs.SecretsService, hs.Cfg.SensitiveDashboardPaths, data)
+}
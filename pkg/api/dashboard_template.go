@@ -0,0 +1,309 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardtemplate"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route POST /dashboards/from-template dashboards createDashboardFromTemplate
+//
+// Create a dashboard from a template dashboard.
+//
+// Creates a copy of the template dashboard with the given title, data sources and variable defaults,
+// and records the template and its version as the lineage of the copy.
+//
+// Responses:
+// 200: postDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (hs *HTTPServer) CreateDashboardFromTemplate(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.CreateDashboardFromTemplateCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return apierrors.ToDashboardBindErrorResponse(err)
+	}
+	cmd.Parameters.Title = strings.TrimSpace(cmd.Parameters.Title)
+	if cmd.Parameters.Title == "" {
+		return response.Err(dashboardtemplate.ErrInvalidParameters.Errorf("the title is required"))
+	}
+
+	ctx := c.Req.Context()
+	orgID := c.SignedInUser.GetOrgID()
+	template, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: cmd.TemplateUID, OrgID: orgID})
+	if err != nil {
+		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
+	}
+	if template.IsFolder {
+		return response.Err(dashboardtemplate.ErrInvalidParameters.Errorf("the template %s is a folder", cmd.TemplateUID))
+	}
+	g, err := guardian.NewByDashboard(ctx, template, orgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+
+	limitReached, err := hs.QuotaService.QuotaReached(c, dashboards.QuotaTargetSrv)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get quota", err)
+	}
+	if limitReached {
+		return response.Error(http.StatusForbidden, "Quota reached", nil)
+	}
+
+	data, err := dashboardtemplate.Materialize(template.Data, cmd.Parameters)
+	if err != nil {
+		return response.Err(err)
+	}
+	data.Set("uid", cmd.UID)
+
+	message := cmd.Message
+	if message == "" {
+		message = fmt.Sprintf("Created from template %s version %d", template.UID, template.Version)
+	}
+	saveCmd := dashboards.SaveDashboardCommand{
+		Dashboard: data,
+		FolderUID: cmd.FolderUID,
+		OrgID:     orgID,
+		Message:   message,
+	}
+	namespaceID, userIDstr := c.SignedInUser.GetNamespacedID()
+	if namespaceID == identity.NamespaceUser || namespaceID == identity.NamespaceServiceAccount {
+		if userID, err := identity.IntIdentifier(namespaceID, userIDstr); err == nil {
+			saveCmd.UserID = userID
+		}
+	}
+
+	dashboard, err := hs.DashboardService.SaveDashboard(alerting.WithUAEnabled(ctx, hs.Cfg.UnifiedAlerting.IsEnabled()), &dashboards.SaveDashboardDTO{
+		Dashboard: saveCmd.GetDashboardModel(),
+		Message:   message,
+		OrgID:     orgID,
+		User:      c.SignedInUser,
+	}, true)
+	if err != nil {
+		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
+	}
+
+	// Clear the permission cache so that the caller can immediately interact with the created dashboard
+	hs.accesscontrolService.ClearUserPermissionCache(c.SignedInUser)
+
+	if err := hs.LibraryPanelService.ConnectLibraryPanelsForDashboard(ctx, c.SignedInUser, dashboard); err != nil {
+		return response.Error(http.StatusInternalServerError, "Error while connecting library panels", err)
+	}
+
+	now := time.Now()
+	lineage := &dashboardtemplate.Lineage{
+		DashboardUID:    dashboard.UID,
+		TemplateUID:     template.UID,
+		TemplateVersion: template.Version,
+		Parameters:      cmd.Parameters,
+		Created:         now,
+		Updated:         now,
+	}
+	if err := hs.dashboardTemplateService.SaveLineage(ctx, orgID, lineage); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to save the template lineage", err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"status":          "success",
+		"slug":            dashboard.Slug,
+		"version":         dashboard.Version,
+		"id":              dashboard.ID,
+		"uid":             dashboard.UID,
+		"url":             dashboard.GetURL(),
+		"folderUid":       dashboard.FolderUID,
+		"templateUid":     template.UID,
+		"templateVersion": template.Version,
+	})
+}
+
+// swagger:route GET /dashboards/uid/{uid}/lineage dashboards getDashboardTemplateLineage
+//
+// Get the template a dashboard was created from.
+//
+// Responses:
+// 200: dashboardTemplateLineageResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardTemplateLineage(c *contextmodel.ReqContext) response.Response {
+	lineage, err := hs.dashboardTemplateService.GetLineage(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the template lineage", err)
+	}
+	return response.JSON(http.StatusOK, lineage)
+}
+
+// swagger:route GET /dashboards/uid/{uid}/lineage/drift dashboards getDashboardTemplateDrift
+//
+// Get the drift of a dashboard from the template it was created from.
+//
+// Lists the changes of the template since the version in the lineage, the local changes of the dashboard,
+// and the properties changed by both.
+//
+// Responses:
+// 200: dashboardTemplateDriftResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardTemplateDrift(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	result, err := hs.dashboardTemplateService.Merge(ctx, c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"], "")
+	if err != nil {
+		return hs.dashboardTemplateErrorResponse(ctx, err)
+	}
+	return response.JSON(http.StatusOK, result.Drift)
+}
+
+// swagger:route POST /dashboards/uid/{uid}/lineage/reapply dashboards reapplyDashboardTemplate
+//
+// Re-apply the changes of the template of a dashboard.
+//
+// Merges the changes of the template since the version in the lineage into the dashboard, keeping its
+// local changes. The request fails when the template and the dashboard changed the same properties,
+// unless a resolution tells which side to keep.
+//
+// Responses:
+// 200: postDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (hs *HTTPServer) ReapplyDashboardTemplate(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.ReapplyDashboardTemplateCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	ctx := c.Req.Context()
+	orgID := c.SignedInUser.GetOrgID()
+	result, err := hs.dashboardTemplateService.Merge(ctx, orgID, web.Params(c.Req)[":uid"], cmd.Resolution)
+	if err != nil {
+		return hs.dashboardTemplateErrorResponse(ctx, err)
+	}
+	if len(result.Drift.Conflicts) > 0 && cmd.Resolution == "" {
+		return response.JSON(http.StatusConflict, util.DynMap{
+			"status":  "conflict",
+			"message": dashboardtemplate.ErrReapplyConflict.Errorf("conflicting changes in %s", strings.Join(result.Drift.Conflicts, ", ")).Error(),
+			"drift":   result.Drift,
+		})
+	}
+	if cmd.DryRun {
+		return response.JSON(http.StatusOK, util.DynMap{
+			"dryRun":    true,
+			"changed":   result.Changed,
+			"drift":     result.Drift,
+			"dashboard": result.Data,
+		})
+	}
+
+	dash := result.Dashboard
+	var resp response.Response = response.JSON(http.StatusOK, util.DynMap{
+		"status":  "success",
+		"slug":    dash.Slug,
+		"version": dash.Version,
+		"id":      dash.ID,
+		"uid":     dash.UID,
+		"url":     dash.GetURL(),
+	})
+	if result.Changed {
+		message := cmd.Message
+		if message == "" {
+			message = fmt.Sprintf("Re-applied template %s version %d", result.Lineage.TemplateUID, result.Drift.ToVersion)
+		}
+		resp = hs.postDashboard(c, dashboards.SaveDashboardCommand{
+			Dashboard: result.Data,
+			OrgID:     orgID,
+			FolderUID: dash.FolderUID,
+			Message:   message,
+		})
+		if resp.Status() != http.StatusOK {
+			return resp
+		}
+	}
+
+	// the dashboard now has the changes of the current version of the template
+	result.Lineage.TemplateVersion = result.Drift.ToVersion
+	result.Lineage.Updated = time.Now()
+	result.Lineage.Drift = nil
+	if err := hs.dashboardTemplateService.SaveLineage(ctx, orgID, result.Lineage); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to save the template lineage", err)
+	}
+	return resp
+}
+
+func (hs *HTTPServer) dashboardTemplateErrorResponse(ctx context.Context, err error) response.Response {
+	var dashboardErr dashboards.DashboardErr
+	if errors.As(err, &dashboardErr) {
+		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, "Failed to merge the template changes", err)
+}
+
+// swagger:parameters createDashboardFromTemplate
+type CreateDashboardFromTemplateParams struct {
+	// in:body
+	// required:true
+	Body dtos.CreateDashboardFromTemplateCommand
+}
+
+// swagger:parameters getDashboardTemplateLineage
+type GetDashboardTemplateLineageParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters getDashboardTemplateDrift
+type GetDashboardTemplateDriftParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters reapplyDashboardTemplate
+type ReapplyDashboardTemplateParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body dtos.ReapplyDashboardTemplateCommand
+}
+
+// swagger:response dashboardTemplateLineageResponse
+type DashboardTemplateLineageResponse struct {
+	// in:body
+	Body dashboardtemplate.Lineage `json:"body"`
+}
+
+// swagger:response dashboardTemplateDriftResponse
+type DashboardTemplateDriftResponse struct {
+	// in:body
+	Body dashboardtemplate.Drift `json:"body"`
+}
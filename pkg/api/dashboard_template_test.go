@@ -0,0 +1,229 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardtemplate"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+const testDashboardTemplate = `{
+	"id": 3,
+	"uid": "template",
+	"title": "Service template",
+	"version": 4,
+	"panels": [
+		{"type": "timeseries", "datasource": {"type": "prometheus", "uid": "${DS_PROM}"}, "targets": [{"expr": "up", "datasource": {"uid": "${DS_PROM}"}}]},
+		{"type": "logs", "datasource": "Loki"}
+	],
+	"templating": {"list": [
+		{"name": "service", "type": "textbox", "query": "api"},
+		{"name": "env", "type": "custom", "query": "prod,staging", "current": {"text": "prod", "value": "prod"}}
+	]}
+}`
+
+func TestHTTPServer_CreateDashboardFromTemplate(t *testing.T) {
+	templateData, err := simplejson.NewJson([]byte(testDashboardTemplate))
+	require.NoError(t, err)
+	template := &dashboards.Dashboard{ID: 3, UID: "template", OrgID: 1, Title: "Service template", Version: 4, Data: templateData}
+
+	var saved *dashboards.SaveDashboardDTO
+	var templates *dashboardtemplate.Service
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+			return q.UID == "template"
+		})).Return(template, nil).Maybe()
+		dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
+		dashSvc.On("SaveDashboard", mock.Anything, mock.Anything, true).Return(func(_ context.Context, dto *dashboards.SaveDashboardDTO, _ bool) (*dashboards.Dashboard, error) {
+			saved = dto
+			dash := dto.Dashboard
+			dash.ID, dash.UID, dash.Version = 10, "payments", 1
+			return dash, nil
+		}).Maybe()
+		hs.DashboardService = dashSvc
+		hs.LibraryPanelService = &mockLibraryPanelService{}
+		hs.accesscontrolService = actest.FakeService{}
+		templates = dashboardtemplate.ProvideService(kvstore.NewFakeKVStore(), dashSvc, dashvertest.NewDashboardVersionServiceFake(), nil)
+		hs.dashboardTemplateService = templates
+
+		hs.Cfg = setting.NewCfg()
+		hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+		guardian.InitAccessControlGuardian(hs.Cfg, hs.AccessControl, hs.DashboardService)
+	})
+
+	create := func(t *testing.T, body string, permissions []accesscontrol.Permission) *http.Response {
+		t.Helper()
+		req := server.NewRequest(http.MethodPost, "/api/dashboards/from-template", strings.NewReader(body))
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(req, userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		return res
+	}
+	canCreate := accesscontrol.Permission{Action: dashboards.ActionDashboardsCreate, Scope: "folders:*"}
+
+	t.Run("should require the permission to read the template", func(t *testing.T) {
+		res := create(t, `{"templateUid": "template", "parameters": {"title": "Payments"}}`, []accesscontrol.Permission{canCreate})
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		assert.Nil(t, saved)
+	})
+
+	t.Run("should create the dashboard and record its lineage", func(t *testing.T) {
+		res := create(t, `{"templateUid": "template", "parameters": {"title": "Payments", "variables": {"service": "payments"}}}`, []accesscontrol.Permission{
+			canCreate, {Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:template"},
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, "payments", body["uid"])
+		assert.Equal(t, float64(4), body["templateVersion"])
+
+		require.NotNil(t, saved)
+		assert.Equal(t, "Payments", saved.Dashboard.Title)
+		assert.Equal(t, int64(0), saved.Dashboard.Data.Get("id").MustInt64())
+
+		lineage, err := templates.GetLineage(context.Background(), 1, "payments")
+		require.NoError(t, err)
+		assert.Equal(t, "template", lineage.TemplateUID)
+		assert.Equal(t, 4, lineage.TemplateVersion)
+		assert.Equal(t, map[string]string{"service": "payments"}, lineage.Parameters.Variables)
+	})
+
+	t.Run("should reject unknown variables", func(t *testing.T) {
+		res := create(t, `{"templateUid": "template", "parameters": {"title": "Payments", "variables": {"missing": "x"}}}`, []accesscontrol.Permission{
+			canCreate, {Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:template"},
+		})
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
+func TestHTTPServer_ReapplyDashboardTemplate(t *testing.T) {
+	baseData, err := simplejson.NewJson([]byte(`{"title": "Service template", "panels": [
+		{"id": 1, "type": "timeseries", "title": "Requests"},
+		{"id": 2, "type": "logs", "title": "Logs"}
+	]}`))
+	require.NoError(t, err)
+	templateData, err := simplejson.NewJson([]byte(`{"title": "Service template", "panels": [
+		{"id": 1, "type": "timeseries", "title": "Requests per second"},
+		{"id": 2, "type": "logs", "title": "Logs"}
+	]}`))
+	require.NoError(t, err)
+	template := &dashboards.Dashboard{ID: 3, UID: "template", OrgID: 1, Version: 5, Data: templateData}
+
+	setup := func(t *testing.T, derived string) (*webtest.Server, *dashboardtemplate.Service, *[]*dashboards.SaveDashboardDTO) {
+		t.Helper()
+		derivedData, err := simplejson.NewJson([]byte(derived))
+		require.NoError(t, err)
+		saved := []*dashboards.SaveDashboardDTO{}
+		var templates *dashboardtemplate.Service
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			dashSvc := dashboards.NewFakeDashboardService(t)
+			dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+				return q.UID == "template"
+			})).Return(template, nil).Maybe()
+			dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+				return q.UID == "payments"
+			})).Return(&dashboards.Dashboard{ID: 10, UID: "payments", OrgID: 1, Version: 2, Data: derivedData}, nil).Maybe()
+			dashSvc.On("SaveDashboard", mock.Anything, mock.Anything, true).Return(func(_ context.Context, dto *dashboards.SaveDashboardDTO, _ bool) (*dashboards.Dashboard, error) {
+				saved = append(saved, dto)
+				dash := dto.Dashboard
+				dash.Version = 3
+				return dash, nil
+			}).Maybe()
+			versions := dashvertest.NewDashboardVersionServiceFake()
+			versions.ExpectedDashboardVersion = &dashver.DashboardVersionDTO{Version: 4, Data: baseData}
+
+			hs.DashboardService = dashSvc
+			hs.dashboardProvisioningService = mockDashboardProvisioningService{}
+			hs.log = log.New("test")
+			hs.LibraryPanelService = &mockLibraryPanelService{}
+			templates = dashboardtemplate.ProvideService(kvstore.NewFakeKVStore(), dashSvc, versions, nil)
+			hs.dashboardTemplateService = templates
+		})
+		require.NoError(t, templates.SaveLineage(context.Background(), 1, &dashboardtemplate.Lineage{
+			DashboardUID:    "payments",
+			TemplateUID:     "template",
+			TemplateVersion: 4,
+			Parameters:      dashboardtemplate.Parameters{Title: "Payments"},
+		}))
+		return server, templates, &saved
+	}
+
+	reapply := func(t *testing.T, server *webtest.Server, body string) *http.Response {
+		t.Helper()
+		req := server.NewRequest(http.MethodPost, "/api/dashboards/uid/payments/lineage/reapply", strings.NewReader(body))
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{
+			{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:payments"},
+		})))
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should save the merged dashboard and update the lineage", func(t *testing.T) {
+		server, templates, saved := setup(t, `{"id": 10, "uid": "payments", "version": 2, "title": "Payments", "panels": [
+			{"id": 1, "type": "timeseries", "title": "Requests"},
+			{"id": 2, "type": "table", "title": "Logs"}
+		]}`)
+
+		res := reapply(t, server, `{}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		require.Len(t, *saved, 1)
+		panels := (*saved)[0].Dashboard.Data.Get("panels")
+		assert.Equal(t, "Requests per second", panels.GetIndex(0).Get("title").MustString())
+		assert.Equal(t, "table", panels.GetIndex(1).Get("type").MustString())
+		assert.Equal(t, 2, (*saved)[0].Dashboard.Data.Get("version").MustInt(), "the version of the loaded dashboard prevents overwriting newer changes")
+
+		lineage, err := templates.GetLineage(context.Background(), 1, "payments")
+		require.NoError(t, err)
+		assert.Equal(t, 5, lineage.TemplateVersion)
+	})
+
+	t.Run("should not save the dashboard when the changes conflict", func(t *testing.T) {
+		server, templates, saved := setup(t, `{"id": 10, "uid": "payments", "version": 2, "title": "Payments", "panels": [
+			{"id": 1, "type": "timeseries", "title": "Payments requests"},
+			{"id": 2, "type": "logs", "title": "Logs"}
+		]}`)
+
+		res := reapply(t, server, `{}`)
+		require.Equal(t, http.StatusConflict, res.StatusCode)
+		var body struct {
+			Drift dashboardtemplate.Drift `json:"drift"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, []string{"panels[id=1].title"}, body.Drift.Conflicts)
+		assert.Empty(t, *saved)
+
+		res = reapply(t, server, `{"resolution": "template", "dryRun": true}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		assert.Empty(t, *saved)
+
+		lineage, err := templates.GetLineage(context.Background(), 1, "payments")
+		require.NoError(t, err)
+		assert.Equal(t, 4, lineage.TemplateVersion)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -55,6 +56,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/publicdashboards/api"
 	publicdashboardModels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	secretstest "github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/services/star/startest"
 	"github.com/grafana/grafana/pkg/services/supportbundles/supportbundlestest"
 	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
@@ -263,6 +265,7 @@ func TestHTTPServer_DeleteDashboardByUID_AccessControl(t *testing.T) {
 			dashSvc := dashboards.NewFakeDashboardService(t)
 			dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(dash, nil).Maybe()
 			dashSvc.On("DeleteDashboard", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			dashSvc.On("GetDashboardDependents", mock.Anything, mock.Anything).Return(&dashboards.DashboardDependents{}, nil).Maybe()
 			hs.DashboardService = dashSvc
 
 			hs.Cfg = setting.NewCfg()
@@ -309,6 +312,81 @@ func TestHTTPServer_DeleteDashboardByUID_AccessControl(t *testing.T) {
 	})
 }
 
+func TestHTTPServer_DeleteDashboardByUID_Dependents(t *testing.T) {
+	panelID := int64(2)
+	dependents := &dashboards.DashboardDependents{
+		AlertRules:  []dashboards.DashboardDependentAlertRule{{UID: "rule", Title: "High CPU", FolderUID: "folder", PanelID: &panelID}},
+		Annotations: 3,
+	}
+	setup := func(dashSvc *dashboards.FakeDashboardService) *webtest.Server {
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			dash := dashboards.NewDashboard("some dash")
+			dash.ID = 1
+			dash.UID = "1"
+
+			dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(dash, nil).Maybe()
+			dashSvc.On("GetDashboardDependents", mock.Anything, mock.Anything).Return(dependents, nil)
+			hs.DashboardService = dashSvc
+
+			hs.Cfg = setting.NewCfg()
+			hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+			hs.starService = startest.NewStarServiceFake()
+
+			hs.LibraryPanelService = &mockLibraryPanelService{}
+			hs.LibraryElementService = &mockLibraryElementService{}
+
+			pubDashService := publicdashboards.NewFakePublicDashboardService(t)
+			pubDashService.On("DeleteByDashboard", mock.Anything, mock.Anything).Return(nil).Maybe()
+			middleware := publicdashboards.NewFakePublicDashboardMiddleware(t)
+			license := licensingtest.NewFakeLicensing()
+			license.On("FeatureEnabled", publicdashboardModels.FeaturePublicDashboardsEmailSharing).Return(false)
+			hs.PublicDashboardsApi = api.ProvideApi(pubDashService, nil, hs.AccessControl, featuremgmt.WithFeatures(), middleware, hs.Cfg, license)
+
+			guardian.InitAccessControlGuardian(hs.Cfg, hs.AccessControl, hs.DashboardService)
+		})
+	}
+	deleteDashboard := func(server *webtest.Server, url string, permissions []accesscontrol.Permission) *http.Response {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, url, nil), userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		return res
+	}
+	canDeleteDashboard := accesscontrol.Permission{Action: dashboards.ActionDashboardsDelete, Scope: "dashboards:uid:1"}
+
+	t.Run("Should return the dependents without forceDeleteRules", func(t *testing.T) {
+		server := setup(dashboards.NewFakeDashboardService(t))
+		res := deleteDashboard(server, "/api/dashboards/uid/1", []accesscontrol.Permission{canDeleteDashboard})
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+
+		var body struct {
+			Status     string                         `json:"status"`
+			Dependents dashboards.DashboardDependents `json:"dependents"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, "has-dependents", body.Status)
+		assert.Equal(t, *dependents, body.Dependents)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should not delete the alert rules without permission", func(t *testing.T) {
+		server := setup(dashboards.NewFakeDashboardService(t))
+		res := deleteDashboard(server, "/api/dashboards/uid/1?forceDeleteRules=true", []accesscontrol.Permission{canDeleteDashboard})
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should delete the dashboard and the alert rules with forceDeleteRules", func(t *testing.T) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("DeleteDashboardAndRules", mock.Anything, int64(1), int64(1)).Return(nil).Once()
+		server := setup(dashSvc)
+		res := deleteDashboard(server, "/api/dashboards/uid/1?forceDeleteRules=true", []accesscontrol.Permission{
+			canDeleteDashboard,
+			{Action: accesscontrol.ActionAlertingRuleDelete, Scope: "folders:uid:folder"},
+		})
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func TestHTTPServer_GetDashboardVersions_AccessControl(t *testing.T) {
 	setup := func() *webtest.Server {
 		return SetupAPITestServer(t, func(hs *HTTPServer) {
@@ -378,6 +456,27 @@ func TestHTTPServer_GetDashboardVersions_AccessControl(t *testing.T) {
 	})
 }
 
+func TestHTTPServer_PostDashboard_PayloadLimit(t *testing.T) {
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.Cfg.DashboardMaxPayloadSize = 64
+		hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+	})
+
+	body := `{"dashboard": {"title": "Dash", "description": "` + strings.Repeat("a", 64) + `"}}`
+	req := server.NewPostRequest("/api/dashboards/db", strings.NewReader(body))
+	res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsCreate, Scope: dashboards.ScopeFoldersAll},
+	})))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+	assert.Equal(t, "payload-too-large", result["status"])
+	require.NoError(t, res.Body.Close())
+}
+
 func TestDashboardAPIEndpoint(t *testing.T) {
 	t.Run("Given two dashboards with the same title in different folders", func(t *testing.T) {
 		dashOne := dashboards.NewDashboard("dash")
@@ -818,7 +917,7 @@ func getDashboardShouldReturn200WithConfig(t *testing.T, sc *scenarioContext, pr
 	if dashboardStore == nil {
 		sql := db.InitTestDB(t)
 		quotaService := quotatest.New(false, nil)
-		dashboardStore, err = database.ProvideDashboardStore(sql, sql.Cfg, features, tagimpl.ProvideService(sql), quotaService)
+		dashboardStore, err = database.ProvideDashboardStore(sql, sql.Cfg, features, tagimpl.ProvideService(sql), quotaService, secretstest.NewFakeSecretsService())
 		require.NoError(t, err)
 	}
 
@@ -1042,6 +1141,7 @@ func (m *mockLibraryPanelService) ImportLibraryPanelsForDashboard(c context.Cont
 }
 
 type mockLibraryElementService struct {
+	disconnectedDashboardIDs []int64
 }
 
 func (l *mockLibraryElementService) CreateElement(c context.Context, signedInUser identity.Requester, cmd model.CreateLibraryElementCommand) (model.LibraryElementDTO, error) {
@@ -1065,6 +1165,7 @@ func (l *mockLibraryElementService) ConnectElementsToDashboard(c context.Context
 
 // DisconnectElementsFromDashboard disconnects elements from a specific dashboard.
 func (l *mockLibraryElementService) DisconnectElementsFromDashboard(c context.Context, dashboardID int64) error {
+	l.disconnectedDashboardIDs = append(l.disconnectedDashboardIDs, dashboardID)
 	return nil
 }
 
@@ -1,6 +1,9 @@
 package api
 
-import contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+import (
+	"github.com/grafana/grafana/pkg/services/concurrencylimit"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
 
 // swagger:route GET /datasources/proxy/{id}/{datasource_proxy_route} datasources datasourceProxyGETcalls
 //
@@ -18,6 +21,7 @@ import contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/mode
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 429: tooManyRequestsError
 // 500: internalServerError
 
 // swagger:route POST /datasources/proxy/{id}/{datasource_proxy_route} datasources datasourceProxyPOSTcalls
@@ -37,6 +41,7 @@ import contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/mode
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 429: tooManyRequestsError
 // 500: internalServerError
 
 // swagger:route DELETE /datasources/proxy/{id}/{datasource_proxy_route} datasources datasourceProxyDELETEcalls
@@ -55,8 +60,15 @@ import contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/mode
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 429: tooManyRequestsError
 // 500: internalServerError
 func (hs *HTTPServer) ProxyDataSourceRequest(c *contextmodel.ReqContext) {
+	release, ok := hs.acquireDataSourceProxySlot(c)
+	if !ok {
+		return
+	}
+	defer release()
+
 	hs.DataProxy.ProxyDataSourceRequest(c)
 }
 
@@ -72,6 +84,7 @@ func (hs *HTTPServer) ProxyDataSourceRequest(c *contextmodel.ReqContext) {
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 429: tooManyRequestsError
 // 500: internalServerError
 
 // swagger:route POST /datasources/proxy/uid/{uid}/{datasource_proxy_route} datasources datasourceProxyPOSTByUIDcalls
@@ -87,6 +100,7 @@ func (hs *HTTPServer) ProxyDataSourceRequest(c *contextmodel.ReqContext) {
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 429: tooManyRequestsError
 // 500: internalServerError
 
 // swagger:route DELETE /datasources/proxy/uid/{uid}/{datasource_proxy_route} datasources datasourceProxyDELETEByUIDcalls
@@ -101,11 +115,32 @@ func (hs *HTTPServer) ProxyDataSourceRequest(c *contextmodel.ReqContext) {
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 429: tooManyRequestsError
 // 500: internalServerError
 func (hs *HTTPServer) ProxyDataSourceRequestWithUID(c *contextmodel.ReqContext) {
+	release, ok := hs.acquireDataSourceProxySlot(c)
+	if !ok {
+		return
+	}
+	defer release()
+
 	hs.DataProxy.ProxyDatasourceRequestWithUID(c, "")
 }
 
+// acquireDataSourceProxySlot enforces the datasource_proxy concurrency limit, the request is
+// rejected with a 429 error when the limit is reached
+func (hs *HTTPServer) acquireDataSourceProxySlot(c *contextmodel.ReqContext) (func(), bool) {
+	if hs.concurrencyLimits == nil {
+		return func() {}, true
+	}
+	release, err := hs.concurrencyLimits.TryAcquire(concurrencylimit.SubsystemDataSourceProxy)
+	if err != nil {
+		c.WriteErr(err)
+		return nil, false
+	}
+	return release, true
+}
+
 // swagger:parameters datasourceProxyDELETEcalls
 type DatasourceProxyDELETEcallsParams struct {
 	// in:path
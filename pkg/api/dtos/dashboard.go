@@ -5,6 +5,8 @@ import (
 
 	dashboardsV0 "github.com/grafana/grafana/pkg/apis/dashboard/v0alpha1"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardtemplate"
 )
 
 type DashboardMeta struct {
@@ -36,6 +38,8 @@ type DashboardMeta struct {
 	AnnotationsPermissions *dashboardsV0.AnnotationPermission `json:"annotationsPermissions"`
 	PublicDashboardUID     string                             `json:"publicDashboardUid,omitempty"`
 	PublicDashboardEnabled bool                               `json:"publicDashboardEnabled,omitempty"`
+	// RenderProfile is the render profile applied to the dashboard, its page breaks apply to the printed copies
+	RenderProfile *dashboards.RenderProfile `json:"renderProfile,omitempty"`
 }
 
 type DashboardFullWithMeta struct {
@@ -62,3 +66,63 @@ type CalculateDiffTarget struct {
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
+
+type PanelPluginUsage struct {
+	PanelType  string `json:"panelType"`
+	Name       string `json:"name"`
+	Installed  bool   `json:"installed"`
+	Panels     int64  `json:"panels"`
+	Dashboards int64  `json:"dashboards"`
+}
+
+type ApplyDashboardsCommand struct {
+	Dashboards []ApplyDashboardManifest `json:"dashboards" binding:"Required"`
+	// Prune deletes dashboards in the targeted folders that are not part of the manifests.
+	Prune bool `json:"prune"`
+	// DryRun only computes the plan without changing anything.
+	DryRun  bool   `json:"dryRun"`
+	Message string `json:"message"`
+}
+
+type ApplyDashboardManifest struct {
+	Dashboard *simplejson.Json `json:"dashboard" binding:"Required"`
+	FolderUID string           `json:"folderUid"`
+}
+
+type DashboardApplyAction string
+
+const (
+	DashboardApplyCreate DashboardApplyAction = "create"
+	DashboardApplyUpdate DashboardApplyAction = "update"
+	DashboardApplyNoop   DashboardApplyAction = "noop"
+	DashboardApplyDelete DashboardApplyAction = "delete"
+)
+
+type DashboardApplyStep struct {
+	Action    DashboardApplyAction `json:"action"`
+	UID       string               `json:"uid"`
+	Title     string               `json:"title"`
+	FolderUID string               `json:"folderUid"`
+	Version   int                  `json:"version,omitempty"`
+}
+
+type DashboardApplyPlan struct {
+	DryRun bool                 `json:"dryRun"`
+	Steps  []DashboardApplyStep `json:"steps"`
+}
+
+type CreateDashboardFromTemplateCommand struct {
+	TemplateUID string                       `json:"templateUid" binding:"Required"`
+	UID         string                       `json:"uid"`
+	FolderUID   string                       `json:"folderUid"`
+	Message     string                       `json:"message"`
+	Parameters  dashboardtemplate.Parameters `json:"parameters"`
+}
+
+type ReapplyDashboardTemplateCommand struct {
+	// Resolution is the side kept for the properties changed by both the template and the dashboard,
+	// the reapply fails when they conflict and there's no resolution.
+	Resolution dashboardtemplate.Resolution `json:"resolution"`
+	DryRun     bool                         `json:"dryRun"`
+	Message    string                       `json:"message"`
+}
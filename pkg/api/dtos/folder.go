@@ -35,4 +35,6 @@ type FolderSearchHit struct {
 	UID       string `json:"uid" xorm:"uid"`
 	Title     string `json:"title"`
 	ParentUID string `json:"parentUid,omitempty"`
+	// AlertRuleCount is the number of alert rules in the folder and its subfolders, set when requested
+	AlertRuleCount *int64 `json:"alertRuleCount,omitempty"`
 }
@@ -203,7 +203,7 @@ type FrontendSettingsDTO struct {
 
 	LicenseInfo FrontendSettingsLicenseInfoDTO `json:"licenseInfo"`
 
-	FeatureToggles                   map[string]bool                `json:"featureToggles"`
+	FeatureToggles                   map[string]bool                `json:"featureToggles,omitempty"`
 	AnonymousEnabled                 bool                           `json:"anonymousEnabled"`
 	AnonymousDeviceLimit             int64                          `json:"anonymousDeviceLimit"`
 	RendererAvailable                bool                           `json:"rendererAvailable"`
@@ -261,3 +261,16 @@ type FrontendSettingsDTO struct {
 	Licensing     *FrontendSettingsLicensingDTO     `json:"licensing,omitempty"`
 	Whitelabeling *FrontendSettingsWhitelabelingDTO `json:"whitelabeling,omitempty"`
 }
+
+// FrontendCapabilitiesDTO describes what the signed in user can do in the current organization.
+type FrontendCapabilitiesDTO struct {
+	OrgID        int64           `json:"orgId"`
+	Capabilities map[string]bool `json:"capabilities"`
+	// Features are the feature toggles enabled in the current organization
+	Features map[string]bool `json:"features"`
+}
+
+// FrontendFeatureOverridesDTO enables or disables frontend only feature toggles in an organization.
+type FrontendFeatureOverridesDTO struct {
+	Overrides map[string]bool `json:"overrides"`
+}
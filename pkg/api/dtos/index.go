@@ -59,3 +59,20 @@ func (a *EntryPointAssets) SetContentDeliveryURL(prefix string) {
 		a.JSFiles[i].FilePath = prefix + p.FilePath
 	}
 }
+
+// AssetManifest lists the built frontend files keyed by their logical name, e.g. app.js
+type AssetManifest struct {
+	ContentDeliveryURL string                     `json:"cdn,omitempty"`
+	Assets             map[string]EntryPointAsset `json:"assets"`
+}
+
+func (m *AssetManifest) SetContentDeliveryURL(prefix string) {
+	if prefix == "" {
+		return
+	}
+	m.ContentDeliveryURL = prefix
+	for name, a := range m.Assets {
+		a.FilePath = prefix + a.FilePath
+		m.Assets[name] = a
+	}
+}
@@ -0,0 +1,29 @@
+package dtos
+
+import "time"
+
+type CreatePanelEmbedCommand struct {
+	// ExpiresIn is the lifetime of the embed token in seconds. Defaults to one day.
+	ExpiresIn int64  `json:"expiresIn"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Theme     string `json:"theme"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
+
+type PanelEmbed struct {
+	ID      string    `json:"id"`
+	Token   string    `json:"token"`
+	URL     string    `json:"url"`
+	Expires time.Time `json:"expires"`
+}
+
+// PanelEmbedInfo describes a panel embed without its token
+type PanelEmbedInfo struct {
+	ID      string    `json:"id"`
+	PanelID int64     `json:"panelId"`
+	UserID  int64     `json:"userId"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
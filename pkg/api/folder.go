@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/libraryelements/model"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/store/entity"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -36,6 +37,7 @@ const REDACTED = "redacted"
 // and returns the immediate subfolders that the authenticated user has permission to view.
 // If the parameter is not supplied then it returns immediate subfolders under the root
 // that the authenticated user has permission to view.
+// With alertRuleCounts the folders include the number of alert rules in the folder and its subfolders.
 //
 // Responses:
 // 200: getFoldersResponse
@@ -68,6 +70,9 @@ func (hs *HTTPServer) GetFolders(c *contextmodel.ReqContext) response.Response {
 			metrics.MFolderIDsAPICount.WithLabelValues(metrics.GetFolders).Inc()
 		}
 
+		if err := hs.setFolderAlertRuleCounts(c, hits); err != nil {
+			return apierrors.ToFolderErrorResponse(err)
+		}
 		return response.JSON(http.StatusOK, hits)
 	}
 
@@ -76,9 +81,28 @@ func (hs *HTTPServer) GetFolders(c *contextmodel.ReqContext) response.Response {
 		return apierrors.ToFolderErrorResponse(err)
 	}
 
+	if err := hs.setFolderAlertRuleCounts(c, hits); err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
 	return response.JSON(http.StatusOK, hits)
 }
 
+// setFolderAlertRuleCounts sets the number of alert rules of the folders when the alertRuleCounts query parameter is set
+func (hs *HTTPServer) setFolderAlertRuleCounts(c *contextmodel.ReqContext, hits []dtos.FolderSearchHit) error {
+	if !c.QueryBool("alertRuleCounts") {
+		return nil
+	}
+	for i := range hits {
+		counts, err := hs.folderService.GetDescendantCounts(c.Req.Context(), &folder.GetDescendantCountsQuery{OrgID: c.SignedInUser.GetOrgID(), UID: &hits[i].UID, SignedInUser: c.SignedInUser})
+		if err != nil {
+			return err
+		}
+		count := counts[entity.StandardKindAlertRule]
+		hits[i].AlertRuleCount = &count
+	}
+	return nil
+}
+
 // swagger:route GET /folders/{folder_uid} folders getFolderByUID
 //
 // Get folder by uid.
@@ -494,6 +518,11 @@ type GetFoldersParams struct {
 	// in:query
 	// required:false
 	ParentUID string `json:"parentUid"`
+	// Include the number of alert rules in each folder and its subfolders
+	// in:query
+	// required:false
+	// default:false
+	AlertRuleCounts bool `json:"alertRuleCounts"`
 }
 
 // swagger:parameters getFolderByUID
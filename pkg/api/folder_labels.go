@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /folders/{folder_uid}/labels folders getFolderLabels
+//
+// Get the labels of a folder.
+//
+// Responses:
+// 200: folderLabelsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetFolderLabels(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	f, err := hs.folderService.Get(c.Req.Context(), &folder.GetFolderQuery{OrgID: c.SignedInUser.GetOrgID(), UID: &uid, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	labels, err := hs.DashboardService.GetDashboardLabels(c.Req.Context(), &dashboards.GetDashboardLabelsQuery{
+		OrgID:       c.SignedInUser.GetOrgID(),
+		DashboardID: f.ID, // nolint:staticcheck
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get folder labels", err)
+	}
+	return response.JSON(http.StatusOK, labels)
+}
+
+// swagger:route PUT /folders/{folder_uid}/labels folders updateFolderLabels
+//
+// Replace the labels of a folder.
+//
+// The labels of the dashboards are part of the dashboard JSON model. Folders have no JSON model,
+// their labels are set with this endpoint.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateFolderLabels(c *contextmodel.ReqContext) response.Response {
+	labels := map[string]string{}
+	if err := web.Bind(c.Req, &labels); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	f, err := hs.folderService.Get(c.Req.Context(), &folder.GetFolderQuery{OrgID: c.SignedInUser.GetOrgID(), UID: &uid, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	if err := hs.DashboardService.SaveDashboardLabels(c.Req.Context(), &dashboards.SaveDashboardLabelsCommand{
+		OrgID:       c.SignedInUser.GetOrgID(),
+		DashboardID: f.ID, // nolint:staticcheck
+		Labels:      labels,
+	}); err != nil {
+		return apierrors.ToDashboardErrorResponse(c.Req.Context(), hs.pluginStore, err)
+	}
+	return response.Success("Folder labels updated")
+}
+
+// swagger:parameters getFolderLabels
+type GetFolderLabelsParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+}
+
+// swagger:parameters updateFolderLabels
+type UpdateFolderLabelsParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+	// in:body
+	// required:true
+	Body map[string]string `json:"body"`
+}
+
+// swagger:response folderLabelsResponse
+type FolderLabelsResponse struct {
+	// in:body
+	Body map[string]string `json:"body"`
+}
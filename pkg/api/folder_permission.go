@@ -121,6 +121,28 @@ func (hs *HTTPServer) UpdateFolderPermissions(c *contextmodel.ReqContext) respon
 	return response.Success("Folder permissions updated")
 }
 
+// swagger:route GET /folders/{folder_uid}/permissions/history folder_permissions getFolderPermissionsHistory
+//
+// Gets the history of permission changes for the given folder.
+//
+// Changes are returned most recent first with the user who made them and the permissions before and after the change.
+//
+// Responses:
+// 200: getDashboardPermissionsHistoryResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetFolderPermissionsHistory(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	folder, err := hs.folderService.Get(c.Req.Context(), &folder.GetFolderQuery{OrgID: c.SignedInUser.GetOrgID(), UID: &uid, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	return hs.getPermissionsHistory(c, folder.UID)
+}
+
 var folderPermissionMap = map[string]dashboardaccess.PermissionType{
 	"View":  dashboardaccess.PERMISSION_VIEW,
 	"Edit":  dashboardaccess.PERMISSION_EDIT,
@@ -181,6 +203,23 @@ type GetFolderPermissionListParams struct {
 	FolderUID string `json:"folder_uid"`
 }
 
+// swagger:parameters getFolderPermissionsHistory
+type GetFolderPermissionsHistoryParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+	// Maximum number of changes to return
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+	// Page index, starting at 1
+	// in:query
+	// required:false
+	// default:1
+	Page int `json:"page"`
+}
+
 // swagger:parameters updateFolderPermissions
 type UpdateFolderPermissionsParams struct {
 	// in:path
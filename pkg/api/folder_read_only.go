@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// FolderReadOnlyDTO is the read-only status of a folder
+type FolderReadOnlyDTO struct {
+	ReadOnly bool `json:"readOnly"`
+	// Inherited is set when the folder is read-only because one of its parent folders is
+	Inherited bool `json:"inherited"`
+	// The flag of the folder, or of the parent folder it's inherited from
+	*dashboards.FolderReadOnly
+}
+
+type UpdateFolderReadOnlyForm struct {
+	ReadOnly bool `json:"readOnly"`
+	// Why the folder is read-only, returned in the errors of the rejected saves
+	// example: Frozen for the incident review of INC-2041
+	Reason string `json:"reason"`
+}
+
+// swagger:route GET /folders/{folder_uid}/read-only folders getFolderReadOnly
+//
+// Get the read-only status of a folder.
+//
+// Responses:
+// 200: folderReadOnlyResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetFolderReadOnly(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	f, err := hs.folderService.Get(c.Req.Context(), &folder.GetFolderQuery{OrgID: c.SignedInUser.GetOrgID(), UID: &uid, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	readOnly, err := hs.DashboardService.GetFolderReadOnly(c.Req.Context(), &dashboards.GetFolderReadOnlyQuery{OrgID: f.OrgID, FolderUID: f.UID})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get the read-only status of the folder", err)
+	}
+	if readOnly == nil {
+		return response.JSON(http.StatusOK, FolderReadOnlyDTO{})
+	}
+	return response.JSON(http.StatusOK, FolderReadOnlyDTO{ReadOnly: true, Inherited: readOnly.FolderUID != f.UID, FolderReadOnly: readOnly})
+}
+
+// swagger:route PUT /folders/{folder_uid}/read-only folders updateFolderReadOnly
+//
+// Set or clear the read-only flag of a folder.
+//
+// The dashboards of a read-only folder and of its subfolders can't be saved, created, or moved in or out
+// of it, except by the users with the `folders.readonly:write` permission.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateFolderReadOnly(c *contextmodel.ReqContext) response.Response {
+	form := UpdateFolderReadOnlyForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	f, err := hs.folderService.Get(c.Req.Context(), &folder.GetFolderQuery{OrgID: c.SignedInUser.GetOrgID(), UID: &uid, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	// the flags set by other identities than users and service accounts have no author
+	userID, _ := identity.IntIdentifier(c.SignedInUser.GetNamespacedID())
+	if err := hs.DashboardService.SetFolderReadOnly(c.Req.Context(), &dashboards.SetFolderReadOnlyCommand{
+		OrgID:     f.OrgID,
+		FolderUID: f.UID,
+		ReadOnly:  form.ReadOnly,
+		Reason:    form.Reason,
+		UserID:    userID,
+	}); err != nil {
+		return apierrors.ToDashboardErrorResponse(c.Req.Context(), hs.pluginStore, err)
+	}
+	if form.ReadOnly {
+		return response.Success("Folder is read-only")
+	}
+	return response.Success("Folder is no longer read-only")
+}
+
+// swagger:parameters getFolderReadOnly
+type GetFolderReadOnlyParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+}
+
+// swagger:parameters updateFolderReadOnly
+type UpdateFolderReadOnlyParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+	// in:body
+	// required:true
+	Body UpdateFolderReadOnlyForm `json:"body"`
+}
+
+// swagger:response folderReadOnlyResponse
+type FolderReadOnlyResponse struct {
+	// in:body
+	Body FolderReadOnlyDTO `json:"body"`
+}
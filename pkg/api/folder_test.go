@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/store/entity"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web/webtest"
@@ -414,6 +415,42 @@ func TestFolderMoveAPIEndpoint(t *testing.T) {
 	}
 }
 
+func TestFoldersGetAPIEndpoint_AlertRuleCounts(t *testing.T) {
+	folderService := &foldertest.FakeService{
+		ExpectedFolders:          []*folder.Folder{{UID: "uid", Title: "Folder"}},
+		ExpectedDescendantCounts: map[string]int64{entity.StandardKindAlertRule: 3, entity.StandardKindDashboard: 2},
+	}
+	srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.Features = featuremgmt.WithFeatures(featuremgmt.FlagNestedFolders)
+		hs.folderService = folderService
+	})
+	permissions := []accesscontrol.Permission{{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll}}
+
+	getFolders := func(t *testing.T, url string) []dtos.FolderSearchHit {
+		req := webtest.RequestWithSignedInUser(srv.NewGetRequest(url), userWithPermissions(1, permissions))
+		resp, err := srv.SendJSON(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var hits []dtos.FolderSearchHit
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&hits))
+		require.NoError(t, resp.Body.Close())
+		require.Len(t, hits, 1)
+		return hits
+	}
+
+	t.Run("should not count alert rules by default", func(t *testing.T) {
+		hits := getFolders(t, "/api/folders")
+		assert.Nil(t, hits[0].AlertRuleCount)
+	})
+
+	t.Run("should count alert rules when requested", func(t *testing.T) {
+		hits := getFolders(t, "/api/folders?alertRuleCounts=true")
+		require.NotNil(t, hits[0].AlertRuleCount)
+		assert.Equal(t, int64(3), *hits[0].AlertRuleCount)
+	})
+}
+
 func TestFolderGetAPIEndpoint(t *testing.T) {
 	folderService := &foldertest.FakeService{
 		ExpectedFolder: &folder.Folder{
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginaccesscontrol"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	frontendFeaturesNamespace = "frontend_features"
+	frontendFeaturesKey       = "overrides"
+	// frontendFeaturesCacheTTL bounds how long other instances keep using the previous overrides after an update
+	frontendFeaturesCacheTTL = time.Minute
+)
+
+var ErrInvalidFrontendFeature = errutil.BadRequest("frontend.invalid-feature")
+
+// frontendOnlyFeatures lists the feature toggles that only change the frontend, these are the
+// only toggles that can be overridden per organization since the backend reads toggles globally.
+var frontendOnlyFeatures = sync.OnceValue(func() map[string]bool {
+	features := map[string]bool{}
+	list, err := featuremgmt.GetEmbeddedFeatureList()
+	if err != nil {
+		return features
+	}
+	for _, f := range list.Items {
+		if f.Spec.FrontendOnly {
+			features[f.Name] = true
+		}
+	}
+	return features
+})
+
+// frontendCapabilities are the capabilities reported to the frontend, keyed by the name used in the capability document
+var frontendCapabilities = map[string]ac.Evaluator{
+	"dashboards.create":      ac.EvalPermission(dashboards.ActionDashboardsCreate),
+	"folders.create":         ac.EvalPermission(dashboards.ActionFoldersCreate),
+	"libraryPanels.create":   ac.EvalPermission(libraryelements.ActionLibraryPanelsCreate),
+	"annotations.create":     ac.EvalPermission(ac.ActionAnnotationsCreate),
+	"datasources.explore":    ac.EvalPermission(ac.ActionDatasourcesExplore),
+	"datasources.manage":     ac.EvalAny(ac.EvalPermission(datasources.ActionCreate), ac.EvalPermission(datasources.ActionWrite)),
+	"alerting.rules.read":    ac.EvalPermission(ac.ActionAlertingRuleRead),
+	"org.users.read":         ac.EvalPermission(ac.ActionOrgUsersRead),
+	"teams.read":             ac.EvalPermission(ac.ActionTeamsRead),
+	"serviceaccounts.read":   ac.EvalPermission(serviceaccounts.ActionRead),
+	"plugins.install":        ac.EvalPermission(pluginaccesscontrol.ActionInstall),
+	"server.stats.read":      ac.EvalPermission(ac.ActionServerStatsRead),
+	"server.settings.read":   ac.EvalPermission(ac.ActionSettingsRead),
+	"dashboards.permissions": ac.EvalPermission(dashboards.ActionDashboardsPermissionsWrite),
+}
+
+// swagger:route GET /frontend/capabilities frontend getFrontendCapabilities
+//
+// Get the capabilities of the signed in user.
+//
+// Returns what the signed in user can do in the current organization, as decided by access control,
+// together with the enabled feature toggles. The response carries an ETag and requests with a matching
+// If-None-Match header get a 304 Not Modified response.
+//
+// Responses:
+// 200: getFrontendCapabilitiesResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) GetFrontendCapabilities(c *contextmodel.ReqContext) response.Response {
+	doc := dtos.FrontendCapabilitiesDTO{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		Capabilities: make(map[string]bool, len(frontendCapabilities)),
+	}
+
+	features, err := hs.getFrontendFeatures(c.Req.Context(), doc.OrgID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get feature toggles", err)
+	}
+	doc.Features = features
+
+	for name, evaluator := range frontendCapabilities {
+		hasAccess, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to evaluate capabilities", err)
+		}
+		doc.Capabilities[name] = hasAccess
+	}
+
+	// json.Marshal sorts map keys so equal documents always produce the same ETag
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to encode capabilities", err)
+	}
+
+	// capabilities differ per user, so they may only be cached by the browser and must be revalidated
+	return conditionalJSON(c, body, "private, no-cache")
+}
+
+// getFrontendFeatures returns the feature toggles enabled in the organization, with the frontend
+// only toggles overridden by the organization applied.
+func (hs *HTTPServer) getFrontendFeatures(ctx context.Context, orgID int64) (map[string]bool, error) {
+	overrides, err := hs.getFrontendFeatureOverrides(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	features := hs.Features.GetEnabled(ctx)
+	for name, enabled := range overrides {
+		if enabled {
+			features[name] = true
+		} else {
+			delete(features, name)
+		}
+	}
+	return features, nil
+}
+
+func frontendFeaturesCacheKey(orgID int64) string {
+	return fmt.Sprintf("frontend-features-%d", orgID)
+}
+
+func (hs *HTTPServer) getFrontendFeatureOverrides(ctx context.Context, orgID int64) (map[string]bool, error) {
+	if cached, ok := hs.CacheService.Get(frontendFeaturesCacheKey(orgID)); ok {
+		return cached.(map[string]bool), nil
+	}
+
+	value, ok, err := hs.kvStore.Get(ctx, orgID, frontendFeaturesNamespace, frontendFeaturesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := map[string]bool{}
+	if ok {
+		if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+			return nil, fmt.Errorf("failed to parse feature toggle overrides of org %d: %w", orgID, err)
+		}
+	}
+
+	hs.CacheService.Set(frontendFeaturesCacheKey(orgID), overrides, frontendFeaturesCacheTTL)
+	return overrides, nil
+}
+
+func (hs *HTTPServer) setFrontendFeatureOverrides(ctx context.Context, orgID int64, overrides map[string]bool) error {
+	if overrides == nil {
+		overrides = map[string]bool{}
+	}
+	for name := range overrides {
+		if !frontendOnlyFeatures()[name] {
+			return ErrInvalidFrontendFeature.Errorf("%q is not a frontend only feature toggle", name)
+		}
+	}
+
+	value, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+	if err := hs.kvStore.Set(ctx, orgID, frontendFeaturesNamespace, frontendFeaturesKey, string(value)); err != nil {
+		return err
+	}
+
+	hs.CacheService.Delete(frontendFeaturesCacheKey(orgID))
+	return nil
+}
+
+// swagger:route GET /org/frontend-features org getOrgFrontendFeatures
+//
+// Get the frontend feature toggles overridden in the current organization.
+//
+// Responses:
+// 200: getOrgFrontendFeaturesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetOrgFrontendFeatures(c *contextmodel.ReqContext) response.Response {
+	overrides, err := hs.getFrontendFeatureOverrides(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get feature toggle overrides", err)
+	}
+	return response.JSON(http.StatusOK, dtos.FrontendFeatureOverridesDTO{Overrides: overrides})
+}
+
+// swagger:route PUT /org/frontend-features org updateOrgFrontendFeatures
+//
+// Override frontend feature toggles in the current organization.
+//
+// Only the toggles that are frontend only can be overridden, the overrides replace the previous ones.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateOrgFrontendFeatures(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.FrontendFeatureOverridesDTO{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if err := hs.setFrontendFeatureOverrides(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd.Overrides); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update feature toggle overrides", err)
+	}
+	return response.Success("Feature toggle overrides updated")
+}
+
+// swagger:parameters updateOrgFrontendFeatures
+type UpdateOrgFrontendFeaturesParams struct {
+	// in:body
+	// required:true
+	Body dtos.FrontendFeatureOverridesDTO `json:"body"`
+}
+
+// swagger:response getOrgFrontendFeaturesResponse
+type GetOrgFrontendFeaturesResponse struct {
+	// in: body
+	Body dtos.FrontendFeatureOverridesDTO `json:"body"`
+}
+
+// swagger:response getFrontendCapabilitiesResponse
+type GetFrontendCapabilitiesResponse struct {
+	// in: body
+	Body dtos.FrontendCapabilitiesDTO `json:"body"`
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_GetFrontendCapabilities(t *testing.T) {
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Features = featuremgmt.WithFeatures(featuremgmt.FlagNestedFolders, featuremgmt.FlagQueryOverLive)
+		hs.kvStore = kvstore.NewFakeKVStore()
+		hs.CacheService = localcache.ProvideService()
+	})
+	signedInUser := userWithPermissions(1, []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsCreate, Scope: dashboards.ScopeFoldersAll},
+	})
+
+	res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/frontend/capabilities"), signedInUser))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	etag := res.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "private, no-cache", res.Header.Get("Cache-Control"))
+
+	var doc dtos.FrontendCapabilitiesDTO
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&doc))
+	require.NoError(t, res.Body.Close())
+
+	assert.Equal(t, int64(1), doc.OrgID)
+	assert.True(t, doc.Capabilities["dashboards.create"])
+	assert.False(t, doc.Capabilities["folders.create"])
+	assert.Len(t, doc.Capabilities, len(frontendCapabilities))
+	assert.True(t, doc.Features[featuremgmt.FlagNestedFolders])
+
+	t.Run("should return not modified when the etag matches", func(t *testing.T) {
+		req := server.NewGetRequest("/api/frontend/capabilities")
+		req.Header.Set("If-None-Match", etag)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, signedInUser))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotModified, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should apply the frontend feature overrides of the org", func(t *testing.T) {
+		admin := userWithPermissions(1, []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsPreferencesWrite}})
+		body := `{"overrides": {"queryOverLive": false, "exploreContentOutline": true}}`
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(server.NewRequest(http.MethodPut, "/api/org/frontend-features", strings.NewReader(body)), admin))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		res, err = server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/frontend/capabilities"), signedInUser))
+		require.NoError(t, err)
+		var doc dtos.FrontendCapabilitiesDTO
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&doc))
+		require.NoError(t, res.Body.Close())
+		assert.True(t, doc.Features[featuremgmt.FlagNestedFolders])
+		assert.False(t, doc.Features[featuremgmt.FlagQueryOverLive])
+		assert.True(t, doc.Features[featuremgmt.FlagExploreContentOutline])
+
+		res, err = server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/frontend/capabilities"), userWithPermissions(2, nil)))
+		require.NoError(t, err)
+		doc = dtos.FrontendCapabilitiesDTO{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&doc))
+		require.NoError(t, res.Body.Close())
+		assert.True(t, doc.Features[featuremgmt.FlagQueryOverLive])
+	})
+
+	t.Run("should only allow overriding frontend only feature toggles", func(t *testing.T) {
+		admin := userWithPermissions(1, []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsPreferencesWrite}})
+		body := `{"overrides": {"nestedFolders": false}}`
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(server.NewRequest(http.MethodPut, "/api/org/frontend-features", strings.NewReader(body)), admin))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should return a different etag when capabilities differ", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/frontend/capabilities"), userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.NotEqual(t, etag, res.Header.Get("ETag"))
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"hash"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/webassets"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/plugins"
@@ -74,6 +76,54 @@ func (hs *HTTPServer) GetFrontendAssets(c *contextmodel.ReqContext) {
 	c.JSON(http.StatusOK, keys)
 }
 
+// swagger:route GET /frontend/assets/manifest frontend getFrontendAssetManifest
+//
+// Get the frontend asset manifest.
+//
+// Returns the content hashed file names of the built frontend assets keyed by their logical name.
+// The manifest is the same for every user, so it may be cached by proxies. The response carries an
+// ETag and requests with a matching If-None-Match header get a 304 Not Modified response.
+//
+// Responses:
+// 200: getFrontendAssetManifestResponse
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetFrontendAssetManifest(c *contextmodel.ReqContext) response.Response {
+	manifest, err := webassets.GetAssetManifest(c.Req.Context(), hs.Cfg, hs.License)
+	if err != nil {
+		return response.Error(http.StatusNotFound, "Asset manifest not found", err)
+	}
+
+	body, err := json.Marshal(manifest)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to encode asset manifest", err)
+	}
+	// the manifest changes with every release, so caches must revalidate it
+	return conditionalJSON(c, body, "public, no-cache")
+}
+
+// conditionalJSON responds with the JSON encoded body and its ETag, or with 304 Not Modified
+// when the request has a matching If-None-Match header.
+func conditionalJSON(c *contextmodel.ReqContext, body []byte, cacheControl string) response.Response {
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(body))
+	if c.Req.Header.Get("If-None-Match") == etag {
+		return response.Empty(http.StatusNotModified).
+			SetHeader("ETag", etag).
+			SetHeader("Cache-Control", cacheControl)
+	}
+
+	return response.Respond(http.StatusOK, body).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("ETag", etag).
+		SetHeader("Cache-Control", cacheControl)
+}
+
+// swagger:response getFrontendAssetManifestResponse
+type GetFrontendAssetManifestResponse struct {
+	// in: body
+	Body dtos.AssetManifest `json:"body"`
+}
+
 func sortedHash(vals []string, hash hash.Hash) string {
 	hash.Reset()
 	sort.Strings(vals)
@@ -90,6 +140,11 @@ func (hs *HTTPServer) GetFrontendSettings(c *contextmodel.ReqContext) {
 		return
 	}
 
+	// clients that load the capability document don't need the feature toggles twice
+	if c.QueryBool("split") {
+		settings.FeatureToggles = nil
+	}
+
 	c.JSON(http.StatusOK, settings)
 }
 
@@ -113,6 +168,11 @@ func (hs *HTTPServer) getFrontendSettings(c *contextmodel.ReqContext) (*dtos.Fro
 		return nil, err
 	}
 
+	features, err := hs.getFrontendFeatures(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return nil, err
+	}
+
 	defaultDS := "-- Grafana --"
 	for n, ds := range dataSources {
 		if ds.IsDefault {
@@ -147,7 +207,7 @@ func (hs *HTTPServer) getFrontendSettings(c *contextmodel.ReqContext) (*dtos.Fro
 		}
 	}
 
-	hideVersion := hs.Cfg.AnonymousHideVersion && !c.IsSignedIn
+	hideVersion := hs.hideVersion(c)
 	version := setting.BuildVersion
 	commit := setting.BuildCommit
 	buildstamp := setting.BuildStamp
@@ -240,8 +300,8 @@ func (hs *HTTPServer) getFrontendSettings(c *contextmodel.ReqContext) (*dtos.Fro
 			EnabledFeatures: hs.License.EnabledFeatures(),
 		},
 
-		FeatureToggles:                   hs.Features.GetEnabled(c.Req.Context()),
-		AnonymousEnabled:                 hs.Cfg.AnonymousEnabled,
+		FeatureToggles:                   features,
+		AnonymousEnabled:                 hs.anonymousEnabled(c),
 		AnonymousDeviceLimit:             hs.Cfg.AnonymousDeviceLimit,
 		RendererAvailable:                hs.RenderService.IsAvailable(c.Req.Context()),
 		RendererVersion:                  hs.RenderService.Version(),
@@ -709,3 +769,32 @@ func (hs *HTTPServer) getEnabledOAuthProviders() map[string]any {
 	}
 	return providers
 }
+
+// anonymousEnabled reports whether anonymous access is enabled for the organization of the user
+func (hs *HTTPServer) anonymousEnabled(c *contextmodel.ReqContext) bool {
+	if c.SignedInUser == nil {
+		return hs.Cfg.AnonymousEnabled
+	}
+	conf, err := hs.anonService.GetOrgConfig(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		c.Logger.Warn("Failed to get anonymous access settings", "error", err)
+		return false
+	}
+	return conf.Enabled
+}
+
+// hideVersion reports whether the version must be hidden from the user, which anonymous
+// access can be configured to do per organization.
+func (hs *HTTPServer) hideVersion(c *contextmodel.ReqContext) bool {
+	if c.IsSignedIn {
+		return false
+	}
+	if c.SignedInUser != nil && c.SignedInUser.IsAnonymous {
+		conf, err := hs.anonService.GetOrgConfig(c.Req.Context(), c.SignedInUser.GetOrgID())
+		if err == nil {
+			return conf.HideVersion
+		}
+		c.Logger.Warn("Failed to get anonymous access settings", "error", err)
+	}
+	return hs.Cfg.AnonymousHideVersion
+}
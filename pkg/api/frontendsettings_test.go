@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/login/social/socialimpl"
@@ -19,6 +23,8 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/pluginscdn"
 	accesscontrolmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/anonymous"
+	"github.com/grafana/grafana/pkg/services/anonymous/anontest"
 	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/licensing"
@@ -76,6 +82,9 @@ func setupTestEnvironment(t *testing.T, cfg *setting.Cfg, features featuremgmt.F
 			PluginSettings:        cfg.PluginSettings,
 		}),
 		namespacer:    request.GetNamespaceMapper(cfg),
+		kvStore:       kvstore.NewFakeKVStore(),
+		CacheService:  localcache.ProvideService(),
+		anonService:   &anontest.FakeService{ExpectedOrgConfig: &anonymous.OrgConfig{HideVersion: cfg.AnonymousHideVersion}},
 		SocialService: socialimpl.ProvideService(cfg, features, &usagestats.UsageStatsMock{}, supportbundlestest.NewFakeBundleService(), remotecache.NewFakeCacheStorage(), &ssosettingstests.MockService{}),
 	}
 
@@ -141,7 +150,7 @@ func TestHTTPServer_GetFrontendSettings_hideVersionAnonymous(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
-			hs.Cfg.AnonymousHideVersion = test.hideVersion
+			hs.anonService = &anontest.FakeService{ExpectedOrgConfig: &anonymous.OrgConfig{HideVersion: test.hideVersion}}
 			expected := test.expected
 
 			recorder := httptest.NewRecorder()
@@ -156,6 +165,29 @@ func TestHTTPServer_GetFrontendSettings_hideVersionAnonymous(t *testing.T) {
 	}
 }
 
+func TestHTTPServer_GetFrontendSettings_split(t *testing.T) {
+	m, _ := setupTestEnvironment(t, setting.NewCfg(), featuremgmt.WithFeatures(featuremgmt.FlagNestedFolders), nil, nil)
+
+	for _, test := range []struct {
+		target          string
+		expectedToggles bool
+	}{
+		{target: "/api/frontend/settings", expectedToggles: true},
+		{target: "/api/frontend/settings?split=true", expectedToggles: false},
+	} {
+		t.Run(test.target, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			m.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.target, nil))
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			got := map[string]any{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			_, ok := got["featureToggles"]
+			assert.Equal(t, test.expectedToggles, ok)
+		})
+	}
+}
+
 func TestHTTPServer_GetFrontendSettings_pluginsCDNBaseURL(t *testing.T) {
 	type settings struct {
 		PluginsCDNBaseURL string `json:"pluginsCDNBaseURL"`
@@ -347,3 +379,37 @@ func newAppSettings(id string, enabled bool) map[string]*pluginsettings.DTO {
 		},
 	}
 }
+
+func TestHTTPServer_GetFrontendAssetManifest(t *testing.T) {
+	manifest, err := os.ReadFile("webassets/testdata/sample-assets-manifest.json")
+	require.NoError(t, err)
+	rootDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "build"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "build", "assets-manifest.json"), manifest, 0600))
+
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		// the manifest is not cached in development
+		hs.Cfg.Env = setting.Dev
+		hs.Cfg.StaticRootPath = rootDir
+	})
+
+	res, err := server.Send(server.NewGetRequest("/api/frontend/assets/manifest"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	etag := res.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "public, no-cache", res.Header.Get("Cache-Control"))
+
+	var doc dtos.AssetManifest
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&doc))
+	require.NoError(t, res.Body.Close())
+	assert.Equal(t, "public/build/app.0439db6f56ee4aa501b2.js", doc.Assets["app.js"].FilePath)
+
+	req := server.NewGetRequest("/api/frontend/assets/manifest")
+	req.Header.Set("If-None-Match", etag)
+	res, err = server.Send(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, res.StatusCode)
+	require.NoError(t, res.Body.Close())
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
 	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/services/warmup"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -170,6 +172,33 @@ func TestHealthAPI_DatabaseHealthCached(t *testing.T) {
 	require.True(t, healthy.(bool))
 }
 
+func TestHealthAPI_Warmup(t *testing.T) {
+	m, hs := setupHealthAPITestEnvironment(t, func(cfg *setting.Cfg) {
+		cfg.DeferredWarmup = true
+	})
+	hs.warmupService = warmup.ProvideService(hs.Cfg)
+	task := hs.warmupService.Track("search_index")
+	task.SetProgress(1, 2)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/health/warmup", nil)
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var status warmup.Status
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	require.False(t, status.Ready)
+	require.Len(t, status.Tasks, 1)
+	require.Equal(t, warmup.StateRunning, status.Tasks[0].State)
+	require.Equal(t, 1, status.Tasks[0].Done)
+	require.Equal(t, 2, status.Tasks[0].Total)
+
+	task.Finish(nil)
+	rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
 func setupHealthAPITestEnvironment(t *testing.T, cbs ...func(*setting.Cfg)) (*web.Mux, *HTTPServer) {
 	t.Helper()
 
@@ -185,5 +214,6 @@ func setupHealthAPITestEnvironment(t *testing.T, cbs ...func(*setting.Cfg)) (*we
 	}
 
 	m.Get("/api/health", hs.apiHealthHandler)
+	m.Get("/api/health/warmup", hs.warmupHealthHandler)
 	return m, hs
 }
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+)
+
+// The sources of the home dashboard, from the most specific one
+const (
+	HomeDashboardSourceUser     = "user"
+	HomeDashboardSourceTeam     = "team"
+	HomeDashboardSourceOrg      = "org"
+	HomeDashboardSourceHomePage = "homePage"
+	HomeDashboardSourceDefault  = "default"
+)
+
+// The statuses of the candidates of the fallback chain
+const (
+	HomeDashboardCandidateResolved  = "resolved"
+	HomeDashboardCandidateNotSet    = "not-set"
+	HomeDashboardCandidateNotFound  = "not-found"
+	HomeDashboardCandidateForbidden = "forbidden"
+)
+
+// HomeDashboardCandidate is a step of the fallback chain of the home dashboard resolution
+type HomeDashboardCandidate struct {
+	Source string `json:"source"`
+	// TeamID is the team of the preference, for the team candidates
+	TeamID       int64  `json:"teamId,omitempty"`
+	DashboardID  int64  `json:"dashboardId,omitempty"`
+	DashboardUID string `json:"dashboardUid,omitempty"`
+	Status       string `json:"status"`
+}
+
+// HomeDashboardResolution is the effective home dashboard of the user
+type HomeDashboardResolution struct {
+	// Source is the source of the candidate the home dashboard is resolved from
+	Source       string `json:"source"`
+	DashboardUID string `json:"dashboardUid,omitempty"`
+	// URL is the URL of the dashboard or of the configured home page, empty for the default home dashboard
+	URL string `json:"url,omitempty"`
+	// Chain are the candidates evaluated to resolve the home dashboard, in order
+	Chain []HomeDashboardCandidate `json:"chain"`
+}
+
+// swagger:route GET /dashboards/home/resolve dashboards resolveHomeDashboard
+//
+// Resolve the home dashboard.
+//
+// Returns the effective home dashboard of the signed in user: the home dashboard of the user preferences, then of
+// the preferences of their teams, then of the organization preferences, then the configured home page or the
+// default home dashboard. The dashboards that no longer exist or that the user can't view are skipped, the chain of
+// the evaluated candidates is returned for debugging.
+//
+// Responses:
+// 200: resolveHomeDashboardResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) ResolveHomeDashboard(c *contextmodel.ReqContext) response.Response {
+	resolution, err := hs.resolveHomeDashboard(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to resolve the home dashboard", err)
+	}
+	return response.JSON(http.StatusOK, resolution)
+}
+
+func (hs *HTTPServer) resolveHomeDashboard(ctx context.Context, user identity.Requester) (*HomeDashboardResolution, error) {
+	orgID := user.GetOrgID()
+	candidates := make([]HomeDashboardCandidate, 0, len(user.GetTeams())+2)
+	queries := make([]*pref.GetPreferenceQuery, 0, cap(candidates))
+
+	namespaceID, identifier := user.GetNamespacedID()
+	if namespaceID == identity.NamespaceUser || namespaceID == identity.NamespaceServiceAccount {
+		if userID, err := identity.IntIdentifier(namespaceID, identifier); err == nil {
+			candidates = append(candidates, HomeDashboardCandidate{Source: HomeDashboardSourceUser})
+			queries = append(queries, &pref.GetPreferenceQuery{OrgID: orgID, UserID: userID})
+		}
+	}
+	// the preferences of the teams with the highest IDs take precedence, as in the merged preferences
+	teams := slices.Clone(user.GetTeams())
+	slices.Sort(teams)
+	for i := len(teams) - 1; i >= 0; i-- {
+		candidates = append(candidates, HomeDashboardCandidate{Source: HomeDashboardSourceTeam, TeamID: teams[i]})
+		queries = append(queries, &pref.GetPreferenceQuery{OrgID: orgID, TeamID: teams[i]})
+	}
+	candidates = append(candidates, HomeDashboardCandidate{Source: HomeDashboardSourceOrg})
+	queries = append(queries, &pref.GetPreferenceQuery{OrgID: orgID})
+
+	resolution := &HomeDashboardResolution{Chain: make([]HomeDashboardCandidate, 0, len(candidates)+1)}
+	for i, candidate := range candidates {
+		preference, err := hs.preferenceService.Get(ctx, queries[i])
+		if err != nil {
+			return nil, err
+		}
+		candidate.DashboardID = preference.HomeDashboardID
+		if candidate.DashboardID == 0 {
+			candidate.Status = HomeDashboardCandidateNotSet
+			resolution.Chain = append(resolution.Chain, candidate)
+			continue
+		}
+
+		dash, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{ID: candidate.DashboardID, OrgID: orgID})
+		if err != nil && !errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return nil, err
+		}
+		if dash == nil || dash.IsFolder {
+			candidate.Status = HomeDashboardCandidateNotFound
+			resolution.Chain = append(resolution.Chain, candidate)
+			continue
+		}
+		candidate.DashboardUID = dash.UID
+
+		g, err := guardian.NewByDashboard(ctx, dash, orgID, user)
+		if err != nil {
+			return nil, err
+		}
+		canView, err := g.CanView()
+		if err != nil {
+			return nil, err
+		}
+		if !canView {
+			candidate.Status = HomeDashboardCandidateForbidden
+			resolution.Chain = append(resolution.Chain, candidate)
+			continue
+		}
+
+		candidate.Status = HomeDashboardCandidateResolved
+		resolution.Chain = append(resolution.Chain, candidate)
+		resolution.Source = candidate.Source
+		resolution.DashboardUID = dash.UID
+		resolution.URL = dash.GetURL()
+		return resolution, nil
+	}
+
+	if hs.Cfg.HomePage != "" {
+		resolution.Source = HomeDashboardSourceHomePage
+		resolution.URL = hs.Cfg.HomePage
+	} else {
+		resolution.Source = HomeDashboardSourceDefault
+	}
+	resolution.Chain = append(resolution.Chain, HomeDashboardCandidate{Source: resolution.Source, Status: HomeDashboardCandidateResolved})
+	return resolution, nil
+}
+
+// swagger:response resolveHomeDashboardResponse
+type ResolveHomeDashboardResponse struct {
+	// in: body
+	Body HomeDashboardResolution `json:"body"`
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/org"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/services/preference/preftest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+type fakeHomeDashboardPreferences struct {
+	preftest.FakePreferenceService
+	// homeDashboardIDs by user ID and team ID, the org preference is at {0, 0}
+	homeDashboardIDs map[[2]int64]int64
+}
+
+func (f *fakeHomeDashboardPreferences) Get(_ context.Context, query *pref.GetPreferenceQuery) (*pref.Preference, error) {
+	return &pref.Preference{HomeDashboardID: f.homeDashboardIDs[[2]int64{query.UserID, query.TeamID}]}, nil
+}
+
+func TestHTTPServer_ResolveHomeDashboard(t *testing.T) {
+	preferences := &fakeHomeDashboardPreferences{}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		for id, uid := range map[int64]string{11: "team", 12: "org"} {
+			id, uid := id, uid
+			dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+				return q.ID == id
+			})).Return(&dashboards.Dashboard{ID: id, UID: uid, OrgID: 1, Title: uid, Slug: uid}, nil).Maybe()
+		}
+		dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
+		hs.DashboardService = dashSvc
+		hs.preferenceService = preferences
+
+		hs.Cfg = setting.NewCfg()
+		hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+		guardian.InitAccessControlGuardian(hs.Cfg, hs.AccessControl, hs.DashboardService)
+	})
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{2, 3}, Permissions: map[int64]map[string][]string{
+		1: accesscontrol.GroupScopesByAction([]accesscontrol.Permission{{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:org"}}),
+	}}
+	resolve := func(t *testing.T) HomeDashboardResolution {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/dashboards/home/resolve"), signedInUser))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var resolution HomeDashboardResolution
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&resolution))
+		return resolution
+	}
+
+	t.Run("should skip the dashboards that don't exist or that the user can't view", func(t *testing.T) {
+		preferences.homeDashboardIDs = map[[2]int64]int64{{1, 0}: 10, {0, 3}: 11, {0, 0}: 12}
+		assert.Equal(t, HomeDashboardResolution{
+			Source:       HomeDashboardSourceOrg,
+			DashboardUID: "org",
+			URL:          "/d/org/org",
+			Chain: []HomeDashboardCandidate{
+				{Source: HomeDashboardSourceUser, DashboardID: 10, Status: HomeDashboardCandidateNotFound},
+				{Source: HomeDashboardSourceTeam, TeamID: 3, DashboardID: 11, DashboardUID: "team", Status: HomeDashboardCandidateForbidden},
+				{Source: HomeDashboardSourceTeam, TeamID: 2, Status: HomeDashboardCandidateNotSet},
+				{Source: HomeDashboardSourceOrg, DashboardID: 12, DashboardUID: "org", Status: HomeDashboardCandidateResolved},
+			},
+		}, resolve(t))
+	})
+
+	t.Run("should fall back to the default home dashboard", func(t *testing.T) {
+		preferences.homeDashboardIDs = map[[2]int64]int64{{1, 0}: 10}
+		resolution := resolve(t)
+		assert.Equal(t, HomeDashboardSourceDefault, resolution.Source)
+		assert.Empty(t, resolution.URL)
+		require.Len(t, resolution.Chain, 5)
+		assert.Equal(t, HomeDashboardCandidate{Source: HomeDashboardSourceDefault, Status: HomeDashboardCandidateResolved}, resolution.Chain[4])
+	})
+}
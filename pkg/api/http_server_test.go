@@ -1,11 +1,17 @@
 package api
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func TestHTTPServer_MetricsBasicAuth(t *testing.T) {
@@ -27,3 +33,27 @@ func TestHTTPServer_MetricsBasicAuth(t *testing.T) {
 		assert.False(t, ts.metricsEndpointBasicAuthEnabled())
 	})
 }
+
+func TestHTTPServer_MapStaticBuildCacheHeaders(t *testing.T) {
+	rootDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "build"), 0750))
+	for _, name := range []string{"app.0439db6f56ee4aa501b2.js", "assets-manifest.json"} {
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, "build", name), []byte("{}"), 0600))
+	}
+
+	hs := &HTTPServer{Cfg: setting.NewCfg()}
+	hs.Cfg.Env = setting.Prod
+	m := web.New()
+	hs.mapStatic(m, rootDir, "build", "public/build")
+
+	tests := map[string]string{
+		"/public/build/app.0439db6f56ee4aa501b2.js": "public, max-age=31536000, immutable",
+		"/public/build/assets-manifest.json":        "public, max-age=3600",
+	}
+	for path, cacheControl := range tests {
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Equal(t, cacheControl, rec.Header().Get("Cache-Control"), path)
+	}
+}
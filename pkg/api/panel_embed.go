@@ -0,0 +1,546 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/signedtoken"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	defaultPanelEmbedExpiry = 24 * time.Hour
+	maxPanelEmbedExpiry     = 30 * 24 * time.Hour
+	maxPanelEmbedWidth      = 3840
+	maxPanelEmbedHeight     = 2160
+
+	panelEmbedNamespace = "panel_embeds"
+	// panelEmbedCacheTTL matches the max-age of the embed response, every instance renders
+	// a token at most once per TTL however often the embed is loaded
+	panelEmbedCacheTTL = time.Minute
+	// panelEmbedFailureTTL throttles the renders of a token after a failure
+	panelEmbedFailureTTL = 10 * time.Second
+	// panelEmbedKeyPurpose scopes the signing key of the embed tokens
+	panelEmbedKeyPurpose = "panel-embed"
+)
+
+var (
+	errInvalidPanelEmbedToken = errors.New("invalid embed token")
+	errPanelEmbedRenderFailed = errors.New("rendering of the embed failed recently")
+)
+
+// panelEmbedClaims are signed into the embed token. The token only identifies the embed, the user
+// who created it is stored server-side and resolved again on each request.
+type panelEmbedClaims struct {
+	ID           string       `json:"jti"`
+	OrgID        int64        `json:"org"`
+	DashboardUID string       `json:"dash"`
+	PanelID      int64        `json:"panel"`
+	Width        int          `json:"w,omitempty"`
+	Height       int          `json:"h,omitempty"`
+	Theme        models.Theme `json:"theme"`
+	From         string       `json:"from,omitempty"`
+	To           string       `json:"to,omitempty"`
+	Expires      int64        `json:"exp"`
+}
+
+// panelEmbedRecord is stored for each created embed, deleting it revokes the token
+type panelEmbedRecord struct {
+	ID           string    `json:"id"`
+	DashboardUID string    `json:"dashboardUid"`
+	PanelID      int64     `json:"panelId"`
+	UserID       int64     `json:"userId"`
+	Created      time.Time `json:"created"`
+	Expires      time.Time `json:"expires"`
+}
+
+func panelEmbedKey(dashboardUID, id string) string {
+	return dashboardUID + "/" + id
+}
+
+func panelEmbedCacheKey(id string) string {
+	return "panel-embed-" + id
+}
+
+func signPanelEmbedToken(secretKey string, claims panelEmbedClaims) (string, error) {
+	return signedtoken.Sign(secretKey, panelEmbedKeyPurpose, claims)
+}
+
+func verifyPanelEmbedToken(secretKey string, token string, now time.Time) (*panelEmbedClaims, error) {
+	claims := &panelEmbedClaims{}
+	if err := signedtoken.Verify(secretKey, panelEmbedKeyPurpose, token, claims); err != nil || claims.ID == "" {
+		return nil, errInvalidPanelEmbedToken
+	}
+	if now.Unix() >= claims.Expires {
+		return nil, fmt.Errorf("%w: token has expired", errInvalidPanelEmbedToken)
+	}
+	return claims, nil
+}
+
+func (hs *HTTPServer) getPanelEmbedRecords(ctx context.Context, orgID int64, dashboardUID string) ([]*panelEmbedRecord, error) {
+	keys, err := hs.kvStore.Keys(ctx, orgID, panelEmbedNamespace, dashboardUID+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*panelEmbedRecord, 0, len(keys))
+	for _, key := range keys {
+		value, ok, err := hs.kvStore.Get(ctx, orgID, panelEmbedNamespace, key.Key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		record := &panelEmbedRecord{}
+		if err := json.Unmarshal([]byte(value), record); err != nil {
+			return nil, fmt.Errorf("failed to parse panel embed %s: %w", key.Key, err)
+		}
+
+		// the records of expired tokens are pruned, the tokens can't be used anymore
+		if !record.Expires.After(time.Now()) {
+			if err := hs.kvStore.Del(ctx, orgID, panelEmbedNamespace, key.Key); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Created.After(records[j].Created) })
+	return records, nil
+}
+
+// resolvePanelEmbedUser returns the user who created the embed, as long as the embed isn't revoked
+// and the user can still read the dashboard.
+func (hs *HTTPServer) resolvePanelEmbedUser(ctx context.Context, claims *panelEmbedClaims) (*user.SignedInUser, error) {
+	value, ok, err := hs.kvStore.Get(ctx, claims.OrgID, panelEmbedNamespace, panelEmbedKey(claims.DashboardUID, claims.ID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: token has been revoked", errInvalidPanelEmbedToken)
+	}
+	record := &panelEmbedRecord{}
+	if err := json.Unmarshal([]byte(value), record); err != nil {
+		return nil, err
+	}
+
+	usr, err := hs.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: record.UserID, OrgID: claims.OrgID})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidPanelEmbedToken, err)
+	}
+	if usr.IsDisabled {
+		return nil, fmt.Errorf("%w: user is disabled", errInvalidPanelEmbedToken)
+	}
+
+	permissions, err := hs.accesscontrolService.GetUserPermissions(ctx, usr, accesscontrol.Options{})
+	if err != nil {
+		return nil, err
+	}
+	usr.Permissions = map[int64]map[string][]string{usr.OrgID: accesscontrol.GroupScopesByAction(permissions)}
+
+	canRead, err := hs.AccessControl.Evaluate(ctx, usr, accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(claims.DashboardUID)))
+	if err != nil {
+		return nil, err
+	}
+	if !canRead {
+		return nil, fmt.Errorf("%w: user can't read the dashboard anymore", errInvalidPanelEmbedToken)
+	}
+	return usr, nil
+}
+
+// swagger:route POST /dashboards/uid/{uid}/panels/{panelId}/embed dashboards createPanelEmbed
+//
+// Create a panel embed.
+//
+// Creates a signed token for an embed of the panel that can be shown without signing in,
+// e.g. in wikis and status pages. The panel is rendered with the current permissions of the user
+// who created the embed until the token expires or is revoked.
+//
+// Responses:
+// 200: createPanelEmbedResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) CreatePanelEmbed(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.CreatePanelEmbedCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	panelID, err := strconv.ParseInt(web.Params(c.Req)[":panelId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "panelId is invalid", err)
+	}
+
+	expiresIn := defaultPanelEmbedExpiry
+	if cmd.ExpiresIn != 0 {
+		expiresIn = time.Duration(cmd.ExpiresIn) * time.Second
+	}
+	if expiresIn <= 0 || expiresIn > maxPanelEmbedExpiry {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("expiresIn must be between 1 and %d seconds", int64(maxPanelEmbedExpiry.Seconds())), nil)
+	}
+	if cmd.Width < 0 || cmd.Width > maxPanelEmbedWidth || cmd.Height < 0 || cmd.Height > maxPanelEmbedHeight {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("width and height must be at most %dx%d", maxPanelEmbedWidth, maxPanelEmbedHeight), nil)
+	}
+
+	theme := models.ThemeDark
+	if cmd.Theme != "" {
+		if theme, err = models.ParseTheme(cmd.Theme); err != nil {
+			return response.Error(http.StatusBadRequest, "theme is invalid", err)
+		}
+	}
+
+	dash, err := hs.DashboardService.GetDashboard(c.Req.Context(), &dashboards.GetDashboardQuery{
+		UID:   web.Params(c.Req)[":uid"],
+		OrgID: c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		return response.Error(http.StatusNotFound, "Dashboard not found", err)
+	}
+	if dash.GetPanel(panelID) == nil {
+		return response.Error(http.StatusNotFound, "Panel not found", nil)
+	}
+
+	namespace, identifier := c.SignedInUser.GetNamespacedID()
+	if namespace != identity.NamespaceUser {
+		return response.Error(http.StatusForbidden, "Only users can create panel embeds", nil)
+	}
+	userID, err := identity.IntIdentifier(namespace, identifier)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to parse user id", err)
+	}
+
+	now := time.Now()
+	record := &panelEmbedRecord{
+		ID:           util.GenerateShortUID(),
+		DashboardUID: dash.UID,
+		PanelID:      panelID,
+		UserID:       userID,
+		Created:      now,
+		Expires:      now.Add(expiresIn),
+	}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create embed", err)
+	}
+	if err := hs.kvStore.Set(c.Req.Context(), dash.OrgID, panelEmbedNamespace, panelEmbedKey(dash.UID, record.ID), string(value)); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create embed", err)
+	}
+
+	token, err := signPanelEmbedToken(hs.Cfg.SecretKey, panelEmbedClaims{
+		ID:           record.ID,
+		OrgID:        dash.OrgID,
+		DashboardUID: dash.UID,
+		PanelID:      panelID,
+		Width:        cmd.Width,
+		Height:       cmd.Height,
+		Theme:        theme,
+		From:         cmd.From,
+		To:           cmd.To,
+		Expires:      record.Expires.Unix(),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to sign embed token", err)
+	}
+
+	return response.JSON(http.StatusOK, dtos.PanelEmbed{
+		ID:      record.ID,
+		Token:   token,
+		URL:     strings.TrimSuffix(hs.Cfg.AppURL, "/") + "/api/embed/panels/" + token,
+		Expires: record.Expires,
+	})
+}
+
+// swagger:route GET /dashboards/uid/{uid}/embeds dashboards getPanelEmbeds
+//
+// Get the panel embeds of a dashboard.
+//
+// Returns the panel embeds of the dashboard that haven't expired nor been revoked.
+//
+// Responses:
+// 200: getPanelEmbedsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetPanelEmbeds(c *contextmodel.ReqContext) response.Response {
+	records, err := hs.getPanelEmbedRecords(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get panel embeds", err)
+	}
+
+	result := make([]dtos.PanelEmbedInfo, 0, len(records))
+	for _, r := range records {
+		result = append(result, dtos.PanelEmbedInfo{ID: r.ID, PanelID: r.PanelID, UserID: r.UserID, Created: r.Created, Expires: r.Expires})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route DELETE /dashboards/uid/{uid}/embeds/{embedId} dashboards revokePanelEmbed
+//
+// Revoke a panel embed.
+//
+// The token of the embed can't be used anymore.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) RevokePanelEmbed(c *contextmodel.ReqContext) response.Response {
+	orgID, id := c.SignedInUser.GetOrgID(), web.Params(c.Req)[":embedId"]
+	key := panelEmbedKey(web.Params(c.Req)[":uid"], id)
+
+	_, ok, err := hs.kvStore.Get(c.Req.Context(), orgID, panelEmbedNamespace, key)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to revoke panel embed", err)
+	}
+	if !ok {
+		return response.Error(http.StatusNotFound, "Panel embed not found", nil)
+	}
+	if err := hs.kvStore.Del(c.Req.Context(), orgID, panelEmbedNamespace, key); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to revoke panel embed", err)
+	}
+
+	hs.CacheService.Delete(panelEmbedCacheKey(id))
+	return response.Success("Panel embed revoked")
+}
+
+// swagger:route GET /embed/panels/{token} dashboards getPanelEmbed
+//
+// Get a panel embed.
+//
+// Renders the panel of a signed embed token to a self-contained HTML document, or to an SVG image
+// with `format=svg`. No authentication is needed besides the token.
+//
+// Produces:
+// - text/html
+// - image/svg+xml
+//
+// Responses:
+// 200: getPanelEmbedResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetPanelEmbed(c *contextmodel.ReqContext) response.Response {
+	claims, err := verifyPanelEmbedToken(hs.Cfg.SecretKey, web.Params(c.Req)[":token"], time.Now())
+	if err != nil {
+		return response.Error(http.StatusUnauthorized, "Invalid or expired embed token", err)
+	}
+
+	format := c.Query("format")
+	if format != "" && format != "html" && format != "svg" {
+		return response.Error(http.StatusBadRequest, "format must be html or svg", nil)
+	}
+
+	usr, err := hs.resolvePanelEmbedUser(c.Req.Context(), claims)
+	if err != nil {
+		if errors.Is(err, errInvalidPanelEmbedToken) {
+			return response.Error(http.StatusUnauthorized, "Invalid or expired embed token", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to verify embed token", err)
+	}
+
+	dash, err := hs.DashboardService.GetDashboard(c.Req.Context(), &dashboards.GetDashboardQuery{
+		UID:   claims.DashboardUID,
+		OrgID: claims.OrgID,
+	})
+	if err != nil {
+		return response.Error(http.StatusNotFound, "Dashboard not found", err)
+	}
+	panel := dash.GetPanel(claims.PanelID)
+	if panel == nil {
+		return response.Error(http.StatusNotFound, "Panel not found", nil)
+	}
+
+	width, height := claims.Width, claims.Height
+	if width == 0 {
+		width = hs.Cfg.RendererDefaultImageWidth
+	}
+	if height == 0 {
+		height = hs.Cfg.RendererDefaultImageHeight
+	}
+
+	image, err := hs.renderPanelEmbed(c.Req.Context(), claims, usr, dash, width, height)
+	if err != nil {
+		switch {
+		case errors.Is(err, rendering.ErrConcurrentLimitReached), errors.Is(err, errPanelEmbedRenderFailed):
+			return response.Error(http.StatusTooManyRequests, "Too many embed renders, try again later", err).
+				SetHeader("Retry-After", strconv.Itoa(int(panelEmbedFailureTTL.Seconds())))
+		case errors.Is(err, rendering.ErrTimeout):
+			return response.Error(http.StatusInternalServerError, err.Error(), err)
+		case errors.Is(err, rendering.ErrShuttingDown):
+			return response.Error(http.StatusServiceUnavailable, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Rendering failed.", err)
+	}
+
+	dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(image)
+	title := html.EscapeString(panel.Get("title").MustString(dash.Title))
+
+	var body, contentType string
+	if format == "svg" {
+		contentType = "image/svg+xml"
+		body = fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d"><title>%s</title><image href="%s" width="%d" height="%d"/></svg>`,
+			width, height, width, height, title, dataURI, width, height)
+	} else {
+		contentType = "text/html; charset=UTF-8"
+		body = fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="utf-8"><title>%s</title><style>html,body{margin:0}img{display:block;max-width:100%%;height:auto}</style></head><body><img src="%s" width="%d" height="%d" alt="%s"></body></html>`,
+			title, dataURI, width, height, title)
+	}
+
+	// the embed is self-contained, so it never needs to load anything else
+	return response.Respond(http.StatusOK, body).
+		SetHeader("Content-Type", contentType).
+		SetHeader("Content-Security-Policy", "default-src 'none'; img-src data:; style-src 'unsafe-inline'").
+		SetHeader("Cache-Control", "public, max-age=60")
+}
+
+// renderPanelEmbed returns the rendered panel of the embed. The image is cached per token and the
+// concurrent requests of a token share a single render, failed renders are not retried for a while.
+func (hs *HTTPServer) renderPanelEmbed(ctx context.Context, claims *panelEmbedClaims, usr *user.SignedInUser, dash *dashboards.Dashboard, width, height int) ([]byte, error) {
+	cacheKey := panelEmbedCacheKey(claims.ID)
+	if cached, ok := hs.CacheService.Get(cacheKey); ok {
+		if image, ok := cached.([]byte); ok {
+			return image, nil
+		}
+		return nil, errPanelEmbedRenderFailed
+	}
+
+	image, err, _ := hs.panelEmbedRenders.Do(cacheKey, func() (any, error) {
+		params := url.Values{}
+		params.Set("orgId", strconv.FormatInt(claims.OrgID, 10))
+		params.Set("panelId", strconv.FormatInt(claims.PanelID, 10))
+		if claims.From != "" && claims.To != "" {
+			params.Set("from", claims.From)
+			params.Set("to", claims.To)
+		}
+		for name, values := range hs.resolveRenderVariables(ctx, usr, dash, params) {
+			params[name] = values
+		}
+
+		// the render isn't tied to the request that started it since it is shared
+		result, err := hs.RenderService.Render(context.WithoutCancel(ctx), rendering.RenderPNG, rendering.Opts{
+			TimeoutOpts: rendering.TimeoutOpts{
+				Timeout: 60 * time.Second,
+			},
+			AuthOpts: rendering.AuthOpts{
+				OrgID:   usr.OrgID,
+				UserID:  usr.UserID,
+				OrgRole: usr.OrgRole,
+			},
+			ErrorOpts: rendering.ErrorOpts{
+				ErrorConcurrentLimitReached: true,
+				ErrorRenderUnavailable:      true,
+			},
+			Width:             width,
+			Height:            height,
+			Path:              path.Join("d-solo", dash.UID, dash.Slug) + "?" + params.Encode(),
+			ConcurrentLimit:   hs.renderConcurrentLimit(),
+			DeviceScaleFactor: hs.Cfg.RendererDefaultImageScale,
+			Theme:             claims.Theme,
+		}, nil)
+		if err != nil {
+			hs.CacheService.Set(cacheKey, err, panelEmbedFailureTTL)
+			return nil, err
+		}
+
+		// nolint:gosec
+		image, err := os.ReadFile(result.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		hs.CacheService.Set(cacheKey, image, panelEmbedCacheTTL)
+		return image, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return image.([]byte), nil
+}
+
+// swagger:parameters createPanelEmbed
+type CreatePanelEmbedParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:path
+	// required:true
+	PanelID int64 `json:"panelId"`
+	// in:body
+	// required:true
+	Body dtos.CreatePanelEmbedCommand
+}
+
+// swagger:parameters getPanelEmbeds
+type GetPanelEmbedsParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters revokePanelEmbed
+type RevokePanelEmbedParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:path
+	// required:true
+	EmbedID string `json:"embedId"`
+}
+
+// swagger:parameters getPanelEmbed
+type GetPanelEmbedParams struct {
+	// in:path
+	// required:true
+	Token string `json:"token"`
+	// in:query
+	// required:false
+	// enum: html,svg
+	Format string `json:"format"`
+}
+
+// swagger:response createPanelEmbedResponse
+type CreatePanelEmbedResponse struct {
+	// in: body
+	Body dtos.PanelEmbed `json:"body"`
+}
+
+// swagger:response getPanelEmbedsResponse
+type GetPanelEmbedsResponse struct {
+	// in: body
+	Body []dtos.PanelEmbedInfo `json:"body"`
+}
+
+// swagger:response getPanelEmbedResponse
+type GetPanelEmbedResponse struct {
+	// in: body
+	Body []byte `json:"body"`
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/signedtoken"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestPanelEmbedToken(t *testing.T) {
+	now := time.Now()
+	claims := panelEmbedClaims{ID: "embed", OrgID: 1, DashboardUID: "abc", PanelID: 3, Theme: models.ThemeLight, Expires: now.Add(time.Hour).Unix()}
+	key := "secret"
+
+	token, err := signPanelEmbedToken(key, claims)
+	require.NoError(t, err)
+
+	t.Run("should verify a valid token", func(t *testing.T) {
+		verified, err := verifyPanelEmbedToken(key, token, now)
+		require.NoError(t, err)
+		assert.Equal(t, claims, *verified)
+	})
+
+	t.Run("should reject a token signed with another key", func(t *testing.T) {
+		_, err := verifyPanelEmbedToken("other", token, now)
+		assert.ErrorIs(t, err, errInvalidPanelEmbedToken)
+	})
+
+	t.Run("should reject a token signed for another purpose", func(t *testing.T) {
+		raw, err := signedtoken.Sign(key, "share-link", claims)
+		require.NoError(t, err)
+		_, err = verifyPanelEmbedToken(key, raw, now)
+		assert.ErrorIs(t, err, errInvalidPanelEmbedToken)
+	})
+
+	t.Run("should reject a token with modified claims", func(t *testing.T) {
+		modified := claims
+		modified.PanelID = 4
+		forged, err := signPanelEmbedToken("other", modified)
+		require.NoError(t, err)
+		_, signature, _ := strings.Cut(token, ".")
+		payload, _, _ := strings.Cut(forged, ".")
+
+		_, err = verifyPanelEmbedToken(key, payload+"."+signature, now)
+		assert.ErrorIs(t, err, errInvalidPanelEmbedToken)
+	})
+
+	t.Run("should reject an expired token", func(t *testing.T) {
+		_, err := verifyPanelEmbedToken(key, token, now.Add(2*time.Hour))
+		assert.ErrorIs(t, err, errInvalidPanelEmbedToken)
+	})
+}
+
+func TestHTTPServer_PanelEmbed(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(`{"title": "Dash", "panels": [{"id": 2, "title": "CPU <usage>"}]}`))
+	require.NoError(t, err)
+	dash := dashboards.NewDashboardFromJson(data)
+	dash.OrgID = 1
+	dash.UID = "abc"
+
+	imagePath := filepath.Join(t.TempDir(), "panel.png")
+	require.NoError(t, os.WriteFile(imagePath, []byte("png"), 0600))
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(dash, nil)
+
+	renderService := rendering.NewMockService(gomock.NewController(t))
+	creator := &user.SignedInUser{UserID: 5, OrgID: 1, OrgRole: org.RoleViewer}
+	acService := &actest.FakeService{ExpectedPermissions: []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:abc"},
+	}}
+
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.DashboardService = dashboardService
+		hs.RenderService = renderService
+		hs.Cfg = setting.NewCfg()
+		hs.Cfg.SecretKey = "secret"
+		hs.Cfg.AppURL = "http://localhost:3000/"
+		hs.kvStore = kvstore.NewFakeKVStore()
+		hs.CacheService = localcache.ProvideService()
+		hs.userService = &usertest.FakeUserService{ExpectedSignedInUser: creator}
+		hs.accesscontrolService = acService
+	})
+
+	create := func(t *testing.T, path string, body string) *http.Response {
+		t.Helper()
+		req := server.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		signedInUser := userWithPermissions(1, []accesscontrol.Permission{
+			{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:abc"},
+		})
+		signedInUser.UserID = 5
+		signedInUser.IsAnonymous = false
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, signedInUser))
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should return not found for an unknown panel", func(t *testing.T) {
+		res := create(t, "/api/dashboards/uid/abc/panels/3/embed", `{}`)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should reject a size above the maximum", func(t *testing.T) {
+		res := create(t, "/api/dashboards/uid/abc/panels/2/embed", `{"width": 10000, "height": 200}`)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should reject an expiry above the maximum", func(t *testing.T) {
+		res := create(t, "/api/dashboards/uid/abc/panels/2/embed", `{"expiresIn": 100000000}`)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should reject an invalid token", func(t *testing.T) {
+		res, err := server.Send(server.NewGetRequest("/api/embed/panels/invalid.token"))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should render the panel of a created embed without authentication", func(t *testing.T) {
+		res := create(t, "/api/dashboards/uid/abc/panels/2/embed", `{"width": 400, "height": 200, "theme": "light"}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var embed dtos.PanelEmbed
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&embed))
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, "http://localhost:3000/api/embed/panels/"+embed.Token, embed.URL)
+
+		renderService.EXPECT().Render(gomock.Any(), rendering.RenderPNG, gomock.Any(), nil).
+			DoAndReturn(func(_ any, _ rendering.RenderType, opts rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+				assert.Equal(t, rendering.AuthOpts{OrgID: 1, UserID: 5, OrgRole: org.RoleViewer}, opts.AuthOpts)
+				assert.Equal(t, "d-solo/abc/dash?orgId=1&panelId=2", opts.Path)
+				assert.Equal(t, 400, opts.Width)
+				assert.Equal(t, models.ThemeLight, opts.Theme)
+				return &rendering.RenderResult{FilePath: imagePath}, nil
+			}).Times(1)
+
+		res, err := server.Send(server.NewGetRequest("/api/embed/panels/" + embed.Token))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/html; charset=UTF-8", res.Header.Get("Content-Type"))
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Contains(t, string(body), `<img src="data:image/png;base64,cG5n"`)
+		assert.Contains(t, string(body), "<title>CPU &lt;usage&gt;</title>")
+
+		// the rendered panel is cached, so the second request doesn't render it again
+		res, err = server.Send(server.NewGetRequest("/api/embed/panels/" + embed.Token + "?format=svg"))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "image/svg+xml", res.Header.Get("Content-Type"))
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should reject the token once the user can't read the dashboard", func(t *testing.T) {
+		res := create(t, "/api/dashboards/uid/abc/panels/2/embed", `{}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var embed dtos.PanelEmbed
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&embed))
+		require.NoError(t, res.Body.Close())
+
+		acService.ExpectedPermissions = nil
+		t.Cleanup(func() {
+			acService.ExpectedPermissions = []accesscontrol.Permission{{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:abc"}}
+		})
+		res, err := server.Send(server.NewGetRequest("/api/embed/panels/" + embed.Token))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should reject a revoked token", func(t *testing.T) {
+		res := create(t, "/api/dashboards/uid/abc/panels/2/embed", `{}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var embed dtos.PanelEmbed
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&embed))
+		require.NoError(t, res.Body.Close())
+
+		writer := userWithPermissions(1, []accesscontrol.Permission{{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:abc"}})
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/dashboards/uid/abc/embeds"), writer))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var embeds []dtos.PanelEmbedInfo
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&embeds))
+		require.NoError(t, res.Body.Close())
+		require.NotEmpty(t, embeds)
+		assert.Equal(t, embed.ID, embeds[0].ID)
+
+		res, err = server.Send(webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, "/api/dashboards/uid/abc/embeds/"+embed.ID, nil), writer))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		res, err = server.Send(server.NewGetRequest("/api/embed/panels/" + embed.Token))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -1,12 +1,15 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/stats"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -54,6 +57,58 @@ func (hs *HTTPServer) getOrgQuotasHelper(c *contextmodel.ReqContext, orgID int64
 	return response.JSON(http.StatusOK, q)
 }
 
+// swagger:route GET /org/usage getCurrentOrg getCurrentOrgUsage
+//
+// Fetch the resource usage of the current organization.
+//
+// Returns the number of dashboards, folders, alerts, data sources, users, API keys and snapshots of the organization and the storage used by its dashboards and snapshots.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `orgs.quotas:read` and scope `org:id:1` (orgIDScope).
+//
+// Responses:
+// 200: getOrgUsageResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetCurrentOrgUsage(c *contextmodel.ReqContext) response.Response {
+	return hs.getOrgUsageHelper(c, c.SignedInUser.GetOrgID())
+}
+
+// swagger:route GET /orgs/{org_id}/usage orgs getOrgUsage
+//
+// Fetch the resource usage of an organization.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `orgs.quotas:read` and scope `org:id:1` (orgIDScope).
+//
+// Responses:
+// 200: getOrgUsageResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetOrgUsage(c *contextmodel.ReqContext) response.Response {
+	orgId, err := strconv.ParseInt(web.Params(c.Req)[":orgId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "orgId is invalid", err)
+	}
+	return hs.getOrgUsageHelper(c, orgId)
+}
+
+func (hs *HTTPServer) getOrgUsageHelper(c *contextmodel.ReqContext, orgID int64) response.Response {
+	if _, err := hs.orgService.GetByID(c.Req.Context(), &org.GetOrgByIDQuery{ID: orgID}); err != nil {
+		if errors.Is(err, org.ErrOrgNotFound) {
+			return response.Error(http.StatusNotFound, "Organization not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get organization", err)
+	}
+
+	usage, err := hs.statsService.GetOrgStats(c.Req.Context(), &stats.GetOrgStatsQuery{OrgID: orgID})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get organization usage", err)
+	}
+	return response.JSON(http.StatusOK, usage)
+}
+
 // swagger:route PUT /orgs/{org_id}/quotas/{quota_target} orgs updateOrgQuota
 //
 // Update user quota.
@@ -187,6 +242,13 @@ type GetOrgQuotaParams struct {
 	OrgID int64 `json:"org_id"`
 }
 
+// swagger:parameters getOrgUsage
+type GetOrgUsageParams struct {
+	// in:path
+	// required:true
+	OrgID int64 `json:"org_id"`
+}
+
 // swagger:parameters updateOrgQuota
 type UpdateOrgQuotaParam struct {
 	// in:body
@@ -205,3 +267,9 @@ type GetQuotaResponseResponse struct {
 	// in:body
 	Body []*quota.QuotaDTO `json:"body"`
 }
+
+// swagger:response getOrgUsageResponse
+type GetOrgUsageResponse struct {
+	// in:body
+	Body stats.OrgStats `json:"body"`
+}
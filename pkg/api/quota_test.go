@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -11,6 +12,10 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/stats"
+	"github.com/grafana/grafana/pkg/services/stats/statstest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
@@ -153,3 +158,56 @@ func TestAPIEndpoint_PutOrgQuotas(t *testing.T) {
 		require.NoError(t, response.Body.Close())
 	})
 }
+
+func TestAPIEndpoint_GetOrgUsage(t *testing.T) {
+	fakeStatsService := statstest.NewFakeService()
+	fakeOrgService := orgtest.NewOrgServiceFake()
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.statsService = fakeStatsService
+		hs.orgService = fakeOrgService
+		hs.accesscontrolService = &actest.FakeService{ExpectedPermissions: []accesscontrol.Permission{}}
+		hs.userService = &usertest.FakeUserService{
+			ExpectedSignedInUser: &user.SignedInUser{OrgID: 2},
+		}
+	})
+
+	t.Run("should return the usage of the current org", func(t *testing.T) {
+		fakeStatsService.ExpectedOrgStats = &stats.OrgStats{OrgID: 1, Dashboards: 4, DashboardBytes: 2048}
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/org/usage"), userWithPermissions(1, []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsQuotasRead}}))
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var usage stats.OrgStats
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&usage))
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, int64(4), usage.Dashboards)
+		assert.Equal(t, int64(2048), usage.DashboardBytes)
+	})
+
+	t.Run("AccessControl allows viewing another org usage with correct permissions", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/orgs/2/usage"), userWithPermissions(2, []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsQuotasRead}}))
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should return 404 for an org that doesn't exist", func(t *testing.T) {
+		fakeOrgService.ExpectedError = org.ErrOrgNotFound
+		t.Cleanup(func() { fakeOrgService.ExpectedError = nil })
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/orgs/2/usage"), userWithPermissions(2, []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsQuotasRead}}))
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("AccessControl prevents viewing another org usage with incorrect permissions", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/orgs/2/usage"), userWithPermissions(2, []accesscontrol.Permission{{Action: "orgs:invalid"}}))
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -3,18 +3,53 @@ package api
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/concurrencylimit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
 
+// renderConcurrentLimit returns the render concurrency limit, which can be changed at runtime
+func (hs *HTTPServer) renderConcurrentLimit() int {
+	if hs.concurrencyLimits == nil {
+		return hs.Cfg.RendererConcurrentRequestLimit
+	}
+	if limit := hs.concurrencyLimits.Limit(concurrencylimit.SubsystemRender); limit > 0 {
+		return limit
+	}
+	return math.MaxInt32
+}
+
+// capRenderSize scales the requested image down to the maximum size of the settings, keeping its aspect
+// ratio, and lowers its scale to the maximum scale
+func capRenderSize(cfg *setting.Cfg, width, height int, scale float64) (int, int, float64) {
+	factor := 1.0
+	if cfg.RendererMaxImageWidth > 0 && width > cfg.RendererMaxImageWidth {
+		factor = float64(cfg.RendererMaxImageWidth) / float64(width)
+	}
+	if cfg.RendererMaxImageHeight > 0 && height > cfg.RendererMaxImageHeight {
+		factor = math.Min(factor, float64(cfg.RendererMaxImageHeight)/float64(height))
+	}
+	if factor < 1 {
+		width = int(math.Max(1, math.Floor(float64(width)*factor)))
+		height = int(math.Max(1, math.Floor(float64(height)*factor)))
+	}
+	if cfg.RendererMaxImageScale > 0 && scale > cfg.RendererMaxImageScale {
+		scale = cfg.RendererMaxImageScale
+	}
+	return width, height, scale
+}
+
 func (hs *HTTPServer) RenderToPng(c *contextmodel.ReqContext) {
 	queryReader, err := util.NewURLQueryReader(c.Req.URL)
 	if err != nil {
@@ -23,6 +58,46 @@ func (hs *HTTPServer) RenderToPng(c *contextmodel.ReqContext) {
 	}
 
 	queryParams := fmt.Sprintf("?%s", c.Req.URL.RawQuery)
+	params := c.Req.URL.Query()
+	if name := params.Get(timeRangePresetParam); name != "" {
+		preset, err := hs.resolveOrgTimeRangePreset(c.Req.Context(), c.SignedInUser.GetOrgID(), name)
+		if err != nil {
+			c.Handle(hs.Cfg, http.StatusBadRequest, "Render parameters error", err)
+			return
+		}
+		params.Del(timeRangePresetParam)
+		params.Set("from", strconv.FormatInt(preset.From, 10))
+		params.Set("to", strconv.FormatInt(preset.To, 10))
+		queryParams = "?" + params.Encode()
+	}
+
+	renderPath := web.Params(c.Req)["*"]
+	if dash := hs.renderDashboard(c.Req.Context(), c.SignedInUser, renderPath); dash != nil {
+		// the queries of the panels rendered with a full dashboard are checked by the query API
+		if panelID, err := strconv.ParseInt(params.Get("panelId"), 10, 64); err == nil && strings.HasPrefix(strings.TrimPrefix(renderPath, "/"), "d-solo/") &&
+			hs.isPanelHidden(c.Req.Context(), c.SignedInUser, dash, panelID) {
+			c.Handle(hs.Cfg, http.StatusForbidden, "Dashboard panel is hidden by its visibility", nil)
+			return
+		}
+		// the profile is passed through to the rendered page, which loads the dashboard with it
+		if name := params.Get(renderProfileParam); name != "" {
+			profiles, err := dash.GetRenderProfiles()
+			if err != nil {
+				c.Handle(hs.Cfg, http.StatusInternalServerError, "Failed to read the render profiles of the dashboard", err)
+				return
+			}
+			if _, ok := profiles[name]; !ok {
+				c.Handle(hs.Cfg, http.StatusBadRequest, "Unknown render profile: "+name, nil)
+				return
+			}
+		}
+		if variables := hs.resolveRenderVariables(c.Req.Context(), c.SignedInUser, dash, params); len(variables) > 0 {
+			if len(queryParams) > 1 {
+				queryParams += "&"
+			}
+			queryParams += variables.Encode()
+		}
+	}
 
 	width := c.QueryInt("width")
 	if width == 0 {
@@ -45,6 +120,8 @@ func (hs *HTTPServer) RenderToPng(c *contextmodel.ReqContext) {
 		scale = hs.Cfg.RendererDefaultImageScale
 	}
 
+	width, height, scale = capRenderSize(hs.Cfg, width, height, scale)
+
 	headers := http.Header{}
 	acceptLanguageHeader := c.Req.Header.Values("Accept-Language")
 	if len(acceptLanguageHeader) > 0 {
@@ -69,19 +146,28 @@ func (hs *HTTPServer) RenderToPng(c *contextmodel.ReqContext) {
 		},
 		Width:             width,
 		Height:            height,
-		Path:              web.Params(c.Req)["*"] + queryParams,
+		Path:              renderPath + queryParams,
 		Timezone:          queryReader.Get("tz", ""),
 		Encoding:          encoding,
-		ConcurrentLimit:   hs.Cfg.RendererConcurrentRequestLimit,
+		ConcurrentLimit:   hs.renderConcurrentLimit(),
 		DeviceScaleFactor: scale,
 		Headers:           headers,
 		Theme:             models.ThemeDark,
+		Resizable:         true,
 	}, nil)
 	if err != nil {
 		if errors.Is(err, rendering.ErrTimeout) {
 			c.Handle(hs.Cfg, http.StatusInternalServerError, err.Error(), err)
 			return
 		}
+		if errors.Is(err, rendering.ErrShuttingDown) {
+			c.Handle(hs.Cfg, http.StatusServiceUnavailable, err.Error(), err)
+			return
+		}
+		if errors.Is(err, rendering.ErrCapabilityNotSupported) {
+			c.Handle(hs.Cfg, http.StatusBadRequest, err.Error(), err)
+			return
+		}
 
 		c.Handle(hs.Cfg, http.StatusInternalServerError, "Rendering failed.", err)
 		return
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestCapRenderSize(t *testing.T) {
+	cfg := setting.NewCfg()
+
+	t.Run("should not cap the image without limits", func(t *testing.T) {
+		width, height, scale := capRenderSize(cfg, 8000, 6000, 4)
+		assert.Equal(t, []any{8000, 6000, 4.0}, []any{width, height, scale})
+	})
+
+	cfg.RendererMaxImageWidth = 2000
+	cfg.RendererMaxImageHeight = 1000
+	cfg.RendererMaxImageScale = 2
+
+	t.Run("should keep the image within the limits", func(t *testing.T) {
+		width, height, scale := capRenderSize(cfg, 1000, 500, 2)
+		assert.Equal(t, []any{1000, 500, 2.0}, []any{width, height, scale})
+	})
+
+	t.Run("should scale the image down to the limits keeping its aspect ratio", func(t *testing.T) {
+		width, height, _ := capRenderSize(cfg, 4000, 1000, 1)
+		assert.Equal(t, []int{2000, 500}, []int{width, height})
+
+		width, height, _ = capRenderSize(cfg, 1000, 4000, 1)
+		assert.Equal(t, []int{250, 1000}, []int{width, height})
+	})
+
+	t.Run("should lower the scale to the limit", func(t *testing.T) {
+		_, _, scale := capRenderSize(cfg, 1000, 500, 3)
+		assert.Equal(t, 2.0, scale)
+	})
+}
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+const (
+	renderVariableRefID = "variable"
+	// renderVariableAll is the value of the All option of the variables
+	renderVariableAll = "$__all"
+)
+
+// renderDashboard returns the dashboard of a d/ or d-solo/ render path, or nil when the path isn't a dashboard
+// the user can read
+func (hs *HTTPServer) renderDashboard(ctx context.Context, usr identity.Requester, renderPath string) *dashboards.Dashboard {
+	parts := strings.Split(strings.TrimPrefix(renderPath, "/"), "/")
+	if len(parts) < 2 || (parts[0] != "d" && parts[0] != "d-solo") || parts[1] == "" {
+		return nil
+	}
+	uid := parts[1]
+
+	ok, err := hs.AccessControl.Evaluate(ctx, usr, ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(uid)))
+	if err != nil || !ok {
+		return nil
+	}
+	dash, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: usr.GetOrgID(), UID: uid})
+	if err != nil {
+		hs.log.Debug("Failed to get the dashboard of the render", "uid", uid, "err", err)
+		return nil
+	}
+	return dash
+}
+
+// resolveRenderVariables returns the var- parameters of the dashboard variables that aren't set in params,
+// so that the renders don't show unresolved variables. The current value of the variables is used when the
+// dashboard has one, otherwise the default value is resolved the way the frontend does, executing the
+// variable queries of backend data sources with the permissions of usr.
+func (hs *HTTPServer) resolveRenderVariables(ctx context.Context, usr identity.Requester, dash *dashboards.Dashboard, params url.Values) url.Values {
+	resolved := url.Values{}
+	if dash == nil || dash.Data == nil {
+		return resolved
+	}
+
+	// the values of the variables are available to the queries of the next variables
+	values := map[string][]string{}
+	for _, v := range dash.Data.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(v)
+		name := variable.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+		if explicit, ok := params["var-"+name]; ok {
+			values[name] = explicit
+			continue
+		}
+
+		current, err := hs.resolveRenderVariable(ctx, usr, variable, values, params)
+		if err != nil {
+			hs.log.Warn("Failed to resolve the variable of the render", "dashboard", dash.UID, "variable", name, "err", err)
+			continue
+		}
+		if len(current) == 0 {
+			continue
+		}
+		values[name] = current
+		resolved["var-"+name] = current
+	}
+	return resolved
+}
+
+func (hs *HTTPServer) resolveRenderVariable(ctx context.Context, usr identity.Requester, variable *simplejson.Json, values map[string][]string, params url.Values) ([]string, error) {
+	if current := renderVariableValues(variable.GetPath("current", "value").Interface()); len(current) > 0 {
+		return current, nil
+	}
+
+	query := variable.Get("query")
+	switch variable.Get("type").MustString() {
+	case "constant", "textbox":
+		if s := query.MustString(); s != "" {
+			return []string{s}, nil
+		}
+	case "custom", "interval":
+		for _, option := range variable.Get("options").MustArray() {
+			if value := renderVariableValues(simplejson.NewFromAny(option).Get("value").Interface()); len(value) > 0 {
+				return value, nil
+			}
+		}
+		for _, option := range strings.Split(query.MustString(), ",") {
+			if option = strings.TrimSpace(option); option != "" {
+				return []string{option}, nil
+			}
+		}
+	case "query":
+		if variable.Get("includeAll").MustBool() {
+			return []string{renderVariableAll}, nil
+		}
+		options, err := hs.queryRenderVariable(ctx, usr, variable, values, params)
+		if err != nil || len(options) == 0 {
+			return nil, err
+		}
+		return options[:1], nil
+	}
+	return nil, nil
+}
+
+// queryRenderVariable executes the query of a query variable. Queries stored as strings are sent as raw SQL
+// tables, the format of the SQL data sources, other data sources store their variable queries as objects.
+func (hs *HTTPServer) queryRenderVariable(ctx context.Context, usr identity.Requester, variable *simplejson.Json, values map[string][]string, params url.Values) ([]string, error) {
+	var uid string
+	switch ref := variable.Get("datasource").Interface().(type) {
+	case map[string]any:
+		uid = interpolateRenderVariables(fmt.Sprint(ref["uid"]), values)
+	case string:
+		// legacy reference by name, resolved to its uid
+		ds, err := hs.DataSourcesService.GetDataSource(ctx, &datasources.GetDataSourceQuery{Name: interpolateRenderVariables(ref, values), OrgID: usr.GetOrgID()})
+		if err != nil {
+			return nil, err
+		}
+		uid = ds.UID
+	default:
+		return nil, fmt.Errorf("variable has no data source")
+	}
+
+	model := simplejson.New()
+	switch q := variable.Get("query").Interface().(type) {
+	case map[string]any:
+		for k, v := range q {
+			if s, ok := v.(string); ok {
+				v = interpolateRenderVariables(s, values)
+			}
+			model.Set(k, v)
+		}
+	case string:
+		q = interpolateRenderVariables(q, values)
+		model.Set("rawSql", q)
+		model.Set("format", "table")
+		model.Set("query", q)
+	default:
+		return nil, fmt.Errorf("variable has no query")
+	}
+	model.Set("refId", renderVariableRefID)
+	model.Set("datasource", map[string]any{"uid": uid})
+
+	from, to := params.Get("from"), params.Get("to")
+	if from == "" || to == "" {
+		from, to = "now-6h", "now"
+	}
+	resp, err := hs.queryDataService.QueryData(ctx, usr, false, dtos.MetricRequest{
+		From:    from,
+		To:      to,
+		Queries: []*simplejson.Json{model},
+	})
+	if err != nil {
+		return nil, err
+	}
+	res, ok := resp.Responses[renderVariableRefID]
+	if !ok {
+		return nil, nil
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	var re *regexp.Regexp
+	if pattern := strings.Trim(variable.Get("regex").MustString(), "/"); pattern != "" {
+		if re, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid regex of the variable: %w", err)
+		}
+	}
+
+	var options []string
+	seen := map[string]struct{}{}
+	for _, frame := range res.Frames {
+		if len(frame.Fields) == 0 {
+			continue
+		}
+		// the SQL data sources return the values in __value when they differ from the texts
+		field := frame.Fields[0]
+		if f, idx := frame.FieldByName("__value"); idx >= 0 {
+			field = f
+		}
+		for i := 0; i < field.Len(); i++ {
+			v, ok := field.ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			option := fmt.Sprint(v)
+			if re != nil {
+				match := re.FindStringSubmatch(option)
+				if match == nil {
+					continue
+				}
+				if len(match) > 1 {
+					option = match[1]
+				}
+			}
+			if _, ok := seen[option]; ok {
+				continue
+			}
+			seen[option] = struct{}{}
+			options = append(options, option)
+		}
+	}
+	return options, nil
+}
+
+func renderVariableValues(value any) []string {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		if len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
+
+// interpolateRenderVariables replaces the $name, ${name} and [[name]] references to the resolved variables,
+// the values of multi-value variables are joined by commas
+func interpolateRenderVariables(s string, values map[string][]string) string {
+	if !strings.ContainsAny(s, "$[") {
+		return s
+	}
+	// longest names first, so that $ab isn't replaced by the value of $a
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	for _, name := range names {
+		value := strings.Join(values[name], ",")
+		s = strings.NewReplacer("${"+name+"}", value, "[["+name+"]]", value, "$"+name, value).Replace(s)
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeRenderQueryService struct {
+	requests []dtos.MetricRequest
+}
+
+func (s *fakeRenderQueryService) Run(context.Context) error { return nil }
+
+func (s *fakeRenderQueryService) QueryData(_ context.Context, _ identity.Requester, _ bool, req dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	s.requests = append(s.requests, req)
+	frame := data.NewFrame("", data.NewField("__text", nil, []string{"EU West"}), data.NewField("__value", nil, []string{"eu-west-1"}))
+	return &backend.QueryDataResponse{Responses: backend.Responses{
+		renderVariableRefID: backend.DataResponse{Frames: data.Frames{frame}},
+	}}, nil
+}
+
+func TestResolveRenderVariables(t *testing.T) {
+	dashboardData, err := simplejson.NewJson([]byte(`{
+		"templating": {"list": [
+			{"name": "env", "type": "custom", "query": "prod, staging"},
+			{"name": "host", "type": "textbox", "query": "", "current": {"value": "web-1"}},
+			{"name": "region", "type": "query", "datasource": {"uid": "sql"}, "query": "SELECT region FROM hosts WHERE env = '$env'"},
+			{"name": "service", "type": "query", "datasource": {"uid": "sql"}, "query": "SELECT 1", "includeAll": true},
+			{"name": "cluster", "type": "constant", "query": "a"}
+		]}
+	}`))
+	require.NoError(t, err)
+	dash := &dashboards.Dashboard{UID: "dash", Data: dashboardData}
+	querySvc := &fakeRenderQueryService{}
+	hs := &HTTPServer{queryDataService: querySvc, log: log.New("test")}
+
+	variables := hs.resolveRenderVariables(context.Background(), &user.SignedInUser{OrgID: 1}, dash, url.Values{"var-cluster": {"b"}, "from": {"now-1h"}, "to": {"now"}})
+	assert.Equal(t, url.Values{
+		"var-env":     {"prod"},
+		"var-host":    {"web-1"},
+		"var-region":  {"eu-west-1"},
+		"var-service": {renderVariableAll},
+	}, variables)
+
+	require.Len(t, querySvc.requests, 1)
+	assert.Equal(t, "now-1h", querySvc.requests[0].From)
+	assert.Equal(t, "SELECT region FROM hosts WHERE env = 'prod'", querySvc.requests[0].Queries[0].Get("rawSql").MustString())
+	assert.Equal(t, "sql", querySvc.requests[0].Queries[0].GetPath("datasource", "uid").MustString())
+}
+
+func TestInterpolateRenderVariables(t *testing.T) {
+	values := map[string][]string{"a": {"1"}, "ab": {"2", "3"}}
+	assert.Equal(t, "1 2,3 1 2,3", interpolateRenderVariables("$a $ab ${a} [[ab]]", values))
+}
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -17,23 +20,154 @@ import (
 //
 // Responses:
 // 200: searchResponse
+// 400: badRequestError
 // 401: unauthorisedError
 // 422: unprocessableEntityError
 // 500: internalServerError
 func (hs *HTTPServer) Search(c *contextmodel.ReqContext) response.Response {
+	limit := c.QueryInt64("limit")
+	if limit > 5000 {
+		return response.Error(http.StatusUnprocessableEntity, "Limit is above maximum allowed (5000), use page parameter to access hits beyond limit", nil)
+	}
+
+	searchQuery, errResp := searchQueryFromRequest(c)
+	if errResp != nil {
+		return errResp
+	}
+	searchQuery.Limit = limit
+	searchQuery.Page = c.QueryInt64("page")
+	searchQuery.Sort = c.Query("sort")
+
+	if token := c.Query("continue"); token != "" {
+		next, err := parseSearchContinueToken(token)
+		if err != nil {
+			return response.Error(http.StatusBadRequest, "Invalid continue token", err)
+		}
+		searchQuery.Limit, searchQuery.Page = next.Limit, next.Page
+	}
+
+	totals := model.TotalsMode(c.Query("totals"))
+	switch totals {
+	case "", model.TotalsNone, model.TotalsExact, model.TotalsEstimated:
+	default:
+		return response.Error(http.StatusBadRequest, "The totals mode must be exact, estimated or none", nil)
+	}
+
+	hits, err := hs.SearchService.SearchHandler(c.Req.Context(), searchQuery)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Search failed", err)
+	}
+
+	defer c.TimeRequest(metrics.MApiDashboardSearch)
+
+	resp := response.JSON(http.StatusOK, hits)
+	if totals == model.TotalsExact || totals == model.TotalsEstimated {
+		total, err := hs.SearchService.TotalHandler(c.Req.Context(), searchQuery, totals)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to count the search results", err)
+		}
+		resp.SetHeader(searchTotalHeader, strconv.FormatInt(total.Value, 10))
+		resp.SetHeader(searchTotalRelationHeader, string(total.Relation))
+	}
+
+	// a full page of results might be followed by more results
+	pageLimit := searchQuery.Limit
+	if pageLimit < 1 {
+		pageLimit = defaultSearchLimit
+	}
+	if int64(len(hits)) >= pageLimit {
+		page := max(searchQuery.Page, 1)
+		token, err := searchContinueToken{Limit: pageLimit, Page: page + 1}.encode()
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Search failed", err)
+		}
+		resp.SetHeader(searchContinueHeader, token)
+	}
+	return resp
+}
+
+const (
+	// searchTotalHeader is the total number of results of the search, when requested with the totals parameter
+	searchTotalHeader = "X-Search-Total"
+	// searchTotalRelationHeader tells if the total is exact (eq) or a lower bound (gte)
+	searchTotalRelationHeader = "X-Search-Total-Relation"
+	// searchContinueHeader is the token of the next page of results, if there might be one
+	searchContinueHeader = "X-Search-Continue"
+
+	// defaultSearchLimit is the number of results of the searches without a limit
+	defaultSearchLimit = 1000
+)
+
+// searchContinueToken is the page of results a continue token points to
+type searchContinueToken struct {
+	Limit int64 `json:"l"`
+	Page  int64 `json:"p"`
+}
+
+func (t searchContinueToken) encode() (string, error) {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func parseSearchContinueToken(token string) (searchContinueToken, error) {
+	var t searchContinueToken
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return t, err
+	}
+	if t.Limit < 1 || t.Limit > 5000 || t.Page < 1 {
+		return t, fmt.Errorf("invalid page %d of %d results", t.Page, t.Limit)
+	}
+	return t, nil
+}
+
+// swagger:route GET /search/facets search searchFacets
+//
+// Count the search results per tag, folder, data source and panel type.
+//
+// The counts are computed for the same filters as the search, and only include the dashboards and
+// folders the user can access. The values of each facet are ordered by their number of results, and
+// the data sources and panel types are only counted for the dashboards.
+//
+// Responses:
+// 200: searchFacetsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 422: unprocessableEntityError
+// 500: internalServerError
+func (hs *HTTPServer) SearchFacets(c *contextmodel.ReqContext) response.Response {
+	limit := c.QueryInt64("limit")
+	if limit > 1000 {
+		return response.Error(http.StatusUnprocessableEntity, "Limit is above maximum allowed (1000)", nil)
+	}
+
+	searchQuery, errResp := searchQueryFromRequest(c)
+	if errResp != nil {
+		return errResp
+	}
+	searchQuery.Limit = limit
+
+	facets, err := hs.SearchService.FacetsHandler(c.Req.Context(), searchQuery)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to count the search facets", err)
+	}
+	return response.JSON(http.StatusOK, facets)
+}
+
+// searchQueryFromRequest returns the search query of the filters of the request
+func searchQueryFromRequest(c *contextmodel.ReqContext) (*search.Query, response.Response) {
 	query := c.Query("query")
 	tags := c.QueryStrings("tag")
 	starred := c.Query("starred")
-	limit := c.QueryInt64("limit")
-	page := c.QueryInt64("page")
 	dashboardType := c.Query("type")
-	sort := c.Query("sort")
 	permission := dashboardaccess.PERMISSION_VIEW
 
-	if limit > 5000 {
-		return response.Error(http.StatusUnprocessableEntity, "Limit is above maximum allowed (5000), use page parameter to access hits beyond limit", nil)
-	}
-
 	if c.Query("permission") == "Edit" {
 		permission = dashboardaccess.PERMISSION_EDIT
 	}
@@ -63,19 +197,27 @@ func (hs *HTTPServer) Search(c *contextmodel.ReqContext) response.Response {
 
 	folderUIDs := c.QueryStrings("folderUIDs")
 
+	labels := make([]model.LabelSelector, 0)
+	for _, l := range c.QueryStrings("label") {
+		selector, err := model.ParseLabelSelector(l)
+		if err != nil {
+			return nil, response.Error(http.StatusBadRequest, err.Error(), err)
+		}
+		labels = append(labels, selector)
+	}
+
 	bothDashboardIds := len(dbIDs) > 0 && len(dbUIDs) > 0
 	bothFolderIds := len(folderIDs) > 0 && len(folderUIDs) > 0
 
 	if bothDashboardIds || bothFolderIds {
-		return response.Error(http.StatusBadRequest, "search supports UIDs or IDs, not both", nil)
+		return nil, response.Error(http.StatusBadRequest, "search supports UIDs or IDs, not both", nil)
 	}
 
-	searchQuery := search.Query{
+	return &search.Query{
 		Title:         query,
 		Tags:          tags,
+		Labels:        labels,
 		SignedInUser:  c.SignedInUser,
-		Limit:         limit,
-		Page:          page,
 		IsStarred:     starred == "true",
 		OrgId:         c.SignedInUser.GetOrgID(),
 		DashboardIds:  dbIDs,
@@ -84,17 +226,7 @@ func (hs *HTTPServer) Search(c *contextmodel.ReqContext) response.Response {
 		FolderIds:     folderIDs, // nolint:staticcheck
 		FolderUIDs:    folderUIDs,
 		Permission:    permission,
-		Sort:          sort,
-	}
-
-	hits, err := hs.SearchService.SearchHandler(c.Req.Context(), &searchQuery)
-	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Search failed", err)
-	}
-
-	defer c.TimeRequest(metrics.MApiDashboardSearch)
-
-	return response.JSON(http.StatusOK, hits)
+	}, nil
 }
 
 // swagger:route GET /search/sorting search listSortOptions
@@ -134,6 +266,12 @@ type SearchParams struct {
 	// type: array
 	// collectionFormat: multi
 	Tag []string `json:"tag"`
+	// List of label selectors the results match, such as env=prod, env!=prod, env or !env
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	Label []string `json:"label"`
 	// Type to search for, dash-folder or dash-db
 	// in:query
 	// required: false
@@ -178,6 +316,16 @@ type SearchParams struct {
 	// in:query
 	// required: false
 	Page int64 `json:"page"`
+	// Token of the page of results to return, from the X-Search-Continue header of the previous page. It replaces the limit and page parameters.
+	// in:query
+	// required: false
+	Continue string `json:"continue"`
+	// How to compute the total number of results returned in the X-Search-Total header: exact counts all the results, estimated counts up to 10000 results, none doesn't compute it.
+	// in:query
+	// required: false
+	// default: none
+	// Enum: exact,estimated,none
+	Totals string `json:"totals"`
 	// Set to `Edit` to return dashboards/folders that the user can edit
 	// in:query
 	// required: false
@@ -198,6 +346,61 @@ type SearchResponse struct {
 	Body model.HitList `json:"body"`
 }
 
+// swagger:parameters searchFacets
+type SearchFacetsParams struct {
+	// Search Query
+	// in:query
+	// required: false
+	Query string `json:"query"`
+	// List of tags to search for
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	Tag []string `json:"tag"`
+	// List of label selectors the results match, such as env=prod, env!=prod, env or !env
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	Label []string `json:"label"`
+	// Type to search for, dash-folder or dash-db
+	// in:query
+	// required: false
+	// Enum: dash-folder,dash-db
+	Type string `json:"type"`
+	// List of dashboard uid’s to search for
+	// in:query
+	// required: false
+	DashboardUIDs []string `json:"dashboardUIDs"`
+	// List of folder UID’s to search in for dashboards
+	// If it's an empty string then it will query for the top level folders
+	// in:query
+	// required: false
+	FolderUIDs []string `json:"folderUIDs"`
+	// Flag indicating if only starred Dashboards should be counted
+	// in:query
+	// required: false
+	Starred bool `json:"starred"`
+	// Limit the number of values per facet (max 1000)
+	// in:query
+	// required: false
+	// default: 50
+	Limit int64 `json:"limit"`
+	// Set to `Edit` to count dashboards/folders that the user can edit
+	// in:query
+	// required: false
+	// default:View
+	// Enum: Edit,View
+	Permission string `json:"permission"`
+}
+
+// swagger:response searchFacetsResponse
+type SearchFacetsResponse struct {
+	// in: body
+	Body model.Facets `json:"body"`
+}
+
 // swagger:response listSortOptionsResponse
 type ListSortOptionsResponse struct {
 	// in: body
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_Search(t *testing.T) {
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.SearchService = &mockSearchService{
+			ExpectedResult: model.HitList{{UID: "a"}, {UID: "b"}},
+			ExpectedTotal:  &model.Total{Value: 10000, Relation: model.TotalRelationGreaterEqual},
+		}
+	})
+	search := func(query string) *http.Response {
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/search?"+query), &user.SignedInUser{UserID: 1, OrgID: 1})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res
+	}
+
+	t.Run("should return the total of the results when requested", func(t *testing.T) {
+		res := search("limit=2&totals=estimated")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "10000", res.Header.Get(searchTotalHeader))
+		assert.Equal(t, "gte", res.Header.Get(searchTotalRelationHeader))
+
+		res = search("limit=2")
+		assert.Empty(t, res.Header.Get(searchTotalHeader))
+
+		res = search("totals=all")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should return a continue token after a full page of results", func(t *testing.T) {
+		res := search("limit=2&page=3")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		token, err := parseSearchContinueToken(res.Header.Get(searchContinueHeader))
+		require.NoError(t, err)
+		assert.Equal(t, searchContinueToken{Limit: 2, Page: 4}, token)
+
+		res = search("limit=3")
+		assert.Empty(t, res.Header.Get(searchContinueHeader))
+
+		res = search("continue=invalid")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
@@ -62,6 +62,16 @@ type ConflictError GenericError
 // swagger:response preconditionFailedError
 type PreconditionFailedError GenericError
 
+// PayloadTooLargeError is returned when the request or the resource exceeds a configured size limit.
+//
+// swagger:response payloadTooLargeError
+type PayloadTooLargeError GenericError
+
+// TooManyRequestsError
+//
+// swagger:response tooManyRequestsError
+type TooManyRequestsError GenericError
+
 // UnprocessableEntityError
 //
 // swagger:response unprocessableEntityError
@@ -130,6 +140,11 @@ type UnauthorisedPublicError PublicErrorResponse
 // swagger:response forbiddenPublicError
 type ForbiddenPublicError PublicErrorResponse
 
+// TooManyRequestsPublicError is returned when the request is over the limits of the public dashboard.
+//
+// swagger:response tooManyRequestsPublicError
+type TooManyRequestsPublicError PublicErrorResponse
+
 // InternalServerPublicError is a general error indicating something went wrong internally.
 //
 // swagger:response internalServerPublicError
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/tag"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	defaultTagAutocompleteLimit = 10
+	// maxTagLength is the length of the term column of the dashboard tags
+	maxTagLength = 50
+)
+
+var ErrInvalidTagRename = errutil.BadRequest("tags.invalid-rename")
+
+// RenameTagCommand renames or merges a tag across the dashboards and annotations of the organization
+type RenameTagCommand struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// swagger:route GET /tags tags getTagUsage
+//
+// Get the tags of the dashboards, annotations and alert rules of the current organization.
+//
+// Returns the number of dashboards, annotations and alert rules using each tag, the most used tags first.
+// The labels of the alert rules are returned as key:value tags.
+//
+// Responses:
+// 200: getTagUsageResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) GetTagUsage(c *contextmodel.ReqContext) response.Response {
+	usage, err := hs.tagService.GetUsage(c.Req.Context(), &tag.GetUsageQuery{
+		OrgID:  c.SignedInUser.GetOrgID(),
+		Prefix: c.Query("prefix"),
+		Limit:  c.QueryInt("limit"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get tags", err)
+	}
+	return response.JSON(http.StatusOK, usage)
+}
+
+// swagger:route GET /tags/autocomplete tags autocompleteTags
+//
+// Get the most used tags starting with a prefix.
+//
+// Responses:
+// 200: autocompleteTagsResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) AutocompleteTags(c *contextmodel.ReqContext) response.Response {
+	limit := c.QueryInt("limit")
+	if limit <= 0 {
+		limit = defaultTagAutocompleteLimit
+	}
+	usage, err := hs.tagService.GetUsage(c.Req.Context(), &tag.GetUsageQuery{
+		OrgID:  c.SignedInUser.GetOrgID(),
+		Prefix: c.Query("q"),
+		Limit:  limit,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get tags", err)
+	}
+
+	tags := make([]string, 0, len(usage))
+	for _, u := range usage {
+		tags = append(tags, u.Tag)
+	}
+	return response.JSON(http.StatusOK, tags)
+}
+
+// swagger:route POST /tags/rename tags renameTag
+//
+// Rename a tag of the dashboards and annotations of the current organization.
+//
+// The tag is merged into the new tag when the new tag is already in use. The rename creates no dashboard versions.
+// The labels of the alert rules aren't renamed, since they route the notifications of the rules.
+//
+// Responses:
+// 200: renameTagResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) RenameTag(c *contextmodel.ReqContext) response.Response {
+	cmd := RenameTagCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.From, cmd.To = strings.TrimSpace(cmd.From), strings.TrimSpace(cmd.To)
+	if cmd.From == "" || cmd.To == "" {
+		return response.Err(ErrInvalidTagRename.Errorf("from and to are required"))
+	}
+	if cmd.From == cmd.To {
+		return response.Err(ErrInvalidTagRename.Errorf("from and to are the same tag"))
+	}
+	if utf8.RuneCountInString(cmd.To) > maxTagLength {
+		return response.Err(ErrInvalidTagRename.Errorf("tags are limited to %d characters", maxTagLength))
+	}
+
+	result, err := hs.tagService.Rename(c.Req.Context(), &tag.RenameCommand{
+		OrgID: c.SignedInUser.GetOrgID(),
+		From:  cmd.From,
+		To:    cmd.To,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to rename tag", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters getTagUsage
+type GetTagUsageParams struct {
+	// in:query
+	// required:false
+	Prefix string `json:"prefix"`
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters autocompleteTags
+type AutocompleteTagsParams struct {
+	// in:query
+	// required:false
+	Q string `json:"q"`
+	// in:query
+	// required:false
+	// default:10
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters renameTag
+type RenameTagParams struct {
+	// in:body
+	// required:true
+	Body RenameTagCommand `json:"body"`
+}
+
+// swagger:response getTagUsageResponse
+type GetTagUsageResponse struct {
+	// in:body
+	Body []*tag.Usage `json:"body"`
+}
+
+// swagger:response autocompleteTagsResponse
+type AutocompleteTagsResponse struct {
+	// in:body
+	Body []string `json:"body"`
+}
+
+// swagger:response renameTagResponse
+type RenameTagResponse struct {
+	// in:body
+	Body tag.RenameResult `json:"body"`
+}
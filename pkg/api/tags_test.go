@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/tag"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+type fakeTagService struct {
+	tag.Service
+	usage   []*tag.Usage
+	queries []*tag.GetUsageQuery
+	renames []*tag.RenameCommand
+}
+
+func (s *fakeTagService) GetUsage(_ context.Context, query *tag.GetUsageQuery) ([]*tag.Usage, error) {
+	s.queries = append(s.queries, query)
+	return s.usage, nil
+}
+
+func (s *fakeTagService) Rename(_ context.Context, cmd *tag.RenameCommand) (*tag.RenameResult, error) {
+	s.renames = append(s.renames, cmd)
+	return &tag.RenameResult{Dashboards: 2}, nil
+}
+
+func TestHTTPServer_Tags(t *testing.T) {
+	tagService := &fakeTagService{usage: []*tag.Usage{
+		{Tag: "prod", Dashboards: 2, Total: 2},
+		{Tag: "production", Annotations: 1, Total: 1},
+	}}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.tagService = tagService
+	})
+
+	t.Run("should autocomplete the tags with the most used first", func(t *testing.T) {
+		req := server.NewGetRequest("/api/tags/autocomplete?q=prod")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var tags []string
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&tags))
+		require.NoError(t, res.Body.Close())
+
+		assert.Equal(t, []string{"prod", "production"}, tags)
+		assert.Equal(t, &tag.GetUsageQuery{OrgID: 1, Prefix: "prod", Limit: defaultTagAutocompleteLimit}, tagService.queries[len(tagService.queries)-1])
+	})
+
+	t.Run("should require the permission to write the organization to rename tags", func(t *testing.T) {
+		req := server.NewRequest(http.MethodPost, "/api/tags/rename", strings.NewReader(`{"from": "prod", "to": "production"}`))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		assert.Empty(t, tagService.renames)
+	})
+
+	t.Run("should validate and rename tags", func(t *testing.T) {
+		rename := func(body string) int {
+			req := server.NewRequest(http.MethodPost, "/api/tags/rename", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsWrite}})))
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			return res.StatusCode
+		}
+
+		assert.Equal(t, http.StatusBadRequest, rename(`{"from": "prod"}`))
+		assert.Equal(t, http.StatusBadRequest, rename(`{"from": "prod", "to": " prod "}`))
+		assert.Equal(t, http.StatusBadRequest, rename(`{"from": "prod", "to": "`+strings.Repeat("a", maxTagLength+1)+`"}`))
+		assert.Empty(t, tagService.renames)
+
+		assert.Equal(t, http.StatusOK, rename(`{"from": "prod", "to": "production"}`))
+		assert.Equal(t, []*tag.RenameCommand{{OrgID: 1, From: "prod", To: "production"}}, tagService.renames)
+	})
+}
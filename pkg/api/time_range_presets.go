@@ -0,0 +1,267 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// timeRangePresetParam is the render parameter referencing a preset by name instead of from and to
+const timeRangePresetParam = "timePreset"
+
+var (
+	ErrInvalidTimeRangePreset  = errutil.BadRequest("preferences.invalid-time-range-preset")
+	ErrTimeRangePresetNotFound = errutil.NotFound("preferences.time-range-preset-not-found", errutil.WithPublicMessage("Time range preset not found"))
+
+	timeRangePresetPeriodRegexp = regexp.MustCompile(`^(\d+)([dwMy])$`)
+)
+
+// ResolvedTimeRangePreset is the time range of a preset at the time of the request, in epoch milliseconds
+type ResolvedTimeRangePreset struct {
+	Name string `json:"name"`
+	From int64  `json:"from"`
+	To   int64  `json:"to"`
+}
+
+func validateTimeRangePresets(presets []pref.TimeRangePreset) error {
+	names := make(map[string]struct{}, len(presets))
+	now := time.Now()
+	for i := range presets {
+		preset := &presets[i]
+		preset.Name = strings.TrimSpace(preset.Name)
+		if preset.Name == "" {
+			return ErrInvalidTimeRangePreset.Errorf("name is required")
+		}
+		if _, ok := names[preset.Name]; ok {
+			return ErrInvalidTimeRangePreset.Errorf("duplicate preset %q", preset.Name)
+		}
+		names[preset.Name] = struct{}{}
+
+		if (preset.Anchor == nil) != (preset.Period == "") {
+			return ErrInvalidTimeRangePreset.Errorf("the anchor and the period of preset %q must be set together", preset.Name)
+		}
+		if preset.Anchor != nil && (preset.From != "" || preset.To != "") {
+			return ErrInvalidTimeRangePreset.Errorf("preset %q must have either from and to, or an anchor and a period", preset.Name)
+		}
+		if preset.Anchor == nil && (preset.From == "" || preset.To == "") {
+			return ErrInvalidTimeRangePreset.Errorf("preset %q requires from and to", preset.Name)
+		}
+		if _, _, err := resolveTimeRangePreset(preset, now, time.UTC, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveTimeRangePreset returns the time range of the preset at now, the relative times are resolved in loc
+func resolveTimeRangePreset(preset *pref.TimeRangePreset, now time.Time, loc *time.Location, weekstart *time.Weekday) (time.Time, time.Time, error) {
+	if preset.Anchor != nil {
+		return timeRangePresetPeriod(preset, now)
+	}
+
+	options := []legacydata.TimeRangeOption{legacydata.WithLocation(loc)}
+	if weekstart != nil {
+		options = append(options, legacydata.WithWeekstart(*weekstart))
+	}
+	tr := legacydata.DataTimeRange{From: preset.From, To: preset.To, Now: now}
+	from, err := tr.ParseFrom(options...)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidTimeRangePreset.Errorf("invalid from %q of preset %q: %w", preset.From, preset.Name, err)
+	}
+	to, err := tr.ParseTo(options...)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidTimeRangePreset.Errorf("invalid to %q of preset %q: %w", preset.To, preset.Name, err)
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, ErrInvalidTimeRangePreset.Errorf("preset %q ends before it starts", preset.Name)
+	}
+	return from, to, nil
+}
+
+// timeRangePresetPeriod returns the period of the preset that contains now, the periods are counted in
+// calendar days, months and years from the anchor so that they don't drift
+func timeRangePresetPeriod(preset *pref.TimeRangePreset, now time.Time) (time.Time, time.Time, error) {
+	match := timeRangePresetPeriodRegexp.FindStringSubmatch(preset.Period)
+	if match == nil {
+		return time.Time{}, time.Time{}, ErrInvalidTimeRangePreset.Errorf("invalid period %q of preset %q, expected a number of days, weeks, months or years such as 2w", preset.Period, preset.Name)
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil || n == 0 {
+		return time.Time{}, time.Time{}, ErrInvalidTimeRangePreset.Errorf("invalid period %q of preset %q", preset.Period, preset.Name)
+	}
+	var years, months, days int
+	switch match[2] {
+	case "d":
+		days = n
+	case "w":
+		days = 7 * n
+	case "M":
+		months = n
+	case "y":
+		years = n
+	}
+	period := func(k int) time.Time {
+		return preset.Anchor.AddDate(k*years, k*months, k*days)
+	}
+
+	// estimate the number of periods between the anchor and now, then adjust it
+	approx := time.Duration(years)*365*24*time.Hour + time.Duration(months)*30*24*time.Hour + time.Duration(days)*24*time.Hour
+	k := int(now.Sub(*preset.Anchor) / approx)
+	for period(k).After(now) {
+		k--
+	}
+	for !period(k + 1).After(now) {
+		k++
+	}
+	return period(k), period(k + 1).Add(-time.Millisecond), nil
+}
+
+// getTimeRangePresets returns the presets of the organization with its timezone and week start
+func (hs *HTTPServer) getTimeRangePresets(ctx context.Context, orgID int64) ([]pref.TimeRangePreset, *time.Location, *time.Weekday, error) {
+	prefs, err := hs.preferenceService.Get(ctx, &pref.GetPreferenceQuery{OrgID: orgID})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	loc := time.UTC
+	if prefs.Timezone != "" && prefs.Timezone != "browser" {
+		if l, err := time.LoadLocation(prefs.Timezone); err == nil {
+			loc = l
+		}
+	}
+	var weekstart *time.Weekday
+	if prefs.WeekStart != nil {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.EqualFold(d.String(), *prefs.WeekStart) {
+				weekstart = &d
+				break
+			}
+		}
+	}
+
+	var presets []pref.TimeRangePreset
+	if prefs.JSONData != nil {
+		presets = prefs.JSONData.TimeRangePresets
+	}
+	return presets, loc, weekstart, nil
+}
+
+// resolveOrgTimeRangePreset returns the current time range of the preset of the organization with the given name
+func (hs *HTTPServer) resolveOrgTimeRangePreset(ctx context.Context, orgID int64, name string) (*ResolvedTimeRangePreset, error) {
+	presets, loc, weekstart, err := hs.getTimeRangePresets(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range presets {
+		if presets[i].Name != name {
+			continue
+		}
+		from, to, err := resolveTimeRangePreset(&presets[i], time.Now(), loc, weekstart)
+		if err != nil {
+			return nil, err
+		}
+		return &ResolvedTimeRangePreset{Name: name, From: from.UnixMilli(), To: to.UnixMilli()}, nil
+	}
+	return nil, ErrTimeRangePresetNotFound.Errorf("no time range preset %q", name)
+}
+
+// swagger:route GET /org/preferences/time-range-presets org_preferences getTimeRangePresets
+//
+// Get the quick time range presets of the current organization.
+//
+// Responses:
+// 200: getTimeRangePresetsResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) GetTimeRangePresets(c *contextmodel.ReqContext) response.Response {
+	presets, _, _, err := hs.getTimeRangePresets(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get time range presets", err)
+	}
+	if presets == nil {
+		presets = []pref.TimeRangePreset{}
+	}
+	return response.JSON(http.StatusOK, presets)
+}
+
+// swagger:route PUT /org/preferences/time-range-presets org_preferences updateTimeRangePresets
+//
+// Replace the quick time range presets of the current organization.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateTimeRangePresets(c *contextmodel.ReqContext) response.Response {
+	presets := []pref.TimeRangePreset{}
+	if err := web.Bind(c.Req, &presets); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if err := validateTimeRangePresets(presets); err != nil {
+		return response.Err(err)
+	}
+
+	if err := hs.preferenceService.Patch(c.Req.Context(), &pref.PatchPreferenceCommand{
+		OrgID:            c.SignedInUser.GetOrgID(),
+		TimeRangePresets: &presets,
+	}); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to save time range presets", err)
+	}
+	return response.Success("Time range presets updated")
+}
+
+// swagger:route GET /org/preferences/time-range-presets/resolve org_preferences resolveTimeRangePreset
+//
+// Resolve a quick time range preset of the current organization to its current time range.
+//
+// Responses:
+// 200: resolveTimeRangePresetResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) ResolveTimeRangePreset(c *contextmodel.ReqContext) response.Response {
+	resolved, err := hs.resolveOrgTimeRangePreset(c.Req.Context(), c.SignedInUser.GetOrgID(), c.Query("name"))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to resolve time range preset", err)
+	}
+	return response.JSON(http.StatusOK, resolved)
+}
+
+// swagger:parameters updateTimeRangePresets
+type UpdateTimeRangePresetsParams struct {
+	// in:body
+	// required:true
+	Body []pref.TimeRangePreset `json:"body"`
+}
+
+// swagger:parameters resolveTimeRangePreset
+type ResolveTimeRangePresetParams struct {
+	// in:query
+	// required:true
+	Name string `json:"name"`
+}
+
+// swagger:response getTimeRangePresetsResponse
+type GetTimeRangePresetsResponse struct {
+	// in:body
+	Body []pref.TimeRangePreset `json:"body"`
+}
+
+// swagger:response resolveTimeRangePresetResponse
+type ResolveTimeRangePresetResponse struct {
+	// in:body
+	Body ResolvedTimeRangePreset `json:"body"`
+}
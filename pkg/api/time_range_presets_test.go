@@ -0,0 +1,51 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pref "github.com/grafana/grafana/pkg/services/preference"
+)
+
+func TestResolveTimeRangePreset(t *testing.T) {
+	now := time.Date(2024, time.March, 20, 10, 30, 0, 0, time.UTC)
+
+	t.Run("should resolve relative times", func(t *testing.T) {
+		from, to, err := resolveTimeRangePreset(&pref.TimeRangePreset{Name: "This month", From: "now/M", To: "now/M"}, now, time.UTC, nil)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), from)
+		assert.Equal(t, time.Date(2024, time.March, 31, 23, 59, 59, 999000000, time.UTC), to.Truncate(time.Millisecond))
+	})
+
+	t.Run("should resolve the period that contains now", func(t *testing.T) {
+		anchor := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		from, to, err := resolveTimeRangePreset(&pref.TimeRangePreset{Name: "This sprint", Anchor: &anchor, Period: "2w"}, now, time.UTC, nil)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC), from)
+		assert.Equal(t, time.Date(2024, time.March, 25, 0, 0, 0, 0, time.UTC).Add(-time.Millisecond), to)
+
+		anchor = time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)
+		from, _, err = resolveTimeRangePreset(&pref.TimeRangePreset{Name: "Billing month", Anchor: &anchor, Period: "1M"}, now, time.UTC, nil)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC), from)
+	})
+}
+
+func TestValidateTimeRangePresets(t *testing.T) {
+	anchor := time.Now()
+	for name, presets := range map[string][]pref.TimeRangePreset{
+		"missing name":       {{From: "now-1d", To: "now"}},
+		"duplicate name":     {{Name: "a", From: "now-1d", To: "now"}, {Name: "a", From: "now-2d", To: "now"}},
+		"missing to":         {{Name: "a", From: "now-1d"}},
+		"invalid from":       {{Name: "a", From: "yesterday", To: "now"}},
+		"period and from":    {{Name: "a", From: "now-1d", To: "now", Anchor: &anchor, Period: "1w"}},
+		"period unit":        {{Name: "a", Anchor: &anchor, Period: "1h"}},
+		"missing the anchor": {{Name: "a", Period: "1w"}},
+	} {
+		assert.ErrorIs(t, validateTimeRangePresets(presets), ErrInvalidTimeRangePreset, name)
+	}
+	assert.NoError(t, validateTimeRangePresets([]pref.TimeRangePreset{{Name: "Last week", From: "now-1w/w", To: "now-1w/w"}}))
+}
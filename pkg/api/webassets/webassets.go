@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/services/licensing"
@@ -32,6 +33,16 @@ type EntryPointInfo struct {
 }
 
 var entryPointAssetsCache *dtos.EntryPointAssets = nil
+var assetManifestCache *dtos.AssetManifest = nil
+
+// contentHashPattern matches the content hash webpack adds to the name of the files it builds
+var contentHashPattern = regexp.MustCompile(`\.[0-9a-f]{16,}\.`)
+
+// IsContentHashed reports whether the file name contains a content hash, in which case
+// its content never changes and it can be cached forever.
+func IsContentHashed(name string) bool {
+	return contentHashPattern.MatchString(filepath.Base(name))
+}
 
 func GetWebAssets(ctx context.Context, cfg *setting.Cfg, license licensing.Licensing) (*dtos.EntryPointAssets, error) {
 	if cfg.Env != setting.Dev && entryPointAssetsCache != nil {
@@ -60,6 +71,57 @@ func GetWebAssets(ctx context.Context, cfg *setting.Cfg, license licensing.Licen
 	return entryPointAssetsCache, err
 }
 
+// GetAssetManifest returns every file listed in the webpack assets manifest, keyed by its logical name
+func GetAssetManifest(ctx context.Context, cfg *setting.Cfg, license licensing.Licensing) (*dtos.AssetManifest, error) {
+	if cfg.Env != setting.Dev && assetManifestCache != nil {
+		return assetManifestCache, nil
+	}
+
+	result, err := readAssetManifestFromFile(filepath.Join(cfg.StaticRootPath, "build", "assets-manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	cdn, _ := cfg.GetContentDeliveryURL(license.ContentDeliveryPrefix())
+	result.SetContentDeliveryURL(cdn)
+
+	assetManifestCache = result
+	return assetManifestCache, nil
+}
+
+func readAssetManifestFromFile(manifestpath string) (*dtos.AssetManifest, error) {
+	//nolint:gosec
+	f, err := os.Open(manifestpath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load assets-manifest.json %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return readAssetManifest(f)
+}
+
+func readAssetManifest(r io.Reader) (*dtos.AssetManifest, error) {
+	manifest := map[string]ManifestInfo{}
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to read assets-manifest.json %w", err)
+	}
+
+	assets := make(map[string]dtos.EntryPointAsset, len(manifest))
+	for name, v := range manifest {
+		// the entrypoints entry has no file of its own
+		if v.FilePath == "" {
+			continue
+		}
+		assets[name] = dtos.EntryPointAsset{
+			FilePath:  v.FilePath,
+			Integrity: v.Integrity,
+		}
+	}
+
+	return &dtos.AssetManifest{Assets: assets}, nil
+}
+
 func readWebAssetsFromFile(manifestpath string) (*dtos.EntryPointAssets, error) {
 	//nolint:gosec
 	f, err := os.Open(manifestpath)
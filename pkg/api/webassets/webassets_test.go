@@ -5,9 +5,32 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func TestIsContentHashed(t *testing.T) {
+	assert.True(t, IsContentHashed("/public/build/app.0439db6f56ee4aa501b2.js"))
+	assert.True(t, IsContentHashed("/public/build/1096.0bbf0dc9a95cc43faff1.js.map"))
+	assert.True(t, IsContentHashed("grafana.dark.a28b24b45b2bbcc628cc.css"))
+	assert.False(t, IsContentHashed("/public/build/assets-manifest.json"))
+	assert.False(t, IsContentHashed("/public/build/app.js"))
+	assert.False(t, IsContentHashed("/public/build.0439db6f56ee4aa501b2.dir/app.js"))
+}
+
+func TestReadAssetManifest(t *testing.T) {
+	manifest, err := readAssetManifestFromFile("testdata/sample-assets-manifest.json")
+	require.NoError(t, err)
+
+	require.NotContains(t, manifest.Assets, "entrypoints")
+	require.Equal(t, "public/build/app.0439db6f56ee4aa501b2.js", manifest.Assets["app.js"].FilePath)
+	require.NotEmpty(t, manifest.Assets["app.js"].Integrity)
+
+	manifest.SetContentDeliveryURL("https://grafana-assets.grafana.net/grafana/10.3.0-64123/")
+	require.Equal(t, "https://grafana-assets.grafana.net/grafana/10.3.0-64123/", manifest.ContentDeliveryURL)
+	require.Equal(t, "https://grafana-assets.grafana.net/grafana/10.3.0-64123/public/build/app.0439db6f56ee4aa501b2.js", manifest.Assets["app.js"].FilePath)
+}
+
 func TestReadWebassets(t *testing.T) {
 	assets, err := readWebAssetsFromFile("testdata/sample-assets-manifest.json")
 	require.NoError(t, err)
@@ -115,6 +115,9 @@ func NewImageUploader(cfg *setting.Cfg) (ImageUploader, error) {
 		return NewAzureBlobUploader(account_name, account_key, container_name, sas_token_expiration_days), nil
 
 	case "local":
+		if cfg.ImageUploadLocalSignedURLs {
+			return NewSignedLocalImageUploader(cfg.SecretKey, cfg.ImageUploadLocalSignedURLExpiration)
+		}
 		return NewLocalImageUploader()
 	}
 
@@ -0,0 +1,41 @@
+package imguploader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// LocalImageURLQuery returns the query parameters signing the URL of an image of the local provider until it
+// expires, the signature is an HMAC-SHA256 of the file name and expiry
+func LocalImageURLQuery(secretKey string, filename string, expires time.Time) url.Values {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return url.Values{
+		"expires":   []string{exp},
+		"signature": []string{base64.RawURLEncoding.EncodeToString(localImageSignature(secretKey, filename, exp))},
+	}
+}
+
+// VerifyLocalImageURLQuery checks that the query parameters sign the URL of the image and haven't expired
+func VerifyLocalImageURLQuery(secretKey string, filename string, query url.Values, now time.Time) bool {
+	exp := query.Get("expires")
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || now.Unix() >= expires {
+		return false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(query.Get("signature"))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(signature, localImageSignature(secretKey, filename, exp))
+}
+
+func localImageSignature(secretKey string, filename string, expires string) []byte {
+	mac := hmac.New(sha256.New, []byte("image-attachments:"+secretKey))
+	_, _ = mac.Write([]byte(filename + ":" + expires))
+	return mac.Sum(nil)
+}
@@ -4,19 +4,33 @@ import (
 	"context"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 type LocalUploader struct {
+	// the URLs are signed when the expiration is set
+	secretKey           string
+	signedURLExpiration time.Duration
+	now                 func() time.Time
 }
 
 func (u *LocalUploader) Upload(ctx context.Context, imageOnDiskPath string) (string, error) {
 	filename := filepath.Base(imageOnDiskPath)
 	image_url := setting.ToAbsUrl(path.Join("public/img/attachments", filename))
+	if u.signedURLExpiration > 0 {
+		image_url += "?" + LocalImageURLQuery(u.secretKey, filename, u.now().Add(u.signedURLExpiration)).Encode()
+	}
 	return image_url, nil
 }
 
 func NewLocalImageUploader() (*LocalUploader, error) {
-	return &LocalUploader{}, nil
+	return &LocalUploader{now: time.Now}, nil
+}
+
+// NewSignedLocalImageUploader returns a local uploader signing the URLs of the images, they expire after the
+// expiration
+func NewSignedLocalImageUploader(secretKey string, expiration time.Duration) (*LocalUploader, error) {
+	return &LocalUploader{secretKey: secretKey, signedURLExpiration: expiration, now: time.Now}, nil
 }
@@ -2,7 +2,10 @@ package imguploader
 
 import (
 	"context"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -16,3 +19,21 @@ func TestUploadToLocal(t *testing.T) {
 		require.Contains(t, path, "/public/img/attachments")
 	})
 }
+
+func TestSignedUploadToLocal(t *testing.T) {
+	now := time.Now()
+	localUploader, err := NewSignedLocalImageUploader("secret", time.Hour)
+	require.NoError(t, err)
+	localUploader.now = func() time.Time { return now }
+
+	imageURL, err := localUploader.Upload(context.Background(), "../../../public/img/logo_transparent_400x.png")
+	require.NoError(t, err)
+	u, err := url.Parse(imageURL)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(u.Path, "/public/img/attachments/logo_transparent_400x.png"))
+
+	require.True(t, VerifyLocalImageURLQuery("secret", "logo_transparent_400x.png", u.Query(), now))
+	require.False(t, VerifyLocalImageURLQuery("secret", "logo_transparent_400x.png", u.Query(), now.Add(time.Hour)))
+	require.False(t, VerifyLocalImageURLQuery("other", "logo_transparent_400x.png", u.Query(), now))
+	require.False(t, VerifyLocalImageURLQuery("secret", "other.png", u.Query(), now))
+}
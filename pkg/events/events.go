@@ -78,3 +78,72 @@ type FolderTitleUpdated struct {
 	UID       string    `json:"uid"`
 	OrgID     int64     `json:"org_id"`
 }
+
+// ResourcePermissionsChanged is published when the managed permissions of a resource have changed
+type ResourcePermissionsChanged struct {
+	Timestamp  time.Time `json:"timestamp"`
+	OrgID      int64     `json:"org_id"`
+	Resource   string    `json:"resource"`
+	ResourceID string    `json:"resource_id"`
+	// ActorID and ActorLogin identify the user who changed the permissions, they are empty for changes made by Grafana
+	ActorID    int64                `json:"actor_id,omitempty"`
+	ActorLogin string               `json:"actor_login,omitempty"`
+	Before     []ResourcePermission `json:"before"`
+	After      []ResourcePermission `json:"after"`
+}
+
+// ResourcePermission is the permission a user, team or basic role has on a resource
+type ResourcePermission struct {
+	// Subject is the assignee of the permission, e.g. "user:admin", "team:devs" or "role:Viewer"
+	Subject    string `json:"subject"`
+	UserID     int64  `json:"user_id,omitempty"`
+	TeamID     int64  `json:"team_id,omitempty"`
+	Permission string `json:"permission"`
+}
+
+// DashboardCreated is published after a dashboard or folder has been created in the dashboard store
+type DashboardCreated struct {
+	Timestamp time.Time `json:"timestamp"`
+	OrgID     int64     `json:"org_id"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	Title     string    `json:"title"`
+	FolderUID string    `json:"folder_uid"`
+	IsFolder  bool      `json:"is_folder"`
+	Version   int       `json:"version"`
+}
+
+// DashboardUpdated is published after a dashboard or folder has been updated in the dashboard store
+type DashboardUpdated struct {
+	Timestamp time.Time `json:"timestamp"`
+	OrgID     int64     `json:"org_id"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	Title     string    `json:"title"`
+	FolderUID string    `json:"folder_uid"`
+	IsFolder  bool      `json:"is_folder"`
+	Version   int       `json:"version"`
+}
+
+// DashboardDeleted is published after a dashboard or folder has been deleted, including the dashboards
+// deleted together with their folder or their organization
+type DashboardDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	OrgID     int64     `json:"org_id"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	Title     string    `json:"title"`
+	IsFolder  bool      `json:"is_folder"`
+}
+
+// LoginAnomalyDetected is published when a user signs in in an unusual way, for example from a new IP address
+type LoginAnomalyDetected struct {
+	Timestamp      time.Time `json:"timestamp"`
+	OrgID          int64     `json:"org_id"`
+	UserID         int64     `json:"user_id"`
+	Login          string    `json:"login"`
+	IP             string    `json:"ip"`
+	UserAgent      string    `json:"user_agent"`
+	Reasons        []string  `json:"reasons"`
+	FailedAttempts int       `json:"failed_attempts"`
+}
@@ -45,7 +45,7 @@ func (f *FakeKVStore) Keys(ctx context.Context, orgId int64, namespace string, k
 	for k := range f.store {
 		if orgId == AllOrganizations && namespace == "" && keyPrefix == "" {
 			res = append(res, k)
-		} else if k.OrgId == orgId && k.Namespace == namespace && strings.HasPrefix(k.Key, keyPrefix) {
+		} else if (orgId == AllOrganizations || k.OrgId == orgId) && k.Namespace == namespace && strings.HasPrefix(k.Key, keyPrefix) {
 			res = append(res, k)
 		}
 	}
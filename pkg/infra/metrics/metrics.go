@@ -93,6 +93,24 @@ var (
 	// MRenderingQueue is a metric gauge for image rendering queue size
 	MRenderingQueue prometheus.Gauge
 
+	// MRenderingQueueWaiting is a metric gauge for the renders waiting in the render queue, by priority
+	MRenderingQueueWaiting *prometheus.GaugeVec
+
+	// MRenderingQueueRunning is a metric gauge for the renders of the render queue in progress, by priority
+	MRenderingQueueRunning *prometheus.GaugeVec
+
+	// MRenderingQueueRejectedTotal is a metric counter for the renders rejected by the full render queue, by priority
+	MRenderingQueueRejectedTotal *prometheus.CounterVec
+
+	// MRenderingQueueWaitDuration is a metric histogram for the time the renders wait in the render queue, by priority
+	MRenderingQueueWaitDuration *prometheus.HistogramVec
+
+	// MRenderedImagesDeletedTotal is a metric counter for the rendered images deleted by the cleanup
+	MRenderedImagesDeletedTotal prometheus.Counter
+
+	// MRenderedImagesDeletedBytesTotal is a metric counter for the size of the rendered images deleted by the cleanup
+	MRenderedImagesDeletedBytesTotal prometheus.Counter
+
 	// MAccessEvaluationCount is a metric gauge for total number of evaluation requests
 	MAccessEvaluationCount prometheus.Counter
 
@@ -108,11 +126,17 @@ var (
 	// MPublicDashboardDatasourceQuerySuccess is a metric counter for successful queries labelled by datasource
 	MPublicDashboardDatasourceQuerySuccess *prometheus.CounterVec
 
+	// MPublicDashboardThrottledTotal is a metric counter for public dashboards queries rejected by their limits
+	MPublicDashboardThrottledTotal *prometheus.CounterVec
+
 	// MFolderIDsAPICount is a metric counter for folder ids count in the api package
 	MFolderIDsAPICount *prometheus.CounterVec
 
 	// MFolderIDsServicesCount is a metric counter for folder ids count in the services package
 	MFolderIDsServiceCount *prometheus.CounterVec
+
+	// MDashboardLimitExceeded is a metric counter for dashboards rejected by the dashboard size limits
+	MDashboardLimitExceeded *prometheus.CounterVec
 )
 
 // Timers
@@ -244,10 +268,18 @@ const (
 	DashboardImport  string = "dashboardimport"
 )
 
+// labels of MDashboardLimitExceeded
+const (
+	DashboardLimitJSONSize    string = "json_size"
+	DashboardLimitPanels      string = "panels"
+	DashboardLimitPayloadSize string = "payload_size"
+)
+
 func init() {
 	httpStatusCodes := []string{"200", "404", "500", "unknown"}
 	objectiveMap := map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
 	apiFolderIDMethods := []string{GetAlerts, GetDashboard, RestoreDashboardVersion, GetFolderByID, GetFolderDescendantCounts, SearchFolders, GetFolderPermissionList, UpdateFolderPermissions, GetFolderACL, Search, GetDashboardACL, NewToFolderDTO, GetFolders}
+	dashboardLimits := []string{DashboardLimitJSONSize, DashboardLimitPanels, DashboardLimitPayloadSize}
 	folderIDServices := []string{Folder, Dashboard, LibraryElements, LibraryPanels, NGAlerts, Provisioning, PublicDashboards, AccessControl, Guardian, Search, DashboardImport}
 
 	MInstanceStart = prometheus.NewCounter(prometheus.CounterOpts{
@@ -436,6 +468,43 @@ func init() {
 		Namespace: ExporterName,
 	})
 
+	MRenderingQueueWaiting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "rendering_queue_waiting",
+		Help:      "number of renders waiting in the render queue",
+		Namespace: ExporterName,
+	}, []string{"priority"})
+
+	MRenderingQueueRunning = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "rendering_queue_running",
+		Help:      "number of renders of the render queue in progress",
+		Namespace: ExporterName,
+	}, []string{"priority"})
+
+	MRenderingQueueRejectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "rendering_queue_rejected_total",
+		Help:      "counter for renders rejected because the render queue is full",
+		Namespace: ExporterName,
+	}, []string{"priority"})
+
+	MRenderingQueueWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "rendering_queue_wait_duration_seconds",
+		Help:      "histogram of the time renders wait in the render queue",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30},
+		Namespace: ExporterName,
+	}, []string{"priority"})
+
+	MRenderedImagesDeletedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "rendered_images_deleted_total",
+		Help:      "counter for rendered images deleted after their retention",
+		Namespace: ExporterName,
+	})
+
+	MRenderedImagesDeletedBytesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "rendered_images_deleted_bytes_total",
+		Help:      "counter for the size of the rendered images deleted after their retention",
+		Namespace: ExporterName,
+	})
+
 	MDataSourceProxyReqTimer = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name:       "api_dataproxy_request_all_milliseconds",
 		Help:       "summary for dataproxy request duration",
@@ -468,6 +537,12 @@ func init() {
 		Namespace: ExporterName,
 	}, []string{"datasource", "status"}, map[string][]string{"status": pubdash.QueryResultStatuses})
 
+	MPublicDashboardThrottledTotal = metricutil.NewCounterVecStartingAtZero(prometheus.CounterOpts{
+		Name:      "public_dashboard_throttled_total",
+		Help:      "counter for public dashboards queries rejected by the query rate limit or the concurrent viewers limit, labelled by reason",
+		Namespace: ExporterName,
+	}, []string{"reason"}, map[string][]string{"reason": pubdash.ThrottleReasons})
+
 	MFolderIDsAPICount = metricutil.NewCounterVecStartingAtZero(prometheus.CounterOpts{
 		Name:      "folder_id_api_count",
 		Help:      "counter for folder id usage in api package",
@@ -480,6 +555,12 @@ func init() {
 		Namespace: ExporterName,
 	}, []string{"service"}, map[string][]string{"service": folderIDServices})
 
+	MDashboardLimitExceeded = metricutil.NewCounterVecStartingAtZero(prometheus.CounterOpts{
+		Name:      "dashboard_limit_exceeded_total",
+		Help:      "counter for dashboards rejected by the dashboard size limits labelled by limit",
+		Namespace: ExporterName,
+	}, []string{"limit"}, map[string][]string{"limit": dashboardLimits})
+
 	MStatTotalDashboards = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name:      "stat_totals_dashboard",
 		Help:      "total amount of dashboards",
@@ -742,6 +823,12 @@ func initMetricVars(reg prometheus.Registerer) {
 		MRenderingSummary,
 		MRenderingUserLookupSummary,
 		MRenderingQueue,
+		MRenderingQueueWaiting,
+		MRenderingQueueRunning,
+		MRenderingQueueRejectedTotal,
+		MRenderingQueueWaitDuration,
+		MRenderedImagesDeletedTotal,
+		MRenderedImagesDeletedBytesTotal,
 		MAccessPermissionsSummary,
 		MAccessEvaluationsSummary,
 		MAccessSearchPermissionsSummary,
@@ -774,8 +861,10 @@ func initMetricVars(reg prometheus.Registerer) {
 		MStatTotalPublicDashboards,
 		MPublicDashboardRequestCount,
 		MPublicDashboardDatasourceQuerySuccess,
+		MPublicDashboardThrottledTotal,
 		MStatTotalCorrelations,
 		MFolderIDsAPICount,
 		MFolderIDsServiceCount,
+		MDashboardLimitExceeded,
 	)
 }
@@ -0,0 +1,225 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrEgressDenied is returned for the requests to hosts that the egress policy doesn't allow.
+var ErrEgressDenied = errors.New("egress denied by policy")
+
+// EgressPolicy allows or denies the hosts of the HTTP requests Grafana sends to URLs set by users, such as
+// webhooks. The patterns are host names, wildcards such as *.example.com matching the subdomains, IP
+// addresses and CIDR ranges. Denied patterns take precedence over allowed ones, and when there are allowed
+// patterns, only the hosts matching one of them are allowed.
+type EgressPolicy struct {
+	allowedNames []string
+	allowedNets  []*net.IPNet
+	deniedNames  []string
+	deniedNets   []*net.IPNet
+}
+
+// NewEgressPolicy returns the policy of the allowed and denied patterns, or nil if there are none.
+func NewEgressPolicy(allowed, denied []string) (*EgressPolicy, error) {
+	if len(allowed) == 0 && len(denied) == 0 {
+		return nil, nil
+	}
+	p := &EgressPolicy{}
+	var err error
+	if p.allowedNames, p.allowedNets, err = parseEgressPatterns(allowed); err != nil {
+		return nil, err
+	}
+	if p.deniedNames, p.deniedNets, err = parseEgressPatterns(denied); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func parseEgressPatterns(patterns []string) ([]string, []*net.IPNet, error) {
+	var names []string
+	var nets []*net.IPNet
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		switch {
+		case pattern == "":
+			continue
+		case strings.Contains(pattern, "/"):
+			_, ipNet, err := net.ParseCIDR(pattern)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid egress CIDR %q: %w", pattern, err)
+			}
+			nets = append(nets, ipNet)
+		case net.ParseIP(pattern) != nil:
+			ip := net.ParseIP(pattern)
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		case strings.Contains(strings.TrimPrefix(pattern, "*."), "*"):
+			return nil, nil, fmt.Errorf("invalid egress host %q, only a leading *. wildcard is supported", pattern)
+		default:
+			names = append(names, pattern)
+		}
+	}
+	return names, nets, nil
+}
+
+// CheckHost returns ErrEgressDenied if the policy denies the host name or IP address. The IP addresses
+// a host name resolves to are only checked when the connection is dialed.
+func (p *EgressPolicy) CheckHost(host string) error {
+	_, err := p.checkHost(host)
+	return err
+}
+
+// checkHost also returns whether the host is only allowed if its IP addresses match an allowed pattern
+func (p *EgressPolicy) checkHost(host string) (bool, error) {
+	if p == nil {
+		return false, nil
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if ip := net.ParseIP(host); ip != nil {
+		return false, p.checkIP(ip, true)
+	}
+	if matchEgressName(p.deniedNames, host) {
+		return false, fmt.Errorf("%w: host %q is denied", ErrEgressDenied, host)
+	}
+	if (len(p.allowedNames) == 0 && len(p.allowedNets) == 0) || matchEgressName(p.allowedNames, host) {
+		return false, nil
+	}
+	if len(p.allowedNets) > 0 {
+		return true, nil
+	}
+	return false, fmt.Errorf("%w: host %q is not allowed", ErrEgressDenied, host)
+}
+
+func (p *EgressPolicy) checkIP(ip net.IP, requireAllowed bool) error {
+	if matchEgressNet(p.deniedNets, ip) {
+		return fmt.Errorf("%w: address %s is denied", ErrEgressDenied, ip)
+	}
+	if requireAllowed && (len(p.allowedNames) > 0 || len(p.allowedNets) > 0) && !matchEgressNet(p.allowedNets, ip) {
+		return fmt.Errorf("%w: address %s is not allowed", ErrEgressDenied, ip)
+	}
+	return nil
+}
+
+func matchEgressName(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func matchEgressNet(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type egressPolicyKey struct{}
+
+type egressTarget struct {
+	policy *EgressPolicy
+	host   string
+	// requireAllowedIP is true when the host is only allowed if its IP addresses are
+	requireAllowedIP bool
+}
+
+// ContextWithEgressPolicy returns a context enforcing the policy on the requests of the transports wrapped
+// with EgressRoundTripper and EgressDialContext.
+func ContextWithEgressPolicy(ctx context.Context, policy *EgressPolicy) context.Context {
+	if policy == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, egressPolicyKey{}, &egressTarget{policy: policy})
+}
+
+// EgressRoundTripper checks the host of each request, redirects included, against the egress policy of the
+// request context.
+func EgressRoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		target, ok := req.Context().Value(egressPolicyKey{}).(*egressTarget)
+		if !ok {
+			return next.RoundTrip(req)
+		}
+		host := req.URL.Hostname()
+		requireAllowedIP, err := target.policy.checkHost(host)
+		if err != nil {
+			return nil, err
+		}
+		ctx := context.WithValue(req.Context(), egressPolicyKey{}, &egressTarget{
+			policy:           target.policy,
+			host:             strings.ToLower(host),
+			requireAllowedIP: requireAllowedIP,
+		})
+		return next.RoundTrip(req.WithContext(ctx))
+	})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+// DialContextFunc is the signature of net.Dialer.DialContext
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// EgressDialContext checks the IP addresses the host of the request resolves to against the egress policy
+// of the request context, and dials the checked addresses in turn so that the host can't resolve to another
+// address afterwards. The connections to a proxy aren't checked, the host of the request is checked instead.
+func EgressDialContext(dial DialContextFunc) DialContextFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		target, ok := ctx.Value(egressPolicyKey{}).(*egressTarget)
+		if !ok || target.host == "" {
+			return dial(ctx, network, address)
+		}
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		host = strings.ToLower(host)
+		if host != target.host {
+			// the connection to a proxy, the proxy connects to the host
+			if target.requireAllowedIP {
+				return nil, fmt.Errorf("%w: host %q is not allowed", ErrEgressDenied, target.host)
+			}
+			return dial(ctx, network, address)
+		}
+		if net.ParseIP(host) != nil {
+			// IP addresses were checked with the request
+			return dial(ctx, network, address)
+		}
+
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			if err := target.policy.checkIP(addr.IP, target.requireAllowedIP); err != nil {
+				return nil, fmt.Errorf("host %q: %w", host, err)
+			}
+		}
+		err = fmt.Errorf("no addresses for host %q", host)
+		for _, addr := range addrs {
+			var conn net.Conn
+			if conn, err = dial(ctx, network, net.JoinHostPort(addr.IP.String(), port)); err == nil {
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
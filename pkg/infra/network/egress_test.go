@@ -0,0 +1,103 @@
+package network
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEgressPolicy_CheckHost(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		allowed []string
+		denied  []string
+		host    string
+		denies  bool
+	}{
+		{desc: "No patterns", host: "example.com"},
+		{desc: "Denied host", denied: []string{"example.com"}, host: "Example.com", denies: true},
+		{desc: "Denied wildcard", denied: []string{"*.internal"}, host: "hooks.internal", denies: true},
+		{desc: "Wildcard doesn't match the domain itself", denied: []string{"*.internal"}, host: "internal"},
+		{desc: "Denied CIDR", denied: []string{"169.254.0.0/16"}, host: "169.254.169.254", denies: true},
+		{desc: "Denied IP", denied: []string{"::1"}, host: "::1", denies: true},
+		{desc: "Allowed host", allowed: []string{"hooks.slack.com"}, host: "hooks.slack.com"},
+		{desc: "Not allowed host", allowed: []string{"hooks.slack.com"}, host: "example.com", denies: true},
+		{desc: "Not allowed IP", allowed: []string{"hooks.slack.com"}, host: "10.0.0.1", denies: true},
+		{desc: "Allowed CIDR", allowed: []string{"10.0.0.0/8"}, host: "10.0.0.1"},
+		{desc: "Denied takes precedence", allowed: []string{"*.example.com"}, denied: []string{"admin.example.com"}, host: "admin.example.com", denies: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			policy, err := NewEgressPolicy(tc.allowed, tc.denied)
+			require.NoError(t, err)
+			err = policy.CheckHost(tc.host)
+			if tc.denies {
+				require.ErrorIs(t, err, ErrEgressDenied)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("Invalid patterns", func(t *testing.T) {
+		_, err := NewEgressPolicy(nil, []string{"10.0.0.0/33"})
+		require.Error(t, err)
+		_, err = NewEgressPolicy([]string{"hooks.*.com"}, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestEgressTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: EgressRoundTripper(&http.Transport{
+		DialContext: EgressDialContext((&net.Dialer{}).DialContext),
+	})}
+	send := func(t *testing.T, policy *EgressPolicy, url string) error {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ContextWithEgressPolicy(context.Background(), policy), http.MethodGet, url, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err == nil {
+			require.NoError(t, resp.Body.Close())
+		}
+		return err
+	}
+
+	t.Run("should send the requests without policy", func(t *testing.T) {
+		require.NoError(t, send(t, nil, server.URL))
+	})
+
+	t.Run("should deny the addresses a host resolves to", func(t *testing.T) {
+		policy, err := NewEgressPolicy(nil, []string{"127.0.0.0/8", "::1"})
+		require.NoError(t, err)
+		require.ErrorIs(t, send(t, policy, "http://localhost:"+port), ErrEgressDenied)
+	})
+
+	t.Run("should allow a host by the addresses it resolves to", func(t *testing.T) {
+		policy, err := NewEgressPolicy([]string{"127.0.0.0/8", "::1"}, nil)
+		require.NoError(t, err)
+		require.NoError(t, send(t, policy, "http://localhost:"+port))
+	})
+
+	t.Run("should check the redirects", func(t *testing.T) {
+		policy, err := NewEgressPolicy(nil, []string{"169.254.0.0/16"})
+		require.NoError(t, err)
+		err = send(t, policy, server.URL+"/redirect")
+		assert.ErrorIs(t, err, ErrEgressDenied)
+	})
+}
@@ -0,0 +1,259 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Encoders are pooled since brotli and zstd encoders are expensive to allocate.
+var encoderPools = map[string]*sync.Pool{
+	"gzip": {New: func() any { return gzip.NewWriter(nil) }},
+	"br":   {New: func() any { return brotli.NewWriter(nil) }},
+	"zstd": {New: func() any {
+		// NewWriter only fails on invalid options
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		return enc
+	}},
+}
+
+// compressResponseWriter buffers the response until it reaches the minimum size and then
+// decides whether to compress it. Smaller responses are written as is.
+type compressResponseWriter struct {
+	web.ResponseWriter
+	encoding string
+	minSize  int
+
+	status  int
+	buf     []byte
+	started bool
+	enc     encoder
+}
+
+func (cw *compressResponseWriter) WriteHeader(status int) {
+	if cw.status == 0 {
+		cw.status = status
+	}
+}
+
+func (cw *compressResponseWriter) Write(p []byte) (int, error) {
+	if cw.started {
+		return cw.write(p)
+	}
+
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if cw.Header().Get("Content-Type") == "" {
+		cw.Header().Set("Content-Type", http.DetectContentType(p))
+	}
+
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) < cw.minSize && !cw.belowMinSize() {
+		return len(p), nil
+	}
+
+	buf := cw.buf
+	cw.buf = nil
+	cw.start(!cw.belowMinSize())
+	if _, err := cw.write(buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// belowMinSize reports whether the handler announced a Content-Length below the minimum size
+func (cw *compressResponseWriter) belowMinSize() bool {
+	length, err := strconv.Atoi(cw.Header().Get("Content-Length"))
+	return err == nil && length < cw.minSize
+}
+
+func (cw *compressResponseWriter) write(p []byte) (int, error) {
+	if cw.enc != nil {
+		return cw.enc.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+func (cw *compressResponseWriter) start(compress bool) {
+	cw.started = true
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+
+	h := cw.Header()
+	h.Add("Vary", "Accept-Encoding")
+	if compress && h.Get("Content-Encoding") == "" && bodyAllowedForStatus(cw.status) {
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		cw.enc = encoderPools[cw.encoding].Get().(encoder)
+		cw.enc.Reset(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
+
+// Written reports whether the handler wrote a response, even if it is still buffered
+func (cw *compressResponseWriter) Written() bool {
+	return cw.status != 0 || cw.ResponseWriter.Written()
+}
+
+func (cw *compressResponseWriter) Status() int {
+	if cw.status != 0 {
+		return cw.status
+	}
+	return cw.ResponseWriter.Status()
+}
+
+func (cw *compressResponseWriter) Flush() {
+	// flushing means the handler is streaming, so waiting for the minimum size is not an option
+	if !cw.started {
+		buf := cw.buf
+		cw.buf = nil
+		cw.start(true)
+		if _, err := cw.write(buf); err != nil {
+			return
+		}
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Flush()
+	}
+	cw.ResponseWriter.Flush()
+}
+
+func (cw *compressResponseWriter) close() {
+	if !cw.started && cw.status != 0 {
+		buf := cw.buf
+		cw.buf = nil
+		cw.start(false)
+		_, _ = cw.write(buf)
+	}
+
+	if cw.enc != nil {
+		// We can't really handle close errors at this point and we can't report them to the caller
+		_ = cw.enc.Close()
+		cw.enc.Reset(nil)
+		encoderPools[cw.encoding].Put(cw.enc)
+		cw.enc = nil
+	}
+}
+
+func (cw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("compression ResponseWriter doesn't implement the Hijacker interface")
+}
+
+func bodyAllowedForStatus(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// negotiateEncoding returns the first of the supported encodings accepted by the
+// Accept-Encoding header, or an empty string if the client accepts none of them.
+func negotiateEncoding(acceptEncoding string, supported []string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+
+		ok := true
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if qvalue, err := strconv.ParseFloat(q, 64); err == nil && qvalue == 0 {
+				ok = false
+			}
+		}
+		accepted[coding] = ok
+	}
+
+	for _, encoding := range supported {
+		if ok, found := accepted[encoding]; found {
+			if ok {
+				return encoding
+			}
+			continue
+		}
+		if accepted["*"] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+type matcher func(s string) bool
+
+func prefix(p string) matcher { return func(s string) bool { return strings.HasPrefix(s, p) } }
+func substr(p string) matcher { return func(s string) bool { return strings.Contains(s, p) } }
+
+var compressionIgnoredPaths = []matcher{
+	prefix("/api/datasources"),
+	prefix("/api/plugins"),
+	prefix("/api/plugin-proxy/"),
+	prefix("/api/gnet/"), // Already gzipped by grafana.com.
+	prefix("/metrics"),
+	prefix("/api/live/ws"),   // WebSocket does not support compression.
+	prefix("/api/live/push"), // WebSocket does not support compression.
+	substr("/resources"),
+}
+
+// Compression compresses responses using the first algorithm of cfg.CompressionAlgorithms
+// that is accepted by the client.
+func Compression(cfg *setting.Cfg) func(http.Handler) http.Handler {
+	ignoredPaths := append([]matcher{}, compressionIgnoredPaths...)
+	for _, p := range cfg.CompressionExcludedPaths {
+		ignoredPaths = append(ignoredPaths, prefix(p))
+	}
+
+	algorithms := cfg.CompressionAlgorithms
+	if len(algorithms) == 0 {
+		algorithms = []string{"gzip"}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			requestPath := req.URL.RequestURI()
+
+			for _, pathMatcher := range ignoredPaths {
+				if pathMatcher(requestPath) {
+					next.ServeHTTP(rw, req)
+					return
+				}
+			}
+
+			encoding := negotiateEncoding(req.Header.Get("Accept-Encoding"), algorithms)
+			if encoding == "" || req.Method == http.MethodHead {
+				next.ServeHTTP(rw, req)
+				return
+			}
+
+			cw := &compressResponseWriter{
+				ResponseWriter: rw.(web.ResponseWriter),
+				encoding:       encoding,
+				minSize:        cfg.CompressionMinSize,
+			}
+
+			next.ServeHTTP(cw, req)
+			cw.close()
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	supported := []string{"zstd", "br", "gzip"}
+
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{acceptEncoding: "", expected: ""},
+		{acceptEncoding: "gzip, deflate", expected: "gzip"},
+		{acceptEncoding: "gzip, deflate, br", expected: "br"},
+		{acceptEncoding: "gzip, br, zstd", expected: "zstd"},
+		{acceptEncoding: "gzip;q=1.0, br;q=0, zstd;q=0", expected: "gzip"},
+		{acceptEncoding: "*", expected: "zstd"},
+		{acceptEncoding: "*, zstd;q=0", expected: "br"},
+		{acceptEncoding: "identity", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiateEncoding(tt.acceptEncoding, supported))
+		})
+	}
+}
+
+func TestCompression(t *testing.T) {
+	body := strings.Repeat("grafana ", 500)
+
+	serve := func(t *testing.T, cfg *setting.Cfg, path, acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		Compression(cfg)(handler).ServeHTTP(web.NewResponseWriter(req.Method, rec), req)
+		return rec
+	}
+
+	writeBody := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}
+
+	decode := map[string]func(r io.Reader) (io.Reader, error){
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"br":   func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+		"zstd": func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) },
+	}
+
+	for encoding, newReader := range decode {
+		t.Run("compresses with "+encoding, func(t *testing.T) {
+			cfg := setting.NewCfg()
+			cfg.CompressionAlgorithms = []string{encoding}
+
+			rec := serve(t, cfg, "/api/dashboards/uid/abc", "gzip, br, zstd", writeBody)
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, encoding, rec.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+			assert.Less(t, rec.Body.Len(), len(body))
+
+			r, err := newReader(rec.Body)
+			require.NoError(t, err)
+			decoded, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, body, string(decoded))
+		})
+	}
+
+	t.Run("does not compress responses below the minimum size", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.CompressionAlgorithms = []string{"gzip"}
+		cfg.CompressionMinSize = 1024
+
+		rec := serve(t, cfg, "/api/health", "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("small"))
+		})
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "small", rec.Body.String())
+
+		rec = serve(t, cfg, "/api/search", "gzip", writeBody)
+		assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("does not compress excluded paths", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.CompressionExcludedPaths = []string{"/api/stream"}
+
+		for _, path := range []string{"/api/stream/events", "/api/live/ws"} {
+			rec := serve(t, cfg, path, "gzip", writeBody)
+			assert.Empty(t, rec.Header().Get("Content-Encoding"), path)
+			assert.Equal(t, body, rec.Body.String(), path)
+		}
+	})
+
+	t.Run("does not compress when the client accepts none of the algorithms", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.CompressionAlgorithms = []string{"zstd"}
+
+		rec := serve(t, cfg, "/api/search", "gzip", writeBody)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rec.Body.String())
+	})
+
+	t.Run("does not compress responses without a body", func(t *testing.T) {
+		rec := serve(t, setting.NewCfg(), "/api/frontend/capabilities", "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotModified)
+		})
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Zero(t, rec.Body.Len())
+	})
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// LimitDashboardPayload rejects the dashboard save and import requests with a body larger than limit bytes,
+// 0 means unlimited. The bodies without content length fail with a *http.MaxBytesError while they are read.
+func LimitDashboardPayload(limit int64) web.Handler {
+	return func(c *contextmodel.ReqContext) {
+		if limit <= 0 || c.Req.Body == nil {
+			return
+		}
+		if c.Req.ContentLength > limit {
+			metrics.MDashboardLimitExceeded.WithLabelValues(metrics.DashboardLimitPayloadSize).Inc()
+			err := dashboards.NewDashboardLimitErr(dashboards.ErrDashboardPayloadTooLarge, "bytes", c.Req.ContentLength, limit)
+			c.JSON(http.StatusRequestEntityTooLarge, err.Body())
+			return
+		}
+		c.Req.Body = http.MaxBytesReader(c.Resp, c.Req.Body, limit)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const imageAttachmentsPrefix = "/public/img/attachments/"
+
+// ValidateSignedImageAttachments rejects the requests of the images stored by the local image uploader that
+// aren't signed, or whose signature expired
+func ValidateSignedImageAttachments(cfg *setting.Cfg) web.Handler {
+	return func(c *web.Context) {
+		if !strings.HasPrefix(c.Req.URL.Path, imageAttachmentsPrefix) {
+			return
+		}
+
+		filename := strings.TrimPrefix(c.Req.URL.Path, imageAttachmentsPrefix)
+		if !imguploader.VerifyLocalImageURLQuery(cfg.SecretKey, filename, c.Req.URL.Query(), time.Now()) {
+			http.Error(c.Resp, "Invalid or expired image signature", http.StatusForbidden)
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/components/imguploader"
+)
+
+func TestValidateSignedImageAttachments(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		url          func(secretKey string) string
+		expectedCode int
+	}{
+		{
+			desc: "should serve images with a valid signature",
+			url: func(secretKey string) string {
+				return "/public/img/attachments/a.png?" + imguploader.LocalImageURLQuery(secretKey, "a.png", time.Now().Add(time.Minute)).Encode()
+			},
+			expectedCode: 200,
+		},
+		{
+			desc:         "should reject images without a signature",
+			url:          func(string) string { return "/public/img/attachments/a.png" },
+			expectedCode: 403,
+		},
+		{
+			desc: "should reject images signed for another file",
+			url: func(secretKey string) string {
+				return "/public/img/attachments/a.png?" + imguploader.LocalImageURLQuery(secretKey, "b.png", time.Now().Add(time.Minute)).Encode()
+			},
+			expectedCode: 403,
+		},
+		{
+			desc: "should reject expired signatures",
+			url: func(secretKey string) string {
+				return "/public/img/attachments/a.png?" + imguploader.LocalImageURLQuery(secretKey, "a.png", time.Now().Add(-time.Minute)).Encode()
+			},
+			expectedCode: 403,
+		},
+	}
+
+	for _, tc := range testCases {
+		middlewareScenario(t, tc.desc, func(t *testing.T, sc *scenarioContext) {
+			sc.m.Use(ValidateSignedImageAttachments(sc.cfg))
+			sc.m.Get("/public/img/attachments/a.png", sc.defaultHandler)
+
+			sc.fakeReq("GET", tc.url(sc.cfg.SecretKey)).exec()
+			assert.Equal(t, tc.expectedCode, sc.resp.Code)
+		})
+	}
+}
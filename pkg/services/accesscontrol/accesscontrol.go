@@ -45,6 +45,8 @@ type Service interface {
 	SaveExternalServiceRole(ctx context.Context, cmd SaveExternalServiceRoleCommand) error
 	// DeleteExternalServiceRole removes an external service's role and its assignment.
 	DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error
+	// SaveOrgTemplateRole creates or updates a role of an organization and assigns it to the given teams and basic roles.
+	SaveOrgTemplateRole(ctx context.Context, cmd SaveOrgTemplateRoleCommand) error
 	// SyncUserRoles adds provided roles to user
 	SyncUserRoles(ctx context.Context, orgID int64, cmd SyncUserRolesCommand) error
 }
@@ -58,6 +60,7 @@ type Store interface {
 	DeleteTeamPermissions(ctx context.Context, orgID, teamID int64) error
 	SaveExternalServiceRole(ctx context.Context, cmd SaveExternalServiceRoleCommand) error
 	DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error
+	SaveOrgTemplateRole(ctx context.Context, cmd SaveOrgTemplateRoleCommand) error
 }
 
 type RoleRegistry interface {
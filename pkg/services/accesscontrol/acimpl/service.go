@@ -125,7 +125,7 @@ func (s *Service) getUserPermissions(ctx context.Context, user identity.Requeste
 		UserID:       userID,
 		Roles:        accesscontrol.GetOrgRoles(user),
 		TeamIDs:      user.GetTeams(),
-		RolePrefixes: []string{accesscontrol.ManagedRolePrefix, accesscontrol.ExternalServiceRolePrefix},
+		RolePrefixes: []string{accesscontrol.ManagedRolePrefix, accesscontrol.ExternalServiceRolePrefix, accesscontrol.OrgTemplateRolePrefix},
 	})
 	if err != nil {
 		return nil, err
@@ -443,6 +443,14 @@ func (s *Service) SaveExternalServiceRole(ctx context.Context, cmd accesscontrol
 	return s.store.SaveExternalServiceRole(ctx, cmd)
 }
 
+func (s *Service) SaveOrgTemplateRole(ctx context.Context, cmd accesscontrol.SaveOrgTemplateRoleCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+
+	return s.store.SaveOrgTemplateRole(ctx, cmd)
+}
+
 func (s *Service) DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error {
 	if !s.features.IsEnabled(ctx, featuremgmt.FlagExternalServiceAccounts) {
 		s.log.Debug("Deleting an external service role is behind a feature flag, enable it to use this feature.")
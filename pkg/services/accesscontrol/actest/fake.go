@@ -57,6 +57,10 @@ func (f FakeService) SaveExternalServiceRole(ctx context.Context, cmd accesscont
 	return f.ExpectedErr
 }
 
+func (f FakeService) SaveOrgTemplateRole(ctx context.Context, cmd accesscontrol.SaveOrgTemplateRoleCommand) error {
+	return f.ExpectedErr
+}
+
 func (f FakeService) DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error {
 	return f.ExpectedErr
 }
@@ -106,6 +110,10 @@ func (f FakeStore) SaveExternalServiceRole(ctx context.Context, cmd accesscontro
 	return f.ExpectedErr
 }
 
+func (f FakeStore) SaveOrgTemplateRole(ctx context.Context, cmd accesscontrol.SaveOrgTemplateRoleCommand) error {
+	return f.ExpectedErr
+}
+
 func (f FakeStore) DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error {
 	return f.ExpectedErr
 }
@@ -147,6 +147,24 @@ func (_m *MockStore) SaveExternalServiceRole(ctx context.Context, cmd accesscont
 	return r0
 }
 
+// SaveOrgTemplateRole provides a mock function with given fields: ctx, cmd
+func (_m *MockStore) SaveOrgTemplateRole(ctx context.Context, cmd accesscontrol.SaveOrgTemplateRoleCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOrgTemplateRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, accesscontrol.SaveOrgTemplateRoleCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SearchUsersPermissions provides a mock function with given fields: ctx, orgID, options
 func (_m *MockStore) SearchUsersPermissions(ctx context.Context, orgID int64, options accesscontrol.SearchOptions) (map[int64][]accesscontrol.Permission, error) {
 	ret := _m.Called(ctx, orgID, options)
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+func orgTemplateRoleName(name string) string {
+	return fmt.Sprintf("%s%s", accesscontrol.OrgTemplateRolePrefix, name)
+}
+
+func (s *AccessControlStore) SaveOrgTemplateRole(ctx context.Context, cmd accesscontrol.SaveOrgTemplateRoleCommand) error {
+	name := orgTemplateRoleName(cmd.Name)
+	now := time.Now()
+	role := accesscontrol.Role{
+		OrgID:   cmd.OrgID,
+		Version: 1,
+		Name:    name,
+		// role uids are unique across organizations
+		UID:         accesscontrol.PrefixedRoleUID(fmt.Sprintf("%s:%d", name, cmd.OrgID)),
+		DisplayName: cmd.DisplayName,
+		Description: cmd.Description,
+		Group:       "Organization template",
+		Created:     now,
+		Updated:     now,
+	}
+
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existingRole, err := s.saveRole(ctx, sess, &role)
+		if err != nil {
+			return err
+		}
+		if err := s.savePermissions(ctx, sess, existingRole.ID, cmd.Permissions); err != nil {
+			return err
+		}
+
+		// the assignments are replaced so that the role is only granted to the teams and basic roles of the command
+		if _, err := sess.Where("role_id = ?", existingRole.ID).Delete(&accesscontrol.TeamRole{}); err != nil {
+			return err
+		}
+		for _, teamID := range cmd.TeamIDs {
+			if _, err := sess.Insert(&accesscontrol.TeamRole{OrgID: cmd.OrgID, RoleID: existingRole.ID, TeamID: teamID, Created: now}); err != nil {
+				return err
+			}
+		}
+
+		if _, err := sess.Where("role_id = ?", existingRole.ID).Delete(&accesscontrol.BuiltinRole{}); err != nil {
+			return err
+		}
+		for _, basicRole := range cmd.BasicRoles {
+			if _, err := sess.Insert(&accesscontrol.BuiltinRole{OrgID: cmd.OrgID, RoleID: existingRole.ID, Role: basicRole, Created: now, Updated: now}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
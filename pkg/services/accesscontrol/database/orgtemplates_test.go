@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+func TestAccessControlStore_SaveOrgTemplateRole(t *testing.T) {
+	ctx := context.Background()
+	s := &AccessControlStore{
+		sql: db.InitTestDB(t),
+	}
+
+	cmd := accesscontrol.SaveOrgTemplateRoleCommand{
+		OrgID:       2,
+		Name:        "dashboard-creators",
+		Permissions: []accesscontrol.Permission{{Action: "dashboards:create", Scope: "folders:uid:shared"}},
+		TeamIDs:     []int64{7},
+		BasicRoles:  []string{"Viewer"},
+	}
+	require.NoError(t, s.SaveOrgTemplateRole(ctx, cmd))
+
+	prefixes := []string{accesscontrol.OrgTemplateRolePrefix}
+	permissions, err := s.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{OrgID: 2, TeamIDs: []int64{7}, RolePrefixes: prefixes})
+	require.NoError(t, err)
+	require.Len(t, permissions, 1)
+	assert.Equal(t, "dashboards:create", permissions[0].Action)
+
+	permissions, err = s.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{OrgID: 2, Roles: []string{"Viewer"}, RolePrefixes: prefixes})
+	require.NoError(t, err)
+	require.Len(t, permissions, 1)
+
+	t.Run("should keep the roles of other orgs apart", func(t *testing.T) {
+		other := cmd
+		other.OrgID = 3
+		require.NoError(t, s.SaveOrgTemplateRole(ctx, other))
+
+		permissions, err := s.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{OrgID: 2, TeamIDs: []int64{7}, RolePrefixes: prefixes})
+		require.NoError(t, err)
+		require.Len(t, permissions, 1)
+	})
+
+	t.Run("should replace the permissions and the assignments of the role", func(t *testing.T) {
+		updated := cmd
+		updated.Permissions = []accesscontrol.Permission{{Action: "dashboards:read", Scope: "folders:uid:shared"}}
+		updated.BasicRoles = nil
+		require.NoError(t, s.SaveOrgTemplateRole(ctx, updated))
+
+		permissions, err := s.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{OrgID: 2, TeamIDs: []int64{7}, RolePrefixes: prefixes})
+		require.NoError(t, err)
+		require.Len(t, permissions, 1)
+		assert.Equal(t, "dashboards:read", permissions[0].Action)
+
+		permissions, err = s.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{OrgID: 2, Roles: []string{"Viewer"}, RolePrefixes: prefixes})
+		require.NoError(t, err)
+		assert.Empty(t, permissions)
+	})
+}
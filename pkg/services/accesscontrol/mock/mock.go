@@ -32,6 +32,7 @@ type Calls struct {
 	SearchUsersPermissions         []interface{}
 	SearchUserPermissions          []interface{}
 	SaveExternalServiceRole        []interface{}
+	SaveOrgTemplateRole            []interface{}
 	DeleteExternalServiceRole      []interface{}
 }
 
@@ -58,6 +59,7 @@ type Mock struct {
 	SearchUsersPermissionsFunc         func(context.Context, identity.Requester, int64, accesscontrol.SearchOptions) (map[int64][]accesscontrol.Permission, error)
 	SearchUserPermissionsFunc          func(ctx context.Context, orgID int64, searchOptions accesscontrol.SearchOptions) ([]accesscontrol.Permission, error)
 	SaveExternalServiceRoleFunc        func(ctx context.Context, cmd accesscontrol.SaveExternalServiceRoleCommand) error
+	SaveOrgTemplateRoleFunc            func(ctx context.Context, cmd accesscontrol.SaveOrgTemplateRoleCommand) error
 	DeleteExternalServiceRoleFunc      func(ctx context.Context, externalServiceID string) error
 	SyncUserRolesFunc                  func(ctx context.Context, orgID int64, cmd accesscontrol.SyncUserRolesCommand) error
 
@@ -239,6 +241,15 @@ func (m *Mock) SaveExternalServiceRole(ctx context.Context, cmd accesscontrol.Sa
 	return nil
 }
 
+func (m *Mock) SaveOrgTemplateRole(ctx context.Context, cmd accesscontrol.SaveOrgTemplateRoleCommand) error {
+	m.Calls.SaveOrgTemplateRole = append(m.Calls.SaveOrgTemplateRole, []interface{}{ctx, cmd})
+	// Use override if provided
+	if m.SaveOrgTemplateRoleFunc != nil {
+		return m.SaveOrgTemplateRoleFunc(ctx, cmd)
+	}
+	return nil
+}
+
 func (m *Mock) DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error {
 	m.Calls.DeleteExternalServiceRole = append(m.Calls.DeleteExternalServiceRole, []interface{}{ctx, externalServiceID})
 	// Use override if provided
@@ -328,6 +328,55 @@ func (cmd *SaveExternalServiceRoleCommand) Validate() error {
 	return nil
 }
 
+// SaveOrgTemplateRoleCommand creates or updates a role of an organization template and replaces its
+// assignments to teams and basic roles
+type SaveOrgTemplateRoleCommand struct {
+	OrgID       int64
+	Name        string
+	DisplayName string
+	Description string
+	Permissions []Permission
+	TeamIDs     []int64
+	BasicRoles  []string
+}
+
+func (cmd *SaveOrgTemplateRoleCommand) Validate() error {
+	if cmd.OrgID <= 0 {
+		return fmt.Errorf("invalid org id %d", cmd.OrgID)
+	}
+	if cmd.Name == "" {
+		return errors.New("role name not specified")
+	}
+
+	// slugify the name for the role to have correct name and uid
+	cmd.Name = slugify.Slugify(cmd.Name)
+
+	if len(cmd.Permissions) == 0 {
+		return errors.New("no permissions provided")
+	}
+	dedupMap := map[Permission]bool{}
+	dedup := make([]Permission, 0, len(cmd.Permissions))
+	for i := range cmd.Permissions {
+		if len(cmd.Permissions[i].Action) == 0 {
+			return fmt.Errorf("role %v has a permission with no Action", cmd.Name)
+		}
+		if dedupMap[cmd.Permissions[i]] {
+			continue
+		}
+		dedupMap[cmd.Permissions[i]] = true
+		dedup = append(dedup, cmd.Permissions[i])
+	}
+	cmd.Permissions = dedup
+
+	for _, role := range cmd.BasicRoles {
+		if !org.RoleType(role).IsValid() {
+			return fmt.Errorf("role %v is assigned to invalid basic role %q", cmd.Name, role)
+		}
+	}
+
+	return nil
+}
+
 const (
 	GlobalOrgID      = 0
 	NoOrgID          = int64(-1)
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -18,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/licensing"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/retriever"
 	"github.com/grafana/grafana/pkg/services/team"
@@ -205,6 +208,8 @@ func ProvideDashboardPermissions(
 		ReaderRoleName: "Dashboard permission reader",
 		WriterRoleName: "Dashboard permission writer",
 		RoleGroup:      "Dashboards",
+		OnChange:       auditDashboardACLChange(dashboardStore, false),
+		PublishChanges: cfg.PermissionChangeNotifications.Enabled(),
 	}
 
 	srv, err := resourcepermissions.New(cfg, options, features, router, license, ac, service, sql, teamService, userService)
@@ -214,6 +219,44 @@ func ProvideDashboardPermissions(
 	return &DashboardPermissionsService{srv}, nil
 }
 
+// auditDashboardACLChange records the permissions of a dashboard or folder before and after a change,
+// together with the user who made it, in the ACL audit history.
+func auditDashboardACLChange(dashboardStore dashboards.Store, isFolder bool) func(ctx context.Context, change *events.ResourcePermissionsChanged) error {
+	toAuditItems := func(permissions []events.ResourcePermission) []dashboards.DashboardACLAuditItem {
+		items := make([]dashboards.DashboardACLAuditItem, 0, len(permissions))
+		for _, p := range permissions {
+			item := dashboards.DashboardACLAuditItem{UserID: p.UserID, TeamID: p.TeamID}
+			if role, ok := strings.CutPrefix(p.Subject, "role:"); ok {
+				orgRole := org.RoleType(role)
+				item.Role = &orgRole
+			}
+			switch p.Permission {
+			case "View":
+				item.Permission = dashboardaccess.PERMISSION_VIEW
+			case "Edit":
+				item.Permission = dashboardaccess.PERMISSION_EDIT
+			case "Admin":
+				item.Permission = dashboardaccess.PERMISSION_ADMIN
+			}
+			items = append(items, item)
+		}
+		return items
+	}
+
+	return func(ctx context.Context, change *events.ResourcePermissionsChanged) error {
+		return dashboardStore.SaveDashboardACLAudit(ctx, &dashboards.DashboardACLAuditEntry{
+			OrgID:        change.OrgID,
+			DashboardUID: change.ResourceID,
+			IsFolder:     isFolder,
+			ActorID:      change.ActorID,
+			ActorLogin:   change.ActorLogin,
+			Previous:     toAuditItems(change.Before),
+			New:          toAuditItems(change.After),
+			Created:      change.Timestamp,
+		})
+	}
+}
+
 type FolderPermissionsService struct {
 	*resourcepermissions.Service
 }
@@ -270,6 +313,8 @@ func ProvideFolderPermissions(
 		ReaderRoleName: "Folder permission reader",
 		WriterRoleName: "Folder permission writer",
 		RoleGroup:      "Folders",
+		OnChange:       auditDashboardACLChange(dashboardStore, true),
+		PublishChanges: cfg.PermissionChangeNotifications.Enabled(),
 	}
 	srv, err := resourcepermissions.New(cfg, options, features, router, license, accesscontrol, service, sql, teamService, userService)
 	if err != nil {
@@ -3,6 +3,7 @@ package resourcepermissions
 import (
 	"context"
 
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/web"
@@ -42,4 +43,12 @@ type Options struct {
 	InheritedScopesSolver InheritedScopesSolver
 	// LicenseMV if configured is applied to endpoints that can modify permissions
 	LicenseMW web.Handler
+	// OnChange if configured will be called, within the transaction of the change, each time the managed
+	// permissions of a resource are modified. Returning an error rolls the change back.
+	OnChange func(ctx context.Context, change *events.ResourcePermissionsChanged) error
+	// PublishChanges will publish an events.ResourcePermissionsChanged event, with the managed permissions
+	// before and after the change, each time the permissions of a resource are modified.
+	// Computing the permissions before and after the change takes two extra queries, it should only be
+	// enabled when the event is consumed. The same queries are shared with OnChange.
+	PublishChanges bool
 }
@@ -3,15 +3,20 @@ package resourcepermissions
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sort"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/appcontext"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/licensing"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -153,13 +158,19 @@ func (s *Service) SetUserPermission(ctx context.Context, orgID int64, user acces
 		return nil, err
 	}
 
-	return s.store.SetUserResourcePermission(ctx, orgID, user, SetResourcePermissionCommand{
-		Actions:           actions,
-		Permission:        permission,
-		Resource:          s.options.Resource,
-		ResourceID:        resourceID,
-		ResourceAttribute: s.options.ResourceAttribute,
-	}, s.options.OnSetUser)
+	var result *accesscontrol.ResourcePermission
+	err = s.withChangeEvent(ctx, orgID, resourceID, func(ctx context.Context) error {
+		var err error
+		result, err = s.store.SetUserResourcePermission(ctx, orgID, user, SetResourcePermissionCommand{
+			Actions:           actions,
+			Permission:        permission,
+			Resource:          s.options.Resource,
+			ResourceID:        resourceID,
+			ResourceAttribute: s.options.ResourceAttribute,
+		}, s.options.OnSetUser)
+		return err
+	})
+	return result, err
 }
 
 func (s *Service) SetTeamPermission(ctx context.Context, orgID, teamID int64, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
@@ -176,13 +187,19 @@ func (s *Service) SetTeamPermission(ctx context.Context, orgID, teamID int64, re
 		return nil, err
 	}
 
-	return s.store.SetTeamResourcePermission(ctx, orgID, teamID, SetResourcePermissionCommand{
-		Actions:           actions,
-		Permission:        permission,
-		Resource:          s.options.Resource,
-		ResourceID:        resourceID,
-		ResourceAttribute: s.options.ResourceAttribute,
-	}, s.options.OnSetTeam)
+	var result *accesscontrol.ResourcePermission
+	err = s.withChangeEvent(ctx, orgID, resourceID, func(ctx context.Context) error {
+		var err error
+		result, err = s.store.SetTeamResourcePermission(ctx, orgID, teamID, SetResourcePermissionCommand{
+			Actions:           actions,
+			Permission:        permission,
+			Resource:          s.options.Resource,
+			ResourceID:        resourceID,
+			ResourceAttribute: s.options.ResourceAttribute,
+		}, s.options.OnSetTeam)
+		return err
+	})
+	return result, err
 }
 
 func (s *Service) SetBuiltInRolePermission(ctx context.Context, orgID int64, builtInRole, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
@@ -199,13 +216,19 @@ func (s *Service) SetBuiltInRolePermission(ctx context.Context, orgID int64, bui
 		return nil, err
 	}
 
-	return s.store.SetBuiltInResourcePermission(ctx, orgID, builtInRole, SetResourcePermissionCommand{
-		Actions:           actions,
-		Permission:        permission,
-		Resource:          s.options.Resource,
-		ResourceID:        resourceID,
-		ResourceAttribute: s.options.ResourceAttribute,
-	}, s.options.OnSetBuiltInRole)
+	var result *accesscontrol.ResourcePermission
+	err = s.withChangeEvent(ctx, orgID, resourceID, func(ctx context.Context) error {
+		var err error
+		result, err = s.store.SetBuiltInResourcePermission(ctx, orgID, builtInRole, SetResourcePermissionCommand{
+			Actions:           actions,
+			Permission:        permission,
+			Resource:          s.options.Resource,
+			ResourceID:        resourceID,
+			ResourceAttribute: s.options.ResourceAttribute,
+		}, s.options.OnSetBuiltInRole)
+		return err
+	})
+	return result, err
 }
 
 func (s *Service) SetPermissions(
@@ -251,11 +274,17 @@ func (s *Service) SetPermissions(
 		})
 	}
 
-	return s.store.SetResourcePermissions(ctx, orgID, dbCommands, ResourceHooks{
-		User:        s.options.OnSetUser,
-		Team:        s.options.OnSetTeam,
-		BuiltInRole: s.options.OnSetBuiltInRole,
+	var result []accesscontrol.ResourcePermission
+	err := s.withChangeEvent(ctx, orgID, resourceID, func(ctx context.Context) error {
+		var err error
+		result, err = s.store.SetResourcePermissions(ctx, orgID, dbCommands, ResourceHooks{
+			User:        s.options.OnSetUser,
+			Team:        s.options.OnSetTeam,
+			BuiltInRole: s.options.OnSetBuiltInRole,
+		})
+		return err
 	})
+	return result, err
 }
 
 func (s *Service) MapActions(permission accesscontrol.ResourcePermission) string {
@@ -275,6 +304,112 @@ func (s *Service) DeleteResourcePermissions(ctx context.Context, orgID int64, re
 	})
 }
 
+// withChangeEvent runs fn in a transaction and, if fn modified the managed permissions of the resource,
+// calls OnChange within the same transaction and, when PublishChanges is enabled, publishes an
+// events.ResourcePermissionsChanged event once the transaction is committed.
+func (s *Service) withChangeEvent(ctx context.Context, orgID int64, resourceID string, fn func(ctx context.Context) error) error {
+	if !s.options.PublishChanges && s.options.OnChange == nil {
+		return fn(ctx)
+	}
+
+	return s.sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.managedPermissionsSummary(ctx, orgID, resourceID)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(ctx); err != nil {
+			return err
+		}
+
+		after, err := s.managedPermissionsSummary(ctx, orgID, resourceID)
+		if err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(before, after) {
+			return nil
+		}
+
+		evt := &events.ResourcePermissionsChanged{
+			Timestamp:  time.Now(),
+			OrgID:      orgID,
+			Resource:   s.options.Resource,
+			ResourceID: resourceID,
+			Before:     before,
+			After:      after,
+		}
+		if actor, err := appcontext.User(ctx); err == nil {
+			evt.ActorID = actor.UserID
+			evt.ActorLogin = actor.Login
+		}
+
+		if s.options.OnChange != nil {
+			if err := s.options.OnChange(ctx, evt); err != nil {
+				return err
+			}
+		}
+
+		if !s.options.PublishChanges {
+			return nil
+		}
+
+		return s.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			sess.PublishAfterCommit(evt)
+			return nil
+		})
+	})
+}
+
+// managedPermissionsSummary returns the managed permissions of a resource sorted by subject
+func (s *Service) managedPermissionsSummary(ctx context.Context, orgID int64, resourceID string) ([]events.ResourcePermission, error) {
+	// the summary must include all assignees regardless of who made the change
+	summaryUser := accesscontrol.BackgroundUser("resource_permissions_summary", orgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+		{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+		{Action: serviceaccounts.ActionRead, Scope: serviceaccounts.ScopeAll},
+	})
+
+	permissions, err := s.store.GetResourcePermissions(ctx, orgID, GetResourcePermissionsQuery{
+		User:                 summaryUser,
+		Actions:              s.actions,
+		Resource:             s.options.Resource,
+		ResourceID:           resourceID,
+		ResourceAttribute:    s.options.ResourceAttribute,
+		OnlyManaged:          true,
+		EnforceAccessControl: s.license.FeatureEnabled("accesscontrol.enforcement"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := make([]events.ResourcePermission, 0, len(permissions))
+	for _, p := range permissions {
+		if !p.IsManaged {
+			continue
+		}
+
+		var subject string
+		switch {
+		case p.UserId != 0 && p.IsServiceAccount:
+			subject = "serviceaccount:" + p.UserLogin
+		case p.UserId != 0:
+			subject = "user:" + p.UserLogin
+		case p.TeamId != 0:
+			subject = "team:" + p.Team
+		default:
+			subject = "role:" + p.BuiltInRole
+		}
+
+		summary = append(summary, events.ResourcePermission{Subject: subject, UserID: p.UserId, TeamID: p.TeamId, Permission: s.MapActions(p)})
+	}
+
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].Subject < summary[j].Subject
+	})
+	return summary, nil
+}
+
 func (s *Service) mapPermission(permission string) ([]string, error) {
 	if permission == "" {
 		return []string{}, nil
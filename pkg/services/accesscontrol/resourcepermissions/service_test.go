@@ -2,12 +2,14 @@ package resourcepermissions
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
@@ -232,6 +234,72 @@ func TestService_SetPermissions(t *testing.T) {
 	}
 }
 
+func TestService_PublishChanges(t *testing.T) {
+	service, sql, _ := setupTestEnvironment(t, Options{
+		Resource:             "dashboards",
+		ResourceAttribute:    "uid",
+		Assignments:          Assignments{BuiltInRoles: true},
+		PermissionsToActions: map[string][]string{"View": {"dashboards:read"}, "Edit": {"dashboards:read", "dashboards:write"}},
+		PublishChanges:       true,
+	})
+
+	var published []*events.ResourcePermissionsChanged
+	sql.Bus().AddEventListener(func(ctx context.Context, evt *events.ResourcePermissionsChanged) error {
+		published = append(published, evt)
+		return nil
+	})
+
+	_, err := service.SetBuiltInRolePermission(context.Background(), 1, "Viewer", "abc", "View")
+	require.NoError(t, err)
+	_, err = service.SetBuiltInRolePermission(context.Background(), 1, "Viewer", "abc", "Edit")
+	require.NoError(t, err)
+	// setting the same permission again is not a change
+	_, err = service.SetBuiltInRolePermission(context.Background(), 1, "Viewer", "abc", "Edit")
+	require.NoError(t, err)
+
+	require.Len(t, published, 2)
+	assert.Empty(t, published[0].Before)
+	assert.Equal(t, []events.ResourcePermission{{Subject: "role:Viewer", Permission: "View"}}, published[0].After)
+	assert.Equal(t, published[0].After, published[1].Before)
+	assert.Equal(t, []events.ResourcePermission{{Subject: "role:Viewer", Permission: "Edit"}}, published[1].After)
+	assert.Equal(t, "dashboards", published[1].Resource)
+	assert.Equal(t, "abc", published[1].ResourceID)
+}
+
+func TestService_OnChange(t *testing.T) {
+	var changes []*events.ResourcePermissionsChanged
+	fail := false
+	service, _, _ := setupTestEnvironment(t, Options{
+		Resource:             "dashboards",
+		ResourceAttribute:    "uid",
+		Assignments:          Assignments{BuiltInRoles: true},
+		PermissionsToActions: map[string][]string{"View": {"dashboards:read"}, "Edit": {"dashboards:read", "dashboards:write"}},
+		OnChange: func(ctx context.Context, change *events.ResourcePermissionsChanged) error {
+			if fail {
+				return errors.New("audit failed")
+			}
+			changes = append(changes, change)
+			return nil
+		},
+	})
+	ctx := context.Background()
+
+	_, err := service.SetBuiltInRolePermission(ctx, 1, "Viewer", "abc", "View")
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, []events.ResourcePermission{{Subject: "role:Viewer", Permission: "View"}}, changes[0].After)
+
+	t.Run("should roll the change back when OnChange fails", func(t *testing.T) {
+		fail = true
+		_, err := service.SetBuiltInRolePermission(ctx, 1, "Viewer", "abc", "Edit")
+		require.Error(t, err)
+
+		permissions, err := service.managedPermissionsSummary(ctx, 1, "abc")
+		require.NoError(t, err)
+		assert.Equal(t, []events.ResourcePermission{{Subject: "role:Viewer", Permission: "View"}}, permissions)
+	})
+}
+
 func setupTestEnvironment(t *testing.T, ops Options) (*Service, *sqlstore.SQLStore, team.Service) {
 	t.Helper()
 
@@ -24,6 +24,9 @@ const (
 
 	ManagedRolePrefix = "managed:"
 
+	OrgTemplateRolePrefix    = "orgtemplate:"
+	OrgTemplateRoleUIDPrefix = "orgtemplate_"
+
 	PluginRolePrefix = "plugins:"
 
 	BasicRoleNoneUID  = "basic_none"
@@ -0,0 +1,340 @@
+package accessrequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/usernotification"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// KindAccessRequested and KindAccessReviewed are the kinds of the in-app notifications sent to the owners
+	// of the dashboard when access is requested, and to the requester when the request is reviewed
+	KindAccessRequested = "access.requested"
+	KindAccessReviewed  = "access.reviewed"
+
+	emailTemplate = "access_requested"
+	// ownerPermission is the permission that makes a user an owner of a dashboard
+	ownerPermission = "Admin"
+)
+
+// Service lets the users who aren't allowed to view a dashboard request access to it. The owners of the
+// dashboard are notified, by email and in Grafana, and approving a request grants the chosen permission
+// to the user with the dashboard permissions service.
+type Service struct {
+	cfg                  *setting.Cfg
+	store                db.DB
+	accessControl        accesscontrol.AccessControl
+	dashboardService     dashboards.DashboardService
+	dashboardPermissions accesscontrol.DashboardPermissionsService
+	teamService          team.Service
+	notifications        notifications.Service
+	userNotifications    usernotification.Service
+	log                  log.Logger
+	now                  func() time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl accesscontrol.AccessControl,
+	dashboardService dashboards.DashboardService, dashboardPermissions accesscontrol.DashboardPermissionsService, teamService team.Service,
+	notificationService notifications.Service, userNotifications usernotification.Service) *Service {
+	s := &Service{
+		cfg:                  cfg,
+		store:                sqlStore,
+		accessControl:        accessControl,
+		dashboardService:     dashboardService,
+		dashboardPermissions: dashboardPermissions,
+		teamService:          teamService,
+		notifications:        notificationService,
+		userNotifications:    userNotifications,
+		log:                  log.New("access-request"),
+		now:                  time.Now,
+	}
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+// Create stores the request of the user and notifies the owners of the dashboard. The user must not be
+// able to view the dashboard, and can only have one pending request for it.
+func (s *Service) Create(ctx context.Context, cmd *CreateRequestCommand) (*AccessRequest, error) {
+	userID, err := identity.UserIdentifier(cmd.User.GetNamespacedID())
+	if err != nil {
+		return nil, err
+	}
+	if len(cmd.Reason) > maxReasonLength {
+		return nil, ErrReasonTooLong.Errorf("the reason has %d characters, the maximum is %d", len(cmd.Reason), maxReasonLength)
+	}
+	orgID := cmd.User.GetOrgID()
+	dash, err := s.getDashboard(ctx, orgID, cmd.DashboardUID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.accessControl.Evaluate(ctx, cmd.User, accesscontrol.EvalPermission(dashboards.ActionDashboardsRead,
+		dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dash.UID)))
+	if err != nil {
+		return nil, err
+	}
+	if canView {
+		return nil, ErrAlreadyHasAccess.Errorf("user %d can view dashboard %s", userID, dash.UID)
+	}
+
+	req := &AccessRequest{
+		OrgID:        orgID,
+		DashboardUID: dash.UID,
+		UserID:       userID,
+		UserLogin:    cmd.User.GetLogin(),
+		Reason:       cmd.Reason,
+		Status:       StatusPending,
+		Created:      s.now(),
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		pending, err := sess.Where("org_id = ? AND dashboard_uid = ? AND user_id = ? AND status = ?",
+			req.OrgID, req.DashboardUID, req.UserID, StatusPending).Exist(&AccessRequest{})
+		if err != nil {
+			return err
+		}
+		if pending {
+			return ErrPendingRequestExists.Errorf("user %d has a pending request for dashboard %s", req.UserID, req.DashboardUID)
+		}
+		_, err = sess.Insert(req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.FromContext(ctx).Info("Dashboard access requested", "id", req.ID, "dashboardUid", req.DashboardUID, "userId", req.UserID)
+	s.notifyOwners(ctx, dash, req)
+	return req, nil
+}
+
+func (s *Service) Search(ctx context.Context, query *SearchRequestsQuery) ([]*AccessRequest, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	offset := 0
+	if query.Page > 1 {
+		offset = (query.Page - 1) * limit
+	}
+
+	result := make([]*AccessRequest, 0)
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id = ?", query.OrgID)
+		if query.DashboardUID != "" {
+			sess.And("dashboard_uid = ?", query.DashboardUID)
+		}
+		if query.UserID != 0 {
+			sess.And("user_id = ?", query.UserID)
+		}
+		if query.Status != "" {
+			sess.And("status = ?", query.Status)
+		}
+		return sess.Desc("created", "id").Limit(limit, offset).Find(&result)
+	})
+	return result, err
+}
+
+// Review approves or denies a pending request. Approving it grants the permission to the user, the
+// request is only marked as approved if the permission is granted.
+func (s *Service) Review(ctx context.Context, cmd *ReviewRequestCommand) (*AccessRequest, error) {
+	if cmd.Approve {
+		switch cmd.Permission {
+		case "View", "Edit", "Admin":
+		default:
+			return nil, ErrInvalidPermission.Errorf("invalid permission %q", cmd.Permission)
+		}
+	}
+
+	var req AccessRequest
+	err := s.store.InTransaction(ctx, func(ctx context.Context) error {
+		return s.store.WithDbSession(ctx, func(sess *db.Session) error {
+			found, err := sess.Where("id = ? AND org_id = ? AND dashboard_uid = ?", cmd.ID, cmd.OrgID, cmd.DashboardUID).Get(&req)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return ErrRequestNotFound.Errorf("access request %d not found", cmd.ID)
+			}
+			if req.Status != StatusPending {
+				return ErrAlreadyReviewed.Errorf("access request %d is %s", cmd.ID, req.Status)
+			}
+
+			reviewed := s.now()
+			req.Status, req.Permission, req.Reviewed = StatusDenied, "", &reviewed
+			if cmd.Approve {
+				req.Status, req.Permission = StatusApproved, cmd.Permission
+			}
+			req.ReviewerID, req.ReviewerLogin = cmd.ReviewerID, cmd.ReviewerLogin
+			// the status condition keeps concurrent reviews from both applying
+			affected, err := sess.Where("id = ? AND status = ?", req.ID, StatusPending).
+				Cols("status", "permission", "reviewer_id", "reviewer_login", "reviewed").Update(&req)
+			if err != nil {
+				return err
+			}
+			if affected == 0 {
+				return ErrAlreadyReviewed.Errorf("access request %d was reviewed concurrently", cmd.ID)
+			}
+
+			if !cmd.Approve {
+				return nil
+			}
+			_, err = s.dashboardPermissions.SetUserPermission(ctx, req.OrgID, accesscontrol.User{ID: req.UserID}, req.DashboardUID, req.Permission)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.FromContext(ctx).Info("Dashboard access request reviewed", "id", req.ID, "dashboardUid", req.DashboardUID, "userId", req.UserID,
+		"status", req.Status, "permission", req.Permission, "reviewerId", req.ReviewerID)
+	s.notifyRequester(ctx, &req)
+	return &req, nil
+}
+
+func (s *Service) getDashboard(ctx context.Context, orgID int64, uid string) (*dashboards.Dashboard, error) {
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: orgID, UID: uid})
+	if errors.Is(err, dashboards.ErrDashboardNotFound) || (err == nil && dash.IsFolder) {
+		return nil, ErrDashboardNotFound.Errorf("dashboard %s not found", uid)
+	}
+	return dash, err
+}
+
+// notifyOwners notifies the owners of the dashboard, a request is kept when they can't be notified
+func (s *Service) notifyOwners(ctx context.Context, dash *dashboards.Dashboard, req *AccessRequest) {
+	owners, err := s.owners(ctx, req.OrgID, dash.UID)
+	if err != nil {
+		s.log.FromContext(ctx).Error("Failed to get the owners to notify of the access request", "dashboardUid", dash.UID, "error", err)
+		return
+	}
+	if len(owners) == 0 {
+		s.log.FromContext(ctx).Warn("No owner to notify of the access request", "dashboardUid", dash.UID)
+		return
+	}
+
+	title := fmt.Sprintf("%s requested access to the dashboard %s", req.UserLogin, dash.Title)
+	// the owners review the request in the permissions of the dashboard
+	path := strings.TrimPrefix(dashboards.GetDashboardURL(dash.UID, dash.Slug), s.cfg.AppSubURL) + "?editview=permissions"
+	text := "No reason given"
+	if req.Reason != "" {
+		text = fmt.Sprintf("Reason: %s", req.Reason)
+	}
+
+	for _, o := range owners {
+		if err := s.userNotifications.Create(ctx, &usernotification.CreateNotificationCommand{
+			OrgID:  req.OrgID,
+			UserID: o.id,
+			Kind:   KindAccessRequested,
+			Title:  title,
+			Text:   text,
+			URL:    path,
+		}); err != nil {
+			s.log.FromContext(ctx).Error("Failed to create access request notification", "dashboardUid", dash.UID, "userId", o.id, "error", err)
+		}
+
+		if o.email == "" {
+			continue
+		}
+		if err := s.notifications.SendEmailCommandHandler(ctx, &notifications.SendEmailCommand{
+			To:       []string{o.email},
+			Template: emailTemplate,
+			Subject:  title,
+			Data: map[string]any{
+				"Name":      o.login,
+				"Requester": req.UserLogin,
+				"Title":     dash.Title,
+				"Reason":    req.Reason,
+				"URL":       strings.TrimSuffix(s.cfg.AppURL, "/") + path,
+			},
+		}); err != nil {
+			s.log.FromContext(ctx).Error("Failed to send access request email", "dashboardUid", dash.UID, "userId", o.id, "error", err)
+		}
+	}
+}
+
+func (s *Service) notifyRequester(ctx context.Context, req *AccessRequest) {
+	dash, err := s.getDashboard(ctx, req.OrgID, req.DashboardUID)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to get the dashboard of the reviewed access request", "dashboardUid", req.DashboardUID, "error", err)
+		return
+	}
+
+	title := fmt.Sprintf("Your request to access the dashboard %s was denied", dash.Title)
+	path := ""
+	if req.Status == StatusApproved {
+		title = fmt.Sprintf("Your request to access the dashboard %s was approved", dash.Title)
+		path = strings.TrimPrefix(dashboards.GetDashboardURL(dash.UID, dash.Slug), s.cfg.AppSubURL)
+	}
+	if err := s.userNotifications.Create(ctx, &usernotification.CreateNotificationCommand{
+		OrgID:  req.OrgID,
+		UserID: req.UserID,
+		Kind:   KindAccessReviewed,
+		Title:  title,
+		Text:   fmt.Sprintf("Reviewed by %s", req.ReviewerLogin),
+		URL:    path,
+	}); err != nil {
+		s.log.FromContext(ctx).Error("Failed to create access review notification", "dashboardUid", req.DashboardUID, "userId", req.UserID, "error", err)
+	}
+}
+
+// owner is a user notified of the access requests of a dashboard
+type owner struct {
+	id    int64
+	login string
+	email string
+}
+
+// owners returns the users with the Admin permission on the dashboard, directly, through a team or
+// inherited from a folder
+func (s *Service) owners(ctx context.Context, orgID int64, dashboardUID string) ([]owner, error) {
+	lister := accesscontrol.BackgroundUser("access_request", orgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsPermissionsRead, Scope: dashboards.ScopeDashboardsAll},
+		{Action: dashboards.ActionFoldersPermissionsRead, Scope: dashboards.ScopeFoldersAll},
+		{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+		{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+	})
+	permissions, err := s.dashboardPermissions.GetPermissions(ctx, lister, dashboardUID)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := map[int64]owner{}
+	for _, p := range permissions {
+		if s.dashboardPermissions.MapActions(p) != ownerPermission || p.IsServiceAccount {
+			continue
+		}
+		if p.UserId != 0 {
+			owners[p.UserId] = owner{id: p.UserId, login: p.UserLogin, email: p.UserEmail}
+		}
+		if p.TeamId != 0 {
+			members, err := s.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{OrgID: orgID, TeamID: p.TeamId, SignedInUser: lister})
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range members {
+				owners[m.UserID] = owner{id: m.UserID, login: m.Login, email: m.Email}
+			}
+		}
+	}
+
+	result := make([]owner, 0, len(owners))
+	for _, o := range owners {
+		result = append(result, o)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].id < result[j].id })
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package accessrequest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	accesscontrolmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/usernotification/usernotificationtest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationAccessRequests(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	var emails []*notifications.SendEmailCommand
+	notificationService := &notifications.NotificationServiceMock{
+		EmailHandler: func(ctx context.Context, cmd *notifications.SendEmailCommand) error {
+			emails = append(emails, cmd)
+			return nil
+		},
+	}
+	inApp := &usernotificationtest.FakeService{}
+
+	dashSvc := dashboards.NewFakeDashboardService(t)
+	dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "abc", Slug: "prod", Title: "Prod"}, nil).Maybe()
+
+	permissions := accesscontrolmock.NewMockedPermissionsService()
+	permissions.On("GetPermissions", mock.Anything, mock.Anything, "abc").Return([]accesscontrol.ResourcePermission{
+		{UserId: 2, UserLogin: "owner", UserEmail: "owner@example.com"},
+		{TeamId: 1},
+	}, nil).Maybe()
+	permissions.On("MapActions", mock.Anything).Return("Admin").Maybe()
+	permissions.On("SetUserPermission", mock.Anything, int64(1), accesscontrol.User{ID: 10}, "abc", "Edit").
+		Return(&accesscontrol.ResourcePermission{}, nil).Once()
+
+	teams := &teamtest.FakeService{ExpectedMembers: []*team.TeamMemberDTO{{UserID: 3, Login: "member"}}}
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = "http://localhost:3000/"
+	s := ProvideService(cfg, db.InitTestDB(t), routing.NewRouteRegister(), actest.FakeAccessControl{}, dashSvc, permissions, teams,
+		notificationService, inApp)
+	now := time.Now().Truncate(time.Second)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+	requester := &user.SignedInUser{UserID: 10, OrgID: 1, Login: "requester"}
+
+	req, err := s.Create(ctx, &CreateRequestCommand{DashboardUID: "abc", Reason: "on call", User: requester})
+	require.NoError(t, err)
+
+	t.Run("should notify the owners of the dashboard", func(t *testing.T) {
+		require.Len(t, inApp.Created, 2)
+		assert.Equal(t, []int64{2, 3}, []int64{inApp.Created[0].UserID, inApp.Created[1].UserID})
+		assert.Equal(t, "requester requested access to the dashboard Prod", inApp.Created[0].Title)
+		assert.Equal(t, "/d/abc/prod?editview=permissions", inApp.Created[0].URL)
+
+		require.Len(t, emails, 1)
+		assert.Equal(t, []string{"owner@example.com"}, emails[0].To)
+		assert.Equal(t, emailTemplate, emails[0].Template)
+		assert.Equal(t, "on call", emails[0].Data["Reason"])
+	})
+
+	t.Run("should reject a second pending request", func(t *testing.T) {
+		_, err := s.Create(ctx, &CreateRequestCommand{DashboardUID: "abc", User: requester})
+		assert.ErrorIs(t, err, ErrPendingRequestExists)
+	})
+
+	t.Run("should reject the requests of the users who can view the dashboard", func(t *testing.T) {
+		viewer := ProvideService(cfg, s.store, routing.NewRouteRegister(), actest.FakeAccessControl{ExpectedEvaluate: true}, dashSvc,
+			permissions, teams, notificationService, inApp)
+		_, err := viewer.Create(ctx, &CreateRequestCommand{DashboardUID: "abc", User: &user.SignedInUser{UserID: 11, OrgID: 1}})
+		assert.ErrorIs(t, err, ErrAlreadyHasAccess)
+	})
+
+	t.Run("should grant the permission when the request is approved", func(t *testing.T) {
+		inApp.Created = nil
+		_, err := s.Review(ctx, &ReviewRequestCommand{OrgID: 1, DashboardUID: "abc", ID: req.ID, Approve: true, Permission: "Owner"})
+		assert.ErrorIs(t, err, ErrInvalidPermission)
+
+		approved, err := s.Review(ctx, &ReviewRequestCommand{
+			OrgID: 1, DashboardUID: "abc", ID: req.ID, Approve: true, Permission: "Edit", ReviewerID: 2, ReviewerLogin: "owner",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, StatusApproved, approved.Status)
+		permissions.AssertExpectations(t)
+
+		require.Len(t, inApp.Created, 1)
+		assert.Equal(t, int64(10), inApp.Created[0].UserID)
+		assert.Equal(t, "Your request to access the dashboard Prod was approved", inApp.Created[0].Title)
+
+		_, err = s.Review(ctx, &ReviewRequestCommand{OrgID: 1, DashboardUID: "abc", ID: req.ID})
+		assert.ErrorIs(t, err, ErrAlreadyReviewed)
+	})
+
+	t.Run("should keep the reviewed requests", func(t *testing.T) {
+		result, err := s.Search(ctx, &SearchRequestsQuery{OrgID: 1, DashboardUID: "abc"})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "Edit", result[0].Permission)
+		assert.Equal(t, "owner", result[0].ReviewerLogin)
+		require.NotNil(t, result[0].Reviewed)
+
+		result, err = s.Search(ctx, &SearchRequestsQuery{OrgID: 1, UserID: 10, Status: StatusPending})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("should not grant a permission when the request is denied", func(t *testing.T) {
+		req, err := s.Create(ctx, &CreateRequestCommand{DashboardUID: "abc", User: requester})
+		require.NoError(t, err)
+		denied, err := s.Review(ctx, &ReviewRequestCommand{OrgID: 1, DashboardUID: "abc", ID: req.ID, ReviewerID: 2})
+		require.NoError(t, err)
+		assert.Equal(t, StatusDenied, denied.Status)
+		assert.Empty(t, denied.Permission)
+		permissions.AssertNumberOfCalls(t, "SetUserPermission", 1)
+	})
+
+	t.Run("should return not found for the requests of other dashboards", func(t *testing.T) {
+		_, err := s.Review(ctx, &ReviewRequestCommand{OrgID: 1, DashboardUID: "other", ID: req.ID})
+		assert.ErrorIs(t, err, ErrRequestNotFound)
+		var errutilErr errutil.Error
+		assert.ErrorAs(t, err, &errutilErr)
+	})
+}
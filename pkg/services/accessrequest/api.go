@@ -0,0 +1,253 @@
+package accessrequest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(s.accessControl)
+	dashboardScope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(accesscontrol.Parameter(":uid"))
+
+	routeRegister.Group("/api/dashboards/uid/:uid/access-requests", func(r routing.RouteRegister) {
+		r.Post("/", routing.Wrap(s.createHandler))
+		r.Get("/", authorize(accesscontrol.EvalPermission(dashboards.ActionDashboardsPermissionsRead, dashboardScope)), routing.Wrap(s.searchHandler))
+		r.Post("/:id/approve", authorize(accesscontrol.EvalPermission(dashboards.ActionDashboardsPermissionsWrite, dashboardScope)), routing.Wrap(s.approveHandler))
+		r.Post("/:id/deny", authorize(accesscontrol.EvalPermission(dashboards.ActionDashboardsPermissionsWrite, dashboardScope)), routing.Wrap(s.denyHandler))
+	}, middleware.ReqSignedInNoAnonymous)
+	routeRegister.Get("/api/user/access-requests", middleware.ReqSignedInNoAnonymous, routing.Wrap(s.userSearchHandler))
+}
+
+// swagger:route POST /dashboards/uid/{uid}/access-requests dashboards createDashboardAccessRequest
+//
+// Request access to a dashboard the signed in user can't view.
+//
+// The owners of the dashboard, the users with the Admin permission, are notified of the request.
+//
+// Responses:
+// 200: accessRequestResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) createHandler(c *contextmodel.ReqContext) response.Response {
+	body := CreateAccessRequestBody{}
+	if err := web.Bind(c.Req, &body); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	req, err := s.Create(c.Req.Context(), &CreateRequestCommand{
+		DashboardUID: web.Params(c.Req)[":uid"],
+		Reason:       body.Reason,
+		User:         c.SignedInUser,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to request access", err)
+	}
+	return response.JSON(http.StatusOK, req)
+}
+
+// swagger:route GET /dashboards/uid/{uid}/access-requests dashboards getDashboardAccessRequests
+//
+// Get the access requests of a dashboard, most recent first.
+//
+// The reviewed requests are kept with their reviewer and the granted permission.
+//
+// Responses:
+// 200: accessRequestsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) searchHandler(c *contextmodel.ReqContext) response.Response {
+	result, err := s.Search(c.Req.Context(), &SearchRequestsQuery{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		DashboardUID: web.Params(c.Req)[":uid"],
+		Status:       Status(c.Query("status")),
+		Limit:        c.QueryInt("limit"),
+		Page:         c.QueryInt("page"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get access requests", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /user/access-requests signed_in_user getUserAccessRequests
+//
+// Get the access requests of the signed in user, most recent first.
+//
+// Responses:
+// 200: accessRequestsResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *Service) userSearchHandler(c *contextmodel.ReqContext) response.Response {
+	result, err := s.Search(c.Req.Context(), &SearchRequestsQuery{
+		OrgID:  c.SignedInUser.GetOrgID(),
+		UserID: c.SignedInUser.UserID,
+		Status: Status(c.Query("status")),
+		Limit:  c.QueryInt("limit"),
+		Page:   c.QueryInt("page"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get access requests", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /dashboards/uid/{uid}/access-requests/{access_request_id}/approve dashboards approveDashboardAccessRequest
+//
+// Approve an access request, granting the permission to the user who requested access.
+//
+// Responses:
+// 200: accessRequestResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) approveHandler(c *contextmodel.ReqContext) response.Response {
+	body := ApproveAccessRequestBody{}
+	if err := web.Bind(c.Req, &body); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return s.review(c, true, body.Permission)
+}
+
+// swagger:route POST /dashboards/uid/{uid}/access-requests/{access_request_id}/deny dashboards denyDashboardAccessRequest
+//
+// Deny an access request.
+//
+// Responses:
+// 200: accessRequestResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) denyHandler(c *contextmodel.ReqContext) response.Response {
+	return s.review(c, false, "")
+}
+
+func (s *Service) review(c *contextmodel.ReqContext, approve bool, permission string) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+	req, err := s.Review(c.Req.Context(), &ReviewRequestCommand{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		DashboardUID:  web.Params(c.Req)[":uid"],
+		ID:            id,
+		Approve:       approve,
+		Permission:    permission,
+		ReviewerID:    c.SignedInUser.UserID,
+		ReviewerLogin: c.SignedInUser.GetLogin(),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to review access request", err)
+	}
+	return response.JSON(http.StatusOK, req)
+}
+
+type CreateAccessRequestBody struct {
+	// Reason is shown to the owners of the dashboard
+	Reason string `json:"reason"`
+}
+
+type ApproveAccessRequestBody struct {
+	// Permission granted to the user, View, Edit or Admin
+	Permission string `json:"permission"`
+}
+
+// swagger:parameters createDashboardAccessRequest
+type CreateDashboardAccessRequestParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body CreateAccessRequestBody
+}
+
+// swagger:parameters getDashboardAccessRequests
+type GetDashboardAccessRequestsParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// Only return the requests with the status, pending, approved or denied
+	// in:query
+	// required:false
+	Status Status `json:"status"`
+	// Maximum number of requests to return
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+	// Page index, starting at 1
+	// in:query
+	// required:false
+	// default:1
+	Page int `json:"page"`
+}
+
+// swagger:parameters getUserAccessRequests
+type GetUserAccessRequestsParams struct {
+	// Only return the requests with the status, pending, approved or denied
+	// in:query
+	// required:false
+	Status Status `json:"status"`
+	// Maximum number of requests to return
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+	// Page index, starting at 1
+	// in:query
+	// required:false
+	// default:1
+	Page int `json:"page"`
+}
+
+// swagger:parameters approveDashboardAccessRequest
+type ApproveDashboardAccessRequestParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:path
+	// required:true
+	AccessRequestID int64 `json:"access_request_id"`
+	// in:body
+	// required:true
+	Body ApproveAccessRequestBody
+}
+
+// swagger:parameters denyDashboardAccessRequest
+type DenyDashboardAccessRequestParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:path
+	// required:true
+	AccessRequestID int64 `json:"access_request_id"`
+}
+
+// swagger:response accessRequestResponse
+type AccessRequestResponse struct {
+	// in:body
+	Body *AccessRequest `json:"body"`
+}
+
+// swagger:response accessRequestsResponse
+type AccessRequestsResponse struct {
+	// in:body
+	Body []*AccessRequest `json:"body"`
+}
@@ -0,0 +1,79 @@
+package accessrequest
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrRequestNotFound      = errutil.NotFound("accessrequest.not-found", errutil.WithPublicMessage("Access request not found"))
+	ErrDashboardNotFound    = errutil.NotFound("accessrequest.dashboard-not-found", errutil.WithPublicMessage("Dashboard not found"))
+	ErrAlreadyHasAccess     = errutil.BadRequest("accessrequest.has-access", errutil.WithPublicMessage("You can already view the dashboard"))
+	ErrInvalidPermission    = errutil.BadRequest("accessrequest.invalid-permission", errutil.WithPublicMessage("The permission must be View, Edit or Admin"))
+	ErrReasonTooLong        = errutil.BadRequest("accessrequest.reason-too-long", errutil.WithPublicMessage("The reason is too long"))
+	ErrPendingRequestExists = errutil.Conflict("accessrequest.pending", errutil.WithPublicMessage("You already requested access to the dashboard"))
+	ErrAlreadyReviewed      = errutil.Conflict("accessrequest.reviewed", errutil.WithPublicMessage("The access request was already reviewed"))
+)
+
+// maxReasonLength is the maximum length of the reason of a request
+const maxReasonLength = 500
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusDenied   Status = "denied"
+)
+
+// AccessRequest is the request of a user to view a dashboard they aren't allowed to view. It's kept once
+// reviewed, with the reviewer and the granted permission, as the history of the access given to the dashboard.
+type AccessRequest struct {
+	ID           int64  `json:"id" xorm:"pk autoincr 'id'"`
+	OrgID        int64  `json:"orgId" xorm:"org_id"`
+	DashboardUID string `json:"dashboardUid" xorm:"dashboard_uid"`
+	UserID       int64  `json:"userId" xorm:"user_id"`
+	UserLogin    string `json:"userLogin"`
+	Reason       string `json:"reason"`
+	Status       Status `json:"status"`
+	// Permission is the permission granted to the user when the request is approved
+	Permission    string     `json:"permission,omitempty"`
+	ReviewerID    int64      `json:"reviewerId,omitempty" xorm:"reviewer_id"`
+	ReviewerLogin string     `json:"reviewerLogin,omitempty"`
+	Created       time.Time  `json:"created"`
+	Reviewed      *time.Time `json:"reviewed,omitempty"`
+}
+
+func (AccessRequest) TableName() string {
+	return "access_request"
+}
+
+type CreateRequestCommand struct {
+	DashboardUID string
+	Reason       string
+	// User requesting access, a user of the organization of the dashboard
+	User identity.Requester
+}
+
+type SearchRequestsQuery struct {
+	OrgID int64
+	// DashboardUID and UserID filter the requests when set
+	DashboardUID string
+	UserID       int64
+	Status       Status
+	Limit        int
+	Page         int
+}
+
+type ReviewRequestCommand struct {
+	OrgID        int64
+	DashboardUID string
+	ID           int64
+	Approve      bool
+	// Permission granted when the request is approved, View, Edit or Admin
+	Permission    string
+	ReviewerID    int64
+	ReviewerLogin string
+}
@@ -220,6 +220,7 @@ func (n *notificationService) renderAndUploadImage(evalCtx *EvalContext, timeout
 		Width:           1000,
 		Height:          500,
 		ConcurrentLimit: n.cfg.AlertingRenderLimit,
+		Priority:        rendering.PriorityAlerting,
 		Theme:           models.ThemeDark,
 	}
 
@@ -383,6 +383,14 @@ func (s *testRenderService) Version() string {
 	return ""
 }
 
+func (s *testRenderService) GetSigningKeys(_ context.Context) ([]rendering.SigningKey, error) {
+	return nil, nil
+}
+
+func (s *testRenderService) RotateSigningKey(_ context.Context) ([]rendering.SigningKey, error) {
+	return nil, nil
+}
+
 func (s *testRenderService) CreateRenderingSession(ctx context.Context, authOpts rendering.AuthOpts, sessionOpts rendering.SessionOpts) (rendering.Session, error) {
 	return nil, nil
 }
@@ -25,32 +25,39 @@ const (
 	apiAnnotationType       = "alert_id = 0 AND dashboard_id = 0"
 )
 
-// Run deletes old annotations created by alert rules, API
+// Run rolls up and deletes old annotations created by alert rules, API
 // requests and human made in the UI. It subsequently deletes orphaned rows
 // from the annotation_tag table. Cleanup actions are performed in batches
 // so that no query takes too long to complete.
 //
-// Returns the number of annotation and annotation_tag rows deleted. If an
-// error occurs, it returns the number of rows affected so far.
+// Returns the number of annotation and annotation_tag rows deleted, including
+// the annotations replaced by rollups. If an error occurs, it returns the number
+// of rows affected so far.
 func (cs *CleanupServiceImpl) Run(ctx context.Context, cfg *setting.Cfg) (int64, int64, error) {
 	var totalCleanedAnnotations int64
-	affected, err := cs.store.CleanAnnotations(ctx, cfg.AlertingAnnotationCleanupSetting, alertAnnotationType)
-	totalCleanedAnnotations += affected
-	if err != nil {
-		return totalCleanedAnnotations, 0, err
-	}
+	for _, cleanup := range []struct {
+		settings       setting.AnnotationCleanupSettings
+		annotationType string
+	}{
+		{cfg.AlertingAnnotationCleanupSetting, alertAnnotationType},
+		{cfg.APIAnnotationCleanupSettings, apiAnnotationType},
+		{cfg.DashboardAnnotationCleanupSettings, dashboardAnnotationType},
+	} {
+		affected, err := cs.store.RollupAnnotations(ctx, cleanup.settings, cleanup.annotationType)
+		totalCleanedAnnotations += affected
+		if err != nil {
+			return totalCleanedAnnotations, 0, err
+		}
 
-	affected, err = cs.store.CleanAnnotations(ctx, cfg.APIAnnotationCleanupSettings, apiAnnotationType)
-	totalCleanedAnnotations += affected
-	if err != nil {
-		return totalCleanedAnnotations, 0, err
+		affected, err = cs.store.CleanAnnotations(ctx, cleanup.settings, cleanup.annotationType)
+		totalCleanedAnnotations += affected
+		if err != nil {
+			return totalCleanedAnnotations, 0, err
+		}
 	}
 
-	affected, err = cs.store.CleanAnnotations(ctx, cfg.DashboardAnnotationCleanupSettings, dashboardAnnotationType)
-	totalCleanedAnnotations += affected
-	if err != nil {
-		return totalCleanedAnnotations, 0, err
-	}
+	var affected int64
+	var err error
 	if totalCleanedAnnotations > 0 {
 		affected, err = cs.store.CleanOrphanedAnnotationTags(ctx)
 	}
@@ -12,6 +12,8 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotations/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -205,6 +207,100 @@ func TestIntegrationOldAnnotationsAreDeletedFirst(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestIntegrationAnnotationRollup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	fakeSQL := db.InitTestDB(t)
+	t.Cleanup(func() {
+		err := fakeSQL.WithDbSession(context.Background(), func(session *db.Session) error {
+			_, deleteAnnotationErr := session.Exec("DELETE FROM annotation")
+			_, deleteRollupErr := session.Exec("DELETE FROM annotation_rollup")
+			return errors.Join(deleteAnnotationErr, deleteRollupErr)
+		})
+		assert.NoError(t, err)
+	})
+
+	day := time.Now().UTC().AddDate(0, 0, -100).Truncate(24 * time.Hour)
+	newAnnotation := func(epoch time.Time, tags ...string) annotations.Item {
+		return annotations.Item{OrgID: 1, DashboardID: 1, PanelID: 2, Epoch: epoch.UnixMilli(), EpochEnd: epoch.UnixMilli(), Tags: tags, Created: epoch.UnixMilli()}
+	}
+	err := fakeSQL.WithDbSession(context.Background(), func(sess *db.Session) error {
+		for _, item := range []annotations.Item{
+			newAnnotation(day.Add(time.Hour), "deploy", "env:prod"),
+			newAnnotation(day.Add(2*time.Hour), "deploy"),
+			newAnnotation(day.AddDate(0, 0, 1), "deploy"),
+			newAnnotation(time.Now(), "deploy"),
+		} {
+			if _, err := sess.Table("annotation").Insert(&item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	cfg := setting.NewCfg()
+	cfg.AnnotationCleanupJobBatchSize = 2
+	store := NewXormStore(cfg, log.New("annotation.test"), fakeSQL, nil)
+	settings := setting.AnnotationCleanupSettings{RollupAfter: 90 * 24 * time.Hour}
+
+	affected, err := store.RollupAnnotations(context.Background(), settings, dashboardAnnotationType)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), affected)
+	assertAnnotationCount(t, fakeSQL, dashboardAnnotationType, 1)
+
+	query := &annotations.ItemQuery{OrgID: 1, SignedInUser: &user.SignedInUser{OrgID: 1}, Limit: 100}
+	resources := &accesscontrol.AccessResources{CanAccessDashAnnotations: true, Dashboards: map[string]int64{"dash": 1}}
+
+	t.Run("should merge the rollups into the annotations", func(t *testing.T) {
+		items, err := store.Get(context.Background(), query, resources)
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		assert.NotZero(t, items[0].ID)
+
+		rollup := items[2]
+		assert.Equal(t, day.UnixMilli(), rollup.Time)
+		assert.Equal(t, "2 annotations", rollup.Text)
+		assert.Equal(t, []string{"deploy", "env:prod"}, rollup.Tags)
+		assert.Equal(t, int64(1), rollup.Data.GetPath("rollup", "tags", "env:prod").MustInt64())
+	})
+
+	t.Run("should filter the rollups by tags", func(t *testing.T) {
+		query := *query
+		query.Tags = []string{"env:prod"}
+		items, err := store.Get(context.Background(), &query, resources)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "2 annotations", items[0].Text)
+	})
+
+	t.Run("should add the annotations of a day that was already rolled up", func(t *testing.T) {
+		err := fakeSQL.WithDbSession(context.Background(), func(sess *db.Session) error {
+			item := newAnnotation(day.Add(3 * time.Hour))
+			_, err := sess.Table("annotation").Insert(&item)
+			return err
+		})
+		require.NoError(t, err)
+
+		_, err = store.RollupAnnotations(context.Background(), settings, dashboardAnnotationType)
+		require.NoError(t, err)
+		items, err := store.Get(context.Background(), query, resources)
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		assert.Equal(t, "3 annotations", items[2].Text)
+	})
+
+	t.Run("should delete the rollups older than the rollup max age", func(t *testing.T) {
+		_, err := store.RollupAnnotations(context.Background(), setting.AnnotationCleanupSettings{RollupAfter: settings.RollupAfter, RollupMaxAge: 99 * 24 * time.Hour}, dashboardAnnotationType)
+		require.NoError(t, err)
+		items, err := store.Get(context.Background(), query, resources)
+		require.NoError(t, err)
+		assert.Len(t, items, 2)
+	})
+}
+
 func assertAnnotationCount(t *testing.T, fakeSQL db.DB, sql string, expectedCount int64) {
 	t.Helper()
 
@@ -0,0 +1,293 @@
+package annotationsimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotations/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/tag"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const rollupInterval = 24 * time.Hour
+
+// annotationRollup is the daily aggregate of the annotations of a dashboard panel,
+// alert or organization that are older than the rollup_after setting.
+type annotationRollup struct {
+	ID          int64 `xorm:"pk autoincr 'id'"`
+	OrgID       int64 `xorm:"org_id"`
+	AlertID     int64 `xorm:"alert_id"`
+	DashboardID int64 `xorm:"dashboard_id"`
+	PanelID     int64 `xorm:"panel_id"`
+	// Epoch is the start of the day in UTC, EpochEnd the end of the day
+	Epoch    int64 `xorm:"epoch"`
+	EpochEnd int64 `xorm:"epoch_end"`
+	Count    int64 `xorm:"count"`
+	// Tags is the JSON object of the number of annotations of each tag
+	Tags    string `xorm:"tags"`
+	Created int64  `xorm:"created"`
+	Updated int64  `xorm:"updated"`
+}
+
+func (r annotationRollup) TableName() string {
+	return "annotation_rollup"
+}
+
+type rollupKey struct {
+	OrgID       int64
+	AlertID     int64
+	DashboardID int64
+	PanelID     int64
+	Epoch       int64
+}
+
+type rollupSource struct {
+	ID          int64    `xorm:"id"`
+	OrgID       int64    `xorm:"org_id"`
+	AlertID     int64    `xorm:"alert_id"`
+	DashboardID int64    `xorm:"dashboard_id"`
+	PanelID     int64    `xorm:"panel_id"`
+	Epoch       int64    `xorm:"epoch"`
+	Tags        []string `xorm:"tags"`
+}
+
+// RollupAnnotations replaces the annotations of the given type older than cfg.RollupAfter by daily rollups,
+// which keep the number of annotations of each tag. Rollups older than cfg.RollupMaxAge are deleted.
+//
+// Returns the number of annotation rows rolled up. If an error occurs, it returns the number of rows
+// affected so far.
+func (r *xormRepositoryImpl) RollupAnnotations(ctx context.Context, cfg setting.AnnotationCleanupSettings, annotationType string) (int64, error) {
+	if cfg.RollupAfter <= 0 {
+		return 0, nil
+	}
+
+	// only whole days are rolled up, so that a rollup isn't updated by the next runs
+	cutoff := timeNow().Add(-cfg.RollupAfter).UTC().Truncate(rollupInterval).UnixMilli()
+	affected, err := untilDoneOrCancelled(ctx, func() (int64, error) {
+		var rows []rollupSource
+		err := r.db.WithDbSession(ctx, func(sess *db.Session) error {
+			sql := fmt.Sprintf(`SELECT id, org_id, alert_id, dashboard_id, panel_id, epoch, tags FROM annotation WHERE %s AND epoch < ? ORDER BY id %s`,
+				annotationType, r.db.GetDialect().Limit(r.cfg.AnnotationCleanupJobBatchSize))
+			return sess.SQL(sql, cutoff).Find(&rows)
+		})
+		if err != nil || len(rows) == 0 {
+			return 0, err
+		}
+
+		var affected int64
+		err = r.db.InTransaction(ctx, func(ctx context.Context) error {
+			if err := r.addToRollups(ctx, rows); err != nil {
+				return err
+			}
+			ids := make([]int64, 0, len(rows))
+			for _, row := range rows {
+				ids = append(ids, row.ID)
+			}
+			var err error
+			affected, err = r.deleteByIDs(ctx, "annotation", ids)
+			return err
+		})
+		return affected, err
+	})
+	if err != nil {
+		return affected, err
+	}
+
+	if cfg.RollupMaxAge > 0 {
+		cutoff := timeNow().Add(-cfg.RollupMaxAge).UnixMilli()
+		err = r.db.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Exec(fmt.Sprintf(`DELETE FROM annotation_rollup WHERE %s AND epoch_end < ?`, annotationType), cutoff)
+			return err
+		})
+	}
+	return affected, err
+}
+
+func (r *xormRepositoryImpl) addToRollups(ctx context.Context, rows []rollupSource) error {
+	counts := map[rollupKey]int64{}
+	tags := map[rollupKey]map[string]int64{}
+	for _, row := range rows {
+		key := rollupKey{
+			OrgID:       row.OrgID,
+			AlertID:     row.AlertID,
+			DashboardID: row.DashboardID,
+			PanelID:     row.PanelID,
+			Epoch:       time.UnixMilli(row.Epoch).UTC().Truncate(rollupInterval).UnixMilli(),
+		}
+		counts[key]++
+		if tags[key] == nil {
+			tags[key] = map[string]int64{}
+		}
+		for _, t := range row.Tags {
+			tags[key][t]++
+		}
+	}
+
+	now := timeNow().UnixMilli()
+	return r.db.WithDbSession(ctx, func(sess *db.Session) error {
+		for key, count := range counts {
+			rollup := annotationRollup{}
+			has, err := sess.Where("org_id = ? AND alert_id = ? AND dashboard_id = ? AND panel_id = ? AND epoch = ?",
+				key.OrgID, key.AlertID, key.DashboardID, key.PanelID, key.Epoch).Get(&rollup)
+			if err != nil {
+				return err
+			}
+
+			rollupTags := map[string]int64{}
+			if has && rollup.Tags != "" {
+				if err := json.Unmarshal([]byte(rollup.Tags), &rollupTags); err != nil {
+					return fmt.Errorf("failed to parse the tags of annotation rollup %d: %w", rollup.ID, err)
+				}
+			}
+			for t, n := range tags[key] {
+				rollupTags[t] += n
+			}
+			encoded, err := json.Marshal(rollupTags)
+			if err != nil {
+				return err
+			}
+
+			if has {
+				rollup.Count += count
+				rollup.Tags = string(encoded)
+				rollup.Updated = now
+				if _, err := sess.ID(rollup.ID).Cols("count", "tags", "updated").Update(&rollup); err != nil {
+					return err
+				}
+				continue
+			}
+
+			rollup = annotationRollup{
+				OrgID:       key.OrgID,
+				AlertID:     key.AlertID,
+				DashboardID: key.DashboardID,
+				PanelID:     key.PanelID,
+				Epoch:       key.Epoch,
+				EpochEnd:    key.Epoch + rollupInterval.Milliseconds() - 1,
+				Count:       count,
+				Tags:        string(encoded),
+				Created:     now,
+				Updated:     now,
+			}
+			if _, err := sess.Insert(&rollup); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// getRollups returns the rollups matching the query as annotations, so that the trends remain visible
+// once the annotations are rolled up. The rollups have no ID, and their data includes the number of
+// annotations of the day and of each tag.
+func (r *xormRepositoryImpl) getRollups(ctx context.Context, query *annotations.ItemQuery, accessResources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, error) {
+	// rollups don't keep the annotations themselves nor their authors
+	if query.AnnotationID != 0 || query.UserID != 0 {
+		return nil, nil
+	}
+
+	sql := strings.Builder{}
+	sql.WriteString(`SELECT * FROM annotation_rollup a WHERE a.org_id = ?`)
+	params := []any{query.OrgID}
+	if query.AlertID != 0 {
+		sql.WriteString(` AND a.alert_id = ?`)
+		params = append(params, query.AlertID)
+	}
+	if query.DashboardID != 0 {
+		sql.WriteString(` AND a.dashboard_id = ?`)
+		params = append(params, query.DashboardID)
+	}
+	if query.PanelID != 0 {
+		sql.WriteString(` AND a.panel_id = ?`)
+		params = append(params, query.PanelID)
+	}
+	if query.From > 0 && query.To > 0 {
+		sql.WriteString(` AND a.epoch <= ? AND a.epoch_end >= ?`)
+		params = append(params, query.To, query.From)
+	}
+	if query.Type == "alert" {
+		sql.WriteString(` AND a.alert_id > 0`)
+	} else if query.Type == "annotation" {
+		sql.WriteString(` AND a.alert_id = 0`)
+	}
+
+	acFilter, err := r.getAccessControlFilter(query.SignedInUser, accessResources)
+	if err != nil {
+		return nil, err
+	}
+	sql.WriteString(fmt.Sprintf(" AND (%s)", acFilter))
+	sql.WriteString(" ORDER BY a.org_id, a.epoch_end DESC, a.epoch DESC" + r.db.GetDialect().Limit(query.Limit))
+
+	var rollups []annotationRollup
+	err = r.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(sql.String(), params...).Find(&rollups)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	filters := tag.ParseTagPairs(query.Tags)
+	items := make([]*annotations.ItemDTO, 0, len(rollups))
+	for _, rollup := range rollups {
+		tagCounts := map[string]int64{}
+		if rollup.Tags != "" {
+			if err := json.Unmarshal([]byte(rollup.Tags), &tagCounts); err != nil {
+				return nil, fmt.Errorf("failed to parse the tags of annotation rollup %d: %w", rollup.ID, err)
+			}
+		}
+		if !rollupMatchesTags(tagCounts, filters, query.MatchAny) {
+			continue
+		}
+
+		tags := make([]string, 0, len(tagCounts))
+		counts := make(map[string]any, len(tagCounts))
+		for t, n := range tagCounts {
+			tags = append(tags, t)
+			counts[t] = n
+		}
+		sort.Strings(tags)
+		data := simplejson.NewFromAny(map[string]any{
+			"rollup": map[string]any{"count": rollup.Count, "tags": counts},
+		})
+		items = append(items, &annotations.ItemDTO{
+			AlertID:     rollup.AlertID,
+			DashboardID: rollup.DashboardID,
+			PanelID:     rollup.PanelID,
+			Time:        rollup.Epoch,
+			TimeEnd:     rollup.EpochEnd,
+			Text:        fmt.Sprintf("%d annotations", rollup.Count),
+			Tags:        tags,
+			Created:     rollup.Created,
+			Updated:     rollup.Updated,
+			Data:        data,
+		})
+	}
+	return items, nil
+}
+
+func rollupMatchesTags(tagCounts map[string]int64, filters []*tag.Tag, matchAny bool) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	matches := 0
+	for _, filter := range filters {
+		for t := range tagCounts {
+			parsed := tag.ParseTagPairs([]string{t})
+			if len(parsed) == 1 && parsed[0].Key == filter.Key && (filter.Value == "" || parsed[0].Value == filter.Value) {
+				matches++
+				break
+			}
+		}
+	}
+	if matchAny {
+		return matches > 0
+	}
+	return matches == len(filters)
+}
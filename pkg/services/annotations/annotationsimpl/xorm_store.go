@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -363,8 +364,20 @@ func (r *xormRepositoryImpl) Get(ctx context.Context, query *annotations.ItemQue
 		return nil
 	},
 	)
+	if err != nil {
+		return items, err
+	}
 
-	return items, err
+	rollups, err := r.getRollups(ctx, query, accessResources)
+	if err != nil || len(rollups) == 0 {
+		return items, err
+	}
+	items = append(items, rollups...)
+	sort.Sort(annotations.SortedItems(items))
+	if int64(len(items)) > query.Limit {
+		items = items[:query.Limit]
+	}
+	return items, nil
 }
 
 func (r *xormRepositoryImpl) getAccessControlFilter(user identity.Requester, accessResources *accesscontrol.AccessResources) (string, error) {
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -9,11 +10,13 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/anonymous"
 	"github.com/grafana/grafana/pkg/services/anonymous/anonimpl/anonstore"
 	"github.com/grafana/grafana/pkg/services/anonymous/sortopts"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 const anonymousDeviceExpiration = 30 * 24 * time.Hour
@@ -24,9 +27,16 @@ type deviceDTO struct {
 	AvatarUrl  string `json:"avatarUrl"`
 }
 
+// OrgConfigService reads and stores the anonymous access settings of organizations
+type OrgConfigService interface {
+	GetOrgConfig(ctx context.Context, orgID int64) (*anonymous.OrgConfig, error)
+	SetOrgConfig(ctx context.Context, orgID int64, conf *anonymous.OrgConfig) error
+}
+
 type AnonDeviceServiceAPI struct {
 	cfg            *setting.Cfg
 	store          anonstore.AnonStore
+	orgConfigs     OrgConfigService
 	accesscontrol  accesscontrol.AccessControl
 	RouterRegister routing.RouteRegister
 	log            log.Logger
@@ -35,12 +45,14 @@ type AnonDeviceServiceAPI struct {
 func NewAnonDeviceServiceAPI(
 	cfg *setting.Cfg,
 	anonstore anonstore.AnonStore,
+	orgConfigs OrgConfigService,
 	accesscontrol accesscontrol.AccessControl,
 	routerRegister routing.RouteRegister,
 ) *AnonDeviceServiceAPI {
 	return &AnonDeviceServiceAPI{
 		cfg:            cfg,
 		store:          anonstore,
+		orgConfigs:     orgConfigs,
 		accesscontrol:  accesscontrol,
 		RouterRegister: routerRegister,
 		log:            log.New("anon.api"),
@@ -52,6 +64,8 @@ func (api *AnonDeviceServiceAPI) RegisterAPIEndpoints() {
 	api.RouterRegister.Group("/api/anonymous", func(anonRoutes routing.RouteRegister) {
 		anonRoutes.Get("/devices", auth(accesscontrol.EvalPermission(accesscontrol.ActionUsersRead)), routing.Wrap(api.ListDevices))
 		anonRoutes.Get("/search", auth(accesscontrol.EvalPermission(accesscontrol.ActionUsersRead)), routing.Wrap(api.SearchDevices))
+		anonRoutes.Get("/config", auth(accesscontrol.EvalPermission(accesscontrol.ActionOrgsRead)), routing.Wrap(api.GetOrgConfig))
+		anonRoutes.Put("/config", auth(accesscontrol.EvalPermission(accesscontrol.ActionOrgsWrite)), routing.Wrap(api.UpdateOrgConfig))
 	})
 }
 
@@ -137,6 +151,65 @@ func (api *AnonDeviceServiceAPI) SearchDevices(c *contextmodel.ReqContext) respo
 	return response.JSON(http.StatusOK, results)
 }
 
+// swagger:route GET /anonymous/config devices getAnonymousOrgConfig
+//
+// # Get the anonymous access settings of the current organization
+//
+// Produces:
+// - application/json
+//
+// Responses:
+//
+//	200: anonymousOrgConfigResponse
+//	401: unauthorisedError
+//	403: forbiddenError
+//	500: internalServerError
+func (api *AnonDeviceServiceAPI) GetOrgConfig(c *contextmodel.ReqContext) response.Response {
+	conf, err := api.orgConfigs.GetOrgConfig(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get anonymous access settings", err)
+	}
+	return response.JSON(http.StatusOK, conf)
+}
+
+// swagger:route PUT /anonymous/config devices updateAnonymousOrgConfig
+//
+// # Update the anonymous access settings of the current organization
+//
+// The settings are applied without restarting Grafana and take precedence over the [auth.anonymous] settings of the configuration file.
+// It fails with 403 when allow_org_override is disabled.
+//
+// Responses:
+//
+//	200: okResponse
+//	400: badRequestError
+//	401: unauthorisedError
+//	403: forbiddenError
+//	500: internalServerError
+func (api *AnonDeviceServiceAPI) UpdateOrgConfig(c *contextmodel.ReqContext) response.Response {
+	conf := anonymous.OrgConfig{}
+	if err := web.Bind(c.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if err := api.orgConfigs.SetOrgConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), &conf); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update anonymous access settings", err)
+	}
+	return response.Success("Anonymous access settings updated")
+}
+
+// swagger:parameters updateAnonymousOrgConfig
+type UpdateAnonymousOrgConfigParams struct {
+	// in:body
+	// required:true
+	Body anonymous.OrgConfig `json:"body"`
+}
+
+// swagger:response anonymousOrgConfigResponse
+type AnonymousOrgConfigResponse struct {
+	// in:body
+	Body anonymous.OrgConfig `json:"body"`
+}
+
 // swagger:response devicesResponse
 type DevicesResponse struct {
 	// in:body
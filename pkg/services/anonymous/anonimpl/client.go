@@ -16,6 +16,8 @@ import (
 
 var _ authn.ContextAwareClient = new(Anonymous)
 
+var errAnonymousDisabled = errors.New("anonymous access is disabled for the organization")
+
 type Anonymous struct {
 	cfg               *setting.Cfg
 	log               log.Logger
@@ -28,9 +30,8 @@ func (a *Anonymous) Name() string {
 }
 
 func (a *Anonymous) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
-	o, err := a.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: a.cfg.AnonymousOrgName})
+	o, conf, err := a.resolveOrg(ctx, r)
 	if err != nil {
-		a.log.FromContext(ctx).Error("Failed to find organization", "name", a.cfg.AnonymousOrgName, "error", err)
 		return nil, err
 	}
 
@@ -53,14 +54,54 @@ func (a *Anonymous) Authenticate(ctx context.Context, r *authn.Request) (*authn.
 		ID:           authn.AnonymousNamespaceID,
 		OrgID:        o.ID,
 		OrgName:      o.Name,
-		OrgRoles:     map[int64]org.RoleType{o.ID: org.RoleType(a.cfg.AnonymousOrgRole)},
+		OrgRoles:     map[int64]org.RoleType{o.ID: org.RoleType(conf.Role)},
 		ClientParams: authn.ClientParams{SyncPermissions: true},
 	}, nil
 }
 
+// resolveOrg returns the organization of the anonymous user: the requested organization if it
+// allows anonymous access, and the organization named by org_name otherwise.
+func (a *Anonymous) resolveOrg(ctx context.Context, r *authn.Request) (*org.Org, *anonymous.OrgConfig, error) {
+	if r.OrgID > 0 {
+		conf, err := a.anonDeviceService.GetOrgConfig(ctx, r.OrgID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if conf.Enabled {
+			o, err := a.orgService.GetByID(ctx, &org.GetOrgByIDQuery{ID: r.OrgID})
+			if err != nil {
+				a.log.FromContext(ctx).Error("Failed to find organization", "id", r.OrgID, "error", err)
+				return nil, nil, err
+			}
+			return o, conf, nil
+		}
+	}
+
+	o, err := a.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: a.cfg.AnonymousOrgName})
+	if err != nil {
+		a.log.FromContext(ctx).Error("Failed to find organization", "name", a.cfg.AnonymousOrgName, "error", err)
+		return nil, nil, err
+	}
+	conf, err := a.anonDeviceService.GetOrgConfig(ctx, o.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !conf.Enabled {
+		return nil, nil, errAnonymousDisabled
+	}
+	return o, conf, nil
+}
+
 func (a *Anonymous) Test(ctx context.Context, r *authn.Request) bool {
-	// If anonymous client is register it can always be used for authentication
-	return true
+	if a.cfg.AnonymousEnabled || !a.cfg.AnonymousAllowOrgOverride {
+		return a.cfg.AnonymousEnabled
+	}
+	// without anonymous access in the configuration file, only orgs that enabled it can be requested
+	if r.OrgID > 0 {
+		conf, err := a.anonDeviceService.GetOrgConfig(ctx, r.OrgID)
+		return err == nil && conf.Enabled
+	}
+	return false
 }
 
 func (a *Anonymous) Priority() uint {
@@ -70,6 +111,12 @@ func (a *Anonymous) Priority() uint {
 func (a *Anonymous) UsageStatFn(ctx context.Context) (map[string]any, error) {
 	m := map[string]any{}
 
+	// anonymous access can be enabled per organization, so it is reported by the client rather than the configuration
+	m["stats.auth_enabled.anonymous.count"] = 0
+	if enabled, err := a.anonDeviceService.AnyOrgEnabled(ctx); err == nil && enabled {
+		m["stats.auth_enabled.anonymous.count"] = 1
+	}
+
 	// Add stats about anonymous auth
 	m["stats.anonymous.customized_role.count"] = 0
 	if !strings.EqualFold(a.cfg.AnonymousOrgRole, "Viewer") {
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/anonymous"
 	"github.com/grafana/grafana/pkg/services/anonymous/anontest"
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -49,7 +50,7 @@ func TestAnonymous_Authenticate(t *testing.T) {
 				cfg:               tt.cfg,
 				log:               log.NewNopLogger(),
 				orgService:        &orgtest.FakeOrgService{ExpectedOrg: tt.org, ExpectedError: tt.err},
-				anonDeviceService: anontest.NewFakeService(),
+				anonDeviceService: &anontest.FakeService{ExpectedOrgConfig: &anonymous.OrgConfig{Enabled: true, Role: tt.cfg.AnonymousOrgRole}},
 			}
 
 			identity, err := c.Authenticate(context.Background(), &authn.Request{})
@@ -67,3 +68,48 @@ func TestAnonymous_Authenticate(t *testing.T) {
 		})
 	}
 }
+
+func TestAnonymous_AuthenticateWithOrgConfig(t *testing.T) {
+	cfg := &setting.Cfg{AnonymousOrgName: "default org", AnonymousOrgRole: "Viewer", AnonymousAllowOrgOverride: true}
+
+	t.Run("should sign in to the requested org when it enabled anonymous access", func(t *testing.T) {
+		c := Anonymous{
+			cfg:               cfg,
+			log:               log.NewNopLogger(),
+			orgService:        &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 2, Name: "tenant"}},
+			anonDeviceService: &anontest.FakeService{ExpectedOrgConfig: &anonymous.OrgConfig{Enabled: true, Role: "Editor"}},
+		}
+
+		r := &authn.Request{OrgID: 2}
+		require.True(t, c.Test(context.Background(), r))
+		identity, err := c.Authenticate(context.Background(), r)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), identity.OrgID)
+		assert.Equal(t, org.RoleEditor, identity.GetOrgRole())
+	})
+
+	t.Run("should not authenticate when the org disabled anonymous access", func(t *testing.T) {
+		c := Anonymous{
+			cfg:               cfg,
+			log:               log.NewNopLogger(),
+			orgService:        &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1, Name: "default org"}},
+			anonDeviceService: &anontest.FakeService{ExpectedOrgConfig: &anonymous.OrgConfig{Role: "Viewer"}},
+		}
+
+		require.False(t, c.Test(context.Background(), &authn.Request{OrgID: 1}))
+		require.False(t, c.Test(context.Background(), &authn.Request{}))
+		_, err := c.Authenticate(context.Background(), &authn.Request{})
+		require.ErrorIs(t, err, errAnonymousDisabled)
+	})
+
+	t.Run("should not test the org settings when the server doesn't allow them", func(t *testing.T) {
+		c := Anonymous{
+			cfg:               &setting.Cfg{AnonymousOrgName: "default org", AnonymousOrgRole: "Viewer"},
+			log:               log.NewNopLogger(),
+			orgService:        &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 2, Name: "tenant"}},
+			anonDeviceService: &anontest.FakeService{ExpectedOrgConfig: &anonymous.OrgConfig{Enabled: true, Role: "Editor"}},
+		}
+
+		require.False(t, c.Test(context.Background(), &authn.Request{OrgID: 2}))
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/network"
@@ -30,6 +31,8 @@ type AnonDeviceService struct {
 	log        log.Logger
 	localCache *localcache.CacheService
 	anonStore  anonstore.AnonStore
+	kvStore    kvstore.KVStore
+	orgService org.Service
 	serverLock *serverlock.ServerLockService
 	cfg        *setting.Cfg
 }
@@ -42,6 +45,8 @@ func ProvideAnonymousDeviceService(usageStats usagestats.Service, authBroker aut
 		log:        log.New("anonymous-session-service"),
 		localCache: localcache.New(29*time.Minute, 15*time.Minute),
 		anonStore:  anonstore.ProvideAnonDBStore(sqlStore, cfg.AnonymousDeviceLimit),
+		kvStore:    kvstore.ProvideService(sqlStore),
+		orgService: orgService,
 		serverLock: serverLockService,
 		cfg:        cfg,
 	}
@@ -55,12 +60,11 @@ func ProvideAnonymousDeviceService(usageStats usagestats.Service, authBroker aut
 		anonDeviceService: a,
 	}
 
-	if cfg.AnonymousEnabled {
-		authBroker.RegisterClient(anonClient)
-		authBroker.RegisterPostLoginHook(a.untagDevice, 100)
-	}
+	// the client is always registered since anonymous access can be enabled for an org at runtime
+	authBroker.RegisterClient(anonClient)
+	authBroker.RegisterPostLoginHook(a.untagDevice, 100)
 
-	anonAPI := api.NewAnonDeviceServiceAPI(cfg, a.anonStore, accesscontrol, routeRegister)
+	anonAPI := api.NewAnonDeviceServiceAPI(cfg, a.anonStore, a, accesscontrol, routeRegister)
 	anonAPI.RegisterAPIEndpoints()
 
 	return a
@@ -153,8 +157,8 @@ func (a *AnonDeviceService) TagDevice(ctx context.Context, httpReq *http.Request
 
 // ListDevices returns all devices that have been updated between the given times.
 func (a *AnonDeviceService) ListDevices(ctx context.Context, from *time.Time, to *time.Time) ([]*anonstore.Device, error) {
-	if !a.cfg.AnonymousEnabled {
-		a.log.Debug("Anonymous access is disabled, returning empty result")
+	if enabled, err := a.AnyOrgEnabled(ctx); err != nil || !enabled {
+		a.log.Debug("Anonymous access is disabled, returning empty result", "error", err)
 		return []*anonstore.Device{}, nil
 	}
 
@@ -163,8 +167,8 @@ func (a *AnonDeviceService) ListDevices(ctx context.Context, from *time.Time, to
 
 // CountDevices returns the number of devices that have been updated between the given times.
 func (a *AnonDeviceService) CountDevices(ctx context.Context, from time.Time, to time.Time) (int64, error) {
-	if !a.cfg.AnonymousEnabled {
-		a.log.Debug("Anonymous access is disabled, returning empty result")
+	if enabled, err := a.AnyOrgEnabled(ctx); err != nil || !enabled {
+		a.log.Debug("Anonymous access is disabled, returning empty result", "error", err)
 		return 0, nil
 	}
 
@@ -172,8 +176,8 @@ func (a *AnonDeviceService) CountDevices(ctx context.Context, from time.Time, to
 }
 
 func (a *AnonDeviceService) SearchDevices(ctx context.Context, query *anonstore.SearchDeviceQuery) (*anonstore.SearchDeviceQueryResult, error) {
-	if !a.cfg.AnonymousEnabled {
-		a.log.Debug("Anonymous access is disabled, returning empty result")
+	if enabled, err := a.AnyOrgEnabled(ctx); err != nil || !enabled {
+		a.log.Debug("Anonymous access is disabled, returning empty result", "error", err)
 		return nil, nil
 	}
 	return a.anonStore.SearchDevices(ctx, query)
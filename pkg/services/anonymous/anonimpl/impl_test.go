@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/anonymous"
 	"github.com/grafana/grafana/pkg/services/anonymous/anonimpl/anonstore"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
@@ -271,3 +272,61 @@ func TestIntegrationDeviceService_SearchDevice(t *testing.T) {
 		})
 	}
 }
+
+func TestIntegrationDeviceService_OrgConfig(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	store := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.AnonymousEnabled = true
+	cfg.AnonymousOrgName = "anon org"
+	cfg.AnonymousOrgRole = "Viewer"
+	cfg.AnonymousAllowOrgOverride = true
+	orgService := &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 2, Name: "anon org"}}
+	anonService := ProvideAnonymousDeviceService(&usagestats.UsageStatsMock{}, &authntest.FakeService{}, store, cfg, orgService, nil, actest.FakeAccessControl{}, &routing.RouteRegisterImpl{})
+
+	conf, err := anonService.GetOrgConfig(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, &anonymous.OrgConfig{Enabled: true, Role: "Viewer"}, conf)
+
+	conf, err = anonService.GetOrgConfig(context.Background(), 3)
+	require.NoError(t, err)
+	assert.False(t, conf.Enabled)
+
+	err = anonService.SetOrgConfig(context.Background(), 3, &anonymous.OrgConfig{Enabled: true, Role: "Editor", HideVersion: true})
+	require.NoError(t, err)
+	conf, err = anonService.GetOrgConfig(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, &anonymous.OrgConfig{Enabled: true, Role: "Editor", HideVersion: true}, conf)
+
+	err = anonService.SetOrgConfig(context.Background(), 3, &anonymous.OrgConfig{Enabled: true, Role: "Owner"})
+	require.ErrorIs(t, err, ErrInvalidOrgConfig)
+	err = anonService.SetOrgConfig(context.Background(), 3, &anonymous.OrgConfig{Enabled: true, Role: "Admin"})
+	require.ErrorIs(t, err, ErrInvalidOrgConfig)
+
+	t.Run("should report anonymous access as enabled when any org enables it", func(t *testing.T) {
+		require.NoError(t, anonService.SetOrgConfig(context.Background(), 2, &anonymous.OrgConfig{Role: "Viewer"}))
+		enabled, err := anonService.AnyOrgEnabled(context.Background())
+		require.NoError(t, err)
+		assert.True(t, enabled)
+
+		require.NoError(t, anonService.SetOrgConfig(context.Background(), 3, &anonymous.OrgConfig{Role: "Viewer"}))
+		enabled, err = anonService.AnyOrgEnabled(context.Background())
+		require.NoError(t, err)
+		assert.False(t, enabled)
+	})
+
+	t.Run("should ignore the org settings when the server doesn't allow them", func(t *testing.T) {
+		require.NoError(t, anonService.SetOrgConfig(context.Background(), 3, &anonymous.OrgConfig{Enabled: true, Role: "Editor"}))
+		cfg.AnonymousAllowOrgOverride = false
+		t.Cleanup(func() { cfg.AnonymousAllowOrgOverride = true })
+
+		conf, err := anonService.GetOrgConfig(context.Background(), 3)
+		require.NoError(t, err)
+		assert.False(t, conf.Enabled)
+
+		err = anonService.SetOrgConfig(context.Background(), 3, &anonymous.OrgConfig{Enabled: true, Role: "Editor"})
+		require.ErrorIs(t, err, ErrOrgOverrideNotAllowed)
+	})
+}
@@ -0,0 +1,146 @@
+package anonimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/anonymous"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	orgConfigNamespace = "anonymous"
+	orgConfigKey       = "org_config"
+	// orgConfigCacheTTL bounds how long other instances keep using the previous settings after an update
+	orgConfigCacheTTL = time.Minute
+
+	anyOrgEnabledCacheKey = "anonymous-any-org-enabled"
+)
+
+var (
+	ErrInvalidOrgConfig      = errutil.BadRequest("anonymous.invalid-org-config")
+	ErrOrgOverrideNotAllowed = errutil.Forbidden("anonymous.org-override-not-allowed", errutil.WithPublicMessage("The anonymous access settings can't be changed per organization on this server"))
+)
+
+func orgConfigCacheKey(orgID int64) string {
+	return fmt.Sprintf("anonymous-org-config-%d", orgID)
+}
+
+func (a *AnonDeviceService) GetOrgConfig(ctx context.Context, orgID int64) (*anonymous.OrgConfig, error) {
+	if cached, ok := a.localCache.Get(orgConfigCacheKey(orgID)); ok {
+		return cached.(*anonymous.OrgConfig), nil
+	}
+	// the stored settings are ignored when the server doesn't allow them, even if they were stored before
+	if !a.cfg.AnonymousAllowOrgOverride {
+		conf := a.defaultOrgConfig(ctx, orgID)
+		a.localCache.Set(orgConfigCacheKey(orgID), conf, orgConfigCacheTTL)
+		return conf, nil
+	}
+
+	value, ok, err := a.kvStore.Get(ctx, orgID, orgConfigNamespace, orgConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var conf *anonymous.OrgConfig
+	if ok {
+		conf = &anonymous.OrgConfig{}
+		if err := json.Unmarshal([]byte(value), conf); err != nil {
+			return nil, fmt.Errorf("failed to parse anonymous access settings of org %d: %w", orgID, err)
+		}
+	} else {
+		conf = a.defaultOrgConfig(ctx, orgID)
+	}
+
+	a.localCache.Set(orgConfigCacheKey(orgID), conf, orgConfigCacheTTL)
+	return conf, nil
+}
+
+// SetOrgConfig stores the anonymous access settings of the organization, they take precedence
+// over the settings of the configuration file.
+func (a *AnonDeviceService) SetOrgConfig(ctx context.Context, orgID int64, conf *anonymous.OrgConfig) error {
+	if !a.cfg.AnonymousAllowOrgOverride {
+		return ErrOrgOverrideNotAllowed.Errorf("allow_org_override is disabled")
+	}
+	if !org.RoleType(conf.Role).IsValid() {
+		return ErrInvalidOrgConfig.Errorf("invalid role %q", conf.Role)
+	}
+	// anonymous users could otherwise manage the organization, including its anonymous access
+	if org.RoleType(conf.Role) == org.RoleAdmin {
+		return ErrInvalidOrgConfig.Errorf("anonymous users can't have the %s role", org.RoleAdmin)
+	}
+
+	value, err := json.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	if err := a.kvStore.Set(ctx, orgID, orgConfigNamespace, orgConfigKey, string(value)); err != nil {
+		return err
+	}
+
+	a.localCache.Delete(orgConfigCacheKey(orgID))
+	a.localCache.Delete(anyOrgEnabledCacheKey)
+	return nil
+}
+
+// AnyOrgEnabled reports whether anonymous access is enabled for at least one organization
+func (a *AnonDeviceService) AnyOrgEnabled(ctx context.Context) (bool, error) {
+	if !a.cfg.AnonymousAllowOrgOverride {
+		return a.cfg.AnonymousEnabled, nil
+	}
+	if cached, ok := a.localCache.Get(anyOrgEnabledCacheKey); ok {
+		return cached.(bool), nil
+	}
+
+	keys, err := a.kvStore.Keys(ctx, kvstore.AllOrganizations, orgConfigNamespace, orgConfigKey)
+	if err != nil {
+		return false, err
+	}
+	orgIDs := make([]int64, 0, len(keys)+1)
+	for _, key := range keys {
+		orgIDs = append(orgIDs, key.OrgId)
+	}
+	// the organization named by org_name uses the configuration file until its settings are changed
+	if o, err := a.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: a.cfg.AnonymousOrgName}); err == nil {
+		orgIDs = append(orgIDs, o.ID)
+	}
+
+	enabled := false
+	for _, orgID := range orgIDs {
+		conf, err := a.GetOrgConfig(ctx, orgID)
+		if err != nil {
+			return false, err
+		}
+		if conf.Enabled {
+			enabled = true
+			break
+		}
+	}
+
+	a.localCache.Set(anyOrgEnabledCacheKey, enabled, orgConfigCacheTTL)
+	return enabled, nil
+}
+
+// defaultOrgConfig returns the settings of the configuration file, which only enable
+// anonymous access for the organization named by org_name.
+func (a *AnonDeviceService) defaultOrgConfig(ctx context.Context, orgID int64) *anonymous.OrgConfig {
+	conf := &anonymous.OrgConfig{
+		Role:        a.cfg.AnonymousOrgRole,
+		HideVersion: a.cfg.AnonymousHideVersion,
+	}
+	if !a.cfg.AnonymousEnabled {
+		return conf
+	}
+
+	o, err := a.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: a.cfg.AnonymousOrgName})
+	if err != nil {
+		a.log.Debug("Failed to find anonymous organization", "name", a.cfg.AnonymousOrgName, "error", err)
+		return conf
+	}
+	conf.Enabled = o.ID == orgID
+	return conf
+}
@@ -12,6 +12,7 @@ import (
 type FakeService struct {
 	ExpectedCountDevices int64
 	ExpectedListDevices  []*anonstore.Device
+	ExpectedOrgConfig    *anonymous.OrgConfig
 	ExpectedError        error
 }
 
@@ -30,3 +31,11 @@ func (f *FakeService) CountDevices(ctx context.Context, from time.Time, to time.
 func (f *FakeService) ListDevices(ctx context.Context, from *time.Time, to *time.Time) ([]*anonstore.Device, error) {
 	return f.ExpectedListDevices, f.ExpectedError
 }
+
+func (f *FakeService) GetOrgConfig(ctx context.Context, orgID int64) (*anonymous.OrgConfig, error) {
+	return f.ExpectedOrgConfig, f.ExpectedError
+}
+
+func (f *FakeService) AnyOrgEnabled(ctx context.Context) (bool, error) {
+	return f.ExpectedOrgConfig != nil && f.ExpectedOrgConfig.Enabled, f.ExpectedError
+}
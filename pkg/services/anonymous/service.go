@@ -18,4 +18,16 @@ type Service interface {
 	TagDevice(context.Context, *http.Request, DeviceKind) error
 	CountDevices(ctx context.Context, from time.Time, to time.Time) (int64, error)
 	ListDevices(ctx context.Context, from *time.Time, to *time.Time) ([]*anonstore.Device, error)
+	// GetOrgConfig returns the anonymous access settings of the organization. Organizations
+	// without stored settings use the [auth.anonymous] settings of the configuration file.
+	GetOrgConfig(ctx context.Context, orgID int64) (*OrgConfig, error)
+	// AnyOrgEnabled reports whether anonymous access is enabled for at least one organization
+	AnyOrgEnabled(ctx context.Context) (bool, error)
+}
+
+// OrgConfig are the anonymous access settings of an organization
+type OrgConfig struct {
+	Enabled     bool   `json:"enabled"`
+	Role        string `json:"role"`
+	HideVersion bool   `json:"hideVersion"`
 }
@@ -0,0 +1,148 @@
+package apikeyusage
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware/requestmeta"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// defaultUnusedDays is the number of days without use of the report of the unused keys, if not set
+const defaultUnusedDays = 90
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/auth/keys", func(r routing.RouteRegister) {
+		r.Get("/unused", authorize(accesscontrol.EvalAll(
+			accesscontrol.EvalPermission(accesscontrol.ActionAPIKeyRead),
+			accesscontrol.EvalPermission(serviceaccounts.ActionRead),
+		)), routing.Wrap(s.getUnusedHandler))
+		r.Get("/:id/usage", authorize(accesscontrol.EvalPermission(accesscontrol.ActionAPIKeyRead,
+			accesscontrol.Scope("apikeys", "id", accesscontrol.Parameter(":id")))), routing.Wrap(s.getAPIKeyUsageHandler))
+	}, requestmeta.SetOwner(requestmeta.TeamAuth))
+	routeRegister.Get("/api/serviceaccounts/:serviceAccountId/tokens/:tokenId/usage",
+		authorize(accesscontrol.EvalPermission(serviceaccounts.ActionRead, serviceaccounts.ScopeID)),
+		requestmeta.SetOwner(requestmeta.TeamAuth), routing.Wrap(s.getTokenUsageHandler))
+}
+
+// swagger:route GET /auth/keys/{id}/usage api_keys getAPIKeyUsage
+//
+// Get the usage of an API key, its number of calls and the endpoints it's the most used for.
+//
+// Responses:
+// 200: getAPIKeyUsageResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) getAPIKeyUsageHandler(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+	usage, err := s.GetUsage(c.Req.Context(), &GetUsageQuery{OrgID: c.SignedInUser.GetOrgID(), APIKeyID: id})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get API key usage", err)
+	}
+	return response.JSON(http.StatusOK, usage)
+}
+
+// swagger:route GET /serviceaccounts/{serviceAccountId}/tokens/{tokenId}/usage service_accounts getTokenUsage
+//
+// Get the usage of a service account token, its number of calls and the endpoints it's the most used for.
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:read` scope: `serviceaccounts:id:1` (single service account)
+//
+// Responses:
+// 200: getAPIKeyUsageResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) getTokenUsageHandler(c *contextmodel.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
+	}
+	tokenID, err := strconv.ParseInt(web.Params(c.Req)[":tokenId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Token ID is invalid", err)
+	}
+	usage, err := s.GetUsage(c.Req.Context(), &GetUsageQuery{OrgID: c.SignedInUser.GetOrgID(), APIKeyID: tokenID, ServiceAccountID: &saID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get service account token usage", err)
+	}
+	return response.JSON(http.StatusOK, usage)
+}
+
+// swagger:route GET /auth/keys/unused api_keys getUnusedAPIKeys
+//
+// Get the API keys and service account tokens that weren't used for a number of days, the least recently used first.
+//
+// Expired and revoked keys aren't returned.
+//
+// Responses:
+// 200: getUnusedAPIKeysResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) getUnusedHandler(c *contextmodel.ReqContext) response.Response {
+	days := defaultUnusedDays
+	if c.Query("days") != "" {
+		days = c.QueryInt("days")
+	}
+	keys, err := s.GetUnused(c.Req.Context(), &GetUnusedQuery{OrgID: c.SignedInUser.GetOrgID(), Days: days})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get unused API keys", err)
+	}
+	return response.JSON(http.StatusOK, keys)
+}
+
+// swagger:parameters getAPIKeyUsage
+type GetAPIKeyUsageParams struct {
+	// in:path
+	// required:true
+	ID int64 `json:"id"`
+}
+
+// swagger:parameters getTokenUsage
+type GetTokenUsageParams struct {
+	// in:path
+	// required:true
+	ServiceAccountID int64 `json:"serviceAccountId"`
+	// in:path
+	// required:true
+	TokenID int64 `json:"tokenId"`
+}
+
+// swagger:parameters getUnusedAPIKeys
+type GetUnusedAPIKeysParams struct {
+	// Number of days without use
+	// in:query
+	// required:false
+	// default:90
+	Days int `json:"days"`
+}
+
+// swagger:response getAPIKeyUsageResponse
+type GetAPIKeyUsageResponse struct {
+	// in:body
+	Body *KeyUsage `json:"body"`
+}
+
+// swagger:response getUnusedAPIKeysResponse
+type GetUnusedAPIKeysResponse struct {
+	// in:body
+	Body []*UnusedKey `json:"body"`
+}
@@ -0,0 +1,221 @@
+package apikeyusage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	// flushInterval is how often the recorded usage is written to the database
+	flushInterval = 30 * time.Second
+	// cleanupInterval is how often the usage of the deleted keys is removed
+	cleanupInterval = time.Hour
+	// topEndpointsLimit is the number of endpoints returned with the usage of a key
+	topEndpointsLimit = 10
+	// maxEndpointLength is the length of the endpoint column
+	maxEndpointLength = 190
+)
+
+type usageKey struct {
+	apiKeyID int64
+	endpoint string
+}
+
+type pendingUsage struct {
+	count    int64
+	lastUsed time.Time
+}
+
+// Service records the requests made with API keys and service account tokens, and reports their usage.
+//
+// The requests are counted in memory and written to the database every flushInterval, so that
+// authenticating a request doesn't write to the database.
+type Service struct {
+	store         db.DB
+	accessControl accesscontrol.AccessControl
+	log           log.Logger
+	now           func() time.Time
+
+	mu      sync.Mutex
+	pending map[usageKey]*pendingUsage
+}
+
+func ProvideService(sqlStore db.DB, accessControl accesscontrol.AccessControl, routeRegister routing.RouteRegister) *Service {
+	s := &Service{
+		store:         sqlStore,
+		accessControl: accessControl,
+		log:           log.New("apikey.usage"),
+		now:           time.Now,
+		pending:       map[usageKey]*pendingUsage{},
+	}
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+// Record counts a request made with the API key to the endpoint
+func (s *Service) Record(apiKeyID int64, endpoint string) {
+	if len(endpoint) > maxEndpointLength {
+		endpoint = endpoint[:maxEndpointLength]
+	}
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := usageKey{apiKeyID: apiKeyID, endpoint: endpoint}
+	if p, ok := s.pending[key]; ok {
+		p.count++
+		p.lastUsed = now
+		return
+	}
+	s.pending[key] = &pendingUsage{count: 1, lastUsed: now}
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	flush := time.NewTicker(flushInterval)
+	defer flush.Stop()
+	cleanup := time.NewTicker(cleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-flush.C:
+			if err := s.flush(ctx); err != nil {
+				s.log.Warn("Failed to write API key usage", "error", err)
+			}
+		case <-cleanup.C:
+			if err := s.deleteOrphanedUsage(ctx); err != nil {
+				s.log.Warn("Failed to delete the usage of deleted API keys", "error", err)
+			}
+		case <-ctx.Done():
+			// the server is stopping, write the usage recorded since the last flush
+			if err := s.flush(context.Background()); err != nil {
+				s.log.Warn("Failed to write API key usage", "error", err)
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+// flush adds the recorded usage to the database
+func (s *Service) flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = map[usageKey]*pendingUsage{}
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	return s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		for key, p := range pending {
+			if err := s.addUsage(sess, key, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Service) addUsage(sess *db.Session, key usageKey, p *pendingUsage) error {
+	update := func() (int64, error) {
+		result, err := sess.Exec("UPDATE api_key_usage SET call_count = call_count + ?, last_used = ? WHERE api_key_id = ? AND endpoint = ?",
+			p.count, p.lastUsed, key.apiKeyID, key.endpoint)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	updated, err := update()
+	if err != nil || updated > 0 {
+		return err
+	}
+	if _, err := sess.Insert(&endpointUsage{APIKeyID: key.apiKeyID, Endpoint: key.endpoint, Count: p.count, LastUsed: p.lastUsed}); err != nil {
+		// another instance inserted the usage in between
+		_, err = update()
+		return err
+	}
+	return nil
+}
+
+func (s *Service) deleteOrphanedUsage(ctx context.Context) error {
+	return s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM api_key_usage WHERE api_key_id NOT IN (SELECT id FROM api_key)")
+		return err
+	})
+}
+
+// GetUsage returns the usage of the API key or the service account token of the query
+func (s *Service) GetUsage(ctx context.Context, query *GetUsageQuery) (*KeyUsage, error) {
+	result := &KeyUsage{APIKeyID: query.APIKeyID, TopEndpoints: []*EndpointUsage{}}
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		key := struct {
+			LastUsedAt *time.Time `xorm:"last_used_at"`
+		}{}
+		q := sess.Table("api_key").Cols("last_used_at").Where("id = ? AND org_id = ?", query.APIKeyID, query.OrgID)
+		if query.ServiceAccountID != nil {
+			q = q.And("service_account_id = ?", *query.ServiceAccountID)
+		} else {
+			q = q.And("service_account_id IS NULL")
+		}
+		found, err := q.Get(&key)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrKeyNotFound.Errorf("API key %d not found", query.APIKeyID)
+		}
+		result.LastUsedAt = key.LastUsedAt
+
+		total := struct {
+			Count int64 `xorm:"count"`
+		}{}
+		if _, err := sess.SQL("SELECT COALESCE(SUM(call_count), 0) AS count FROM api_key_usage WHERE api_key_id = ?", query.APIKeyID).Get(&total); err != nil {
+			return err
+		}
+		result.CallCount = total.Count
+
+		var endpoints []*endpointUsage
+		if err := sess.Where("api_key_id = ?", query.APIKeyID).Desc("call_count").Asc("endpoint").Limit(topEndpointsLimit).Find(&endpoints); err != nil {
+			return err
+		}
+		for _, e := range endpoints {
+			result.TopEndpoints = append(result.TopEndpoints, &EndpointUsage{Endpoint: e.Endpoint, Count: e.Count, LastUsed: e.LastUsed})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetUnused returns the API keys and service account tokens of the organization which weren't used for
+// the days of the query, the least recently used first. The expired and revoked keys aren't returned.
+func (s *Service) GetUnused(ctx context.Context, query *GetUnusedQuery) ([]*UnusedKey, error) {
+	if query.Days <= 0 {
+		return nil, ErrInvalidDays.Errorf("invalid number of days %d", query.Days)
+	}
+	now := s.now()
+	cutoff := now.AddDate(0, 0, -query.Days)
+
+	result := make([]*UnusedKey, 0)
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		dialect := s.store.GetDialect()
+		return sess.SQL("SELECT api_key.id, api_key.name, api_key.service_account_id, u.login AS service_account_login, api_key.created, api_key.last_used_at"+
+			" FROM api_key LEFT JOIN "+dialect.Quote("user")+" u ON u.id = api_key.service_account_id"+
+			" WHERE api_key.org_id = ?"+
+			" AND (api_key.is_revoked IS NULL OR api_key.is_revoked = "+dialect.BooleanStr(false)+")"+
+			" AND (api_key.expires IS NULL OR api_key.expires > ?)"+
+			" AND (api_key.last_used_at < ? OR (api_key.last_used_at IS NULL AND api_key.created < ?))"+
+			" ORDER BY COALESCE(api_key.last_used_at, api_key.created), api_key.id",
+			query.OrgID, now.Unix(), cutoff, cutoff).Find(&result)
+	})
+	return result, err
+}
@@ -0,0 +1,117 @@
+package apikeyusage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationAPIKeyUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	s := ProvideService(db.InitTestDB(t), actest.FakeAccessControl{}, routing.NewRouteRegister())
+	now := time.Now().Truncate(time.Second)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	saID := int64(7)
+	revoked := true
+	lastUsed := now.AddDate(0, 0, -10)
+	keys := []*apikey.APIKey{
+		{OrgID: 1, Name: "used", Key: "a", Role: org.RoleViewer, Created: now.AddDate(0, -1, 0), LastUsedAt: &now},
+		{OrgID: 1, Name: "stale", Key: "b", Role: org.RoleViewer, Created: now.AddDate(0, -1, 0), LastUsedAt: &lastUsed, ServiceAccountId: &saID},
+		{OrgID: 1, Name: "never", Key: "c", Role: org.RoleViewer, Created: now.AddDate(0, -1, 0)},
+		{OrgID: 1, Name: "new", Key: "d", Role: org.RoleViewer, Created: now},
+		{OrgID: 1, Name: "revoked", Key: "e", Role: org.RoleViewer, Created: now.AddDate(0, -1, 0), IsRevoked: &revoked},
+		{OrgID: 2, Name: "other", Key: "f", Role: org.RoleViewer, Created: now.AddDate(0, -1, 0)},
+	}
+	require.NoError(t, s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, k := range keys {
+			k.Updated = k.Created
+			if _, err := sess.Insert(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	t.Run("should count the requests by endpoint", func(t *testing.T) {
+		s.Record(keys[0].ID, "GET /api/dashboards/uid/:uid")
+		s.Record(keys[0].ID, "GET /api/dashboards/uid/:uid")
+		s.Record(keys[0].ID, "POST /api/ds/query")
+		require.NoError(t, s.flush(ctx))
+		s.Record(keys[0].ID, "POST /api/ds/query")
+		s.Record(keys[0].ID, "POST /api/ds/query")
+		require.NoError(t, s.flush(ctx))
+
+		usage, err := s.GetUsage(ctx, &GetUsageQuery{OrgID: 1, APIKeyID: keys[0].ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), usage.CallCount)
+		require.Len(t, usage.TopEndpoints, 2)
+		assert.Equal(t, "POST /api/ds/query", usage.TopEndpoints[0].Endpoint)
+		assert.Equal(t, int64(3), usage.TopEndpoints[0].Count)
+		assert.Equal(t, int64(2), usage.TopEndpoints[1].Count)
+	})
+
+	t.Run("should only return the usage of the keys of the organization and service account", func(t *testing.T) {
+		_, err := s.GetUsage(ctx, &GetUsageQuery{OrgID: 2, APIKeyID: keys[0].ID})
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+		_, err = s.GetUsage(ctx, &GetUsageQuery{OrgID: 1, APIKeyID: keys[0].ID, ServiceAccountID: &saID})
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+
+		usage, err := s.GetUsage(ctx, &GetUsageQuery{OrgID: 1, APIKeyID: keys[1].ID, ServiceAccountID: &saID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), usage.CallCount)
+		assert.Empty(t, usage.TopEndpoints)
+	})
+
+	t.Run("should report the keys unused for the days", func(t *testing.T) {
+		unused, err := s.GetUnused(ctx, &GetUnusedQuery{OrgID: 1, Days: 7})
+		require.NoError(t, err)
+		names := []string{}
+		for _, k := range unused {
+			names = append(names, k.Name)
+		}
+		assert.Equal(t, []string{"never", "stale"}, names)
+		assert.Equal(t, &saID, unused[1].ServiceAccountID)
+
+		unused, err = s.GetUnused(ctx, &GetUnusedQuery{OrgID: 1, Days: 60})
+		require.NoError(t, err)
+		assert.Empty(t, unused)
+
+		_, err = s.GetUnused(ctx, &GetUnusedQuery{OrgID: 1})
+		assert.ErrorIs(t, err, ErrInvalidDays)
+	})
+
+	t.Run("should delete the usage of deleted keys", func(t *testing.T) {
+		require.NoError(t, s.store.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Exec("DELETE FROM api_key WHERE id = ?", keys[0].ID)
+			return err
+		}))
+		require.NoError(t, s.deleteOrphanedUsage(ctx))
+
+		var count int64
+		require.NoError(t, s.store.WithDbSession(ctx, func(sess *db.Session) error {
+			var err error
+			count, err = sess.Count(&endpointUsage{})
+			return err
+		}))
+		assert.Zero(t, count)
+	})
+}
@@ -0,0 +1,66 @@
+package apikeyusage
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrKeyNotFound = errutil.NotFound("apikeyusage.not-found", errutil.WithPublicMessage("API key not found"))
+	ErrInvalidDays = errutil.BadRequest("apikeyusage.invalid-days", errutil.WithPublicMessage("The number of days must be positive"))
+)
+
+// endpointUsage is the usage of an API key for an endpoint, stored in the api_key_usage table
+type endpointUsage struct {
+	ID       int64     `xorm:"pk autoincr 'id'"`
+	APIKeyID int64     `xorm:"api_key_id"`
+	Endpoint string    `xorm:"endpoint"`
+	Count    int64     `xorm:"call_count"`
+	LastUsed time.Time `xorm:"last_used"`
+}
+
+func (endpointUsage) TableName() string {
+	return "api_key_usage"
+}
+
+// KeyUsage is the usage of an API key or a service account token
+type KeyUsage struct {
+	APIKeyID   int64      `json:"apiKeyId"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	// CallCount is the number of requests made with the key since its usage is tracked
+	CallCount int64 `json:"callCount"`
+	// TopEndpoints are the endpoints the key made the most requests to
+	TopEndpoints []*EndpointUsage `json:"topEndpoints"`
+}
+
+type EndpointUsage struct {
+	// Endpoint is the method and route of the requests, e.g. GET /api/dashboards/uid/:uid
+	Endpoint string    `json:"endpoint"`
+	Count    int64     `json:"count"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// UnusedKey is an API key or a service account token that wasn't used for a number of days
+type UnusedKey struct {
+	ID   int64  `json:"id" xorm:"id"`
+	Name string `json:"name" xorm:"name"`
+	// ServiceAccountID and ServiceAccountLogin are only set for the tokens of service accounts
+	ServiceAccountID    *int64     `json:"serviceAccountId,omitempty" xorm:"service_account_id"`
+	ServiceAccountLogin string     `json:"serviceAccountLogin,omitempty" xorm:"service_account_login"`
+	Created             time.Time  `json:"created" xorm:"created"`
+	LastUsedAt          *time.Time `json:"lastUsedAt" xorm:"last_used_at"`
+}
+
+type GetUsageQuery struct {
+	OrgID    int64
+	APIKeyID int64
+	// ServiceAccountID is set to get the usage of a token of the service account, and unset for an API key
+	ServiceAccountID *int64
+}
+
+type GetUnusedQuery struct {
+	OrgID int64
+	// Days without use, the keys created more recently aren't returned
+	Days int
+}
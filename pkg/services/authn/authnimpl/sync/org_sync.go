@@ -11,6 +11,12 @@ import (
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var errOrgOverrideForbidden = errutil.Forbidden(
+	"org.sync.override-forbidden",
+	errutil.WithPublicMessage("Not allowed to act in the requested organization"),
 )
 
 func ProvideOrgSync(userService user.Service, orgService org.Service, accessControl accesscontrol.Service) *OrgSync {
@@ -128,3 +134,46 @@ func (s *OrgSync) SyncOrgRolesHook(ctx context.Context, id *authn.Identity, _ *a
 
 	return nil
 }
+
+// SyncOrgOverrideHook resolves the identity in the organization targeted by the X-Grafana-Org-Id header or the
+// targetOrgId query when it isn't a member of it. Only the identities allowed to manage the organization, like the
+// server admins, can act in it, as an admin of the organization and without changing the user's current organization.
+func (s *OrgSync) SyncOrgOverrideHook(ctx context.Context, id *authn.Identity, r *authn.Request) error {
+	if r.OrgID <= 0 || id.OrgID == r.OrgID || !id.ClientParams.FetchSyncedUser || r.GetMeta(authn.MetaKeyIsLogin) != "" {
+		return nil
+	}
+
+	namespace, _ := id.GetNamespacedID()
+	if namespace != authn.NamespaceUser {
+		return nil
+	}
+
+	ctxLogger := s.log.FromContext(ctx)
+	permissions, err := s.accessControl.GetUserPermissions(ctx, id, accesscontrol.Options{ReloadCache: false})
+	if err != nil {
+		ctxLogger.Error("Failed to fetch permissions for organization override", "id", id.ID, "error", err)
+		return errOrgOverrideForbidden.Errorf("failed to fetch permissions: %w", err)
+	}
+
+	// the identity isn't a member of the org, so it can only be granted to manage orgs globally
+	if !accesscontrol.EvalPermission(accesscontrol.ActionOrgsWrite).Evaluate(accesscontrol.GroupScopesByAction(permissions)) {
+		return errOrgOverrideForbidden.Errorf("identity %s is not a member of organization %d", id.ID, r.OrgID)
+	}
+
+	o, err := s.orgService.GetByID(ctx, &org.GetOrgByIDQuery{ID: r.OrgID})
+	if err != nil {
+		if errors.Is(err, org.ErrOrgNotFound) {
+			return errOrgOverrideForbidden.Errorf("organization %d not found", r.OrgID)
+		}
+		return err
+	}
+
+	ctxLogger.Info("Acting in organization the identity is not a member of", "id", id.ID, "orgId", o.ID)
+	if id.OrgRoles == nil {
+		id.OrgRoles = make(map[int64]org.RoleType)
+	}
+	id.OrgID = o.ID
+	id.OrgName = o.Name
+	id.OrgRoles[o.ID] = org.RoleAdmin
+	return nil
+}
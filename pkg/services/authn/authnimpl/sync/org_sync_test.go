@@ -124,3 +124,51 @@ func TestOrgSync_SyncOrgRolesHook(t *testing.T) {
 		})
 	}
 }
+
+func TestOrgSync_SyncOrgOverrideHook(t *testing.T) {
+	orgAdmin := []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsWrite}}
+
+	tests := []struct {
+		name        string
+		permissions []accesscontrol.Permission
+		requestOrg  int64
+		wantErr     bool
+		wantOrgID   int64
+		wantRole    org.RoleType
+	}{
+		{
+			name:        "should act in the requested org as an admin with the permission to manage it",
+			permissions: orgAdmin,
+			requestOrg:  2,
+			wantOrgID:   2,
+			wantRole:    org.RoleAdmin,
+		},
+		{
+			name:       "should reject the identities without the permission to manage the org",
+			requestOrg: 2,
+			wantErr:    true,
+			wantOrgID:  -1,
+		},
+		{
+			name:       "should not change the identity without a requested org",
+			wantOrgID:  -1,
+			requestOrg: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ProvideOrgSync(&usertest.FakeUserService{}, &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 2, Name: "other"}},
+				&actest.FakeService{ExpectedPermissions: tt.permissions})
+			id := &authn.Identity{ID: "user:1", OrgID: -1, ClientParams: authn.ClientParams{FetchSyncedUser: true}}
+
+			err := s.SyncOrgOverrideHook(context.Background(), id, &authn.Request{OrgID: tt.requestOrg})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errOrgOverrideForbidden)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantOrgID, id.OrgID)
+			assert.Equal(t, tt.wantRole, id.OrgRoles[tt.wantOrgID])
+		})
+	}
+}
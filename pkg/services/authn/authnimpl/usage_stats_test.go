@@ -28,7 +28,7 @@ func TestService_getUsageStats(t *testing.T) {
 
 	got, err := svc.getUsageStats(context.Background())
 	require.NoError(t, err)
-	want := map[string]any{"stats.auth_enabled.anonymous.count": 0,
+	want := map[string]any{
 		"stats.auth_enabled.auth_proxy.count":       1,
 		"stats.auth_enabled.basic_auth.count":       1,
 		"stats.auth_enabled.grafana_password.count": 1,
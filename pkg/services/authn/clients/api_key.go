@@ -3,12 +3,14 @@ package clients
 import (
 	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/components/apikeygen"
 	"github.com/grafana/grafana/pkg/components/satokengen"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	authidentity "github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/authn"
@@ -17,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 var (
@@ -29,11 +32,17 @@ var (
 var _ authn.HookClient = new(APIKey)
 var _ authn.ContextAwareClient = new(APIKey)
 
-func ProvideAPIKey(apiKeyService apikey.Service, userService user.Service) *APIKey {
+// APIKeyUsageRecorder records the requests made with API keys
+type APIKeyUsageRecorder interface {
+	Record(apiKeyID int64, endpoint string)
+}
+
+func ProvideAPIKey(apiKeyService apikey.Service, userService user.Service, usage APIKeyUsageRecorder) *APIKey {
 	return &APIKey{
 		log:           log.New(authn.ClientAPIKey),
 		userService:   userService,
 		apiKeyService: apiKeyService,
+		usage:         usage,
 	}
 }
 
@@ -41,6 +50,7 @@ type APIKey struct {
 	log           log.Logger
 	userService   user.Service
 	apiKeyService apikey.Service
+	usage         APIKeyUsageRecorder
 }
 
 func (s *APIKey) Name() string {
@@ -172,9 +182,28 @@ func (s *APIKey) Hook(ctx context.Context, identity *authn.Identity, r *authn.Re
 		}
 	}(id)
 
+	if s.usage != nil && r.Resp != nil {
+		// the route of the request is only known once it's handled
+		r.Resp.Before(func(web.ResponseWriter) {
+			s.usage.Record(id, requestEndpoint(r.HTTPRequest))
+		})
+	}
+
 	return nil
 }
 
+// requestEndpoint returns the method and route of the request, e.g. GET /api/dashboards/uid/:uid
+func requestEndpoint(req *http.Request) string {
+	route := "unknown"
+	if c := web.FromContext(req.Context()); c != nil {
+		req = c.Req
+	}
+	if name, ok := middleware.RouteOperationName(req); ok {
+		route = name
+	}
+	return req.Method + " " + route
+}
+
 func (s *APIKey) getAPIKeyID(ctx context.Context, identity *authn.Identity, r *authn.Request) (apiKeyID int64, exists bool) {
 	namespace, identifier := identity.GetNamespacedID()
 
@@ -128,7 +128,7 @@ func TestAPIKey_Authenticate(t *testing.T) {
 				ExpectedAPIKey: tt.expectedKey,
 			}, &usertest.FakeUserService{
 				ExpectedSignedInUser: tt.expectedUser,
-			})
+			}, nil)
 
 			identity, err := c.Authenticate(context.Background(), tt.req)
 			if tt.expectedErr != nil {
@@ -195,7 +195,7 @@ func TestAPIKey_Test(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideAPIKey(&apikeytest.Service{}, usertest.NewUserServiceFake())
+			c := ProvideAPIKey(&apikeytest.Service{}, usertest.NewUserServiceFake(), nil)
 			assert.Equal(t, tt.expected, c.Test(context.Background(), tt.req))
 		})
 	}
@@ -299,7 +299,7 @@ func TestAPIKey_GetAPIKeyIDFromIdentity(t *testing.T) {
 				ExpectedAPIKey: tt.expectedKey,
 			}, &usertest.FakeUserService{
 				ExpectedSignedInUser: signedInUser,
-			})
+			}, nil)
 			id, exists := c.getAPIKeyID(context.Background(), tt.expectedIdentity, req)
 			assert.Equal(t, tt.expectedExists, exists)
 			assert.Equal(t, tt.expectedKeyID, id)
@@ -85,6 +85,11 @@ type Identity struct {
 	// IDToken is a signed token representing the identity that can be forwarded to plugins and external services.
 	// Will only be set when featuremgmt.FlagIdForwarding is enabled.
 	IDToken string
+	// ImpersonatorID is the id of the Grafana admin that is impersonating the entity.
+	// Zero unless the identity was switched by an impersonation session.
+	ImpersonatorID int64
+	// ImpersonatorLogin is the login of the Grafana admin that is impersonating the entity.
+	ImpersonatorLogin string
 }
 
 func (i *Identity) GetAuthenticatedBy() string {
@@ -219,6 +224,9 @@ func (i *Identity) SignedInUser() *user.SignedInUser {
 		Teams:           i.Teams,
 		Permissions:     i.Permissions,
 		IDToken:         i.IDToken,
+
+		ImpersonatorID:    i.ImpersonatorID,
+		ImpersonatorLogin: i.ImpersonatorLogin,
 	}
 
 	if namespace == NamespaceAPIKey {
@@ -14,22 +14,26 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
+	"github.com/grafana/grafana/pkg/services/usernotification"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner,
+	userNotificationService usernotification.Service, dashboardService dashboards.DashboardService) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -43,6 +47,8 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tempUserService:           tempUserService,
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
+		userNotificationService:   userNotificationService,
+		dashboardService:          dashboardService,
 	}
 	return s
 }
@@ -60,6 +66,8 @@ type CleanUpService struct {
 	deleteExpiredImageService *image.DeleteExpiredService
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
+	userNotificationService   usernotification.Service
+	dashboardService          dashboards.DashboardService
 }
 
 type cleanUpJob struct {
@@ -73,6 +81,7 @@ func (j cleanUpJob) String() string {
 
 func (srv *CleanUpService) Run(ctx context.Context) error {
 	srv.cleanUpTmpFiles(ctx)
+	srv.deleteExpiredRenderedImages(ctx)
 
 	ticker := time.NewTicker(time.Minute * 10)
 	for {
@@ -95,6 +104,7 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 
 	cleanupJobs := []cleanUpJob{
 		{"clean up temporary files", srv.cleanUpTmpFiles},
+		{"delete expired rendered images", srv.deleteExpiredRenderedImages},
 		{"delete expired snapshots", srv.deleteExpiredSnapshots},
 		{"delete expired dashboard versions", srv.deleteExpiredDashboardVersions},
 		{"delete expired images", srv.deleteExpiredImages},
@@ -103,6 +113,8 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
 		{"delete stale query history", srv.deleteStaleQueryHistory},
 		{"expire old email verifications", srv.expireOldVerifications},
+		{"delete expired user notifications", srv.deleteExpiredUserNotifications},
+		{"delete expired dashboard changes", srv.deleteExpiredDashboardChanges},
 	}
 
 	logger := srv.log.FromContext(ctx)
@@ -133,7 +145,6 @@ func (srv *CleanUpService) cleanUpOldAnnotations(ctx context.Context) {
 
 func (srv *CleanUpService) cleanUpTmpFiles(ctx context.Context) {
 	folders := []string{
-		srv.Cfg.ImagesDir,
 		srv.Cfg.CSVsDir,
 		srv.Cfg.PDFsDir,
 	}
@@ -141,24 +152,34 @@ func (srv *CleanUpService) cleanUpTmpFiles(ctx context.Context) {
 	for _, f := range folders {
 		ctx, span := srv.tracer.Start(ctx, "delete stale files in temporary directory")
 		span.SetAttributes(attribute.String("directory", f))
-		srv.cleanUpTmpFolder(ctx, f)
+		srv.cleanUpTmpFolder(ctx, f, srv.Cfg.TempDataLifetime)
 		span.End()
 	}
 }
 
-func (srv *CleanUpService) cleanUpTmpFolder(ctx context.Context, folder string) {
+// deleteExpiredRenderedImages deletes the rendered images, served from /public/img/attachments, older than their
+// retention
+func (srv *CleanUpService) deleteExpiredRenderedImages(ctx context.Context) {
+	deleted, size := srv.cleanUpTmpFolder(ctx, srv.Cfg.ImagesDir, srv.Cfg.RenderedImagesLifetime)
+	metrics.MRenderedImagesDeletedTotal.Add(float64(deleted))
+	metrics.MRenderedImagesDeletedBytesTotal.Add(float64(size))
+}
+
+// cleanUpTmpFolder deletes the files of the folder older than the lifetime, it returns the number and total size of
+// the deleted files
+func (srv *CleanUpService) cleanUpTmpFolder(ctx context.Context, folder string, lifetime time.Duration) (int, int64) {
 	logger := srv.log.FromContext(ctx)
 	if _, err := os.Stat(folder); os.IsNotExist(err) {
-		return
+		return 0, 0
 	}
 
 	files, err := os.ReadDir(folder)
 	if err != nil {
 		logger.Error("Problem reading dir", "folder", folder, "error", err)
-		return
+		return 0, 0
 	}
 
-	var toDelete []fs.DirEntry
+	var toDelete []fs.FileInfo
 	var now = time.Now()
 
 	for _, file := range files {
@@ -168,28 +189,38 @@ func (srv *CleanUpService) cleanUpTmpFolder(ctx context.Context, folder string)
 			continue
 		}
 
-		if srv.shouldCleanupTempFile(info.ModTime(), now) {
-			toDelete = append(toDelete, file)
+		if shouldCleanupFile(info.ModTime(), now, lifetime) {
+			toDelete = append(toDelete, info)
 		}
 	}
 
+	deleted := 0
+	var size int64
 	for _, file := range toDelete {
 		fullPath := path.Join(folder, file.Name())
 		err := os.Remove(fullPath)
 		if err != nil {
 			logger.Error("Failed to delete temp file", "file", file.Name(), "error", err)
+			continue
 		}
+		deleted++
+		size += file.Size()
 	}
 
-	logger.Debug("Found old rendered file to delete", "folder", folder, "deleted", len(toDelete), "kept", len(files))
+	logger.Debug("Found old rendered file to delete", "folder", folder, "deleted", deleted, "kept", len(files)-deleted)
+	return deleted, size
 }
 
 func (srv *CleanUpService) shouldCleanupTempFile(filemtime time.Time, now time.Time) bool {
-	if srv.Cfg.TempDataLifetime == 0 {
+	return shouldCleanupFile(filemtime, now, srv.Cfg.TempDataLifetime)
+}
+
+func shouldCleanupFile(filemtime time.Time, now time.Time, lifetime time.Duration) bool {
+	if lifetime == 0 {
 		return false
 	}
 
-	return filemtime.Add(srv.Cfg.TempDataLifetime).Before(now)
+	return filemtime.Add(lifetime).Before(now)
 }
 
 func (srv *CleanUpService) deleteExpiredSnapshots(ctx context.Context) {
@@ -266,6 +297,26 @@ func (srv *CleanUpService) deleteStaleShortURLs(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) deleteExpiredUserNotifications(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	rowsCount, err := srv.userNotificationService.DeleteExpired(ctx)
+	if err != nil {
+		logger.Error("Problem deleting expired user notifications", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired user notifications", "rows affected", rowsCount)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredDashboardChanges(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	rowsCount, err := srv.dashboardService.DeleteExpiredDashboardChanges(ctx, time.Now().Add(-srv.Cfg.DashboardChangeFeedRetention))
+	if err != nil {
+		logger.Error("Problem deleting expired dashboard changes", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired dashboard changes", "rows affected", rowsCount)
+	}
+}
+
 func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
 	// Delete query history from 14+ days ago with exception of starred queries
@@ -1,11 +1,15 @@
 package cleanup
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -36,3 +40,28 @@ func TestCleanUpTmpFiles(t *testing.T) {
 		require.False(t, service.shouldCleanupTempFile(weekAgo, now))
 	})
 }
+
+func TestDeleteExpiredRenderedImages(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.ImagesDir = t.TempDir()
+	cfg.RenderedImagesLifetime = time.Hour
+	service := CleanUpService{
+		Cfg: cfg,
+		log: log.New("cleanup"),
+	}
+
+	now := time.Now()
+	for name, mtime := range map[string]time.Time{"old.png": now.Add(-2 * time.Hour), "recent.png": now.Add(-time.Minute)} {
+		file := filepath.Join(cfg.ImagesDir, name)
+		require.NoError(t, os.WriteFile(file, []byte("png"), 0600))
+		require.NoError(t, os.Chtimes(file, mtime, mtime))
+	}
+
+	deleted, size := service.cleanUpTmpFolder(context.Background(), cfg.ImagesDir, cfg.RenderedImagesLifetime)
+	require.Equal(t, 1, deleted)
+	require.Equal(t, int64(3), size)
+	_, err := os.Stat(filepath.Join(cfg.ImagesDir, "old.png"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(cfg.ImagesDir, "recent.png"))
+	require.NoError(t, err)
+}
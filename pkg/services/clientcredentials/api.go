@@ -0,0 +1,267 @@
+package clientcredentials
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware/requestmeta"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	grantTypeClientCredentials = "client_credentials"
+	grantTypeJWTBearer         = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/serviceaccounts/:serviceAccountId/clients", func(r routing.RouteRegister) {
+		r.Get("/", authorize(accesscontrol.EvalPermission(serviceaccounts.ActionRead, serviceaccounts.ScopeID)), routing.Wrap(s.listHandler))
+		r.Post("/", authorize(accesscontrol.EvalPermission(serviceaccounts.ActionWrite, serviceaccounts.ScopeID)), routing.Wrap(s.createHandler))
+		r.Delete("/:clientId", authorize(accesscontrol.EvalPermission(serviceaccounts.ActionWrite, serviceaccounts.ScopeID)), routing.Wrap(s.deleteHandler))
+	}, requestmeta.SetOwner(requestmeta.TeamAuth))
+	routeRegister.Post("/oauth2/token", requestmeta.SetOwner(requestmeta.TeamAuth), routing.Wrap(s.tokenHandler))
+}
+
+type CreateClientForm struct {
+	// example: deploy-pipeline
+	Name string `json:"name"`
+	// JWTSubject allows the client to exchange the JWTs of the trusted issuer with this subject for access tokens
+	// example: repo:grafana/deploy:ref:refs/heads/main
+	JWTSubject string `json:"jwtSubject"`
+}
+
+// swagger:model
+type CreateClientResult struct {
+	*Client
+	// ClientSecret is only returned when the client is created
+	ClientSecret string `json:"clientSecret"`
+}
+
+// TokenResponse is the successful response of the token endpoint, as defined by RFC 6749
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// TokenErrorResponse is the error response of the token endpoint, as defined by RFC 6749
+type TokenErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// swagger:route GET /serviceaccounts/{serviceAccountId}/clients service_accounts listOAuth2Clients
+//
+// Get the OAuth2 clients of a service account.
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:read` scope: `serviceaccounts:id:1` (single service account)
+//
+// Responses:
+// 200: listOAuth2ClientsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) listHandler(c *contextmodel.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
+	}
+	clients, err := s.List(c.Req.Context(), c.SignedInUser.GetOrgID(), saID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get OAuth2 clients", err)
+	}
+	return response.JSON(http.StatusOK, clients)
+}
+
+// swagger:route POST /serviceaccounts/{serviceAccountId}/clients service_accounts createOAuth2Client
+//
+// Create an OAuth2 client for a service account.
+//
+// The client exchanges its credentials for short-lived access tokens with the permissions of the service account.
+// Its secret is only returned in the response.
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:write` scope: `serviceaccounts:id:1` (single service account)
+//
+// Responses:
+// 200: createOAuth2ClientResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) createHandler(c *contextmodel.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
+	}
+	form := CreateClientForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+
+	client, secret, err := s.Create(c.Req.Context(), &CreateClientCommand{
+		OrgID:            c.SignedInUser.GetOrgID(),
+		ServiceAccountID: saID,
+		Name:             form.Name,
+		JWTSubject:       form.JWTSubject,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create OAuth2 client", err)
+	}
+	return response.JSON(http.StatusOK, CreateClientResult{Client: client, ClientSecret: secret})
+}
+
+// swagger:route DELETE /serviceaccounts/{serviceAccountId}/clients/{clientId} service_accounts deleteOAuth2Client
+//
+// Delete an OAuth2 client of a service account.
+//
+// The access tokens issued to the client are rejected within a minute.
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:write` scope: `serviceaccounts:id:1` (single service account)
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) deleteHandler(c *contextmodel.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
+	}
+	if err := s.Delete(c.Req.Context(), &DeleteClientCommand{
+		OrgID:            c.SignedInUser.GetOrgID(),
+		ServiceAccountID: saID,
+		ClientID:         web.Params(c.Req)[":clientId"],
+	}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete OAuth2 client", err)
+	}
+	return response.Success("OAuth2 client deleted")
+}
+
+// tokenHandler is the token endpoint of OAuth2. It implements the client credentials grant, with the
+// credentials either sent with basic authentication or as the client_id and client_secret form parameters,
+// and the JWT bearer grant, exchanging a JWT of the issuer trusted by the JWT authentication.
+func (s *Service) tokenHandler(c *contextmodel.ReqContext) response.Response {
+	if err := c.Req.ParseForm(); err != nil {
+		return tokenError(http.StatusBadRequest, "invalid_request", "The request body is invalid")
+	}
+
+	var clientID string
+	var basic bool
+	var token *AccessToken
+	var err error
+	switch c.Req.PostForm.Get("grant_type") {
+	case grantTypeClientCredentials:
+		var secret string
+		clientID, secret, basic = c.Req.BasicAuth()
+		if basic {
+			// the credentials are form encoded before being sent with basic authentication
+			var idErr, secretErr error
+			clientID, idErr = url.QueryUnescape(clientID)
+			secret, secretErr = url.QueryUnescape(secret)
+			if idErr != nil || secretErr != nil {
+				return tokenError(http.StatusBadRequest, "invalid_request", "The client credentials are invalid")
+			}
+		} else {
+			clientID, secret = c.Req.PostForm.Get("client_id"), c.Req.PostForm.Get("client_secret")
+		}
+		if clientID == "" || secret == "" {
+			return tokenError(http.StatusBadRequest, "invalid_request", "The client credentials are missing")
+		}
+		token, err = s.Exchange(c.Req.Context(), clientID, secret)
+	case grantTypeJWTBearer:
+		if !s.cfg.JWTAuth.Enabled {
+			return tokenError(http.StatusBadRequest, "unsupported_grant_type", "The JWT authentication isn't enabled")
+		}
+		clientID = c.Req.PostForm.Get("client_id")
+		assertion := c.Req.PostForm.Get("assertion")
+		if clientID == "" || assertion == "" {
+			return tokenError(http.StatusBadRequest, "invalid_request", "The client_id and assertion parameters are required")
+		}
+		token, err = s.ExchangeJWT(c.Req.Context(), clientID, assertion)
+	default:
+		return tokenError(http.StatusBadRequest, "unsupported_grant_type", "The grant type must be client_credentials or "+grantTypeJWTBearer)
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrInvalidGrant):
+		c.Logger.Warn("Rejected OAuth2 assertion", "clientId", clientID, "error", err)
+		return tokenError(http.StatusBadRequest, "invalid_grant", "The assertion is invalid or isn't trusted by the client")
+	case errors.Is(err, ErrInvalidClient):
+		c.Logger.Warn("Rejected OAuth2 client credentials", "clientId", clientID, "error", err)
+		resp := tokenError(http.StatusUnauthorized, "invalid_client", "Client authentication failed")
+		if basic {
+			resp.SetHeader("WWW-Authenticate", `Basic realm="grafana"`)
+		}
+		return resp
+	default:
+		c.Logger.Error("Failed to issue access token", "clientId", clientID, "error", err)
+		return tokenError(http.StatusInternalServerError, "server_error", "")
+	}
+
+	return response.JSON(http.StatusOK, TokenResponse{
+		AccessToken: token.Token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(token.Expires.Sub(s.now()).Seconds()),
+	}).SetHeader("Cache-Control", "no-store").SetHeader("Pragma", "no-cache")
+}
+
+func tokenError(status int, code, description string) *response.NormalResponse {
+	return response.JSON(status, TokenErrorResponse{Error: code, ErrorDescription: description}).
+		SetHeader("Cache-Control", "no-store").SetHeader("Pragma", "no-cache")
+}
+
+// swagger:parameters listOAuth2Clients
+type ListOAuth2ClientsParams struct {
+	// in:path
+	// required:true
+	ServiceAccountID int64 `json:"serviceAccountId"`
+}
+
+// swagger:parameters createOAuth2Client
+type CreateOAuth2ClientParams struct {
+	// in:path
+	// required:true
+	ServiceAccountID int64 `json:"serviceAccountId"`
+	// in:body
+	Body CreateClientForm
+}
+
+// swagger:parameters deleteOAuth2Client
+type DeleteOAuth2ClientParams struct {
+	// in:path
+	// required:true
+	ServiceAccountID int64 `json:"serviceAccountId"`
+	// in:path
+	// required:true
+	ClientID string `json:"clientId"`
+}
+
+// swagger:response listOAuth2ClientsResponse
+type ListOAuth2ClientsResponse struct {
+	// in:body
+	Body []*Client
+}
+
+// swagger:response createOAuth2ClientResponse
+type CreateOAuth2ClientResponse struct {
+	// in:body
+	Body CreateClientResult
+}
@@ -0,0 +1,302 @@
+package clientcredentials
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// ClientName is the authn client authenticating the access tokens of OAuth2 clients
+	ClientName = "auth.client.client-credentials"
+	// AuthModule is what the requests made with an access token are authenticated by
+	AuthModule = "oauth2_client_credentials"
+
+	// clientPriority runs the client before the API keys, which accept any bearer token
+	clientPriority = 25
+
+	// clientCheckInterval is how often the client of an access token is checked to still exist, so a
+	// token can be used at most this long after its client was deleted
+	clientCheckInterval = time.Minute
+
+	secretLength = 48
+)
+
+var _ authn.Client = (*Service)(nil)
+
+// Service manages the OAuth2 clients of the service accounts, issues access tokens for their
+// credentials and authenticates the requests made with them.
+type Service struct {
+	cfg             *setting.Cfg
+	store           db.DB
+	accessControl   accesscontrol.AccessControl
+	serviceAccounts serviceaccounts.Service
+	userService     user.Service
+	jwtService      auth.JWTVerifierService
+	cache           *localcache.CacheService
+	log             log.Logger
+	now             func() time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, authnService authn.Service, accessControl accesscontrol.AccessControl,
+	serviceAccounts serviceaccounts.Service, userService user.Service, jwtService auth.JWTVerifierService,
+	routeRegister routing.RouteRegister) *Service {
+	s := &Service{
+		cfg:             cfg,
+		store:           sqlStore,
+		accessControl:   accessControl,
+		serviceAccounts: serviceAccounts,
+		userService:     userService,
+		jwtService:      jwtService,
+		cache:           localcache.New(clientCheckInterval, 2*clientCheckInterval),
+		log:             log.New("client-credentials"),
+		now:             time.Now,
+	}
+
+	if !cfg.ClientCredentialsEnabled {
+		return s
+	}
+
+	authnService.RegisterClient(s)
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+// Create adds a client to the service account and returns it with its secret
+func (s *Service) Create(ctx context.Context, cmd *CreateClientCommand) (*Client, string, error) {
+	if strings.TrimSpace(cmd.Name) == "" {
+		return nil, "", ErrInvalidName.Errorf("the name of the client is empty")
+	}
+	if _, err := s.serviceAccounts.RetrieveServiceAccount(ctx, cmd.OrgID, cmd.ServiceAccountID); err != nil {
+		return nil, "", err
+	}
+
+	secret, err := util.GetRandomString(secretLength)
+	if err != nil {
+		return nil, "", err
+	}
+	client := &Client{
+		OrgID:            cmd.OrgID,
+		ServiceAccountID: cmd.ServiceAccountID,
+		Name:             cmd.Name,
+		JWTSubject:       cmd.JWTSubject,
+		ClientID:         util.GenerateShortUID(),
+		SecretHash:       hashSecret(secret),
+		Created:          s.now(),
+	}
+	if err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(client)
+		return err
+	}); err != nil {
+		return nil, "", err
+	}
+
+	s.log.FromContext(ctx).Info("Created OAuth2 client", "clientId", client.ClientID, "serviceAccountId", client.ServiceAccountID)
+	return client, secret, nil
+}
+
+// List returns the clients of the service account
+func (s *Service) List(ctx context.Context, orgID, serviceAccountID int64) ([]*Client, error) {
+	result := make([]*Client, 0)
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND service_account_id = ?", orgID, serviceAccountID).Asc("id").Find(&result)
+	})
+	return result, err
+}
+
+// Delete removes the client, its access tokens are rejected within clientCheckInterval
+func (s *Service) Delete(ctx context.Context, cmd *DeleteClientCommand) error {
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		deleted, err := sess.Where("org_id = ? AND service_account_id = ? AND client_id = ?", cmd.OrgID, cmd.ServiceAccountID, cmd.ClientID).
+			Delete(&Client{})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return ErrClientNotFound.Errorf("OAuth2 client %s not found", cmd.ClientID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cache.Delete(clientCacheKey(cmd.ClientID))
+	s.log.FromContext(ctx).Info("Deleted OAuth2 client", "clientId", cmd.ClientID, "serviceAccountId", cmd.ServiceAccountID)
+	return nil
+}
+
+// Exchange returns an access token for the credentials of a client, the client credentials grant of OAuth2
+func (s *Service) Exchange(ctx context.Context, clientID, secret string) (*AccessToken, error) {
+	client, err := s.getClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashSecret(secret))) != 1 {
+		return nil, ErrInvalidClient.Errorf("invalid credentials for OAuth2 client %s", clientID)
+	}
+	return s.issue(ctx, client)
+}
+
+// ExchangeJWT returns an access token for a JWT of the trusted issuer of the JWT authentication, the
+// JWT bearer grant of OAuth2. The subject of the JWT must be the one trusted by the client.
+func (s *Service) ExchangeJWT(ctx context.Context, clientID, assertion string) (*AccessToken, error) {
+	client, err := s.getClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, ErrInvalidClient.Errorf("OAuth2 client %s not found", clientID)
+	}
+
+	claims, err := s.jwtService.Verify(ctx, assertion)
+	if err != nil {
+		return nil, ErrInvalidGrant.Errorf("invalid JWT for OAuth2 client %s: %w", clientID, err)
+	}
+	subject, _ := claims["sub"].(string)
+	if client.JWTSubject == "" || subtle.ConstantTimeCompare([]byte(client.JWTSubject), []byte(subject)) != 1 {
+		return nil, ErrInvalidGrant.Errorf("the subject %q of the JWT isn't trusted by OAuth2 client %s", subject, clientID)
+	}
+	return s.issue(ctx, client)
+}
+
+// issue returns an access token with the permissions of the service account of the client
+func (s *Service) issue(ctx context.Context, client *Client) (*AccessToken, error) {
+	if _, err := s.getServiceAccount(ctx, client.OrgID, client.ServiceAccountID); err != nil {
+		return nil, ErrInvalidClient.Errorf("service account of OAuth2 client %s: %w", client.ClientID, err)
+	}
+
+	now := s.now()
+	expires := now.Add(s.cfg.ClientCredentialsTokenTTL).Truncate(time.Second)
+	token, err := signToken(s.cfg.SecretKey, claims{
+		ClientID:         client.ClientID,
+		OrgID:            client.OrgID,
+		ServiceAccountID: client.ServiceAccountID,
+		Expires:          expires.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.ID(client.ID).Cols("last_used").Update(&Client{LastUsed: &now})
+		return err
+	}); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to record the use of the OAuth2 client", "clientId", client.ClientID, "error", err)
+	}
+
+	s.log.FromContext(ctx).Info("Issued access token", "clientId", client.ClientID, "serviceAccountId", client.ServiceAccountID, "expires", expires)
+	return &AccessToken{Token: token, Expires: expires}, nil
+}
+
+func (s *Service) Name() string {
+	return ClientName
+}
+
+func (s *Service) Priority() uint {
+	return clientPriority
+}
+
+func (s *Service) Test(ctx context.Context, r *authn.Request) bool {
+	return strings.HasPrefix(getTokenFromRequest(r), TokenPrefix)
+}
+
+// Authenticate returns the identity of the service account of the access token
+func (s *Service) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	c, err := verifyToken(s.cfg.SecretKey, getTokenFromRequest(r), s.now())
+	if err != nil {
+		return nil, ErrInvalidToken.Errorf("invalid access token: %w", err)
+	}
+	if r.OrgID == 0 {
+		r.OrgID = c.OrgID
+	} else if r.OrgID != c.OrgID {
+		return nil, ErrInvalidToken.Errorf("access token does not belong to organization %d", r.OrgID)
+	}
+	if err := s.checkClient(ctx, c.ClientID); err != nil {
+		return nil, err
+	}
+
+	usr, err := s.getServiceAccount(ctx, c.OrgID, c.ServiceAccountID)
+	if err != nil {
+		return nil, ErrInvalidToken.Errorf("service account of the access token: %w", err)
+	}
+	return authn.IdentityFromSignedInUser(authn.NamespacedID(authn.NamespaceServiceAccount, usr.UserID), usr,
+		authn.ClientParams{SyncPermissions: true}, AuthModule), nil
+}
+
+// getClient returns the client with the ID, or nil if there is none
+func (s *Service) getClient(ctx context.Context, clientID string) (*Client, error) {
+	var client Client
+	var found bool
+	if err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Where("client_id = ?", clientID).Get(&client)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &client, nil
+}
+
+// checkClient returns an error if the client was deleted, it's only checked once per clientCheckInterval
+func (s *Service) checkClient(ctx context.Context, clientID string) error {
+	if _, ok := s.cache.Get(clientCacheKey(clientID)); ok {
+		return nil
+	}
+
+	var found bool
+	if err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Where("client_id = ?", clientID).Exist(&Client{})
+		return err
+	}); err != nil {
+		return err
+	}
+	if !found {
+		return ErrInvalidToken.Errorf("OAuth2 client %s was deleted", clientID)
+	}
+	s.cache.Set(clientCacheKey(clientID), true, clientCheckInterval)
+	return nil
+}
+
+func (s *Service) getServiceAccount(ctx context.Context, orgID, serviceAccountID int64) (*user.SignedInUser, error) {
+	usr, err := s.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{UserID: serviceAccountID, OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	if !usr.IsServiceAccount || usr.IsDisabled {
+		return nil, fmt.Errorf("service account %d is disabled", serviceAccountID)
+	}
+	return usr, nil
+}
+
+func getTokenFromRequest(r *authn.Request) string {
+	if r.HTTPRequest == nil {
+		return ""
+	}
+	token, ok := strings.CutPrefix(r.HTTPRequest.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return token
+}
+
+func clientCacheKey(clientID string) string {
+	return fmt.Sprintf("client-credentials-%s", clientID)
+}
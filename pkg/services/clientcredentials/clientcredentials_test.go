@@ -0,0 +1,173 @@
+package clientcredentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/auth/jwt"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	satests "github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestToken(t *testing.T) {
+	now := time.Now()
+	token, err := signToken("secret", claims{ClientID: "abc", OrgID: 1, ServiceAccountID: 2, Expires: now.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, TokenPrefix))
+
+	c, err := verifyToken("secret", token, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), c.ServiceAccountID)
+
+	_, err = verifyToken("other", token, now)
+	assert.ErrorIs(t, err, errInvalidToken)
+	_, err = verifyToken("secret", token, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, errInvalidToken)
+	_, err = verifyToken("secret", strings.TrimPrefix(token, TokenPrefix), now)
+	assert.ErrorIs(t, err, errInvalidToken)
+}
+
+func TestIntegrationClientCredentials(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	cfg := setting.NewCfg()
+	cfg.SecretKey = "secret"
+	cfg.ClientCredentialsEnabled = true
+	cfg.ClientCredentialsTokenTTL = time.Hour
+	cfg.JWTAuth.Enabled = true
+	users := &usertest.FakeUserService{ExpectedSignedInUser: &user.SignedInUser{UserID: 2, OrgID: 1, Login: "sa-deploy", IsServiceAccount: true}}
+	jwtService := &jwt.FakeJWTService{VerifyProvider: func(ctx context.Context, token string) (jwt.JWTClaims, error) {
+		if token != "valid" {
+			return nil, errors.New("invalid signature")
+		}
+		return jwt.JWTClaims{"sub": "repo:grafana/deploy"}, nil
+	}}
+	routeRegister := routing.NewRouteRegister()
+	s := ProvideService(cfg, db.InitTestDB(t), &authntest.FakeService{}, actest.FakeAccessControl{ExpectedEvaluate: true},
+		&satests.FakeServiceAccountService{ExpectedServiceAccountProfile: &serviceaccounts.ServiceAccountProfileDTO{Id: 2}}, users, jwtService, routeRegister)
+	start := time.Now().Truncate(time.Second)
+	now := start
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+	server := webtest.NewServer(t, routeRegister)
+
+	requestToken := func(form url.Values) (*http.Response, map[string]any) {
+		req := server.NewPostRequest("/oauth2/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := server.Send(req)
+		require.NoError(t, err)
+		body := map[string]any{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.NoError(t, resp.Body.Close())
+		return resp, body
+	}
+	authenticate := func(token string) (*authn.Identity, error) {
+		req, err := http.NewRequest(http.MethodGet, "/api/search", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r := &authn.Request{HTTPRequest: req}
+		require.True(t, s.Test(ctx, r))
+		return s.Authenticate(ctx, r)
+	}
+
+	_, _, err := s.Create(ctx, &CreateClientCommand{OrgID: 1, ServiceAccountID: 2})
+	assert.ErrorIs(t, err, ErrInvalidName)
+	client, secret, err := s.Create(ctx, &CreateClientCommand{OrgID: 1, ServiceAccountID: 2, Name: "deploy"})
+	require.NoError(t, err)
+
+	var token string
+	t.Run("should issue an access token for the credentials of the client", func(t *testing.T) {
+		resp, body := requestToken(url.Values{"grant_type": {"client_credentials"}, "client_id": {client.ClientID}, "client_secret": {secret}})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+		assert.Equal(t, "Bearer", body["token_type"])
+		assert.Equal(t, float64(3600), body["expires_in"])
+		token = body["access_token"].(string)
+
+		clients, err := s.List(ctx, 1, 2)
+		require.NoError(t, err)
+		require.Len(t, clients, 1)
+		assert.NotNil(t, clients[0].LastUsed)
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		resp, body := requestToken(url.Values{"grant_type": {"client_credentials"}, "client_id": {client.ClientID}, "client_secret": {"wrong"}})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, "invalid_client", body["error"])
+
+		resp, body = requestToken(url.Values{"grant_type": {"password"}, "client_id": {client.ClientID}, "client_secret": {secret}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "unsupported_grant_type", body["error"])
+	})
+
+	t.Run("should issue an access token for a JWT with the subject trusted by the client", func(t *testing.T) {
+		federated, _, err := s.Create(ctx, &CreateClientCommand{OrgID: 1, ServiceAccountID: 2, Name: "ci", JWTSubject: "repo:grafana/deploy"})
+		require.NoError(t, err)
+
+		resp, body := requestToken(url.Values{"grant_type": {grantTypeJWTBearer}, "client_id": {federated.ClientID}, "assertion": {"valid"}})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		_, err = authenticate(body["access_token"].(string))
+		require.NoError(t, err)
+
+		resp, body = requestToken(url.Values{"grant_type": {grantTypeJWTBearer}, "client_id": {federated.ClientID}, "assertion": {"forged"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "invalid_grant", body["error"])
+
+		// the client without a trusted subject can't exchange JWTs
+		resp, body = requestToken(url.Values{"grant_type": {grantTypeJWTBearer}, "client_id": {client.ClientID}, "assertion": {"valid"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "invalid_grant", body["error"])
+	})
+
+	t.Run("should authenticate the service account with the access token", func(t *testing.T) {
+		identity, err := authenticate(token)
+		require.NoError(t, err)
+		assert.Equal(t, authn.NamespacedID(authn.NamespaceServiceAccount, 2), identity.ID)
+		assert.Equal(t, AuthModule, identity.AuthenticatedBy)
+		assert.True(t, identity.ClientParams.SyncPermissions)
+
+		_, err = authenticate(token + "x")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("should reject the access tokens once expired", func(t *testing.T) {
+		now = start.Add(2 * time.Hour)
+		defer func() { now = start }()
+		_, err := authenticate(token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("should reject the access tokens of deleted clients", func(t *testing.T) {
+		err := s.Delete(ctx, &DeleteClientCommand{OrgID: 1, ServiceAccountID: 3, ClientID: client.ClientID})
+		assert.ErrorIs(t, err, ErrClientNotFound)
+
+		require.NoError(t, s.Delete(ctx, &DeleteClientCommand{OrgID: 1, ServiceAccountID: 2, ClientID: client.ClientID}))
+		_, err = authenticate(token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
@@ -0,0 +1,54 @@
+package clientcredentials
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrClientNotFound = errutil.NotFound("clientcredentials.not-found", errutil.WithPublicMessage("OAuth2 client not found"))
+	ErrInvalidClient  = errutil.Unauthorized("clientcredentials.invalid-client", errutil.WithPublicMessage("Invalid client credentials"))
+	ErrInvalidGrant   = errutil.BadRequest("clientcredentials.invalid-grant", errutil.WithPublicMessage("Invalid assertion"))
+	ErrInvalidToken   = errutil.Unauthorized("clientcredentials.invalid-token", errutil.WithPublicMessage("Invalid access token"))
+	ErrInvalidName    = errutil.BadRequest("clientcredentials.invalid-name", errutil.WithPublicMessage("The name of the client is required"))
+)
+
+// Client is an OAuth2 client of a service account, which exchanges its credentials for access tokens
+// with the permissions of the service account
+type Client struct {
+	ID               int64  `json:"id" xorm:"pk autoincr 'id'"`
+	OrgID            int64  `json:"orgId" xorm:"org_id"`
+	ServiceAccountID int64  `json:"serviceAccountId" xorm:"service_account_id"`
+	Name             string `json:"name" xorm:"name"`
+	ClientID         string `json:"clientId" xorm:"client_id"`
+	// SecretHash is the SHA-256 hash of the secret, the secret itself is only returned when the client is created
+	SecretHash string `json:"-" xorm:"secret_hash"`
+	// JWTSubject is the subject of the JWTs of the trusted issuer the client can exchange for access tokens
+	JWTSubject string     `json:"jwtSubject,omitempty" xorm:"jwt_subject"`
+	Created    time.Time  `json:"created" xorm:"created"`
+	LastUsed   *time.Time `json:"lastUsed" xorm:"last_used"`
+}
+
+func (Client) TableName() string {
+	return "oauth_client_credentials"
+}
+
+type CreateClientCommand struct {
+	OrgID            int64
+	ServiceAccountID int64
+	Name             string
+	JWTSubject       string
+}
+
+type DeleteClientCommand struct {
+	OrgID            int64
+	ServiceAccountID int64
+	ClientID         string
+}
+
+// AccessToken is issued to a client for its credentials
+type AccessToken struct {
+	Token   string
+	Expires time.Time
+}
@@ -0,0 +1,110 @@
+package concurrencylimit
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+var settingsScope = accesscontrol.Scope("settings", "concurrency_limits", "*")
+
+func (s *Service) registerAPIEndpoints(ac accesscontrol.AccessControl, routeRegister routing.RouteRegister) {
+	auth := accesscontrol.Middleware(ac)
+	routeRegister.Group("/api/admin/concurrency-limits", func(r routing.RouteRegister) {
+		r.Get("/", auth(accesscontrol.EvalPermission(accesscontrol.ActionSettingsRead, settingsScope)), routing.Wrap(s.handleGetLimits))
+		r.Put("/:subsystem", auth(accesscontrol.EvalPermission(accesscontrol.ActionSettingsWrite, settingsScope)), routing.Wrap(s.handleSetLimit))
+		r.Delete("/:subsystem", auth(accesscontrol.EvalPermission(accesscontrol.ActionSettingsWrite, settingsScope)), routing.Wrap(s.handleResetLimit))
+	}, middleware.ReqSignedIn)
+}
+
+// swagger:route GET /admin/concurrency-limits admin getConcurrencyLimits
+//
+// # Get the concurrency limits of the subsystems
+//
+// Produces:
+// - application/json
+//
+// Responses:
+//
+//	200: getConcurrencyLimitsResponse
+//	401: unauthorisedError
+//	403: forbiddenError
+func (s *Service) handleGetLimits(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, s.GetLimits())
+}
+
+// swagger:route PUT /admin/concurrency-limits/{subsystem} admin setConcurrencyLimit
+//
+// # Set the concurrency limit of a subsystem
+//
+// The limit takes precedence over the configuration file on all the instances.
+//
+// Responses:
+//
+//	200: okResponse
+//	400: badRequestError
+//	401: unauthorisedError
+//	403: forbiddenError
+//	404: notFoundError
+//	500: internalServerError
+func (s *Service) handleSetLimit(c *contextmodel.ReqContext) response.Response {
+	cmd := SetLimitCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if err := s.SetLimit(c.Req.Context(), web.Params(c.Req)[":subsystem"], cmd.Limit); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to set concurrency limit", err)
+	}
+	return response.Success("Concurrency limit updated")
+}
+
+// swagger:route DELETE /admin/concurrency-limits/{subsystem} admin resetConcurrencyLimit
+//
+// # Reset the concurrency limit of a subsystem to the limit of the configuration file
+//
+// Responses:
+//
+//	200: okResponse
+//	401: unauthorisedError
+//	403: forbiddenError
+//	404: notFoundError
+//	500: internalServerError
+func (s *Service) handleResetLimit(c *contextmodel.ReqContext) response.Response {
+	if err := s.ResetLimit(c.Req.Context(), web.Params(c.Req)[":subsystem"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to reset concurrency limit", err)
+	}
+	return response.Success("Concurrency limit reset")
+}
+
+type SetLimitCommand struct {
+	// Limit is the maximum number of concurrent operations, 0 means unlimited
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters setConcurrencyLimit
+type SetConcurrencyLimitParams struct {
+	// in:path
+	// required:true
+	Subsystem string `json:"subsystem"`
+	// in:body
+	// required:true
+	Body SetLimitCommand `json:"body"`
+}
+
+// swagger:parameters resetConcurrencyLimit
+type ResetConcurrencyLimitParams struct {
+	// in:path
+	// required:true
+	Subsystem string `json:"subsystem"`
+}
+
+// swagger:response getConcurrencyLimitsResponse
+type GetConcurrencyLimitsResponse struct {
+	// in:body
+	Body []Limit `json:"body"`
+}
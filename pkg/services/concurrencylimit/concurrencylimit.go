@@ -0,0 +1,288 @@
+package concurrencylimit
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	SubsystemRender          = "render"
+	SubsystemDataSourceProxy = "datasource_proxy"
+	SubsystemAlertEvaluation = "alert_evaluation"
+
+	limitsNamespace = "concurrency_limits"
+	limitsKey       = "limits"
+)
+
+var (
+	ErrUnknownSubsystem = errutil.NotFound("concurrencylimit.unknown-subsystem")
+	ErrInvalidLimit     = errutil.BadRequest("concurrencylimit.invalid-limit")
+	ErrLimitReached     = errutil.TooManyRequests("concurrencylimit.limit-reached", errutil.WithPublicMessage("Too many concurrent requests, try again later"))
+)
+
+// Limit is the concurrency limit of a subsystem
+type Limit struct {
+	Subsystem string `json:"subsystem"`
+	// Limit is the maximum number of concurrent operations, 0 means unlimited
+	Limit int `json:"limit"`
+	// Default is the limit of the configuration file
+	Default int `json:"default"`
+	// Overridden is true when the limit was set through the API
+	Overridden bool `json:"overridden"`
+	// InFlight is the number of operations in progress on this instance
+	InFlight int `json:"inFlight"`
+}
+
+type subsystem struct {
+	inFlight int
+	// released is closed and replaced whenever a slot may have become available
+	released chan struct{}
+}
+
+// Service holds the concurrency limits of the subsystems. The limits set through the admin API are
+// stored in the database and take precedence over the configuration file, other instances load them
+// every reload_interval.
+type Service struct {
+	cfg     *setting.Cfg
+	kvStore *kvstore.NamespacedKVStore
+	log     log.Logger
+
+	// updateMu serializes the updates of the stored limits
+	updateMu sync.Mutex
+	// mu protects the limits and the slots of the subsystems
+	mu         sync.Mutex
+	defaults   map[string]int
+	overrides  map[string]int
+	subsystems map[string]*subsystem
+}
+
+func ProvideService(cfg *setting.Cfg, kvStore kvstore.KVStore, ac accesscontrol.AccessControl, routeRegister routing.RouteRegister, ng *ngalert.AlertNG) *Service {
+	s := &Service{
+		cfg:     cfg,
+		kvStore: kvstore.WithNamespace(kvStore, 0, limitsNamespace),
+		log:     log.New("concurrencylimit"),
+		defaults: map[string]int{
+			SubsystemRender:          cfg.RendererConcurrentRequestLimit,
+			SubsystemDataSourceProxy: cfg.ConcurrencyLimits.DataSourceProxy,
+			SubsystemAlertEvaluation: cfg.ConcurrencyLimits.AlertEvaluation,
+		},
+		overrides:  map[string]int{},
+		subsystems: map[string]*subsystem{},
+	}
+	for name := range s.defaults {
+		s.subsystems[name] = &subsystem{released: make(chan struct{})}
+	}
+
+	if err := s.reload(context.Background()); err != nil {
+		s.log.Warn("Failed to load concurrency limits", "error", err)
+	}
+
+	if ng != nil {
+		ng.SetEvaluationLimiter(func(ctx context.Context) (func(), error) {
+			return s.Acquire(ctx, SubsystemAlertEvaluation)
+		})
+	}
+
+	s.registerAPIEndpoints(ac, routeRegister)
+	return s
+}
+
+// Run loads the limits changed by other instances
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.ConcurrencyLimits.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.reload(ctx); err != nil {
+				s.log.Warn("Failed to reload concurrency limits", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) reload(ctx context.Context) error {
+	value, ok, err := s.kvStore.Get(ctx, limitsKey)
+	if err != nil {
+		return err
+	}
+
+	overrides := map[string]int{}
+	if ok {
+		if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides = overrides
+	s.notifyAll()
+	return nil
+}
+
+// Limit returns the current limit of the subsystem, 0 means unlimited
+func (s *Service) Limit(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limit(name)
+}
+
+func (s *Service) limit(name string) int {
+	if limit, ok := s.overrides[name]; ok {
+		return limit
+	}
+	return s.defaults[name]
+}
+
+// TryAcquire takes a slot of the subsystem without waiting, it fails with ErrLimitReached when
+// all the slots are taken. The returned func must be called to release the slot.
+func (s *Service) TryAcquire(name string) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subsystems[name]
+	if !ok {
+		return nil, ErrUnknownSubsystem.Errorf("unknown subsystem %q", name)
+	}
+	if limit := s.limit(name); limit > 0 && sub.inFlight >= limit {
+		return nil, ErrLimitReached.Errorf("%s concurrency limit of %d reached", name, limit)
+	}
+	sub.inFlight++
+	return s.releaseFunc(sub), nil
+}
+
+// Acquire takes a slot of the subsystem, waiting for one to be released if needed.
+// The returned func must be called to release the slot.
+func (s *Service) Acquire(ctx context.Context, name string) (func(), error) {
+	for {
+		s.mu.Lock()
+		sub, ok := s.subsystems[name]
+		if !ok {
+			s.mu.Unlock()
+			return nil, ErrUnknownSubsystem.Errorf("unknown subsystem %q", name)
+		}
+		if limit := s.limit(name); limit <= 0 || sub.inFlight < limit {
+			sub.inFlight++
+			s.mu.Unlock()
+			return s.releaseFunc(sub), nil
+		}
+		released := sub.released
+		s.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (s *Service) releaseFunc(sub *subsystem) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			sub.inFlight--
+			close(sub.released)
+			sub.released = make(chan struct{})
+		})
+	}
+}
+
+// notifyAll wakes up the operations waiting for a slot after the limits changed
+func (s *Service) notifyAll() {
+	for _, sub := range s.subsystems {
+		close(sub.released)
+		sub.released = make(chan struct{})
+	}
+}
+
+// GetLimits returns the limits of all the subsystems
+func (s *Service) GetLimits() []Limit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limits := make([]Limit, 0, len(s.subsystems))
+	for name, sub := range s.subsystems {
+		_, overridden := s.overrides[name]
+		limits = append(limits, Limit{
+			Subsystem:  name,
+			Limit:      s.limit(name),
+			Default:    s.defaults[name],
+			Overridden: overridden,
+			InFlight:   sub.inFlight,
+		})
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Subsystem < limits[j].Subsystem })
+	return limits
+}
+
+// SetLimit stores the limit of the subsystem, it takes precedence over the configuration file
+func (s *Service) SetLimit(ctx context.Context, name string, limit int) error {
+	if limit < 0 {
+		return ErrInvalidLimit.Errorf("limit must be 0 or greater, got %d", limit)
+	}
+	return s.update(ctx, name, func(overrides map[string]int) {
+		overrides[name] = limit
+	})
+}
+
+// ResetLimit removes the limit set through the API, the subsystem uses the limit of the configuration file
+func (s *Service) ResetLimit(ctx context.Context, name string) error {
+	return s.update(ctx, name, func(overrides map[string]int) {
+		delete(overrides, name)
+	})
+}
+
+func (s *Service) update(ctx context.Context, name string, fn func(overrides map[string]int)) error {
+	if _, ok := s.defaults[name]; !ok {
+		return ErrUnknownSubsystem.Errorf("unknown subsystem %q", name)
+	}
+
+	// the database is accessed without holding mu so that updates don't block the subsystems
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	// starts from the stored limits, which can have been changed by other instances since the last reload
+	overrides := map[string]int{}
+	stored, ok, err := s.kvStore.Get(ctx, limitsKey)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if err := json.Unmarshal([]byte(stored), &overrides); err != nil {
+			return err
+		}
+	}
+	fn(overrides)
+
+	value, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+	if err := s.kvStore.Set(ctx, limitsKey, string(value)); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides = overrides
+	s.notifyAll()
+	return nil
+}
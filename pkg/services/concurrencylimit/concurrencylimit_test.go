@@ -0,0 +1,134 @@
+package concurrencylimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func setupTestService(t *testing.T, kvStore kvstore.KVStore) *Service {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.RendererConcurrentRequestLimit = 30
+	cfg.ConcurrencyLimits.DataSourceProxy = 2
+	return ProvideService(cfg, kvStore, actest.FakeAccessControl{}, routing.NewRouteRegister(), nil)
+}
+
+func TestService_TryAcquire(t *testing.T) {
+	s := setupTestService(t, kvstore.NewFakeKVStore())
+
+	release1, err := s.TryAcquire(SubsystemDataSourceProxy)
+	require.NoError(t, err)
+	release2, err := s.TryAcquire(SubsystemDataSourceProxy)
+	require.NoError(t, err)
+
+	_, err = s.TryAcquire(SubsystemDataSourceProxy)
+	assert.ErrorIs(t, err, ErrLimitReached)
+
+	release1()
+	// releasing twice doesn't free another slot
+	release1()
+	release3, err := s.TryAcquire(SubsystemDataSourceProxy)
+	require.NoError(t, err)
+	_, err = s.TryAcquire(SubsystemDataSourceProxy)
+	assert.ErrorIs(t, err, ErrLimitReached)
+
+	release2()
+	release3()
+
+	t.Run("should not limit subsystems with a limit of 0", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			_, err := s.TryAcquire(SubsystemAlertEvaluation)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("should fail for unknown subsystems", func(t *testing.T) {
+		_, err := s.TryAcquire("slack")
+		assert.ErrorIs(t, err, ErrUnknownSubsystem)
+	})
+}
+
+func TestService_Acquire(t *testing.T) {
+	s := setupTestService(t, kvstore.NewFakeKVStore())
+	require.NoError(t, s.SetLimit(context.Background(), SubsystemAlertEvaluation, 1))
+
+	release, err := s.Acquire(context.Background(), SubsystemAlertEvaluation)
+	require.NoError(t, err)
+
+	t.Run("should stop waiting when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := s.Acquire(ctx, SubsystemAlertEvaluation)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should wait for a slot to be released", func(t *testing.T) {
+		acquired := make(chan struct{})
+		go func() {
+			release, err := s.Acquire(context.Background(), SubsystemAlertEvaluation)
+			assert.NoError(t, err)
+			release()
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("slot acquired before it was released")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		release()
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("slot not acquired after it was released")
+		}
+	})
+}
+
+func TestService_SetLimit(t *testing.T) {
+	kvStore := kvstore.NewFakeKVStore()
+	s := setupTestService(t, kvStore)
+	ctx := context.Background()
+
+	assert.Equal(t, 30, s.Limit(SubsystemRender))
+	require.NoError(t, s.SetLimit(ctx, SubsystemRender, 5))
+	assert.Equal(t, 5, s.Limit(SubsystemRender))
+
+	t.Run("should load the limits set by other instances", func(t *testing.T) {
+		other := setupTestService(t, kvStore)
+		assert.Equal(t, 5, other.Limit(SubsystemRender))
+
+		require.NoError(t, other.SetLimit(ctx, SubsystemDataSourceProxy, 10))
+		require.NoError(t, s.reload(ctx))
+		assert.Equal(t, 10, s.Limit(SubsystemDataSourceProxy))
+		assert.Equal(t, 5, s.Limit(SubsystemRender))
+	})
+
+	t.Run("should reset the limit to the configuration file", func(t *testing.T) {
+		require.NoError(t, s.ResetLimit(ctx, SubsystemRender))
+		assert.Equal(t, 30, s.Limit(SubsystemRender))
+
+		for _, limit := range s.GetLimits() {
+			if limit.Subsystem == SubsystemRender {
+				assert.False(t, limit.Overridden)
+				assert.Equal(t, 30, limit.Default)
+			}
+		}
+	})
+
+	t.Run("should reject invalid limits", func(t *testing.T) {
+		assert.ErrorIs(t, s.SetLimit(ctx, SubsystemRender, -1), ErrInvalidLimit)
+		assert.ErrorIs(t, s.SetLimit(ctx, "slack", 1), ErrUnknownSubsystem)
+	})
+}
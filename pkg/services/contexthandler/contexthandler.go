@@ -124,6 +124,15 @@ func (h *ContextHandler) Middleware(next http.Handler) http.Handler {
 		}
 
 		reqContext.Logger = reqContext.Logger.New("userId", reqContext.UserID, "orgId", reqContext.OrgID, "uname", reqContext.Login)
+		if reqContext.SignedInUser.ImpersonatorID != 0 {
+			// tag everything done while impersonating with the admin as well
+			reqContext.Logger = reqContext.Logger.New("impersonatorId", reqContext.SignedInUser.ImpersonatorID,
+				"impersonatorLogin", reqContext.SignedInUser.ImpersonatorLogin)
+			span.AddEvent("impersonator", trace.WithAttributes(
+				attribute.String("uname", reqContext.SignedInUser.ImpersonatorLogin),
+				attribute.Int64("userId", reqContext.SignedInUser.ImpersonatorID),
+			))
+		}
 		span.AddEvent("user", trace.WithAttributes(
 			attribute.String("uname", reqContext.Login),
 			attribute.Int64("orgId", reqContext.OrgID),
@@ -0,0 +1,146 @@
+package dashboardbulkdelete
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware/requestmeta"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(s.accessControl)
+	canDelete := authorize(accesscontrol.EvalPermission(dashboards.ActionDashboardsDelete))
+
+	routeRegister.Group("/api/dashboards/bulk-delete", func(r routing.RouteRegister) {
+		r.Post("/", canDelete, routing.Wrap(s.bulkDeleteHandler))
+		r.Get("/:uid", canDelete, routing.Wrap(s.getJobHandler))
+		r.Post("/:uid/undo", canDelete, routing.Wrap(s.undoHandler))
+	}, requestmeta.SetOwner(requestmeta.TeamBackend))
+}
+
+type BulkDeleteForm struct {
+	Query Query `json:"query"`
+	// DryRun only lists the dashboards the query would delete, with the token confirming their deletion
+	DryRun bool `json:"dryRun"`
+	// ConfirmToken is the token of the dry run, required to delete the dashboards
+	ConfirmToken string `json:"confirmToken"`
+}
+
+// swagger:route POST /dashboards/bulk-delete dashboards bulkDeleteDashboards
+//
+// Delete the dashboards matching a query.
+//
+// Run the deletion as a dry run first: it lists the dashboards the query matches that the user can delete,
+// the others with the reason they're skipped, and returns a confirmation token. The deletion itself requires
+// the token and is rejected when the dashboards matching the query changed since the dry run. It runs in the
+// background, the response is the job of the deletion to follow its progress.
+//
+// Responses:
+// 200: bulkDeleteDryRunResponse
+// 202: bulkDeleteJobResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) bulkDeleteHandler(c *contextmodel.ReqContext) response.Response {
+	form := BulkDeleteForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+
+	if form.DryRun {
+		result, err := s.DryRun(c.Req.Context(), c.SignedInUser, form.Query)
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to evaluate the bulk deletion", err)
+		}
+		return response.JSON(http.StatusOK, result)
+	}
+
+	if form.ConfirmToken == "" {
+		return response.Error(http.StatusBadRequest, "A confirmation token from a dry run is required", nil)
+	}
+	job, err := s.Start(c.Req.Context(), c.SignedInUser, form.Query, form.ConfirmToken)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to start the bulk deletion", err)
+	}
+	return response.JSON(http.StatusAccepted, job)
+}
+
+// swagger:route GET /dashboards/bulk-delete/{uid} dashboards getBulkDeleteDashboardsJob
+//
+// Get the progress of a bulk deletion of dashboards.
+//
+// Responses:
+// 200: bulkDeleteJobResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) getJobHandler(c *contextmodel.ReqContext) response.Response {
+	job, err := s.GetJob(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the bulk deletion", err)
+	}
+	return response.JSON(http.StatusOK, job)
+}
+
+// swagger:route POST /dashboards/bulk-delete/{uid}/undo dashboards undoBulkDeleteDashboards
+//
+// Restore the dashboards deleted by a bulk deletion.
+//
+// A finished bulk deletion can be undone until the end of its undo window. The dashboards are restored with
+// their UID and JSON into their folder, their version history and permissions aren't restored.
+//
+// Responses:
+// 200: bulkDeleteJobResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) undoHandler(c *contextmodel.ReqContext) response.Response {
+	job, err := s.Undo(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to undo the bulk deletion", err)
+	}
+	return response.JSON(http.StatusOK, job)
+}
+
+// swagger:parameters bulkDeleteDashboards
+type BulkDeleteDashboardsParams struct {
+	// in:body
+	// required:true
+	Body BulkDeleteForm
+}
+
+// swagger:parameters getBulkDeleteDashboardsJob
+type GetBulkDeleteDashboardsJobParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters undoBulkDeleteDashboards
+type UndoBulkDeleteDashboardsParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response bulkDeleteDryRunResponse
+type BulkDeleteDryRunResponse struct {
+	// in:body
+	Body DryRunResult `json:"body"`
+}
+
+// swagger:response bulkDeleteJobResponse
+type BulkDeleteJobResponse struct {
+	// in:body
+	Body Job `json:"body"`
+}
@@ -0,0 +1,435 @@
+package dashboardbulkdelete
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/librarypanels"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// maxMatches is the most dashboards a bulk deletion deletes
+	maxMatches = 1000
+
+	// confirmTokenTTL is how long the confirmation token of a dry run can be used
+	confirmTokenTTL = 15 * time.Minute
+
+	// interruptedAfter is how long a running job can go without progress before it's considered interrupted,
+	// as it is when the instance running it stops
+	interruptedAfter = 10 * time.Minute
+
+	cleanupLockName = "dashboard-bulk-delete-cleanup"
+	cleanupInterval = time.Hour
+)
+
+// Service deletes the dashboards matching a query in bulk. The deletions are confirmed by the token of a prior
+// dry run and run in the background, the deleted dashboards are kept in a trash so that a deletion can be
+// undone until the end of its undo window.
+type Service struct {
+	cfg                   *setting.Cfg
+	store                 db.DB
+	dashboardService      dashboards.DashboardService
+	provisioningService   dashboards.DashboardProvisioningService
+	libraryElementService libraryelements.Service
+	libraryPanelService   librarypanels.Service
+	publicDashboards      publicdashboards.Service
+	accessControl         accesscontrol.AccessControl
+	serverLock            *serverlock.ServerLockService
+	log                   log.Logger
+	now                   func() time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, dashboardService dashboards.DashboardService,
+	provisioningService dashboards.DashboardProvisioningService, libraryElementService libraryelements.Service,
+	libraryPanelService librarypanels.Service, publicDashboards publicdashboards.Service, accessControl accesscontrol.AccessControl,
+	serverLock *serverlock.ServerLockService, routeRegister routing.RouteRegister) *Service {
+	s := &Service{
+		cfg:                   cfg,
+		store:                 sqlStore,
+		dashboardService:      dashboardService,
+		provisioningService:   provisioningService,
+		libraryElementService: libraryElementService,
+		libraryPanelService:   libraryPanelService,
+		publicDashboards:      publicDashboards,
+		accessControl:         accessControl,
+		serverLock:            serverLock,
+		log:                   log.New("dashboards.bulk-delete"),
+		now:                   time.Now,
+	}
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+// DryRun returns the dashboards the query would delete and the token confirming their deletion
+func (s *Service) DryRun(ctx context.Context, user identity.Requester, query Query) (*DryRunResult, error) {
+	result, _, err := s.evaluate(ctx, user, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Expires = s.now().Add(confirmTokenTTL)
+	result.ConfirmToken, err = signConfirmToken(s.cfg.SecretKey, confirmClaims{
+		OrgID:   user.GetOrgID(),
+		User:    requesterID(user),
+		Matches: hashMatches(result.Matches),
+		Expires: result.Expires.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Start checks that the query still matches the dashboards of the dry run of the token and starts deleting them
+// in the background. It returns the job of the deletion.
+func (s *Service) Start(ctx context.Context, user identity.Requester, query Query, confirmToken string) (*Job, error) {
+	claims, err := verifyConfirmToken(s.cfg.SecretKey, confirmToken, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if claims.OrgID != user.GetOrgID() || claims.User != requesterID(user) {
+		return nil, ErrInvalidToken.Errorf("confirmation token issued to another user")
+	}
+
+	result, dashs, err := s.evaluate(ctx, user, query)
+	if err != nil {
+		return nil, err
+	}
+	if hashMatches(result.Matches) != claims.Matches {
+		return nil, ErrMatchesChanged.Errorf("the dashboards matching the query changed since the dry run")
+	}
+
+	now := s.now()
+	job := &Job{
+		UID:         util.GenerateShortUID(),
+		OrgID:       user.GetOrgID(),
+		CreatedBy:   requesterID(user),
+		Status:      JobStatusRunning,
+		Total:       len(dashs),
+		Failures:    []Failure{},
+		UndoExpires: now.Add(s.cfg.DashboardBulkDeleteUndoWindow),
+	}
+	if err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(job)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Starting bulk deletion of dashboards", "jobUid", job.UID, "orgId", job.OrgID, "user", job.CreatedBy, "total", job.Total)
+	started := *job
+	// the deletion outlives the request, it's interrupted when the instance stops
+	go s.execute(context.WithoutCancel(ctx), job, dashs)
+	return &started, nil
+}
+
+// execute moves the dashboards to the trash and deletes them, updating the progress of the job
+func (s *Service) execute(ctx context.Context, job *Job, dashs []*dashboards.Dashboard) {
+	for _, dash := range dashs {
+		if err := s.deleteDashboard(ctx, job, dash); err != nil {
+			s.log.Warn("Failed to delete dashboard", "jobUid", job.UID, "dashboardUid", dash.UID, "error", err)
+			job.Failures = append(job.Failures, Failure{UID: dash.UID, Error: err.Error()})
+		} else {
+			job.Deleted++
+		}
+		if err := s.updateJob(ctx, job); err != nil {
+			s.log.Error("Failed to update the bulk deletion", "jobUid", job.UID, "error", err)
+		}
+	}
+
+	job.Status = JobStatusDone
+	if err := s.updateJob(ctx, job); err != nil {
+		s.log.Error("Failed to update the bulk deletion", "jobUid", job.UID, "error", err)
+	}
+	s.log.Info("Finished bulk deletion of dashboards", "jobUid", job.UID, "deleted", job.Deleted, "failed", len(job.Failures))
+}
+
+// deleteDashboard deletes a dashboard the way the dashboard API does, after copying it to the trash of the job
+func (s *Service) deleteDashboard(ctx context.Context, job *Job, dash *dashboards.Dashboard) error {
+	trashed := &trashedDashboard{
+		OrgID:        job.OrgID,
+		JobUID:       job.UID,
+		DashboardUID: dash.UID,
+		FolderUID:    dash.FolderUID,
+		Title:        dash.Title,
+		Data:         dash.Data,
+		Deleted:      s.now(),
+		Expires:      job.UndoExpires,
+	}
+	if err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(trashed)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to move the dashboard to the trash: %w", err)
+	}
+
+	err := s.libraryElementService.DisconnectElementsFromDashboard(ctx, dash.ID)
+	if err == nil {
+		err = s.publicDashboards.DeleteByDashboard(ctx, dash)
+	}
+	if err == nil {
+		err = s.dashboardService.DeleteDashboard(ctx, dash.ID, dash.OrgID)
+	}
+	if err != nil {
+		if err := s.deleteTrashed(ctx, trashed.ID); err != nil {
+			s.log.Error("Failed to remove the dashboard from the trash", "jobUid", job.UID, "dashboardUid", dash.UID, "error", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetJob returns a bulk deletion of the org
+func (s *Service) GetJob(ctx context.Context, orgID int64, uid string) (*Job, error) {
+	job := &Job{}
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(job)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrJobNotFound.Errorf("no bulk deletion %s", uid)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Undo restores the dashboards of a finished bulk deletion from the trash. The restored dashboards are saved as
+// new dashboards with their UIDs, their version history and permissions aren't restored.
+func (s *Service) Undo(ctx context.Context, user identity.Requester, uid string) (*Job, error) {
+	job, err := s.GetJob(ctx, user.GetOrgID(), uid)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != JobStatusDone && job.Status != JobStatusInterrupted {
+		return nil, ErrCannotUndo.Errorf("the bulk deletion is %s", job.Status)
+	}
+	if !s.now().Before(job.UndoExpires) {
+		return nil, ErrCannotUndo.Errorf("the undo window of the bulk deletion is over")
+	}
+
+	var trashed []*trashedDashboard
+	if err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND job_uid = ?", job.OrgID, job.UID).Asc("id").Find(&trashed)
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, t := range trashed {
+		if err := s.restoreDashboard(ctx, user, t); err != nil {
+			s.log.Warn("Failed to restore dashboard", "jobUid", job.UID, "dashboardUid", t.DashboardUID, "error", err)
+			job.Failures = append(job.Failures, Failure{UID: t.DashboardUID, Error: err.Error()})
+			continue
+		}
+		job.Restored++
+	}
+
+	job.Status = JobStatusRestored
+	if err := s.updateJob(ctx, job); err != nil {
+		return nil, err
+	}
+	s.log.Info("Restored bulk deleted dashboards", "jobUid", job.UID, "restored", job.Restored)
+	return job, nil
+}
+
+func (s *Service) restoreDashboard(ctx context.Context, user identity.Requester, t *trashedDashboard) error {
+	data := t.Data
+	if data == nil {
+		data = simplejson.New()
+	}
+	data.Del("id")
+	data.Set("uid", t.DashboardUID)
+	dash := dashboards.NewDashboardFromJson(data)
+	dash.FolderUID = t.FolderUID
+
+	saved, err := s.dashboardService.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+		OrgID:     t.OrgID,
+		User:      user,
+		Dashboard: dash,
+		Message:   "Restored from bulk deletion",
+	}, false)
+	if err != nil {
+		return err
+	}
+	if err := s.libraryPanelService.ConnectLibraryPanelsForDashboard(ctx, user, saved); err != nil {
+		s.log.Warn("Failed to connect the library panels of the restored dashboard", "dashboardUid", saved.UID, "error", err)
+	}
+	return s.deleteTrashed(ctx, t.ID)
+}
+
+// evaluate returns the dashboards matching the query that the user can delete, and the reasons the others
+// can't be deleted
+func (s *Service) evaluate(ctx context.Context, user identity.Requester, query Query) (*DryRunResult, []*dashboards.Dashboard, error) {
+	search := &dashboards.FindPersistedDashboardsQuery{
+		Title:        query.Title,
+		OrgId:        user.GetOrgID(),
+		SignedInUser: user,
+		Type:         searchstore.TypeDashboard,
+		FolderUIDs:   query.FolderUIDs,
+		Tags:         query.Tags,
+		Limit:        maxMatches + 1,
+		Page:         1,
+		Permission:   dashboardaccess.PERMISSION_EDIT,
+	}
+	if query.StaleFor != "" {
+		staleFor, err := gtime.ParseDuration(query.StaleFor)
+		if err != nil || staleFor <= 0 {
+			return nil, nil, ErrInvalidQuery.Errorf("invalid staleFor %q", query.StaleFor)
+		}
+		search.Filters = append(search.Filters, searchstore.UpdatedBeforeFilter{Before: s.now().Add(-staleFor)})
+	}
+	if len(query.Tags) == 0 && len(query.FolderUIDs) == 0 && query.Title == "" && query.StaleFor == "" {
+		return nil, nil, ErrInvalidQuery.Errorf("the query has no criteria")
+	}
+
+	hits, err := s.dashboardService.SearchDashboards(ctx, search)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(hits) > maxMatches {
+		return nil, nil, ErrTooManyMatches.Errorf("the query matches more than %d dashboards", maxMatches)
+	}
+
+	result := &DryRunResult{Matches: []Match{}, Skipped: []Skipped{}}
+	dashs := make([]*dashboards.Dashboard, 0, len(hits))
+	for _, hit := range hits {
+		dash, reason, err := s.deletable(ctx, user, hit)
+		if err != nil {
+			return nil, nil, err
+		}
+		if reason != "" {
+			result.Skipped = append(result.Skipped, Skipped{UID: hit.UID, Title: hit.Title, Reason: reason})
+			continue
+		}
+		result.Matches = append(result.Matches, Match{UID: dash.UID, Title: dash.Title, FolderUID: dash.FolderUID, Version: dash.Version})
+		dashs = append(dashs, dash)
+	}
+	return result, dashs, nil
+}
+
+// deletable returns the dashboard of a search hit, or the reason it can't be deleted
+func (s *Service) deletable(ctx context.Context, user identity.Requester, hit *model.Hit) (*dashboards.Dashboard, string, error) {
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: user.GetOrgID(), UID: hit.UID})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return nil, "the dashboard was deleted", nil
+		}
+		return nil, "", err
+	}
+
+	g, err := guardian.NewByDashboard(ctx, dash, user.GetOrgID(), user)
+	if err != nil {
+		return nil, "", err
+	}
+	if canDelete, err := g.CanDelete(); err != nil || !canDelete {
+		return nil, "the user can't delete the dashboard", err
+	}
+
+	provisioned, err := s.provisioningService.GetProvisionedDashboardDataByDashboardUID(ctx, dash.OrgID, dash.UID)
+	if err != nil {
+		return nil, "", err
+	}
+	if provisioned != nil {
+		return nil, "the dashboard is provisioned", nil
+	}
+
+	dependents, err := s.dashboardService.GetDashboardDependents(ctx, &dashboards.GetDashboardDependentsQuery{OrgID: dash.OrgID, UID: dash.UID})
+	if err != nil {
+		return nil, "", err
+	}
+	if !dependents.IsEmpty() {
+		return nil, "alert rules or annotations reference the dashboard", nil
+	}
+	return dash, "", nil
+}
+
+func (s *Service) updateJob(ctx context.Context, job *Job) error {
+	return s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.ID(job.ID).Cols("status", "deleted", "restored", "failures", "updated").Update(job)
+		return err
+	})
+}
+
+func (s *Service) deleteTrashed(ctx context.Context, id int64) error {
+	return s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.ID(id).Delete(&trashedDashboard{})
+		return err
+	})
+}
+
+// Run cleans up the trash and the jobs every cleanupInterval, on a single instance at a time
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for {
+		err := s.serverLock.LockAndExecute(ctx, cleanupLockName, cleanupInterval, func(ctx context.Context) {
+			if err := s.Cleanup(ctx); err != nil {
+				s.log.Error("Failed to clean up the bulk deletions of dashboards", "error", err)
+			}
+		})
+		if err != nil {
+			s.log.Error("Failed to lock the clean up of the bulk deletions of dashboards", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Cleanup deletes the trashed dashboards and the jobs past their undo window, and marks the jobs that stopped
+// making progress as interrupted
+func (s *Service) Cleanup(ctx context.Context) error {
+	now := s.now()
+	return s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		trashed, err := sess.Where("expires <= ?", now).Delete(&trashedDashboard{})
+		if err != nil {
+			return err
+		}
+		jobs, err := sess.Where("undo_expires <= ? AND status <> ?", now, JobStatusRunning).Delete(&Job{})
+		if err != nil {
+			return err
+		}
+		interrupted, err := sess.Where("status = ? AND updated < ?", JobStatusRunning, now.Add(-interruptedAfter)).
+			Cols("status").Update(&Job{Status: JobStatusInterrupted})
+		if err != nil {
+			return err
+		}
+		if trashed > 0 || jobs > 0 || interrupted > 0 {
+			s.log.Debug("Cleaned up the bulk deletions of dashboards", "trashed", trashed, "jobs", jobs, "interrupted", interrupted)
+		}
+		return nil
+	})
+}
+
+// requesterID identifies the user of a confirmation token and the creator of a job
+func requesterID(user identity.Requester) string {
+	namespace, id := user.GetNamespacedID()
+	return namespace + ":" + id
+}
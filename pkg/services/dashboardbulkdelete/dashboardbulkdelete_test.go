@@ -0,0 +1,242 @@
+package dashboardbulkdelete
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/librarypanels"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestConfirmToken(t *testing.T) {
+	now := time.Now()
+	token, err := signConfirmToken("secret", confirmClaims{OrgID: 1, User: "user:1", Matches: "abc", Expires: now.Add(time.Minute).Unix()})
+	require.NoError(t, err)
+
+	c, err := verifyConfirmToken("secret", token, now)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", c.Matches)
+
+	_, err = verifyConfirmToken("other", token, now)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = verifyConfirmToken("secret", token, now.Add(2*time.Minute))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	assert.Equal(t, hashMatches([]Match{{UID: "a", Version: 1}, {UID: "b", Version: 2}}), hashMatches([]Match{{UID: "b", Version: 2}, {UID: "a", Version: 1}}))
+	assert.NotEqual(t, hashMatches([]Match{{UID: "a", Version: 1}}), hashMatches([]Match{{UID: "a", Version: 2}}))
+}
+
+func TestIntegrationBulkDelete(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	var mu sync.Mutex
+	stored := map[string]*dashboards.Dashboard{}
+	for i, uid := range []string{"a", "b", "c"} {
+		data := simplejson.NewFromAny(map[string]any{"id": int64(i + 1), "uid": uid, "title": uid, "version": 1})
+		dash := dashboards.NewDashboardFromJson(data)
+		dash.ID, dash.OrgID, dash.FolderUID = int64(i+1), 1, "folder"
+		stored[uid] = dash
+	}
+
+	dashSvc := dashboards.NewFakeDashboardService(t)
+	dashSvc.On("SearchDashboards", mock.Anything, mock.Anything).Return(func(_ context.Context, q *dashboards.FindPersistedDashboardsQuery) (model.HitList, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		hits := model.HitList{}
+		for _, uid := range []string{"a", "b", "c"} {
+			if _, ok := stored[uid]; ok {
+				hits = append(hits, &model.Hit{UID: uid, Title: uid, FolderUID: "folder"})
+			}
+		}
+		return hits, nil
+	}).Maybe()
+	dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(func(_ context.Context, q *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if dash, ok := stored[q.UID]; ok {
+			return dash, nil
+		}
+		return nil, dashboards.ErrDashboardNotFound
+	}).Maybe()
+	dashSvc.On("GetDashboardDependents", mock.Anything, mock.Anything).Return(&dashboards.DashboardDependents{}, nil).Maybe()
+	dashSvc.On("DeleteDashboard", mock.Anything, mock.Anything, int64(1)).Return(func(_ context.Context, id int64, _ int64) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for uid, dash := range stored {
+			if dash.ID == id {
+				delete(stored, uid)
+			}
+		}
+		return nil
+	}).Maybe()
+	var restored []*dashboards.SaveDashboardDTO
+	dashSvc.On("SaveDashboard", mock.Anything, mock.Anything, false).Return(func(_ context.Context, dto *dashboards.SaveDashboardDTO, _ bool) (*dashboards.Dashboard, error) {
+		restored = append(restored, dto)
+		return dto.Dashboard, nil
+	}).Maybe()
+
+	provisioning := dashboards.NewFakeDashboardProvisioning(t)
+	provisioning.On("GetProvisionedDashboardDataByDashboardUID", mock.Anything, int64(1), "b").Return(&dashboards.DashboardProvisioning{Name: "default"}, nil).Maybe()
+	provisioning.On("GetProvisionedDashboardDataByDashboardUID", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+
+	publicDashboards := publicdashboards.NewFakePublicDashboardService(t)
+	publicDashboards.On("DeleteByDashboard", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+
+	cfg := setting.NewCfg()
+	cfg.SecretKey = "secret"
+	cfg.DashboardBulkDeleteUndoWindow = time.Hour
+	sqlStore := db.InitTestDB(t)
+	s := ProvideService(cfg, sqlStore, dashSvc, provisioning, &fakeLibraryElements{}, &fakeLibraryPanels{}, publicDashboards,
+		actest.FakeAccessControl{}, serverlock.ProvideService(sqlStore, nil), routing.NewRouteRegister())
+	now := time.Now().Truncate(time.Second)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1}
+	query := Query{Tags: []string{"old"}}
+
+	t.Run("should reject queries without criteria", func(t *testing.T) {
+		_, err := s.DryRun(ctx, signedInUser, Query{})
+		assert.ErrorIs(t, err, ErrInvalidQuery)
+		_, err = s.DryRun(ctx, signedInUser, Query{StaleFor: "soon"})
+		assert.ErrorIs(t, err, ErrInvalidQuery)
+	})
+
+	var dryRun *DryRunResult
+	t.Run("should list the dashboards to delete and skip the provisioned ones", func(t *testing.T) {
+		var err error
+		dryRun, err = s.DryRun(ctx, signedInUser, query)
+		require.NoError(t, err)
+		assert.Equal(t, []Match{{UID: "a", Title: "a", FolderUID: "folder", Version: 1}, {UID: "c", Title: "c", FolderUID: "folder", Version: 1}}, dryRun.Matches)
+		assert.Equal(t, []Skipped{{UID: "b", Title: "b", Reason: "the dashboard is provisioned"}}, dryRun.Skipped)
+		assert.NotEmpty(t, dryRun.ConfirmToken)
+	})
+
+	t.Run("should only accept the token of the dry run of the user when the matches didn't change", func(t *testing.T) {
+		_, err := s.Start(ctx, signedInUser, query, "invalid")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		_, err = s.Start(ctx, &user.SignedInUser{UserID: 2, OrgID: 1}, query, dryRun.ConfirmToken)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		mu.Lock()
+		stored["c"].Version = 2
+		mu.Unlock()
+		_, err = s.Start(ctx, signedInUser, query, dryRun.ConfirmToken)
+		assert.ErrorIs(t, err, ErrMatchesChanged)
+		mu.Lock()
+		stored["c"].Version = 1
+		mu.Unlock()
+	})
+
+	var job *Job
+	t.Run("should delete the dashboards in the background", func(t *testing.T) {
+		started, err := s.Start(ctx, signedInUser, query, dryRun.ConfirmToken)
+		require.NoError(t, err)
+		assert.Equal(t, 2, started.Total)
+
+		require.Eventually(t, func() bool {
+			job, err = s.GetJob(ctx, 1, started.UID)
+			require.NoError(t, err)
+			return job.Status == JobStatusDone
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, 2, job.Deleted)
+		assert.Empty(t, job.Failures)
+		assert.Equal(t, "user:1", job.CreatedBy)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Len(t, stored, 1)
+		assert.Contains(t, stored, "b")
+
+		_, err = s.GetJob(ctx, 2, started.UID)
+		assert.ErrorIs(t, err, ErrJobNotFound)
+	})
+
+	t.Run("should restore the dashboards from the trash", func(t *testing.T) {
+		undone, err := s.Undo(ctx, signedInUser, job.UID)
+		require.NoError(t, err)
+		assert.Equal(t, JobStatusRestored, undone.Status)
+		assert.Equal(t, 2, undone.Restored)
+
+		require.Len(t, restored, 2)
+		for _, dto := range restored {
+			assert.Equal(t, int64(0), dto.Dashboard.ID)
+			assert.Equal(t, "folder", dto.Dashboard.FolderUID)
+		}
+		assert.ElementsMatch(t, []string{"a", "c"}, []string{restored[0].Dashboard.UID, restored[1].Dashboard.UID})
+
+		var trashed int64
+		require.NoError(t, sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			trashed, err = sess.Count(&trashedDashboard{})
+			return err
+		}))
+		assert.Zero(t, trashed)
+
+		_, err = s.Undo(ctx, signedInUser, job.UID)
+		assert.ErrorIs(t, err, ErrCannotUndo)
+	})
+
+	t.Run("should clean up the expired jobs and interrupt the stalled ones", func(t *testing.T) {
+		stalled := &Job{UID: "stalled", OrgID: 1, Status: JobStatusRunning, Failures: []Failure{}, UndoExpires: now.Add(time.Hour)}
+		require.NoError(t, sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			if _, err := sess.Insert(stalled); err != nil {
+				return err
+			}
+			_, err := sess.Exec("UPDATE dashboard_bulk_delete_job SET updated = ? WHERE uid = ?", now.Add(-time.Hour), stalled.UID)
+			return err
+		}))
+
+		s.now = func() time.Time { return now.Add(2 * time.Hour) }
+		require.NoError(t, s.Cleanup(ctx))
+		_, err := s.GetJob(ctx, 1, job.UID)
+		assert.ErrorIs(t, err, ErrJobNotFound)
+
+		s.now = func() time.Time { return now }
+		require.NoError(t, s.Cleanup(ctx))
+		interrupted, err := s.GetJob(ctx, 1, stalled.UID)
+		require.NoError(t, err)
+		assert.Equal(t, JobStatusInterrupted, interrupted.Status)
+	})
+}
+
+type fakeLibraryElements struct {
+	libraryelements.Service
+}
+
+func (f *fakeLibraryElements) DisconnectElementsFromDashboard(_ context.Context, _ int64) error {
+	return nil
+}
+
+type fakeLibraryPanels struct {
+	librarypanels.Service
+}
+
+func (f *fakeLibraryPanels) ConnectLibraryPanelsForDashboard(_ context.Context, _ identity.Requester, _ *dashboards.Dashboard) error {
+	return nil
+}
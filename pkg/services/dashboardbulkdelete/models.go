@@ -0,0 +1,105 @@
+package dashboardbulkdelete
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrInvalidQuery   = errutil.BadRequest("dashboardbulkdelete.invalid-query", errutil.WithPublicMessage("The query must match the dashboards by tag, folder, title or staleness"))
+	ErrTooManyMatches = errutil.BadRequest("dashboardbulkdelete.too-many-matches", errutil.WithPublicMessage("The query matches more than 1000 dashboards"))
+	ErrInvalidToken   = errutil.BadRequest("dashboardbulkdelete.invalid-token", errutil.WithPublicMessage("The confirmation token is invalid or expired, run the dry run again"))
+	ErrMatchesChanged = errutil.Conflict("dashboardbulkdelete.matches-changed", errutil.WithPublicMessage("The dashboards matching the query changed since the dry run, run the dry run again"))
+	ErrJobNotFound    = errutil.NotFound("dashboardbulkdelete.job-not-found", errutil.WithPublicMessage("Bulk deletion not found"))
+	ErrCannotUndo     = errutil.Conflict("dashboardbulkdelete.cannot-undo", errutil.WithPublicMessage("The bulk deletion can't be undone"))
+)
+
+// Query matches the dashboards to delete, all its criteria must match
+type Query struct {
+	// Tags matches the dashboards with all the tags
+	Tags []string `json:"tags"`
+	// FolderUIDs matches the dashboards in the folders
+	FolderUIDs []string `json:"folderUids"`
+	// Title matches the dashboards with the text in their title
+	Title string `json:"title"`
+	// StaleFor matches the dashboards that weren't updated for the duration, for example 90d
+	StaleFor string `json:"staleFor"`
+}
+
+// Match is a dashboard matching the query that the user can delete
+type Match struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid,omitempty"`
+	Version   int    `json:"version"`
+}
+
+// Skipped is a dashboard matching the query that isn't deleted
+type Skipped struct {
+	UID    string `json:"uid"`
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// DryRunResult is what a bulk deletion would delete. The confirmation token is required to run the deletion,
+// it's only accepted if the same dashboards still match the query.
+type DryRunResult struct {
+	Matches      []Match   `json:"matches"`
+	Skipped      []Skipped `json:"skipped"`
+	ConfirmToken string    `json:"confirmToken"`
+	Expires      time.Time `json:"expires"`
+}
+
+type JobStatus string
+
+const (
+	JobStatusRunning     JobStatus = "running"
+	JobStatusDone        JobStatus = "done"
+	JobStatusRestored    JobStatus = "restored"
+	JobStatusInterrupted JobStatus = "interrupted"
+)
+
+// Failure is a dashboard a job failed to delete or restore
+type Failure struct {
+	UID   string `json:"uid"`
+	Error string `json:"error"`
+}
+
+// Job is a bulk deletion. The deleted dashboards are kept in the trash until UndoExpires.
+type Job struct {
+	ID          int64     `json:"-" xorm:"pk autoincr 'id'"`
+	UID         string    `json:"uid" xorm:"uid"`
+	OrgID       int64     `json:"-" xorm:"org_id"`
+	CreatedBy   string    `json:"createdBy" xorm:"created_by"`
+	Status      JobStatus `json:"status" xorm:"status"`
+	Total       int       `json:"total" xorm:"total"`
+	Deleted     int       `json:"deleted" xorm:"'deleted'"`
+	Restored    int       `json:"restored" xorm:"restored"`
+	Failures    []Failure `json:"failures" xorm:"failures"`
+	Created     time.Time `json:"created" xorm:"created"`
+	Updated     time.Time `json:"updated" xorm:"updated"`
+	UndoExpires time.Time `json:"undoExpires" xorm:"undo_expires"`
+}
+
+func (Job) TableName() string {
+	return "dashboard_bulk_delete_job"
+}
+
+// trashedDashboard is a dashboard deleted by a job, kept until the undo window of the job is over
+type trashedDashboard struct {
+	ID           int64            `xorm:"pk autoincr 'id'"`
+	OrgID        int64            `xorm:"org_id"`
+	JobUID       string           `xorm:"job_uid"`
+	DashboardUID string           `xorm:"dashboard_uid"`
+	FolderUID    string           `xorm:"folder_uid"`
+	Title        string           `xorm:"title"`
+	Data         *simplejson.Json `xorm:"data"`
+	Deleted      time.Time        `xorm:"'deleted'"`
+	Expires      time.Time        `xorm:"expires"`
+}
+
+func (trashedDashboard) TableName() string {
+	return "dashboard_trash"
+}
@@ -0,0 +1,51 @@
+package dashboardbulkdelete
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/signedtoken"
+)
+
+// confirmClaims are signed into the confirmation tokens of the dry runs, a token only confirms the deletion of
+// the dashboards listed by its dry run, for the user who ran it
+type confirmClaims struct {
+	OrgID   int64  `json:"o"`
+	User    string `json:"u"`
+	Matches string `json:"m"`
+	Expires int64  `json:"e"`
+}
+
+// hashMatches returns the hash of the UIDs and versions of the matches, so that updating a dashboard after the
+// dry run invalidates its token too
+func hashMatches(matches []Match) string {
+	keys := make([]string, 0, len(matches))
+	for _, m := range matches {
+		keys = append(keys, fmt.Sprintf("%s:%d", m.UID, m.Version))
+	}
+	sort.Strings(keys)
+	sum := sha256.Sum256([]byte(strings.Join(keys, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// confirmTokenPurpose scopes the signing key of the confirmation tokens
+const confirmTokenPurpose = "dashboard-bulk-delete"
+
+func signConfirmToken(secretKey string, c confirmClaims) (string, error) {
+	return signedtoken.Sign(secretKey, confirmTokenPurpose, c)
+}
+
+func verifyConfirmToken(secretKey string, token string, now time.Time) (*confirmClaims, error) {
+	var c confirmClaims
+	if err := signedtoken.Verify(secretKey, confirmTokenPurpose, token, &c); err != nil {
+		return nil, ErrInvalidToken.Errorf("invalid confirmation token: %w", err)
+	}
+	if now.Unix() >= c.Expires {
+		return nil, ErrInvalidToken.Errorf("confirmation token expired")
+	}
+	return &c, nil
+}
@@ -15,19 +15,22 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
 type ImportDashboardAPI struct {
+	cfg                    *setting.Cfg
 	dashboardImportService dashboardimport.Service
 	quotaService           QuotaService
 	pluginStore            pluginstore.Store
 	ac                     accesscontrol.AccessControl
 }
 
-func New(dashboardImportService dashboardimport.Service, quotaService QuotaService,
+func New(cfg *setting.Cfg, dashboardImportService dashboardimport.Service, quotaService QuotaService,
 	pluginStore pluginstore.Store, ac accesscontrol.AccessControl) *ImportDashboardAPI {
 	return &ImportDashboardAPI{
+		cfg:                    cfg,
 		dashboardImportService: dashboardImportService,
 		quotaService:           quotaService,
 		pluginStore:            pluginStore,
@@ -41,8 +44,15 @@ func (api *ImportDashboardAPI) RegisterAPIEndpoints(routeRegister routing.RouteR
 		route.Post(
 			"/import",
 			authorize(accesscontrol.EvalPermission(dashboards.ActionDashboardsCreate)),
+			middleware.LimitDashboardPayload(api.cfg.DashboardMaxPayloadSize),
 			routing.Wrap(api.ImportDashboard),
 		)
+		route.Post(
+			"/import/conflicts",
+			authorize(accesscontrol.EvalPermission(dashboards.ActionDashboardsCreate)),
+			middleware.LimitDashboardPayload(api.cfg.DashboardMaxPayloadSize),
+			routing.Wrap(api.CheckImportConflicts),
+		)
 	}, middleware.ReqSignedIn)
 }
 
@@ -54,18 +64,22 @@ func (api *ImportDashboardAPI) RegisterAPIEndpoints(routeRegister routing.RouteR
 // 200: importDashboardResponse
 // 400: badRequestError
 // 401: unauthorisedError
+// 409: importConflictError
 // 412: preconditionFailedError
 // 422: unprocessableEntityError
 // 500: internalServerError
 func (api *ImportDashboardAPI) ImportDashboard(c *contextmodel.ReqContext) response.Response {
 	req := dashboardimport.ImportDashboardRequest{}
 	if err := web.Bind(c.Req, &req); err != nil {
-		return response.Error(http.StatusBadRequest, "bad request data", err)
+		return apierrors.ToDashboardBindErrorResponse(err)
 	}
 
 	if req.PluginId == "" && req.Dashboard == nil {
 		return response.Error(http.StatusUnprocessableEntity, "Dashboard must be set", nil)
 	}
+	if err := req.ConflictStrategy.Validate(); err != nil {
+		return response.Err(err)
+	}
 
 	limitReached, err := api.quotaService.QuotaReached(c, dashboards.QuotaTargetSrv)
 	if err != nil {
@@ -79,15 +93,61 @@ func (api *ImportDashboardAPI) ImportDashboard(c *contextmodel.ReqContext) respo
 	req.User = c.SignedInUser
 	resp, err := api.dashboardImportService.ImportDashboard(c.Req.Context(), &req)
 	if err != nil {
-		if errors.Is(err, utils.ErrDashboardInputMissing) {
-			return response.Error(http.StatusBadRequest, err.Error(), err)
-		}
-		return apierrors.ToDashboardErrorResponse(c.Req.Context(), api.pluginStore, err)
+		return api.toImportErrorResponse(c, err)
 	}
 
 	return response.JSON(http.StatusOK, resp)
 }
 
+// swagger:route POST /dashboards/import/conflicts dashboards checkImportConflicts
+//
+// Check the conflicts of a dashboard import.
+//
+// Returns the existing dashboards conflicting with the import, by UID or by title in the folder, and how
+// the conflict strategy of the request resolves them, without importing the dashboard.
+//
+// Responses:
+// 200: checkImportConflictsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 422: unprocessableEntityError
+// 500: internalServerError
+func (api *ImportDashboardAPI) CheckImportConflicts(c *contextmodel.ReqContext) response.Response {
+	req := dashboardimport.ImportDashboardRequest{}
+	if err := web.Bind(c.Req, &req); err != nil {
+		return apierrors.ToDashboardBindErrorResponse(err)
+	}
+
+	if req.PluginId == "" && req.Dashboard == nil {
+		return response.Error(http.StatusUnprocessableEntity, "Dashboard must be set", nil)
+	}
+	if err := req.ConflictStrategy.Validate(); err != nil {
+		return response.Err(err)
+	}
+
+	req.User = c.SignedInUser
+	report, err := api.dashboardImportService.CheckImportConflicts(c.Req.Context(), &req)
+	if err != nil {
+		return api.toImportErrorResponse(c, err)
+	}
+
+	return response.JSON(http.StatusOK, report)
+}
+
+func (api *ImportDashboardAPI) toImportErrorResponse(c *contextmodel.ReqContext, err error) response.Response {
+	if errors.Is(err, utils.ErrDashboardInputMissing) {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+	if errors.Is(err, dashboardimport.ErrInvalidConflictStrategy) {
+		return response.Err(err)
+	}
+	var conflictErr dashboardimport.ImportConflictErr
+	if errors.As(err, &conflictErr) {
+		return response.JSON(http.StatusConflict, conflictErr.Body())
+	}
+	return apierrors.ToDashboardErrorResponse(c.Req.Context(), api.pluginStore, err)
+}
+
 type QuotaService interface {
 	QuotaReached(c *contextmodel.ReqContext, target quota.TargetSrv) (bool, error)
 }
@@ -105,6 +165,30 @@ type ImportDashboardParams struct {
 	Body dashboardimport.ImportDashboardRequest
 }
 
+// swagger:parameters checkImportConflicts
+type CheckImportConflictsParams struct {
+	// in:body
+	// required:true
+	Body dashboardimport.ImportDashboardRequest
+}
+
+// swagger:response checkImportConflictsResponse
+type CheckImportConflictsResponse struct {
+	// in: body
+	Body dashboardimport.ImportConflictReport `json:"body"`
+}
+
+// swagger:response importConflictError
+type ImportConflictError struct {
+	// in: body
+	Body struct {
+		// Status is always import-conflict
+		Status    string                           `json:"status"`
+		Message   string                           `json:"message"`
+		Conflicts []dashboardimport.ImportConflict `json:"conflicts"`
+	} `json:"body"`
+}
+
 // swagger:response importDashboardResponse
 type ImportDashboardResponse struct {
 	// in: body
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
@@ -30,7 +31,7 @@ func TestImportDashboardAPI(t *testing.T) {
 			},
 		}
 
-		importDashboardAPI := New(service, quotaServiceFunc(quotaNotReached), nil, actest.FakeAccessControl{ExpectedEvaluate: true})
+		importDashboardAPI := New(setting.NewCfg(), service, quotaServiceFunc(quotaNotReached), nil, actest.FakeAccessControl{ExpectedEvaluate: true})
 		routeRegister := routing.NewRouteRegister()
 		importDashboardAPI.RegisterAPIEndpoints(routeRegister)
 		s := webtest.NewServer(t, routeRegister)
@@ -107,7 +108,7 @@ func TestImportDashboardAPI(t *testing.T) {
 			},
 		}
 
-		importDashboardAPI := New(service, quotaServiceFunc(quotaNotReached), nil, actest.FakeAccessControl{ExpectedEvaluate: true})
+		importDashboardAPI := New(setting.NewCfg(), service, quotaServiceFunc(quotaNotReached), nil, actest.FakeAccessControl{ExpectedEvaluate: true})
 		routeRegister := routing.NewRouteRegister()
 		importDashboardAPI.RegisterAPIEndpoints(routeRegister)
 		s := webtest.NewServer(t, routeRegister)
@@ -135,7 +136,7 @@ func TestImportDashboardAPI(t *testing.T) {
 
 	t.Run("Quota reached", func(t *testing.T) {
 		service := &serviceMock{}
-		importDashboardAPI := New(service, quotaServiceFunc(quotaReached), nil, actest.FakeAccessControl{ExpectedEvaluate: true})
+		importDashboardAPI := New(setting.NewCfg(), service, quotaServiceFunc(quotaReached), nil, actest.FakeAccessControl{ExpectedEvaluate: true})
 
 		routeRegister := routing.NewRouteRegister()
 		importDashboardAPI.RegisterAPIEndpoints(routeRegister)
@@ -157,10 +158,111 @@ func TestImportDashboardAPI(t *testing.T) {
 			require.Equal(t, http.StatusForbidden, resp.StatusCode)
 		})
 	})
+
+	t.Run("Conflicts", func(t *testing.T) {
+		report := &dashboardimport.ImportConflictReport{
+			Strategy: dashboardimport.ConflictStrategyFail,
+			Conflicts: []dashboardimport.ImportConflict{
+				{Reason: dashboardimport.ConflictReasonUID, UID: "abc", Title: "Existing"},
+			},
+			UID:   "abc",
+			Title: "Imported",
+			Error: "The dashboard conflicts with existing dashboards",
+		}
+		service := &serviceMock{
+			importDashboardFunc: func(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error) {
+				return nil, dashboardimport.ImportConflictErr{Report: report}
+			},
+			checkImportConflictsFunc: func(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportConflictReport, error) {
+				return report, nil
+			},
+		}
+		importDashboardAPI := New(setting.NewCfg(), service, quotaServiceFunc(quotaNotReached), nil, actest.FakeAccessControl{ExpectedEvaluate: true})
+
+		routeRegister := routing.NewRouteRegister()
+		importDashboardAPI.RegisterAPIEndpoints(routeRegister)
+		s := webtest.NewServer(t, routeRegister)
+
+		post := func(t *testing.T, path string, cmd *dashboardimport.ImportDashboardRequest) (int, map[string]any) {
+			t.Helper()
+			jsonBytes, err := json.Marshal(cmd)
+			require.NoError(t, err)
+			req := s.NewPostRequest(path, bytes.NewReader(jsonBytes))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				UserID: 1,
+			})
+			resp, err := s.SendJSON(req)
+			require.NoError(t, err)
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			require.NoError(t, resp.Body.Close())
+			return resp.StatusCode, body
+		}
+
+		t.Run("Signed in, invalid conflict strategy should return 400", func(t *testing.T) {
+			status, _ := post(t, "/api/dashboards/import", &dashboardimport.ImportDashboardRequest{
+				Dashboard:        simplejson.New(),
+				ConflictStrategy: "merge",
+			})
+			require.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("Signed in, import failing with its strategy should return 409 with the conflicts", func(t *testing.T) {
+			status, body := post(t, "/api/dashboards/import", &dashboardimport.ImportDashboardRequest{
+				Dashboard:        simplejson.New(),
+				ConflictStrategy: dashboardimport.ConflictStrategyFail,
+			})
+			require.Equal(t, http.StatusConflict, status)
+			require.Equal(t, "import-conflict", body["status"])
+			require.Len(t, body["conflicts"], 1)
+		})
+
+		t.Run("Signed in, conflict check should return the report", func(t *testing.T) {
+			status, body := post(t, "/api/dashboards/import/conflicts", &dashboardimport.ImportDashboardRequest{
+				Dashboard: simplejson.New(),
+			})
+			require.Equal(t, http.StatusOK, status)
+			require.Equal(t, "fail", body["strategy"])
+			require.Equal(t, report.Error, body["error"])
+		})
+	})
+
+	t.Run("Payload too large", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DashboardMaxPayloadSize = 16
+		importDashboardAPI := New(cfg, &serviceMock{}, quotaServiceFunc(quotaNotReached), nil, actest.FakeAccessControl{ExpectedEvaluate: true})
+
+		routeRegister := routing.NewRouteRegister()
+		importDashboardAPI.RegisterAPIEndpoints(routeRegister)
+		s := webtest.NewServer(t, routeRegister)
+
+		t.Run("Signed in, body larger than the limit, should return 413 with the limit", func(t *testing.T) {
+			cmd := &dashboardimport.ImportDashboardRequest{
+				Dashboard: simplejson.NewFromAny(map[string]any{"title": "Too large for the limit"}),
+			}
+			jsonBytes, err := json.Marshal(cmd)
+			require.NoError(t, err)
+			req := s.NewPostRequest("/api/dashboards/import", bytes.NewReader(jsonBytes))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				UserID: 1,
+			})
+			resp, err := s.SendJSON(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, "payload-too-large", body["status"])
+			require.Equal(t, float64(16), body["limit"])
+			require.Equal(t, float64(len(jsonBytes)), body["actual"])
+		})
+	})
 }
 
 type serviceMock struct {
-	importDashboardFunc func(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error)
+	importDashboardFunc      func(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error)
+	checkImportConflictsFunc func(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportConflictReport, error)
 }
 
 func (s *serviceMock) ImportDashboard(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error) {
@@ -171,6 +273,14 @@ func (s *serviceMock) ImportDashboard(ctx context.Context, req *dashboardimport.
 	return nil, nil
 }
 
+func (s *serviceMock) CheckImportConflicts(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportConflictReport, error) {
+	if s.checkImportConflictsFunc != nil {
+		return s.checkImportConflictsFunc(ctx, req)
+	}
+
+	return nil, nil
+}
+
 func quotaReached(c *contextmodel.ReqContext, target quota.TargetSrv) (bool, error) {
 	return true, nil
 }
@@ -2,9 +2,47 @@ package dashboardimport
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var ErrInvalidConflictStrategy = errutil.BadRequest("dashboards.import-invalid-conflict-strategy")
+
+// ConflictStrategy is how an import handles the existing dashboards with the same UID, or with the same
+// title in the folder of the import
+type ConflictStrategy string
+
+const (
+	// ConflictStrategyFail fails the import when there's a conflict
+	ConflictStrategyFail ConflictStrategy = "fail"
+	// ConflictStrategyOverwrite replaces the conflicting dashboard
+	ConflictStrategyOverwrite ConflictStrategy = "overwrite"
+	// ConflictStrategyRename imports the dashboard as a new dashboard, with a new UID when the UID conflicts
+	// and with a numbered suffix added to the title when the title conflicts
+	ConflictStrategyRename ConflictStrategy = "rename"
+	// ConflictStrategyCreateInFolder imports the conflicting dashboard as a new dashboard in the conflict folder
+	ConflictStrategyCreateInFolder ConflictStrategy = "create-in-folder"
+)
+
+func (s ConflictStrategy) Validate() error {
+	switch s {
+	case "", ConflictStrategyFail, ConflictStrategyOverwrite, ConflictStrategyRename, ConflictStrategyCreateInFolder:
+		return nil
+	}
+	return ErrInvalidConflictStrategy.Errorf("invalid conflict strategy %q, expected fail, overwrite, rename or create-in-folder", s)
+}
+
+// ConflictReason is why an existing dashboard conflicts with the imported dashboard
+type ConflictReason string
+
+const (
+	ConflictReasonUID   ConflictReason = "uid"
+	ConflictReasonTitle ConflictReason = "title"
 )
 
 // ImportDashboardInput definition of input parameters when importing a dashboard.
@@ -17,11 +55,18 @@ type ImportDashboardInput struct {
 
 // ImportDashboardRequest request object for importing a dashboard.
 type ImportDashboardRequest struct {
-	PluginId  string                 `json:"pluginId"`
-	Path      string                 `json:"path"`
-	Overwrite bool                   `json:"overwrite"`
-	Dashboard *simplejson.Json       `json:"dashboard"`
-	Inputs    []ImportDashboardInput `json:"inputs"`
+	PluginId string `json:"pluginId"`
+	Path     string `json:"path"`
+	// Overwrite is the legacy conflict strategy, it's only used when ConflictStrategy isn't set:
+	// true overwrites the conflicting dashboard and false fails the import.
+	Overwrite bool `json:"overwrite"`
+	// ConflictStrategy is how the import handles the existing dashboards with the same UID, or with the
+	// same title in the folder: fail, overwrite, rename or create-in-folder.
+	ConflictStrategy ConflictStrategy `json:"conflictStrategy,omitempty"`
+	// ConflictFolderUid is the folder of the create-in-folder strategy.
+	ConflictFolderUid string                 `json:"conflictFolderUid,omitempty"`
+	Dashboard         *simplejson.Json       `json:"dashboard"`
+	Inputs            []ImportDashboardInput `json:"inputs"`
 	// Deprecated: use FolderUID instead
 	FolderId  int64  `json:"folderId"`
 	FolderUid string `json:"folderUid"`
@@ -49,7 +94,54 @@ type ImportDashboardResponse struct {
 	Removed          bool   `json:"removed"`
 }
 
+// ImportConflict is an existing dashboard conflicting with an imported dashboard
+type ImportConflict struct {
+	Reason    ConflictReason `json:"reason"`
+	UID       string         `json:"uid"`
+	Title     string         `json:"title"`
+	FolderUID string         `json:"folderUid"`
+}
+
+// ImportConflictReport is the pre-flight check of an import: the conflicting dashboards and how the
+// conflict strategy resolves them.
+type ImportConflictReport struct {
+	Strategy  ConflictStrategy `json:"strategy"`
+	Conflicts []ImportConflict `json:"conflicts"`
+	// UID is the UID the dashboard is imported with, it's empty when a new UID is generated
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
+	// Overwrite is true when the import replaces the conflicting dashboard
+	Overwrite bool `json:"overwrite"`
+	// Error is why the import fails with the strategy, if it does
+	Error string `json:"error,omitempty"`
+}
+
+// ImportConflictErr is the error of the imports failing with their conflict strategy
+type ImportConflictErr struct {
+	Report *ImportConflictReport
+}
+
+func (e ImportConflictErr) Error() string {
+	titles := make([]string, 0, len(e.Report.Conflicts))
+	for _, c := range e.Report.Conflicts {
+		titles = append(titles, fmt.Sprintf("%s (%s %s)", c.Title, c.Reason, c.UID))
+	}
+	return fmt.Sprintf("%s: %s", e.Report.Error, strings.Join(titles, ", "))
+}
+
+// Body returns the error's response body.
+func (e ImportConflictErr) Body() util.DynMap {
+	return util.DynMap{
+		"status":    "import-conflict",
+		"message":   e.Report.Error,
+		"conflicts": e.Report.Conflicts,
+	}
+}
+
 // Service service interface for importing dashboards.
 type Service interface {
 	ImportDashboard(ctx context.Context, req *ImportDashboardRequest) (*ImportDashboardResponse, error)
+	// CheckImportConflicts returns the conflicts of an import and how they are resolved, without importing.
+	CheckImportConflicts(ctx context.Context, req *ImportDashboardRequest) (*ImportConflictReport, error)
 }
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboardimport"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+)
+
+// maxRenameSuffix is the highest suffix tried by the rename strategy
+const maxRenameSuffix = 100
+
+func (s *ImportDashboardService) CheckImportConflicts(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportConflictReport, error) {
+	generatedDash, _, err := s.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	report, _, err := s.resolveConflicts(ctx, req, generatedDash)
+	return report, err
+}
+
+// resolveConflicts returns the conflicts of the import and how its strategy resolves them, with the folder
+// the dashboard is imported in instead of the folder of the request, if any.
+func (s *ImportDashboardService) resolveConflicts(ctx context.Context, req *dashboardimport.ImportDashboardRequest, dash *simplejson.Json) (*dashboardimport.ImportConflictReport, *folder.Folder, error) {
+	strategy := req.ConflictStrategy
+	if err := strategy.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if strategy == "" {
+		strategy = dashboardimport.ConflictStrategyFail
+		if req.Overwrite {
+			strategy = dashboardimport.ConflictStrategyOverwrite
+		}
+	}
+	if strategy == dashboardimport.ConflictStrategyCreateInFolder && req.ConflictFolderUid == "" {
+		return nil, nil, dashboardimport.ErrInvalidConflictStrategy.Errorf("the create-in-folder strategy requires a conflict folder")
+	}
+
+	orgID := req.User.GetOrgID()
+	report := &dashboardimport.ImportConflictReport{
+		Strategy:  strategy,
+		Conflicts: []dashboardimport.ImportConflict{},
+		UID:       dash.Get("uid").MustString(),
+		Title:     dash.Get("title").MustString(),
+		FolderUID: req.FolderUid,
+	}
+
+	uidMatch, err := s.findDashboard(ctx, &dashboards.GetDashboardQuery{UID: report.UID, OrgID: orgID})
+	if err != nil {
+		return nil, nil, err
+	}
+	if uidMatch != nil {
+		report.Conflicts = append(report.Conflicts, importConflict(dashboardimport.ConflictReasonUID, uidMatch))
+	}
+	titleMatch, err := s.findByTitle(ctx, orgID, report.Title, report.FolderUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// importing a dashboard with the UID of the dashboard with the same title updates that dashboard
+	if titleMatch != nil && (uidMatch == nil || titleMatch.ID != uidMatch.ID) {
+		report.Conflicts = append(report.Conflicts, importConflict(dashboardimport.ConflictReasonTitle, titleMatch))
+	}
+	if len(report.Conflicts) == 0 {
+		return report, nil, nil
+	}
+
+	switch strategy {
+	case dashboardimport.ConflictStrategyFail:
+		report.Error = "The dashboard conflicts with existing dashboards"
+
+	case dashboardimport.ConflictStrategyOverwrite:
+		report.Overwrite = true
+
+	case dashboardimport.ConflictStrategyRename:
+		if uidMatch != nil {
+			report.UID = ""
+		}
+		// as a new dashboard, it also conflicts with the dashboard of the same UID and title
+		if titleMatch != nil {
+			title, err := s.uniqueTitle(ctx, orgID, report.Title, report.FolderUID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if title == "" {
+				report.Error = fmt.Sprintf("No title with a suffix up to %d is available in the folder", maxRenameSuffix)
+			}
+			report.Title = title
+		}
+
+	case dashboardimport.ConflictStrategyCreateInFolder:
+		conflictFolder, err := s.folderService.Get(ctx, &folder.GetFolderQuery{
+			OrgID:        orgID,
+			UID:          &req.ConflictFolderUid,
+			SignedInUser: req.User,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if uidMatch != nil {
+			report.UID = ""
+		}
+		report.FolderUID = conflictFolder.UID
+		match, err := s.findByTitle(ctx, orgID, report.Title, conflictFolder.UID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if match != nil {
+			report.Conflicts = append(report.Conflicts, importConflict(dashboardimport.ConflictReasonTitle, match))
+			report.Error = "A dashboard with the same title exists in the conflict folder"
+		}
+		return report, conflictFolder, nil
+	}
+	return report, nil, nil
+}
+
+// uniqueTitle returns the title with the lowest numbered suffix that no dashboard of the folder has,
+// or an empty string if they are all taken
+func (s *ImportDashboardService) uniqueTitle(ctx context.Context, orgID int64, title, folderUID string) (string, error) {
+	for n := 1; n <= maxRenameSuffix; n++ {
+		candidate := fmt.Sprintf("%s (%d)", title, n)
+		match, err := s.findByTitle(ctx, orgID, candidate, folderUID)
+		if err != nil {
+			return "", err
+		}
+		if match == nil {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *ImportDashboardService) findByTitle(ctx context.Context, orgID int64, title, folderUID string) (*dashboards.Dashboard, error) {
+	if title == "" {
+		return nil, nil
+	}
+	return s.findDashboard(ctx, &dashboards.GetDashboardQuery{Title: &title, FolderUID: &folderUID, OrgID: orgID})
+}
+
+// findDashboard returns the dashboard of the query, or nil if there is none
+func (s *ImportDashboardService) findDashboard(ctx context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
+	if query.UID == "" && query.Title == nil {
+		return nil, nil
+	}
+	dash, err := s.dashboardService.GetDashboard(ctx, query)
+	if errors.Is(err, dashboards.ErrDashboardNotFound) {
+		return nil, nil
+	}
+	return dash, err
+}
+
+func importConflict(reason dashboardimport.ConflictReason, dash *dashboards.Dashboard) dashboardimport.ImportConflict {
+	return dashboardimport.ImportConflict{Reason: reason, UID: dash.UID, Title: dash.Title, FolderUID: dash.FolderUID}
+}
@@ -17,9 +17,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/plugindashboards"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(routeRegister routing.RouteRegister,
+func ProvideService(cfg *setting.Cfg, routeRegister routing.RouteRegister,
 	quotaService quota.Service,
 	pluginDashboardService plugindashboards.Service, pluginStore pluginstore.Store,
 	libraryPanelService librarypanels.Service, dashboardService dashboards.DashboardService,
@@ -32,7 +33,7 @@ func ProvideService(routeRegister routing.RouteRegister,
 		folderService:          folderService,
 	}
 
-	dashboardImportAPI := api.New(s, quotaService, pluginStore, ac)
+	dashboardImportAPI := api.New(cfg, s, quotaService, pluginStore, ac)
 	dashboardImportAPI.RegisterAPIEndpoints(routeRegister)
 
 	return s
@@ -46,67 +47,32 @@ type ImportDashboardService struct {
 }
 
 func (s *ImportDashboardService) ImportDashboard(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error) {
-	var draftDashboard *dashboards.Dashboard
-	if req.PluginId != "" {
-		loadReq := &plugindashboards.LoadPluginDashboardRequest{
-			PluginID:  req.PluginId,
-			Reference: req.Path,
-		}
-		if resp, err := s.pluginDashboardService.LoadPluginDashboard(ctx, loadReq); err != nil {
-			return nil, err
-		} else {
-			draftDashboard = resp.Dashboard
-		}
-	} else {
-		draftDashboard = dashboards.NewDashboardFromJson(req.Dashboard)
-	}
-
-	evaluator := utils.NewDashTemplateEvaluator(draftDashboard.Data, req.Inputs)
-	generatedDash, err := evaluator.Eval()
+	generatedDash, libraryElements, err := s.prepare(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	// Maintain backwards compatibility by transforming array of library elements to map
-	libraryElements := generatedDash.Get("__elements")
-	libElementsArr, err := libraryElements.Array()
-	if err == nil {
-		elementMap := map[string]any{}
-		for _, el := range libElementsArr {
-			libElement := simplejson.NewFromAny(el)
-			elementMap[libElement.Get("uid").MustString()] = el
-		}
-		libraryElements = simplejson.NewFromAny(elementMap)
-	}
-
-	// No need to keep these in the stored dashboard JSON
-	generatedDash.Del("__elements")
-	generatedDash.Del("__inputs")
-	generatedDash.Del("__requires")
-
-	metrics.MFolderIDsServiceCount.WithLabelValues(metrics.DashboardImport).Inc()
-	// here we need to get FolderId from FolderUID if it present in the request, if both exist, FolderUID would overwrite FolderID
-	if req.FolderUid != "" {
-		folder, err := s.folderService.Get(ctx, &folder.GetFolderQuery{
-			OrgID:        req.User.GetOrgID(),
-			UID:          &req.FolderUid,
-			SignedInUser: req.User,
-		})
+	overwrite := req.Overwrite
+	// without a strategy, the conflicts are handled by the dashboard service according to the overwrite flag
+	if req.ConflictStrategy != "" {
+		report, conflictFolder, err := s.resolveConflicts(ctx, req, generatedDash)
 		if err != nil {
 			return nil, err
 		}
-		// nolint:staticcheck
-		req.FolderId = folder.ID
-	} else {
-		folder, err := s.folderService.Get(ctx, &folder.GetFolderQuery{
-			ID:           &req.FolderId, // nolint:staticcheck
-			OrgID:        req.User.GetOrgID(),
-			SignedInUser: req.User,
-		})
-		if err != nil {
-			return nil, err
+		if report.Error != "" {
+			return nil, dashboardimport.ImportConflictErr{Report: report}
+		}
+
+		overwrite = report.Overwrite
+		if report.UID != generatedDash.Get("uid").MustString() {
+			generatedDash.Del("id")
+			generatedDash.Set("uid", report.UID)
+		}
+		generatedDash.Set("title", report.Title)
+		if conflictFolder != nil {
+			// nolint:staticcheck
+			req.FolderId, req.FolderUid = conflictFolder.ID, conflictFolder.UID
 		}
-		req.FolderUid = folder.UID
 	}
 
 	namespaceID, identifier := req.User.GetNamespacedID()
@@ -121,7 +87,7 @@ func (s *ImportDashboardService) ImportDashboard(ctx context.Context, req *dashb
 		Dashboard: generatedDash,
 		OrgID:     req.User.GetOrgID(),
 		UserID:    userID,
-		Overwrite: req.Overwrite,
+		Overwrite: overwrite,
 		PluginID:  req.PluginId,
 		FolderID:  req.FolderId, // nolint:staticcheck
 		FolderUID: req.FolderUid,
@@ -169,3 +135,72 @@ func (s *ImportDashboardService) ImportDashboard(ctx context.Context, req *dashb
 		Slug:             savedDashboard.Slug,
 	}, nil
 }
+
+// prepare returns the dashboard JSON of the import with its inputs evaluated and its library elements,
+// and resolves the folder of the import.
+func (s *ImportDashboardService) prepare(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*simplejson.Json, *simplejson.Json, error) {
+	var draftDashboard *dashboards.Dashboard
+	if req.PluginId != "" {
+		loadReq := &plugindashboards.LoadPluginDashboardRequest{
+			PluginID:  req.PluginId,
+			Reference: req.Path,
+		}
+		if resp, err := s.pluginDashboardService.LoadPluginDashboard(ctx, loadReq); err != nil {
+			return nil, nil, err
+		} else {
+			draftDashboard = resp.Dashboard
+		}
+	} else {
+		draftDashboard = dashboards.NewDashboardFromJson(req.Dashboard)
+	}
+
+	evaluator := utils.NewDashTemplateEvaluator(draftDashboard.Data, req.Inputs)
+	generatedDash, err := evaluator.Eval()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Maintain backwards compatibility by transforming array of library elements to map
+	libraryElements := generatedDash.Get("__elements")
+	libElementsArr, err := libraryElements.Array()
+	if err == nil {
+		elementMap := map[string]any{}
+		for _, el := range libElementsArr {
+			libElement := simplejson.NewFromAny(el)
+			elementMap[libElement.Get("uid").MustString()] = el
+		}
+		libraryElements = simplejson.NewFromAny(elementMap)
+	}
+
+	// No need to keep these in the stored dashboard JSON
+	generatedDash.Del("__elements")
+	generatedDash.Del("__inputs")
+	generatedDash.Del("__requires")
+
+	metrics.MFolderIDsServiceCount.WithLabelValues(metrics.DashboardImport).Inc()
+	// here we need to get FolderId from FolderUID if it present in the request, if both exist, FolderUID would overwrite FolderID
+	if req.FolderUid != "" {
+		folder, err := s.folderService.Get(ctx, &folder.GetFolderQuery{
+			OrgID:        req.User.GetOrgID(),
+			UID:          &req.FolderUid,
+			SignedInUser: req.User,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		// nolint:staticcheck
+		req.FolderId = folder.ID
+	} else {
+		folder, err := s.folderService.Get(ctx, &folder.GetFolderQuery{
+			ID:           &req.FolderId, // nolint:staticcheck
+			OrgID:        req.User.GetOrgID(),
+			SignedInUser: req.User,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		req.FolderUid = folder.UID
+	}
+
+	return generatedDash, libraryElements, nil
+}
@@ -164,6 +164,144 @@ func TestImportDashboardService(t *testing.T) {
 	})
 }
 
+func TestImportDashboardService_ConflictStrategies(t *testing.T) {
+	existing := []*dashboards.Dashboard{
+		{ID: 1, UID: "abc", Title: "Other"},
+		{ID: 2, UID: "xyz", Title: "Sales"},
+		{ID: 3, UID: "s1", Title: "Sales (1)"},
+		{ID: 4, UID: "d1", Title: "Costs", FolderUID: "dest"},
+	}
+	newService := func(saved **dashboards.SaveDashboardDTO) *ImportDashboardService {
+		return &ImportDashboardService{
+			dashboardService: &dashboardServiceMock{
+				getDashboardFunc: func(ctx context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
+					for _, dash := range existing {
+						if query.UID != "" && dash.UID == query.UID {
+							return dash, nil
+						}
+						if query.Title != nil && dash.Title == *query.Title && dash.FolderUID == *query.FolderUID {
+							return dash, nil
+						}
+					}
+					return nil, dashboards.ErrDashboardNotFound
+				},
+				importDashboardFunc: func(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*dashboards.Dashboard, error) {
+					*saved = dto
+					return dto.Dashboard, nil
+				},
+			},
+			libraryPanelService: &libraryPanelServiceMock{},
+			folderService: &folderServiceMock{folders: []*folder.Folder{
+				{ID: 0, UID: ""},
+				{ID: 10, UID: "dest"},
+			}},
+		}
+	}
+	newRequest := func(uid, title string, strategy dashboardimport.ConflictStrategy) *dashboardimport.ImportDashboardRequest {
+		return &dashboardimport.ImportDashboardRequest{
+			Dashboard:        simplejson.NewFromAny(map[string]any{"id": 7, "uid": uid, "title": title}),
+			ConflictStrategy: strategy,
+			User:             &user.SignedInUser{UserID: 2, OrgRole: org.RoleAdmin, OrgID: 3},
+		}
+	}
+
+	t.Run("fail should return the conflicts", func(t *testing.T) {
+		var saved *dashboards.SaveDashboardDTO
+		_, err := newService(&saved).ImportDashboard(context.Background(), newRequest("abc", "Sales", dashboardimport.ConflictStrategyFail))
+		var conflictErr dashboardimport.ImportConflictErr
+		require.ErrorAs(t, err, &conflictErr)
+		require.Equal(t, []dashboardimport.ImportConflict{
+			{Reason: dashboardimport.ConflictReasonUID, UID: "abc", Title: "Other"},
+			{Reason: dashboardimport.ConflictReasonTitle, UID: "xyz", Title: "Sales"},
+		}, conflictErr.Report.Conflicts)
+		require.Nil(t, saved)
+	})
+
+	t.Run("fail should import the dashboard without conflicts", func(t *testing.T) {
+		var saved *dashboards.SaveDashboardDTO
+		_, err := newService(&saved).ImportDashboard(context.Background(), newRequest("new", "New", dashboardimport.ConflictStrategyFail))
+		require.NoError(t, err)
+		require.Equal(t, "new", saved.Dashboard.UID)
+		require.False(t, saved.Overwrite)
+	})
+
+	t.Run("overwrite should replace the conflicting dashboard", func(t *testing.T) {
+		var saved *dashboards.SaveDashboardDTO
+		_, err := newService(&saved).ImportDashboard(context.Background(), newRequest("xyz", "Sales", dashboardimport.ConflictStrategyOverwrite))
+		require.NoError(t, err)
+		require.Equal(t, "xyz", saved.Dashboard.UID)
+		require.True(t, saved.Overwrite)
+	})
+
+	t.Run("rename should import a new dashboard with the first available suffix", func(t *testing.T) {
+		var saved *dashboards.SaveDashboardDTO
+		_, err := newService(&saved).ImportDashboard(context.Background(), newRequest("abc", "Sales", dashboardimport.ConflictStrategyRename))
+		require.NoError(t, err)
+		require.Equal(t, "", saved.Dashboard.UID)
+		require.Equal(t, int64(0), saved.Dashboard.ID)
+		require.Equal(t, "Sales (2)", saved.Dashboard.Title)
+		require.False(t, saved.Overwrite)
+	})
+
+	t.Run("rename should add a suffix when the dashboard conflicts with itself", func(t *testing.T) {
+		var saved *dashboards.SaveDashboardDTO
+		_, err := newService(&saved).ImportDashboard(context.Background(), newRequest("xyz", "Sales", dashboardimport.ConflictStrategyRename))
+		require.NoError(t, err)
+		require.Equal(t, "", saved.Dashboard.UID)
+		require.Equal(t, "Sales (2)", saved.Dashboard.Title)
+	})
+
+	t.Run("create-in-folder should import a new dashboard in the conflict folder", func(t *testing.T) {
+		var saved *dashboards.SaveDashboardDTO
+		req := newRequest("abc", "Sales", dashboardimport.ConflictStrategyCreateInFolder)
+		req.ConflictFolderUid = "dest"
+		_, err := newService(&saved).ImportDashboard(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, "", saved.Dashboard.UID)
+		require.Equal(t, "Sales", saved.Dashboard.Title)
+		require.Equal(t, "dest", saved.Dashboard.FolderUID)
+	})
+
+	t.Run("create-in-folder should fail when the title conflicts in the conflict folder", func(t *testing.T) {
+		var saved *dashboards.SaveDashboardDTO
+		existingCosts := &dashboards.Dashboard{ID: 5, UID: "c1", Title: "Costs"}
+		existing = append(existing, existingCosts)
+		t.Cleanup(func() { existing = existing[:len(existing)-1] })
+
+		req := newRequest("new", "Costs", dashboardimport.ConflictStrategyCreateInFolder)
+		req.ConflictFolderUid = "dest"
+		_, err := newService(&saved).ImportDashboard(context.Background(), req)
+		var conflictErr dashboardimport.ImportConflictErr
+		require.ErrorAs(t, err, &conflictErr)
+		require.Len(t, conflictErr.Report.Conflicts, 2)
+		require.Equal(t, "dest", conflictErr.Report.Conflicts[1].FolderUID)
+	})
+
+	t.Run("create-in-folder should require the conflict folder", func(t *testing.T) {
+		var saved *dashboards.SaveDashboardDTO
+		_, err := newService(&saved).ImportDashboard(context.Background(), newRequest("abc", "Sales", dashboardimport.ConflictStrategyCreateInFolder))
+		require.ErrorIs(t, err, dashboardimport.ErrInvalidConflictStrategy)
+	})
+
+	t.Run("check should report the conflicts of the overwrite flag without importing", func(t *testing.T) {
+		var saved *dashboards.SaveDashboardDTO
+		req := newRequest("abc", "New", "")
+		report, err := newService(&saved).CheckImportConflicts(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, dashboardimport.ConflictStrategyFail, report.Strategy)
+		require.Len(t, report.Conflicts, 1)
+		require.NotEmpty(t, report.Error)
+
+		req.Overwrite = true
+		report, err = newService(&saved).CheckImportConflicts(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, dashboardimport.ConflictStrategyOverwrite, report.Strategy)
+		require.True(t, report.Overwrite)
+		require.Empty(t, report.Error)
+		require.Nil(t, saved)
+	})
+}
+
 func loadTestDashboard(ctx context.Context, req *plugindashboards.LoadPluginDashboardRequest) (*plugindashboards.LoadPluginDashboardResponse, error) {
 	// It's safe to ignore gosec warning G304 since this is a test and arguments comes from test configuration.
 	// nolint:gosec
@@ -197,9 +335,18 @@ func (m *pluginDashboardServiceMock) LoadPluginDashboard(ctx context.Context, re
 
 type dashboardServiceMock struct {
 	dashboards.DashboardService
+	getDashboardFunc    func(ctx context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error)
 	importDashboardFunc func(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*dashboards.Dashboard, error)
 }
 
+func (s *dashboardServiceMock) GetDashboard(ctx context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
+	if s.getDashboardFunc != nil {
+		return s.getDashboardFunc(ctx, query)
+	}
+
+	return nil, dashboards.ErrDashboardNotFound
+}
+
 func (s *dashboardServiceMock) ImportDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*dashboards.Dashboard, error) {
 	if s.importDashboardFunc != nil {
 		return s.importDashboardFunc(ctx, dto)
@@ -208,6 +355,21 @@ func (s *dashboardServiceMock) ImportDashboard(ctx context.Context, dto *dashboa
 	return nil, nil
 }
 
+type folderServiceMock struct {
+	folder.Service
+	folders []*folder.Folder
+}
+
+func (s *folderServiceMock) Get(ctx context.Context, q *folder.GetFolderQuery) (*folder.Folder, error) {
+	for _, f := range s.folders {
+		// nolint:staticcheck
+		if (q.UID != nil && f.UID == *q.UID) || (q.ID != nil && f.ID == *q.ID) {
+			return f, nil
+		}
+	}
+	return nil, dashboards.ErrFolderNotFound
+}
+
 type libraryPanelServiceMock struct {
 	librarypanels.Service
 	connectLibraryPanelsForDashboardFunc func(c context.Context, signedInUser identity.Requester, dash *dashboards.Dashboard) error
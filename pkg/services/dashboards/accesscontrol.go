@@ -20,6 +20,8 @@ const (
 	ActionFoldersDelete           = "folders:delete"
 	ActionFoldersPermissionsRead  = "folders.permissions:read"
 	ActionFoldersPermissionsWrite = "folders.permissions:write"
+	// ActionFoldersReadOnlyWrite allows to set the read-only flag of folders and to save the dashboards of read-only folders
+	ActionFoldersReadOnlyWrite = "folders.readonly:write"
 
 	ScopeDashboardsRoot   = "dashboards"
 	ScopeDashboardsPrefix = "dashboards:uid:"
@@ -31,6 +33,7 @@ const (
 	ActionDashboardsPermissionsRead  = "dashboards.permissions:read"
 	ActionDashboardsPermissionsWrite = "dashboards.permissions:write"
 	ActionDashboardsPublicWrite      = "dashboards.public:write"
+	ActionDashboardsSensitiveRead    = "dashboards.sensitive:read"
 )
 
 var (
@@ -2,6 +2,7 @@ package dashboards
 
 import (
 	"context"
+	"time"
 
 	alertmodels "github.com/grafana/grafana/pkg/services/alerting/models"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
@@ -16,16 +17,43 @@ import (
 type DashboardService interface {
 	BuildSaveDashboardCommand(ctx context.Context, dto *SaveDashboardDTO, shouldValidateAlerts bool, validateProvisionedDashboard bool) (*SaveDashboardCommand, error)
 	DeleteDashboard(ctx context.Context, dashboardId int64, orgId int64) error
+	// DeleteDashboardAndRules deletes a dashboard with the alert rules that reference it.
+	DeleteDashboardAndRules(ctx context.Context, dashboardId int64, orgId int64) error
 	FindDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error)
 	// GetDashboard fetches a dashboard.
 	// To fetch a dashboard under root by title should set the folder UID to point to an empty string
 	// eg. util.Pointer("")
 	GetDashboard(ctx context.Context, query *GetDashboardQuery) (*Dashboard, error)
 	GetDashboards(ctx context.Context, query *GetDashboardsQuery) ([]*Dashboard, error)
+	// GetDashboardACLAudit returns the permission changes of a dashboard or folder, most recent first.
+	GetDashboardACLAudit(ctx context.Context, query *GetDashboardACLAuditQuery) ([]*DashboardACLAuditEntry, error)
+	// GetDashboardDependents returns the alert rules and annotations that reference a dashboard.
+	GetDashboardDependents(ctx context.Context, query *GetDashboardDependentsQuery) (*DashboardDependents, error)
+	// GetDashboardLabels returns the labels of a dashboard or folder.
+	GetDashboardLabels(ctx context.Context, query *GetDashboardLabelsQuery) (map[string]string, error)
 	GetDashboardTags(ctx context.Context, query *GetDashboardTagsQuery) ([]*DashboardTagCloudItem, error)
 	GetDashboardUIDByID(ctx context.Context, query *GetDashboardRefByIDQuery) (*DashboardRef, error)
+	// GetPanelTypeUsage returns how many panels and dashboards in the org use each panel plugin.
+	GetPanelTypeUsage(ctx context.Context, query *GetPanelTypeUsageQuery) ([]*PanelTypeUsage, error)
+	// GetFolderReadOnly returns the read-only flag of a folder, or of its closest read-only parent folder,
+	// or nil if the folder isn't read-only.
+	GetFolderReadOnly(ctx context.Context, query *GetFolderReadOnlyQuery) (*FolderReadOnly, error)
+	// GetSearchFacets returns the most frequent tags, folders, data sources and panel types of the search results.
+	GetSearchFacets(ctx context.Context, query *FindPersistedDashboardsQuery) (*model.Facets, error)
+	// CountSearchResults returns the number of search results, up to max if max is positive.
+	CountSearchResults(ctx context.Context, query *FindPersistedDashboardsQuery, max int64) (int64, error)
 	ImportDashboard(ctx context.Context, dto *SaveDashboardDTO) (*Dashboard, error)
+	// ListDashboardChanges returns a batch of the change feed of the dashboards and folders of an org.
+	ListDashboardChanges(ctx context.Context, query *ListDashboardChangesQuery) (*ListDashboardChangesResult, error)
+	// DeleteExpiredDashboardChanges deletes the changes of the change feed written before olderThan.
+	DeleteExpiredDashboardChanges(ctx context.Context, olderThan time.Time) (int64, error)
+	// ListDashboardsAfter returns a batch of the dashboards of an org, folders excluded, in the order of their IDs.
+	ListDashboardsAfter(ctx context.Context, query *ListDashboardsAfterQuery) ([]*Dashboard, error)
 	SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*Dashboard, error)
+	// SaveDashboardLabels replaces the labels of a folder.
+	SaveDashboardLabels(ctx context.Context, cmd *SaveDashboardLabelsCommand) error
+	// SetFolderReadOnly sets or clears the read-only flag of a folder.
+	SetFolderReadOnly(ctx context.Context, cmd *SetFolderReadOnlyCommand) error
 	SearchDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) (model.HitList, error)
 	CountInFolders(ctx context.Context, orgID int64, folderUIDs []string, user identity.Requester) (int64, error)
 	GetDashboardsSharedWithUser(ctx context.Context, user identity.Requester) ([]*Dashboard, error)
@@ -60,16 +88,38 @@ type Store interface {
 	GetDashboard(ctx context.Context, query *GetDashboardQuery) (*Dashboard, error)
 	GetDashboardUIDByID(ctx context.Context, query *GetDashboardRefByIDQuery) (*DashboardRef, error)
 	GetDashboards(ctx context.Context, query *GetDashboardsQuery) ([]*Dashboard, error)
+	GetDashboardACLAudit(ctx context.Context, query *GetDashboardACLAuditQuery) ([]*DashboardACLAuditEntry, error)
+	GetDashboardDependents(ctx context.Context, query *GetDashboardDependentsQuery) (*DashboardDependents, error)
 	// GetDashboardsByPluginID retrieves dashboards identified by plugin.
 	GetDashboardsByPluginID(ctx context.Context, query *GetDashboardsByPluginIDQuery) ([]*Dashboard, error)
+	GetDashboardLabels(ctx context.Context, query *GetDashboardLabelsQuery) (map[string]string, error)
 	GetDashboardTags(ctx context.Context, query *GetDashboardTagsQuery) ([]*DashboardTagCloudItem, error)
+	// GetPanelTypeUsage aggregates the panel types indexed on dashboard save.
+	GetPanelTypeUsage(ctx context.Context, query *GetPanelTypeUsageQuery) ([]*PanelTypeUsage, error)
 	GetProvisionedDashboardData(ctx context.Context, name string) ([]*DashboardProvisioning, error)
 	GetProvisionedDataByDashboardID(ctx context.Context, dashboardID int64) (*DashboardProvisioning, error)
 	GetProvisionedDataByDashboardUID(ctx context.Context, orgID int64, dashboardUID string) (*DashboardProvisioning, error)
+	// GetReadOnlyFolders returns the read-only flags of the folders, the folders that aren't read-only have none.
+	GetReadOnlyFolders(ctx context.Context, orgID int64, folderUIDs []string) ([]*FolderReadOnly, error)
+	// GetSearchFacets counts the dashboards matching the query per tag, folder, data source and panel type.
+	GetSearchFacets(ctx context.Context, query *FindPersistedDashboardsQuery) (*model.Facets, error)
+	// CountSearchResults counts the dashboards matching the query, up to max if max is positive.
+	CountSearchResults(ctx context.Context, query *FindPersistedDashboardsQuery, max int64) (int64, error)
+	// ListDashboardChanges lists the changes of the change feed written after the cursor of the query.
+	ListDashboardChanges(ctx context.Context, query *ListDashboardChangesQuery) (*ListDashboardChangesResult, error)
+	// DeleteExpiredDashboardChanges deletes the changes written before olderThan, except the last change.
+	DeleteExpiredDashboardChanges(ctx context.Context, olderThan time.Time) (int64, error)
+	ListDashboardsAfter(ctx context.Context, query *ListDashboardsAfterQuery) ([]*Dashboard, error)
+	// NormalizeStoredDashboards rewrites the JSON of a batch of saved dashboards with NormalizeDashboardData.
+	// It doesn't create dashboard versions.
+	NormalizeStoredDashboards(ctx context.Context, cmd *NormalizeStoredDashboardsCommand) (*NormalizeStoredDashboardsResult, error)
 	// SaveAlerts saves dashboard alerts.
 	SaveAlerts(ctx context.Context, dashID int64, alerts []*alertmodels.Alert) error
 	SaveDashboard(ctx context.Context, cmd SaveDashboardCommand) (*Dashboard, error)
+	SaveDashboardACLAudit(ctx context.Context, entry *DashboardACLAuditEntry) error
+	SaveDashboardLabels(ctx context.Context, cmd *SaveDashboardLabelsCommand) error
 	SaveProvisionedDashboard(ctx context.Context, cmd SaveDashboardCommand, provisioning *DashboardProvisioning) (*Dashboard, error)
+	SetFolderReadOnly(ctx context.Context, cmd *SetFolderReadOnlyCommand) error
 	UnprovisionDashboard(ctx context.Context, id int64) error
 	// ValidateDashboardBeforeSave validates a dashboard before save.
 	ValidateDashboardBeforeSave(ctx context.Context, dashboard *Dashboard, overwrite bool) (bool, error)
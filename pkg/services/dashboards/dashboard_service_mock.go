@@ -4,6 +4,7 @@ package dashboards
 
 import (
 	context "context"
+	time "time"
 
 	identity "github.com/grafana/grafana/pkg/services/auth/identity"
 	mock "github.com/stretchr/testify/mock"
@@ -66,6 +67,30 @@ func (_m *FakeDashboardService) CountInFolders(ctx context.Context, orgID int64,
 	return r0, r1
 }
 
+// CountSearchResults provides a mock function with given fields: ctx, query, max
+func (_m *FakeDashboardService) CountSearchResults(ctx context.Context, query *FindPersistedDashboardsQuery, max int64) (int64, error) {
+	ret := _m.Called(ctx, query, max)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *FindPersistedDashboardsQuery, int64) (int64, error)); ok {
+		return rf(ctx, query, max)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *FindPersistedDashboardsQuery, int64) int64); ok {
+		r0 = rf(ctx, query, max)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *FindPersistedDashboardsQuery, int64) error); ok {
+		r1 = rf(ctx, query, max)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteDashboard provides a mock function with given fields: ctx, dashboardId, orgId
 func (_m *FakeDashboardService) DeleteDashboard(ctx context.Context, dashboardId int64, orgId int64) error {
 	ret := _m.Called(ctx, dashboardId, orgId)
@@ -80,6 +105,44 @@ func (_m *FakeDashboardService) DeleteDashboard(ctx context.Context, dashboardId
 	return r0
 }
 
+// DeleteDashboardAndRules provides a mock function with given fields: ctx, dashboardId, orgId
+func (_m *FakeDashboardService) DeleteDashboardAndRules(ctx context.Context, dashboardId int64, orgId int64) error {
+	ret := _m.Called(ctx, dashboardId, orgId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, dashboardId, orgId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpiredDashboardChanges provides a mock function with given fields: ctx, olderThan
+func (_m *FakeDashboardService) DeleteExpiredDashboardChanges(ctx context.Context, olderThan time.Time) (int64, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, olderThan)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindDashboards provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) FindDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error) {
 	ret := _m.Called(ctx, query)
@@ -132,6 +195,84 @@ func (_m *FakeDashboardService) GetDashboard(ctx context.Context, query *GetDash
 	return r0, r1
 }
 
+// GetDashboardACLAudit provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) GetDashboardACLAudit(ctx context.Context, query *GetDashboardACLAuditQuery) ([]*DashboardACLAuditEntry, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*DashboardACLAuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardACLAuditQuery) ([]*DashboardACLAuditEntry, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardACLAuditQuery) []*DashboardACLAuditEntry); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DashboardACLAuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDashboardACLAuditQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDashboardDependents provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) GetDashboardDependents(ctx context.Context, query *GetDashboardDependentsQuery) (*DashboardDependents, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *DashboardDependents
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardDependentsQuery) (*DashboardDependents, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardDependentsQuery) *DashboardDependents); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardDependents)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDashboardDependentsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDashboardLabels provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) GetDashboardLabels(ctx context.Context, query *GetDashboardLabelsQuery) (map[string]string, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardLabelsQuery) (map[string]string, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardLabelsQuery) map[string]string); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDashboardLabelsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDashboardTags provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) GetDashboardTags(ctx context.Context, query *GetDashboardTagsQuery) ([]*DashboardTagCloudItem, error) {
 	ret := _m.Called(ctx, query)
@@ -236,6 +377,84 @@ func (_m *FakeDashboardService) GetDashboardsSharedWithUser(ctx context.Context,
 	return r0, r1
 }
 
+// GetFolderReadOnly provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) GetFolderReadOnly(ctx context.Context, query *GetFolderReadOnlyQuery) (*FolderReadOnly, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *FolderReadOnly
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetFolderReadOnlyQuery) (*FolderReadOnly, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetFolderReadOnlyQuery) *FolderReadOnly); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*FolderReadOnly)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetFolderReadOnlyQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPanelTypeUsage provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) GetPanelTypeUsage(ctx context.Context, query *GetPanelTypeUsageQuery) ([]*PanelTypeUsage, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*PanelTypeUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetPanelTypeUsageQuery) ([]*PanelTypeUsage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetPanelTypeUsageQuery) []*PanelTypeUsage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*PanelTypeUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetPanelTypeUsageQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSearchFacets provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) GetSearchFacets(ctx context.Context, query *FindPersistedDashboardsQuery) (*model.Facets, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *model.Facets
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *FindPersistedDashboardsQuery) (*model.Facets, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *FindPersistedDashboardsQuery) *model.Facets); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Facets)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *FindPersistedDashboardsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ImportDashboard provides a mock function with given fields: ctx, dto
 func (_m *FakeDashboardService) ImportDashboard(ctx context.Context, dto *SaveDashboardDTO) (*Dashboard, error) {
 	ret := _m.Called(ctx, dto)
@@ -262,6 +481,58 @@ func (_m *FakeDashboardService) ImportDashboard(ctx context.Context, dto *SaveDa
 	return r0, r1
 }
 
+// ListDashboardChanges provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) ListDashboardChanges(ctx context.Context, query *ListDashboardChangesQuery) (*ListDashboardChangesResult, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *ListDashboardChangesResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDashboardChangesQuery) (*ListDashboardChangesResult, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDashboardChangesQuery) *ListDashboardChangesResult); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ListDashboardChangesResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ListDashboardChangesQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDashboardsAfter provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) ListDashboardsAfter(ctx context.Context, query *ListDashboardsAfterQuery) ([]*Dashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDashboardsAfterQuery) ([]*Dashboard, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDashboardsAfterQuery) []*Dashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ListDashboardsAfterQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveDashboard provides a mock function with given fields: ctx, dto, allowUiUpdate
 func (_m *FakeDashboardService) SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*Dashboard, error) {
 	ret := _m.Called(ctx, dto, allowUiUpdate)
@@ -288,6 +559,20 @@ func (_m *FakeDashboardService) SaveDashboard(ctx context.Context, dto *SaveDash
 	return r0, r1
 }
 
+// SaveDashboardLabels provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) SaveDashboardLabels(ctx context.Context, cmd *SaveDashboardLabelsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *SaveDashboardLabelsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SearchDashboards provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) SearchDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) (model.HitList, error) {
 	ret := _m.Called(ctx, query)
@@ -314,6 +599,20 @@ func (_m *FakeDashboardService) SearchDashboards(ctx context.Context, query *Fin
 	return r0, r1
 }
 
+// SetFolderReadOnly provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) SetFolderReadOnly(ctx context.Context, cmd *SetFolderReadOnlyCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *SetFolderReadOnlyCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewFakeDashboardService creates a new instance of FakeDashboardService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewFakeDashboardService(t interface {
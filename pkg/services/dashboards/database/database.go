@@ -1,6 +1,7 @@
 package database
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -9,6 +10,8 @@ import (
 
 	"xorm.io/xorm"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
@@ -18,6 +21,8 @@ import (
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 	"github.com/grafana/grafana/pkg/services/sqlstore/permissions"
@@ -29,12 +34,18 @@ import (
 	"github.com/grafana/grafana/pkg/util"
 )
 
+// tempTableBatchSize is the number of values inserted at once in the temporary tables of the searches
+const tempTableBatchSize = 500
+
 type dashboardStore struct {
 	store      db.DB
 	cfg        *setting.Cfg
 	log        log.Logger
 	features   featuremgmt.FeatureToggles
 	tagService tag.Service
+	secrets    secrets.Service
+	// filters caches the permission filters of the searches
+	filters *permissions.FilterCache
 }
 
 // SQL bean helper to save tags
@@ -44,11 +55,36 @@ type dashboardTag struct {
 	Term        string
 }
 
+// SQL bean helper to save panel type counts
+type dashboardLabel struct {
+	Id          int64
+	OrgId       int64
+	DashboardId int64
+	Name        string
+	Value       string
+}
+
+type dashboardPanelType struct {
+	Id          int64
+	OrgId       int64
+	DashboardId int64
+	PanelType   string
+	PanelCount  int
+}
+
+type dashboardDatasource struct {
+	Id            int64
+	OrgId         int64
+	DashboardId   int64
+	DatasourceUid string
+}
+
 // DashboardStore implements the Store interface
 var _ dashboards.Store = (*dashboardStore)(nil)
 
-func ProvideDashboardStore(sqlStore db.DB, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tagService tag.Service, quotaService quota.Service) (dashboards.Store, error) {
-	s := &dashboardStore{store: sqlStore, cfg: cfg, log: log.New("dashboard-store"), features: features, tagService: tagService}
+func ProvideDashboardStore(sqlStore db.DB, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tagService tag.Service, quotaService quota.Service, secretsService secrets.Service) (dashboards.Store, error) {
+	s := &dashboardStore{store: sqlStore, cfg: cfg, log: log.New("dashboard-store"), features: features, tagService: tagService, secrets: secretsService,
+		filters: permissions.NewFilterCache()}
 
 	defaultLimits, err := readQuotaConfig(cfg)
 	if err != nil {
@@ -139,6 +175,10 @@ func (d *dashboardStore) GetProvisionedDashboardData(ctx context.Context, name s
 }
 
 func (d *dashboardStore) SaveProvisionedDashboard(ctx context.Context, cmd dashboards.SaveDashboardCommand, provisioning *dashboards.DashboardProvisioning) (*dashboards.Dashboard, error) {
+	if err := d.encryptSensitiveFields(ctx, &cmd); err != nil {
+		return nil, err
+	}
+
 	var result *dashboards.Dashboard
 	var err error
 	err = d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
@@ -157,6 +197,10 @@ func (d *dashboardStore) SaveProvisionedDashboard(ctx context.Context, cmd dashb
 }
 
 func (d *dashboardStore) SaveDashboard(ctx context.Context, cmd dashboards.SaveDashboardCommand) (*dashboards.Dashboard, error) {
+	if err := d.encryptSensitiveFields(ctx, &cmd); err != nil {
+		return nil, err
+	}
+
 	var result *dashboards.Dashboard
 	var err error
 	err = d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
@@ -172,6 +216,48 @@ func (d *dashboardStore) SaveDashboard(ctx context.Context, cmd dashboards.SaveD
 	return result, err
 }
 
+// encryptSensitiveFields encrypts the sensitive fields of the dashboard about to be saved. The fields that are
+// still encrypted or redacted get the value stored for the same panel in the current dashboard or, when a version
+// is restored, in the restored version.
+func (d *dashboardStore) encryptSensitiveFields(ctx context.Context, cmd *dashboards.SaveDashboardCommand) error {
+	if len(d.cfg.SensitiveDashboardPaths) == 0 || cmd.IsFolder || cmd.Dashboard == nil {
+		return nil
+	}
+
+	var previous []*simplejson.Json
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var existing dashboards.Dashboard
+		var exists bool
+		var err error
+		if id := cmd.Dashboard.Get("id").MustInt64(); id > 0 {
+			exists, err = sess.Where("id=? AND org_id=?", id, cmd.OrgID).Get(&existing)
+		} else if uid := cmd.Dashboard.Get("uid").MustString(); uid != "" {
+			exists, err = sess.Where("uid=? AND org_id=?", uid, cmd.OrgID).Get(&existing)
+		}
+		if err != nil || !exists {
+			return err
+		}
+		previous = append(previous, existing.Data)
+
+		if cmd.RestoredFrom > 0 {
+			var restored dashver.DashboardVersion
+			exists, err := sess.Where("dashboard_id=? AND version=?", existing.ID, cmd.RestoredFrom).Get(&restored)
+			if err != nil {
+				return err
+			}
+			if exists {
+				previous = append(previous, restored.Data)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return dashboards.EncryptSensitiveFields(ctx, d.secrets, d.cfg.SensitiveDashboardPaths, cmd.Dashboard, previous...)
+}
+
 func (d *dashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []*alertmodels.Alert) error {
 	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		existingAlerts, err := GetAlertsByDashboardId2(dashID, sess)
@@ -425,10 +511,11 @@ func saveDashboard(sess *db.Session, cmd *dashboards.SaveDashboardCommand, emitE
 	}
 
 	parentVersion := dash.Version
+	isNew := dash.ID == 0
 	var affectedRows int64
 	var err error
 
-	if dash.ID == 0 {
+	if isNew {
 		dash.SetVersion(1)
 		dash.Created = time.Now()
 		dash.CreatedBy = userId
@@ -491,15 +578,108 @@ func saveDashboard(sess *db.Session, cmd *dashboards.SaveDashboardCommand, emitE
 		}
 	}
 
+	if err := savePanelTypes(sess, dash); err != nil {
+		return nil, err
+	}
+
+	if err := saveDatasources(sess, dash); err != nil {
+		return nil, err
+	}
+
+	// the labels of the folders aren't in their JSON, they are saved with SaveDashboardLabels
+	if !dash.IsFolder {
+		if err := saveLabels(sess, dash.OrgID, dash.ID, dash.GetLabels()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := recordDashboardChange(sess, dash.OrgID, dash.UID, dash.IsFolder, dash.Version, dashboards.DashboardChangeUpsert); err != nil {
+		return nil, err
+	}
+
 	if emitEntityEvent {
 		_, err := sess.Insert(createEntityEvent(dash, store.EntityEventTypeUpdate))
 		if err != nil {
 			return dash, err
 		}
 	}
+
+	if isNew {
+		sess.PublishAfterCommit(&events.DashboardCreated{
+			Timestamp: dash.Created,
+			OrgID:     dash.OrgID,
+			ID:        dash.ID,
+			UID:       dash.UID,
+			Title:     dash.Title,
+			FolderUID: dash.FolderUID,
+			IsFolder:  dash.IsFolder,
+			Version:   dash.Version,
+		})
+	} else {
+		sess.PublishAfterCommit(&events.DashboardUpdated{
+			Timestamp: dash.Updated,
+			OrgID:     dash.OrgID,
+			ID:        dash.ID,
+			UID:       dash.UID,
+			Title:     dash.Title,
+			FolderUID: dash.FolderUID,
+			IsFolder:  dash.IsFolder,
+			Version:   dash.Version,
+		})
+	}
 	return dash, nil
 }
 
+// savePanelTypes replaces the indexed panel type counts of the dashboard.
+func savePanelTypes(sess *db.Session, dash *dashboards.Dashboard) error {
+	if _, err := sess.Exec("DELETE FROM dashboard_panel_type WHERE dashboard_id=?", dash.ID); err != nil {
+		return err
+	}
+
+	if dash.IsFolder {
+		return nil
+	}
+
+	for panelType, count := range dash.GetPanelTypeCounts() {
+		if _, err := sess.Insert(dashboardPanelType{OrgId: dash.OrgID, DashboardId: dash.ID, PanelType: panelType, PanelCount: count}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveDatasources replaces the indexed data sources of the dashboard.
+func saveDatasources(sess *db.Session, dash *dashboards.Dashboard) error {
+	if _, err := sess.Exec("DELETE FROM dashboard_datasource WHERE dashboard_id=?", dash.ID); err != nil {
+		return err
+	}
+
+	if dash.IsFolder {
+		return nil
+	}
+
+	for _, uid := range dash.GetDatasourceUIDs() {
+		if _, err := sess.Insert(dashboardDatasource{OrgId: dash.OrgID, DashboardId: dash.ID, DatasourceUid: uid}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveLabels replaces the indexed labels of the dashboard or folder.
+func saveLabels(sess *db.Session, orgID, dashboardID int64, labels map[string]string) error {
+	if _, err := sess.Exec("DELETE FROM dashboard_label WHERE dashboard_id=?", dashboardID); err != nil {
+		return err
+	}
+
+	for name, value := range labels {
+		if _, err := sess.Insert(dashboardLabel{OrgId: orgID, DashboardId: dashboardID, Name: name, Value: value}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func saveProvisionedData(sess *db.Session, provisioning *dashboards.DashboardProvisioning, dashboard *dashboards.Dashboard) error {
 	result := &dashboards.DashboardProvisioning{}
 
@@ -673,6 +853,9 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 
 	deletes := []string{
 		"DELETE FROM dashboard_tag WHERE dashboard_id = ? ",
+		"DELETE FROM dashboard_panel_type WHERE dashboard_id = ? ",
+		"DELETE FROM dashboard_label WHERE dashboard_id = ? ",
+		"DELETE FROM dashboard_datasource WHERE dashboard_id = ? ",
 		"DELETE FROM star WHERE dashboard_id = ? ",
 		"DELETE FROM dashboard WHERE id = ?",
 		"DELETE FROM playlist_item WHERE type = 'dashboard_by_id' AND value = ?",
@@ -681,10 +864,16 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 		"DELETE FROM dashboard_acl WHERE dashboard_id = ?",
 	}
 
+	var children []childDashboard
 	if dashboard.IsFolder {
 		deletes = append(deletes, "DELETE FROM dashboard WHERE folder_id = ?")
 
-		if err := d.deleteChildrenDashboardAssociations(sess, &dashboard); err != nil {
+		children, err = d.deleteChildrenDashboardAssociations(sess, &dashboard)
+		if err != nil {
+			return err
+		}
+
+		if _, err := sess.Exec("DELETE FROM folder_read_only WHERE org_id = ? AND folder_uid = ?", dashboard.OrgID, dashboard.UID); err != nil {
 			return err
 		}
 
@@ -703,6 +892,12 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 		return err
 	}
 
+	if cmd.ForceDeleteRules && !dashboard.IsFolder {
+		if err := d.deleteDashboardAlertRules(sess, dashboard.OrgID, dashboard.UID); err != nil {
+			return err
+		}
+	}
+
 	_, err = sess.Exec("DELETE FROM annotation WHERE dashboard_id = ? AND org_id = ?", dashboard.ID, dashboard.OrgID)
 	if err != nil {
 		return err
@@ -721,9 +916,37 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 			return err
 		}
 	}
+
+	deleted := append([]childDashboard{{Id: dashboard.ID, Uid: dashboard.UID, Title: dashboard.Title, IsFolder: dashboard.IsFolder, Version: dashboard.Version}}, children...)
+	for _, dash := range deleted {
+		if err := recordDashboardChange(sess, dashboard.OrgID, dash.Uid, dash.IsFolder, dash.Version, dashboards.DashboardChangeDelete); err != nil {
+			return err
+		}
+		publishDashboardDeleted(sess, dashboard.OrgID, dash)
+	}
 	return nil
 }
 
+// childDashboard is a dashboard or folder deleted together with its folder
+type childDashboard struct {
+	Id       int64
+	Uid      string
+	Title    string
+	IsFolder bool
+	Version  int
+}
+
+func publishDashboardDeleted(sess *db.Session, orgID int64, dash childDashboard) {
+	sess.PublishAfterCommit(&events.DashboardDeleted{
+		Timestamp: time.Now(),
+		OrgID:     orgID,
+		ID:        dash.Id,
+		UID:       dash.Uid,
+		Title:     dash.Title,
+		IsFolder:  dash.IsFolder,
+	})
+}
+
 // FIXME: Remove me and handle nested deletions in the service with the DashboardPermissionsService
 func (d *dashboardStore) deleteResourcePermissions(sess *db.Session, orgID int64, resourceScope string) error {
 	// retrieve all permissions for the resource scope and org id
@@ -742,30 +965,32 @@ func (d *dashboardStore) deleteResourcePermissions(sess *db.Session, orgID int64
 	return err
 }
 
-func (d *dashboardStore) deleteChildrenDashboardAssociations(sess *db.Session, dashboard *dashboards.Dashboard) error {
-	var dashIds []struct {
-		Id  int64
-		Uid string
-	}
-	err := sess.SQL("SELECT id, uid FROM dashboard WHERE folder_id = ?", dashboard.ID).Find(&dashIds)
+// deleteChildrenDashboardAssociations deletes what is associated to the children of the folder and returns
+// them so that the caller can publish the deletion of the children it deletes.
+func (d *dashboardStore) deleteChildrenDashboardAssociations(sess *db.Session, dashboard *dashboards.Dashboard) ([]childDashboard, error) {
+	var dashIds []childDashboard
+	err := sess.SQL("SELECT id, uid, title, is_folder, version FROM dashboard WHERE folder_id = ?", dashboard.ID).Find(&dashIds)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(dashIds) > 0 {
 		for _, dash := range dashIds {
 			if err := d.deleteAlertDefinition(dash.Id, sess); err != nil {
-				return err
+				return nil, err
 			}
 
 			// remove all access control permission with child dashboard scopes
 			if err := d.deleteResourcePermissions(sess, dashboard.OrgID, ac.GetResourceScopeUID("dashboards", dash.Uid)); err != nil {
-				return err
+				return nil, err
 			}
 		}
 
 		childrenDeletes := []string{
 			"DELETE FROM dashboard_tag WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
+			"DELETE FROM dashboard_panel_type WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
+			"DELETE FROM dashboard_label WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
+			"DELETE FROM dashboard_datasource WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 			"DELETE FROM star WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 			"DELETE FROM dashboard_version WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 			"DELETE FROM dashboard_provisioning WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
@@ -774,17 +999,30 @@ func (d *dashboardStore) deleteChildrenDashboardAssociations(sess *db.Session, d
 
 		_, err = sess.Exec("DELETE FROM annotation WHERE org_id = ? AND dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)", dashboard.OrgID, dashboard.OrgID, dashboard.ID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for _, sql := range childrenDeletes {
 			_, err := sess.Exec(sql, dashboard.OrgID, dashboard.ID)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
-	return nil
+	return dashIds, nil
+}
+
+// recordDashboardChange appends a change to the change feed of the dashboards, in the transaction of the change
+func recordDashboardChange(sess *db.Session, orgID int64, uid string, isFolder bool, version int, action dashboards.DashboardChangeAction) error {
+	_, err := sess.Insert(&dashboards.DashboardChange{
+		OrgID:        orgID,
+		DashboardUID: uid,
+		IsFolder:     isFolder,
+		Action:       action,
+		Version:      version,
+		Created:      time.Now(),
+	})
+	return err
 }
 
 func createEntityEvent(dashboard *dashboards.Dashboard, eventType store.EntityEventType) *store.EntityEvent {
@@ -822,6 +1060,61 @@ func (d *dashboardStore) deleteAlertDefinition(dashboardId int64, sess *db.Sessi
 	return nil
 }
 
+// deleteDashboardAlertRules deletes the alert rules linked to the panels of a dashboard, with their versions and state
+func (d *dashboardStore) deleteDashboardAlertRules(sess *db.Session, orgID int64, dashboardUID string) error {
+	var ruleUIDs []string
+	if err := sess.Table("alert_rule").Where("org_id = ? AND dashboard_uid = ?", orgID, dashboardUID).Cols("uid").Find(&ruleUIDs); err != nil {
+		return err
+	}
+	if len(ruleUIDs) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ruleUIDs)), ",")
+	args := []any{orgID}
+	for _, uid := range ruleUIDs {
+		args = append(args, uid)
+	}
+	deletes := []string{
+		"DELETE FROM alert_rule WHERE org_id = ? AND uid IN (" + placeholders + ")",
+		"DELETE FROM alert_rule_version WHERE rule_org_id = ? AND rule_uid IN (" + placeholders + ")",
+		"DELETE FROM alert_instance WHERE rule_org_id = ? AND rule_uid IN (" + placeholders + ")",
+	}
+	for _, sql := range deletes {
+		if _, err := sess.Exec(append([]any{sql}, args...)...); err != nil {
+			return err
+		}
+	}
+	d.log.Info("Deleted the alert rules of a dashboard", "orgId", orgID, "dashboardUid", dashboardUID, "count", len(ruleUIDs))
+	return nil
+}
+
+// GetDashboardDependents returns the alert rules linked to the panels of a dashboard and the number of its annotations
+func (d *dashboardStore) GetDashboardDependents(ctx context.Context, query *dashboards.GetDashboardDependentsQuery) (*dashboards.DashboardDependents, error) {
+	dependents := &dashboards.DashboardDependents{AlertRules: []dashboards.DashboardDependentAlertRule{}}
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		dashboard := dashboards.Dashboard{OrgID: query.OrgID, UID: query.UID}
+		has, err := sess.Get(&dashboard)
+		if err != nil {
+			return err
+		} else if !has {
+			return dashboards.ErrDashboardNotFound
+		}
+
+		if err := sess.Table("alert_rule").Where("org_id = ? AND dashboard_uid = ?", query.OrgID, query.UID).
+			Cols("uid", "title", "namespace_uid", "panel_id").OrderBy("title").Find(&dependents.AlertRules); err != nil {
+			return err
+		}
+
+		dependents.Annotations, err = sess.Table("annotation").Where("org_id = ? AND dashboard_id = ?", query.OrgID, dashboard.ID).Count()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dependents, nil
+}
+
 func (d *dashboardStore) GetDashboard(ctx context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
 	var queryResult *dashboards.Dashboard
 	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
@@ -908,13 +1201,46 @@ func (d *dashboardStore) GetDashboards(ctx context.Context, query *dashboards.Ge
 }
 
 func (d *dashboardStore) FindDashboards(ctx context.Context, query *dashboards.FindPersistedDashboardsQuery) ([]dashboards.DashboardSearchProjection, error) {
+	filters, err := d.searchFilters(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []dashboards.DashboardSearchProjection
+	sb := &searchstore.Builder{Dialect: d.store.GetDialect(), Filters: filters, Features: d.features}
+
+	limit := query.Limit
+	if limit < 1 {
+		limit = 1000
+	}
+
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+
+	sql, params := sb.ToSQL(limit, page)
+
+	err = d.withSearchSession(ctx, filters, func(sess *db.Session) error {
+		return sess.SQL(sql, params...).Find(&res)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// searchFilters returns the search filters of the query, including the permission filter of the signed in user
+func (d *dashboardStore) searchFilters(ctx context.Context, query *dashboards.FindPersistedDashboardsQuery) ([]any, error) {
 	recursiveQueriesAreSupported, err := d.store.RecursiveQueriesAreSupported()
 	if err != nil {
 		return nil, err
 	}
 
 	filters := []any{
-		permissions.NewAccessControlDashboardPermissionFilter(query.SignedInUser, query.Permission, query.Type, d.features, recursiveQueriesAreSupported),
+		d.filters.Get(query.SignedInUser, query.Permission, query.Type, d.features, recursiveQueriesAreSupported),
 	}
 
 	for _, filter := range query.Sort.Filter {
@@ -936,6 +1262,10 @@ func (d *dashboardStore) FindDashboards(ctx context.Context, query *dashboards.F
 		filters = append(filters, searchstore.TagsFilter{Tags: query.Tags})
 	}
 
+	if len(query.Labels) > 0 {
+		filters = append(filters, searchstore.LabelsFilter{Selectors: query.Labels})
+	}
+
 	if len(query.DashboardUIDs) > 0 {
 		filters = append(filters, searchstore.DashboardFilter{UIDs: query.DashboardUIDs})
 	} else if len(query.DashboardIds) > 0 {
@@ -964,30 +1294,156 @@ func (d *dashboardStore) FindDashboards(ctx context.Context, query *dashboards.F
 		})
 	}
 
-	var res []dashboards.DashboardSearchProjection
-	sb := &searchstore.Builder{Dialect: d.store.GetDialect(), Filters: filters, Features: d.features}
+	return filters, nil
+}
+
+// GetSearchFacets returns the most frequent values of the tags, folders, data sources and panel types of
+// the dashboards and folders matching the query. The limit of the query is the number of values per facet.
+func (d *dashboardStore) GetSearchFacets(ctx context.Context, query *dashboards.FindPersistedDashboardsQuery) (*model.Facets, error) {
+	filters, err := d.searchFilters(ctx, query)
+	if err != nil {
+		return nil, err
+	}
 
 	limit := query.Limit
 	if limit < 1 {
-		limit = 1000
+		limit = 50
+	}
+
+	facets := &model.Facets{}
+	err = d.withSearchSession(ctx, filters, func(sess *db.Session) error {
+		for _, f := range []struct {
+			facet  searchstore.Facet
+			values *[]*model.FacetValue
+		}{
+			{searchstore.Facet{Column: "dashboard_tag.term", Join: "dashboard_tag ON dashboard_tag.dashboard_id = dashboard.id"}, &facets.Tags},
+			{searchstore.Facet{Column: "COALESCE(dashboard.folder_uid, '')"}, &facets.Folders},
+			{searchstore.Facet{Column: "dashboard_datasource.datasource_uid", Join: "dashboard_datasource ON dashboard_datasource.dashboard_id = dashboard.id"}, &facets.Datasources},
+			{searchstore.Facet{Column: "dashboard_panel_type.panel_type", Join: "dashboard_panel_type ON dashboard_panel_type.dashboard_id = dashboard.id"}, &facets.PanelTypes},
+		} {
+			sb := &searchstore.Builder{Dialect: d.store.GetDialect(), Filters: filters, Features: d.features}
+			sql, params := sb.ToFacetSQL(f.facet, limit)
+
+			var rows []struct {
+				FacetValue string
+				FacetCount int64
+			}
+			if err := sess.SQL(sql, params...).Find(&rows); err != nil {
+				return err
+			}
+			*f.values = make([]*model.FacetValue, 0, len(rows))
+			for _, row := range rows {
+				*f.values = append(*f.values, &model.FacetValue{Value: row.FacetValue, Count: row.FacetCount})
+			}
+		}
+
+		orgID := query.OrgId
+		if orgID == 0 {
+			orgID = query.SignedInUser.GetOrgID()
+		}
+		return d.setFolderFacetTitles(sess, orgID, facets.Folders)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return facets, nil
+}
 
-	page := query.Page
-	if page < 1 {
-		page = 1
+// CountSearchResults counts the dashboards and folders matching the query, up to max if max is positive
+func (d *dashboardStore) CountSearchResults(ctx context.Context, query *dashboards.FindPersistedDashboardsQuery, max int64) (int64, error) {
+	filters, err := d.searchFilters(ctx, query)
+	if err != nil {
+		return 0, err
 	}
 
-	sql, params := sb.ToSQL(limit, page)
+	sb := &searchstore.Builder{Dialect: d.store.GetDialect(), Filters: filters, Features: d.features}
+	sql, params := sb.ToCountSQL(max)
 
-	err = d.store.WithDbSession(ctx, func(sess *db.Session) error {
-		return sess.SQL(sql, params...).Find(&res)
+	var total int64
+	err = d.withSearchSession(ctx, filters, func(sess *db.Session) error {
+		_, err := sess.SQL(sql, params...).Get(&total)
+		return err
 	})
+	return total, err
+}
 
+// withSearchSession runs the searches of the filters in a session, in which the temporary tables joined by the
+// filters are created for the duration of the transaction
+func (d *dashboardStore) withSearchSession(ctx context.Context, filters []any, fn func(sess *db.Session) error) error {
+	var tables []model.TempTable
+	for _, f := range filters {
+		if f, ok := f.(model.FilterTempTables); ok {
+			tables = append(tables, f.TempTables()...)
+		}
+	}
+	if len(tables) == 0 {
+		return d.store.WithDbSession(ctx, fn)
+	}
+
+	// the temporary tables only exist in the connection of the transaction
+	return d.store.InTransaction(ctx, func(ctx context.Context) error {
+		return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+			dropStatement := "DROP TABLE "
+			if d.store.GetDialect().DriverName() == migrator.MySQL {
+				dropStatement = "DROP TEMPORARY TABLE "
+			}
+
+			for _, t := range tables {
+				if _, err := sess.Exec("CREATE TEMPORARY TABLE " + t.Name + " (uid VARCHAR(40) NOT NULL)"); err != nil {
+					return err
+				}
+				for start := 0; start < len(t.Values); start += tempTableBatchSize {
+					values := t.Values[start:min(start+tempTableBatchSize, len(t.Values))]
+					insert := "INSERT INTO " + t.Name + " (uid) VALUES (?)" + strings.Repeat(", (?)", len(values)-1)
+					if _, err := sess.Exec(append([]any{insert}, values...)...); err != nil {
+						return err
+					}
+				}
+			}
+
+			err := fn(sess)
+			for _, t := range tables {
+				// the tables are dropped by the rollback of the transaction if the search failed, but MySQL
+				// keeps them in the connection
+				if _, dropErr := sess.Exec(dropStatement + t.Name); dropErr != nil && err == nil {
+					err = dropErr
+				}
+			}
+			return err
+		})
+	})
+}
+
+func (d *dashboardStore) setFolderFacetTitles(sess *db.Session, orgID int64, folders []*model.FacetValue) error {
+	uids := make([]string, 0, len(folders))
+	for _, f := range folders {
+		if f.Value != "" {
+			uids = append(uids, f.Value)
+		}
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+
+	var rows []struct {
+		UID   string `xorm:"uid"`
+		Title string
+	}
+	err := sess.Table("dashboard").Cols("uid", "title").
+		Where("org_id = ? AND is_folder = ?", orgID, d.store.GetDialect().BooleanStr(true)).
+		In("uid", uids).Find(&rows)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return res, nil
+	titles := make(map[string]string, len(rows))
+	for _, row := range rows {
+		titles[row.UID] = row.Title
+	}
+	for _, f := range folders {
+		f.Title = titles[f.Value]
+	}
+	return nil
 }
 
 func (d *dashboardStore) GetDashboardTags(ctx context.Context, query *dashboards.GetDashboardTagsQuery) ([]*dashboards.DashboardTagCloudItem, error) {
@@ -1012,6 +1468,224 @@ func (d *dashboardStore) GetDashboardTags(ctx context.Context, query *dashboards
 	return queryResult, nil
 }
 
+// GetPanelTypeUsage returns the number of panels and dashboards using each panel type in the org.
+func (d *dashboardStore) GetPanelTypeUsage(ctx context.Context, query *dashboards.GetPanelTypeUsageQuery) ([]*dashboards.PanelTypeUsage, error) {
+	result := make([]*dashboards.PanelTypeUsage, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		sql := `SELECT
+					panel_type,
+					SUM(panel_count) AS panels,
+					COUNT(DISTINCT dashboard_id) AS dashboards
+				FROM dashboard_panel_type
+				WHERE org_id = ?
+				GROUP BY panel_type
+				ORDER BY panel_type`
+		return sess.SQL(sql, query.OrgID).Find(&result)
+	})
+	return result, err
+}
+
+func (d *dashboardStore) GetDashboardLabels(ctx context.Context, query *dashboards.GetDashboardLabelsQuery) (map[string]string, error) {
+	labels := make(map[string]string)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var rows []dashboardLabel
+		if err := sess.Where("org_id = ? AND dashboard_id = ?", query.OrgID, query.DashboardID).Find(&rows); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			labels[row.Name] = row.Value
+		}
+		return nil
+	})
+	return labels, err
+}
+
+func (d *dashboardStore) GetReadOnlyFolders(ctx context.Context, orgID int64, folderUIDs []string) ([]*dashboards.FolderReadOnly, error) {
+	readOnly := make([]*dashboards.FolderReadOnly, 0)
+	if len(folderUIDs) == 0 {
+		return readOnly, nil
+	}
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).In("folder_uid", folderUIDs).Find(&readOnly)
+	})
+	return readOnly, err
+}
+
+func (d *dashboardStore) SetFolderReadOnly(ctx context.Context, cmd *dashboards.SetFolderReadOnlyCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("org_id = ? AND folder_uid = ?", cmd.OrgID, cmd.FolderUID).Delete(&dashboards.FolderReadOnly{}); err != nil {
+			return err
+		}
+		if !cmd.ReadOnly {
+			return nil
+		}
+		_, err := sess.Insert(&dashboards.FolderReadOnly{
+			OrgID:     cmd.OrgID,
+			FolderUID: cmd.FolderUID,
+			Reason:    cmd.Reason,
+			CreatedBy: cmd.UserID,
+			Created:   time.Now(),
+		})
+		return err
+	})
+}
+
+func (d *dashboardStore) SaveDashboardLabels(ctx context.Context, cmd *dashboards.SaveDashboardLabelsCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Table("dashboard").Where("org_id = ? AND id = ?", cmd.OrgID, cmd.DashboardID).Exist()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return dashboards.ErrDashboardNotFound
+		}
+		return saveLabels(sess, cmd.OrgID, cmd.DashboardID, cmd.Labels)
+	})
+}
+
+func (d *dashboardStore) SaveDashboardACLAudit(ctx context.Context, entry *dashboards.DashboardACLAuditEntry) error {
+	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(entry)
+		return err
+	})
+}
+
+func (d *dashboardStore) GetDashboardACLAudit(ctx context.Context, query *dashboards.GetDashboardACLAuditQuery) ([]*dashboards.DashboardACLAuditEntry, error) {
+	result := make([]*dashboards.DashboardACLAuditEntry, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id = ? AND dashboard_uid = ?", query.OrgID, query.DashboardUID).Desc("created", "id")
+		if query.Limit > 0 {
+			offset := 0
+			if query.Page > 1 {
+				offset = (query.Page - 1) * query.Limit
+			}
+			sess.Limit(query.Limit, offset)
+		}
+		return sess.Find(&result)
+	})
+	return result, err
+}
+
+func (d *dashboardStore) ListDashboardsAfter(ctx context.Context, query *dashboards.ListDashboardsAfterQuery) ([]*dashboards.Dashboard, error) {
+	result := make([]*dashboards.Dashboard, 0, query.Limit)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND id > ? AND is_folder = ?", query.OrgID, query.AfterID, d.store.GetDialect().BooleanStr(false)).
+			OrderBy("id").Limit(query.Limit).Find(&result)
+	})
+	return result, err
+}
+
+func (d *dashboardStore) ListDashboardChanges(ctx context.Context, query *dashboards.ListDashboardChangesQuery) (*dashboards.ListDashboardChangesResult, error) {
+	result := &dashboards.ListDashboardChangesResult{Changes: make([]*dashboards.DashboardChange, 0), LastID: query.AfterID}
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		// the IDs are shared by the orgs, the head is the last change of any org
+		head := func() (int64, error) {
+			var id int64
+			sql, args := "SELECT COALESCE(MAX(id), 0) FROM dashboard_change", []any{}
+			if !query.Before.IsZero() {
+				sql, args = sql+" WHERE created < ?", append(args, query.Before)
+			}
+			_, err := sess.SQL(sql, args...).Get(&id)
+			return id, err
+		}
+		if query.Head {
+			var err error
+			result.LastID, err = head()
+			return err
+		}
+
+		if query.AfterID > 0 {
+			// the retention keeps the last change, so the changes after the cursor expired if the oldest
+			// change left isn't the one right after it
+			var oldest int64
+			if _, err := sess.SQL("SELECT COALESCE(MIN(id), 0) FROM dashboard_change").Get(&oldest); err != nil {
+				return err
+			}
+			if oldest > query.AfterID+1 {
+				result.Expired = true
+				return nil
+			}
+		}
+
+		sess.Where("org_id = ? AND id > ?", query.OrgID, query.AfterID)
+		if !query.Before.IsZero() {
+			sess.And("created < ?", query.Before)
+		}
+		if err := sess.OrderBy("id").Limit(query.Limit).Find(&result.Changes); err != nil {
+			return err
+		}
+		if len(result.Changes) > 0 {
+			result.LastID = result.Changes[len(result.Changes)-1].ID
+		}
+		if len(result.Changes) < query.Limit {
+			// the feed of the org was read to the end, the cursor moves past the changes of the other orgs
+			// so that it doesn't expire while there are no changes in the org
+			last, err := head()
+			if err != nil {
+				return err
+			}
+			result.LastID = max(result.LastID, last)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (d *dashboardStore) DeleteExpiredDashboardChanges(ctx context.Context, olderThan time.Time) (int64, error) {
+	var deleted int64
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		// the last change is kept, it tells the consumers of the feed whether their cursor expired
+		var last int64
+		if _, err := sess.SQL("SELECT COALESCE(MAX(id), 0) FROM dashboard_change").Get(&last); err != nil {
+			return err
+		}
+		res, err := sess.Exec("DELETE FROM dashboard_change WHERE created < ? AND id < ?", olderThan, last)
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
+
+func (d *dashboardStore) NormalizeStoredDashboards(ctx context.Context, cmd *dashboards.NormalizeStoredDashboardsCommand) (*dashboards.NormalizeStoredDashboardsResult, error) {
+	result := &dashboards.NormalizeStoredDashboardsResult{LastID: cmd.AfterID}
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var rows []*dashboards.Dashboard
+		err := sess.Cols("id", "data").Where("id > ? AND is_folder = ?", cmd.AfterID, d.store.GetDialect().BooleanStr(false)).
+			OrderBy("id").Limit(cmd.Limit).Find(&rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			result.LastID = row.ID
+			before, err := row.Data.Encode()
+			if err != nil {
+				return err
+			}
+			normalized := dashboards.NormalizeDashboardData(row.Data)
+			after, err := normalized.Encode()
+			if err != nil {
+				return err
+			}
+			if bytes.Equal(before, after) {
+				continue
+			}
+
+			if _, err := sess.ID(row.ID).Cols("data").Update(&dashboards.Dashboard{Data: normalized}); err != nil {
+				return err
+			}
+			result.Updated++
+		}
+
+		result.Done = len(rows) < cmd.Limit
+		return nil
+	})
+	return result, err
+}
+
 // CountDashboardsInFolder returns a count of all dashboards associated with the
 // given parent folder ID.
 func (d *dashboardStore) CountDashboardsInFolders(
@@ -1056,7 +1730,8 @@ func (d *dashboardStore) DeleteDashboardsInFolders(
 				return dashboards.ErrFolderNotFound
 			}
 
-			if err := d.deleteChildrenDashboardAssociations(sess, &dashboard); err != nil {
+			children, err := d.deleteChildrenDashboardAssociations(sess, &dashboard)
+			if err != nil {
 				return err
 			}
 
@@ -1064,6 +1739,16 @@ func (d *dashboardStore) DeleteDashboardsInFolders(
 			if err != nil {
 				return err
 			}
+
+			// the subfolders are left in place
+			for _, child := range children {
+				if !child.IsFolder {
+					if err := recordDashboardChange(sess, req.OrgID, child.Uid, false, child.Version, dashboards.DashboardChangeDelete); err != nil {
+						return err
+					}
+					publishDashboardDeleted(sess, req.OrgID, child)
+				}
+			}
 		}
 		return nil
 	})
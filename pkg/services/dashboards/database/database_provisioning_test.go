@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	secretstest "github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
 )
 
@@ -20,7 +21,7 @@ func TestIntegrationDashboardProvisioningTest(t *testing.T) {
 	}
 	sqlStore := db.InitTestDB(t)
 	quotaService := quotatest.New(false, nil)
-	dashboardStore, err := ProvideDashboardStore(sqlStore, sqlStore.Cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService)
+	dashboardStore, err := ProvideDashboardStore(sqlStore, sqlStore.Cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService, secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 
 	folderCmd := dashboards.SaveDashboardCommand{
@@ -12,18 +12,22 @@ import (
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/folder/folderimpl"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/search/model"
+	secretstest "github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 	"github.com/grafana/grafana/pkg/services/supportbundles/supportbundlestest"
@@ -52,7 +56,7 @@ func TestIntegrationDashboardDataAccess(t *testing.T) {
 		sqlStore, cfg = db.InitTestDBwithCfg(t)
 		quotaService := quotatest.New(false, nil)
 		var err error
-		dashboardStore, err = ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService)
+		dashboardStore, err = ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService, secretstest.NewFakeSecretsService())
 		require.NoError(t, err)
 		// insertTestDashboard creates the following hierarchy:
 		// 1 test dash folder
@@ -223,6 +227,59 @@ func TestIntegrationDashboardDataAccess(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Should publish events when dashboards are created, updated and deleted", func(t *testing.T) {
+		setup()
+		var created []*events.DashboardCreated
+		var updated []*events.DashboardUpdated
+		var deleted []*events.DashboardDeleted
+		sqlStore.Bus().AddEventListener(func(_ context.Context, e *events.DashboardCreated) error {
+			created = append(created, e)
+			return nil
+		})
+		sqlStore.Bus().AddEventListener(func(_ context.Context, e *events.DashboardUpdated) error {
+			updated = append(updated, e)
+			return nil
+		})
+		sqlStore.Bus().AddEventListener(func(_ context.Context, e *events.DashboardDeleted) error {
+			deleted = append(deleted, e)
+			return nil
+		})
+
+		dash := insertTestDashboard(t, dashboardStore, "events", 1, savedFolder.ID, savedFolder.UID, false)
+		require.Len(t, created, 1)
+		require.Equal(t, dash.UID, created[0].UID)
+		require.Equal(t, savedFolder.UID, created[0].FolderUID)
+		require.Equal(t, 1, created[0].Version)
+
+		_, err := dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+			OrgID:     dash.OrgID,
+			FolderID:  dash.FolderID, // nolint:staticcheck
+			FolderUID: dash.FolderUID,
+			Dashboard: dash.Data,
+			Overwrite: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, updated, 1)
+		require.Equal(t, dash.ID, updated[0].ID)
+		require.Equal(t, 2, updated[0].Version)
+
+		err = dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1})
+		require.NoError(t, err)
+		// the dashboards in the folder are deleted with it
+		deletedTitles := make([]string, 0, len(deleted))
+		for _, e := range deleted {
+			deletedTitles = append(deletedTitles, e.Title)
+		}
+		require.ElementsMatch(t, []string{"1 test dash folder", "test dash 23", "test dash 45", "events"}, deletedTitles)
+
+		_, err = dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+			OrgID:     1,
+			Dashboard: simplejson.NewFromAny(map[string]any{"id": 9999, "title": "missing"}),
+		})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+		require.Len(t, updated, 1, "no events are published when the transaction fails")
+	})
+
 	t.Run("Should be able to create dashboard", func(t *testing.T) {
 		setup()
 		cmd := dashboards.SaveDashboardCommand{
@@ -334,6 +391,277 @@ func TestIntegrationDashboardDataAccess(t *testing.T) {
 		require.Equal(t, len(queryResult), 2)
 	})
 
+	t.Run("Should index panel types on save and delete", func(t *testing.T) {
+		setup()
+		save := func(title string, panels ...string) *dashboards.Dashboard {
+			panelList := make([]interface{}, 0, len(panels))
+			for _, p := range panels {
+				panelList = append(panelList, map[string]interface{}{"type": p})
+			}
+			dash, err := dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+				OrgID: 1,
+				Dashboard: simplejson.NewFromAny(map[string]interface{}{
+					"title":  title,
+					"panels": panelList,
+				}),
+			})
+			require.NoError(t, err)
+			return dash
+		}
+		save("panels 1", "timeseries", "timeseries", "stat")
+		dash := save("panels 2", "timeseries", "table")
+
+		queryResult, err := dashboardStore.GetPanelTypeUsage(context.Background(), &dashboards.GetPanelTypeUsageQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []*dashboards.PanelTypeUsage{
+			{PanelType: "stat", Panels: 1, Dashboards: 1},
+			{PanelType: "table", Panels: 1, Dashboards: 1},
+			{PanelType: "timeseries", Panels: 3, Dashboards: 2},
+		}, queryResult)
+
+		err = dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: dash.ID, OrgID: 1})
+		require.NoError(t, err)
+
+		queryResult, err = dashboardStore.GetPanelTypeUsage(context.Background(), &dashboards.GetPanelTypeUsageQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []*dashboards.PanelTypeUsage{
+			{PanelType: "stat", Panels: 1, Dashboards: 1},
+			{PanelType: "timeseries", Panels: 2, Dashboards: 1},
+		}, queryResult)
+	})
+
+	t.Run("Should record and list ACL audit entries most recent first", func(t *testing.T) {
+		setup()
+		viewer := org.RoleViewer
+		for i, permission := range []dashboardaccess.PermissionType{dashboardaccess.PERMISSION_VIEW, dashboardaccess.PERMISSION_EDIT} {
+			err := dashboardStore.SaveDashboardACLAudit(context.Background(), &dashboards.DashboardACLAuditEntry{
+				OrgID:        1,
+				DashboardUID: savedDash.UID,
+				ActorID:      1,
+				ActorLogin:   "admin",
+				Previous:     []dashboards.DashboardACLAuditItem{},
+				New:          []dashboards.DashboardACLAuditItem{{Role: &viewer, Permission: permission}},
+				Created:      time.Now().Add(time.Duration(i) * time.Minute),
+			})
+			require.NoError(t, err)
+		}
+
+		entries, err := dashboardStore.GetDashboardACLAudit(context.Background(), &dashboards.GetDashboardACLAuditQuery{OrgID: 1, DashboardUID: savedDash.UID})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, "admin", entries[0].ActorLogin)
+		require.Equal(t, []dashboards.DashboardACLAuditItem{{Role: &viewer, Permission: dashboardaccess.PERMISSION_EDIT}}, entries[0].New)
+		require.Equal(t, dashboardaccess.PERMISSION_VIEW, entries[1].New[0].Permission)
+
+		entries, err = dashboardStore.GetDashboardACLAudit(context.Background(), &dashboards.GetDashboardACLAuditQuery{OrgID: 1, DashboardUID: savedDash.UID, Limit: 1, Page: 2})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, dashboardaccess.PERMISSION_VIEW, entries[0].New[0].Permission)
+	})
+
+	t.Run("Should normalize stored dashboards in batches", func(t *testing.T) {
+		setup()
+		savedDash.Data.Set("description", nil)
+		savedDash.Data.Set("panels", []any{map[string]any{"id": 1, "transparent": false}})
+		_, err := dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+			OrgID:     savedDash.OrgID,
+			FolderUID: savedDash.FolderUID,
+			Dashboard: savedDash.Data,
+			Overwrite: true,
+		})
+		require.NoError(t, err)
+
+		var afterID int64
+		var updated int
+		for {
+			result, err := dashboardStore.NormalizeStoredDashboards(context.Background(), &dashboards.NormalizeStoredDashboardsCommand{AfterID: afterID, Limit: 2})
+			require.NoError(t, err)
+			updated += result.Updated
+			afterID = result.LastID
+			if result.Done {
+				break
+			}
+		}
+		// the other test dashboards are saved with a null id
+		require.NotZero(t, updated)
+
+		dash, err := dashboardStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{OrgID: savedDash.OrgID, UID: savedDash.UID})
+		require.NoError(t, err)
+		_, ok := dash.Data.CheckGet("description")
+		require.False(t, ok)
+		require.Equal(t, []any{map[string]any{"id": json.Number("1")}}, dash.Data.Get("panels").MustArray())
+
+		result, err := dashboardStore.NormalizeStoredDashboards(context.Background(), &dashboards.NormalizeStoredDashboardsCommand{Limit: 100})
+		require.NoError(t, err)
+		require.Zero(t, result.Updated)
+		require.True(t, result.Done)
+	})
+
+	t.Run("Should list the dashboards in batches in the order of their IDs", func(t *testing.T) {
+		setup()
+		var titles []string
+		var afterID int64
+		for {
+			batch, err := dashboardStore.ListDashboardsAfter(context.Background(), &dashboards.ListDashboardsAfterQuery{OrgID: 1, AfterID: afterID, Limit: 2})
+			require.NoError(t, err)
+			for _, dash := range batch {
+				require.Greater(t, dash.ID, afterID)
+				afterID = dash.ID
+				titles = append(titles, dash.Title)
+			}
+			if len(batch) < 2 {
+				break
+			}
+		}
+		require.Equal(t, []string{"test dash 23", "test dash 45", "test dash 67"}, titles)
+	})
+
+	t.Run("Should index labels and find dashboards and folders by label selectors", func(t *testing.T) {
+		setup()
+		save := func(title string, labels map[string]any) *dashboards.Dashboard {
+			dash, err := dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+				OrgID:     1,
+				Dashboard: simplejson.NewFromAny(map[string]any{"title": title, "labels": labels}),
+			})
+			require.NoError(t, err)
+			return dash
+		}
+		prod := save("labels prod", map[string]any{"env": "prod", "team": "db"})
+		save("labels staging", map[string]any{"env": "staging"})
+		save("labels none", nil)
+
+		err := dashboardStore.SaveDashboardLabels(context.Background(), &dashboards.SaveDashboardLabelsCommand{
+			OrgID: 1, DashboardID: savedFolder.ID, Labels: map[string]string{"env": "prod"},
+		})
+		require.NoError(t, err)
+		labels, err := dashboardStore.GetDashboardLabels(context.Background(), &dashboards.GetDashboardLabelsQuery{OrgID: 1, DashboardID: prod.ID})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"env": "prod", "team": "db"}, labels)
+
+		find := func(selectors ...string) []string {
+			query := dashboards.FindPersistedDashboardsQuery{
+				OrgId: 1,
+				SignedInUser: &user.SignedInUser{
+					OrgID:   1,
+					OrgRole: org.RoleEditor,
+					Permissions: map[int64]map[string][]string{1: {
+						dashboards.ActionDashboardsRead: []string{dashboards.ScopeDashboardsAll},
+						dashboards.ActionFoldersRead:    []string{dashboards.ScopeFoldersAll},
+					}},
+				},
+			}
+			for _, s := range selectors {
+				selector, err := model.ParseLabelSelector(s)
+				require.NoError(t, err)
+				query.Labels = append(query.Labels, selector)
+			}
+			hits, err := testSearchDashboards(dashboardStore, &query)
+			require.NoError(t, err)
+			titles := make([]string, 0, len(hits))
+			for _, hit := range hits {
+				titles = append(titles, hit.Title)
+			}
+			return titles
+		}
+		require.Equal(t, []string{"1 test dash folder", "labels prod"}, find("env=prod"))
+		require.Equal(t, []string{"labels prod"}, find("env=prod", "team"))
+		require.Equal(t, []string{"labels staging"}, find("env", "env!=prod"))
+		require.Equal(t, []string{"labels none", "test dash 23", "test dash 45", "test dash 67"}, find("!env"))
+
+		// the folder labels are kept when the folder is saved, the dashboard labels are replaced
+		_, err = dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+			OrgID: 1, IsFolder: true, Overwrite: true,
+			Dashboard: simplejson.NewFromAny(map[string]any{"id": savedFolder.ID, "uid": savedFolder.UID, "title": "1 test dash folder"}),
+		})
+		require.NoError(t, err)
+		_, err = dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+			OrgID: 1, Overwrite: true,
+			Dashboard: simplejson.NewFromAny(map[string]any{"id": prod.ID, "uid": prod.UID, "title": "labels prod", "labels": map[string]any{"env": "dev"}}),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"1 test dash folder"}, find("env=prod"))
+
+		err = dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: prod.ID, OrgID: 1})
+		require.NoError(t, err)
+		labels, err = dashboardStore.GetDashboardLabels(context.Background(), &dashboards.GetDashboardLabelsQuery{OrgID: 1, DashboardID: prod.ID})
+		require.NoError(t, err)
+		require.Empty(t, labels)
+	})
+
+	t.Run("Should count the search results per tag, folder, data source and panel type", func(t *testing.T) {
+		setup()
+		_, err := dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+			OrgID: 1,
+			Dashboard: simplejson.NewFromAny(map[string]any{"title": "facets", "tags": []any{"webapp"}, "panels": []any{
+				map[string]any{"type": "timeseries", "datasource": map[string]any{"uid": "prom"}},
+				map[string]any{"type": "stat", "datasource": map[string]any{"uid": "prom"}, "targets": []any{map[string]any{"datasource": map[string]any{"uid": "loki"}}}},
+			}}),
+		})
+		require.NoError(t, err)
+
+		facets := func(query dashboards.FindPersistedDashboardsQuery) *model.Facets {
+			query.OrgId = 1
+			query.SignedInUser = &user.SignedInUser{
+				OrgID:   1,
+				OrgRole: org.RoleEditor,
+				Permissions: map[int64]map[string][]string{1: {
+					dashboards.ActionDashboardsRead: []string{dashboards.ScopeDashboardsAll},
+					dashboards.ActionFoldersRead:    []string{dashboards.ScopeFoldersAll},
+				}},
+			}
+			result, err := dashboardStore.GetSearchFacets(context.Background(), &query)
+			require.NoError(t, err)
+			return result
+		}
+
+		result := facets(dashboards.FindPersistedDashboardsQuery{})
+		require.Equal(t, []*model.FacetValue{{Value: "prod", Count: 4}, {Value: "webapp", Count: 3}}, result.Tags)
+		require.Equal(t, []*model.FacetValue{{Value: "", Count: 3}, {Value: savedFolder.UID, Title: "1 test dash folder", Count: 2}}, result.Folders)
+		require.Equal(t, []*model.FacetValue{{Value: "loki", Count: 1}, {Value: "prom", Count: 1}}, result.Datasources)
+		require.Equal(t, []*model.FacetValue{{Value: "stat", Count: 1}, {Value: "timeseries", Count: 1}}, result.PanelTypes)
+
+		result = facets(dashboards.FindPersistedDashboardsQuery{Tags: []string{"webapp"}, Type: searchstore.TypeDashboard, Limit: 1})
+		require.Equal(t, []*model.FacetValue{{Value: "webapp", Count: 2}}, result.Tags)
+		require.Equal(t, []*model.FacetValue{{Value: "", Count: 1}}, result.Folders)
+	})
+
+	t.Run("Should set and clear the read-only flag of folders and delete it with the folder", func(t *testing.T) {
+		setup()
+		ctx := context.Background()
+		err := dashboardStore.SetFolderReadOnly(ctx, &dashboards.SetFolderReadOnlyCommand{
+			OrgID: 1, FolderUID: savedFolder.UID, ReadOnly: true, Reason: "audit", UserID: 3,
+		})
+		require.NoError(t, err)
+		err = dashboardStore.SetFolderReadOnly(ctx, &dashboards.SetFolderReadOnlyCommand{
+			OrgID: 1, FolderUID: savedFolder.UID, ReadOnly: true, Reason: "incident review", UserID: 4,
+		})
+		require.NoError(t, err)
+
+		flags, err := dashboardStore.GetReadOnlyFolders(ctx, 1, []string{savedFolder.UID, "other"})
+		require.NoError(t, err)
+		require.Len(t, flags, 1)
+		require.Equal(t, "incident review", flags[0].Reason)
+		require.Equal(t, int64(4), flags[0].CreatedBy)
+
+		flags, err = dashboardStore.GetReadOnlyFolders(ctx, 2, []string{savedFolder.UID})
+		require.NoError(t, err)
+		require.Empty(t, flags)
+
+		err = dashboardStore.SetFolderReadOnly(ctx, &dashboards.SetFolderReadOnlyCommand{OrgID: 1, FolderUID: savedFolder.UID})
+		require.NoError(t, err)
+		flags, err = dashboardStore.GetReadOnlyFolders(ctx, 1, []string{savedFolder.UID})
+		require.NoError(t, err)
+		require.Empty(t, flags)
+
+		err = dashboardStore.SetFolderReadOnly(ctx, &dashboards.SetFolderReadOnlyCommand{OrgID: 1, FolderUID: savedFolder.UID, ReadOnly: true})
+		require.NoError(t, err)
+		err = dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1})
+		require.NoError(t, err)
+		flags, err = dashboardStore.GetReadOnlyFolders(ctx, 1, []string{savedFolder.UID})
+		require.NoError(t, err)
+		require.Empty(t, flags)
+	})
+
 	t.Run("Should be able to find dashboard folder", func(t *testing.T) {
 		setup()
 		query := dashboards.FindPersistedDashboardsQuery{
@@ -537,6 +865,29 @@ func TestIntegrationDashboardDataAccess(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, count, int64(0))
 	})
+
+	t.Run("Should only publish events for the dashboards deleted in folder", func(t *testing.T) {
+		setup()
+		folder := insertTestDashboard(t, dashboardStore, "events folder", 1, 0, "", true)
+		_ = insertTestDashboard(t, dashboardStore, "events dash", 1, folder.ID, folder.UID, false)
+		_ = insertTestDashboard(t, dashboardStore, "events subfolder", 1, folder.ID, folder.UID, true)
+
+		var deleted []*events.DashboardDeleted
+		sqlStore.Bus().AddEventListener(func(_ context.Context, e *events.DashboardDeleted) error {
+			deleted = append(deleted, e)
+			return nil
+		})
+
+		err := dashboardStore.DeleteDashboardsInFolders(
+			context.Background(),
+			&dashboards.DeleteDashboardsInFolderRequest{
+				FolderUIDs: []string{folder.UID},
+				OrgID:      1,
+			})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, "events dash", deleted[0].Title)
+	})
 }
 
 func TestIntegrationDashboardDataAccessGivenPluginWithImportedDashboards(t *testing.T) {
@@ -545,7 +896,7 @@ func TestIntegrationDashboardDataAccessGivenPluginWithImportedDashboards(t *test
 	}
 	sqlStore := db.InitTestDB(t)
 	quotaService := quotatest.New(false, nil)
-	dashboardStore, err := ProvideDashboardStore(sqlStore, &setting.Cfg{}, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService)
+	dashboardStore, err := ProvideDashboardStore(sqlStore, &setting.Cfg{}, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService, secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 	pluginId := "test-app"
 
@@ -564,13 +915,205 @@ func TestIntegrationDashboardDataAccessGivenPluginWithImportedDashboards(t *test
 	require.Equal(t, len(queryResult), 2)
 }
 
+func TestIntegrationDashboardSensitiveFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBwithCfg(t)
+	cfg.SensitiveDashboardPaths = []string{"panels.*.options.token"}
+	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotatest.New(false, nil), secretstest.NewFakeSecretsService())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var id int64
+	save := func(t *testing.T, data string, restoredFrom int) (*dashboards.Dashboard, error) {
+		t.Helper()
+		dash, err := simplejson.NewJson([]byte(data))
+		require.NoError(t, err)
+		if id > 0 {
+			dash.Set("id", id)
+		}
+		return dashboardStore.SaveDashboard(ctx, dashboards.SaveDashboardCommand{OrgID: 1, Dashboard: dash, Overwrite: true, RestoredFrom: restoredFrom})
+	}
+	getToken := func(t *testing.T, dash *dashboards.Dashboard, panel int) any {
+		t.Helper()
+		stored, err := dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: dash.UID, OrgID: 1})
+		require.NoError(t, err)
+		return stored.Data.Get("panels").GetIndex(panel).Get("options").Get("token").Interface()
+	}
+
+	dash, err := save(t, `{"uid": "sensitive", "title": "Sensitive", "panels": [{"id": 1, "options": {"token": "first"}}]}`, 0)
+	require.NoError(t, err)
+	id = dash.ID
+	first := getToken(t, dash, 0)
+	marker, ok := dashboards.SensitiveFieldMarker(first)
+	require.True(t, ok)
+	require.Equal(t, dashboards.EncryptedFieldKey, marker)
+
+	t.Run("should keep the stored value of a redacted field of a panel that moved", func(t *testing.T) {
+		dash, err := save(t, `{"uid": "sensitive", "title": "Sensitive", "panels": [
+			{"id": 2, "options": {"token": "second"}},
+			{"id": 1, "options": {"token": {"$redacted": true}}}
+		]}`, 0)
+		require.NoError(t, err)
+		assert.Equal(t, first, getToken(t, dash, 1))
+	})
+
+	t.Run("should reject a redacted field without a stored value for the same panel", func(t *testing.T) {
+		_, err := save(t, `{"uid": "sensitive", "title": "Sensitive", "panels": [{"id": 3, "options": {"token": {"$redacted": true}}}]}`, 0)
+		assert.ErrorIs(t, err, dashboards.ErrDashboardSensitiveFieldNotFound)
+	})
+
+	t.Run("should restore the stored value of the restored version", func(t *testing.T) {
+		_, err := save(t, `{"uid": "sensitive", "title": "Sensitive", "panels": []}`, 0)
+		require.NoError(t, err)
+
+		restored := fmt.Sprintf(`{"uid": "sensitive", "title": "Sensitive", "panels": [{"id": 1, "options": {"token": {"$encrypted": %q}}}]}`,
+			first.(map[string]any)[dashboards.EncryptedFieldKey])
+		dash, err := save(t, restored, 1)
+		require.NoError(t, err)
+		assert.Equal(t, first, getToken(t, dash, 0))
+	})
+}
+
+func TestIntegrationDashboardDependents(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBwithCfg(t)
+	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotatest.New(false, nil), secretstest.NewFakeSecretsService())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	folder := insertTestDashboard(t, dashboardStore, "alerts", 1, 0, "", true)
+	dash := insertTestDashboard(t, dashboardStore, "referenced", 1, 0, "", false)
+	insertTestRule(t, sqlStore, 1, folder.UID)
+	err = sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("UPDATE alert_rule SET dashboard_uid = ?, panel_id = ? WHERE uid = ?", dash.UID, 2, "rule"); err != nil {
+			return err
+		}
+		_, err := sess.Exec("INSERT INTO annotation (org_id, dashboard_id, panel_id, type, title, text, prev_state, new_state, data, epoch, epoch_end, created, updated) VALUES (?, ?, ?, '', '', ?, '', '', '{}', ?, ?, ?, ?)",
+			1, dash.ID, 2, "deploy", 1, 1, 1, 1)
+		return err
+	})
+	require.NoError(t, err)
+
+	dependents, err := dashboardStore.GetDashboardDependents(ctx, &dashboards.GetDashboardDependentsQuery{OrgID: 1, UID: dash.UID})
+	require.NoError(t, err)
+	require.Len(t, dependents.AlertRules, 1)
+	assert.Equal(t, "rule", dependents.AlertRules[0].UID)
+	assert.Equal(t, folder.UID, dependents.AlertRules[0].FolderUID)
+	assert.Equal(t, int64(2), *dependents.AlertRules[0].PanelID)
+	assert.Equal(t, int64(1), dependents.Annotations)
+
+	err = dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: 1, ID: dash.ID, ForceDeleteRules: true})
+	require.NoError(t, err)
+
+	err = sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, table := range []string{"alert_rule", "alert_rule_version", "annotation"} {
+			count, err := sess.Table(table).Count()
+			require.NoError(t, err)
+			assert.Zero(t, count, table)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestIntegrationDashboardChanges(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBwithCfg(t)
+	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotatest.New(false, nil), secretstest.NewFakeSecretsService())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	head, err := dashboardStore.ListDashboardChanges(ctx, &dashboards.ListDashboardChangesQuery{OrgID: 1, Head: true})
+	require.NoError(t, err)
+	assert.Zero(t, head.LastID)
+
+	folder := insertTestDashboard(t, dashboardStore, "changes folder", 1, 0, "", true)
+	dash := insertTestDashboard(t, dashboardStore, "changes dash", 1, folder.ID, folder.UID, false)
+	insertTestDashboard(t, dashboardStore, "other org dash", 2, 0, "", false)
+	dash.Data.Set("title", "changes dash updated")
+	_, err = dashboardStore.SaveDashboard(ctx, dashboards.SaveDashboardCommand{
+		OrgID:     1,
+		FolderID:  folder.ID, // nolint:staticcheck
+		FolderUID: folder.UID,
+		Dashboard: dash.Data,
+		Overwrite: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: 1, ID: folder.ID}))
+
+	t.Run("should list the changes of the org in order, with the tombstones of the deleted dashboards", func(t *testing.T) {
+		type change struct {
+			uid     string
+			action  dashboards.DashboardChangeAction
+			version int
+		}
+		var changes []change
+		var afterID int64
+		for {
+			result, err := dashboardStore.ListDashboardChanges(ctx, &dashboards.ListDashboardChangesQuery{OrgID: 1, AfterID: afterID, Limit: 2})
+			require.NoError(t, err)
+			require.False(t, result.Expired)
+			for _, c := range result.Changes {
+				changes = append(changes, change{c.DashboardUID, c.Action, c.Version})
+			}
+			require.GreaterOrEqual(t, result.LastID, afterID)
+			afterID = result.LastID
+			if len(result.Changes) < 2 {
+				break
+			}
+		}
+		assert.Equal(t, []change{
+			{folder.UID, dashboards.DashboardChangeUpsert, 1},
+			{dash.UID, dashboards.DashboardChangeUpsert, 1},
+			{dash.UID, dashboards.DashboardChangeUpsert, 2},
+			{folder.UID, dashboards.DashboardChangeDelete, 1},
+			{dash.UID, dashboards.DashboardChangeDelete, 2},
+		}, changes)
+
+		head, err := dashboardStore.ListDashboardChanges(ctx, &dashboards.ListDashboardChangesQuery{OrgID: 1, Head: true})
+		require.NoError(t, err)
+		assert.Equal(t, head.LastID, afterID)
+	})
+
+	t.Run("should only list the changes written before the bound", func(t *testing.T) {
+		result, err := dashboardStore.ListDashboardChanges(ctx, &dashboards.ListDashboardChangesQuery{OrgID: 1, Before: time.Now().Add(-time.Hour), Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, result.Changes)
+		assert.Zero(t, result.LastID)
+	})
+
+	t.Run("should keep the last change and expire the cursors before the deleted changes", func(t *testing.T) {
+		deleted, err := dashboardStore.DeleteExpiredDashboardChanges(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), deleted)
+
+		result, err := dashboardStore.ListDashboardChanges(ctx, &dashboards.ListDashboardChangesQuery{OrgID: 1, AfterID: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.True(t, result.Expired)
+
+		head, err := dashboardStore.ListDashboardChanges(ctx, &dashboards.ListDashboardChangesQuery{OrgID: 1, Head: true})
+		require.NoError(t, err)
+		result, err = dashboardStore.ListDashboardChanges(ctx, &dashboards.ListDashboardChangesQuery{OrgID: 1, AfterID: head.LastID - 1, Limit: 10})
+		require.NoError(t, err)
+		assert.False(t, result.Expired)
+		require.Len(t, result.Changes, 1)
+		assert.Equal(t, dashboards.DashboardChangeDelete, result.Changes[0].Action)
+	})
+}
+
 func TestIntegrationDashboard_SortingOptions(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
 	}
 	sqlStore := db.InitTestDB(t)
 	quotaService := quotatest.New(false, nil)
-	dashboardStore, err := ProvideDashboardStore(sqlStore, &setting.Cfg{}, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService)
+	dashboardStore, err := ProvideDashboardStore(sqlStore, &setting.Cfg{}, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService, secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 
 	dashB := insertTestDashboard(t, dashboardStore, "Beta", 1, 0, "", false)
@@ -622,7 +1165,7 @@ func TestIntegrationDashboard_Filter(t *testing.T) {
 	sqlStore := db.InitTestDB(t)
 	cfg := setting.NewCfg()
 	quotaService := quotatest.New(false, nil)
-	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService)
+	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService, secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 	insertTestDashboard(t, dashboardStore, "Alfa", 1, 0, "", false)
 	dashB := insertTestDashboard(t, dashboardStore, "Beta", 1, 0, "", false)
@@ -660,13 +1203,60 @@ func TestIntegrationDashboard_Filter(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, results, 1)
 	assert.Equal(t, dashB.ID, results[0].ID)
+
+	total, err := dashboardStore.CountSearchResults(context.Background(), qNoFilter, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	total, err = dashboardStore.CountSearchResults(context.Background(), qNoFilter, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	total, err = dashboardStore.CountSearchResults(context.Background(), qFilter, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestIntegrationDashboard_FilterWithManyPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	dashboardStore, err := ProvideDashboardStore(sqlStore, setting.NewCfg(), testFeatureToggles, tagimpl.ProvideService(sqlStore), quotatest.New(false, nil), secretstest.NewFakeSecretsService())
+	require.NoError(t, err)
+	dashA := insertTestDashboard(t, dashboardStore, "Alfa", 1, 0, "", false)
+	dashB := insertTestDashboard(t, dashboardStore, "Beta", 1, 0, "", false)
+	insertTestDashboard(t, dashboardStore, "Gamma", 1, 0, "", false)
+
+	// the UIDs of the self-contained permissions are too many to be inlined in the query
+	scopes := []string{dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashA.UID), dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashB.UID)}
+	for i := 0; i < 1000; i++ {
+		scopes = append(scopes, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(fmt.Sprintf("deleted-%d", i)))
+	}
+	query := &dashboards.FindPersistedDashboardsQuery{
+		SignedInUser: &user.SignedInUser{
+			OrgID:           1,
+			UserID:          1,
+			OrgRole:         org.RoleViewer,
+			AuthenticatedBy: login.ExtendedJWTModule,
+			Permissions: map[int64]map[string][]string{
+				1: {dashboards.ActionDashboardsRead: scopes},
+			},
+		},
+	}
+	results, err := dashboardStore.FindDashboards(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	// the filter is cached and its temporary tables created again for each query
+	results, err = dashboardStore.FindDashboards(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
 }
 
 func TestGetExistingDashboardByTitleAndFolder(t *testing.T) {
 	sqlStore := db.InitTestDB(t)
 	cfg := setting.NewCfg()
 	quotaService := quotatest.New(false, nil)
-	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService)
+	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotaService, secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 	insertTestDashboard(t, dashboardStore, "Apple", 1, 0, "", false)
 	t.Run("Finds a dashboard with existing name in root directory and throws DashboardWithSameNameInFolderExists error", func(t *testing.T) {
@@ -705,7 +1295,7 @@ func TestIntegrationFindDashboardsByTitle(t *testing.T) {
 	cfg := setting.NewCfg()
 	quotaService := quotatest.New(false, nil)
 	features := featuremgmt.WithFeatures(featuremgmt.FlagNestedFolders, featuremgmt.FlagPanelTitleSearch)
-	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, features, tagimpl.ProvideService(sqlStore), quotaService)
+	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, features, tagimpl.ProvideService(sqlStore), quotaService, secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 
 	orgID := int64(1)
@@ -787,7 +1377,7 @@ func TestIntegrationFindDashboardsByTitle(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, features, tagimpl.ProvideService(sqlStore), quotaService)
+			dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, features, tagimpl.ProvideService(sqlStore), quotaService, secretstest.NewFakeSecretsService())
 			require.NoError(t, err)
 			res, err := dashboardStore.FindDashboards(context.Background(), &dashboards.FindPersistedDashboardsQuery{
 				SignedInUser: user,
@@ -822,7 +1412,7 @@ func TestIntegrationFindDashboardsByFolder(t *testing.T) {
 	cfg := setting.NewCfg()
 	quotaService := quotatest.New(false, nil)
 	features := featuremgmt.WithFeatures(featuremgmt.FlagNestedFolders, featuremgmt.FlagPanelTitleSearch)
-	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, features, tagimpl.ProvideService(sqlStore), quotaService)
+	dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, features, tagimpl.ProvideService(sqlStore), quotaService, secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 
 	orgID := int64(1)
@@ -971,7 +1561,7 @@ func TestIntegrationFindDashboardsByFolder(t *testing.T) {
 	for _, tc := range testCases {
 		for featureFlags := range tc.expectedResult {
 			t.Run(fmt.Sprintf("%s with featureFlags: %v", tc.desc, featureFlags), func(t *testing.T) {
-				dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(featureFlags), tagimpl.ProvideService(sqlStore), quotaService)
+				dashboardStore, err := ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(featureFlags), tagimpl.ProvideService(sqlStore), quotaService, secretstest.NewFakeSecretsService())
 				require.NoError(t, err)
 				res, err := dashboardStore.FindDashboards(context.Background(), &dashboards.FindPersistedDashboardsQuery{
 					SignedInUser: user,
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func AddDashboardChangeMigrations(mg *migrator.Migrator) {
+	dashboardChangeV1 := migrator.Table{
+		Name: "dashboard_change",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "is_folder", Type: migrator.DB_Bool, Nullable: false},
+			{Name: "action", Type: migrator.DB_NVarchar, Length: 10, Nullable: false},
+			{Name: "version", Type: migrator.DB_Int, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "id"}, Type: migrator.IndexType},
+			{Cols: []string{"created"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create dashboard_change table", migrator.NewAddTableMigration(dashboardChangeV1))
+	mg.AddMigration("add index dashboard_change.org_id_id", migrator.NewAddIndexMigration(dashboardChangeV1, dashboardChangeV1.Indices[0]))
+	mg.AddMigration("add index dashboard_change.created", migrator.NewAddIndexMigration(dashboardChangeV1, dashboardChangeV1.Indices[1]))
+}
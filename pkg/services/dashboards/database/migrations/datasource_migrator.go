@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// DatasourceMigration is a code migration that indexes the data sources referenced by existing dashboards
+type DatasourceMigration struct {
+	migrator.MigrationBase
+}
+
+func (m *DatasourceMigration) SQL(dialect migrator.Dialect) string {
+	return "code migration"
+}
+
+func (m *DatasourceMigration) Exec(sess *xorm.Session, mgrtr *migrator.Migrator) error {
+	const batchSize = 100
+
+	type dashboardRow struct {
+		ID    int64 `xorm:"id"`
+		OrgID int64 `xorm:"org_id"`
+		Data  []byte
+	}
+
+	type datasourceRow struct {
+		OrgID         int64  `xorm:"org_id"`
+		DashboardID   int64  `xorm:"dashboard_id"`
+		DatasourceUID string `xorm:"datasource_uid"`
+	}
+
+	var lastID int64
+	for {
+		var rows []dashboardRow
+		err := sess.SQL("SELECT id, org_id, data FROM dashboard WHERE is_folder = ? AND id > ? ORDER BY id LIMIT ?",
+			mgrtr.Dialect.BooleanStr(false), lastID, batchSize).Find(&rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			data, err := simplejson.NewJson(row.Data)
+			if err != nil {
+				mgrtr.Logger.Warn("Failed to parse dashboard data, skipping data source indexing", "dashboardID", row.ID, "error", err)
+				continue
+			}
+
+			dash := dashboards.NewDashboardFromJson(data)
+			for _, uid := range dash.GetDatasourceUIDs() {
+				if _, err := sess.Table("dashboard_datasource").Insert(&datasourceRow{
+					OrgID:         row.OrgID,
+					DashboardID:   row.ID,
+					DatasourceUID: uid,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+func AddDashboardDatasourceMigrations(mg *migrator.Migrator) {
+	dashboardDatasourceV1 := migrator.Table{
+		Name: "dashboard_datasource",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "datasource_uid", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"dashboard_id", "datasource_uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "datasource_uid"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create dashboard_datasource table", migrator.NewAddTableMigration(dashboardDatasourceV1))
+	mg.AddMigration("add unique index dashboard_datasource.dashboard_id_datasource_uid", migrator.NewAddIndexMigration(dashboardDatasourceV1, dashboardDatasourceV1.Indices[0]))
+	mg.AddMigration("add index dashboard_datasource.org_id_datasource_uid", migrator.NewAddIndexMigration(dashboardDatasourceV1, dashboardDatasourceV1.Indices[1]))
+	mg.AddMigration("Populate dashboard_datasource table", &DatasourceMigration{})
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func AddFolderReadOnlyMigrations(mg *migrator.Migrator) {
+	folderReadOnlyV1 := migrator.Table{
+		Name: "folder_read_only",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "reason", Type: migrator.DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "created_by", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "folder_uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create folder_read_only table", migrator.NewAddTableMigration(folderReadOnlyV1))
+	mg.AddMigration("add unique index folder_read_only.org_id_folder_uid", migrator.NewAddIndexMigration(folderReadOnlyV1, folderReadOnlyV1.Indices[0]))
+}
@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// LabelMigration is a code migration that indexes the labels of existing dashboards
+type LabelMigration struct {
+	migrator.MigrationBase
+}
+
+func (m *LabelMigration) SQL(dialect migrator.Dialect) string {
+	return "code migration"
+}
+
+func (m *LabelMigration) Exec(sess *xorm.Session, mgrtr *migrator.Migrator) error {
+	const batchSize = 100
+
+	type dashboardRow struct {
+		ID    int64 `xorm:"id"`
+		OrgID int64 `xorm:"org_id"`
+		Data  []byte
+	}
+
+	type labelRow struct {
+		OrgID       int64  `xorm:"org_id"`
+		DashboardID int64  `xorm:"dashboard_id"`
+		Name        string `xorm:"name"`
+		Value       string `xorm:"value"`
+	}
+
+	var lastID int64
+	for {
+		var rows []dashboardRow
+		err := sess.SQL("SELECT id, org_id, data FROM dashboard WHERE is_folder = ? AND id > ? ORDER BY id LIMIT ?",
+			mgrtr.Dialect.BooleanStr(false), lastID, batchSize).Find(&rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			data, err := simplejson.NewJson(row.Data)
+			if err != nil {
+				mgrtr.Logger.Warn("Failed to parse dashboard data, skipping label indexing", "dashboardID", row.ID, "error", err)
+				continue
+			}
+
+			labels := dashboards.NewDashboardFromJson(data).GetLabels()
+			if err := model.ValidateLabels(labels); err != nil {
+				mgrtr.Logger.Warn("Invalid dashboard labels, skipping label indexing", "dashboardID", row.ID, "error", err)
+				continue
+			}
+			for name, value := range labels {
+				if _, err := sess.Table("dashboard_label").Insert(&labelRow{
+					OrgID:       row.OrgID,
+					DashboardID: row.ID,
+					Name:        name,
+					Value:       value,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+func AddDashboardLabelMigrations(mg *migrator.Migrator) {
+	dashboardLabelV1 := migrator.Table{
+		Name: "dashboard_label",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: model.MaxLabelNameLength, Nullable: false},
+			{Name: "value", Type: migrator.DB_NVarchar, Length: model.MaxLabelValueLength, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"dashboard_id", "name"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "name", "value"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create dashboard_label table", migrator.NewAddTableMigration(dashboardLabelV1))
+	mg.AddMigration("add unique index dashboard_label.dashboard_id_name", migrator.NewAddIndexMigration(dashboardLabelV1, dashboardLabelV1.Indices[0]))
+	mg.AddMigration("add index dashboard_label.org_id_name_value", migrator.NewAddIndexMigration(dashboardLabelV1, dashboardLabelV1.Indices[1]))
+	mg.AddMigration("Populate dashboard_label table", &LabelMigration{})
+}
@@ -0,0 +1,92 @@
+package migrations
+
+import (
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// PanelTypeMigration is a code migration that indexes the panel types of existing dashboards
+type PanelTypeMigration struct {
+	migrator.MigrationBase
+}
+
+func (m *PanelTypeMigration) SQL(dialect migrator.Dialect) string {
+	return "code migration"
+}
+
+func (m *PanelTypeMigration) Exec(sess *xorm.Session, mgrtr *migrator.Migrator) error {
+	const batchSize = 100
+
+	type dashboardRow struct {
+		ID    int64 `xorm:"id"`
+		OrgID int64 `xorm:"org_id"`
+		Data  []byte
+	}
+
+	type panelTypeRow struct {
+		OrgID       int64  `xorm:"org_id"`
+		DashboardID int64  `xorm:"dashboard_id"`
+		PanelType   string `xorm:"panel_type"`
+		PanelCount  int    `xorm:"panel_count"`
+	}
+
+	var lastID int64
+	for {
+		var rows []dashboardRow
+		err := sess.SQL("SELECT id, org_id, data FROM dashboard WHERE is_folder = ? AND id > ? ORDER BY id LIMIT ?",
+			mgrtr.Dialect.BooleanStr(false), lastID, batchSize).Find(&rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			data, err := simplejson.NewJson(row.Data)
+			if err != nil {
+				mgrtr.Logger.Warn("Failed to parse dashboard data, skipping panel type indexing", "dashboardID", row.ID, "error", err)
+				continue
+			}
+
+			dash := dashboards.NewDashboardFromJson(data)
+			for panelType, count := range dash.GetPanelTypeCounts() {
+				if _, err := sess.Table("dashboard_panel_type").Insert(&panelTypeRow{
+					OrgID:       row.OrgID,
+					DashboardID: row.ID,
+					PanelType:   panelType,
+					PanelCount:  count,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+func AddDashboardPanelTypeMigrations(mg *migrator.Migrator) {
+	dashboardPanelTypeV1 := migrator.Table{
+		Name: "dashboard_panel_type",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "panel_type", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "panel_count", Type: migrator.DB_Int, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"dashboard_id", "panel_type"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "panel_type"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create dashboard_panel_type table", migrator.NewAddTableMigration(dashboardPanelTypeV1))
+	mg.AddMigration("add unique index dashboard_panel_type.dashboard_id_panel_type", migrator.NewAddIndexMigration(dashboardPanelTypeV1, dashboardPanelTypeV1.Indices[0]))
+	mg.AddMigration("add index dashboard_panel_type.org_id_panel_type", migrator.NewAddIndexMigration(dashboardPanelTypeV1, dashboardPanelTypeV1.Indices[1]))
+	mg.AddMigration("Populate dashboard_panel_type table", &PanelTypeMigration{})
+}
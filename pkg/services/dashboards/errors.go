@@ -2,6 +2,7 @@ package dashboards
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -23,6 +24,11 @@ var (
 		StatusCode: 404,
 		Status:     "not-found",
 	}
+	ErrDashboardPanelHidden = DashboardErr{
+		Reason:     "Dashboard panel is hidden by its visibility",
+		StatusCode: 403,
+		Status:     "panel-hidden",
+	}
 	ErrDashboardFolderNotFound = DashboardErr{
 		Reason:     "Folder not found",
 		StatusCode: 404,
@@ -91,6 +97,50 @@ var (
 		Reason:     "Dashboard refresh interval is too low",
 		StatusCode: 400,
 	}
+	ErrDashboardInvalidRenderProfiles = DashboardErr{
+		Reason:     "Render profiles need a name, and the fixed heights of their panels need to be positive",
+		StatusCode: 400,
+		Status:     "invalid-render-profiles",
+	}
+	ErrDashboardInvalidLabels = DashboardErr{
+		Reason:     "Label names must be up to 50 letters, digits, '.', '_', '-' or '/' and label values up to 190 characters",
+		StatusCode: 400,
+		Status:     "invalid-labels",
+	}
+	ErrDashboardFolderReadOnly = DashboardErr{
+		Reason:     "Dashboard folder is read-only",
+		StatusCode: 403,
+		Status:     "folder-read-only",
+	}
+	ErrFolderReadOnlyReasonTooLong = DashboardErr{
+		Reason:     "The reason of the read-only flag can be at most 255 characters",
+		StatusCode: 400,
+	}
+	ErrDashboardTooLarge = DashboardErr{
+		Reason:     "Dashboard JSON exceeds the maximum size",
+		StatusCode: 413,
+		Status:     "dashboard-too-large",
+	}
+	ErrDashboardTooManyPanels = DashboardErr{
+		Reason:     "Dashboard exceeds the maximum number of panels",
+		StatusCode: 413,
+		Status:     "too-many-panels",
+	}
+	ErrDashboardPayloadTooLarge = DashboardErr{
+		Reason:     "Request body exceeds the maximum size",
+		StatusCode: 413,
+		Status:     "payload-too-large",
+	}
+	ErrDashboardSensitiveFieldNotFound = DashboardErr{
+		Reason:     "Redacted or encrypted dashboard field has no stored value for the same panel",
+		StatusCode: 400,
+		Status:     "sensitive-field-not-found",
+	}
+	ErrDashboardHasDependents = DashboardErr{
+		Reason:     "Dashboard is referenced by alert rules or annotations",
+		StatusCode: 409,
+		Status:     "has-dependents",
+	}
 	ErrDashboardCannotDeleteProvisionedDashboard = DashboardErr{
 		Reason:     "provisioned dashboard cannot be deleted",
 		StatusCode: 400,
@@ -155,6 +205,85 @@ func (e DashboardErr) Body() util.DynMap {
 	return util.DynMap{"status": e.Status, "message": e.Error()}
 }
 
+// DashboardLimitErr is returned when a dashboard exceeds one of the size limits,
+// its body includes the actual value and the limit.
+type DashboardLimitErr struct {
+	DashboardErr
+	// Unit of the values, bytes or panels
+	Unit   string
+	Actual int64
+	Limit  int64
+}
+
+func NewDashboardLimitErr(base DashboardErr, unit string, actual, limit int64) DashboardLimitErr {
+	return DashboardLimitErr{DashboardErr: base, Unit: unit, Actual: actual, Limit: limit}
+}
+
+// Error returns the error message.
+func (e DashboardLimitErr) Error() string {
+	if e.Actual > 0 {
+		return fmt.Sprintf("%s: %d %s, the limit is %d", e.DashboardErr.Error(), e.Actual, e.Unit, e.Limit)
+	}
+	return fmt.Sprintf("%s of %d %s", e.DashboardErr.Error(), e.Limit, e.Unit)
+}
+
+// Unwrap returns the DashboardErr, so that errors.Is matches ErrDashboardTooLarge for example.
+func (e DashboardLimitErr) Unwrap() error {
+	return e.DashboardErr
+}
+
+// Body returns the error's response body.
+func (e DashboardLimitErr) Body() util.DynMap {
+	body := util.DynMap{"status": e.Status, "message": e.Error(), "unit": e.Unit, "limit": e.Limit}
+	// the size of request bodies without content length isn't known
+	if e.Actual > 0 {
+		body["actual"] = e.Actual
+	}
+	return body
+}
+
+// DashboardDependentsErr is returned when a dashboard that alert rules or annotations
+// reference is deleted without forceDeleteRules, its body lists the dependents.
+type DashboardDependentsErr struct {
+	DashboardErr
+	Dependents *DashboardDependents
+}
+
+func NewDashboardDependentsErr(dependents *DashboardDependents) DashboardDependentsErr {
+	return DashboardDependentsErr{DashboardErr: ErrDashboardHasDependents, Dependents: dependents}
+}
+
+// Unwrap returns the DashboardErr, so that errors.Is matches ErrDashboardHasDependents.
+func (e DashboardDependentsErr) Unwrap() error {
+	return e.DashboardErr
+}
+
+// Body returns the error's response body.
+func (e DashboardDependentsErr) Body() util.DynMap {
+	return util.DynMap{"status": e.Status, "message": e.Error(), "dependents": e.Dependents}
+}
+
+// DashboardFolderReadOnlyErr is returned when a dashboard of a read-only folder is saved, its body has the
+// folder that is read-only and why.
+type DashboardFolderReadOnlyErr struct {
+	DashboardErr
+	ReadOnly *FolderReadOnly
+}
+
+func NewDashboardFolderReadOnlyErr(readOnly *FolderReadOnly) DashboardFolderReadOnlyErr {
+	return DashboardFolderReadOnlyErr{DashboardErr: ErrDashboardFolderReadOnly, ReadOnly: readOnly}
+}
+
+// Unwrap returns the DashboardErr, so that errors.Is matches ErrDashboardFolderReadOnly.
+func (e DashboardFolderReadOnlyErr) Unwrap() error {
+	return e.DashboardErr
+}
+
+// Body returns the error's response body.
+func (e DashboardFolderReadOnlyErr) Body() util.DynMap {
+	return util.DynMap{"status": e.Status, "message": e.Error(), "folderUid": e.ReadOnly.FolderUID, "readOnlyReason": e.ReadOnly.Reason}
+}
+
 type UpdatePluginDashboardError struct {
 	PluginId string
 }
@@ -1,7 +1,11 @@
 package dashboards
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -91,6 +95,300 @@ func (d *Dashboard) GetTags() []string {
 	return d.Data.Get("tags").MustStringArray()
 }
 
+// GetLabels returns the string labels in data json, the labels with other values are ignored
+func (d *Dashboard) GetLabels() map[string]string {
+	labels := make(map[string]string)
+	for name, value := range d.Data.Get("labels").MustMap() {
+		if s, ok := value.(string); ok {
+			labels[name] = s
+		}
+	}
+	return labels
+}
+
+// GetPanelTypeCounts returns the number of panels per panel plugin type, including
+// panels nested in collapsed rows and in the legacy rows layout.
+func (d *Dashboard) GetPanelTypeCounts() map[string]int {
+	counts := make(map[string]int)
+	var count func(panels []any)
+	count = func(panels []any) {
+		for _, p := range panels {
+			panel := simplejson.NewFromAny(p)
+			panelType := panel.Get("type").MustString()
+			if panelType == "row" {
+				count(panel.Get("panels").MustArray())
+				continue
+			}
+			if panelType != "" {
+				counts[panelType]++
+			}
+		}
+	}
+
+	count(d.Data.Get("panels").MustArray())
+	for _, row := range d.Data.Get("rows").MustArray() {
+		count(simplejson.NewFromAny(row).Get("panels").MustArray())
+	}
+	return counts
+}
+
+// GetDatasourceUIDs returns the sorted data sources referenced by the panels, queries, variables and
+// annotations of the dashboard. The legacy references by name are returned as is, the references to
+// variables and to the mixed and dashboard data sources are ignored.
+func (d *Dashboard) GetDatasourceUIDs() []string {
+	seen := make(map[string]struct{})
+	add := func(ref *simplejson.Json) {
+		uid, err := ref.String()
+		if err != nil {
+			uid = ref.Get("uid").MustString()
+		}
+		if uid == "" || strings.HasPrefix(uid, "$") || uid == "-- Mixed --" || uid == "-- Dashboard --" {
+			return
+		}
+		seen[uid] = struct{}{}
+	}
+
+	var walk func(panels []any)
+	walk = func(panels []any) {
+		for _, p := range panels {
+			panel := simplejson.NewFromAny(p)
+			add(panel.Get("datasource"))
+			for _, target := range panel.Get("targets").MustArray() {
+				add(simplejson.NewFromAny(target).Get("datasource"))
+			}
+			walk(panel.Get("panels").MustArray())
+		}
+	}
+
+	walk(d.Data.Get("panels").MustArray())
+	for _, row := range d.Data.Get("rows").MustArray() {
+		walk(simplejson.NewFromAny(row).Get("panels").MustArray())
+	}
+	for _, variable := range d.Data.GetPath("templating", "list").MustArray() {
+		add(simplejson.NewFromAny(variable).Get("datasource"))
+	}
+	for _, annotation := range d.Data.GetPath("annotations", "list").MustArray() {
+		add(simplejson.NewFromAny(annotation).Get("datasource"))
+	}
+
+	uids := make([]string, 0, len(seen))
+	for uid := range seen {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+// GetPanel returns the panel with the given id, including panels nested in rows, or nil if there is none
+func (d *Dashboard) GetPanel(id int64) *simplejson.Json {
+	var find func(panels []any) *simplejson.Json
+	find = func(panels []any) *simplejson.Json {
+		for _, p := range panels {
+			panel := simplejson.NewFromAny(p)
+			if panel.Get("id").MustInt64() == id {
+				return panel
+			}
+			if nested := find(panel.Get("panels").MustArray()); nested != nil {
+				return nested
+			}
+		}
+		return nil
+	}
+
+	if panel := find(d.Data.Get("panels").MustArray()); panel != nil {
+		return panel
+	}
+	for _, row := range d.Data.Get("rows").MustArray() {
+		if panel := find(simplejson.NewFromAny(row).Get("panels").MustArray()); panel != nil {
+			return panel
+		}
+	}
+	return nil
+}
+
+// PanelVisibility restricts the viewers of a panel, it's the visibility property of the panel JSON. The panel
+// is visible to the users with one of the roles, or a role it includes, and to the members of one of the teams.
+type PanelVisibility struct {
+	Roles []org.RoleType `json:"roles,omitempty"`
+	// Teams are the UIDs of the teams
+	Teams []string `json:"teams,omitempty"`
+}
+
+// GetPanelVisibility returns the visibility of the panel, or nil when the panel is visible to all the viewers
+// of the dashboard
+func GetPanelVisibility(panel *simplejson.Json) *PanelVisibility {
+	visibility := panel.Get("visibility")
+	v := &PanelVisibility{Teams: visibility.Get("teams").MustStringArray()}
+	for _, role := range visibility.Get("roles").MustStringArray() {
+		v.Roles = append(v.Roles, org.RoleType(role))
+	}
+	if len(v.Roles) == 0 && len(v.Teams) == 0 {
+		return nil
+	}
+	return v
+}
+
+// Allows returns whether the panel is visible to a user with the role and in the teams
+func (v *PanelVisibility) Allows(role org.RoleType, teamUIDs []string) bool {
+	for _, r := range v.Roles {
+		if r.IsValid() && role.Includes(r) {
+			return true
+		}
+	}
+	for _, uid := range v.Teams {
+		for _, teamUID := range teamUIDs {
+			if uid == teamUID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetPanelVisibilities returns the visibility of the panels of the dashboard by panel ID. The panels
+// without a visibility have the visibility of their row, if any.
+func (d *Dashboard) GetPanelVisibilities() map[int64]*PanelVisibility {
+	visibilities := map[int64]*PanelVisibility{}
+	var collect func(panels []any, parent *PanelVisibility)
+	collect = func(panels []any, parent *PanelVisibility) {
+		row := parent
+		for _, p := range panels {
+			panel := simplejson.NewFromAny(p)
+			v := GetPanelVisibility(panel)
+			if panel.Get("type").MustString() == "row" {
+				// the panels after an expanded row belong to the row
+				row = v
+				if row == nil {
+					row = parent
+				}
+			}
+			if v == nil {
+				v = row
+			}
+			if v != nil {
+				visibilities[panel.Get("id").MustInt64()] = v
+			}
+			collect(panel.Get("panels").MustArray(), v)
+		}
+	}
+
+	collect(d.Data.Get("panels").MustArray(), nil)
+	for _, row := range d.Data.Get("rows").MustArray() {
+		collect(simplejson.NewFromAny(row).Get("panels").MustArray(), nil)
+	}
+	return visibilities
+}
+
+// RemoveHiddenPanels removes the panels that visible rejects the visibility of from the dashboard JSON,
+// including the panels of rows, and returns the number of removed panels
+func (d *Dashboard) RemoveHiddenPanels(visible func(v *PanelVisibility) bool) int {
+	visibilities := d.GetPanelVisibilities()
+	removed := 0
+	var filter func(panels []any) []any
+	filter = func(panels []any) []any {
+		kept := make([]any, 0, len(panels))
+		for _, p := range panels {
+			panel := simplejson.NewFromAny(p)
+			if v := visibilities[panel.Get("id").MustInt64()]; v != nil && !visible(v) {
+				removed++
+				continue
+			}
+			if nested, err := panel.Get("panels").Array(); err == nil {
+				panel.Set("panels", filter(nested))
+			}
+			kept = append(kept, p)
+		}
+		return kept
+	}
+
+	if panels, err := d.Data.Get("panels").Array(); err == nil {
+		d.Data.Set("panels", filter(panels))
+	}
+	for _, row := range d.Data.Get("rows").MustArray() {
+		rowJSON := simplejson.NewFromAny(row)
+		if panels, err := rowJSON.Get("panels").Array(); err == nil {
+			rowJSON.Set("panels", filter(panels))
+		}
+	}
+	return removed
+}
+
+// RenderProfile adapts the layout of a dashboard to a rendering, such as its printed copy. The profiles are the
+// renderProfiles property of the dashboard JSON, by name.
+type RenderProfile struct {
+	// HiddenRows are the IDs of the rows hidden with their panels
+	HiddenRows []int64 `json:"hiddenRows,omitempty"`
+	// PanelHeights are the fixed heights of the panels in grid units, by panel ID
+	PanelHeights map[string]int `json:"panelHeights,omitempty"`
+	// PageBreaks are the IDs of the panels starting a new page
+	PageBreaks []int64 `json:"pageBreaks,omitempty"`
+}
+
+// GetRenderProfiles returns the render profiles of the dashboard by name
+func (d *Dashboard) GetRenderProfiles() (map[string]*RenderProfile, error) {
+	profiles := map[string]*RenderProfile{}
+	raw, ok := d.Data.CheckGet("renderProfiles")
+	if !ok {
+		return profiles, nil
+	}
+	data, err := raw.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, err
+	}
+	for name, profile := range profiles {
+		if strings.TrimSpace(name) == "" || profile == nil {
+			return nil, fmt.Errorf("render profiles need a name")
+		}
+		for id, height := range profile.PanelHeights {
+			if _, err := strconv.ParseInt(id, 10, 64); err != nil || height <= 0 {
+				return nil, fmt.Errorf("invalid height of panel %s in render profile %s", id, name)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// ApplyRenderProfile removes the hidden rows of the profile from the dashboard JSON, with their panels, and sets
+// the fixed heights of the panels
+func (d *Dashboard) ApplyRenderProfile(profile *RenderProfile) {
+	hidden := make(map[int64]bool, len(profile.HiddenRows))
+	for _, id := range profile.HiddenRows {
+		hidden[id] = true
+	}
+	setHeight := func(panel *simplejson.Json) {
+		if height, ok := profile.PanelHeights[strconv.FormatInt(panel.Get("id").MustInt64(), 10)]; ok {
+			panel.SetPath([]string{"gridPos", "h"}, height)
+		}
+	}
+
+	panels := d.Data.Get("panels").MustArray()
+	kept := make([]any, 0, len(panels))
+	// the panels of an expanded row follow it until the next row
+	inHiddenRow := false
+	for _, p := range panels {
+		panel := simplejson.NewFromAny(p)
+		if panel.Get("type").MustString() == "row" {
+			inHiddenRow = hidden[panel.Get("id").MustInt64()]
+			if inHiddenRow {
+				continue
+			}
+			for _, nested := range panel.Get("panels").MustArray() {
+				setHeight(simplejson.NewFromAny(nested))
+			}
+		} else if inHiddenRow {
+			continue
+		}
+		setHeight(panel)
+		kept = append(kept, p)
+	}
+	if _, ok := d.Data.CheckGet("panels"); ok {
+		d.Data.Set("panels", kept)
+	}
+}
+
 func NewDashboardFromJson(data *simplejson.Json) *Dashboard {
 	dash := &Dashboard{}
 	dash.Data = data
@@ -223,6 +521,31 @@ type DeleteDashboardCommand struct {
 	UID                    string
 	OrgID                  int64
 	ForceDeleteFolderRules bool
+	// ForceDeleteRules deletes the alert rules that reference the dashboard together with it
+	ForceDeleteRules bool
+}
+
+type GetDashboardDependentsQuery struct {
+	OrgID int64
+	UID   string
+}
+
+// DashboardDependents are the resources that reference a dashboard and are left dangling or deleted when it's deleted
+type DashboardDependents struct {
+	AlertRules  []DashboardDependentAlertRule `json:"alertRules"`
+	Annotations int64                         `json:"annotations"`
+}
+
+// DashboardDependentAlertRule is an alert rule linked to a panel of a dashboard
+type DashboardDependentAlertRule struct {
+	UID       string `json:"uid" xorm:"uid"`
+	Title     string `json:"title" xorm:"title"`
+	FolderUID string `json:"folderUid" xorm:"namespace_uid"`
+	PanelID   *int64 `json:"panelId,omitempty" xorm:"panel_id"`
+}
+
+func (d *DashboardDependents) IsEmpty() bool {
+	return d == nil || (len(d.AlertRules) == 0 && d.Annotations == 0)
 }
 
 type DeleteOrphanedProvisionedDashboardsCommand struct {
@@ -265,12 +588,70 @@ type GetDashboardTagsQuery struct {
 	OrgID int64
 }
 
+// PanelTypeUsage is the number of panels and dashboards using a panel plugin.
+type PanelTypeUsage struct {
+	PanelType  string `json:"panelType" xorm:"panel_type"`
+	Panels     int64  `json:"panels"`
+	Dashboards int64  `json:"dashboards"`
+}
+
+type GetPanelTypeUsageQuery struct {
+	OrgID int64
+}
+
+type GetDashboardLabelsQuery struct {
+	OrgID       int64
+	DashboardID int64
+}
+
+// SaveDashboardLabelsCommand replaces the labels of a folder, the labels of the dashboards
+// are saved with the dashboard JSON.
+type SaveDashboardLabelsCommand struct {
+	OrgID       int64
+	DashboardID int64
+	Labels      map[string]string
+}
+
+// FolderReadOnly is the read-only flag of a folder. The dashboards of a read-only folder and of its subfolders
+// can't be saved, created or moved in or out of it, except with the folders.readonly:write permission.
+type FolderReadOnly struct {
+	ID        int64     `json:"-" xorm:"pk autoincr 'id'"`
+	OrgID     int64     `json:"-" xorm:"org_id"`
+	FolderUID string    `json:"folderUid" xorm:"folder_uid"`
+	Reason    string    `json:"reason" xorm:"reason"`
+	CreatedBy int64     `json:"createdBy" xorm:"created_by"`
+	Created   time.Time `json:"created" xorm:"created"`
+}
+
+func (f FolderReadOnly) TableName() string { return "folder_read_only" }
+
+type GetFolderReadOnlyQuery struct {
+	OrgID     int64
+	FolderUID string
+}
+
+// SetFolderReadOnlyCommand sets the read-only flag of a folder, or clears it if ReadOnly is false
+type SetFolderReadOnlyCommand struct {
+	OrgID     int64
+	FolderUID string
+	ReadOnly  bool
+	Reason    string
+	UserID    int64
+}
+
 type GetDashboardsQuery struct {
 	DashboardIDs  []int64
 	DashboardUIDs []string
 	OrgID         int64
 }
 
+// ListDashboardsAfterQuery lists the dashboards of an org in the order of their IDs, starting after AfterID
+type ListDashboardsAfterQuery struct {
+	OrgID   int64
+	AfterID int64
+	Limit   int
+}
+
 type GetDashboardsByPluginIDQuery struct {
 	OrgID    int64
 	PluginID string
@@ -399,6 +780,49 @@ type DashboardACLInfoDTO struct {
 	Inherited      bool                           `json:"inherited"`
 }
 
+// DashboardACLAuditItem is a permission of a user, team or role as recorded in the ACL audit history.
+type DashboardACLAuditItem struct {
+	UserID     int64                          `json:"userId,omitempty"`
+	TeamID     int64                          `json:"teamId,omitempty"`
+	Role       *org.RoleType                  `json:"role,omitempty"`
+	Permission dashboardaccess.PermissionType `json:"permission"`
+}
+
+// DashboardACLAuditEntry records who changed the permissions of a dashboard or folder and how.
+type DashboardACLAuditEntry struct {
+	ID           int64                   `json:"id" xorm:"pk autoincr 'id'"`
+	OrgID        int64                   `json:"-" xorm:"org_id"`
+	DashboardUID string                  `json:"dashboardUid" xorm:"dashboard_uid"`
+	IsFolder     bool                    `json:"isFolder" xorm:"is_folder"`
+	ActorID      int64                   `json:"actorId" xorm:"actor_id"`
+	ActorLogin   string                  `json:"actorLogin" xorm:"actor_login"`
+	Previous     []DashboardACLAuditItem `json:"previous" xorm:"previous_items"`
+	New          []DashboardACLAuditItem `json:"new" xorm:"new_items"`
+	Created      time.Time               `json:"created"`
+}
+
+func (e DashboardACLAuditEntry) TableName() string { return "dashboard_acl_audit" }
+
+type GetDashboardACLAuditQuery struct {
+	OrgID        int64
+	DashboardUID string
+	Limit        int
+	Page         int
+}
+
+// NormalizeStoredDashboardsCommand normalizes the JSON of the next Limit dashboards with an ID above AfterID
+type NormalizeStoredDashboardsCommand struct {
+	AfterID int64
+	Limit   int
+}
+
+type NormalizeStoredDashboardsResult struct {
+	// LastID is the ID of the last dashboard that was checked, to continue with in the next batch
+	LastID  int64
+	Updated int
+	Done    bool
+}
+
 type FindPersistedDashboardsQuery struct {
 	Title         string
 	OrgId         int64
@@ -410,6 +834,7 @@ type FindPersistedDashboardsQuery struct {
 	FolderIds  []int64
 	FolderUIDs []string
 	Tags       []string
+	Labels     []model.LabelSelector
 	Limit      int64
 	Page       int64
 	Permission dashboardaccess.PermissionType
@@ -417,3 +842,47 @@ type FindPersistedDashboardsQuery struct {
 
 	Filters []any
 }
+
+// DashboardChangeAction is what a change of the change feed did to its dashboard or folder
+type DashboardChangeAction string
+
+const (
+	// DashboardChangeUpsert is the creation or update of a dashboard or folder, the change has its new version
+	DashboardChangeUpsert DashboardChangeAction = "upsert"
+	// DashboardChangeDelete is the tombstone of a deleted dashboard or folder
+	DashboardChangeDelete DashboardChangeAction = "delete"
+)
+
+// DashboardChange is an entry of the change feed of the dashboards. The changes are numbered in the order
+// they are written, so that the consumers of the feed can resume reading it after the last change they read.
+type DashboardChange struct {
+	ID           int64                 `json:"id" xorm:"pk autoincr 'id'"`
+	OrgID        int64                 `json:"-" xorm:"org_id"`
+	DashboardUID string                `json:"uid" xorm:"dashboard_uid"`
+	IsFolder     bool                  `json:"isFolder" xorm:"is_folder"`
+	Action       DashboardChangeAction `json:"action" xorm:"action"`
+	Version      int                   `json:"version" xorm:"'version'"`
+	Created      time.Time             `json:"created" xorm:"created"`
+}
+
+func (c DashboardChange) TableName() string { return "dashboard_change" }
+
+// ListDashboardChangesQuery lists the changes of the dashboards of an org after AfterID, in the order they were
+// written. If Before isn't zero, only the changes written before it are listed. If Head is set, no changes are
+// listed, only the ID of the last change is returned to start reading the feed from.
+type ListDashboardChangesQuery struct {
+	OrgID   int64
+	AfterID int64
+	Before  time.Time
+	Limit   int
+	Head    bool
+}
+
+// ListDashboardChangesResult is a batch of the change feed. LastID is the ID to read the next batch after.
+// Expired is set when the changes after AfterID were deleted by the retention of the feed, the consumer
+// must then read all the dashboards again.
+type ListDashboardChangesResult struct {
+	Changes []*DashboardChange
+	LastID  int64
+	Expired bool
+}
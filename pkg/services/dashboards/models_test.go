@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/slugify"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -47,6 +48,145 @@ func TestNewDashboardFromJson(t *testing.T) {
 	require.Empty(t, dash.GetTags())
 }
 
+func TestDashboard_GetPanelTypeCounts(t *testing.T) {
+	json, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"type": "timeseries"},
+			{"type": "stat"},
+			{"type": "row", "panels": [{"type": "timeseries"}, {"type": "table"}]},
+			{"libraryPanel": {"uid": "lib"}}
+		],
+		"rows": [{"panels": [{"type": "graph"}]}]
+	}`))
+	require.NoError(t, err)
+
+	dash := NewDashboardFromJson(json)
+	assert.Equal(t, map[string]int{"timeseries": 2, "stat": 1, "table": 1, "graph": 1}, dash.GetPanelTypeCounts())
+}
+
+func TestDashboard_GetDatasourceUIDs(t *testing.T) {
+	json, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"datasource": {"type": "prometheus", "uid": "prom"}, "targets": [{"datasource": {"uid": "prom"}}]},
+			{"datasource": {"uid": "-- Mixed --"}, "targets": [{"datasource": {"uid": "loki"}}, {"datasource": {"uid": "${ds}"}}]},
+			{"type": "row", "panels": [{"datasource": "Legacy"}]}
+		],
+		"rows": [{"panels": [{"datasource": {"uid": "graphite"}}]}],
+		"templating": {"list": [{"type": "query", "datasource": {"uid": "tempo"}}, {"type": "datasource", "query": "prometheus"}]},
+		"annotations": {"list": [{"datasource": {"uid": "grafana"}}]}
+	}`))
+	require.NoError(t, err)
+
+	dash := NewDashboardFromJson(json)
+	assert.Equal(t, []string{"Legacy", "grafana", "graphite", "loki", "prom", "tempo"}, dash.GetDatasourceUIDs())
+}
+
+func TestDashboard_GetPanel(t *testing.T) {
+	json, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 1, "title": "top"},
+			{"id": 2, "type": "row", "panels": [{"id": 3, "title": "collapsed"}]}
+		],
+		"rows": [{"panels": [{"id": 4, "title": "legacy"}]}]
+	}`))
+	require.NoError(t, err)
+
+	dash := NewDashboardFromJson(json)
+	assert.Equal(t, "top", dash.GetPanel(1).Get("title").MustString())
+	assert.Equal(t, "collapsed", dash.GetPanel(3).Get("title").MustString())
+	assert.Equal(t, "legacy", dash.GetPanel(4).Get("title").MustString())
+	assert.Nil(t, dash.GetPanel(5))
+}
+
+func TestPanelVisibility_Allows(t *testing.T) {
+	v := &PanelVisibility{Roles: []org.RoleType{org.RoleEditor}, Teams: []string{"ops"}}
+	assert.True(t, v.Allows(org.RoleEditor, nil))
+	assert.True(t, v.Allows(org.RoleAdmin, nil))
+	assert.True(t, v.Allows(org.RoleViewer, []string{"dev", "ops"}))
+	assert.False(t, v.Allows(org.RoleViewer, []string{"dev"}))
+
+	invalid := &PanelVisibility{Roles: []org.RoleType{"Owner"}}
+	assert.False(t, invalid.Allows(org.RoleViewer, nil))
+}
+
+func TestDashboard_RemoveHiddenPanels(t *testing.T) {
+	json, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 1, "title": "public"},
+			{"id": 2, "visibility": {"roles": ["Admin"]}},
+			{"id": 3, "type": "row", "collapsed": true, "visibility": {"teams": ["ops"]}, "panels": [{"id": 4}]},
+			{"id": 5, "type": "row", "visibility": {"roles": ["Editor"]}},
+			{"id": 6},
+			{"id": 7, "visibility": {"roles": ["Viewer"]}},
+			{"id": 8, "type": "row"},
+			{"id": 9}
+		],
+		"rows": [{"panels": [{"id": 10, "visibility": {"roles": ["Admin"]}}, {"id": 11}]}]
+	}`))
+	require.NoError(t, err)
+	dash := NewDashboardFromJson(json)
+
+	visibilities := dash.GetPanelVisibilities()
+	assert.Equal(t, []org.RoleType{org.RoleAdmin}, visibilities[2].Roles)
+	assert.Equal(t, []string{"ops"}, visibilities[4].Teams)
+	assert.Equal(t, []org.RoleType{org.RoleEditor}, visibilities[6].Roles)
+	assert.Equal(t, []org.RoleType{org.RoleViewer}, visibilities[7].Roles)
+	assert.Nil(t, visibilities[9])
+
+	viewer := func(v *PanelVisibility) bool { return v.Allows(org.RoleViewer, nil) }
+	assert.Equal(t, 5, dash.RemoveHiddenPanels(viewer))
+
+	ids := func(panels []any) []int64 {
+		result := []int64{}
+		for _, p := range panels {
+			result = append(result, simplejson.NewFromAny(p).Get("id").MustInt64())
+		}
+		return result
+	}
+	assert.Equal(t, []int64{1, 7, 8, 9}, ids(dash.Data.Get("panels").MustArray()))
+	assert.Equal(t, []int64{11}, ids(dash.Data.Get("rows").GetIndex(0).Get("panels").MustArray()))
+}
+
+func TestDashboard_ApplyRenderProfile(t *testing.T) {
+	json, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 1, "gridPos": {"h": 8}},
+			{"id": 2, "type": "row"},
+			{"id": 3},
+			{"id": 4, "type": "row", "collapsed": true, "panels": [{"id": 5, "gridPos": {"h": 8}}]},
+			{"id": 6}
+		],
+		"renderProfiles": {
+			"print": {"hiddenRows": [2], "panelHeights": {"1": 12, "5": 20}, "pageBreaks": [4]}
+		}
+	}`))
+	require.NoError(t, err)
+	dash := NewDashboardFromJson(json)
+
+	profiles, err := dash.GetRenderProfiles()
+	require.NoError(t, err)
+	require.Contains(t, profiles, "print")
+	assert.Equal(t, []int64{4}, profiles["print"].PageBreaks)
+
+	dash.ApplyRenderProfile(profiles["print"])
+	panels := dash.Data.Get("panels")
+	require.Len(t, panels.MustArray(), 3)
+	assert.Equal(t, int64(1), panels.GetIndex(0).Get("id").MustInt64())
+	assert.Equal(t, 12, panels.GetIndex(0).GetPath("gridPos", "h").MustInt())
+	assert.Equal(t, int64(4), panels.GetIndex(1).Get("id").MustInt64())
+	assert.Equal(t, 20, panels.GetIndex(1).Get("panels").GetIndex(0).GetPath("gridPos", "h").MustInt())
+	assert.Equal(t, int64(6), panels.GetIndex(2).Get("id").MustInt64())
+
+	t.Run("should reject the invalid heights", func(t *testing.T) {
+		dash.Data.Set("renderProfiles", map[string]any{"print": map[string]any{"panelHeights": map[string]any{"title": 4}}})
+		_, err := dash.GetRenderProfiles()
+		require.Error(t, err)
+		dash.Data.Set("renderProfiles", map[string]any{"print": map[string]any{"panelHeights": map[string]any{"1": 0}}})
+		_, err = dash.GetRenderProfiles()
+		require.Error(t, err)
+	})
+}
+
 func TestSaveDashboardCommand_GetDashboardModel(t *testing.T) {
 	t.Run("should set IsFolder", func(t *testing.T) {
 		json := simplejson.New()
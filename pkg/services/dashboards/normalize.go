@@ -0,0 +1,127 @@
+package dashboards
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// panelDefaults are the panel properties the frontend adds when they are missing, see PanelModel.ts.
+// Leaving them out of the saved model doesn't change how the panel is loaded.
+var panelDefaults = map[string]any{
+	"transparent":     false,
+	"options":         map[string]any{},
+	"links":           []any{},
+	"transformations": []any{},
+	"title":           "",
+	"fieldConfig": map[string]any{
+		"defaults":  map[string]any{},
+		"overrides": []any{},
+	},
+}
+
+// nullableDashboardKeys and nullablePanelKeys are the optional properties the frontend treats the
+// same whether they are null or missing. Other nulls are kept since they can be meaningful, for
+// example a threshold step without value or a panel using the default data source.
+var (
+	nullableDashboardKeys = map[string]bool{
+		"description": true,
+		"gnetId":      true,
+	}
+	nullablePanelKeys = map[string]bool{
+		"description":     true,
+		"pluginVersion":   true,
+		"interval":        true,
+		"maxDataPoints":   true,
+		"timeFrom":        true,
+		"timeShift":       true,
+		"repeat":          true,
+		"repeatDirection": true,
+		"maxPerRow":       true,
+		"cacheTimeout":    true,
+		"queryCachingTTL": true,
+	}
+)
+
+type normalizeKind int
+
+const (
+	normalizeOther normalizeKind = iota
+	normalizeDashboard
+	normalizePanel
+)
+
+// NormalizeDashboardData returns a copy of the dashboard JSON without the optional null properties
+// and without panel properties that are set to their defaults, so that equal dashboards are saved
+// the same way. Keys need no sorting since the JSON encoder always writes object keys in sorted order.
+func NormalizeDashboardData(data *simplejson.Json) *simplejson.Json {
+	return simplejson.NewFromAny(normalizeValue(data.Interface(), normalizeDashboard))
+}
+
+// checksumExcludedKeys are the dashboard properties that change with each save, they are left out of the checksum
+var checksumExcludedKeys = []string{"id", "version"}
+
+// DashboardChecksum returns the hex encoded SHA-256 hash of the normalized dashboard JSON, without its id and
+// version, encoded with sorted keys, without spaces and without escaping HTML characters. The dashboards that
+// only differ by what NormalizeDashboardData removes have the same checksum.
+func DashboardChecksum(data *simplejson.Json) (string, error) {
+	normalized := NormalizeDashboardData(data).MustMap()
+	for _, key := range checksumExcludedKeys {
+		delete(normalized, key)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(normalized); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func normalizeValue(value any, kind normalizeKind) any {
+	switch v := value.(type) {
+	case map[string]any:
+		normalized := make(map[string]any, len(v))
+		for key, item := range v {
+			if item == nil && isNullableKey(kind, key) {
+				continue
+			}
+			itemKind := normalizeOther
+			if key == "panels" && kind != normalizeOther {
+				itemKind = normalizePanel
+			}
+			item = normalizeValue(item, itemKind)
+			if defaultValue, ok := panelDefaults[key]; kind == normalizePanel && ok && reflect.DeepEqual(item, defaultValue) {
+				continue
+			}
+			normalized[key] = item
+		}
+		return normalized
+	case []any:
+		// nulls are kept in arrays since removing them would shift the other items
+		normalized := make([]any, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeValue(item, kind)
+		}
+		return normalized
+	default:
+		return value
+	}
+}
+
+func isNullableKey(kind normalizeKind, key string) bool {
+	switch kind {
+	case normalizeDashboard:
+		return nullableDashboardKeys[key]
+	case normalizePanel:
+		return nullablePanelKeys[key]
+	default:
+		return false
+	}
+}
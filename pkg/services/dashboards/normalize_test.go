@@ -0,0 +1,97 @@
+package dashboards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestNormalizeDashboardData(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(`{
+		"title": "test dash",
+		"description": null,
+		"links": [],
+		"templating": {"list": [{"name": "var", "current": null, "options": [null, "a"]}]},
+		"panels": [
+			{
+				"id": 1,
+				"title": "",
+				"transparent": false,
+				"options": {},
+				"links": [],
+				"transformations": [],
+				"fieldConfig": {"defaults": {}, "overrides": []},
+				"pluginVersion": null,
+				"datasource": null
+			},
+			{
+				"id": 2,
+				"type": "row",
+				"title": "Row",
+				"panels": [{
+					"id": 3,
+					"transparent": true,
+					"options": {"legend": {"show": false}},
+					"fieldConfig": {"defaults": {"thresholds": {"steps": [{"color": "green", "value": null}]}}, "overrides": []},
+					"repeat": null
+				}]
+			}
+		]
+	}`))
+	require.NoError(t, err)
+
+	normalized, err := NormalizeDashboardData(data).Encode()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"title": "test dash",
+		"links": [],
+		"templating": {"list": [{"name": "var", "current": null, "options": [null, "a"]}]},
+		"panels": [
+			{"id": 1, "datasource": null},
+			{
+				"id": 2,
+				"type": "row",
+				"title": "Row",
+				"panels": [{
+					"id": 3,
+					"transparent": true,
+					"options": {"legend": {"show": false}},
+					"fieldConfig": {"defaults": {"thresholds": {"steps": [{"color": "green", "value": null}]}}, "overrides": []}
+				}]
+			}
+		]
+	}`, string(normalized))
+
+	t.Run("should not modify the original dashboard", func(t *testing.T) {
+		_, ok := data.CheckGet("description")
+		assert.True(t, ok)
+	})
+
+	t.Run("should write keys in sorted order", func(t *testing.T) {
+		encoded, err := NormalizeDashboardData(simplejson.NewFromAny(map[string]any{"b": 1, "a": 2})).Encode()
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":2,"b":1}`, string(encoded))
+	})
+}
+
+func TestDashboardChecksum(t *testing.T) {
+	checksum := func(t *testing.T, raw string) string {
+		t.Helper()
+		data, err := simplejson.NewJson([]byte(raw))
+		require.NoError(t, err)
+		sum, err := DashboardChecksum(data)
+		require.NoError(t, err)
+		return sum
+	}
+
+	sum := checksum(t, `{"title": "a <b>", "uid": "abc", "panels": [{"id": 1, "type": "graph"}]}`)
+	// sha256 of {"panels":[{"id":1,"type":"graph"}],"title":"a <b>","uid":"abc"}
+	assert.Equal(t, "0dd95a964cf80a259644dd6f902984eaddbbbccd099ad93739e171c06c250854", sum)
+
+	assert.Equal(t, sum, checksum(t, `{"id": 7, "version": 3, "uid": "abc", "title": "a <b>", "description": null,
+		"panels": [{"type": "graph", "id": 1, "links": [], "transparent": false}]}`), "the id, version and defaults don't change the checksum")
+	assert.NotEqual(t, sum, checksum(t, `{"title": "a <b>", "uid": "abc", "panels": [{"id": 2, "type": "graph"}]}`))
+}
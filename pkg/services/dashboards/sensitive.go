@@ -0,0 +1,191 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+const (
+	// EncryptedFieldKey holds the encrypted value of a sensitive field in the stored dashboard JSON
+	EncryptedFieldKey = "$encrypted"
+	// RedactedFieldKey replaces sensitive fields for users that can't read them
+	RedactedFieldKey = "$redacted"
+)
+
+// visitSensitiveField is called for every value matching a sensitive path with the stable key of the value,
+// where the array items with an id are identified by their id instead of their index.
+// It returns the new value of the field.
+type visitSensitiveField func(value any, key string) any
+
+// walkSensitivePath calls visit for the values matching path, where * matches every key or index
+func walkSensitivePath(value any, path []string, stable []string, visit visitSensitiveField) {
+	if len(path) == 0 {
+		return
+	}
+
+	visitItem := func(item any, key string, set func(any)) {
+		fieldKey := append(stable[:len(stable):len(stable)], key)
+		if len(path) > 1 {
+			walkSensitivePath(item, path[1:], fieldKey, visit)
+			return
+		}
+		if item != nil {
+			set(visit(item, strings.Join(fieldKey, ".")))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if path[0] != "*" && path[0] != key {
+				continue
+			}
+			key := key
+			visitItem(item, key, func(newValue any) { v[key] = newValue })
+		}
+	case []any:
+		for i, item := range v {
+			if path[0] != "*" && path[0] != strconv.Itoa(i) {
+				continue
+			}
+			i := i
+			visitItem(item, arrayItemKey(item, i), func(newValue any) { v[i] = newValue })
+		}
+	}
+}
+
+// arrayItemKey identifies panels and the other array items with an id by their id, so that the sensitive
+// fields follow them when they are moved
+func arrayItemKey(item any, index int) string {
+	if m, ok := item.(map[string]any); ok {
+		if id, ok := m["id"]; ok && id != nil {
+			return fmt.Sprintf("id=%v", id)
+		}
+	}
+	return strconv.Itoa(index)
+}
+
+func walkSensitiveFields(paths []string, data *simplejson.Json, visit visitSensitiveField) {
+	if data == nil {
+		return
+	}
+	for _, path := range paths {
+		walkSensitivePath(data.Interface(), strings.Split(path, "."), nil, visit)
+	}
+}
+
+// SensitiveFieldMarker returns the marker key of an encrypted or redacted field
+func SensitiveFieldMarker(value any) (string, bool) {
+	m, ok := value.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	for key := range m {
+		if key == EncryptedFieldKey || key == RedactedFieldKey {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// EncryptSensitiveFields encrypts the sensitive fields of a dashboard before it's saved.
+// Fields that are still encrypted or redacted keep the value of the previous versions for the same panel,
+// so that a dashboard can be saved by users that can't read its sensitive fields, and the save fails with
+// ErrDashboardSensitiveFieldNotFound when there is no such value.
+func EncryptSensitiveFields(ctx context.Context, secretsService secrets.Service, paths []string, data *simplejson.Json, previous ...*simplejson.Json) error {
+	previousValues := map[string]any{}
+	for _, p := range previous {
+		walkSensitiveFields(paths, p, func(value any, key string) any {
+			if marker, ok := SensitiveFieldMarker(value); ok && marker == EncryptedFieldKey {
+				previousValues[key] = value
+			}
+			return value
+		})
+	}
+
+	var err error
+	walkSensitiveFields(paths, data, func(value any, key string) any {
+		if err != nil {
+			return value
+		}
+		if _, ok := SensitiveFieldMarker(value); ok {
+			previousValue, ok := previousValues[key]
+			if !ok {
+				err = ErrDashboardSensitiveFieldNotFound
+				return value
+			}
+			return previousValue
+		}
+
+		var payload []byte
+		payload, err = json.Marshal(value)
+		if err != nil {
+			return value
+		}
+		var encrypted []byte
+		encrypted, err = secretsService.Encrypt(ctx, payload, secrets.WithoutScope())
+		if err != nil {
+			return value
+		}
+		return map[string]any{EncryptedFieldKey: base64.StdEncoding.EncodeToString(encrypted)}
+	})
+	return err
+}
+
+// DecryptSensitiveFields decrypts the sensitive fields of a dashboard.
+// The fields that fail to decrypt keep their encrypted value and the first error is returned.
+func DecryptSensitiveFields(ctx context.Context, secretsService secrets.Service, paths []string, data *simplejson.Json) error {
+	var err error
+	walkSensitiveFields(paths, data, func(value any, _ string) any {
+		if err != nil {
+			return value
+		}
+		marker, ok := SensitiveFieldMarker(value)
+		if !ok || marker != EncryptedFieldKey {
+			// values saved before the path was declared sensitive aren't encrypted
+			return value
+		}
+
+		decrypted, decryptErr := decryptSensitiveField(ctx, secretsService, value.(map[string]any)[EncryptedFieldKey])
+		if decryptErr != nil {
+			err = decryptErr
+			return value
+		}
+		return decrypted
+	})
+	return err
+}
+
+func decryptSensitiveField(ctx context.Context, secretsService secrets.Service, value any) (any, error) {
+	encoded, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted field is a %T instead of a string", value)
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := secretsService.Decrypt(ctx, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	var decrypted any
+	if err := json.Unmarshal(payload, &decrypted); err != nil {
+		return nil, err
+	}
+	return decrypted, nil
+}
+
+// RedactSensitiveFields replaces the sensitive fields of a dashboard with a redacted marker
+func RedactSensitiveFields(paths []string, data *simplejson.Json) {
+	walkSensitiveFields(paths, data, func(any, string) any {
+		return map[string]any{RedactedFieldKey: true}
+	})
+}
@@ -0,0 +1,119 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+)
+
+func TestSensitiveFields(t *testing.T) {
+	paths := []string{"panels.*.options.token", "panels.*.panels.*.options.token"}
+	secretsService := fakes.NewFakeSecretsService()
+	ctx := context.Background()
+
+	newDashboard := func(t *testing.T, token any) *simplejson.Json {
+		data, err := simplejson.NewJson([]byte(`{
+			"title": "Sensitive",
+			"panels": [
+				{"id": 1, "options": {"url": "http://example.com"}},
+				{"id": 2, "type": "row", "panels": [{"id": 3, "options": {}}]}
+			]
+		}`))
+		require.NoError(t, err)
+		data.Get("panels").GetIndex(0).Get("options").Set("token", token)
+		data.Get("panels").GetIndex(1).Get("panels").GetIndex(0).Get("options").Set("token", token)
+		return data
+	}
+	tokenAt := func(data *simplejson.Json) []any {
+		return []any{
+			data.Get("panels").GetIndex(0).Get("options").Get("token").Interface(),
+			data.Get("panels").GetIndex(1).Get("panels").GetIndex(0).Get("options").Get("token").Interface(),
+		}
+	}
+
+	t.Run("should encrypt and decrypt sensitive fields", func(t *testing.T) {
+		data := newDashboard(t, "secret")
+		require.NoError(t, EncryptSensitiveFields(ctx, secretsService, paths, data))
+
+		for _, token := range tokenAt(data) {
+			marker, ok := SensitiveFieldMarker(token)
+			require.True(t, ok)
+			assert.Equal(t, EncryptedFieldKey, marker)
+		}
+		assert.Equal(t, "http://example.com", data.Get("panels").GetIndex(0).Get("options").Get("url").MustString())
+
+		require.NoError(t, DecryptSensitiveFields(ctx, secretsService, paths, data))
+		assert.Equal(t, []any{"secret", "secret"}, tokenAt(data))
+	})
+
+	t.Run("should redact sensitive fields", func(t *testing.T) {
+		data := newDashboard(t, "secret")
+		RedactSensitiveFields(paths, data)
+		redacted := map[string]any{RedactedFieldKey: true}
+		assert.Equal(t, []any{redacted, redacted}, tokenAt(data))
+	})
+
+	t.Run("should keep the stored value of redacted fields when saving", func(t *testing.T) {
+		previous := newDashboard(t, "secret")
+		require.NoError(t, EncryptSensitiveFields(ctx, secretsService, paths, previous))
+		stored := tokenAt(previous)
+
+		data := newDashboard(t, map[string]any{RedactedFieldKey: true})
+		require.NoError(t, EncryptSensitiveFields(ctx, secretsService, paths, data, previous))
+		assert.Equal(t, stored, tokenAt(data))
+	})
+
+	t.Run("should keep the stored value of a panel that moved", func(t *testing.T) {
+		previous := newDashboard(t, nil)
+		previous.Get("panels").GetIndex(0).Get("options").Set("token", "first")
+		require.NoError(t, EncryptSensitiveFields(ctx, secretsService, paths, previous))
+		stored := previous.Get("panels").GetIndex(0).Get("options").Get("token").Interface()
+
+		data, err := simplejson.NewJson([]byte(`{
+			"panels": [
+				{"id": 4, "options": {"token": "second"}},
+				{"id": 1, "options": {"token": {"$redacted": true}}}
+			]
+		}`))
+		require.NoError(t, err)
+		require.NoError(t, EncryptSensitiveFields(ctx, secretsService, paths, data, previous))
+		assert.Equal(t, stored, data.Get("panels").GetIndex(1).Get("options").Get("token").Interface())
+
+		require.NoError(t, DecryptSensitiveFields(ctx, secretsService, paths, data))
+		assert.Equal(t, "second", data.Get("panels").GetIndex(0).Get("options").Get("token").MustString())
+		assert.Equal(t, "first", data.Get("panels").GetIndex(1).Get("options").Get("token").MustString())
+	})
+
+	t.Run("should reject markers without a stored value for the same panel", func(t *testing.T) {
+		previous := newDashboard(t, "secret")
+		require.NoError(t, EncryptSensitiveFields(ctx, secretsService, paths, previous))
+
+		data, err := simplejson.NewJson([]byte(`{"panels": [{"id": 5, "options": {"token": {"$redacted": true}}}]}`))
+		require.NoError(t, err)
+		err = EncryptSensitiveFields(ctx, secretsService, paths, data, previous)
+		assert.ErrorIs(t, err, ErrDashboardSensitiveFieldNotFound)
+
+		data = newDashboard(t, map[string]any{EncryptedFieldKey: "c2VjcmV0"})
+		err = EncryptSensitiveFields(ctx, secretsService, paths, data)
+		assert.ErrorIs(t, err, ErrDashboardSensitiveFieldNotFound)
+	})
+
+	t.Run("should keep the encrypted value of corrupt fields when decrypting", func(t *testing.T) {
+		for _, encrypted := range []any{
+			base64.StdEncoding.EncodeToString([]byte("not json")),
+			"not base64!",
+			42.0,
+		} {
+			corrupt := map[string]any{EncryptedFieldKey: encrypted}
+			data := newDashboard(t, corrupt)
+			assert.Error(t, DecryptSensitiveFields(ctx, secretsService, paths, data))
+			assert.Equal(t, []any{corrupt, corrupt}, tokenAt(data))
+		}
+	})
+}
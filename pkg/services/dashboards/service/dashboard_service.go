@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -36,6 +37,7 @@ var (
 		{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll},
 		{Action: dashboards.ActionDashboardsCreate, Scope: dashboards.ScopeFoldersAll},
 		{Action: dashboards.ActionDashboardsWrite, Scope: dashboards.ScopeFoldersAll},
+		{Action: dashboards.ActionFoldersReadOnlyWrite, Scope: dashboards.ScopeFoldersAll},
 		{Action: datasources.ActionRead, Scope: datasources.ScopeAll},
 	}
 	// DashboardServiceImpl implements the DashboardService interface
@@ -115,6 +117,10 @@ func (dr *DashboardServiceImpl) BuildSaveDashboardCommand(ctx context.Context, d
 		return nil, dashboards.ErrDashboardTitleEmpty
 	}
 
+	if dr.features.IsEnabled(ctx, featuremgmt.FlagDashboardJSONNormalization) {
+		dash.Data = dashboards.NormalizeDashboardData(dash.Data)
+	}
+
 	metrics.MFolderIDsServiceCount.WithLabelValues(metrics.Dashboard).Inc()
 	// nolint:staticcheck
 	if dash.IsFolder && dash.FolderID > 0 {
@@ -135,6 +141,18 @@ func (dr *DashboardServiceImpl) BuildSaveDashboardCommand(ctx context.Context, d
 		return nil, err
 	}
 
+	if err := validateDashboardLimits(dr.cfg, dash); err != nil {
+		return nil, err
+	}
+
+	if err := model.ValidateLabels(dash.GetLabels()); err != nil {
+		return nil, dashboards.ErrDashboardInvalidLabels
+	}
+
+	if _, err := dash.GetRenderProfiles(); err != nil {
+		return nil, dashboards.ErrDashboardInvalidRenderProfiles
+	}
+
 	if shouldValidateAlerts {
 		dashAlertInfo := alerting.DashAlertInfo{Dash: dash, User: dto.User, OrgID: dash.OrgID}
 		if err := dr.dashAlertExtractor.ValidateAlerts(ctx, dashAlertInfo); err != nil {
@@ -216,6 +234,10 @@ func (dr *DashboardServiceImpl) BuildSaveDashboardCommand(ctx context.Context, d
 		}
 	}
 
+	if err := dr.checkFolderReadOnly(ctx, dash, dto.User, isParentFolderChanged); err != nil {
+		return nil, err
+	}
+
 	userID, err := resolveUserID(dto.User, dr.log)
 	if err != nil {
 		return nil, err
@@ -309,6 +331,42 @@ func validateDashboardRefreshInterval(minRefreshInterval string, dash *dashboard
 	return nil
 }
 
+// validateDashboardLimits rejects dashboards exceeding the configured JSON size or number of panels
+func validateDashboardLimits(cfg *setting.Cfg, dash *dashboards.Dashboard) error {
+	if cfg.DashboardMaxPanels > 0 {
+		if count := countDashboardPanels(dash.Data.Get("panels").MustArray()); count > cfg.DashboardMaxPanels {
+			metrics.MDashboardLimitExceeded.WithLabelValues(metrics.DashboardLimitPanels).Inc()
+			return dashboards.NewDashboardLimitErr(dashboards.ErrDashboardTooManyPanels, "panels", int64(count), int64(cfg.DashboardMaxPanels))
+		}
+	}
+
+	if cfg.DashboardMaxJSONSize > 0 {
+		data, err := dash.Data.Encode()
+		if err != nil {
+			return err
+		}
+		if size := int64(len(data)); size > cfg.DashboardMaxJSONSize {
+			metrics.MDashboardLimitExceeded.WithLabelValues(metrics.DashboardLimitJSONSize).Inc()
+			return dashboards.NewDashboardLimitErr(dashboards.ErrDashboardTooLarge, "bytes", size, cfg.DashboardMaxJSONSize)
+		}
+	}
+
+	return nil
+}
+
+// countDashboardPanels counts the panels of a dashboard, including the panels of collapsed rows
+func countDashboardPanels(panels []any) int {
+	count := len(panels)
+	for _, panel := range panels {
+		if p, ok := panel.(map[string]any); ok {
+			if nested, ok := p["panels"].([]any); ok {
+				count += countDashboardPanels(nested)
+			}
+		}
+	}
+	return count
+}
+
 func (dr *DashboardServiceImpl) SaveProvisionedDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO,
 	provisioning *dashboards.DashboardProvisioning) (*dashboards.Dashboard, error) {
 	if err := validateDashboardRefreshInterval(dr.cfg.MinRefreshInterval, dto.Dashboard); err != nil {
@@ -419,7 +477,16 @@ func (dr *DashboardServiceImpl) SaveDashboard(ctx context.Context, dto *dashboar
 // DeleteDashboard removes dashboard from the DB. Errors out if the dashboard was provisioned. Should be used for
 // operations by the user where we want to make sure user does not delete provisioned dashboard.
 func (dr *DashboardServiceImpl) DeleteDashboard(ctx context.Context, dashboardId int64, orgId int64) error {
-	return dr.deleteDashboard(ctx, dashboardId, orgId, true)
+	return dr.deleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId}, true)
+}
+
+// DeleteDashboardAndRules deletes a dashboard with the alert rules that reference its panels.
+func (dr *DashboardServiceImpl) DeleteDashboardAndRules(ctx context.Context, dashboardId int64, orgId int64) error {
+	return dr.deleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId, ForceDeleteRules: true}, true)
+}
+
+func (dr *DashboardServiceImpl) GetDashboardDependents(ctx context.Context, query *dashboards.GetDashboardDependentsQuery) (*dashboards.DashboardDependents, error) {
+	return dr.dashboardStore.GetDashboardDependents(ctx, query)
 }
 
 func (dr *DashboardServiceImpl) GetDashboardByPublicUid(ctx context.Context, dashboardPublicUid string) (*dashboards.Dashboard, error) {
@@ -428,12 +495,12 @@ func (dr *DashboardServiceImpl) GetDashboardByPublicUid(ctx context.Context, das
 
 // DeleteProvisionedDashboard removes dashboard from the DB even if it is provisioned.
 func (dr *DashboardServiceImpl) DeleteProvisionedDashboard(ctx context.Context, dashboardId int64, orgId int64) error {
-	return dr.deleteDashboard(ctx, dashboardId, orgId, false)
+	return dr.deleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId}, false)
 }
 
-func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, dashboardId int64, orgId int64, validateProvisionedDashboard bool) error {
+func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, cmd *dashboards.DeleteDashboardCommand, validateProvisionedDashboard bool) error {
 	if validateProvisionedDashboard {
-		provisionedData, err := dr.GetProvisionedDashboardDataByDashboardID(ctx, dashboardId)
+		provisionedData, err := dr.GetProvisionedDashboardDataByDashboardID(ctx, cmd.ID)
 		if err != nil {
 			return fmt.Errorf("%v: %w", "failed to check if dashboard is provisioned", err)
 		}
@@ -442,7 +509,6 @@ func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, dashboardId
 			return dashboards.ErrDashboardCannotDeleteProvisionedDashboard
 		}
 	}
-	cmd := &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId}
 	return dr.dashboardStore.DeleteDashboard(ctx, cmd)
 }
 
@@ -727,6 +793,118 @@ func (dr *DashboardServiceImpl) GetDashboardTags(ctx context.Context, query *das
 	return dr.dashboardStore.GetDashboardTags(ctx, query)
 }
 
+func (dr *DashboardServiceImpl) GetPanelTypeUsage(ctx context.Context, query *dashboards.GetPanelTypeUsageQuery) ([]*dashboards.PanelTypeUsage, error) {
+	return dr.dashboardStore.GetPanelTypeUsage(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) ListDashboardChanges(ctx context.Context, query *dashboards.ListDashboardChangesQuery) (*dashboards.ListDashboardChangesResult, error) {
+	return dr.dashboardStore.ListDashboardChanges(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) DeleteExpiredDashboardChanges(ctx context.Context, olderThan time.Time) (int64, error) {
+	return dr.dashboardStore.DeleteExpiredDashboardChanges(ctx, olderThan)
+}
+
+func (dr *DashboardServiceImpl) ListDashboardsAfter(ctx context.Context, query *dashboards.ListDashboardsAfterQuery) ([]*dashboards.Dashboard, error) {
+	return dr.dashboardStore.ListDashboardsAfter(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) GetSearchFacets(ctx context.Context, query *dashboards.FindPersistedDashboardsQuery) (*model.Facets, error) {
+	return dr.dashboardStore.GetSearchFacets(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) CountSearchResults(ctx context.Context, query *dashboards.FindPersistedDashboardsQuery, max int64) (int64, error) {
+	return dr.dashboardStore.CountSearchResults(ctx, query, max)
+}
+
+// checkFolderReadOnly rejects the saves of the dashboards of read-only folders, including the dashboards moved in
+// or out of them, unless the user has the folders.readonly:write permission on the read-only folder
+func (dr *DashboardServiceImpl) checkFolderReadOnly(ctx context.Context, dash *dashboards.Dashboard, user identity.Requester, isParentFolderChanged bool) error {
+	if dash.IsFolder {
+		return nil
+	}
+
+	folderUIDs := make([]string, 0, 2)
+	if dash.FolderUID != "" {
+		folderUIDs = append(folderUIDs, dash.FolderUID)
+	}
+	if isParentFolderChanged && dash.ID != 0 {
+		existing, err := dr.dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: dash.OrgID, ID: dash.ID})
+		if err != nil && !errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return err
+		}
+		if existing != nil && existing.FolderUID != "" && existing.FolderUID != dash.FolderUID {
+			folderUIDs = append(folderUIDs, existing.FolderUID)
+		}
+	}
+
+	for _, folderUID := range folderUIDs {
+		readOnly, err := dr.GetFolderReadOnly(ctx, &dashboards.GetFolderReadOnlyQuery{OrgID: dash.OrgID, FolderUID: folderUID})
+		if err != nil {
+			return err
+		}
+		if readOnly == nil {
+			continue
+		}
+		evaluator := accesscontrol.EvalPermission(dashboards.ActionFoldersReadOnlyWrite, dashboards.ScopeFoldersProvider.GetResourceScopeUID(readOnly.FolderUID))
+		canWrite, err := dr.ac.Evaluate(ctx, user, evaluator)
+		if err != nil {
+			return err
+		}
+		if !canWrite {
+			return dashboards.NewDashboardFolderReadOnlyErr(readOnly)
+		}
+	}
+	return nil
+}
+
+func (dr *DashboardServiceImpl) GetFolderReadOnly(ctx context.Context, query *dashboards.GetFolderReadOnlyQuery) (*dashboards.FolderReadOnly, error) {
+	// the parents are ordered from the root folder, the flag of the closest folder applies
+	folderUIDs := []string{query.FolderUID}
+	parents, err := dr.folderService.GetParents(ctx, folder.GetParentsQuery{UID: query.FolderUID, OrgID: query.OrgID})
+	if err != nil && !errors.Is(err, folder.ErrFolderNotFound) {
+		return nil, err
+	}
+	for i := len(parents) - 1; i >= 0; i-- {
+		folderUIDs = append(folderUIDs, parents[i].UID)
+	}
+
+	flags, err := dr.dashboardStore.GetReadOnlyFolders(ctx, query.OrgID, folderUIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, folderUID := range folderUIDs {
+		for _, flag := range flags {
+			if flag.FolderUID == folderUID {
+				return flag, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (dr *DashboardServiceImpl) SetFolderReadOnly(ctx context.Context, cmd *dashboards.SetFolderReadOnlyCommand) error {
+	if len(cmd.Reason) > 255 {
+		return dashboards.ErrFolderReadOnlyReasonTooLong
+	}
+	return dr.dashboardStore.SetFolderReadOnly(ctx, cmd)
+}
+
+func (dr *DashboardServiceImpl) GetDashboardLabels(ctx context.Context, query *dashboards.GetDashboardLabelsQuery) (map[string]string, error) {
+	return dr.dashboardStore.GetDashboardLabels(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) SaveDashboardLabels(ctx context.Context, cmd *dashboards.SaveDashboardLabelsCommand) error {
+	if err := model.ValidateLabels(cmd.Labels); err != nil {
+		return dashboards.ErrDashboardInvalidLabels
+	}
+	return dr.dashboardStore.SaveDashboardLabels(ctx, cmd)
+}
+
+func (dr *DashboardServiceImpl) GetDashboardACLAudit(ctx context.Context, query *dashboards.GetDashboardACLAuditQuery) ([]*dashboards.DashboardACLAuditEntry, error) {
+	return dr.dashboardStore.GetDashboardACLAudit(ctx, query)
+}
+
 func (dr DashboardServiceImpl) CountInFolders(ctx context.Context, orgID int64, folderUIDs []string, u identity.Requester) (int64, error) {
 	return dr.dashboardStore.CountDashboardsInFolders(ctx, &dashboards.CountDashboardsInFolderRequest{FolderUIDs: folderUIDs, OrgID: orgID})
 }
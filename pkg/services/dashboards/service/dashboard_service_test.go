@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -11,7 +12,9 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/appcontext"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/folder/foldertest"
 	"github.com/grafana/grafana/pkg/services/guardian"
@@ -24,6 +27,8 @@ func TestDashboardService(t *testing.T) {
 	t.Run("Dashboard service tests", func(t *testing.T) {
 		fakeStore := dashboards.FakeDashboardStore{}
 		defer fakeStore.AssertExpectations(t)
+		// the saves moving dashboards out of a folder look up their current folder, it's read-only in none of the tests
+		fakeStore.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
 
 		folderSvc := foldertest.NewFakeService()
 
@@ -33,6 +38,7 @@ func TestDashboardService(t *testing.T) {
 			dashboardStore:     &fakeStore,
 			folderService:      folderSvc,
 			dashAlertExtractor: &dummyDashAlertExtractor{},
+			features:           featuremgmt.WithFeatures(),
 		}
 
 		origNewDashboardGuardian := guardian.New
@@ -117,6 +123,54 @@ func TestDashboardService(t *testing.T) {
 				require.NoError(t, err)
 			})
 
+			t.Run("Should normalize the dashboard JSON when normalization is enabled", func(t *testing.T) {
+				service.features = featuremgmt.WithFeatures(featuremgmt.FlagDashboardJSONNormalization)
+				t.Cleanup(func() { service.features = featuremgmt.WithFeatures() })
+
+				fakeStore.On("ValidateDashboardBeforeSave", mock.Anything, mock.Anything, mock.AnythingOfType("bool")).Return(true, nil).Once()
+				fakeStore.On("SaveDashboard", mock.Anything, mock.MatchedBy(func(cmd dashboards.SaveDashboardCommand) bool {
+					_, hasDescription := cmd.Dashboard.CheckGet("description")
+					return !hasDescription && cmd.Dashboard.Get("title").MustString() == "Dash"
+				})).Return(&dashboards.Dashboard{Data: simplejson.New()}, nil).Once()
+
+				dto.Dashboard = dashboards.NewDashboard("Dash")
+				dto.Dashboard.SetID(3)
+				dto.Dashboard.Data.Set("description", nil)
+				dto.User = &user.SignedInUser{UserID: 1}
+				_, err := service.SaveDashboard(context.Background(), dto, true)
+				require.NoError(t, err)
+			})
+
+			t.Run("Should return an error if the dashboard exceeds the size limits", func(t *testing.T) {
+				t.Cleanup(func() { service.cfg = setting.NewCfg() })
+
+				dto.Dashboard = dashboards.NewDashboard("Dash")
+				dto.Dashboard.Data.Set("panels", []any{
+					map[string]any{"id": 1},
+					map[string]any{"id": 2, "type": "row", "panels": []any{map[string]any{"id": 3}}},
+				})
+				dto.User = &user.SignedInUser{UserID: 1}
+
+				service.cfg = setting.NewCfg()
+				service.cfg.DashboardMaxPanels = 2
+				_, err := service.BuildSaveDashboardCommand(context.Background(), dto, false, false)
+				require.ErrorIs(t, err, dashboards.ErrDashboardTooManyPanels)
+				var limitErr dashboards.DashboardLimitErr
+				require.ErrorAs(t, err, &limitErr)
+				require.Equal(t, map[string]any{
+					"status":  "too-many-panels",
+					"message": "Dashboard exceeds the maximum number of panels: 3 panels, the limit is 2",
+					"unit":    "panels",
+					"actual":  int64(3),
+					"limit":   int64(2),
+				}, map[string]any(limitErr.Body()))
+
+				service.cfg = setting.NewCfg()
+				service.cfg.DashboardMaxJSONSize = 32
+				_, err = service.BuildSaveDashboardCommand(context.Background(), dto, false, false)
+				require.ErrorIs(t, err, dashboards.ErrDashboardTooLarge)
+			})
+
 			t.Run("Should return validation error if alert data is invalid", func(t *testing.T) {
 				origAlertingEnabledSet := service.cfg.AlertingEnabled != nil
 				origAlertingEnabledVal := false
@@ -244,3 +298,62 @@ func TestDashboardService(t *testing.T) {
 		})
 	})
 }
+
+func TestFolderReadOnly(t *testing.T) {
+	fakeStore := dashboards.FakeDashboardStore{}
+	defer fakeStore.AssertExpectations(t)
+	folderStore := foldertest.FakeFolderStore{}
+	folderStore.On("GetFolderByUID", mock.Anything, int64(1), "child").Return(&folder.Folder{ID: 2, UID: "child", OrgID: 1}, nil)
+
+	service := &DashboardServiceImpl{
+		cfg:                setting.NewCfg(),
+		log:                log.New("test.logger"),
+		dashboardStore:     &fakeStore,
+		folderStore:        &folderStore,
+		folderService:      &foldertest.FakeService{ExpectedFolders: []*folder.Folder{{UID: "parent", OrgID: 1}}},
+		dashAlertExtractor: &dummyDashAlertExtractor{},
+		features:           featuremgmt.WithFeatures(),
+	}
+
+	origNewDashboardGuardian := guardian.New
+	defer func() { guardian.New = origNewDashboardGuardian }()
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+
+	flag := &dashboards.FolderReadOnly{OrgID: 1, FolderUID: "parent", Reason: "Frozen"}
+	fakeStore.On("GetReadOnlyFolders", mock.Anything, int64(1), []string{"child", "parent"}).Return([]*dashboards.FolderReadOnly{flag}, nil)
+	fakeStore.On("ValidateDashboardBeforeSave", mock.Anything, mock.Anything, mock.AnythingOfType("bool")).Return(false, nil)
+
+	t.Run("Should return the flag of the closest parent folder", func(t *testing.T) {
+		readOnly, err := service.GetFolderReadOnly(context.Background(), &dashboards.GetFolderReadOnlyQuery{OrgID: 1, FolderUID: "child"})
+		require.NoError(t, err)
+		require.Equal(t, flag, readOnly)
+	})
+
+	newDTO := func() *dashboards.SaveDashboardDTO {
+		dash := dashboards.NewDashboard("Dash")
+		dash.OrgID = 1
+		dash.FolderUID = "child"
+		return &dashboards.SaveDashboardDTO{OrgID: 1, Dashboard: dash, User: &user.SignedInUser{UserID: 1, OrgID: 1}}
+	}
+
+	t.Run("Should reject the saves in a read-only folder", func(t *testing.T) {
+		service.ac = actest.FakeAccessControl{ExpectedEvaluate: false}
+		_, err := service.BuildSaveDashboardCommand(context.Background(), newDTO(), false, false)
+		require.ErrorIs(t, err, dashboards.ErrDashboardFolderReadOnly)
+		var readOnlyErr dashboards.DashboardFolderReadOnlyErr
+		require.ErrorAs(t, err, &readOnlyErr)
+		require.Equal(t, "parent", readOnlyErr.Body()["folderUid"])
+		require.Equal(t, "Frozen", readOnlyErr.Body()["readOnlyReason"])
+	})
+
+	t.Run("Should allow the saves of the users allowed to write read-only folders", func(t *testing.T) {
+		service.ac = actest.FakeAccessControl{ExpectedEvaluate: true}
+		_, err := service.BuildSaveDashboardCommand(context.Background(), newDTO(), false, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("Should reject reasons that are too long", func(t *testing.T) {
+		err := service.SetFolderReadOnly(context.Background(), &dashboards.SetFolderReadOnlyCommand{OrgID: 1, FolderUID: "child", ReadOnly: true, Reason: strings.Repeat("a", 256)})
+		require.ErrorIs(t, err, dashboards.ErrFolderReadOnlyReasonTooLong)
+	})
+}
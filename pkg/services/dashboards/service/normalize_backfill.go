@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+const (
+	normalizeBackfillBatchSize = 100
+	normalizeBackfillLockName  = "dashboard-json-normalization-backfill"
+	// normalizeBackfillLockTimeout is long enough for the backfill of large instances to finish
+	// before another instance can take over the lock
+	normalizeBackfillLockTimeout = time.Hour
+
+	normalizeBackfillNamespace = "dashboards"
+	normalizeBackfillDoneKey   = "json_normalization_backfill_done"
+)
+
+type serverLocker interface {
+	LockExecuteAndRelease(ctx context.Context, actionName string, maxInterval time.Duration, fn func(ctx context.Context)) error
+}
+
+// NormalizeBackfill normalizes the JSON of the dashboards saved before normalization was enabled.
+// It runs on a single instance at a time and only until it completed once.
+type NormalizeBackfill struct {
+	features       featuremgmt.FeatureToggles
+	dashboardStore dashboards.Store
+	serverLock     serverLocker
+	kvStore        *kvstore.NamespacedKVStore
+	log            log.Logger
+}
+
+func ProvideNormalizeBackfill(features featuremgmt.FeatureToggles, dashboardStore dashboards.Store, serverLock *serverlock.ServerLockService, kvStore kvstore.KVStore) *NormalizeBackfill {
+	return &NormalizeBackfill{
+		features:       features,
+		dashboardStore: dashboardStore,
+		serverLock:     serverLock,
+		kvStore:        kvstore.WithNamespace(kvStore, 0, normalizeBackfillNamespace),
+		log:            log.New("dashboards.normalize-backfill"),
+	}
+}
+
+// IsDisabled disables the backfill unless dashboard JSON normalization is enabled.
+func (b *NormalizeBackfill) IsDisabled() bool {
+	return !b.features.IsEnabledGlobally(featuremgmt.FlagDashboardJSONNormalization)
+}
+
+// Run normalizes the stored dashboards unless another instance is already doing it or the
+// backfill completed before. The backfill is an optimization, so failing it must not stop the server.
+func (b *NormalizeBackfill) Run(ctx context.Context) error {
+	done, err := b.isDone(ctx)
+	if err != nil {
+		b.log.Error("Failed to get the state of the backfill", "error", err)
+		return nil
+	}
+	if done {
+		return nil
+	}
+
+	err = b.serverLock.LockExecuteAndRelease(ctx, normalizeBackfillLockName, normalizeBackfillLockTimeout, func(ctx context.Context) {
+		// another instance can have completed the backfill while we were waiting for the lock
+		if done, err := b.isDone(ctx); err != nil || done {
+			return
+		}
+		if err := b.backfill(ctx); err != nil {
+			b.log.Error("Failed to normalize stored dashboards", "error", err)
+			return
+		}
+		if err := b.kvStore.Set(ctx, normalizeBackfillDoneKey, time.Now().UTC().Format(time.RFC3339)); err != nil {
+			b.log.Error("Failed to record the completion of the backfill", "error", err)
+		}
+	})
+	var lockExists *serverlock.ServerLockExistsError
+	if err != nil && !errors.As(err, &lockExists) {
+		b.log.Error("Failed to lock the backfill", "error", err)
+	}
+	return nil
+}
+
+func (b *NormalizeBackfill) isDone(ctx context.Context) (bool, error) {
+	_, ok, err := b.kvStore.Get(ctx, normalizeBackfillDoneKey)
+	return ok, err
+}
+
+func (b *NormalizeBackfill) backfill(ctx context.Context) error {
+	var afterID int64
+	var updated int
+	for {
+		result, err := b.dashboardStore.NormalizeStoredDashboards(ctx, &dashboards.NormalizeStoredDashboardsCommand{
+			AfterID: afterID,
+			Limit:   normalizeBackfillBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		updated += result.Updated
+		afterID = result.LastID
+		if result.Done {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	b.log.Info("Normalized stored dashboards", "updated", updated)
+	return nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+type fakeServerLock struct {
+	locked bool
+	calls  int
+}
+
+func (f *fakeServerLock) LockExecuteAndRelease(ctx context.Context, actionName string, maxInterval time.Duration, fn func(ctx context.Context)) error {
+	f.calls++
+	if f.locked {
+		return &serverlock.ServerLockExistsError{}
+	}
+	fn(ctx)
+	return nil
+}
+
+func setupNormalizeBackfill(t *testing.T, lock *fakeServerLock) (*NormalizeBackfill, *dashboards.FakeDashboardStore) {
+	t.Helper()
+
+	store := dashboards.NewFakeDashboardStore(t)
+	return &NormalizeBackfill{
+		features:       featuremgmt.WithFeatures(featuremgmt.FlagDashboardJSONNormalization),
+		dashboardStore: store,
+		serverLock:     lock,
+		kvStore:        kvstore.WithNamespace(kvstore.NewFakeKVStore(), 0, normalizeBackfillNamespace),
+		log:            log.NewNopLogger(),
+	}, store
+}
+
+func TestNormalizeBackfill(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should normalize stored dashboards in batches once", func(t *testing.T) {
+		lock := &fakeServerLock{}
+		backfill, store := setupNormalizeBackfill(t, lock)
+		store.On("NormalizeStoredDashboards", mock.Anything, &dashboards.NormalizeStoredDashboardsCommand{Limit: normalizeBackfillBatchSize}).
+			Return(&dashboards.NormalizeStoredDashboardsResult{LastID: 100, Updated: 10}, nil).Once()
+		store.On("NormalizeStoredDashboards", mock.Anything, &dashboards.NormalizeStoredDashboardsCommand{AfterID: 100, Limit: normalizeBackfillBatchSize}).
+			Return(&dashboards.NormalizeStoredDashboardsResult{LastID: 150, Updated: 2, Done: true}, nil).Once()
+
+		require.False(t, backfill.IsDisabled())
+		require.NoError(t, backfill.Run(ctx))
+
+		done, err := backfill.isDone(ctx)
+		require.NoError(t, err)
+		assert.True(t, done)
+
+		// the completion marker skips the following runs without taking the lock
+		require.NoError(t, backfill.Run(ctx))
+		assert.Equal(t, 1, lock.calls)
+	})
+
+	t.Run("should skip the backfill when another instance holds the lock", func(t *testing.T) {
+		backfill, _ := setupNormalizeBackfill(t, &fakeServerLock{locked: true})
+		require.NoError(t, backfill.Run(ctx))
+
+		done, err := backfill.isDone(ctx)
+		require.NoError(t, err)
+		assert.False(t, done)
+	})
+
+	t.Run("should not record the completion when the backfill fails", func(t *testing.T) {
+		backfill, store := setupNormalizeBackfill(t, &fakeServerLock{})
+		store.On("NormalizeStoredDashboards", mock.Anything, mock.Anything).Return(nil, context.DeadlineExceeded).Once()
+		require.NoError(t, backfill.Run(ctx))
+
+		done, err := backfill.isDone(ctx)
+		require.NoError(t, err)
+		assert.False(t, done)
+	})
+}
@@ -4,10 +4,13 @@ package dashboards
 
 import (
 	context "context"
+	time "time"
 
 	models "github.com/grafana/grafana/pkg/services/alerting/models"
 	mock "github.com/stretchr/testify/mock"
 
+	model "github.com/grafana/grafana/pkg/services/search/model"
+
 	quota "github.com/grafana/grafana/pkg/services/quota"
 )
 
@@ -66,6 +69,30 @@ func (_m *FakeDashboardStore) CountDashboardsInFolders(ctx context.Context, requ
 	return r0, r1
 }
 
+// CountSearchResults provides a mock function with given fields: ctx, query, max
+func (_m *FakeDashboardStore) CountSearchResults(ctx context.Context, query *FindPersistedDashboardsQuery, max int64) (int64, error) {
+	ret := _m.Called(ctx, query, max)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *FindPersistedDashboardsQuery, int64) (int64, error)); ok {
+		return rf(ctx, query, max)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *FindPersistedDashboardsQuery, int64) int64); ok {
+		r0 = rf(ctx, query, max)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *FindPersistedDashboardsQuery, int64) error); ok {
+		r1 = rf(ctx, query, max)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteDashboard provides a mock function with given fields: ctx, cmd
 func (_m *FakeDashboardStore) DeleteDashboard(ctx context.Context, cmd *DeleteDashboardCommand) error {
 	ret := _m.Called(ctx, cmd)
@@ -94,6 +121,30 @@ func (_m *FakeDashboardStore) DeleteDashboardsInFolders(ctx context.Context, req
 	return r0
 }
 
+// DeleteExpiredDashboardChanges provides a mock function with given fields: ctx, olderThan
+func (_m *FakeDashboardStore) DeleteExpiredDashboardChanges(ctx context.Context, olderThan time.Time) (int64, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, olderThan)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteOrphanedProvisionedDashboards provides a mock function with given fields: ctx, cmd
 func (_m *FakeDashboardStore) DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *DeleteOrphanedProvisionedDashboardsCommand) error {
 	ret := _m.Called(ctx, cmd)
@@ -160,6 +211,84 @@ func (_m *FakeDashboardStore) GetDashboard(ctx context.Context, query *GetDashbo
 	return r0, r1
 }
 
+// GetDashboardACLAudit provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetDashboardACLAudit(ctx context.Context, query *GetDashboardACLAuditQuery) ([]*DashboardACLAuditEntry, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*DashboardACLAuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardACLAuditQuery) ([]*DashboardACLAuditEntry, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardACLAuditQuery) []*DashboardACLAuditEntry); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DashboardACLAuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDashboardACLAuditQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDashboardDependents provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetDashboardDependents(ctx context.Context, query *GetDashboardDependentsQuery) (*DashboardDependents, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *DashboardDependents
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardDependentsQuery) (*DashboardDependents, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardDependentsQuery) *DashboardDependents); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardDependents)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDashboardDependentsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDashboardLabels provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetDashboardLabels(ctx context.Context, query *GetDashboardLabelsQuery) (map[string]string, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardLabelsQuery) (map[string]string, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardLabelsQuery) map[string]string); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDashboardLabelsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDashboardTags provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardStore) GetDashboardTags(ctx context.Context, query *GetDashboardTagsQuery) ([]*DashboardTagCloudItem, error) {
 	ret := _m.Called(ctx, query)
@@ -264,6 +393,32 @@ func (_m *FakeDashboardStore) GetDashboardsByPluginID(ctx context.Context, query
 	return r0, r1
 }
 
+// GetPanelTypeUsage provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetPanelTypeUsage(ctx context.Context, query *GetPanelTypeUsageQuery) ([]*PanelTypeUsage, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*PanelTypeUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetPanelTypeUsageQuery) ([]*PanelTypeUsage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetPanelTypeUsageQuery) []*PanelTypeUsage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*PanelTypeUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetPanelTypeUsageQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProvisionedDashboardData provides a mock function with given fields: ctx, name
 func (_m *FakeDashboardStore) GetProvisionedDashboardData(ctx context.Context, name string) ([]*DashboardProvisioning, error) {
 	ret := _m.Called(ctx, name)
@@ -342,6 +497,136 @@ func (_m *FakeDashboardStore) GetProvisionedDataByDashboardUID(ctx context.Conte
 	return r0, r1
 }
 
+// GetReadOnlyFolders provides a mock function with given fields: ctx, orgID, folderUIDs
+func (_m *FakeDashboardStore) GetReadOnlyFolders(ctx context.Context, orgID int64, folderUIDs []string) ([]*FolderReadOnly, error) {
+	ret := _m.Called(ctx, orgID, folderUIDs)
+
+	var r0 []*FolderReadOnly
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string) ([]*FolderReadOnly, error)); ok {
+		return rf(ctx, orgID, folderUIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string) []*FolderReadOnly); ok {
+		r0 = rf(ctx, orgID, folderUIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*FolderReadOnly)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []string) error); ok {
+		r1 = rf(ctx, orgID, folderUIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSearchFacets provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetSearchFacets(ctx context.Context, query *FindPersistedDashboardsQuery) (*model.Facets, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *model.Facets
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *FindPersistedDashboardsQuery) (*model.Facets, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *FindPersistedDashboardsQuery) *model.Facets); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Facets)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *FindPersistedDashboardsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDashboardChanges provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) ListDashboardChanges(ctx context.Context, query *ListDashboardChangesQuery) (*ListDashboardChangesResult, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *ListDashboardChangesResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDashboardChangesQuery) (*ListDashboardChangesResult, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDashboardChangesQuery) *ListDashboardChangesResult); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ListDashboardChangesResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ListDashboardChangesQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDashboardsAfter provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) ListDashboardsAfter(ctx context.Context, query *ListDashboardsAfterQuery) ([]*Dashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDashboardsAfterQuery) ([]*Dashboard, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDashboardsAfterQuery) []*Dashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ListDashboardsAfterQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NormalizeStoredDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) NormalizeStoredDashboards(ctx context.Context, cmd *NormalizeStoredDashboardsCommand) (*NormalizeStoredDashboardsResult, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *NormalizeStoredDashboardsResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *NormalizeStoredDashboardsCommand) (*NormalizeStoredDashboardsResult, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *NormalizeStoredDashboardsCommand) *NormalizeStoredDashboardsResult); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*NormalizeStoredDashboardsResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *NormalizeStoredDashboardsCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveAlerts provides a mock function with given fields: ctx, dashID, alerts
 func (_m *FakeDashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []*models.Alert) error {
 	ret := _m.Called(ctx, dashID, alerts)
@@ -382,6 +667,34 @@ func (_m *FakeDashboardStore) SaveDashboard(ctx context.Context, cmd SaveDashboa
 	return r0, r1
 }
 
+// SaveDashboardACLAudit provides a mock function with given fields: ctx, entry
+func (_m *FakeDashboardStore) SaveDashboardACLAudit(ctx context.Context, entry *DashboardACLAuditEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DashboardACLAuditEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveDashboardLabels provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) SaveDashboardLabels(ctx context.Context, cmd *SaveDashboardLabelsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *SaveDashboardLabelsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveProvisionedDashboard provides a mock function with given fields: ctx, cmd, provisioning
 func (_m *FakeDashboardStore) SaveProvisionedDashboard(ctx context.Context, cmd SaveDashboardCommand, provisioning *DashboardProvisioning) (*Dashboard, error) {
 	ret := _m.Called(ctx, cmd, provisioning)
@@ -408,6 +721,20 @@ func (_m *FakeDashboardStore) SaveProvisionedDashboard(ctx context.Context, cmd
 	return r0, r1
 }
 
+// SetFolderReadOnly provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) SetFolderReadOnly(ctx context.Context, cmd *SetFolderReadOnlyCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *SetFolderReadOnlyCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnprovisionDashboard provides a mock function with given fields: ctx, id
 func (_m *FakeDashboardStore) UnprovisionDashboard(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -4,11 +4,14 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 type ServiceImpl struct {
+	cfg            *setting.Cfg
 	store          dashboardsnapshots.Store
 	secretsService secrets.Service
 }
@@ -16,8 +19,9 @@ type ServiceImpl struct {
 // ServiceImpl implements the dashboardsnapshots Service interface
 var _ dashboardsnapshots.Service = (*ServiceImpl)(nil)
 
-func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service) *ServiceImpl {
+func ProvideService(cfg *setting.Cfg, store dashboardsnapshots.Store, secretsService secrets.Service) *ServiceImpl {
 	s := &ServiceImpl{
+		cfg:            cfg,
 		store:          store,
 		secretsService: secretsService,
 	}
@@ -26,6 +30,9 @@ func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Servi
 }
 
 func (s *ServiceImpl) CreateDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.CreateDashboardSnapshotCommand) (*dashboardsnapshots.DashboardSnapshot, error) {
+	// snapshots are shared with anyone that has the key, they never include the sensitive fields
+	dashboards.RedactSensitiveFields(s.cfg.SensitiveDashboardPaths, simplejson.NewFromAny(cmd.Dashboard.Object))
+
 	marshalledData, err := cmd.Dashboard.MarshalJSON()
 	if err != nil {
 		return nil, err
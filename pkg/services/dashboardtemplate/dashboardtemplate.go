@@ -0,0 +1,276 @@
+package dashboardtemplate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+)
+
+const (
+	// lineageNamespace is the kvstore namespace of the lineages, keyed by the UID of the created dashboards
+	lineageNamespace = "dashboard-template-lineage"
+
+	reconcileLockName = "dashboard-template-reconciliation"
+	reconcileInterval = time.Hour
+)
+
+var timeNow = time.Now
+
+// Service keeps the lineage of the dashboards created from templates, and merges the changes of the
+// templates into them. It periodically reconciles the lineages, recording the drift of each dashboard
+// and deleting the lineages of the deleted dashboards.
+type Service struct {
+	kvStore                 kvstore.KVStore
+	dashboardService        dashboards.DashboardService
+	dashboardVersionService dashver.Service
+	serverLock              *serverlock.ServerLockService
+	log                     log.Logger
+}
+
+func ProvideService(kvStore kvstore.KVStore, dashboardService dashboards.DashboardService, dashboardVersionService dashver.Service,
+	serverLock *serverlock.ServerLockService) *Service {
+	return &Service{
+		kvStore:                 kvStore,
+		dashboardService:        dashboardService,
+		dashboardVersionService: dashboardVersionService,
+		serverLock:              serverLock,
+		log:                     log.New("dashboards.templates"),
+	}
+}
+
+// GetLineage returns the lineage of a dashboard created from a template
+func (s *Service) GetLineage(ctx context.Context, orgID int64, dashboardUID string) (*Lineage, error) {
+	value, ok, err := s.kvStore.Get(ctx, orgID, lineageNamespace, dashboardUID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLineageNotFound.Errorf("no template lineage for dashboard %s", dashboardUID)
+	}
+	lineage := &Lineage{}
+	if err := json.Unmarshal([]byte(value), lineage); err != nil {
+		return nil, fmt.Errorf("failed to parse the template lineage of dashboard %s: %w", dashboardUID, err)
+	}
+	return lineage, nil
+}
+
+func (s *Service) SaveLineage(ctx context.Context, orgID int64, lineage *Lineage) error {
+	value, err := json.Marshal(lineage)
+	if err != nil {
+		return err
+	}
+	return s.kvStore.Set(ctx, orgID, lineageNamespace, lineage.DashboardUID, string(value))
+}
+
+// Merge returns the three-way merge of the changes of the template of a dashboard since its lineage
+// version into the dashboard. The conflicting properties get the value of the side of the resolution.
+// The value of the dashboard is kept when there's no resolution.
+func (s *Service) Merge(ctx context.Context, orgID int64, dashboardUID string, resolution Resolution) (*MergeResult, error) {
+	if err := resolution.Validate(); err != nil {
+		return nil, err
+	}
+	lineage, err := s.GetLineage(ctx, orgID, dashboardUID)
+	if err != nil {
+		return nil, err
+	}
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: dashboardUID, OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: lineage.TemplateUID, OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	return s.merge(ctx, lineage, dash, template, resolution)
+}
+
+func (s *Service) merge(ctx context.Context, lineage *Lineage, dash *dashboards.Dashboard, template *dashboards.Dashboard, resolution Resolution) (*MergeResult, error) {
+	theirsData, err := Materialize(template.Data, lineage.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	theirs := withoutIgnoredProperties(theirsData.MustMap())
+	ours := withoutIgnoredProperties(dash.Data.MustMap())
+
+	drift := &Drift{
+		FromVersion: lineage.TemplateVersion,
+		ToVersion:   template.Version,
+		Checked:     timeNow(),
+	}
+	var merged map[string]any
+	base, err := s.baseVersion(ctx, lineage, template)
+	if err != nil {
+		return nil, err
+	}
+	if base != nil {
+		m := &merger{resolution: resolution}
+		merged = m.merge("", base, theirs, ours).(map[string]any)
+		drift.TemplateChanges = changes("", base, theirs)
+		drift.LocalChanges = changes("", base, ours)
+		drift.Conflicts = m.conflicts
+	} else {
+		// without the base version, the changes of the template can't be told apart from the local changes
+		drift.BaseMissing = true
+		drift.Conflicts = changes("", theirs, ours)
+		merged = ours
+		if resolution == ResolutionTemplate {
+			merged = theirs
+		}
+	}
+	drift.Status = driftStatus(drift)
+
+	data := simplejson.NewFromAny(merged)
+	data.Set("id", dash.ID)
+	data.Set("uid", dash.UID)
+	data.Set("version", dash.Version)
+	return &MergeResult{
+		Lineage:   lineage,
+		Dashboard: dash,
+		Drift:     drift,
+		Data:      data,
+		Changed:   len(changes("", merged, ours)) > 0,
+	}, nil
+}
+
+// baseVersion returns the materialized template version of the lineage, or nil if the version was deleted
+func (s *Service) baseVersion(ctx context.Context, lineage *Lineage, template *dashboards.Dashboard) (map[string]any, error) {
+	data := template.Data
+	if lineage.TemplateVersion != template.Version {
+		version, err := s.dashboardVersionService.Get(ctx, &dashver.GetDashboardVersionQuery{
+			DashboardID:  template.ID,
+			DashboardUID: template.UID,
+			OrgID:        template.OrgID,
+			Version:      lineage.TemplateVersion,
+		})
+		if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		data = version.Data
+	}
+	base, err := Materialize(data, lineage.Parameters)
+	if err != nil {
+		// the variables of the parameters can have been removed from the template since then
+		if errors.Is(err, ErrInvalidParameters) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return withoutIgnoredProperties(base.MustMap()), nil
+}
+
+func driftStatus(drift *Drift) DriftStatus {
+	if drift.BaseMissing {
+		if len(drift.Conflicts) == 0 {
+			return DriftUpToDate
+		}
+		return DriftDiverged
+	}
+	switch {
+	case len(drift.TemplateChanges) > 0 && len(drift.LocalChanges) > 0:
+		return DriftDiverged
+	case len(drift.TemplateChanges) > 0:
+		return DriftTemplateChanged
+	case len(drift.LocalChanges) > 0:
+		return DriftLocallyModified
+	}
+	return DriftUpToDate
+}
+
+// Run reconciles the lineages every reconcileInterval, on a single instance at a time
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+	for {
+		err := s.serverLock.LockAndExecute(ctx, reconcileLockName, reconcileInterval, func(ctx context.Context) {
+			if err := s.Reconcile(ctx); err != nil {
+				s.log.Error("Failed to reconcile the dashboard template lineages", "error", err)
+			}
+		})
+		if err != nil {
+			s.log.Error("Failed to lock the reconciliation of the dashboard template lineages", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile records the drift of the dashboards of all the lineages and deletes the lineages of the
+// deleted dashboards. It doesn't change the dashboards, the changes of the templates are re-applied on demand.
+func (s *Service) Reconcile(ctx context.Context) error {
+	keys, err := s.kvStore.Keys(ctx, kvstore.AllOrganizations, lineageNamespace, "")
+	if err != nil {
+		return err
+	}
+
+	var checked, pruned int
+	drifted := map[DriftStatus]int{}
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		lineage, err := s.GetLineage(ctx, key.OrgId, key.Key)
+		if err != nil {
+			s.log.Warn("Failed to get the template lineage", "orgId", key.OrgId, "dashboardUid", key.Key, "error", err)
+			continue
+		}
+
+		dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: key.Key, OrgID: key.OrgId})
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			if err := s.kvStore.Del(ctx, key.OrgId, lineageNamespace, key.Key); err != nil {
+				return err
+			}
+			pruned++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		drift, err := s.drift(ctx, lineage, dash)
+		if err != nil {
+			s.log.Warn("Failed to compute the drift of the dashboard from its template", "orgId", key.OrgId, "dashboardUid", key.Key, "error", err)
+			continue
+		}
+		lineage.Drift = drift
+		if err := s.SaveLineage(ctx, key.OrgId, lineage); err != nil {
+			return err
+		}
+		checked++
+		drifted[drift.Status]++
+	}
+
+	s.log.Info("Reconciled the dashboard template lineages", "checked", checked, "pruned", pruned,
+		"templateChanged", drifted[DriftTemplateChanged], "diverged", drifted[DriftDiverged], "templateDeleted", drifted[DriftTemplateDeleted])
+	return nil
+}
+
+func (s *Service) drift(ctx context.Context, lineage *Lineage, dash *dashboards.Dashboard) (*Drift, error) {
+	template, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: lineage.TemplateUID, OrgID: dash.OrgID})
+	if errors.Is(err, dashboards.ErrDashboardNotFound) {
+		return &Drift{Status: DriftTemplateDeleted, FromVersion: lineage.TemplateVersion, Checked: timeNow()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.merge(ctx, lineage, dash, template, "")
+	if err != nil {
+		return nil, err
+	}
+	return result.Drift, nil
+}
@@ -0,0 +1,190 @@
+package dashboardtemplate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
+)
+
+func TestMaterialize(t *testing.T) {
+	template := mustJSON(t, `{
+		"id": 3,
+		"uid": "template",
+		"title": "Service template",
+		"version": 4,
+		"panels": [
+			{"type": "timeseries", "datasource": {"type": "prometheus", "uid": "${DS_PROM}"}, "targets": [{"expr": "up", "datasource": {"uid": "${DS_PROM}"}}]},
+			{"type": "logs", "datasource": "Loki"}
+		],
+		"templating": {"list": [
+			{"name": "service", "type": "textbox", "query": "api"},
+			{"name": "env", "type": "custom", "query": "prod,staging", "current": {"text": "prod", "value": "prod"}}
+		]}
+	}`)
+
+	data, err := Materialize(template, Parameters{
+		Title:       "Payments",
+		Datasources: map[string]string{"${DS_PROM}": "prom-eu", "Loki": "loki-eu"},
+		Variables:   map[string]string{"service": "payments", "env": "staging"},
+	})
+	require.NoError(t, err)
+
+	b, err := data.Encode()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"title": "Payments",
+		"panels": [
+			{"type": "timeseries", "datasource": {"type": "prometheus", "uid": "prom-eu"}, "targets": [{"expr": "up", "datasource": {"uid": "prom-eu"}}]},
+			{"type": "logs", "datasource": {"uid": "loki-eu"}}
+		],
+		"templating": {"list": [
+			{"name": "service", "type": "textbox", "query": "payments", "current": {"text": "payments", "value": "payments", "selected": true}},
+			{"name": "env", "type": "custom", "query": "prod,staging", "current": {"text": "staging", "value": "staging", "selected": true}}
+		]}
+	}`, string(b))
+	assert.Equal(t, "${DS_PROM}", template.GetPath("panels").GetIndex(0).GetPath("datasource", "uid").MustString(), "the template is unchanged")
+
+	_, err = Materialize(template, Parameters{Title: "Payments", Variables: map[string]string{"missing": "x"}})
+	assert.ErrorIs(t, err, ErrInvalidParameters)
+}
+
+const (
+	baseTemplate = `{"id": 3, "uid": "template", "version": 4, "title": "Service template", "refresh": "1m", "panels": [
+		{"id": 1, "type": "timeseries", "title": "Requests"},
+		{"id": 2, "type": "logs", "title": "Logs"}
+	]}`
+	currentTemplate = `{"id": 3, "uid": "template", "version": 5, "title": "Service template", "refresh": "30s", "panels": [
+		{"id": 1, "type": "timeseries", "title": "Requests per second"},
+		{"id": 2, "type": "logs", "title": "Logs"},
+		{"id": 3, "type": "timeseries", "title": "Errors"}
+	]}`
+)
+
+func TestService_Merge(t *testing.T) {
+	setup := func(t *testing.T, derived string, versionErr error) *Service {
+		t.Helper()
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool { return q.UID == "template" })).
+			Return(&dashboards.Dashboard{ID: 3, UID: "template", OrgID: 1, Version: 5, Data: mustJSON(t, currentTemplate)}, nil)
+		dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool { return q.UID == "payments" })).
+			Return(&dashboards.Dashboard{ID: 10, UID: "payments", OrgID: 1, Version: 2, Data: mustJSON(t, derived)}, nil)
+		versions := dashvertest.NewDashboardVersionServiceFake()
+		versions.ExpectedDashboardVersion = &dashver.DashboardVersionDTO{Version: 4, Data: mustJSON(t, baseTemplate)}
+		versions.ExpectedError = versionErr
+
+		s := ProvideService(kvstore.NewFakeKVStore(), dashSvc, versions, nil)
+		require.NoError(t, s.SaveLineage(context.Background(), 1, &Lineage{
+			DashboardUID:    "payments",
+			TemplateUID:     "template",
+			TemplateVersion: 4,
+			Parameters:      Parameters{Title: "Payments"},
+		}))
+		return s
+	}
+
+	t.Run("should merge the template changes with the local changes", func(t *testing.T) {
+		s := setup(t, `{"id": 10, "uid": "payments", "version": 2, "title": "Payments", "refresh": "1m", "panels": [
+			{"id": 2, "type": "table", "title": "Logs"},
+			{"id": 1, "type": "timeseries", "title": "Requests"}
+		]}`, nil)
+
+		result, err := s.Merge(context.Background(), 1, "payments", "")
+		require.NoError(t, err)
+		assert.Equal(t, DriftDiverged, result.Drift.Status)
+		assert.Equal(t, []string{"panels[id=1].title", "panels[id=3]", "refresh"}, result.Drift.TemplateChanges)
+		assert.Equal(t, []string{"panels[id=2].type"}, result.Drift.LocalChanges)
+		assert.Empty(t, result.Drift.Conflicts)
+		assert.True(t, result.Changed)
+
+		b, err := result.Data.Encode()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id": 10, "uid": "payments", "version": 2, "title": "Payments", "refresh": "30s", "panels": [
+			{"id": 2, "type": "table", "title": "Logs"},
+			{"id": 1, "type": "timeseries", "title": "Requests per second"},
+			{"id": 3, "type": "timeseries", "title": "Errors"}
+		]}`, string(b))
+	})
+
+	t.Run("should report the properties changed on both sides as conflicts", func(t *testing.T) {
+		derived := `{"id": 10, "uid": "payments", "version": 2, "title": "Payments", "refresh": "1m", "panels": [
+			{"id": 1, "type": "timeseries", "title": "Payments requests"},
+			{"id": 2, "type": "logs", "title": "Logs"}
+		]}`
+		s := setup(t, derived, nil)
+
+		result, err := s.Merge(context.Background(), 1, "payments", "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"panels[id=1].title"}, result.Drift.Conflicts)
+		assert.Equal(t, "Payments requests", result.Data.Get("panels").GetIndex(0).Get("title").MustString())
+
+		result, err = s.Merge(context.Background(), 1, "payments", ResolutionTemplate)
+		require.NoError(t, err)
+		assert.Equal(t, "Requests per second", result.Data.Get("panels").GetIndex(0).Get("title").MustString())
+		assert.Equal(t, "30s", result.Data.Get("refresh").MustString())
+	})
+
+	t.Run("should report every difference as a conflict without the base version", func(t *testing.T) {
+		s := setup(t, `{"id": 10, "uid": "payments", "version": 2, "title": "Payments", "refresh": "1m", "panels": [
+			{"id": 1, "type": "timeseries", "title": "Requests"},
+			{"id": 2, "type": "logs", "title": "Logs"}
+		]}`, dashver.ErrDashboardVersionNotFound)
+
+		result, err := s.Merge(context.Background(), 1, "payments", "")
+		require.NoError(t, err)
+		assert.True(t, result.Drift.BaseMissing)
+		assert.Equal(t, DriftDiverged, result.Drift.Status)
+		assert.Equal(t, []string{"panels[id=1].title", "panels[id=3]", "refresh"}, result.Drift.Conflicts)
+		assert.False(t, result.Changed)
+	})
+
+	t.Run("should reject an invalid resolution", func(t *testing.T) {
+		s := ProvideService(kvstore.NewFakeKVStore(), nil, nil, nil)
+		_, err := s.Merge(context.Background(), 1, "payments", "theirs")
+		assert.ErrorIs(t, err, ErrInvalidResolution)
+	})
+}
+
+func TestService_Reconcile(t *testing.T) {
+	dashSvc := dashboards.NewFakeDashboardService(t)
+	dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool { return q.UID == "template" })).
+		Return(&dashboards.Dashboard{ID: 3, UID: "template", OrgID: 1, Version: 4, Data: mustJSON(t, baseTemplate)}, nil)
+	dashSvc.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool { return q.UID == "payments" })).
+		Return(&dashboards.Dashboard{ID: 10, UID: "payments", OrgID: 1, Version: 2, Data: mustJSON(t, `{"title": "Payments", "refresh": "5m", "panels": [
+			{"id": 1, "type": "timeseries", "title": "Requests"},
+			{"id": 2, "type": "logs", "title": "Logs"}
+		]}`)}, nil)
+	dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound)
+
+	s := ProvideService(kvstore.NewFakeKVStore(), dashSvc, dashvertest.NewDashboardVersionServiceFake(), nil)
+	ctx := context.Background()
+	for _, uid := range []string{"payments", "deleted"} {
+		require.NoError(t, s.SaveLineage(ctx, 1, &Lineage{DashboardUID: uid, TemplateUID: "template", TemplateVersion: 4, Parameters: Parameters{Title: "Payments"}}))
+	}
+
+	require.NoError(t, s.Reconcile(ctx))
+
+	lineage, err := s.GetLineage(ctx, 1, "payments")
+	require.NoError(t, err)
+	require.NotNil(t, lineage.Drift)
+	assert.Equal(t, DriftLocallyModified, lineage.Drift.Status)
+	assert.Equal(t, []string{"refresh"}, lineage.Drift.LocalChanges)
+
+	_, err = s.GetLineage(ctx, 1, "deleted")
+	assert.ErrorIs(t, err, ErrLineageNotFound)
+}
+
+func mustJSON(t *testing.T, s string) *simplejson.Json {
+	t.Helper()
+	data, err := simplejson.NewJson([]byte(s))
+	require.NoError(t, err)
+	return data
+}
@@ -0,0 +1,79 @@
+package dashboardtemplate
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// Materialize returns a copy of the template JSON with the parameters applied. The copy has no id, uid
+// nor version, so that it's saved as a new dashboard.
+func Materialize(template *simplejson.Json, params Parameters) (*simplejson.Json, error) {
+	b, err := template.Encode()
+	if err != nil {
+		return nil, err
+	}
+	data, err := simplejson.NewJson(b)
+	if err != nil {
+		return nil, err
+	}
+	data.Del("id")
+	data.Del("uid")
+	data.Del("version")
+	data.Set("title", params.Title)
+
+	if len(params.Datasources) > 0 {
+		data = simplejson.NewFromAny(replaceDatasources(data.Interface(), params.Datasources))
+	}
+
+	variables := map[string]struct{}{}
+	for _, v := range data.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(v)
+		name := variable.Get("name").MustString()
+		value, ok := params.Variables[name]
+		if !ok {
+			continue
+		}
+		variables[name] = struct{}{}
+		variable.Set("current", map[string]any{"text": value, "value": value, "selected": true})
+		switch variable.Get("type").MustString() {
+		case "constant", "textbox":
+			variable.Set("query", value)
+		}
+	}
+	for name := range params.Variables {
+		if _, ok := variables[name]; !ok {
+			return nil, ErrInvalidParameters.Errorf("the template has no variable %q", name)
+		}
+	}
+	return data, nil
+}
+
+// replaceDatasources replaces the data source references of the panels, targets, variables and
+// annotations, whether they are set by name, by uid or as a ${DS_NAME} input
+func replaceDatasources(v any, datasources map[string]string) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for k, item := range value {
+			if k != "datasource" {
+				value[k] = replaceDatasources(item, datasources)
+				continue
+			}
+			switch ref := item.(type) {
+			case string:
+				if uid, ok := datasources[ref]; ok {
+					value[k] = map[string]any{"uid": uid}
+				}
+			case map[string]any:
+				if s, ok := ref["uid"].(string); ok {
+					if uid, ok := datasources[s]; ok {
+						ref["uid"] = uid
+					}
+				}
+			}
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = replaceDatasources(item, datasources)
+		}
+	}
+	return v
+}
@@ -0,0 +1,199 @@
+package dashboardtemplate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ignoredProperties are the properties of the dashboards that always differ from their template
+var ignoredProperties = []string{"id", "uid", "version"}
+
+// absent is the value of the properties missing from one of the sides of the merge
+type absent struct{}
+
+// merger merges the changes of theirs, the template, and of ours, the dashboard, since base, the template
+// version the dashboard was created from. The panels are matched by id so that adding or moving a panel
+// on one side doesn't conflict with the changes of the other panels on the other side.
+type merger struct {
+	resolution Resolution
+	conflicts  []string
+}
+
+func (m *merger) merge(path string, base, theirs, ours any) any {
+	if reflect.DeepEqual(theirs, ours) || reflect.DeepEqual(base, theirs) {
+		return ours
+	}
+	if reflect.DeepEqual(base, ours) {
+		return theirs
+	}
+
+	// both sides changed the value, the changes can still be merged when they are in different properties
+	switch t := theirs.(type) {
+	case map[string]any:
+		if o, ok := ours.(map[string]any); ok {
+			b, _ := base.(map[string]any)
+			return m.mergeObjects(path, b, t, o)
+		}
+	case []any:
+		if o, ok := ours.([]any); ok && isPanelsPath(path) {
+			b, _ := base.([]any)
+			if merged, ok := m.mergePanels(path, b, t, o); ok {
+				return merged
+			}
+		}
+	}
+
+	m.conflicts = append(m.conflicts, path)
+	if m.resolution == ResolutionTemplate {
+		return theirs
+	}
+	return ours
+}
+
+func (m *merger) mergeObjects(path string, base, theirs, ours map[string]any) map[string]any {
+	merged := make(map[string]any, len(ours))
+	for _, k := range unionKeys(base, theirs, ours) {
+		if v := m.merge(joinPath(path, k), valueOf(base, k), valueOf(theirs, k), valueOf(ours, k)); v != (absent{}) {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// mergePanels merges the panels by id, in the order of ours followed by the panels added to theirs.
+// It returns false when a panel has no id.
+func (m *merger) mergePanels(path string, base, theirs, ours []any) ([]any, bool) {
+	baseByID, _, ok := panelsByID(base)
+	if !ok {
+		return nil, false
+	}
+	theirsByID, theirsOrder, ok := panelsByID(theirs)
+	if !ok {
+		return nil, false
+	}
+	oursByID, order, ok := panelsByID(ours)
+	if !ok {
+		return nil, false
+	}
+	for _, id := range theirsOrder {
+		if _, ok := oursByID[id]; !ok {
+			order = append(order, id)
+		}
+	}
+
+	merged := make([]any, 0, len(order))
+	for _, id := range order {
+		v := m.merge(fmt.Sprintf("%s[id=%s]", path, id), panelOf(baseByID, id), panelOf(theirsByID, id), panelOf(oursByID, id))
+		if v != (absent{}) {
+			merged = append(merged, v)
+		}
+	}
+	return merged, true
+}
+
+// changes returns the paths of the properties that differ between a and b
+func changes(path string, a, b any) []string {
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+	switch av := a.(type) {
+	case map[string]any:
+		if bv, ok := b.(map[string]any); ok {
+			var paths []string
+			for _, k := range unionKeys(av, bv) {
+				paths = append(paths, changes(joinPath(path, k), valueOf(av, k), valueOf(bv, k))...)
+			}
+			return paths
+		}
+	case []any:
+		if bv, ok := b.([]any); ok && isPanelsPath(path) {
+			aByID, aOrder, aOK := panelsByID(av)
+			bByID, bOrder, bOK := panelsByID(bv)
+			if aOK && bOK {
+				var paths []string
+				for _, id := range unionKeys(aByID, bByID) {
+					paths = append(paths, changes(fmt.Sprintf("%s[id=%s]", path, id), panelOf(aByID, id), panelOf(bByID, id))...)
+				}
+				if len(paths) == 0 && !reflect.DeepEqual(aOrder, bOrder) {
+					// only the order of the panels changed
+					paths = append(paths, path)
+				}
+				return paths
+			}
+		}
+	}
+	return []string{path}
+}
+
+// withoutIgnoredProperties returns a shallow copy of the dashboard JSON without the ignored properties
+func withoutIgnoredProperties(data map[string]any) map[string]any {
+	copied := make(map[string]any, len(data))
+	for k, v := range data {
+		copied[k] = v
+	}
+	for _, k := range ignoredProperties {
+		delete(copied, k)
+	}
+	return copied
+}
+
+func isPanelsPath(path string) bool {
+	return path == "panels" || strings.HasSuffix(path, ".panels")
+}
+
+// panelsByID returns the panels by id and the ids in order, it returns false when a panel has no id
+func panelsByID(panels []any) (map[string]any, []string, bool) {
+	byID := make(map[string]any, len(panels))
+	order := make([]string, 0, len(panels))
+	for _, p := range panels {
+		panel, ok := p.(map[string]any)
+		if !ok || panel["id"] == nil {
+			return nil, nil, false
+		}
+		id := fmt.Sprint(panel["id"])
+		if _, ok := byID[id]; ok {
+			return nil, nil, false
+		}
+		byID[id] = panel
+		order = append(order, id)
+	}
+	return byID, order, true
+}
+
+func panelOf(panels map[string]any, id string) any {
+	if p, ok := panels[id]; ok {
+		return p
+	}
+	return absent{}
+}
+
+func valueOf(m map[string]any, k string) any {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	return absent{}
+}
+
+func unionKeys(maps ...map[string]any) []string {
+	seen := map[string]struct{}{}
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, k string) string {
+	if path == "" {
+		return k
+	}
+	return path + "." + k
+}
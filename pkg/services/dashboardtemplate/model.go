@@ -0,0 +1,99 @@
+package dashboardtemplate
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrInvalidParameters = errutil.BadRequest("dashboards.invalid-template-parameters")
+	ErrLineageNotFound   = errutil.NotFound("dashboards.template-lineage-not-found", errutil.WithPublicMessage("Dashboard wasn't created from a template"))
+	ErrInvalidResolution = errutil.BadRequest("dashboards.invalid-template-resolution")
+	ErrReapplyConflict   = errutil.Conflict("dashboards.template-reapply-conflict", errutil.WithPublicMessage("The template and the dashboard changed the same properties"))
+)
+
+// Parameters are the substitutions applied to a template dashboard
+type Parameters struct {
+	Title string `json:"title"`
+	// Datasources maps the data source UIDs, or the ${DS_NAME} inputs, of the template to the UIDs of the copy.
+	Datasources map[string]string `json:"datasources,omitempty"`
+	// Variables are the default values of the template variables of the copy.
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// Lineage links a dashboard created from a template to the template version it was created from, or that
+// was last re-applied to it
+type Lineage struct {
+	DashboardUID    string     `json:"dashboardUid"`
+	TemplateUID     string     `json:"templateUid"`
+	TemplateVersion int        `json:"templateVersion"`
+	Parameters      Parameters `json:"parameters"`
+	Created         time.Time  `json:"created"`
+	Updated         time.Time  `json:"updated"`
+	// Drift is the drift found by the last reconciliation
+	Drift *Drift `json:"drift,omitempty"`
+}
+
+type DriftStatus string
+
+const (
+	// DriftUpToDate is the status of the dashboards that have all the changes of the template
+	DriftUpToDate DriftStatus = "up-to-date"
+	// DriftTemplateChanged is the status of the unmodified dashboards whose template changed
+	DriftTemplateChanged DriftStatus = "template-changed"
+	// DriftLocallyModified is the status of the modified dashboards whose template didn't change
+	DriftLocallyModified DriftStatus = "locally-modified"
+	// DriftDiverged is the status of the modified dashboards whose template changed too
+	DriftDiverged DriftStatus = "diverged"
+	// DriftTemplateDeleted is the status of the dashboards whose template was deleted
+	DriftTemplateDeleted DriftStatus = "template-deleted"
+)
+
+// Drift is the difference between a dashboard and its template. The changes are the paths of the
+// properties that differ, the panels are identified by their id such as panels[id=2].title.
+type Drift struct {
+	Status DriftStatus `json:"status"`
+	// FromVersion is the version of the template in the lineage, ToVersion its current version
+	FromVersion int `json:"fromVersion"`
+	ToVersion   int `json:"toVersion"`
+	// TemplateChanges are the changes of the template since FromVersion
+	TemplateChanges []string `json:"templateChanges"`
+	// LocalChanges are the changes of the dashboard since it was created or re-applied
+	LocalChanges []string `json:"localChanges"`
+	// Conflicts are the properties changed by both the template and the dashboard
+	Conflicts []string `json:"conflicts"`
+	// BaseMissing is set when the FromVersion was deleted from the version history. Every difference
+	// between the template and the dashboard is then a conflict.
+	BaseMissing bool      `json:"baseMissing,omitempty"`
+	Checked     time.Time `json:"checked"`
+}
+
+// Resolution is the side kept for the conflicts when re-applying a template
+type Resolution string
+
+const (
+	ResolutionTemplate  Resolution = "template"
+	ResolutionDashboard Resolution = "dashboard"
+)
+
+func (r Resolution) Validate() error {
+	switch r {
+	case "", ResolutionTemplate, ResolutionDashboard:
+		return nil
+	}
+	return ErrInvalidResolution.Errorf("invalid resolution %q, expected %q or %q", r, ResolutionTemplate, ResolutionDashboard)
+}
+
+// MergeResult is the three-way merge of the changes of a template into a dashboard created from it
+type MergeResult struct {
+	Lineage   *Lineage
+	Dashboard *dashboards.Dashboard
+	Drift     *Drift
+	// Data is the JSON of the dashboard with the changes of the template, it has the id, uid and version of the dashboard
+	Data *simplejson.Json
+	// Changed is false when Data is the JSON of the dashboard unchanged
+	Changed bool
+}
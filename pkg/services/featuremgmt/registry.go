@@ -1198,6 +1198,14 @@ var (
 			RequiresRestart: true,
 			Expression:      "true", // enabled by default
 		},
+		{
+			Name:            "dashboardJSONNormalization",
+			Description:     "Strips null and default values from dashboard JSON on save and backfills existing dashboards",
+			Stage:           FeatureStageExperimental,
+			Owner:           grafanaBackendPlatformSquad,
+			RequiresRestart: true,
+			HideFromDocs:    true,
+		},
 	}
 )
 
@@ -650,4 +650,8 @@ const (
 	// FlagAlertingUpgradeDryrunOnStart
 	// When activated in legacy alerting mode, this initiates a dry-run of the Unified Alerting upgrade during each startup. It logs any issues detected without implementing any actual changes.
 	FlagAlertingUpgradeDryrunOnStart = "alertingUpgradeDryrunOnStart"
+
+	// FlagDashboardJSONNormalization
+	// Strips null and default values from dashboard JSON on save and backfills existing dashboards
+	FlagDashboardJSONNormalization = "dashboardJSONNormalization"
 )
@@ -0,0 +1,247 @@
+package impersonation
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Post("/api/admin/users/:id/impersonate", middleware.ReqGrafanaAdmin, routing.Wrap(s.handleStart))
+	routeRegister.Delete("/api/user/impersonation", middleware.ReqSignedIn, routing.Wrap(s.handleStop))
+	routeRegister.Get("/api/admin/impersonation/events", middleware.ReqGrafanaAdmin, routing.Wrap(s.handleSearchEvents))
+}
+
+// swagger:parameters startImpersonation
+type StartImpersonationParams struct {
+	// in:path
+	// required:true
+	UserID int64 `json:"user_id"`
+	// in:body
+	Body StartImpersonationCommand `json:"body"`
+}
+
+type StartImpersonationCommand struct {
+	// Duration of the impersonation, e.g. 15m. Defaults to and can't exceed impersonation_max_duration.
+	Duration string `json:"duration"`
+}
+
+// swagger:model
+type ImpersonationDTO struct {
+	Message string    `json:"message"`
+	UserID  int64     `json:"userId"`
+	Login   string    `json:"login"`
+	Expires time.Time `json:"expires"`
+}
+
+// swagger:response startImpersonationResponse
+type StartImpersonationResponse struct {
+	// in:body
+	Body ImpersonationDTO `json:"body"`
+}
+
+// swagger:route POST /admin/users/{user_id}/impersonate admin_users startImpersonation
+//
+// Start acting as another user for a limited time. Only Grafana admins can impersonate, and
+// Grafana admins and service accounts can't be impersonated. Every request made while
+// impersonating is logged with both users, and the requests that can change something are
+// recorded in the impersonation events.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: startImpersonationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleStart(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if c.UserToken == nil {
+		return response.Error(http.StatusBadRequest, "Impersonation requires signing in with a session", nil)
+	}
+	if c.SignedInUser.ImpersonatorID != 0 {
+		return response.Error(http.StatusBadRequest, "Already impersonating a user", nil)
+	}
+	if c.SignedInUser.UserID == userID {
+		return response.Error(http.StatusBadRequest, "You cannot impersonate yourself", nil)
+	}
+
+	duration := s.cfg.ImpersonationMaxDuration
+	cmd := StartImpersonationCommand{}
+	if c.Req.ContentLength != 0 {
+		if err := web.Bind(c.Req, &cmd); err != nil {
+			return response.Error(http.StatusBadRequest, "bad request data", err)
+		}
+	}
+	if cmd.Duration != "" {
+		duration, err = gtime.ParseDuration(cmd.Duration)
+		if err != nil || duration <= 0 {
+			return response.Error(http.StatusBadRequest, "Invalid duration", err)
+		}
+		if duration > s.cfg.ImpersonationMaxDuration {
+			return response.Error(http.StatusBadRequest, "Duration exceeds the maximum impersonation duration of "+s.cfg.ImpersonationMaxDuration.String(), nil)
+		}
+	}
+
+	target, err := s.userService.GetByID(c.Req.Context(), &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.Error(http.StatusNotFound, user.ErrUserNotFound.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get user", err)
+	}
+	if target.IsAdmin {
+		return response.Error(http.StatusForbidden, "Grafana admins cannot be impersonated", nil)
+	}
+	if target.IsServiceAccount {
+		return response.Error(http.StatusBadRequest, "Service accounts cannot be impersonated", nil)
+	}
+	if target.IsDisabled {
+		return response.Error(http.StatusBadRequest, "Disabled users cannot be impersonated", nil)
+	}
+
+	now := time.Now()
+	expires := now.Add(duration)
+	uid := util.GenerateShortUID()
+	token, err := signToken(s.cfg.SecretKey, claims{
+		UID:            uid,
+		ImpersonatorID: c.SignedInUser.UserID,
+		UserID:         target.ID,
+		Login:          target.Login,
+		SessionID:      c.UserToken.Id,
+		Expires:        expires.Unix(),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create impersonation token", err)
+	}
+
+	if err := s.store.insert(c.Req.Context(), &Event{
+		ImpersonationUID:  uid,
+		Kind:              EventStarted,
+		ImpersonatorID:    c.SignedInUser.UserID,
+		ImpersonatorLogin: c.SignedInUser.Login,
+		UserID:            target.ID,
+		UserLogin:         target.Login,
+		Created:           now,
+	}); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to record impersonation", err)
+	}
+	cookies.WriteCookie(c.Resp, CookieName, token, int(duration.Seconds()), nil)
+
+	s.log.FromContext(c.Req.Context()).Info("Started impersonation", "impersonatorId", c.SignedInUser.UserID,
+		"impersonatorLogin", c.SignedInUser.Login, "userId", target.ID, "login", target.Login, "expires", expires)
+
+	return response.JSON(http.StatusOK, ImpersonationDTO{
+		Message: "Impersonation started",
+		UserID:  target.ID,
+		Login:   target.Login,
+		Expires: expires,
+	})
+}
+
+// swagger:route DELETE /user/impersonation signed_in_user stopImpersonation
+//
+// Stop impersonating a user and continue as the Grafana admin.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+func (s *Service) handleStop(c *contextmodel.ReqContext) response.Response {
+	if c.SignedInUser.ImpersonatorID == 0 {
+		return response.Error(http.StatusBadRequest, "Not impersonating a user", nil)
+	}
+
+	cookies.DeleteCookie(c.Resp, CookieName, nil)
+
+	// stopping doesn't fail when it can't be recorded, the impersonation ends either way
+	var uid string
+	if cookie, err := c.Req.Cookie(CookieName); err == nil {
+		if claims, err := verifyToken(s.cfg.SecretKey, cookie.Value, time.Now()); err == nil {
+			uid = claims.UID
+		}
+	}
+	if err := s.store.insert(c.Req.Context(), &Event{
+		ImpersonationUID:  uid,
+		Kind:              EventStopped,
+		ImpersonatorID:    c.SignedInUser.ImpersonatorID,
+		ImpersonatorLogin: c.SignedInUser.ImpersonatorLogin,
+		UserID:            c.SignedInUser.UserID,
+		UserLogin:         c.SignedInUser.Login,
+		Created:           time.Now(),
+	}); err != nil {
+		s.log.FromContext(c.Req.Context()).Error("Failed to record the end of the impersonation", "error", err)
+	}
+
+	s.log.FromContext(c.Req.Context()).Info("Stopped impersonation", "impersonatorId", c.SignedInUser.ImpersonatorID,
+		"impersonatorLogin", c.SignedInUser.ImpersonatorLogin, "userId", c.SignedInUser.UserID, "login", c.SignedInUser.Login)
+
+	return response.Success("Impersonation stopped")
+}
+
+// swagger:parameters searchImpersonationEvents
+type SearchImpersonationEventsParams struct {
+	// in:query
+	ImpersonatorID int64 `json:"impersonatorId"`
+	// in:query
+	UserID int64 `json:"userId"`
+	// in:query
+	ImpersonationUID string `json:"impersonationUid"`
+	// in:query
+	// default:100
+	PerPage int `json:"perpage"`
+	// in:query
+	// default:1
+	Page int `json:"page"`
+}
+
+// swagger:response searchImpersonationEventsResponse
+type SearchImpersonationEventsResponse struct {
+	// in:body
+	Body []*Event `json:"body"`
+}
+
+// swagger:route GET /admin/impersonation/events admin_users searchImpersonationEvents
+//
+// List when impersonations started and stopped and the requests that could change something
+// while they lasted, most recent first.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: searchImpersonationEventsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleSearchEvents(c *contextmodel.ReqContext) response.Response {
+	events, err := s.store.search(c.Req.Context(), &SearchEventsQuery{
+		ImpersonatorID:   c.QueryInt64("impersonatorId"),
+		UserID:           c.QueryInt64("userId"),
+		ImpersonationUID: c.Query("impersonationUid"),
+		Limit:            c.QueryInt("perpage"),
+		Page:             c.QueryInt("page"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to search impersonation events", err)
+	}
+	return response.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,152 @@
+package impersonation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	// CookieName is the cookie holding the signed impersonation token
+	CookieName = "grafana_impersonation"
+
+	// HeaderImpersonatedBy and HeaderImpersonationExpires are added to every response while
+	// impersonating, so that the frontend can show who is impersonating and until when.
+	HeaderImpersonatedBy       = "X-Grafana-Impersonated-By"
+	HeaderImpersonationExpires = "X-Grafana-Impersonation-Expires"
+
+	// hookPriority runs the hook before the user is fetched and the permissions are loaded,
+	// so that both are the ones of the impersonated user.
+	hookPriority = 90
+
+	// impersonatorCheckInterval is how often the impersonator is checked to still be a Grafana admin,
+	// so an impersonation ends at most this long after the admin permission was revoked
+	impersonatorCheckInterval = time.Minute
+)
+
+// Service lets Grafana admins act as another user for a limited time. The session of the
+// admin stays the same, only the identity of the requests is switched.
+type Service struct {
+	cfg         *setting.Cfg
+	userService user.Service
+	store       *store
+	cache       *localcache.CacheService
+	log         log.Logger
+}
+
+func ProvideService(cfg *setting.Cfg, authnService authn.Service, userService user.Service, sqlStore db.DB,
+	routeRegister routing.RouteRegister) *Service {
+	s := &Service{
+		cfg:         cfg,
+		userService: userService,
+		store:       &store{db: sqlStore},
+		cache:       localcache.New(impersonatorCheckInterval, 2*impersonatorCheckInterval),
+		log:         log.New("impersonation"),
+	}
+
+	if !cfg.ImpersonationEnabled {
+		return s
+	}
+
+	authnService.RegisterPostAuthHook(s.hook, hookPriority)
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+// hook switches the identity of requests made with the session of an impersonating admin
+// to the impersonated user
+func (s *Service) hook(ctx context.Context, identity *authn.Identity, r *authn.Request) error {
+	if identity.SessionToken == nil || r.HTTPRequest == nil {
+		return nil
+	}
+
+	cookie, err := r.HTTPRequest.Cookie(CookieName)
+	if err != nil {
+		return nil
+	}
+
+	c, err := verifyToken(s.cfg.SecretKey, cookie.Value, time.Now())
+	if err != nil {
+		s.log.FromContext(ctx).Debug("Ignoring impersonation cookie", "error", err)
+		return nil
+	}
+
+	namespace, id := identity.GetNamespacedID()
+	if namespace != authn.NamespaceUser || id != strconv.FormatInt(c.ImpersonatorID, 10) || identity.SessionToken.Id != c.SessionID {
+		return nil
+	}
+
+	// the impersonator must still be a Grafana admin, otherwise they continue as themselves
+	impersonator, err := s.getImpersonator(ctx, c.ImpersonatorID)
+	if err != nil || !impersonator.IsAdmin || impersonator.IsDisabled {
+		s.log.FromContext(ctx).Warn("Ignoring impersonation by a user that is no longer a Grafana admin", "impersonatorId", c.ImpersonatorID, "userId", c.UserID)
+		return nil
+	}
+
+	if isWriteRequest(r.HTTPRequest) {
+		// the request is rejected when it can't be recorded, so that every change made while impersonating is in the history
+		if err := s.store.insert(ctx, &Event{
+			ImpersonationUID:  c.UID,
+			Kind:              EventRequest,
+			ImpersonatorID:    impersonator.ID,
+			ImpersonatorLogin: impersonator.Login,
+			UserID:            c.UserID,
+			UserLogin:         c.Login,
+			Method:            r.HTTPRequest.Method,
+			Path:              r.HTTPRequest.URL.Path,
+			Created:           time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to record impersonated request: %w", err)
+		}
+	}
+
+	identity.ID = authn.NamespacedID(authn.NamespaceUser, c.UserID)
+	identity.ImpersonatorID = impersonator.ID
+	identity.ImpersonatorLogin = impersonator.Login
+	identity.ClientParams.FetchSyncedUser = true
+	identity.ClientParams.SyncPermissions = true
+
+	if r.Resp != nil {
+		expires := time.Unix(c.Expires, 0).UTC().Format(time.RFC3339)
+		r.Resp.Before(func(w web.ResponseWriter) {
+			w.Header().Set(HeaderImpersonatedBy, impersonator.Login)
+			w.Header().Set(HeaderImpersonationExpires, expires)
+		})
+	}
+	return nil
+}
+
+// getImpersonator returns the impersonator, it is only fetched once per impersonatorCheckInterval
+func (s *Service) getImpersonator(ctx context.Context, id int64) (*user.User, error) {
+	key := fmt.Sprintf("impersonator-%d", id)
+	if cached, ok := s.cache.Get(key); ok {
+		return cached.(*user.User), nil
+	}
+
+	impersonator, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(key, impersonator, impersonatorCheckInterval)
+	return impersonator, nil
+}
+
+func isWriteRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
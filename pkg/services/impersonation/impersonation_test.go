@@ -0,0 +1,285 @@
+package impersonation
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/authn"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func setupTestService(t *testing.T, sqlStore db.DB, userService user.Service) *Service {
+	t.Helper()
+	return &Service{
+		cfg:         &setting.Cfg{SecretKey: "secret", ImpersonationMaxDuration: time.Hour},
+		userService: userService,
+		store:       &store{db: sqlStore},
+		cache:       localcache.New(impersonatorCheckInterval, 2*impersonatorCheckInterval),
+		log:         log.NewNopLogger(),
+	}
+}
+
+func TestIntegrationImpersonationAPI(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+
+	type testCase struct {
+		desc           string
+		target         *user.User
+		body           string
+		noSession      bool
+		expectedStatus int
+	}
+
+	tests := []testCase{
+		{
+			desc:           "should start impersonation and set the cookie",
+			target:         &user.User{ID: 2, Login: "editor"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "should accept a shorter duration",
+			target:         &user.User{ID: 2, Login: "editor"},
+			body:           `{"duration": "10m"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "should reject a duration above the maximum",
+			target:         &user.User{ID: 2, Login: "editor"},
+			body:           `{"duration": "2h"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "should not impersonate Grafana admins",
+			target:         &user.User{ID: 2, Login: "admin2", IsAdmin: true},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "should require a session",
+			target:         &user.User{ID: 2, Login: "editor"},
+			noSession:      true,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := setupTestService(t, sqlStore, &usertest.FakeUserService{ExpectedUser: tt.target})
+			routeRegister := routing.NewRouteRegister()
+			s.registerAPIEndpoints(routeRegister)
+			server := webtest.NewServer(t, routeRegister)
+
+			req := server.NewPostRequest("/api/admin/users/2/impersonate", strings.NewReader(tt.body))
+			reqCtx := &contextmodel.ReqContext{
+				SignedInUser: &user.SignedInUser{UserID: 1, Login: "admin", IsGrafanaAdmin: true},
+				IsSignedIn:   true,
+			}
+			if !tt.noSession {
+				reqCtx.UserToken = &auth.UserToken{Id: 5}
+			}
+			webtest.RequestWithWebContext(req, reqCtx)
+
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			if tt.expectedStatus == http.StatusOK {
+				var cookie *http.Cookie
+				for _, c := range res.Cookies() {
+					if c.Name == CookieName {
+						cookie = c
+					}
+				}
+				require.NotNil(t, cookie)
+				c, err := verifyToken("secret", cookie.Value, time.Now())
+				require.NoError(t, err)
+				assert.Equal(t, claims{UID: c.UID, ImpersonatorID: 1, UserID: 2, Login: "editor", SessionID: 5, Expires: c.Expires}, *c)
+
+				events, err := s.store.search(context.Background(), &SearchEventsQuery{ImpersonationUID: c.UID})
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				assert.Equal(t, EventStarted, events[0].Kind)
+				assert.Equal(t, "admin", events[0].ImpersonatorLogin)
+				assert.Equal(t, "editor", events[0].UserLogin)
+			}
+		})
+	}
+
+	t.Run("should stop impersonation", func(t *testing.T) {
+		s := setupTestService(t, sqlStore, &usertest.FakeUserService{})
+		routeRegister := routing.NewRouteRegister()
+		s.registerAPIEndpoints(routeRegister)
+		server := webtest.NewServer(t, routeRegister)
+
+		token, err := signToken("secret", claims{UID: "stopped", ImpersonatorID: 1, UserID: 2, SessionID: 5, Expires: time.Now().Add(time.Hour).Unix()})
+		require.NoError(t, err)
+		req := server.NewRequest(http.MethodDelete, "/api/user/impersonation", nil)
+		req.AddCookie(&http.Cookie{Name: CookieName, Value: token})
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 2, Login: "editor", ImpersonatorID: 1, ImpersonatorLogin: "admin"})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		events, err := s.store.search(context.Background(), &SearchEventsQuery{ImpersonationUID: "stopped"})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, EventStopped, events[0].Kind)
+		assert.Equal(t, int64(1), events[0].ImpersonatorID)
+
+		req = server.NewRequest(http.MethodDelete, "/api/user/impersonation", nil)
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 2})
+		res, err = server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestIntegrationImpersonationHook(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+
+	type testCase struct {
+		desc         string
+		claims       claims
+		impersonator *user.User
+		expectedID   string
+	}
+
+	expires := time.Now().Add(time.Hour).Unix()
+	tests := []testCase{
+		{
+			desc:         "should switch to the impersonated user",
+			claims:       claims{ImpersonatorID: 1, UserID: 2, SessionID: 5, Expires: expires},
+			impersonator: &user.User{ID: 1, Login: "admin", IsAdmin: true},
+			expectedID:   "user:2",
+		},
+		{
+			desc:         "should ignore impersonation of another session",
+			claims:       claims{ImpersonatorID: 1, UserID: 2, SessionID: 6, Expires: expires},
+			impersonator: &user.User{ID: 1, Login: "admin", IsAdmin: true},
+			expectedID:   "user:1",
+		},
+		{
+			desc:         "should ignore expired impersonation",
+			claims:       claims{ImpersonatorID: 1, UserID: 2, SessionID: 5, Expires: time.Now().Add(-time.Minute).Unix()},
+			impersonator: &user.User{ID: 1, Login: "admin", IsAdmin: true},
+			expectedID:   "user:1",
+		},
+		{
+			desc:         "should ignore impersonation by a user that is no longer a Grafana admin",
+			claims:       claims{ImpersonatorID: 1, UserID: 2, SessionID: 5, Expires: expires},
+			impersonator: &user.User{ID: 1, Login: "admin"},
+			expectedID:   "user:1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := setupTestService(t, sqlStore, &usertest.FakeUserService{ExpectedUser: tt.impersonator})
+
+			token, err := signToken("secret", tt.claims)
+			require.NoError(t, err)
+			httpReq, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			httpReq.AddCookie(&http.Cookie{Name: CookieName, Value: token})
+
+			identity := &authn.Identity{
+				ID:           authn.NamespacedID(authn.NamespaceUser, 1),
+				SessionToken: &auth.UserToken{Id: 5},
+			}
+			require.NoError(t, s.hook(context.Background(), identity, &authn.Request{HTTPRequest: httpReq}))
+
+			assert.Equal(t, tt.expectedID, identity.ID)
+			if tt.expectedID != "user:1" {
+				assert.Equal(t, int64(1), identity.ImpersonatorID)
+				assert.Equal(t, "admin", identity.ImpersonatorLogin)
+				assert.True(t, identity.ClientParams.FetchSyncedUser)
+			} else {
+				assert.Zero(t, identity.ImpersonatorID)
+			}
+		})
+	}
+
+	t.Run("should record the requests that can change something", func(t *testing.T) {
+		s := setupTestService(t, sqlStore, &usertest.FakeUserService{ExpectedUser: &user.User{ID: 1, Login: "admin", IsAdmin: true}})
+		token, err := signToken("secret", claims{UID: "requests", ImpersonatorID: 1, UserID: 2, Login: "editor", SessionID: 5, Expires: expires})
+		require.NoError(t, err)
+
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+			httpReq, err := http.NewRequest(method, "/api/dashboards/uid/abc", nil)
+			require.NoError(t, err)
+			httpReq.AddCookie(&http.Cookie{Name: CookieName, Value: token})
+			identity := &authn.Identity{ID: authn.NamespacedID(authn.NamespaceUser, 1), SessionToken: &auth.UserToken{Id: 5}}
+			require.NoError(t, s.hook(context.Background(), identity, &authn.Request{HTTPRequest: httpReq}))
+		}
+
+		events, err := s.store.search(context.Background(), &SearchEventsQuery{ImpersonationUID: "requests"})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		for _, e := range events {
+			assert.Equal(t, EventRequest, e.Kind)
+			assert.Equal(t, "editor", e.UserLogin)
+			assert.Equal(t, "/api/dashboards/uid/abc", e.Path)
+		}
+	})
+
+	t.Run("should only check the impersonator once per interval", func(t *testing.T) {
+		userService := &usertest.FakeUserService{ExpectedUser: &user.User{ID: 1, Login: "admin", IsAdmin: true}}
+		s := setupTestService(t, sqlStore, userService)
+
+		impersonator, err := s.getImpersonator(context.Background(), 1)
+		require.NoError(t, err)
+		assert.True(t, impersonator.IsAdmin)
+
+		userService.ExpectedUser = &user.User{ID: 1, Login: "admin"}
+		impersonator, err = s.getImpersonator(context.Background(), 1)
+		require.NoError(t, err)
+		assert.True(t, impersonator.IsAdmin)
+	})
+
+	t.Run("should reject tampered tokens", func(t *testing.T) {
+		token, err := signToken("secret", claims{ImpersonatorID: 1, UserID: 2, SessionID: 5, Expires: expires})
+		require.NoError(t, err)
+		_, err = verifyToken("other-secret", token, time.Now())
+		assert.ErrorIs(t, err, errInvalidToken)
+	})
+
+	t.Run("should reject tokens signed with the bare secret key", func(t *testing.T) {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(`{"imp":1,"usr":2,"sid":5,"exp":` + strconv.FormatInt(expires, 10) + `}`))
+		mac := hmac.New(sha256.New, []byte("secret"))
+		_, _ = mac.Write([]byte(payload))
+		_, err := verifyToken("secret", payload+"."+base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), time.Now())
+		assert.ErrorIs(t, err, errInvalidToken)
+	})
+}
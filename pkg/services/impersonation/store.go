@@ -0,0 +1,80 @@
+package impersonation
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+const (
+	EventStarted = "started"
+	EventStopped = "stopped"
+	// EventRequest is a request that can change something, made while impersonating
+	EventRequest = "request"
+)
+
+// Event is the durable record of an impersonation: when it started and stopped and the
+// requests that could change something in between.
+type Event struct {
+	ID int64 `json:"id" xorm:"pk autoincr 'id'"`
+	// ImpersonationUID is shared by all the events of an impersonation
+	ImpersonationUID  string    `json:"impersonationUid" xorm:"impersonation_uid"`
+	Kind              string    `json:"kind"`
+	ImpersonatorID    int64     `json:"impersonatorId" xorm:"impersonator_id"`
+	ImpersonatorLogin string    `json:"impersonatorLogin"`
+	UserID            int64     `json:"userId" xorm:"user_id"`
+	UserLogin         string    `json:"userLogin"`
+	Method            string    `json:"method,omitempty"`
+	Path              string    `json:"path,omitempty"`
+	Created           time.Time `json:"created"`
+}
+
+func (Event) TableName() string {
+	return "impersonation_event"
+}
+
+type SearchEventsQuery struct {
+	ImpersonatorID   int64
+	UserID           int64
+	ImpersonationUID string
+	Limit            int
+	Page             int
+}
+
+type store struct {
+	db db.DB
+}
+
+func (s *store) insert(ctx context.Context, event *Event) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(event)
+		return err
+	})
+}
+
+func (s *store) search(ctx context.Context, query *SearchEventsQuery) ([]*Event, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	offset := 0
+	if query.Page > 1 {
+		offset = (query.Page - 1) * limit
+	}
+
+	result := make([]*Event, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if query.ImpersonatorID != 0 {
+			sess.Where("impersonator_id = ?", query.ImpersonatorID)
+		}
+		if query.UserID != 0 {
+			sess.And("user_id = ?", query.UserID)
+		}
+		if query.ImpersonationUID != "" {
+			sess.And("impersonation_uid = ?", query.ImpersonationUID)
+		}
+		return sess.Desc("created", "id").Limit(limit, offset).Find(&result)
+	})
+	return result, err
+}
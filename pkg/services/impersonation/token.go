@@ -0,0 +1,40 @@
+package impersonation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/signedtoken"
+)
+
+var errInvalidToken = errors.New("invalid impersonation token")
+
+// claims are signed into the impersonation cookie. They are bound to the session of the
+// impersonator so that signing out also ends the impersonation.
+type claims struct {
+	// UID identifies the impersonation in its events
+	UID            string `json:"uid"`
+	ImpersonatorID int64  `json:"imp"`
+	UserID         int64  `json:"usr"`
+	Login          string `json:"lgn"`
+	SessionID      int64  `json:"sid"`
+	Expires        int64  `json:"exp"`
+}
+
+// tokenPurpose scopes the signing key of the impersonation cookies
+const tokenPurpose = "impersonation"
+
+func signToken(secretKey string, c claims) (string, error) {
+	return signedtoken.Sign(secretKey, tokenPurpose, c)
+}
+
+func verifyToken(secretKey string, token string, now time.Time) (*claims, error) {
+	var c claims
+	if err := signedtoken.Verify(secretKey, tokenPurpose, token, &c); err != nil {
+		return nil, errInvalidToken
+	}
+	if now.Unix() >= c.Expires {
+		return nil, errInvalidToken
+	}
+	return &c, nil
+}
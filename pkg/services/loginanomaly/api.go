@@ -0,0 +1,77 @@
+package loginanomaly
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(ac accesscontrol.AccessControl, routeRegister routing.RouteRegister) {
+	auth := accesscontrol.Middleware(ac)
+	routeRegister.Group("/api/org/login-anomaly", func(r routing.RouteRegister) {
+		r.Get("/settings", auth(accesscontrol.EvalPermission(accesscontrol.ActionOrgsRead)), routing.Wrap(s.handleGetOrgConfig))
+		r.Put("/settings", auth(accesscontrol.EvalPermission(accesscontrol.ActionOrgsWrite)), routing.Wrap(s.handleUpdateOrgConfig))
+	})
+}
+
+// swagger:route GET /org/login-anomaly/settings org getLoginAnomalyOrgConfig
+//
+// # Get the login anomaly detection settings of the current organization
+//
+// Produces:
+// - application/json
+//
+// Responses:
+//
+//	200: loginAnomalyOrgConfigResponse
+//	401: unauthorisedError
+//	403: forbiddenError
+//	500: internalServerError
+func (s *Service) handleGetOrgConfig(c *contextmodel.ReqContext) response.Response {
+	conf, err := s.GetOrgConfig(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get login anomaly settings", err)
+	}
+	return response.JSON(http.StatusOK, conf)
+}
+
+// swagger:route PUT /org/login-anomaly/settings org updateLoginAnomalyOrgConfig
+//
+// # Update the login anomaly detection settings of the current organization
+//
+// The settings take precedence over the default sensitivity of the configuration file.
+//
+// Responses:
+//
+//	200: okResponse
+//	400: badRequestError
+//	401: unauthorisedError
+//	403: forbiddenError
+//	500: internalServerError
+func (s *Service) handleUpdateOrgConfig(c *contextmodel.ReqContext) response.Response {
+	conf := OrgConfig{}
+	if err := web.Bind(c.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if err := s.SetOrgConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), &conf); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update login anomaly settings", err)
+	}
+	return response.Success("Login anomaly settings updated")
+}
+
+// swagger:parameters updateLoginAnomalyOrgConfig
+type UpdateLoginAnomalyOrgConfigParams struct {
+	// in:body
+	// required:true
+	Body OrgConfig `json:"body"`
+}
+
+// swagger:response loginAnomalyOrgConfigResponse
+type LoginAnomalyOrgConfigResponse struct {
+	// in:body
+	Body OrgConfig `json:"body"`
+}
@@ -0,0 +1,82 @@
+package loginanomaly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	orgConfigNamespace = "login_anomaly"
+	orgConfigKey       = "org_config"
+	// orgConfigCacheTTL bounds how long other instances keep using the previous settings after an update
+	orgConfigCacheTTL = time.Minute
+)
+
+var ErrInvalidOrgConfig = errutil.BadRequest("loginanomaly.invalid-org-config")
+
+type sensitivity struct {
+	// failedAttempts is the number of failed attempts before a successful sign-in that is reported, 0 to never report
+	failedAttempts int
+	newIP          bool
+}
+
+var sensitivities = map[string]sensitivity{
+	"off":    {},
+	"low":    {failedAttempts: 10},
+	"medium": {failedAttempts: 5, newIP: true},
+	"high":   {failedAttempts: 3, newIP: true},
+}
+
+// OrgConfig is the login anomaly detection configuration of an organization
+type OrgConfig struct {
+	// Sensitivity is one of off, low, medium or high
+	Sensitivity string `json:"sensitivity"`
+}
+
+func orgConfigCacheKey(orgID int64) string {
+	return fmt.Sprintf("login-anomaly-org-config-%d", orgID)
+}
+
+func (s *Service) GetOrgConfig(ctx context.Context, orgID int64) (*OrgConfig, error) {
+	if cached, ok := s.localCache.Get(orgConfigCacheKey(orgID)); ok {
+		return cached.(*OrgConfig), nil
+	}
+
+	value, ok, err := s.kvStore.Get(ctx, orgID, orgConfigNamespace, orgConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &OrgConfig{Sensitivity: s.cfg.DefaultSensitivity}
+	if ok {
+		if err := json.Unmarshal([]byte(value), conf); err != nil {
+			return nil, fmt.Errorf("failed to parse login anomaly settings of org %d: %w", orgID, err)
+		}
+	}
+
+	s.localCache.Set(orgConfigCacheKey(orgID), conf, orgConfigCacheTTL)
+	return conf, nil
+}
+
+// SetOrgConfig stores the login anomaly detection settings of the organization, they take precedence
+// over the default sensitivity of the configuration file.
+func (s *Service) SetOrgConfig(ctx context.Context, orgID int64, conf *OrgConfig) error {
+	if _, ok := sensitivities[conf.Sensitivity]; !ok {
+		return ErrInvalidOrgConfig.Errorf("invalid sensitivity %q", conf.Sensitivity)
+	}
+
+	value, err := json.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	if err := s.kvStore.Set(ctx, orgID, orgConfigNamespace, orgConfigKey, string(value)); err != nil {
+		return err
+	}
+
+	s.localCache.Delete(orgConfigCacheKey(orgID))
+	return nil
+}
@@ -0,0 +1,246 @@
+package loginanomaly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/usernotification"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// EventLoginAnomaly is the event sent to the webhook when an unusual sign-in is detected
+	EventLoginAnomaly = "login.anomaly"
+
+	ReasonNewIP          = "new_ip"
+	ReasonFailedAttempts = "failed_attempts"
+
+	// failedAttemptsWindow is how long failed sign-in attempts are counted for
+	failedAttemptsWindow = time.Hour
+)
+
+// Service detects unusual sign-ins and reports them with a LoginAnomalyDetected event, in-app
+// notifications and the configured webhook.
+type Service struct {
+	cfg               setting.LoginAnomalySettings
+	bus               bus.Bus
+	sessionService    auth.UserTokenService
+	remoteCache       remotecache.CacheStorage
+	kvStore           kvstore.KVStore
+	localCache        *localcache.CacheService
+	userNotifications usernotification.Service
+	orgService        org.Service
+	client            *http.Client
+	log               log.Logger
+}
+
+func ProvideService(cfg *setting.Cfg, bus bus.Bus, authnService authn.Service, sessionService auth.UserTokenService,
+	remoteCache remotecache.CacheStorage, kvStore kvstore.KVStore, userNotifications usernotification.Service,
+	orgService org.Service, ac accesscontrol.AccessControl, routeRegister routing.RouteRegister) *Service {
+	s := &Service{
+		cfg:               cfg.LoginAnomaly,
+		bus:               bus,
+		sessionService:    sessionService,
+		remoteCache:       remoteCache,
+		kvStore:           kvStore,
+		localCache:        localcache.New(orgConfigCacheTTL, 2*orgConfigCacheTTL),
+		userNotifications: userNotifications,
+		orgService:        orgService,
+		client:            &http.Client{Timeout: cfg.LoginAnomaly.WebhookTimeout},
+		log:               log.New("login.anomaly"),
+	}
+
+	if !s.cfg.Enabled {
+		return s
+	}
+
+	authnService.RegisterPostLoginHook(s.loginHook, 150)
+	if s.cfg.NotifyUser || s.cfg.NotifyOrgAdmins {
+		bus.AddEventListener(s.handleLoginAnomaly)
+	}
+	s.registerAPIEndpoints(ac, routeRegister)
+	return s
+}
+
+// WebhookPayload is the body of the request sent to the webhook
+type WebhookPayload struct {
+	Event string `json:"event"`
+	*events.LoginAnomalyDetected
+}
+
+func (s *Service) loginHook(ctx context.Context, identity *authn.Identity, r *authn.Request, err error) {
+	username := r.GetMeta(authn.MetaKeyUsername)
+	if err != nil {
+		if username != "" {
+			s.addFailedAttempt(ctx, username)
+		}
+		return
+	}
+
+	if identity == nil || identity.SessionToken == nil {
+		return
+	}
+	if username == "" {
+		username = identity.Login
+	}
+
+	evt, err := s.detect(ctx, identity, username)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to check sign-in for anomalies", "userId", identity.SessionToken.UserId, "error", err)
+		return
+	}
+	if evt == nil {
+		return
+	}
+
+	s.log.FromContext(ctx).Warn("Detected unusual sign-in", "userId", evt.UserID, "login", evt.Login, "orgId", evt.OrgID,
+		"ip", evt.IP, "reasons", evt.Reasons, "failedAttempts", evt.FailedAttempts)
+	if err := s.bus.Publish(ctx, evt); err != nil {
+		s.log.FromContext(ctx).Error("Failed to publish login anomaly event", "userId", evt.UserID, "error", err)
+	}
+	if s.cfg.WebhookURL != "" {
+		// the sign-in must not wait for the webhook
+		go func() {
+			if err := s.callWebhook(context.Background(), &WebhookPayload{Event: EventLoginAnomaly, LoginAnomalyDetected: evt}); err != nil {
+				s.log.Error("Failed to call login anomaly webhook", "userId", evt.UserID, "error", err)
+			}
+		}()
+	}
+}
+
+// detect returns the event to publish for the sign-in, or nil when the sign-in isn't unusual
+// for the sensitivity of the organization
+func (s *Service) detect(ctx context.Context, identity *authn.Identity, username string) (*events.LoginAnomalyDetected, error) {
+	token := identity.SessionToken
+	failedAttempts := s.resetFailedAttempts(ctx, username)
+
+	conf, err := s.GetOrgConfig(ctx, identity.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	sens := sensitivities[conf.Sensitivity]
+
+	var reasons []string
+	if sens.failedAttempts > 0 && failedAttempts >= sens.failedAttempts {
+		reasons = append(reasons, ReasonFailedAttempts)
+	}
+	if sens.newIP && token.ClientIp != "" {
+		isNew, err := s.isNewIP(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if isNew {
+			reasons = append(reasons, ReasonNewIP)
+		}
+	}
+
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+	return &events.LoginAnomalyDetected{
+		Timestamp:      time.Now(),
+		OrgID:          identity.OrgID,
+		UserID:         token.UserId,
+		Login:          identity.Login,
+		IP:             token.ClientIp,
+		UserAgent:      token.UserAgent,
+		Reasons:        reasons,
+		FailedAttempts: failedAttempts,
+	}, nil
+}
+
+// isNewIP reports whether none of the other active sessions of the user were created from the IP
+// address of the new session. The first session of a user is never reported.
+func (s *Service) isNewIP(ctx context.Context, token *auth.UserToken) (bool, error) {
+	tokens, err := s.sessionService.GetUserTokens(ctx, token.UserId)
+	if err != nil {
+		return false, err
+	}
+
+	others := 0
+	for _, t := range tokens {
+		if t.Id == token.Id {
+			continue
+		}
+		if t.ClientIp == token.ClientIp {
+			return false, nil
+		}
+		others++
+	}
+	return others > 0, nil
+}
+
+func failedAttemptsKey(username string) string {
+	return fmt.Sprintf("login-anomaly-failures-%s", strings.ToLower(username))
+}
+
+func (s *Service) failedAttempts(ctx context.Context, username string) int {
+	value, err := s.remoteCache.Get(ctx, failedAttemptsKey(username))
+	if err != nil {
+		return 0
+	}
+	count, _ := strconv.Atoi(string(value))
+	return count
+}
+
+func (s *Service) addFailedAttempt(ctx context.Context, username string) {
+	// concurrent attempts may be counted once, which is fine for detecting anomalies
+	count := s.failedAttempts(ctx, username) + 1
+	if err := s.remoteCache.Set(ctx, failedAttemptsKey(username), []byte(strconv.Itoa(count)), failedAttemptsWindow); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to count failed sign-in attempt", "error", err)
+	}
+}
+
+// resetFailedAttempts returns the number of failed attempts before the successful sign-in and
+// starts counting from zero again
+func (s *Service) resetFailedAttempts(ctx context.Context, username string) int {
+	count := s.failedAttempts(ctx, username)
+	if count > 0 {
+		_ = s.remoteCache.Delete(ctx, failedAttemptsKey(username))
+	}
+	return count
+}
+
+func (s *Service) callWebhook(ctx context.Context, payload *WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Grafana")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
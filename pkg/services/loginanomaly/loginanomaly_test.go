@@ -0,0 +1,195 @@
+package loginanomaly
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/usernotification"
+	"github.com/grafana/grafana/pkg/services/usernotification/usernotificationtest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService_LoginHook(t *testing.T) {
+	type testCase struct {
+		desc            string
+		sensitivity     string
+		failures        int
+		otherSessionIPs []string
+		expectedReasons []string
+	}
+
+	tests := []testCase{
+		{
+			desc:            "should not report the first session of a user",
+			sensitivity:     "medium",
+			expectedReasons: nil,
+		},
+		{
+			desc:            "should not report a known IP address",
+			sensitivity:     "medium",
+			otherSessionIPs: []string{"10.0.0.2", "10.0.0.1"},
+			expectedReasons: nil,
+		},
+		{
+			desc:            "should report a new IP address",
+			sensitivity:     "medium",
+			otherSessionIPs: []string{"10.0.0.2"},
+			expectedReasons: []string{ReasonNewIP},
+		},
+		{
+			desc:            "should not report a new IP address with low sensitivity",
+			sensitivity:     "low",
+			otherSessionIPs: []string{"10.0.0.2"},
+			failures:        5,
+			expectedReasons: nil,
+		},
+		{
+			desc:            "should report a sign-in after many failed attempts",
+			sensitivity:     "high",
+			failures:        3,
+			expectedReasons: []string{ReasonFailedAttempts},
+		},
+		{
+			desc:            "should not report anything when off",
+			sensitivity:     "off",
+			failures:        20,
+			otherSessionIPs: []string{"10.0.0.2"},
+			expectedReasons: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s, b := setupTestService(t, tt.sensitivity)
+			sessions := []*auth.UserToken{{Id: 1, UserId: 2, ClientIp: "10.0.0.1"}}
+			for i, ip := range tt.otherSessionIPs {
+				sessions = append(sessions, &auth.UserToken{Id: int64(i + 2), UserId: 2, ClientIp: ip})
+			}
+			s.sessionService.(*authtest.FakeUserAuthTokenService).GetUserTokensProvider = func(ctx context.Context, userID int64) ([]*auth.UserToken, error) {
+				return sessions, nil
+			}
+
+			var published []*events.LoginAnomalyDetected
+			b.AddEventListener(func(ctx context.Context, evt *events.LoginAnomalyDetected) error {
+				published = append(published, evt)
+				return nil
+			})
+
+			r := &authn.Request{}
+			r.SetMeta(authn.MetaKeyUsername, "Editor")
+			for i := 0; i < tt.failures; i++ {
+				s.loginHook(context.Background(), nil, r, errors.New("invalid password"))
+			}
+
+			identity := &authn.Identity{OrgID: 1, Login: "editor", SessionToken: sessions[0]}
+			s.loginHook(context.Background(), identity, r, nil)
+
+			if tt.expectedReasons == nil {
+				assert.Empty(t, published)
+			} else {
+				require.Len(t, published, 1)
+				assert.Equal(t, tt.expectedReasons, published[0].Reasons)
+				assert.Equal(t, int64(2), published[0].UserID)
+				assert.Equal(t, "10.0.0.1", published[0].IP)
+				assert.Equal(t, tt.failures, published[0].FailedAttempts)
+			}
+
+			// the failed attempts are reset by the successful sign-in
+			assert.Zero(t, s.failedAttempts(context.Background(), "editor"))
+		})
+	}
+}
+
+func TestService_OrgConfig(t *testing.T) {
+	s, _ := setupTestService(t, "medium")
+	ctx := context.Background()
+
+	conf, err := s.GetOrgConfig(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "medium", conf.Sensitivity)
+
+	require.NoError(t, s.SetOrgConfig(ctx, 1, &OrgConfig{Sensitivity: "high"}))
+	conf, err = s.GetOrgConfig(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "high", conf.Sensitivity)
+
+	conf, err = s.GetOrgConfig(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "medium", conf.Sensitivity)
+
+	err = s.SetOrgConfig(ctx, 1, &OrgConfig{Sensitivity: "paranoid"})
+	assert.ErrorIs(t, err, ErrInvalidOrgConfig)
+}
+
+func TestService_HandleLoginAnomaly(t *testing.T) {
+	evt := &events.LoginAnomalyDetected{OrgID: 1, UserID: 2, Login: "editor", IP: "10.0.0.1", Reasons: []string{ReasonNewIP}}
+	orgUsers := &org.SearchOrgUsersQueryResult{OrgUsers: []*org.OrgUserDTO{
+		{UserID: 1, Role: string(org.RoleAdmin)},
+		{UserID: 2, Role: string(org.RoleAdmin)},
+		{UserID: 3, Role: string(org.RoleViewer)},
+	}}
+
+	t.Run("should notify the user", func(t *testing.T) {
+		s, _ := setupTestService(t, "medium")
+		notifications := s.userNotifications.(*usernotificationtest.FakeService)
+
+		require.NoError(t, s.handleLoginAnomaly(context.Background(), evt))
+		require.Len(t, notifications.Created, 1)
+		assert.Equal(t, &usernotification.CreateNotificationCommand{
+			OrgID:  1,
+			UserID: 2,
+			Kind:   KindLoginAnomaly,
+			Title:  "Unusual sign-in to your account",
+			Text:   "New sign-in from a new IP address (10.0.0.1). If it wasn't you, change your password and revoke the session.",
+			URL:    "/profile",
+		}, notifications.Created[0])
+	})
+
+	t.Run("should notify the other admins of the org when enabled", func(t *testing.T) {
+		s, _ := setupTestService(t, "medium")
+		s.cfg.NotifyUser = false
+		s.cfg.NotifyOrgAdmins = true
+		s.orgService = &orgtest.FakeOrgService{ExpectedSearchOrgUsersResult: orgUsers}
+		notifications := s.userNotifications.(*usernotificationtest.FakeService)
+
+		require.NoError(t, s.handleLoginAnomaly(context.Background(), evt))
+		require.Len(t, notifications.Created, 1)
+		assert.Equal(t, int64(1), notifications.Created[0].UserID)
+		assert.Equal(t, "Unusual sign-in of editor", notifications.Created[0].Title)
+		assert.Equal(t, "editor signed in from a new IP address (10.0.0.1).", notifications.Created[0].Text)
+	})
+}
+
+func setupTestService(t *testing.T, sensitivity string) (*Service, bus.Bus) {
+	t.Helper()
+
+	b := bus.ProvideBus(tracing.InitializeTracerForTest())
+	return &Service{
+		cfg:               setting.LoginAnomalySettings{Enabled: true, DefaultSensitivity: sensitivity, NotifyUser: true},
+		bus:               b,
+		sessionService:    authtest.NewFakeUserAuthTokenService(),
+		remoteCache:       remotecache.NewFakeCacheStorage(),
+		kvStore:           kvstore.NewFakeKVStore(),
+		localCache:        localcache.New(time.Minute, time.Minute),
+		log:               log.NewNopLogger(),
+		userNotifications: &usernotificationtest.FakeService{},
+		orgService:        &orgtest.FakeOrgService{},
+	}, b
+}
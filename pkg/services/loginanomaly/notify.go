@@ -0,0 +1,80 @@
+package loginanomaly
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/usernotification"
+)
+
+// KindLoginAnomaly is the kind of the in-app notifications sent when an unusual sign-in is detected
+const KindLoginAnomaly = "login.anomaly"
+
+var reasonDescriptions = map[string]string{
+	ReasonNewIP:          "from a new IP address",
+	ReasonFailedAttempts: "after several failed attempts",
+}
+
+// handleLoginAnomaly notifies the user in Grafana, and the admins of the organization if configured,
+// so that an unusual sign-in can be noticed without a webhook
+func (s *Service) handleLoginAnomaly(ctx context.Context, evt *events.LoginAnomalyDetected) error {
+	description := describeReasons(evt)
+
+	if s.cfg.NotifyUser {
+		if err := s.userNotifications.Create(ctx, &usernotification.CreateNotificationCommand{
+			OrgID:  evt.OrgID,
+			UserID: evt.UserID,
+			Kind:   KindLoginAnomaly,
+			Title:  "Unusual sign-in to your account",
+			Text:   fmt.Sprintf("New sign-in %s. If it wasn't you, change your password and revoke the session.", description),
+			URL:    "/profile",
+		}); err != nil {
+			s.log.FromContext(ctx).Error("Failed to create login anomaly notification", "userId", evt.UserID, "error", err)
+		}
+	}
+
+	if !s.cfg.NotifyOrgAdmins {
+		return nil
+	}
+
+	admins, err := s.orgService.SearchOrgUsers(ctx, &org.SearchOrgUsersQuery{
+		OrgID:                    evt.OrgID,
+		DontEnforceAccessControl: true,
+	})
+	if err != nil {
+		s.log.FromContext(ctx).Error("Failed to get the admins to notify of the login anomaly", "orgId", evt.OrgID, "error", err)
+		return nil
+	}
+	for _, u := range admins.OrgUsers {
+		if u.Role != string(org.RoleAdmin) || u.UserID == evt.UserID {
+			continue
+		}
+		if err := s.userNotifications.Create(ctx, &usernotification.CreateNotificationCommand{
+			OrgID:  evt.OrgID,
+			UserID: u.UserID,
+			Kind:   KindLoginAnomaly,
+			Title:  fmt.Sprintf("Unusual sign-in of %s", evt.Login),
+			Text:   fmt.Sprintf("%s signed in %s.", evt.Login, description),
+			URL:    "/org/users",
+		}); err != nil {
+			s.log.FromContext(ctx).Error("Failed to create login anomaly notification", "userId", u.UserID, "error", err)
+		}
+	}
+	return nil
+}
+
+func describeReasons(evt *events.LoginAnomalyDetected) string {
+	descriptions := make([]string, 0, len(evt.Reasons)+1)
+	for _, reason := range evt.Reasons {
+		if d, ok := reasonDescriptions[reason]; ok {
+			descriptions = append(descriptions, d)
+		}
+	}
+	if evt.IP != "" {
+		descriptions = append(descriptions, "("+evt.IP+")")
+	}
+	return strings.Join(descriptions, " ")
+}
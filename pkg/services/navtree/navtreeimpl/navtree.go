@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models/roletype"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/anonymous"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -39,6 +40,7 @@ type ServiceImpl struct {
 	kvStore              kvstore.KVStore
 	apiKeyService        apikey.Service
 	license              licensing.Licensing
+	anonService          anonymous.Service
 
 	// Navigation
 	navigationAppConfig     map[string]NavigationAppConfig
@@ -52,7 +54,7 @@ type NavigationAppConfig struct {
 	Icon       string
 }
 
-func ProvideService(cfg *setting.Cfg, accessControl ac.AccessControl, pluginStore pluginstore.Store, pluginSettings pluginsettings.Service, starService star.Service, features featuremgmt.FeatureToggles, dashboardService dashboards.DashboardService, accesscontrolService ac.Service, kvStore kvstore.KVStore, apiKeyService apikey.Service, license licensing.Licensing) navtree.Service {
+func ProvideService(cfg *setting.Cfg, accessControl ac.AccessControl, pluginStore pluginstore.Store, pluginSettings pluginsettings.Service, starService star.Service, features featuremgmt.FeatureToggles, dashboardService dashboards.DashboardService, accesscontrolService ac.Service, kvStore kvstore.KVStore, apiKeyService apikey.Service, license licensing.Licensing, anonService anonymous.Service) navtree.Service {
 	service := &ServiceImpl{
 		cfg:                  cfg,
 		log:                  log.New("navtree service"),
@@ -66,6 +68,7 @@ func ProvideService(cfg *setting.Cfg, accessControl ac.AccessControl, pluginStor
 		kvStore:              kvStore,
 		apiKeyService:        apiKeyService,
 		license:              license,
+		anonService:          anonService,
 	}
 
 	service.readNavigationSettings()
@@ -172,9 +175,22 @@ func (s *ServiceImpl) GetNavTree(c *contextmodel.ReqContext, prefs *pref.Prefere
 	return treeRoot, nil
 }
 
+// anonymousEnabled reports whether anonymous access is enabled for the organization of the request
+func (s *ServiceImpl) anonymousEnabled(c *contextmodel.ReqContext) bool {
+	if s.anonService == nil || c.SignedInUser == nil {
+		return s.cfg.AnonymousEnabled
+	}
+	conf, err := s.anonService.GetOrgConfig(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		s.log.Warn("Failed to get anonymous access settings", "error", err)
+		return false
+	}
+	return conf.Enabled
+}
+
 func (s *ServiceImpl) getHomeNode(c *contextmodel.ReqContext, prefs *pref.Preference) *navtree.NavLink {
 	homeUrl := s.cfg.AppSubURL + "/"
-	if !c.IsSignedIn && !s.cfg.AnonymousEnabled {
+	if !c.IsSignedIn && !s.anonymousEnabled(c) {
 		homeUrl = s.cfg.AppSubURL + "/login"
 	} else {
 		homePage := s.cfg.HomePage
@@ -114,6 +114,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			xactManager:        api.TransactionManager,
 			log:                logger,
 			cfg:                &api.Cfg.UnifiedAlerting,
+			folderRulesLimit:   api.Cfg.Quota.FolderAlertRuleLimit(),
 			authz:              ruleAuthzService,
 			amConfigStore:      api.AlertingStore,
 			amRefresher:        api.MultiOrgAlertmanager,
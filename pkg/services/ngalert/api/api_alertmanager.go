@@ -5,12 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-openapi/strfmt"
 	alertingNotify "github.com/grafana/alerting/notify"
+	amConfig "github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -312,6 +317,56 @@ func (srv AlertmanagerSrv) RouteGetReceivers(c *contextmodel.ReqContext) respons
 	return response.JSON(http.StatusOK, rcvs)
 }
 
+func (srv AlertmanagerSrv) RouteGetReceiversUsage(c *contextmodel.ReqContext) response.Response {
+	am, errResp := srv.AlertmanagerFor(c.SignedInUser.GetOrgID())
+	if errResp != nil {
+		return errResp
+	}
+
+	rcvs, err := am.GetReceivers(c.Req.Context())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to retrieve receivers")
+	}
+	cfg, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID(), false)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to retrieve notification policies")
+	}
+
+	policies := map[string]int{}
+	countPolicies(cfg.AlertmanagerConfig.Route, policies)
+	return response.JSON(http.StatusOK, newContactPointsUsage(rcvs, policies))
+}
+
+// countPolicies counts the notification policies of the routing tree by receiver
+func countPolicies(route *apimodels.Route, policies map[string]int) {
+	if route == nil {
+		return
+	}
+	if route.Receiver != "" {
+		policies[route.Receiver]++
+	}
+	for _, child := range route.Routes {
+		countPolicies(child, policies)
+	}
+}
+
+func newContactPointsUsage(rcvs []apimodels.Receiver, policies map[string]int) apimodels.ContactPointsUsage {
+	usage := make(apimodels.ContactPointsUsage, 0, len(rcvs))
+	for _, rcv := range rcvs {
+		u := apimodels.ContactPointUsage{Name: *rcv.Name, Policies: policies[*rcv.Name], Integrations: rcv.Integrations}
+		for _, integration := range rcv.Integrations {
+			attempt := integration.LastNotifyAttempt
+			if time.Time(attempt).IsZero() || (u.LastNotifyAttempt != nil && !time.Time(attempt).After(time.Time(*u.LastNotifyAttempt))) {
+				continue
+			}
+			u.LastNotifyAttempt = &attempt
+			u.LastNotifyAttemptError = integration.LastNotifyAttemptError
+		}
+		usage = append(usage, u)
+	}
+	return usage
+}
+
 func (srv AlertmanagerSrv) RoutePostTestReceivers(c *contextmodel.ReqContext, body apimodels.TestReceiversConfigBodyParams) response.Response {
 	if err := srv.crypto.ProcessSecureSettings(c.Req.Context(), c.SignedInUser.GetOrgID(), body.Receivers); err != nil {
 		var unknownReceiverError UnknownReceiverError
@@ -361,6 +416,89 @@ func (srv AlertmanagerSrv) RoutePostTestTemplates(c *contextmodel.ReqContext, bo
 	return response.JSON(http.StatusOK, newTestTemplateResult(res))
 }
 
+func (srv AlertmanagerSrv) RoutePostTestRoutes(c *contextmodel.ReqContext, body apimodels.TestRoutesConfigBodyParams) response.Response {
+	route, muteTimes, timeIntervals := body.Route, body.MuteTimeIntervals, body.TimeIntervals
+	if route == nil {
+		canSeeAutogen := c.SignedInUser.HasRole(org.RoleAdmin)
+		cfg, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID(), canSeeAutogen)
+		if err != nil {
+			if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return ErrResp(http.StatusInternalServerError, err, err.Error())
+		}
+		route = cfg.AlertmanagerConfig.Route
+		muteTimes = cfg.AlertmanagerConfig.MuteTimeIntervals
+		timeIntervals = cfg.AlertmanagerConfig.TimeIntervals
+	}
+
+	at := time.Now()
+	if body.Time != nil {
+		at = *body.Time
+	}
+	result, err := testRoutes(route, muteTimes, timeIntervals, body.Labels, at)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid notification policies")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// testRoutes returns the notification policies that a notification with the labels is routed to,
+// and whether their mute timings are active at the given time.
+func testRoutes(route *apimodels.Route, muteTimes []amConfig.MuteTimeInterval, timeIntervals []amConfig.TimeInterval, lset model.LabelSet, at time.Time) (apimodels.TestRoutesResult, error) {
+	if route == nil {
+		return apimodels.TestRoutesResult{}, errors.New("no notification policies to test")
+	}
+	// Validate also sets the group by labels of the policies
+	if err := route.Validate(); err != nil {
+		return apimodels.TestRoutesResult{}, err
+	}
+
+	intervals := make(map[string][]timeinterval.TimeInterval, len(muteTimes)+len(timeIntervals))
+	for _, mt := range muteTimes {
+		intervals[mt.Name] = mt.TimeIntervals
+	}
+	for _, ti := range timeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+	names := make(map[string]struct{}, len(intervals))
+	for name := range intervals {
+		names[name] = struct{}{}
+	}
+	if err := route.ValidateMuteTimes(names); err != nil {
+		return apimodels.TestRoutesResult{}, err
+	}
+
+	result := apimodels.TestRoutesResult{Labels: lset, Time: at, Routes: []apimodels.TestRouteResult{}}
+	for _, matched := range dispatch.NewRoute(route.AsAMRoute(), nil).Match(lset) {
+		r := apimodels.TestRouteResult{
+			Receiver:       matched.RouteOpts.Receiver,
+			ObjectMatchers: apimodels.ObjectMatchers(matched.Matchers),
+		}
+		if matched.RouteOpts.GroupByAll {
+			r.GroupBy = []string{"..."}
+		} else {
+			for label := range matched.RouteOpts.GroupBy {
+				r.GroupBy = append(r.GroupBy, string(label))
+			}
+			sort.Strings(r.GroupBy)
+		}
+		for _, name := range matched.RouteOpts.MuteTimeIntervals {
+			active := false
+			for _, ti := range intervals[name] {
+				if ti.ContainsTime(at.UTC()) {
+					active = true
+					break
+				}
+			}
+			r.MuteTimeIntervals = append(r.MuteTimeIntervals, apimodels.TestRouteMuteTimeResult{Name: name, Active: active})
+			r.Muted = r.Muted || active
+		}
+		result.Routes = append(result.Routes, r)
+	}
+	return result, nil
+}
+
 // contextWithTimeoutFromRequest returns a context with a deadline set from the
 // Request-Timeout header in the HTTP request. If the header is absent then the
 // context will use the default timeout. The timeout in the Request-Timeout
@@ -16,6 +16,7 @@ import (
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/api/response"
@@ -577,6 +578,126 @@ func TestRoutePostTestTemplates(t *testing.T) {
 	})
 }
 
+func TestRouteGetReceiversUsage(t *testing.T) {
+	sut := createSut(t)
+
+	t.Run("assert 409 when alertmanager not ready", func(t *testing.T) {
+		response := sut.RouteGetReceiversUsage(createRequestCtxInOrg(3))
+		require.Equal(t, 409, response.Status())
+	})
+
+	t.Run("assert the contact points are returned with their policies", func(t *testing.T) {
+		response := sut.RouteGetReceiversUsage(createRequestCtxInOrg(1))
+		require.Equal(t, 200, response.Status())
+
+		var usage apimodels.ContactPointsUsage
+		require.NoError(t, json.Unmarshal(response.Body(), &usage))
+		require.Len(t, usage, 1)
+		require.Equal(t, "grafana-default-email", usage[0].Name)
+		require.Equal(t, 1, usage[0].Policies)
+		require.Nil(t, usage[0].LastNotifyAttempt)
+	})
+
+	t.Run("assert the last delivery attempt of the integrations is returned", func(t *testing.T) {
+		name, email, slack := "team", "email", "slack"
+		older := strfmt.DateTime(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+		newer := strfmt.DateTime(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))
+		usage := newContactPointsUsage([]apimodels.Receiver{
+			{Name: &name, Integrations: []*apimodels.Integration{
+				{Name: &email, LastNotifyAttempt: older},
+				{Name: &slack, LastNotifyAttempt: newer, LastNotifyAttemptError: "failed"},
+			}},
+		}, map[string]int{})
+
+		require.Len(t, usage, 1)
+		require.Equal(t, 0, usage[0].Policies)
+		require.Equal(t, newer, *usage[0].LastNotifyAttempt)
+		require.Equal(t, "failed", usage[0].LastNotifyAttemptError)
+	})
+}
+
+func TestRoutePostTestRoutes(t *testing.T) {
+	sut := createSut(t)
+
+	t.Run("assert 404 when no alertmanager configuration found", func(t *testing.T) {
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(10), apimodels.TestRoutesConfigBodyParams{})
+		require.Equal(t, 404, response.Status())
+	})
+
+	t.Run("assert the saved notification policies are tested by default", func(t *testing.T) {
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(1), apimodels.TestRoutesConfigBodyParams{
+			Labels: model.LabelSet{"alertname": "test"},
+		})
+		require.Equal(t, 200, response.Status())
+
+		var result apimodels.TestRoutesResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Routes, 1)
+		require.Equal(t, "grafana-default-email", result.Routes[0].Receiver)
+	})
+
+	body := func(t *testing.T, labels string) apimodels.TestRoutesConfigBodyParams {
+		var body apimodels.TestRoutesConfigBodyParams
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"labels": `+labels+`,
+			"time": "2024-03-02T10:00:00Z",
+			"route": {
+				"receiver": "default",
+				"group_by": ["alertname"],
+				"routes": [{
+					"receiver": "team-a",
+					"object_matchers": [["team", "=", "a"]],
+					"mute_time_intervals": ["weekends"],
+					"continue": true
+				}, {
+					"receiver": "team-a-pager",
+					"object_matchers": [["team", "=", "a"]],
+					"mute_time_intervals": ["nights"]
+				}]
+			},
+			"mute_time_intervals": [{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]}],
+			"time_intervals": [{"name": "nights", "time_intervals": [{"times": [{"start_time": "00:00", "end_time": "06:00"}]}]}]
+		}`), &body))
+		return body
+	}
+
+	t.Run("assert the matching policies and their mute timings are returned", func(t *testing.T) {
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(1), body(t, `{"team": "a"}`))
+		require.Equal(t, 200, response.Status())
+
+		var result apimodels.TestRoutesResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Routes, 2)
+
+		require.Equal(t, "team-a", result.Routes[0].Receiver)
+		require.Equal(t, []string{"alertname"}, result.Routes[0].GroupBy)
+		require.Equal(t, []apimodels.TestRouteMuteTimeResult{{Name: "weekends", Active: true}}, result.Routes[0].MuteTimeIntervals)
+		require.True(t, result.Routes[0].Muted)
+
+		require.Equal(t, "team-a-pager", result.Routes[1].Receiver)
+		require.Equal(t, []apimodels.TestRouteMuteTimeResult{{Name: "nights", Active: false}}, result.Routes[1].MuteTimeIntervals)
+		require.False(t, result.Routes[1].Muted)
+	})
+
+	t.Run("assert the default policy is returned when no other matches", func(t *testing.T) {
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(1), body(t, `{"team": "b"}`))
+		require.Equal(t, 200, response.Status())
+
+		var result apimodels.TestRoutesResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Routes, 1)
+		require.Equal(t, "default", result.Routes[0].Receiver)
+		require.Empty(t, result.Routes[0].MuteTimeIntervals)
+	})
+
+	t.Run("assert 400 when a mute timing does not exist", func(t *testing.T) {
+		b := body(t, `{"team": "a"}`)
+		b.TimeIntervals = nil
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(1), b)
+		require.Equal(t, 400, response.Status())
+	})
+}
+
 func TestSilenceCreate(t *testing.T) {
 	makeSilence := func(comment string, createdBy string,
 		startsAt, endsAt strfmt.DateTime, matchers amv2.Matchers) amv2.Silence {
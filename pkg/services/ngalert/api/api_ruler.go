@@ -51,6 +51,7 @@ type RulerSrv struct {
 	QuotaService       quota.Service
 	log                log.Logger
 	cfg                *setting.UnifiedAlertingSettings
+	folderRulesLimit   int64
 	conditionValidator ConditionValidator
 	authz              RuleAccessControlService
 
@@ -413,6 +414,13 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 			if limitReached {
 				return ngmodels.ErrQuotaReached
 			}
+			namespaces := make([]string, 0, len(finalChanges.New))
+			for _, rule := range finalChanges.New {
+				namespaces = append(namespaces, rule.NamespaceUID)
+			}
+			if err := store.CheckFolderRulesLimit(tranCtx, srv.store, c.SignedInUser.GetOrgID(), srv.folderRulesLimit, namespaces...); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -624,10 +624,11 @@ func createService(store *fakes.RuleStore) *RulerSrv {
 		cfg: &setting.UnifiedAlertingSettings{
 			BaseInterval: 10 * time.Second,
 		},
-		authz:          accesscontrol.NewRuleService(acimpl.ProvideAccessControl(setting.NewCfg())),
-		amConfigStore:  &fakeAMRefresher{},
-		amRefresher:    &fakeAMRefresher{},
-		featureManager: &featuremgmt.FeatureManager{},
+		folderRulesLimit: -1,
+		authz:            accesscontrol.NewRuleService(acimpl.ProvideAccessControl(setting.NewCfg())),
+		amConfigStore:    &fakeAMRefresher{},
+		amRefresher:      &fakeAMRefresher{},
+		featureManager:   &featuremgmt.FeatureManager{},
 	}
 }
 
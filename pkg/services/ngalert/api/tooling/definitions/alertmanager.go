@@ -151,6 +151,13 @@ import (
 //     Responses:
 //       200: receiversResponse
 
+// swagger:route GET /alertmanager/grafana/config/api/v1/receivers/usage alertmanager RouteGetGrafanaReceiversUsage
+//
+// Get the contact points with the number of notification policies that route to them and the result of their last delivery attempt.
+//
+//     Responses:
+//       200: ContactPointsUsage
+
 // swagger:route POST /alertmanager/grafana/config/api/v1/receivers/test alertmanager RoutePostTestGrafanaReceivers
 //
 // Test Grafana managed receivers without saving them.
@@ -165,6 +172,20 @@ import (
 //       408: Failure
 //       409: AlertManagerNotReady
 
+// swagger:route POST /alertmanager/grafana/config/api/v1/routes/test alertmanager RoutePostTestGrafanaRoutes
+//
+// Test which contact points a notification with the given labels is routed to, and the mute timings in effect.
+// The saved notification policies are tested unless a routing tree is provided.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: TestRoutesResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+
 // swagger:route POST /alertmanager/grafana/config/api/v1/templates/test alertmanager RoutePostTestGrafanaTemplates
 //
 // Test Grafana managed templates without saving them.
@@ -278,6 +299,47 @@ type TestReceiversConfigAlertParams struct {
 	Labels      model.LabelSet `yaml:"labels,omitempty" json:"labels,omitempty"`
 }
 
+// swagger:parameters RoutePostTestGrafanaRoutes
+type TestRoutesConfigParams struct {
+	// in:body
+	Body TestRoutesConfigBodyParams
+}
+
+type TestRoutesConfigBodyParams struct {
+	// Labels of the notification to route
+	Labels model.LabelSet `yaml:"labels,omitempty" json:"labels,omitempty"`
+	// Time at which the mute timings are evaluated, defaults to the current time
+	Time *time.Time `yaml:"time,omitempty" json:"time,omitempty"`
+	// Route is a routing tree to test instead of the saved notification policies,
+	// the mute timings it refers to are defined by MuteTimeIntervals and TimeIntervals
+	Route             *Route                    `yaml:"route,omitempty" json:"route,omitempty"`
+	MuteTimeIntervals []config.MuteTimeInterval `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	TimeIntervals     []config.TimeInterval     `yaml:"time_intervals,omitempty" json:"time_intervals,omitempty"`
+}
+
+// swagger:model
+type TestRoutesResult struct {
+	Labels model.LabelSet    `json:"labels"`
+	Time   time.Time         `json:"time"`
+	Routes []TestRouteResult `json:"routes"`
+}
+
+// TestRouteResult is a notification policy that matches the labels
+type TestRouteResult struct {
+	Receiver string `json:"receiver"`
+	// ObjectMatchers are the matchers of the policy, not including the ones of its parents
+	ObjectMatchers    ObjectMatchers            `json:"object_matchers,omitempty"`
+	GroupBy           []string                  `json:"group_by,omitempty"`
+	MuteTimeIntervals []TestRouteMuteTimeResult `json:"mute_time_intervals,omitempty"`
+	// Muted is true when one of the mute timings of the policy is active
+	Muted bool `json:"muted"`
+}
+
+type TestRouteMuteTimeResult struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
 // swagger:model
 type TestReceiversResult struct {
 	Alert      TestReceiversConfigAlertParams `json:"alert"`
@@ -479,6 +541,21 @@ type ReceiversResponse struct {
 // swagger:model integration
 type Integration = amv2.Integration
 
+// swagger:model
+type ContactPointsUsage []ContactPointUsage
+
+type ContactPointUsage struct {
+	Name string `json:"name"`
+	// Policies is the number of notification policies that route to the contact point, not including the
+	// policies generated for the alert rules that set a contact point
+	Policies int `json:"policies"`
+	// LastNotifyAttempt is the time of the most recent delivery attempt of the integrations of the contact point
+	LastNotifyAttempt *strfmt.DateTime `json:"lastNotifyAttempt,omitempty"`
+	// LastNotifyAttemptError is the error of the most recent delivery attempt, empty when it succeeded
+	LastNotifyAttemptError string         `json:"lastNotifyAttemptError,omitempty"`
+	Integrations           []*Integration `json:"integrations"`
+}
+
 // swagger:parameters RouteGetAMAlerts RouteGetAMAlertGroups RouteGetGrafanaAMAlerts RouteGetGrafanaAMAlertGroups
 type AlertsParams struct {
 
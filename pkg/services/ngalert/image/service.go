@@ -20,10 +20,6 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-const (
-	screenshotCacheTTL = time.Minute
-)
-
 // DeleteExpiredService is a service to delete expired images.
 type DeleteExpiredService struct {
 	store store.ImageAdminStore
@@ -91,7 +87,7 @@ func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds da
 
 	// If screenshots are enabled
 	if cfg.UnifiedAlerting.Screenshots.Capture {
-		cache = NewInmemCacheService(screenshotCacheTTL, r)
+		cache = NewInmemCacheService(cfg.UnifiedAlerting.Screenshots.CacheTTL, r)
 		limiter = screenshot.NewTokenRateLimiter(cfg.UnifiedAlerting.Screenshots.MaxConcurrentScreenshots)
 		screenshots = screenshot.NewHeadlessScreenshotService(cfg, ds, rs, r)
 		screenshotTimeout = cfg.UnifiedAlerting.Screenshots.CaptureTimeout
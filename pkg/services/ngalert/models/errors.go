@@ -8,8 +8,18 @@ var (
 	errAlertRuleConflictMsg  = "conflicting alert rule found [rule_uid: '{{ .Public.RuleUID }}', title: '{{ .Public.Title }}', namespace_uid: '{{ .Public.NamespaceUID }}']: {{ .Public.Error }}"
 	ErrAlertRuleConflictBase = errutil.Conflict("alerting.alert-rule.conflict").
 					MustTemplate(errAlertRuleConflictMsg, errutil.WithPublic(errAlertRuleConflictMsg))
+
+	errFolderQuotaReachedMsg  = "folder '{{ .Public.FolderUID }}' would have {{ .Public.Count }} alert rules, the limit is {{ .Public.Limit }}"
+	ErrFolderQuotaReachedBase = errutil.Forbidden("alerting.alert-rule.folder-quota-reached").
+					MustTemplate(errFolderQuotaReachedMsg, errutil.WithPublic(errFolderQuotaReachedMsg))
 )
 
 func ErrAlertRuleConflict(rule AlertRule, underlying error) error {
 	return ErrAlertRuleConflictBase.Build(errutil.TemplateData{Public: map[string]any{"RuleUID": rule.UID, "Title": rule.Title, "NamespaceUID": rule.NamespaceUID, "Error": underlying.Error()}, Error: underlying})
 }
+
+// ErrFolderQuotaReached is returned when the rules of a folder exceed the folder_alert_rule quota,
+// it wraps ErrQuotaReached.
+func ErrFolderQuotaReached(folderUID string, limit, count int64) error {
+	return ErrFolderQuotaReachedBase.Build(errutil.TemplateData{Public: map[string]any{"FolderUID": folderUID, "Limit": limit, "Count": count}, Error: ErrQuotaReached})
+}
@@ -161,6 +161,9 @@ type AlertNG struct {
 	tracer       tracing.Tracer
 
 	upgradeService migration.UpgradeService
+
+	// acquireEvaluationSlot limits the number of rules evaluated at the same time, it's optional
+	acquireEvaluationSlot func(ctx context.Context) (func(), error)
 }
 
 func (ng *AlertNG) init() error {
@@ -282,6 +285,12 @@ func (ng *AlertNG) init() error {
 		AlertSender:          alertsRouter,
 		Tracer:               ng.tracer,
 		Log:                  log.New("ngalert.scheduler"),
+		AcquireEvaluationSlot: func(ctx context.Context) (func(), error) {
+			if ng.acquireEvaluationSlot == nil {
+				return func() {}, nil
+			}
+			return ng.acquireEvaluationSlot(ctx)
+		},
 	}
 
 	// There are a set of feature toggles available that act as short-circuits for common configurations.
@@ -332,7 +341,7 @@ func (ng *AlertNG) init() error {
 	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.dashboardService, ng.QuotaService, ng.store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
-		ng.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit, ng.Log, notifier.NewNotificationSettingsValidationService(ng.store))
+		ng.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit, ng.Cfg.Quota.FolderAlertRuleLimit(), ng.Log, notifier.NewNotificationSettingsValidationService(ng.store))
 
 	ng.api = &api.API{
 		Cfg:                  ng.Cfg,
@@ -453,6 +462,12 @@ func (ng *AlertNG) IsDisabled() bool {
 	return ng.Cfg == nil
 }
 
+// SetEvaluationLimiter limits the number of alert rules evaluated at the same time on this instance.
+// acquire waits for an evaluation slot and returns a func releasing it. It must be called before Run.
+func (ng *AlertNG) SetEvaluationLimiter(acquire func(ctx context.Context) (func(), error)) {
+	ng.acquireEvaluationSlot = acquire
+}
+
 // GetHooks returns a facility for replacing handlers for paths. The handler hook for a path
 // is invoked after all other middleware is invoked (authentication, instrumentation).
 func (ng *AlertNG) GetHooks() *api.Hooks {
@@ -23,6 +23,7 @@ type AlertRuleService struct {
 	defaultIntervalSeconds int64
 	baseIntervalSeconds    int64
 	rulesPerRuleGroupLimit int64
+	folderRulesLimit       int64
 	ruleStore              RuleStore
 	provenanceStore        ProvisioningStore
 	dashboardService       dashboards.DashboardService
@@ -40,6 +41,7 @@ func NewAlertRuleService(ruleStore RuleStore,
 	defaultIntervalSeconds int64,
 	baseIntervalSeconds int64,
 	rulesPerRuleGroupLimit int64,
+	folderRulesLimit int64,
 	log log.Logger,
 	ns NotificationSettingsValidatorProvider,
 ) *AlertRuleService {
@@ -47,6 +49,7 @@ func NewAlertRuleService(ruleStore RuleStore,
 		defaultIntervalSeconds: defaultIntervalSeconds,
 		baseIntervalSeconds:    baseIntervalSeconds,
 		rulesPerRuleGroupLimit: rulesPerRuleGroupLimit,
+		folderRulesLimit:       folderRulesLimit,
 		ruleStore:              ruleStore,
 		provenanceStore:        provenanceStore,
 		dashboardService:       dashboardService,
@@ -176,7 +179,7 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 			return errors.New("couldn't find newly created id")
 		}
 
-		if err = service.checkLimitsTransactionCtx(ctx, rule.OrgID, userID); err != nil {
+		if err = service.checkLimitsTransactionCtx(ctx, rule.OrgID, userID, rule.NamespaceUID); err != nil {
 			return err
 		}
 
@@ -412,7 +415,13 @@ func (service *AlertRuleService) persistDelta(ctx context.Context, orgID int64,
 			}
 		}
 
-		if err := service.checkLimitsTransactionCtx(ctx, orgID, userID); err != nil {
+		namespaces := make([]string, 0, len(delta.New))
+		for _, rule := range delta.New {
+			if rule != nil {
+				namespaces = append(namespaces, rule.NamespaceUID)
+			}
+		}
+		if err := service.checkLimitsTransactionCtx(ctx, orgID, userID, namespaces...); err != nil {
 			return err
 		}
 
@@ -483,8 +492,9 @@ func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int6
 	})
 }
 
-// checkLimitsTransactionCtx checks whether the current transaction (as identified by the ctx) breaches configured alert rule limits.
-func (service *AlertRuleService) checkLimitsTransactionCtx(ctx context.Context, orgID, userID int64) error {
+// checkLimitsTransactionCtx checks whether the current transaction (as identified by the ctx) breaches configured alert rule limits,
+// including the limit of the folders the rules were added to.
+func (service *AlertRuleService) checkLimitsTransactionCtx(ctx context.Context, orgID, userID int64, folderUIDs ...string) error {
 	limitReached, err := service.quotas.CheckQuotaReached(ctx, models.QuotaTargetSrv, &quota.ScopeParameters{
 		OrgID:  orgID,
 		UserID: userID,
@@ -495,7 +505,7 @@ func (service *AlertRuleService) checkLimitsTransactionCtx(ctx context.Context,
 	if limitReached {
 		return models.ErrQuotaReached
 	}
-	return nil
+	return store.CheckFolderRulesLimit(ctx, service.ruleStore, orgID, service.folderRulesLimit, folderUIDs...)
 }
 
 // deleteRules deletes a set of target rules and associated data, while checking for database consistency.
@@ -12,6 +12,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -517,6 +518,42 @@ func TestAlertRuleService(t *testing.T) {
 
 		require.ErrorIs(t, err, models.ErrQuotaReached)
 	})
+
+	t.Run("folder quota met causes create to be rejected", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		ruleService.folderRulesLimit = 1
+
+		rule := dummyRule("folder-quota#1", orgID)
+		rule.NamespaceUID = "folder-quota"
+		_, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+
+		rule = dummyRule("folder-quota#2", orgID)
+		rule.NamespaceUID = "folder-quota"
+		_, err = ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceNone, 0)
+		require.ErrorIs(t, err, models.ErrQuotaReached)
+		require.ErrorIs(t, err, models.ErrFolderQuotaReachedBase)
+
+		var folderErr errutil.Error
+		require.ErrorAs(t, err, &folderErr)
+		require.Equal(t, map[string]any{"FolderUID": "folder-quota", "Limit": int64(1), "Count": int64(2)}, folderErr.PublicPayload)
+
+		rule = dummyRule("folder-quota#3", orgID)
+		rule.NamespaceUID = "other-folder"
+		_, err = ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("folder quota met causes group write to be rejected", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		ruleService.folderRulesLimit = 1
+
+		group := createDummyGroup("folder-quota-reached", 1)
+		group.Rules = append(group.Rules, dummyRule("folder-quota-reached-rule-2", 1))
+		err := ruleService.ReplaceRuleGroup(context.Background(), 1, group, 0, models.ProvenanceAPI)
+
+		require.ErrorIs(t, err, models.ErrQuotaReached)
+	})
 }
 
 func TestCreateAlertRule(t *testing.T) {
@@ -578,6 +615,7 @@ func createAlertRuleService(t *testing.T) AlertRuleService {
 		log:                    log.New("testing"),
 		baseIntervalSeconds:    10,
 		defaultIntervalSeconds: 60,
+		folderRulesLimit:       -1,
 	}
 }
 
@@ -88,6 +88,8 @@ type schedule struct {
 	alertsSender    AlertsSender
 	minRuleInterval time.Duration
 
+	acquireEvaluationSlot func(ctx context.Context) (func(), error)
+
 	// schedulableAlertRules contains the alert rules that are considered for
 	// evaluation in the current tick. The evaluation of an alert rule in the
 	// current tick depends on its evaluation interval and when it was
@@ -112,6 +114,9 @@ type SchedulerCfg struct {
 	AlertSender          AlertsSender
 	Tracer               tracing.Tracer
 	Log                  log.Logger
+	// AcquireEvaluationSlot limits the number of rules evaluated at the same time, it's optional.
+	// It waits for a slot and returns a func releasing it.
+	AcquireEvaluationSlot func(ctx context.Context) (func(), error)
 }
 
 // NewScheduler returns a new schedule.
@@ -138,6 +143,7 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		minRuleInterval:       cfg.MinRuleInterval,
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
+		acquireEvaluationSlot: cfg.AcquireEvaluationSlot,
 		tracer:                cfg.Tracer,
 	}
 
@@ -376,6 +382,15 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 
 	evaluate := func(ctx context.Context, f fingerprint, attempt int64, e *evaluation, span trace.Span, retry bool) error {
 		logger := logger.New("version", e.rule.Version, "fingerprint", f, "attempt", attempt, "now", e.scheduledAt).FromContext(ctx)
+		if sch.acquireEvaluationSlot != nil {
+			release, err := sch.acquireEvaluationSlot(ctx)
+			if err != nil {
+				logger.Debug("Skip evaluation because the context has been cancelled while waiting for an evaluation slot")
+				return nil
+			}
+			defer release()
+		}
+
 		start := sch.clock.Now()
 
 		evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserFor(e.rule.OrgID), sch.newLoadedMetricsReader(e.rule))
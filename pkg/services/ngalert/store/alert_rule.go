@@ -341,6 +341,34 @@ func (st DBstore) CountInFolders(ctx context.Context, orgID int64, folderUIDs []
 	return count, err
 }
 
+// FolderRuleCounter counts the alert rules of folders
+type FolderRuleCounter interface {
+	CountInFolders(ctx context.Context, orgID int64, folderUIDs []string, u identity.Requester) (int64, error)
+}
+
+// CheckFolderRulesLimit returns ErrFolderQuotaReached for the first folder that has more than limit alert rules,
+// it's called after the rules are saved in the transaction of the ctx. A negative limit disables the check.
+func CheckFolderRulesLimit(ctx context.Context, counter FolderRuleCounter, orgID int64, limit int64, folderUIDs ...string) error {
+	if limit < 0 {
+		return nil
+	}
+	checked := make(map[string]struct{}, len(folderUIDs))
+	for _, folderUID := range folderUIDs {
+		if _, ok := checked[folderUID]; ok {
+			continue
+		}
+		checked[folderUID] = struct{}{}
+		count, err := counter.CountInFolders(ctx, orgID, []string{folderUID}, nil)
+		if err != nil {
+			return fmt.Errorf("failed to count alert rules in folder: %w", err)
+		}
+		if count > limit {
+			return ngmodels.ErrFolderQuotaReached(folderUID, limit, count)
+		}
+	}
+	return nil
+}
+
 // ListAlertRules is a handler for retrieving alert rules of specific organisation.
 func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (result ngmodels.RulesGroup, err error) {
 	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -340,5 +341,13 @@ func (f *RuleStore) IncreaseVersionForAllRulesInNamespace(_ context.Context, org
 }
 
 func (f *RuleStore) CountInFolders(ctx context.Context, orgID int64, folderUIDs []string, u identity.Requester) (int64, error) {
-	return 0, nil
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var count int64
+	for _, r := range f.Rules[orgID] {
+		if slices.Contains(folderUIDs, r.NamespaceUID) {
+			count++
+		}
+	}
+	return count, nil
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/network"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -49,6 +50,11 @@ var tmplWelcomeOnSignUp = "welcome_on_signup"
 var tmplVerifyEmail = "verify_email_update"
 
 func ProvideService(bus bus.Bus, cfg *setting.Cfg, mailer Mailer, store TempUserStore) (*NotificationService, error) {
+	egressPolicy, err := network.NewEgressPolicy(cfg.EgressAllowedHosts, cfg.EgressDeniedHosts)
+	if err != nil {
+		return nil, err
+	}
+
 	ns := &NotificationService{
 		Bus:          bus,
 		Cfg:          cfg,
@@ -57,6 +63,7 @@ func ProvideService(bus bus.Bus, cfg *setting.Cfg, mailer Mailer, store TempUser
 		webhookQueue: make(chan *Webhook, 10),
 		mailer:       mailer,
 		store:        store,
+		egressPolicy: egressPolicy,
 	}
 
 	ns.Bus.AddEventListener(ns.signUpStartedHandler)
@@ -110,6 +117,7 @@ type NotificationService struct {
 	mailer       Mailer
 	log          log.Logger
 	store        TempUserStore
+	egressPolicy *network.EgressPolicy
 }
 
 func (ns *NotificationService) Run(ctx context.Context) error {
@@ -2,6 +2,8 @@ package notifications
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"testing"
 
@@ -9,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -272,6 +275,39 @@ func TestSendEmailAsync(t *testing.T) {
 	})
 }
 
+func TestSendWebhookSync_EgressPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("should send the webhook to an allowed host", func(t *testing.T) {
+		cfg := createSmtpConfig()
+		cfg.EgressAllowedHosts = []string{"127.0.0.1"}
+		ns, _, err := createSutWithConfig(t, newBus(t), cfg)
+		require.NoError(t, err)
+
+		require.NoError(t, ns.SendWebhookSync(context.Background(), &SendWebhookSync{Url: server.URL}))
+	})
+
+	t.Run("should not send the webhook to a denied host", func(t *testing.T) {
+		cfg := createSmtpConfig()
+		cfg.EgressDeniedHosts = []string{"127.0.0.0/8"}
+		ns, _, err := createSutWithConfig(t, newBus(t), cfg)
+		require.NoError(t, err)
+
+		err = ns.SendWebhookSync(context.Background(), &SendWebhookSync{Url: server.URL})
+		require.ErrorIs(t, err, network.ErrEgressDenied)
+	})
+
+	t.Run("should fail with an invalid policy", func(t *testing.T) {
+		cfg := createSmtpConfig()
+		cfg.EgressDeniedHosts = []string{"127.0.0.0/99"}
+		_, _, err := createSutWithConfig(t, newBus(t), cfg)
+		require.Error(t, err)
+	})
+}
+
 func createSut(t *testing.T, bus bus.Bus) (*NotificationService, *FakeMailer) {
 	t.Helper()
 
@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"time"
 
+	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -37,14 +39,14 @@ var netTransport = &http.Transport{
 		Renegotiation: tls.RenegotiateFreelyAsClient,
 	},
 	Proxy: http.ProxyFromEnvironment,
-	Dial: (&net.Dialer{
+	DialContext: network.EgressDialContext((&net.Dialer{
 		Timeout: 30 * time.Second,
-	}).Dial,
+	}).DialContext),
 	TLSHandshakeTimeout: 5 * time.Second,
 }
 var netClient WebhookClient = &http.Client{
 	Timeout:   time.Second * 30,
-	Transport: netTransport,
+	Transport: network.EgressRoundTripper(netTransport),
 }
 
 func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *Webhook) error {
@@ -58,6 +60,7 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 		return fmt.Errorf("webhook only supports HTTP methods PUT or POST")
 	}
 
+	ctx = network.ContextWithEgressPolicy(ctx, ns.egressPolicy)
 	request, err := http.NewRequestWithContext(ctx, webhook.HttpMethod, webhook.Url, bytes.NewReader([]byte(webhook.Body)))
 	if err != nil {
 		return err
@@ -80,6 +83,9 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 
 	resp, err := netClient.Do(request)
 	if err != nil {
+		if errors.Is(err, network.ErrEgressDenied) {
+			ns.log.Warn("Webhook denied by the egress policy", "url", request.URL.Redacted(), "error", err)
+		}
 		return err
 	}
 	defer func() {
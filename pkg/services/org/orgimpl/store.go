@@ -223,8 +223,15 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 
 		deletes := []string{
 			"DELETE FROM star WHERE EXISTS (SELECT 1 FROM dashboard WHERE org_id = ? AND star.dashboard_id = dashboard.id)",
+			"DELETE FROM star_collection WHERE org_id = ?",
 			"DELETE FROM dashboard_tag WHERE EXISTS (SELECT 1 FROM dashboard WHERE org_id = ? AND dashboard_tag.dashboard_id = dashboard.id)",
+			"DELETE FROM dashboard_panel_type WHERE org_id = ?",
+			"DELETE FROM dashboard_label WHERE org_id = ?",
+			"DELETE FROM folder_read_only WHERE org_id = ?",
+			"DELETE FROM dashboard_datasource WHERE org_id = ?",
 			"DELETE FROM dashboard WHERE org_id = ?",
+			"DELETE FROM dashboard_acl_audit WHERE org_id = ?",
+			"DELETE FROM user_notification WHERE org_id = ?",
 			"DELETE FROM api_key WHERE org_id = ?",
 			"DELETE FROM data_source WHERE org_id = ?",
 			"DELETE FROM org_user WHERE org_id = ?",
@@ -251,6 +258,16 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 		// Add registered deletes
 		deletes = append(deletes, ss.deletes...)
 
+		var dashboards []struct {
+			Id       int64
+			Uid      string
+			Title    string
+			IsFolder bool
+		}
+		if err := sess.SQL("SELECT id, uid, title, is_folder FROM dashboard WHERE org_id = ?", cmd.ID).Find(&dashboards); err != nil {
+			return err
+		}
+
 		for _, sql := range deletes {
 			_, err := sess.Exec(sql, cmd.ID)
 			if err != nil {
@@ -258,6 +275,18 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 			}
 		}
 
+		now := time.Now()
+		for _, dash := range dashboards {
+			sess.PublishAfterCommit(&events.DashboardDeleted{
+				Timestamp: now,
+				OrgID:     cmd.ID,
+				ID:        dash.Id,
+				UID:       dash.Uid,
+				Title:     dash.Title,
+				IsFolder:  dash.IsFolder,
+			})
+		}
+
 		return nil
 	})
 }
@@ -688,6 +717,7 @@ func (ss *sqlStore) RemoveOrgUser(ctx context.Context, cmd *org.RemoveOrgUserCom
 			"DELETE FROM dashboard_acl WHERE org_id=? and user_id = ?",
 			"DELETE FROM team_member WHERE org_id=? and user_id = ?",
 			"DELETE FROM query_history_star WHERE org_id=? and user_id = ?",
+			"DELETE FROM user_notification WHERE org_id=? and user_id = ?",
 		}
 
 		for _, sql := range deletes {
@@ -811,6 +841,7 @@ func deleteUserAccessControl(sess *db.Session, userID int64) error {
 func (ss *sqlStore) userDeletions() []string {
 	deletes := []string{
 		"DELETE FROM star WHERE user_id = ?",
+		"DELETE FROM star_collection WHERE user_id = ?",
 		"DELETE FROM " + ss.dialect.Quote("user") + " WHERE id = ?",
 		"DELETE FROM org_user WHERE user_id = ?",
 		"DELETE FROM dashboard_acl WHERE user_id = ?",
@@ -819,6 +850,7 @@ func (ss *sqlStore) userDeletions() []string {
 		"DELETE FROM user_auth WHERE user_id = ?",
 		"DELETE FROM user_auth_token WHERE user_id = ?",
 		"DELETE FROM quota WHERE user_id = ?",
+		"DELETE FROM user_notification WHERE user_id = ?",
 	}
 	return deletes
 }
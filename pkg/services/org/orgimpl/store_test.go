@@ -10,9 +10,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota/quotaimpl"
 	"github.com/grafana/grafana/pkg/services/searchusers/sortopts"
@@ -131,6 +133,33 @@ func TestIntegrationOrgDataAccess(t *testing.T) {
 		// require.Equal(t, err, user.ErrUserNotFound)
 	})
 
+	t.Run("Removing org publishes the deletion of its dashboards", func(t *testing.T) {
+		orga := &org.Org{ID: 23, Name: "with dashboards", Version: 1, Created: time.Now(), Updated: time.Now()}
+		_, err := orgStore.Insert(context.Background(), orga)
+		require.NoError(t, err)
+		err = ss.WithDbSession(context.Background(), func(sess *db.Session) error {
+			dash := dashboards.NewDashboard("org dashboard")
+			dash.OrgID = orga.ID
+			dash.UID = "org-dashboard"
+			dash.Created, dash.Updated = time.Now(), time.Now()
+			_, err := sess.Insert(dash)
+			return err
+		})
+		require.NoError(t, err)
+
+		var deleted []*events.DashboardDeleted
+		ss.Bus().AddEventListener(func(_ context.Context, e *events.DashboardDeleted) error {
+			deleted = append(deleted, e)
+			return nil
+		})
+
+		err = orgStore.Delete(context.Background(), &org.DeleteOrgCommand{ID: orga.ID})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, "org-dashboard", deleted[0].UID)
+		require.Equal(t, orga.ID, deleted[0].OrgID)
+	})
+
 	t.Run("Given we have organizations, we can query them by IDs", func(t *testing.T) {
 		var err error
 		var cmd *org.CreateOrgCommand
@@ -0,0 +1,73 @@
+package orglifecycle
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var ErrTemplateNotApplied = errutil.NotFound("orglifecycle.template-not-applied", errutil.WithPublicMessage("The org template hasn't been applied to this organization"))
+
+func (s *Service) registerAPIEndpoints(ac accesscontrol.AccessControl, routeRegister routing.RouteRegister) {
+	auth := accesscontrol.Middleware(ac)
+	routeRegister.Group("/api/org/lifecycle/template", func(r routing.RouteRegister) {
+		r.Get("/", auth(accesscontrol.EvalPermission(accesscontrol.ActionOrgsRead)), routing.Wrap(s.handleGetTemplateStatus))
+		r.Post("/apply", auth(accesscontrol.EvalPermission(accesscontrol.ActionOrgsWrite)), routing.Wrap(s.handleApplyTemplate))
+	}, middleware.ReqSignedIn)
+}
+
+// swagger:route GET /org/lifecycle/template org getOrgTemplateStatus
+//
+// # Get the status of the org template in the current organization
+//
+// Lists the steps of the template that were applied and the errors of the steps that failed.
+//
+// Responses:
+//
+//	200: getOrgTemplateStatusResponse
+//	401: unauthorisedError
+//	403: forbiddenError
+//	404: notFoundError
+//	500: internalServerError
+func (s *Service) handleGetTemplateStatus(c *contextmodel.ReqContext) response.Response {
+	status, err := s.getTemplateStatus(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get org template status", err)
+	}
+	if status == nil {
+		return response.Err(ErrTemplateNotApplied.Errorf("no template status for org %d", c.SignedInUser.GetOrgID()))
+	}
+	return response.JSON(http.StatusOK, status)
+}
+
+// swagger:route POST /org/lifecycle/template/apply org applyOrgTemplate
+//
+// # Apply the org template to the current organization
+//
+// Only the steps that haven't been applied yet are run, so the steps that failed when the
+// organization was created can be retried.
+//
+// Responses:
+//
+//	200: getOrgTemplateStatusResponse
+//	401: unauthorisedError
+//	403: forbiddenError
+//	500: internalServerError
+func (s *Service) handleApplyTemplate(c *contextmodel.ReqContext) response.Response {
+	status, err := s.applyTemplate(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to apply org template", err)
+	}
+	return response.JSON(http.StatusOK, status)
+}
+
+// swagger:response getOrgTemplateStatusResponse
+type GetOrgTemplateStatusResponse struct {
+	// in:body
+	Body TemplateStatus `json:"body"`
+}
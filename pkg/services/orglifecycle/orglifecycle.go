@@ -0,0 +1,355 @@
+package orglifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// EventOrgCreated is the event sent to the webhook when an organization has been created
+const EventOrgCreated = "org.created"
+
+const (
+	statusNamespace = "org_lifecycle"
+	statusKey       = "template_status"
+
+	// templateAttempts is the number of times the failed steps of the template are tried
+	templateAttempts = 3
+)
+
+// Service runs the configured hooks when an organization is created, so that new
+// organizations come with their default folders, teams, roles and data sources.
+type Service struct {
+	cfg               setting.OrgLifecycleSettings
+	template          *Template
+	client            *http.Client
+	folderService     folder.Service
+	folderPermissions accesscontrol.FolderPermissionsService
+	teamService       team.Service
+	datasourceService datasources.DataSourceService
+	acService         accesscontrol.Service
+	kvStore           kvstore.KVStore
+	log               log.Logger
+
+	// retryDelay is the delay before the first retry of the template, it doubles after each attempt
+	retryDelay time.Duration
+	// templateMu serializes the applications of the template so that a resource isn't created twice
+	templateMu sync.Mutex
+}
+
+func ProvideService(cfg *setting.Cfg, bus bus.Bus, folderService folder.Service,
+	folderPermissions accesscontrol.FolderPermissionsService, teamService team.Service,
+	datasourceService datasources.DataSourceService, acService accesscontrol.Service, kvStore kvstore.KVStore,
+	ac accesscontrol.AccessControl, routeRegister routing.RouteRegister) (*Service, error) {
+	s := &Service{
+		cfg:               cfg.OrgLifecycle,
+		client:            &http.Client{Timeout: cfg.OrgLifecycle.WebhookTimeout},
+		folderService:     folderService,
+		folderPermissions: folderPermissions,
+		teamService:       teamService,
+		datasourceService: datasourceService,
+		acService:         acService,
+		kvStore:           kvStore,
+		log:               log.New("org.lifecycle"),
+		retryDelay:        5 * time.Second,
+	}
+
+	if s.cfg.TemplateFile != "" {
+		t, err := readTemplate(s.cfg.TemplateFile)
+		if err != nil {
+			return nil, err
+		}
+		s.template = t
+		s.registerAPIEndpoints(ac, routeRegister)
+	}
+
+	if s.template != nil || s.cfg.WebhookURL != "" {
+		bus.AddEventListener(s.handleOrgCreated)
+	}
+	return s, nil
+}
+
+// WebhookPayload is the body of the request sent to the webhook
+type WebhookPayload struct {
+	Event string `json:"event"`
+	*events.OrgCreated
+	// TemplateErrors are the steps of the template that failed, the template can be applied again through the API
+	TemplateErrors []string `json:"templateErrors,omitempty"`
+}
+
+// TemplateStatus is the state of the application of the template to an organization. It is stored so
+// that the steps that failed can be applied again without creating the other resources twice.
+type TemplateStatus struct {
+	// Applied are the steps that succeeded, such as "team/Developers"
+	Applied map[string]bool `json:"applied"`
+	// Errors are the steps that failed during the last attempt
+	Errors   []string  `json:"errors,omitempty"`
+	Attempts int       `json:"attempts"`
+	Updated  time.Time `json:"updated"`
+	// TeamIDs and FolderUIDs are the teams and folders created by the template, by name and title
+	TeamIDs    map[string]int64  `json:"teamIds"`
+	FolderUIDs map[string]string `json:"folderUids"`
+}
+
+func (s *Service) handleOrgCreated(ctx context.Context, evt *events.OrgCreated) error {
+	// the hooks can be slow, so they don't hold up the creation of the organization
+	go s.runHooks(context.WithoutCancel(ctx), evt)
+	return nil
+}
+
+func (s *Service) runHooks(ctx context.Context, evt *events.OrgCreated) {
+	payload := &WebhookPayload{Event: EventOrgCreated, OrgCreated: evt}
+	if s.template != nil {
+		if status := s.applyTemplateWithRetries(ctx, evt.Id); status != nil {
+			payload.TemplateErrors = status.Errors
+		}
+	}
+
+	if s.cfg.WebhookURL != "" {
+		if err := s.callWebhook(ctx, payload); err != nil {
+			s.log.Error("Failed to call org lifecycle webhook", "orgId", evt.Id, "error", err)
+		}
+	}
+}
+
+func (s *Service) applyTemplateWithRetries(ctx context.Context, orgID int64) *TemplateStatus {
+	delay := s.retryDelay
+	for attempt := 1; ; attempt++ {
+		status, err := s.applyTemplate(ctx, orgID)
+		if err == nil && len(status.Errors) == 0 {
+			return status
+		}
+		if attempt >= templateAttempts {
+			if err == nil {
+				err = fmt.Errorf("%s", strings.Join(status.Errors, "; "))
+			}
+			s.log.Error("Failed to apply org template", "orgId", orgID, "attempts", attempt, "error", err)
+			return status
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return status
+		}
+	}
+}
+
+// applyTemplate creates the resources of the template that haven't been created yet. The steps
+// that fail are recorded in the returned status, the error is only set when the status can't be stored.
+func (s *Service) applyTemplate(ctx context.Context, orgID int64) (*TemplateStatus, error) {
+	s.templateMu.Lock()
+	defer s.templateMu.Unlock()
+
+	status, err := s.getTemplateStatus(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		status = &TemplateStatus{Applied: map[string]bool{}, TeamIDs: map[string]int64{}, FolderUIDs: map[string]string{}}
+	}
+	status.Errors = nil
+
+	step := func(name string, fn func() error) {
+		if status.Applied[name] {
+			return
+		}
+		if err := fn(); err != nil {
+			status.Errors = append(status.Errors, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		status.Applied[name] = true
+	}
+	teamIDs := func(names ...string) ([]int64, error) {
+		ids := make([]int64, 0, len(names))
+		for _, name := range names {
+			id, ok := status.TeamIDs[name]
+			if !ok {
+				return nil, fmt.Errorf("team %q hasn't been created", name)
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	user := accesscontrol.BackgroundUser("org_lifecycle", orgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: dashboards.ActionFoldersCreate, Scope: dashboards.ScopeFoldersAll},
+		{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll},
+		{Action: dashboards.ActionFoldersPermissionsWrite, Scope: dashboards.ScopeFoldersAll},
+	})
+
+	for _, t := range s.template.Teams {
+		step("team/"+t.Name, func() error {
+			created, err := s.teamService.CreateTeam(t.Name, t.Email, orgID)
+			if err != nil {
+				return err
+			}
+			status.TeamIDs[t.Name] = created.ID
+			return nil
+		})
+	}
+
+	for _, f := range s.template.Folders {
+		step("folder/"+f.Title, func() error {
+			created, err := s.folderService.Create(ctx, &folder.CreateFolderCommand{
+				UID:          f.UID,
+				OrgID:        orgID,
+				Title:        f.Title,
+				Description:  f.Description,
+				SignedInUser: user,
+			})
+			if err != nil {
+				return err
+			}
+			status.FolderUIDs[f.Title] = created.UID
+			return nil
+		})
+
+		if len(f.Permissions) == 0 {
+			continue
+		}
+		step("folder-permissions/"+f.Title, func() error {
+			uid, ok := status.FolderUIDs[f.Title]
+			if !ok {
+				return fmt.Errorf("folder %q hasn't been created", f.Title)
+			}
+			commands := make([]accesscontrol.SetResourcePermissionCommand, 0, len(f.Permissions))
+			for _, p := range f.Permissions {
+				cmd := accesscontrol.SetResourcePermissionCommand{BuiltinRole: p.Role, Permission: p.Permission}
+				if p.Team != "" {
+					ids, err := teamIDs(p.Team)
+					if err != nil {
+						return err
+					}
+					cmd.TeamID = ids[0]
+				}
+				commands = append(commands, cmd)
+			}
+			_, err := s.folderPermissions.SetPermissions(ctx, orgID, uid, commands...)
+			return err
+		})
+	}
+
+	for _, r := range s.template.Roles {
+		step("role/"+r.Name, func() error {
+			ids, err := teamIDs(r.Teams...)
+			if err != nil {
+				return err
+			}
+			permissions := make([]accesscontrol.Permission, 0, len(r.Permissions))
+			for _, p := range r.Permissions {
+				permissions = append(permissions, accesscontrol.Permission{Action: p.Action, Scope: p.Scope})
+			}
+			return s.acService.SaveOrgTemplateRole(ctx, accesscontrol.SaveOrgTemplateRoleCommand{
+				OrgID:       orgID,
+				Name:        r.Name,
+				DisplayName: r.DisplayName,
+				Description: r.Description,
+				Permissions: permissions,
+				TeamIDs:     ids,
+				BasicRoles:  r.BasicRoles,
+			})
+		})
+	}
+
+	for _, ds := range s.template.Datasources {
+		step("datasource/"+ds.Name, func() error {
+			access := datasources.DsAccess(ds.Access)
+			if access == "" {
+				access = datasources.DS_ACCESS_PROXY
+			}
+			_, err := s.datasourceService.AddDataSource(ctx, &datasources.AddDataSourceCommand{
+				OrgID:          orgID,
+				UID:            ds.UID,
+				Name:           ds.Name,
+				Type:           ds.Type,
+				Access:         access,
+				URL:            ds.URL,
+				IsDefault:      ds.IsDefault,
+				JsonData:       simplejson.NewFromAny(ds.JSONData),
+				SecureJsonData: ds.SecureJSONData,
+			})
+			return err
+		})
+	}
+
+	status.Attempts++
+	status.Updated = time.Now()
+	if err := s.setTemplateStatus(ctx, orgID, status); err != nil {
+		return nil, err
+	}
+
+	if len(status.Errors) == 0 {
+		s.log.Info("Applied org template", "orgId", orgID, "teams", len(s.template.Teams), "folders", len(s.template.Folders),
+			"roles", len(s.template.Roles), "datasources", len(s.template.Datasources))
+	}
+	return status, nil
+}
+
+func (s *Service) getTemplateStatus(ctx context.Context, orgID int64) (*TemplateStatus, error) {
+	value, ok, err := s.kvStore.Get(ctx, orgID, statusNamespace, statusKey)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var status TemplateStatus
+	if err := json.Unmarshal([]byte(value), &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (s *Service) setTemplateStatus(ctx context.Context, orgID int64, status *TemplateStatus) error {
+	value, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return s.kvStore.Set(ctx, orgID, statusNamespace, statusKey, string(value))
+}
+
+func (s *Service) callWebhook(ctx context.Context, payload *WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Grafana")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package orglifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type recordingFolderService struct {
+	*foldertest.FakeService
+	created []*folder.CreateFolderCommand
+}
+
+func (s *recordingFolderService) Create(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, error) {
+	s.created = append(s.created, cmd)
+	return &folder.Folder{UID: cmd.UID, OrgID: cmd.OrgID, Title: cmd.Title}, nil
+}
+
+type recordingPermissionsService struct {
+	*actest.FakePermissionsService
+	resourceID string
+	commands   []accesscontrol.SetResourcePermissionCommand
+}
+
+func (s *recordingPermissionsService) SetPermissions(ctx context.Context, orgID int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error) {
+	s.resourceID = resourceID
+	s.commands = commands
+	return nil, nil
+}
+
+type recordingRoleService struct {
+	actest.FakeService
+	// failures is the number of calls that fail before the roles are saved
+	failures int
+	saved    []accesscontrol.SaveOrgTemplateRoleCommand
+}
+
+func (s *recordingRoleService) SaveOrgTemplateRole(ctx context.Context, cmd accesscontrol.SaveOrgTemplateRoleCommand) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("database is locked")
+	}
+	s.saved = append(s.saved, cmd)
+	return nil
+}
+
+type testEnv struct {
+	bus         bus.Bus
+	service     *Service
+	folders     *recordingFolderService
+	permissions *recordingPermissionsService
+	roles       *recordingRoleService
+	dataSources *fakeDatasources.FakeDataSourceService
+	payloads    chan WebhookPayload
+}
+
+func setupTestEnv(t *testing.T, roleFailures int) *testEnv {
+	t.Helper()
+
+	env := &testEnv{
+		bus:         bus.ProvideBus(tracing.InitializeTracerForTest()),
+		folders:     &recordingFolderService{FakeService: foldertest.NewFakeService()},
+		permissions: &recordingPermissionsService{FakePermissionsService: &actest.FakePermissionsService{}},
+		roles:       &recordingRoleService{failures: roleFailures},
+		dataSources: &fakeDatasources.FakeDataSourceService{},
+		payloads:    make(chan WebhookPayload, 1),
+	}
+
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var payload WebhookPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusNoContent)
+		env.payloads <- payload
+	}))
+	t.Cleanup(webhook.Close)
+
+	cfg := setting.NewCfg()
+	cfg.OrgLifecycle = setting.OrgLifecycleSettings{
+		TemplateFile:   filepath.Join("testdata", "template.yaml"),
+		WebhookURL:     webhook.URL,
+		WebhookTimeout: time.Second,
+	}
+
+	teams := &teamtest.FakeService{ExpectedTeam: team.Team{ID: 7, Name: "Developers"}}
+	s, err := ProvideService(cfg, env.bus, env.folders, env.permissions, teams, env.dataSources, env.roles,
+		kvstore.NewFakeKVStore(), actest.FakeAccessControl{}, routing.NewRouteRegister())
+	require.NoError(t, err)
+	s.retryDelay = time.Millisecond
+	env.service = s
+	return env
+}
+
+func (env *testEnv) waitForWebhook(t *testing.T) WebhookPayload {
+	t.Helper()
+	select {
+	case payload := <-env.payloads:
+		return payload
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not called")
+		return WebhookPayload{}
+	}
+}
+
+func TestService(t *testing.T) {
+	env := setupTestEnv(t, 0)
+
+	evt := &events.OrgCreated{Timestamp: time.Now(), Id: 3, Name: "tenant"}
+	require.NoError(t, env.bus.Publish(context.Background(), evt))
+	payload := env.waitForWebhook(t)
+
+	require.Len(t, env.folders.created, 1)
+	assert.Equal(t, int64(3), env.folders.created[0].OrgID)
+	assert.Equal(t, "Team dashboards", env.folders.created[0].Title)
+
+	assert.Equal(t, "team-dashboards", env.permissions.resourceID)
+	assert.Equal(t, []accesscontrol.SetResourcePermissionCommand{
+		{TeamID: 7, Permission: "Edit"},
+		{BuiltinRole: "Viewer", Permission: "View"},
+	}, env.permissions.commands)
+
+	require.Len(t, env.roles.saved, 1)
+	assert.Equal(t, accesscontrol.SaveOrgTemplateRoleCommand{
+		OrgID:       3,
+		Name:        "dashboard_creators",
+		DisplayName: "Dashboard creators",
+		Permissions: []accesscontrol.Permission{{Action: "dashboards:create", Scope: "folders:uid:team-dashboards"}},
+		TeamIDs:     []int64{7},
+		BasicRoles:  []string{"Editor"},
+	}, env.roles.saved[0])
+
+	require.Len(t, env.dataSources.DataSources, 1)
+	assert.Equal(t, int64(3), env.dataSources.DataSources[0].OrgID)
+	assert.Equal(t, "prometheus", env.dataSources.DataSources[0].Type)
+
+	assert.Equal(t, EventOrgCreated, payload.Event)
+	require.NotNil(t, payload.OrgCreated)
+	assert.Equal(t, int64(3), payload.Id)
+	assert.Equal(t, "tenant", payload.Name)
+	assert.Empty(t, payload.TemplateErrors)
+
+	status, err := env.service.getTemplateStatus(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Empty(t, status.Errors)
+	assert.Equal(t, 1, status.Attempts)
+	assert.True(t, status.Applied["role/dashboard_creators"])
+}
+
+func TestService_FailedSteps(t *testing.T) {
+	t.Run("should retry only the failed steps", func(t *testing.T) {
+		env := setupTestEnv(t, 1)
+		require.NoError(t, env.bus.Publish(context.Background(), &events.OrgCreated{Id: 3}))
+		payload := env.waitForWebhook(t)
+
+		assert.Empty(t, payload.TemplateErrors)
+		assert.Len(t, env.folders.created, 1)
+		assert.Len(t, env.dataSources.DataSources, 1)
+		assert.Len(t, env.roles.saved, 1)
+
+		status, err := env.service.getTemplateStatus(context.Background(), 3)
+		require.NoError(t, err)
+		assert.Equal(t, 2, status.Attempts)
+	})
+
+	t.Run("should report the steps that still fail and apply them again on demand", func(t *testing.T) {
+		env := setupTestEnv(t, templateAttempts)
+		require.NoError(t, env.bus.Publish(context.Background(), &events.OrgCreated{Id: 3}))
+		payload := env.waitForWebhook(t)
+
+		assert.Equal(t, []string{"role/dashboard_creators: database is locked"}, payload.TemplateErrors)
+		assert.Len(t, env.folders.created, 1)
+		assert.Len(t, env.dataSources.DataSources, 1)
+		assert.Empty(t, env.roles.saved)
+
+		status, err := env.service.applyTemplate(context.Background(), 3)
+		require.NoError(t, err)
+		assert.Empty(t, status.Errors)
+		assert.Equal(t, templateAttempts+1, status.Attempts)
+		assert.Len(t, env.folders.created, 1)
+		assert.Len(t, env.dataSources.DataSources, 1)
+		assert.Len(t, env.roles.saved, 1)
+	})
+}
+
+func TestReadTemplate(t *testing.T) {
+	tmpl, err := readTemplate(filepath.Join("testdata", "template.yaml"))
+	require.NoError(t, err)
+	require.Len(t, tmpl.Roles, 1)
+	assert.Equal(t, []string{"Developers"}, tmpl.Roles[0].Teams)
+	require.Len(t, tmpl.Datasources, 1)
+	assert.Equal(t, "Prometheus", tmpl.Datasources[0].Name)
+	assert.True(t, tmpl.Datasources[0].IsDefault)
+
+	invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte(`
+folders:
+  - title: Shared
+    permissions:
+      - team: Unknown
+        permission: View
+`), 0600))
+	_, err = readTemplate(invalid)
+	require.ErrorContains(t, err, `unknown team "Unknown"`)
+}
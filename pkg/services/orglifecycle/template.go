@@ -0,0 +1,140 @@
+package orglifecycle
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// Template describes the resources that are created in every new organization
+type Template struct {
+	Teams       []TeamTemplate       `yaml:"teams"`
+	Folders     []FolderTemplate     `yaml:"folders"`
+	Roles       []RoleTemplate       `yaml:"roles"`
+	Datasources []DatasourceTemplate `yaml:"datasources"`
+}
+
+type TeamTemplate struct {
+	Name  string `yaml:"name"`
+	Email string `yaml:"email"`
+}
+
+type FolderTemplate struct {
+	UID         string `yaml:"uid"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	// Permissions are granted to teams of the template or to basic roles
+	Permissions []PermissionTemplate `yaml:"permissions"`
+}
+
+type PermissionTemplate struct {
+	Team       string `yaml:"team"`
+	Role       string `yaml:"role"`
+	Permission string `yaml:"permission"`
+}
+
+// RoleTemplate is an RBAC role of the organization granted to teams of the template or to basic roles
+type RoleTemplate struct {
+	Name        string                   `yaml:"name"`
+	DisplayName string                   `yaml:"displayName"`
+	Description string                   `yaml:"description"`
+	Permissions []RolePermissionTemplate `yaml:"permissions"`
+	Teams       []string                 `yaml:"teams"`
+	BasicRoles  []string                 `yaml:"basicRoles"`
+}
+
+type RolePermissionTemplate struct {
+	Action string `yaml:"action"`
+	Scope  string `yaml:"scope"`
+}
+
+type DatasourceTemplate struct {
+	UID       string         `yaml:"uid"`
+	Name      string         `yaml:"name"`
+	Type      string         `yaml:"type"`
+	Access    string         `yaml:"access"`
+	URL       string         `yaml:"url"`
+	IsDefault bool           `yaml:"isDefault"`
+	JSONData  map[string]any `yaml:"jsonData"`
+	// SecureJSONData is encrypted when the data source is created
+	SecureJSONData map[string]string `yaml:"secureJsonData"`
+}
+
+func readTemplate(path string) (*Template, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path comes from the configuration file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read org template: %w", err)
+	}
+
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse org template %s: %w", path, err)
+	}
+	if err := t.validate(); err != nil {
+		return nil, fmt.Errorf("invalid org template %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+func (t *Template) validate() error {
+	teams := make(map[string]bool, len(t.Teams))
+	for _, team := range t.Teams {
+		if team.Name == "" {
+			return fmt.Errorf("team without name")
+		}
+		teams[team.Name] = true
+	}
+
+	for _, f := range t.Folders {
+		if f.Title == "" {
+			return fmt.Errorf("folder without title")
+		}
+		for _, p := range f.Permissions {
+			if (p.Team == "") == (p.Role == "") {
+				return fmt.Errorf("permission of folder %q must have either a team or a role", f.Title)
+			}
+			if p.Team != "" && !teams[p.Team] {
+				return fmt.Errorf("permission of folder %q refers to unknown team %q", f.Title, p.Team)
+			}
+			if p.Permission == "" {
+				return fmt.Errorf("permission of folder %q without permission", f.Title)
+			}
+		}
+	}
+
+	for _, r := range t.Roles {
+		if r.Name == "" {
+			return fmt.Errorf("role without name")
+		}
+		if len(r.Permissions) == 0 {
+			return fmt.Errorf("role %q without permissions", r.Name)
+		}
+		for _, p := range r.Permissions {
+			if p.Action == "" {
+				return fmt.Errorf("permission of role %q without action", r.Name)
+			}
+		}
+		for _, team := range r.Teams {
+			if !teams[team] {
+				return fmt.Errorf("role %q refers to unknown team %q", r.Name, team)
+			}
+		}
+		for _, basicRole := range r.BasicRoles {
+			if !org.RoleType(basicRole).IsValid() {
+				return fmt.Errorf("role %q refers to unknown basic role %q", r.Name, basicRole)
+			}
+		}
+	}
+
+	for _, ds := range t.Datasources {
+		if ds.Name == "" || ds.Type == "" {
+			return fmt.Errorf("data source without name or type")
+		}
+	}
+	return nil
+}
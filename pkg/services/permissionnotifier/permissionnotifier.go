@@ -0,0 +1,238 @@
+package permissionnotifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/usernotification"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// KindPermissionsChanged is the kind of the in-app notifications sent when permissions change
+	KindPermissionsChanged = "permissions.changed"
+
+	emailTemplate = "permissions_changed"
+	// ownerPermission is the permission that makes a user an owner of a dashboard or folder
+	ownerPermission = "Admin"
+)
+
+// Service notifies the owners of a dashboard or folder, by email and in Grafana, and calls the
+// configured webhook when its permissions change.
+type Service struct {
+	cfg               *setting.Cfg
+	notifications     notifications.Service
+	userNotifications usernotification.Service
+	dashboardService  dashboards.DashboardService
+	userService       user.Service
+	teamService       team.Service
+	log               log.Logger
+}
+
+func ProvideService(cfg *setting.Cfg, bus bus.Bus, notificationService notifications.Service, userNotifications usernotification.Service,
+	dashboardService dashboards.DashboardService, userService user.Service, teamService team.Service) *Service {
+	s := &Service{
+		cfg:               cfg,
+		notifications:     notificationService,
+		userNotifications: userNotifications,
+		dashboardService:  dashboardService,
+		userService:       userService,
+		teamService:       teamService,
+		log:               log.New("permission.notifier"),
+	}
+
+	if cfg.PermissionChangeNotifications.Enabled() {
+		bus.AddEventListener(s.handlePermissionsChanged)
+	}
+	return s
+}
+
+// owner is a user notified of the permission changes of a dashboard or folder
+type owner struct {
+	id    int64
+	login string
+	email string
+}
+
+func (s *Service) handlePermissionsChanged(ctx context.Context, evt *events.ResourcePermissionsChanged) error {
+	conf := s.cfg.PermissionChangeNotifications
+	if conf.WebhookURL != "" {
+		s.callWebhook(evt)
+	}
+
+	// the initial permissions of new dashboards and folders aren't a change the owners need to notice
+	if (!conf.Email && !conf.InApp) || len(evt.Before) == 0 {
+		return nil
+	}
+
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: evt.ResourceID, OrgID: evt.OrgID})
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to get the dashboard or folder of the permission change", "uid", evt.ResourceID, "error", err)
+		return nil
+	}
+
+	owners, err := s.owners(ctx, evt)
+	if err != nil {
+		s.log.FromContext(ctx).Error("Failed to get the owners to notify of the permission change", "uid", evt.ResourceID, "error", err)
+		return nil
+	}
+
+	kind := "dashboard"
+	if dash.IsFolder {
+		kind = "folder"
+	}
+	changedBy := evt.ActorLogin
+	if changedBy == "" {
+		changedBy = "Grafana"
+	}
+	changes := describeChanges(evt.Before, evt.After)
+	title := fmt.Sprintf("Permissions of the %s %s changed", kind, dash.Title)
+	path := strings.TrimPrefix(dashboards.GetDashboardFolderURL(dash.IsFolder, dash.UID, dash.Slug), s.cfg.AppSubURL)
+
+	for _, o := range owners {
+		if conf.InApp {
+			if err := s.userNotifications.Create(ctx, &usernotification.CreateNotificationCommand{
+				OrgID:  evt.OrgID,
+				UserID: o.id,
+				Kind:   KindPermissionsChanged,
+				Title:  title,
+				Text:   fmt.Sprintf("Changed by %s: %s", changedBy, strings.Join(changes, ", ")),
+				URL:    path,
+			}); err != nil {
+				s.log.FromContext(ctx).Error("Failed to create permission change notification", "uid", evt.ResourceID, "userId", o.id, "error", err)
+			}
+		}
+
+		if conf.Email && o.email != "" {
+			if err := s.notifications.SendEmailCommandHandler(ctx, &notifications.SendEmailCommand{
+				To:       []string{o.email},
+				Template: emailTemplate,
+				Subject:  title,
+				Data: map[string]any{
+					"Name":      o.login,
+					"Kind":      kind,
+					"Title":     dash.Title,
+					"URL":       strings.TrimSuffix(s.cfg.AppURL, "/") + path,
+					"ChangedBy": changedBy,
+					"Changes":   changes,
+				},
+			}); err != nil {
+				s.log.FromContext(ctx).Error("Failed to send permission change email", "uid", evt.ResourceID, "userId", o.id, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) callWebhook(evt *events.ResourcePermissionsChanged) {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		s.log.Error("Failed to encode permission change", "uid", evt.ResourceID, "error", err)
+		return
+	}
+
+	// the request that changed the permissions must not wait for the webhook
+	go func() {
+		if err := s.notifications.SendWebhookSync(context.Background(), &notifications.SendWebhookSync{
+			Url:         s.cfg.PermissionChangeNotifications.WebhookURL,
+			Body:        string(body),
+			HttpMethod:  http.MethodPost,
+			ContentType: "application/json",
+		}); err != nil {
+			s.log.Error("Failed to call permission change webhook", "uid", evt.ResourceID, "error", err)
+		}
+	}()
+}
+
+// owners returns the users with the Admin permission before or after the change, directly or through
+// a team, except the user who made the change
+func (s *Service) owners(ctx context.Context, evt *events.ResourcePermissionsChanged) ([]owner, error) {
+	userIDs := map[int64]struct{}{}
+	teamIDs := map[int64]struct{}{}
+	for _, p := range append(append([]events.ResourcePermission{}, evt.Before...), evt.After...) {
+		if p.Permission != ownerPermission {
+			continue
+		}
+		if p.UserID != 0 {
+			userIDs[p.UserID] = struct{}{}
+		}
+		if p.TeamID != 0 {
+			teamIDs[p.TeamID] = struct{}{}
+		}
+	}
+
+	owners := map[int64]owner{}
+	for id := range userIDs {
+		usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: id})
+		if err != nil {
+			return nil, err
+		}
+		if usr.IsServiceAccount {
+			continue
+		}
+		owners[usr.ID] = owner{id: usr.ID, login: usr.Login, email: usr.Email}
+	}
+
+	// the members of the teams are listed regardless of who made the change
+	lister := accesscontrol.BackgroundUser("permission_notifier", evt.OrgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+	})
+	for id := range teamIDs {
+		members, err := s.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{OrgID: evt.OrgID, TeamID: id, SignedInUser: lister})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			owners[m.UserID] = owner{id: m.UserID, login: m.Login, email: m.Email}
+		}
+	}
+
+	delete(owners, evt.ActorID)
+	result := make([]owner, 0, len(owners))
+	for _, o := range owners {
+		result = append(result, o)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].id < result[j].id })
+	return result, nil
+}
+
+// describeChanges returns the permissions that were granted, changed or removed, e.g. "role:Viewer: View -> Edit"
+func describeChanges(before, after []events.ResourcePermission) []string {
+	previous := map[string]string{}
+	for _, p := range before {
+		previous[p.Subject] = p.Permission
+	}
+	current := map[string]string{}
+	for _, p := range after {
+		current[p.Subject] = p.Permission
+	}
+
+	changes := []string{}
+	for _, p := range after {
+		if old, ok := previous[p.Subject]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: %s granted", p.Subject, p.Permission))
+		} else if old != p.Permission {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", p.Subject, old, p.Permission))
+		}
+	}
+	for _, p := range before {
+		if _, ok := current[p.Subject]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: %s removed", p.Subject, p.Permission))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
@@ -0,0 +1,130 @@
+package permissionnotifier
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/services/usernotification/usernotificationtest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService_PermissionsChanged(t *testing.T) {
+	type env struct {
+		bus           bus.Bus
+		notifications *notifications.NotificationServiceMock
+		emails        []*notifications.SendEmailCommand
+		webhooks      chan *notifications.SendWebhookSync
+		inApp         *usernotificationtest.FakeService
+	}
+
+	setup := func(t *testing.T, conf setting.PermissionChangeNotificationsSettings) *env {
+		t.Helper()
+
+		e := &env{
+			bus:      bus.ProvideBus(tracing.InitializeTracerForTest()),
+			webhooks: make(chan *notifications.SendWebhookSync, 1),
+			inApp:    &usernotificationtest.FakeService{},
+		}
+		e.notifications = &notifications.NotificationServiceMock{
+			EmailHandler: func(ctx context.Context, cmd *notifications.SendEmailCommand) error {
+				e.emails = append(e.emails, cmd)
+				return nil
+			},
+			WebhookHandler: func(ctx context.Context, cmd *notifications.SendWebhookSync) error {
+				e.webhooks <- cmd
+				return nil
+			},
+		}
+
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "abc", Slug: "prod", Title: "Prod"}, nil).Maybe()
+
+		cfg := setting.NewCfg()
+		cfg.AppURL = "http://localhost:3000/"
+		cfg.PermissionChangeNotifications = conf
+		ProvideService(cfg, e.bus, e.notifications, e.inApp, dashSvc,
+			&usertest.FakeUserService{ExpectedUser: &user.User{ID: 2, Login: "owner", Email: "owner@example.com"}},
+			&teamtest.FakeService{ExpectedMembers: []*team.TeamMemberDTO{
+				{UserID: 1, Login: "admin", Email: "admin@example.com"},
+				{UserID: 3, Login: "dev", Email: "dev@example.com"},
+			}},
+		)
+		return e
+	}
+
+	evt := &events.ResourcePermissionsChanged{
+		OrgID:      1,
+		Resource:   "dashboards",
+		ResourceID: "abc",
+		ActorID:    1,
+		ActorLogin: "admin",
+		Before: []events.ResourcePermission{
+			{Subject: "role:Viewer", Permission: "View"},
+			{Subject: "user:owner", UserID: 2, Permission: "Admin"},
+		},
+		After: []events.ResourcePermission{
+			{Subject: "role:Viewer", Permission: "Edit"},
+			{Subject: "team:devs", TeamID: 5, Permission: "Admin"},
+			{Subject: "user:owner", UserID: 2, Permission: "Admin"},
+		},
+	}
+
+	t.Run("should notify the owners except the user who made the change", func(t *testing.T) {
+		e := setup(t, setting.PermissionChangeNotificationsSettings{Email: true, InApp: true})
+		require.NoError(t, e.bus.Publish(context.Background(), evt))
+
+		require.Len(t, e.inApp.Created, 2)
+		assert.Equal(t, int64(2), e.inApp.Created[0].UserID)
+		assert.Equal(t, int64(3), e.inApp.Created[1].UserID)
+		assert.Equal(t, KindPermissionsChanged, e.inApp.Created[0].Kind)
+		assert.Equal(t, "Permissions of the dashboard Prod changed", e.inApp.Created[0].Title)
+		assert.Equal(t, "Changed by admin: role:Viewer: View -> Edit, team:devs: Admin granted", e.inApp.Created[0].Text)
+		assert.Equal(t, "/d/abc/prod", e.inApp.Created[0].URL)
+
+		require.Len(t, e.emails, 2)
+		assert.Equal(t, []string{"owner@example.com"}, e.emails[0].To)
+		assert.Equal(t, []string{"dev@example.com"}, e.emails[1].To)
+		assert.Equal(t, "http://localhost:3000/d/abc/prod", e.emails[0].Data["URL"])
+	})
+
+	t.Run("should not notify the initial permissions", func(t *testing.T) {
+		e := setup(t, setting.PermissionChangeNotificationsSettings{Email: true, InApp: true})
+		require.NoError(t, e.bus.Publish(context.Background(), &events.ResourcePermissionsChanged{
+			OrgID: 1, Resource: "dashboards", ResourceID: "abc", After: evt.After,
+		}))
+
+		assert.Empty(t, e.inApp.Created)
+		assert.Empty(t, e.emails)
+	})
+
+	t.Run("should only call the webhook when it's the only notification", func(t *testing.T) {
+		e := setup(t, setting.PermissionChangeNotificationsSettings{WebhookURL: "http://localhost/permissions"})
+		require.NoError(t, e.bus.Publish(context.Background(), evt))
+
+		select {
+		case webhook := <-e.webhooks:
+			assert.Equal(t, "http://localhost/permissions", webhook.Url)
+			assert.Equal(t, http.MethodPost, webhook.HttpMethod)
+			assert.Contains(t, webhook.Body, `"resource_id":"abc"`)
+		case <-time.After(time.Second):
+			t.Fatal("webhook not called")
+		}
+		assert.Empty(t, e.inApp.Created)
+		assert.Empty(t, e.emails)
+	})
+}
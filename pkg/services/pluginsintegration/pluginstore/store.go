@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/manager/loader"
 	"github.com/grafana/grafana/pkg/plugins/manager/registry"
 	"github.com/grafana/grafana/pkg/plugins/manager/sources"
+	"github.com/grafana/grafana/pkg/services/warmup"
 )
 
 var _ Store = (*Service)(nil)
@@ -30,26 +31,68 @@ type Service struct {
 }
 
 func ProvideService(pluginRegistry registry.Service, pluginSources sources.Registry,
-	pluginLoader loader.Service) (*Service, error) {
+	pluginLoader loader.Service, warmupService *warmup.Service) (*Service, error) {
 	ctx := context.Background()
+	logger := log.New("plugin.store")
+
+	srcs := pluginSources.List(ctx)
+	if warmupService == nil {
+		// without the warm-up service the plugins are loaded while Grafana starts
+		if err := loadPluginSources(ctx, logger, pluginLoader, srcs, nil); err != nil {
+			return nil, err
+		}
+		return New(pluginRegistry, pluginLoader), nil
+	}
+
+	if !warmupService.IsDisabled() {
+		// the core plugins are used by the services that start with Grafana, the other plugins are loaded
+		// during the warm-up after the HTTP server has started
+		var core []plugins.PluginSource
+		core, srcs = splitCorePluginSources(ctx, srcs)
+		if err := loadPluginSources(ctx, logger, pluginLoader, core, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := warmupService.Defer(ctx, "plugins", func(ctx context.Context, task *warmup.Task) error {
+		return loadPluginSources(ctx, logger, pluginLoader, srcs, task)
+	}); err != nil {
+		return nil, err
+	}
+
+	return New(pluginRegistry, pluginLoader), nil
+}
+
+func splitCorePluginSources(ctx context.Context, srcs []plugins.PluginSource) ([]plugins.PluginSource, []plugins.PluginSource) {
+	var core, other []plugins.PluginSource
+	for _, ps := range srcs {
+		if ps.PluginClass(ctx) == plugins.ClassCore {
+			core = append(core, ps)
+		} else {
+			other = append(other, ps)
+		}
+	}
+	return core, other
+}
+
+func loadPluginSources(ctx context.Context, logger log.Logger, pluginLoader loader.Service, srcs []plugins.PluginSource, task *warmup.Task) error {
 	start := time.Now()
 	totalPlugins := 0
-	logger := log.New("plugin.store")
 	logger.Info("Loading plugins...")
 
-	for _, ps := range pluginSources.List(ctx) {
+	for i, ps := range srcs {
+		task.SetProgress(i, len(srcs))
 		loadedPlugins, err := pluginLoader.Load(ctx, ps)
 		if err != nil {
 			logger.Error("Loading plugin source failed", "source", ps.PluginClass(ctx), "error", err)
-			return nil, err
+			return err
 		}
 
 		totalPlugins += len(loadedPlugins)
 	}
 
 	logger.Info("Plugins loaded", "count", totalPlugins, "duration", time.Since(start))
-
-	return New(pluginRegistry, pluginLoader), nil
+	return nil
 }
 
 func (s *Service) Run(ctx context.Context) error {
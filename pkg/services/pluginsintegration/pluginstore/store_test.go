@@ -11,6 +11,8 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/plugins/log"
 	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
+	"github.com/grafana/grafana/pkg/services/warmup"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 func TestStore_ProvideService(t *testing.T) {
@@ -44,9 +46,57 @@ func TestStore_ProvideService(t *testing.T) {
 			}
 		}}
 
-		_, err := ProvideService(fakes.NewFakePluginRegistry(), srcs, l)
+		_, err := ProvideService(fakes.NewFakePluginRegistry(), srcs, l, warmup.ProvideService(setting.NewCfg()))
 		require.NoError(t, err)
 		require.Equal(t, []string{"path1", "path2", "path3"}, addedPaths)
+
+		// without the warm-up service the plugins are loaded right away
+		addedPaths = nil
+		_, err = ProvideService(fakes.NewFakePluginRegistry(), srcs, l, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"path1", "path2", "path3"}, addedPaths)
+	})
+
+	t.Run("Only core plugins are loaded before the warm-up when it's deferred", func(t *testing.T) {
+		var addedPaths []string
+		l := &fakes.FakeLoader{
+			LoadFunc: func(ctx context.Context, src plugins.PluginSource) ([]*plugins.Plugin, error) {
+				addedPaths = append(addedPaths, src.PluginURIs(ctx)...)
+				return nil, nil
+			},
+		}
+
+		srcs := &fakes.FakeSourceRegistry{ListFunc: func(_ context.Context) []plugins.PluginSource {
+			return []plugins.PluginSource{
+				&fakes.FakePluginSource{
+					PluginClassFunc: func(ctx context.Context) plugins.Class {
+						return plugins.ClassExternal
+					},
+					PluginURIsFunc: func(ctx context.Context) []string {
+						return []string{"external"}
+					},
+				},
+				&fakes.FakePluginSource{
+					PluginClassFunc: func(ctx context.Context) plugins.Class {
+						return plugins.ClassCore
+					},
+					PluginURIsFunc: func(ctx context.Context) []string {
+						return []string{"core"}
+					},
+				},
+			}
+		}}
+
+		cfg := setting.NewCfg()
+		cfg.DeferredWarmup = true
+		warmupService := warmup.ProvideService(cfg)
+		_, err := ProvideService(fakes.NewFakePluginRegistry(), srcs, l, warmupService)
+		require.NoError(t, err)
+		require.Equal(t, []string{"core"}, addedPaths)
+
+		require.NoError(t, warmupService.Run(context.Background()))
+		require.Equal(t, []string{"core", "external"}, addedPaths)
+		require.True(t, warmupService.Status().Ready)
 	})
 }
 
@@ -82,12 +82,26 @@ type PatchPreferenceCommand struct {
 	Language          *string                 `json:"language,omitempty"`
 	QueryHistory      *QueryHistoryPreference `json:"queryHistory,omitempty"`
 	CookiePreferences []CookieType            `json:"cookiePreferences,omitempty"`
+	TimeRangePresets  *[]TimeRangePreset      `json:"timeRangePresets,omitempty"`
 }
 
 type PreferenceJSONData struct {
 	Language          string                 `json:"language"`
 	QueryHistory      QueryHistoryPreference `json:"queryHistory"`
 	CookiePreferences map[string]struct{}    `json:"cookiePreferences"`
+	TimeRangePresets  []TimeRangePreset      `json:"timeRangePresets,omitempty"`
+}
+
+// TimeRangePreset is a named quick time range. The range is either From and To, absolute times or relative
+// times such as now/M, or the period of Period length that contains the current time, counted from Anchor.
+type TimeRangePreset struct {
+	Name string `json:"name"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Anchor is the start of one of the periods, for example the start of a sprint
+	Anchor *time.Time `json:"anchor,omitempty"`
+	// Period is the length of the periods in days, weeks, months or years, for example 2w or 1M
+	Period string `json:"period,omitempty"`
 }
 
 type QueryHistoryPreference struct {
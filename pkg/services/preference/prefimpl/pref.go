@@ -74,6 +74,10 @@ func (s *Service) GetWithDefaults(ctx context.Context, query *pref.GetPreference
 			if p.JSONData.CookiePreferences != nil {
 				res.JSONData.CookiePreferences = p.JSONData.CookiePreferences
 			}
+
+			if p.JSONData.TimeRangePresets != nil {
+				res.JSONData.TimeRangePresets = p.JSONData.TimeRangePresets
+			}
 		}
 	}
 
@@ -135,6 +139,10 @@ func (s *Service) Save(ctx context.Context, cmd *pref.SavePreferenceCommand) err
 	preference.Updated = time.Now()
 	preference.Version += 1
 	preference.HomeDashboardID = cmd.HomeDashboardID
+	// the time range presets are only saved by patches
+	if preference.JSONData != nil {
+		jsonData.TimeRangePresets = preference.JSONData.TimeRangePresets
+	}
 	preference.JSONData = jsonData
 
 	return s.store.Update(ctx, preference)
@@ -179,6 +187,13 @@ func (s *Service) Patch(ctx context.Context, cmd *pref.PatchPreferenceCommand) e
 		}
 	}
 
+	if cmd.TimeRangePresets != nil {
+		if preference.JSONData == nil {
+			preference.JSONData = &pref.PreferenceJSONData{}
+		}
+		preference.JSONData.TimeRangePresets = *cmd.TimeRangePresets
+	}
+
 	if cmd.HomeDashboardID != nil {
 		preference.HomeDashboardID = *cmd.HomeDashboardID
 	}
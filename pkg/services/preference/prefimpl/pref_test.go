@@ -389,6 +389,22 @@ func TestSave(t *testing.T) {
 		assert.Equal(t, "1", *stored.WeekStart)
 		assert.EqualValues(t, 2, stored.Version)
 	})
+
+	t.Run("save keeps the time range presets", func(t *testing.T) {
+		presets := []pref.TimeRangePreset{{Name: "This month", From: "now/M", To: "now/M"}}
+		err := prefService.Patch(context.Background(), &pref.PatchPreferenceCommand{
+			OrgID:            1,
+			TimeRangePresets: &presets,
+		})
+		require.NoError(t, err)
+
+		err = prefService.Save(context.Background(), &pref.SavePreferenceCommand{OrgID: 1, Theme: "dark"})
+		require.NoError(t, err)
+
+		stored := prefService.store.(*inmemStore).preference[preferenceKey{OrgID: 1}]
+		assert.Equal(t, "dark", stored.Theme)
+		assert.Equal(t, presets, stored.JSONData.TimeRangePresets)
+	})
 }
 
 func insertPrefs(t testing.TB, store store, preferences ...pref.Preference) {
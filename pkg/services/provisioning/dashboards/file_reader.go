@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -37,6 +38,8 @@ type FileReader struct {
 	dashboardStore               utils.DashboardStore
 	FoldersFromFilesStructure    bool
 	folderService                folder.Service
+	// workers is the number of dashboards saved concurrently
+	workers int
 
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
@@ -62,6 +65,15 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
 	}
 
+	workers := 1
+	if value, ok := cfg.Options["workers"]; ok {
+		n, err := optionInt(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("failed to load dashboards, workers param must be a positive integer")
+		}
+		workers = n
+	}
+
 	return &FileReader{
 		Cfg:                          cfg,
 		Path:                         path,
@@ -70,10 +82,27 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		dashboardStore:               dashboardStore,
 		folderService:                folderService,
 		FoldersFromFilesStructure:    foldersFromFilesStructure,
+		workers:                      workers,
 		usageTracker:                 newUsageTracker(),
 	}, nil
 }
 
+// optionInt returns the integer of an option, from YAML or from an interpolated environment variable
+func optionInt(value any) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		return int(v), nil
+	case string:
+		return strconv.Atoi(v)
+	}
+	return 0, fmt.Errorf("unexpected type %T", value)
+}
+
 // pollChanges periodically runs walkDisk based on interval specified in the config.
 func (fr *FileReader) pollChanges(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(int64(time.Second) * fr.Cfg.UpdateIntervalSeconds))
@@ -151,15 +180,11 @@ func (fr *FileReader) storeDashboardsInFolder(ctx context.Context, filesFoundOnD
 	}
 
 	// save dashboards based on json files
+	files := make([]dashboardFile, 0, len(filesFoundOnDisk))
 	for path, fileInfo := range filesFoundOnDisk {
-		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, folderUID, fileInfo, dashboardRefs)
-		if err != nil {
-			fr.log.Error("failed to save dashboard", "file", path, "error", err)
-			continue
-		}
-
-		usageTracker.track(provisioningMetadata)
+		files = append(files, dashboardFile{path: path, fileInfo: fileInfo, folderID: folderID, folderUID: folderUID})
 	}
+	fr.saveDashboards(ctx, files, dashboardRefs, usageTracker)
 	return nil
 }
 
@@ -167,6 +192,13 @@ func (fr *FileReader) storeDashboardsInFolder(ctx context.Context, filesFoundOnD
 // in Grafana as they are in on the filesystem.
 func (fr *FileReader) storeDashboardsInFoldersFromFileStructure(ctx context.Context, filesFoundOnDisk map[string]os.FileInfo,
 	dashboardRefs map[string]*dashboards.DashboardProvisioning, resolvedPath string, usageTracker *usageTracker) error {
+	// the folders are created before saving the dashboards, so that the workers don't create the same folder
+	type folderRef struct {
+		id  int64
+		uid string
+	}
+	folders := map[string]folderRef{}
+	files := make([]dashboardFile, 0, len(filesFoundOnDisk))
 	for path, fileInfo := range filesFoundOnDisk {
 		folderName := ""
 
@@ -175,20 +207,87 @@ func (fr *FileReader) storeDashboardsInFoldersFromFileStructure(ctx context.Cont
 			folderName = filepath.Base(dashboardsFolder)
 		}
 
-		folderID, folderUID, err := fr.getOrCreateFolder(ctx, fr.Cfg, fr.dashboardProvisioningService, folderName)
-		if err != nil && !errors.Is(err, ErrFolderNameMissing) {
-			return fmt.Errorf("can't provision folder %q from file system structure: %w", folderName, err)
+		ref, ok := folders[folderName]
+		if !ok {
+			folderID, folderUID, err := fr.getOrCreateFolder(ctx, fr.Cfg, fr.dashboardProvisioningService, folderName)
+			if err != nil && !errors.Is(err, ErrFolderNameMissing) {
+				return fmt.Errorf("can't provision folder %q from file system structure: %w", folderName, err)
+			}
+			ref = folderRef{id: folderID, uid: folderUID}
+			folders[folderName] = ref
 		}
 
-		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, folderUID, fileInfo, dashboardRefs)
-		usageTracker.track(provisioningMetadata)
-		if err != nil {
-			fr.log.Error("failed to save dashboard", "file", path, "error", err)
-		}
+		files = append(files, dashboardFile{path: path, fileInfo: fileInfo, folderID: ref.id, folderUID: ref.uid})
 	}
+	fr.saveDashboards(ctx, files, dashboardRefs, usageTracker)
 	return nil
 }
 
+// dashboardFile is a dashboard file on disk with the folder it's provisioned in
+type dashboardFile struct {
+	path      string
+	fileInfo  os.FileInfo
+	folderID  int64
+	folderUID string
+}
+
+// saveDashboards saves the dashboard files with the workers of the provisioner, and reports the progress
+// of the run once all the files are processed.
+func (fr *FileReader) saveDashboards(ctx context.Context, files []dashboardFile,
+	dashboardRefs map[string]*dashboards.DashboardProvisioning, usageTracker *usageTracker) {
+	type result struct {
+		path     string
+		metadata provisioningMetadata
+		err      error
+	}
+
+	start := time.Now()
+	jobs := make(chan dashboardFile)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for i := 0; i < fr.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				metadata, err := fr.saveDashboard(ctx, file.path, file.folderID, file.folderUID, file.fileInfo, dashboardRefs)
+				results <- result{path: file.path, metadata: metadata, err: err}
+			}
+		}()
+	}
+	go func() {
+		for _, file := range files {
+			jobs <- file
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	saved, failed := 0, 0
+	for r := range results {
+		// the dashboards failing to save are only tracked with the folders from the file structure
+		if r.err == nil || fr.FoldersFromFilesStructure {
+			usageTracker.track(r.metadata)
+		}
+		switch {
+		case r.err != nil:
+			failed++
+			fr.log.Error("failed to save dashboard", "file", r.path, "error", r.err)
+		case r.metadata.saved:
+			saved++
+		}
+	}
+
+	logArgs := []any{"provisioner", fr.Cfg.Name, "files", len(files), "saved", saved, "upToDate", len(files) - saved - failed,
+		"failed", failed, "workers", fr.workers, "duration", time.Since(start)}
+	if saved > 0 || failed > 0 {
+		fr.log.Info("Provisioned dashboards", logArgs...)
+	} else {
+		fr.log.Debug("Provisioned dashboards", logArgs...)
+	}
+}
+
 // handleMissingDashboardFiles will unprovision or delete dashboards which are missing on disk.
 func (fr *FileReader) handleMissingDashboardFiles(ctx context.Context, provisionedDashboardRefs map[string]*dashboards.DashboardProvisioning,
 	filesFoundOnDisk map[string]os.FileInfo) {
@@ -236,8 +335,7 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 
 	jsonFile, err := fr.readDashboardFromFile(path, resolvedFileInfo.ModTime(), folderID, folderUID)
 	if err != nil {
-		fr.log.Error("failed to load dashboard from ", "file", path, "error", err)
-		return provisioningMetadata, nil
+		return provisioningMetadata, fmt.Errorf("failed to load dashboard: %w", err)
 	}
 
 	upToDate := alreadyProvisioned
@@ -307,6 +405,7 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 		if err != nil {
 			return provisioningMetadata, err
 		}
+		provisioningMetadata.saved = true
 	} else {
 		metrics.MFolderIDsServiceCount.WithLabelValues(metrics.Provisioning).Inc()
 		// nolint:staticcheck
@@ -501,6 +600,8 @@ func (fr *FileReader) getUsageTracker() *usageTracker {
 type provisioningMetadata struct {
 	uid      string
 	identity dashboardIdentity
+	// saved is true when the dashboard was written to the database
+	saved bool
 }
 
 type dashboardIdentity struct {
@@ -64,6 +64,28 @@ func TestCreatingNewDashboardFileReader(t *testing.T) {
 		require.NotEqual(t, reader.Path, "")
 	})
 
+	t.Run("using workers as options", func(t *testing.T) {
+		cfg := setup()
+		cfg.Options["path"] = defaultDashboards
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, reader.workers)
+
+		cfg.Options["workers"] = 8
+		reader, err = NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, 8, reader.workers)
+
+		cfg.Options["workers"] = "4"
+		reader, err = NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, 4, reader.workers)
+
+		cfg.Options["workers"] = 0
+		_, err = NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil, nil)
+		require.Error(t, err)
+	})
+
 	t.Run("using full path", func(t *testing.T) {
 		cfg := setup()
 		fullPath := "/var/lib/grafana/dashboards"
@@ -305,6 +327,25 @@ func TestDashboardFileReader(t *testing.T) {
 			require.NoError(t, err)
 		})
 
+		t.Run("Can read dashboards with several workers", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = foldersFromFilesStructure
+			cfg.Options["foldersFromFilesStructure"] = true
+			cfg.Options["workers"] = 3
+
+			fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil).Once()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&folder.Folder{}, nil).Times(2)
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&dashboards.Dashboard{}, nil).Times(3)
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore, nil)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			require.Len(t, reader.getUsageTracker().titleUsage, 3)
+		})
+
 		t.Run("Invalid configuration should return error", func(t *testing.T) {
 			setup()
 			cfg := &config{
@@ -31,6 +31,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/warmup"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -52,6 +53,7 @@ func ProvideService(
 	quotaService quota.Service,
 	secrectService secrets.Service,
 	orgService org.Service,
+	warmupService *warmup.Service,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -77,6 +79,10 @@ func ProvideService(
 		log:                          log.New("provisioning"),
 		orgService:                   orgService,
 		folderService:                folderService,
+		warmup:                       warmupService,
+	}
+	if warmupService != nil {
+		s.dashboardsWarmupTask = warmupService.Track("dashboard_provisioning")
 	}
 	return s, nil
 }
@@ -148,9 +154,23 @@ type ProvisioningServiceImpl struct {
 	quotaService                 quota.Service
 	secretService                secrets.Service
 	folderService                folder.Service
+	warmup                       *warmup.Service
+	// dashboardsWarmupTask reports the progress of the initial dashboard provisioning
+	dashboardsWarmupTask *warmup.Task
 }
 
+// RunInitProvisioners provisions the data sources, plugins, notifiers and alerting resources.
+// With deferred_warmup they are provisioned during the warm-up, after the HTTP server has started.
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
+	if ps.warmup == nil {
+		return ps.runInitProvisioners(ctx)
+	}
+	return ps.warmup.Defer(ctx, "provisioning", func(ctx context.Context, _ *warmup.Task) error {
+		return ps.runInitProvisioners(ctx)
+	})
+}
+
+func (ps *ProvisioningServiceImpl) runInitProvisioners(ctx context.Context) error {
 	err := ps.ProvisionDatasources(ctx)
 	if err != nil {
 		ps.log.Error("Failed to provision data sources", "error", err)
@@ -179,7 +199,15 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 }
 
 func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
+	if ps.warmup != nil {
+		// the dashboards are provisioned once their data sources and plugins are
+		if err := ps.warmup.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
 	err := ps.ProvisionDashboards(ctx)
+	ps.dashboardsWarmupTask.Finish(err)
 	if err != nil {
 		ps.log.Error("Failed to provision dashboard", "error", err)
 		return err
@@ -281,6 +309,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		int64(ps.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ps.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
 		ps.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit,
+		ps.Cfg.Quota.FolderAlertRuleLimit(),
 		ps.log, notifier.NewCachedNotificationSettingsValidationService(&st))
 	receiverSvc := notifier.NewReceiverService(ps.ac, &st, st, ps.secretService, ps.SQLStore, ps.log)
 	contactPointService := provisioning.NewContactPointService(&st, ps.secretService,
@@ -50,6 +50,7 @@ func (api *Api) ViewPublicDashboard(c *contextmodel.ReqContext) response.Respons
 // 404: panelNotFoundPublicError
 // 404: notFoundPublicError
 // 403: forbiddenPublicError
+// 429: tooManyRequestsPublicError
 // 500: internalServerPublicError
 func (api *Api) QueryPublicDashboard(c *contextmodel.ReqContext) response.Response {
 	accessToken := web.Params(c.Req)[":accessToken"]
@@ -66,12 +67,18 @@ func (api *Api) QueryPublicDashboard(c *contextmodel.ReqContext) response.Respon
 	if err = web.Bind(c.Req, &reqDTO); err != nil {
 		return response.Err(ErrBadRequest.Errorf("QueryPublicDashboard: error parsing request: %v", err))
 	}
+	reqDTO.ViewerId = c.RemoteAddr()
 
-	resp, err := api.PublicDashboardService.GetQueryDataResponse(c.Req.Context(), c.SkipDSCache, reqDTO, panelId, accessToken)
+	resp, cacheStatus, err := api.PublicDashboardService.GetQueryDataResponse(c.Req.Context(), c.SkipDSCache, reqDTO, panelId, accessToken)
 	if err != nil {
 		return response.Err(err)
 	}
 
+	c.Resp.Header().Set("X-Cache", string(cacheStatus.State))
+	if cacheStatus.State == QueryCacheHit {
+		c.Resp.Header().Set("Age", strconv.FormatInt(int64(cacheStatus.Age.Seconds()), 10))
+	}
+
 	return toJsonStreamingResponse(c.Req.Context(), api.features, resp)
 }
 
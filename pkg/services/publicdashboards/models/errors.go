@@ -20,9 +20,14 @@ var (
 	ErrInvalidMaxDataPoints                = errutil.BadRequest("publicdashboards.maxDataPoints", errutil.WithPublicMessage("maxDataPoints should be greater than 0"))
 	ErrInvalidTimeRange                    = errutil.BadRequest("publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
 	ErrInvalidShareType                    = errutil.BadRequest("publicdashboards.invalidShareType", errutil.WithPublicMessage("Invalid share type"))
+	ErrInvalidQueryCacheInterval           = errutil.BadRequest("publicdashboards.invalidQueryCacheInterval", errutil.WithPublicMessage("Invalid query cache interval"))
 	ErrDashboardIsPublic                   = errutil.BadRequest("publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
 	ErrPublicDashboardUidExists            = errutil.BadRequest("publicdashboards.uidExists", errutil.WithPublicMessage("Public Dashboard Uid already exists"))
 	ErrPublicDashboardAccessTokenExists    = errutil.BadRequest("publicdashboards.accessTokenExists", errutil.WithPublicMessage("Public Dashboard Access Token already exists"))
+	ErrInvalidThrottleLimit                = errutil.BadRequest("publicdashboards.invalidThrottleLimit", errutil.WithPublicMessage("Invalid query rate limit or concurrent viewers limit"))
 
 	ErrPublicDashboardNotEnabled = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
+
+	ErrQueryRateLimited = errutil.TooManyRequests("publicdashboards.queryRateLimited", errutil.WithPublicMessage("Too many queries to the public dashboard, try again later"))
+	ErrTooManyViewers   = errutil.TooManyRequests("publicdashboards.tooManyViewers", errutil.WithPublicMessage("Too many viewers of the public dashboard, try again later"))
 )
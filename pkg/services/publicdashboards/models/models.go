@@ -26,6 +26,9 @@ func (e PublicDashboardErr) Error() string {
 const (
 	QuerySuccess                                  = "success"
 	QueryFailure                                  = "failure"
+	ThrottleReasonQueryRate                       = "query_rate"
+	ThrottleReasonViewers                         = "viewers"
+	ThrottleUnlimited                             = -1
 	EmailShareType                      ShareType = "email"
 	PublicShareType                     ShareType = "public"
 	FeaturePublicDashboardsEmailSharing           = "publicDashboardsEmailSharing"
@@ -33,6 +36,7 @@ const (
 
 var (
 	QueryResultStatuses = []string{QuerySuccess, QueryFailure}
+	ThrottleReasons     = []string{ThrottleReasonQueryRate, ThrottleReasonViewers}
 	ValidShareTypes     = []ShareType{EmailShareType, PublicShareType}
 )
 
@@ -53,7 +57,14 @@ type PublicDashboard struct {
 	IsEnabled            bool          `json:"isEnabled" xorm:"is_enabled"`
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	Share                ShareType     `json:"share" xorm:"share"`
-	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	// QueryCacheInterval is the number of seconds the query results are cached, 0 for the server default
+	// and QueryCacheIntervalDisabled to disable the cache
+	QueryCacheInterval int64 `json:"queryCacheInterval" xorm:"query_cache_interval"`
+	// QueryRateLimit is the number of queries per minute and MaxConcurrentViewers the number of viewers, 0 for the
+	// server default and ThrottleUnlimited for no limit
+	QueryRateLimit       int64      `json:"queryRateLimit" xorm:"query_rate_limit"`
+	MaxConcurrentViewers int64      `json:"maxConcurrentViewers" xorm:"max_concurrent_viewers"`
+	Recipients           []EmailDTO `json:"recipients,omitempty" xorm:"-"`
 }
 
 type PublicDashboardDTO struct {
@@ -63,6 +74,9 @@ type PublicDashboardDTO struct {
 	IsEnabled            *bool     `json:"isEnabled"`
 	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
 	Share                ShareType `json:"share"`
+	QueryCacheInterval   *int64    `json:"queryCacheInterval"`
+	QueryRateLimit       *int64    `json:"queryRateLimit"`
+	MaxConcurrentViewers *int64    `json:"maxConcurrentViewers"`
 }
 
 type EmailDTO struct {
@@ -154,6 +168,29 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// ViewerId tells apart the viewers of the public dashboard for the limit of concurrent viewers
+	ViewerId string `json:"-"`
+}
+
+// QueryCacheState is whether the query results were served from the cache
+type QueryCacheState string
+
+const (
+	QueryCacheHit      QueryCacheState = "HIT"
+	QueryCacheMiss     QueryCacheState = "MISS"
+	QueryCacheDisabled QueryCacheState = "DISABLED"
+
+	// QueryCacheIntervalDisabled disables the query cache of a public dashboard
+	QueryCacheIntervalDisabled = -1
+	// MaxQueryCacheInterval is the maximum number of seconds the query results of a public dashboard are cached
+	MaxQueryCacheInterval = 3600
+)
+
+// QueryCacheStatus is the cache status of the query results of a public dashboard panel
+type QueryCacheStatus struct {
+	State QueryCacheState
+	// Age is how long ago the cached results were queried
+	Age time.Duration
 }
 
 type AnnotationsQueryDTO struct {
@@ -426,12 +426,13 @@ func (_m *FakePublicDashboardService) GetPublicDashboardForView(ctx context.Cont
 }
 
 // GetQueryDataResponse provides a mock function with given fields: ctx, skipDSCache, reqDTO, panelId, accessToken
-func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context, skipDSCache bool, reqDTO models.PublicDashboardQueryDTO, panelId int64, accessToken string) (*backend.QueryDataResponse, error) {
+func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context, skipDSCache bool, reqDTO models.PublicDashboardQueryDTO, panelId int64, accessToken string) (*backend.QueryDataResponse, models.QueryCacheStatus, error) {
 	ret := _m.Called(ctx, skipDSCache, reqDTO, panelId, accessToken)
 
 	var r0 *backend.QueryDataResponse
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, bool, models.PublicDashboardQueryDTO, int64, string) (*backend.QueryDataResponse, error)); ok {
+	var r1 models.QueryCacheStatus
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool, models.PublicDashboardQueryDTO, int64, string) (*backend.QueryDataResponse, models.QueryCacheStatus, error)); ok {
 		return rf(ctx, skipDSCache, reqDTO, panelId, accessToken)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, bool, models.PublicDashboardQueryDTO, int64, string) *backend.QueryDataResponse); ok {
@@ -442,13 +443,19 @@ func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context,
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, bool, models.PublicDashboardQueryDTO, int64, string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, bool, models.PublicDashboardQueryDTO, int64, string) models.QueryCacheStatus); ok {
 		r1 = rf(ctx, skipDSCache, reqDTO, panelId, accessToken)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(models.QueryCacheStatus)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, bool, models.PublicDashboardQueryDTO, int64, string) error); ok {
+		r2 = rf(ctx, skipDSCache, reqDTO, panelId, accessToken)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // NewPublicDashboardAccessToken provides a mock function with given fields: ctx
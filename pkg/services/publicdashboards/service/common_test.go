@@ -40,6 +40,7 @@ func newPublicDashboardServiceImpl(
 	license.On("FeatureEnabled", FeaturePublicDashboardsEmailSharing).Return(false)
 
 	return &PublicDashboardServiceImpl{
+		cfg:                sqlStore.Cfg,
 		AnnotationsRepo:    annotationsRepo,
 		log:                log.New("test.logger"),
 		intervalCalculator: intervalv2.NewCalculator(),
@@ -47,5 +48,7 @@ func newPublicDashboardServiceImpl(
 		store:              publicDashboardStore,
 		serviceWrapper:     serviceWrapper,
 		license:            license,
+		queryCache:         newQueryCache(1000),
+		throttle:           newQueryThrottle(),
 	}, sqlStore
 }
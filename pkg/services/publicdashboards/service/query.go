@@ -122,22 +122,52 @@ func (pd *PublicDashboardServiceImpl) GetMetricRequest(ctx context.Context, dash
 	return metricReqDTO, nil
 }
 
-// GetQueryDataResponse returns a query data response for the given panel and query
-func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context, skipDSCache bool, queryDto models.PublicDashboardQueryDTO, panelId int64, accessToken string) (*backend.QueryDataResponse, error) {
+// GetQueryDataResponse returns a query data response for the given panel and query, from the query cache when it's
+// enabled for the public dashboard. The queries over the limits of the public dashboard are rejected.
+func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context, skipDSCache bool, queryDto models.PublicDashboardQueryDTO, panelId int64, accessToken string) (*backend.QueryDataResponse, models.QueryCacheStatus, error) {
+	disabled := models.QueryCacheStatus{State: models.QueryCacheDisabled}
 	publicDashboard, dashboard, err := pd.FindEnabledPublicDashboardAndDashboardByAccessToken(ctx, accessToken)
 	if err != nil {
-		return nil, err
+		return nil, disabled, err
 	}
 
 	metricReq, err := pd.GetMetricRequest(ctx, dashboard, publicDashboard, panelId, queryDto)
 	if err != nil {
-		return nil, err
+		return nil, disabled, err
 	}
 
 	if len(metricReq.Queries) == 0 {
-		return nil, models.ErrPanelQueriesNotFound.Errorf("GetQueryDataResponse: failed to extract queries from panel")
+		return nil, disabled, models.ErrPanelQueriesNotFound.Errorf("GetQueryDataResponse: failed to extract queries from panel")
 	}
 
+	queryRateLimit, maxViewers := pd.throttleLimits(publicDashboard)
+	if pd.throttle != nil && queryDto.ViewerId != "" {
+		if err := pd.throttle.admitViewer(publicDashboard.Uid, queryDto.ViewerId, maxViewers); err != nil {
+			return nil, disabled, err
+		}
+	}
+
+	query := func(ctx context.Context) (*backend.QueryDataResponse, error) {
+		// only the queries to the data sources count, not the results served from the cache
+		if pd.throttle != nil {
+			if err := pd.throttle.allowQuery(publicDashboard.Uid, queryRateLimit); err != nil {
+				return nil, err
+			}
+		}
+		return pd.queryData(ctx, dashboard, skipDSCache, metricReq)
+	}
+
+	interval := pd.queryCacheInterval(publicDashboard)
+	if interval <= 0 {
+		res, err := query(ctx)
+		return res, disabled, err
+	}
+
+	// the cache is shared by the anonymous viewers, they can't skip it to query the data sources
+	return pd.queryCache.get(ctx, queryCacheKey(publicDashboard, dashboard, panelId, queryDto), interval, query)
+}
+
+func (pd *PublicDashboardServiceImpl) queryData(ctx context.Context, dashboard *dashboards.Dashboard, skipDSCache bool, metricReq dtos.MetricRequest) (*backend.QueryDataResponse, error) {
 	anonymousUser := buildAnonymousUser(ctx, dashboard)
 	res, err := pd.QueryDataService.QueryData(ctx, anonymousUser, skipDSCache, metricReq)
 
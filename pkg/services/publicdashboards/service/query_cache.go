@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	gocache "github.com/patrickmn/go-cache"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// queryCache keeps the query results of the public dashboard panels, so the viewers of a public dashboard share
+// the results instead of querying the data sources each. The viewers requesting the same results at the same time
+// wait for a single query.
+type queryCache struct {
+	cache      *gocache.Cache
+	group      singleflight.Group
+	maxEntries int
+}
+
+type cachedQueryResult struct {
+	resp    *backend.QueryDataResponse
+	queried time.Time
+}
+
+func newQueryCache(maxEntries int) *queryCache {
+	return &queryCache{
+		cache:      gocache.New(gocache.NoExpiration, 5*time.Minute),
+		maxEntries: maxEntries,
+	}
+}
+
+// get returns the cached results of the key, or queries them and caches them for the interval. The results with
+// errors aren't cached, so a failing data source is queried again by the next viewer.
+func (c *queryCache) get(ctx context.Context, key string, interval time.Duration, query func(ctx context.Context) (*backend.QueryDataResponse, error)) (*backend.QueryDataResponse, models.QueryCacheStatus, error) {
+	if item, ok := c.cache.Get(key); ok {
+		result := item.(*cachedQueryResult)
+		return result.resp, models.QueryCacheStatus{State: models.QueryCacheHit, Age: time.Since(result.queried)}, nil
+	}
+
+	v, err, _ := c.group.Do(key, func() (any, error) {
+		// the query is shared by the waiting viewers, it isn't canceled when the first one goes away
+		resp, err := query(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if !hasQueryErrors(resp) && c.cache.ItemCount() < c.maxEntries {
+			c.cache.Set(key, &cachedQueryResult{resp: resp, queried: time.Now()}, interval)
+		}
+		return resp, nil
+	})
+	if err != nil {
+		return nil, models.QueryCacheStatus{State: models.QueryCacheMiss}, err
+	}
+	return v.(*backend.QueryDataResponse), models.QueryCacheStatus{State: models.QueryCacheMiss}, nil
+}
+
+func hasQueryErrors(resp *backend.QueryDataResponse) bool {
+	for _, res := range resp.Responses {
+		if res.Error != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// queryCacheInterval returns how long the query results of the public dashboard are cached, 0 when they aren't
+func (pd *PublicDashboardServiceImpl) queryCacheInterval(publicDashboard *models.PublicDashboard) time.Duration {
+	if pd.queryCache == nil {
+		return 0
+	}
+	switch {
+	case publicDashboard.QueryCacheInterval == models.QueryCacheIntervalDisabled:
+		return 0
+	case publicDashboard.QueryCacheInterval > 0:
+		return time.Duration(publicDashboard.QueryCacheInterval) * time.Second
+	default:
+		return pd.cfg.PublicDashboardsQueryCacheInterval
+	}
+}
+
+// queryCacheKey returns the key of the query results of a panel. The key has the time range requested rather than
+// the resolved one, so the relative time ranges are refreshed once per interval. The versions of the dashboard and
+// of the public dashboard invalidate the results when either is saved.
+func queryCacheKey(publicDashboard *models.PublicDashboard, dashboard *dashboards.Dashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) string {
+	from, to, timezone := getTimeRangeValuesOrDefault(reqDTO, dashboard, publicDashboard.TimeSelectionEnabled)
+	return fmt.Sprintf("%s/%d/%d/%d/%s/%s/%s/%d/%d/%d",
+		publicDashboard.AccessToken,
+		publicDashboard.UpdatedAt.UnixNano(),
+		dashboard.Version,
+		panelId,
+		from,
+		to,
+		timezone,
+		reqDTO.IntervalMs,
+		reqDTO.MaxDataPoints,
+		reqDTO.QueryCachingTTL,
+	)
+}
@@ -22,6 +22,7 @@ import (
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	secretstest "github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 	"github.com/grafana/grafana/pkg/util"
@@ -682,7 +683,7 @@ func TestGetQueryDataResponse(t *testing.T) {
 	fakeQueryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{}, nil)
 	service.QueryDataService = fakeQueryService
 
-	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil))
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil), secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 
 	publicDashboardQueryDTO := PublicDashboardQueryDTO{
@@ -724,11 +725,90 @@ func TestGetQueryDataResponse(t *testing.T) {
 		pubdashDto, err := service.Create(context.Background(), SignedInUser, dto)
 		require.NoError(t, err)
 
-		resp, _ := service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdashDto.AccessToken)
+		resp, _, _ := service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdashDto.AccessToken)
 		require.NotNil(t, resp)
 	})
 }
 
+func TestGetQueryDataResponseFromQueryCache(t *testing.T) {
+	fakeDashboardService := &dashboards.FakeDashboardService{}
+	service, sqlStore := newPublicDashboardServiceImpl(t, nil, fakeDashboardService, nil)
+
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil), secretstest.NewFakeSecretsService())
+	require.NoError(t, err)
+
+	publicDashboardQueryDTO := PublicDashboardQueryDTO{
+		IntervalMs:    int64(1),
+		MaxDataPoints: int64(1),
+	}
+
+	t.Run("Returns the cached query data until the query cache interval is over", func(t *testing.T) {
+		customPanels := []interface{}{
+			map[string]interface{}{
+				"id": 1,
+				"datasource": map[string]interface{}{
+					"uid": "ds1",
+				},
+				"targets": []interface{}{map[string]interface{}{"refId": "A"}},
+			}}
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashWithQueryCache", 1, 0, "", true, []map[string]interface{}{}, customPanels)
+		fakeDashboardService.On("GetDashboard", mock.Anything, mock.Anything, mock.Anything).Return(dashboard, nil)
+
+		cachedQueryService := &query.FakeQueryService{}
+		cachedQueryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{}, nil)
+		service.QueryDataService = cachedQueryService
+
+		isEnabled := true
+		queryCacheInterval := int64(60)
+		pubdash, err := service.Create(context.Background(), SignedInUser, &SavePublicDashboardDTO{
+			DashboardUid: dashboard.UID,
+			UserId:       7,
+			OrgID:        dashboard.OrgID,
+			PublicDashboard: &PublicDashboardDTO{
+				IsEnabled:          &isEnabled,
+				QueryCacheInterval: &queryCacheInterval,
+			},
+		})
+		require.NoError(t, err)
+
+		_, status, err := service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdash.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, QueryCacheMiss, status.State)
+
+		resp, status, err := service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdash.AccessToken)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, QueryCacheHit, status.State)
+		cachedQueryService.AssertNumberOfCalls(t, "QueryData", 1)
+
+		// another time range is queried again
+		otherQueryDTO := publicDashboardQueryDTO
+		otherQueryDTO.MaxDataPoints = 2
+		_, status, err = service.GetQueryDataResponse(context.Background(), true, otherQueryDTO, 1, pubdash.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, QueryCacheMiss, status.State)
+		cachedQueryService.AssertNumberOfCalls(t, "QueryData", 2)
+
+		disabled := int64(QueryCacheIntervalDisabled)
+		pubdash, err = service.Update(context.Background(), SignedInUser, &SavePublicDashboardDTO{
+			Uid:          pubdash.Uid,
+			DashboardUid: dashboard.UID,
+			UserId:       7,
+			OrgID:        dashboard.OrgID,
+			PublicDashboard: &PublicDashboardDTO{
+				QueryCacheInterval: &disabled,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(QueryCacheIntervalDisabled), pubdash.QueryCacheInterval)
+
+		_, status, err = service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdash.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, QueryCacheDisabled, status.State)
+		cachedQueryService.AssertNumberOfCalls(t, "QueryData", 3)
+	})
+}
+
 func TestFindAnnotations(t *testing.T) {
 	color := "red"
 	name := "annoName"
@@ -1084,7 +1164,7 @@ func TestFindAnnotations(t *testing.T) {
 
 func TestGetMetricRequest(t *testing.T) {
 	service, sqlStore := newPublicDashboardServiceImpl(t, nil, nil, nil)
-	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil))
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil), secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 	dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, "", true, []map[string]interface{}{}, nil)
 
@@ -1166,7 +1246,7 @@ func TestBuildMetricRequest(t *testing.T) {
 	fakeDashboardService := &dashboards.FakeDashboardService{}
 	service, sqlStore := newPublicDashboardServiceImpl(t, nil, fakeDashboardService, nil)
 
-	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil))
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil), secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 	publicDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, "", true, []map[string]interface{}{}, nil)
 	nonPublicDashboard := insertTestDashboard(t, dashboardStore, "testNonPublicDashie", 1, 0, "", true, []map[string]interface{}{}, nil)
@@ -1320,7 +1400,7 @@ func TestBuildMetricRequest(t *testing.T) {
 
 func TestBuildAnonymousUser(t *testing.T) {
 	sqlStore := db.InitTestDB(t)
-	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil))
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil), secretstest.NewFakeSecretsService())
 	require.NoError(t, err)
 	dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, "", true, []map[string]interface{}{}, nil)
 
@@ -40,6 +40,8 @@ type PublicDashboardServiceImpl struct {
 	serviceWrapper     publicdashboards.ServiceWrapper
 	dashboardService   dashboards.DashboardService
 	license            licensing.Licensing
+	queryCache         *queryCache
+	throttle           *queryThrottle
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -71,6 +73,8 @@ func ProvideService(
 		serviceWrapper:     serviceWrapper,
 		dashboardService:   dashboardService,
 		license:            license,
+		queryCache:         newQueryCache(cfg.PublicDashboardsQueryCacheMaxEntries),
+		throttle:           newQueryThrottle(),
 	}
 }
 
@@ -100,6 +104,7 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
 	sanitizeData(dash.Data)
+	dashboards.RedactSensitiveFields(pd.cfg.SensitiveDashboardPaths, dash.Data)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
 }
@@ -493,6 +498,9 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 	isEnabled := returnValueOrDefault(dto.PublicDashboard.IsEnabled, false)
 	annotationsEnabled := returnValueOrDefault(dto.PublicDashboard.AnnotationsEnabled, false)
 	timeSelectionEnabled := returnValueOrDefault(dto.PublicDashboard.TimeSelectionEnabled, false)
+	queryCacheInterval := returnValueOrDefault(dto.PublicDashboard.QueryCacheInterval, 0)
+	queryRateLimit := returnValueOrDefault(dto.PublicDashboard.QueryRateLimit, 0)
+	maxConcurrentViewers := returnValueOrDefault(dto.PublicDashboard.MaxConcurrentViewers, 0)
 
 	share := dto.PublicDashboard.Share
 	if dto.PublicDashboard.Share == "" {
@@ -510,6 +518,9 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         &TimeSettings{},
 		Share:                share,
+		QueryCacheInterval:   queryCacheInterval,
+		QueryRateLimit:       queryRateLimit,
+		MaxConcurrentViewers: maxConcurrentViewers,
 		CreatedBy:            dto.UserId,
 		CreatedAt:            now,
 		UpdatedBy:            dto.UserId,
@@ -523,6 +534,9 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 	timeSelectionEnabled := returnValueOrDefault(pubdashDTO.TimeSelectionEnabled, pd.TimeSelectionEnabled)
 	isEnabled := returnValueOrDefault(pubdashDTO.IsEnabled, pd.IsEnabled)
 	annotationsEnabled := returnValueOrDefault(pubdashDTO.AnnotationsEnabled, pd.AnnotationsEnabled)
+	queryCacheInterval := returnValueOrDefault(pubdashDTO.QueryCacheInterval, pd.QueryCacheInterval)
+	queryRateLimit := returnValueOrDefault(pubdashDTO.QueryRateLimit, pd.QueryRateLimit)
+	maxConcurrentViewers := returnValueOrDefault(pubdashDTO.MaxConcurrentViewers, pd.MaxConcurrentViewers)
 
 	share := pubdashDTO.Share
 	if pubdashDTO.Share == "" {
@@ -536,12 +550,15 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         pd.TimeSettings,
 		Share:                share,
+		QueryCacheInterval:   queryCacheInterval,
+		QueryRateLimit:       queryRateLimit,
+		MaxConcurrentViewers: maxConcurrentViewers,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            time.Now(),
 	}
 }
 
-func returnValueOrDefault(value *bool, defaultValue bool) bool {
+func returnValueOrDefault[T any](value *T, defaultValue T) T {
 	if value != nil {
 		return *value
 	}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// viewerTimeout is how long a viewer of a public dashboard counts after its last query
+const viewerTimeout = time.Minute
+
+// queryThrottle limits the queries to the data sources and the concurrent viewers of each public dashboard, so a
+// public dashboard shared widely can't overload its data sources.
+type queryThrottle struct {
+	mu         sync.Mutex
+	dashboards map[string]*dashboardThrottle
+}
+
+type dashboardThrottle struct {
+	queryRateLimit int64
+	limiter        *rate.Limiter
+	// viewers are the last query times of the viewers
+	viewers map[string]time.Time
+}
+
+func newQueryThrottle() *queryThrottle {
+	return &queryThrottle{dashboards: make(map[string]*dashboardThrottle)}
+}
+
+// admitViewer returns ErrTooManyViewers when the viewer is new and the public dashboard has the maximum number of
+// viewers already. The current viewers are admitted, so they keep their access once the limit is reached.
+func (t *queryThrottle) admitViewer(publicDashboardUid string, viewerId string, maxViewers int64) error {
+	if maxViewers <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	d := t.dashboard(publicDashboardUid)
+	now := time.Now()
+	for viewer, seen := range d.viewers {
+		if now.Sub(seen) > viewerTimeout {
+			delete(d.viewers, viewer)
+		}
+	}
+	if _, ok := d.viewers[viewerId]; !ok && int64(len(d.viewers)) >= maxViewers {
+		metrics.MPublicDashboardThrottledTotal.WithLabelValues(models.ThrottleReasonViewers).Inc()
+		return models.ErrTooManyViewers.Errorf("admitViewer: public dashboard %s has %d viewers", publicDashboardUid, len(d.viewers))
+	}
+	d.viewers[viewerId] = now
+	return nil
+}
+
+// allowQuery returns ErrQueryRateLimited when the public dashboard has queried its data sources the maximum number
+// of times in the last minute. The queries can burst up to the limit, e.g. when a viewer loads all the panels.
+func (t *queryThrottle) allowQuery(publicDashboardUid string, queryRateLimit int64) error {
+	if queryRateLimit <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	d := t.dashboard(publicDashboardUid)
+	if d.limiter == nil || d.queryRateLimit != queryRateLimit {
+		d.queryRateLimit = queryRateLimit
+		d.limiter = rate.NewLimiter(rate.Limit(float64(queryRateLimit)/time.Minute.Seconds()), int(queryRateLimit))
+	}
+	if !d.limiter.Allow() {
+		metrics.MPublicDashboardThrottledTotal.WithLabelValues(models.ThrottleReasonQueryRate).Inc()
+		return models.ErrQueryRateLimited.Errorf("allowQuery: public dashboard %s is over %d queries per minute", publicDashboardUid, queryRateLimit)
+	}
+	return nil
+}
+
+// dashboard returns the throttle of the public dashboard, the lock must be held
+func (t *queryThrottle) dashboard(publicDashboardUid string) *dashboardThrottle {
+	d, ok := t.dashboards[publicDashboardUid]
+	if !ok {
+		d = &dashboardThrottle{viewers: make(map[string]time.Time)}
+		t.dashboards[publicDashboardUid] = d
+	}
+	return d
+}
+
+// throttleLimits returns the query rate limit and the concurrent viewers limit of the public dashboard, 0 for no limit
+func (pd *PublicDashboardServiceImpl) throttleLimits(publicDashboard *models.PublicDashboard) (int64, int64) {
+	limit := func(value int64, defaultValue int64) int64 {
+		switch {
+		case value == models.ThrottleUnlimited:
+			return 0
+		case value > 0:
+			return value
+		default:
+			return defaultValue
+		}
+	}
+	return limit(publicDashboard.QueryRateLimit, pd.cfg.PublicDashboardsQueryRateLimit),
+		limit(publicDashboard.MaxConcurrentViewers, pd.cfg.PublicDashboardsMaxConcurrentViewers)
+}
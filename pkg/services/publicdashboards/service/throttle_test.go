@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestQueryThrottle(t *testing.T) {
+	t.Run("Rejects the queries over the query rate limit of the public dashboard", func(t *testing.T) {
+		throttle := newQueryThrottle()
+		for i := 0; i < 3; i++ {
+			require.NoError(t, throttle.allowQuery("pubdash1", 3))
+		}
+		assert.ErrorIs(t, throttle.allowQuery("pubdash1", 3), ErrQueryRateLimited)
+
+		// the limits are kept for each public dashboard
+		assert.NoError(t, throttle.allowQuery("pubdash2", 3))
+		// a new limit applies right away
+		assert.NoError(t, throttle.allowQuery("pubdash1", 10))
+		assert.NoError(t, throttle.allowQuery("pubdash1", 0))
+	})
+
+	t.Run("Rejects the new viewers over the concurrent viewers limit of the public dashboard", func(t *testing.T) {
+		throttle := newQueryThrottle()
+		require.NoError(t, throttle.admitViewer("pubdash1", "viewer1", 2))
+		require.NoError(t, throttle.admitViewer("pubdash1", "viewer2", 2))
+		assert.ErrorIs(t, throttle.admitViewer("pubdash1", "viewer3", 2), ErrTooManyViewers)
+
+		// the current viewers keep their access
+		assert.NoError(t, throttle.admitViewer("pubdash1", "viewer1", 2))
+		assert.NoError(t, throttle.admitViewer("pubdash2", "viewer3", 2))
+	})
+
+	t.Run("Admits a new viewer once a viewer is gone", func(t *testing.T) {
+		throttle := newQueryThrottle()
+		require.NoError(t, throttle.admitViewer("pubdash1", "viewer1", 1))
+		throttle.dashboards["pubdash1"].viewers["viewer1"] = time.Now().Add(-2 * viewerTimeout)
+
+		assert.NoError(t, throttle.admitViewer("pubdash1", "viewer2", 1))
+		assert.ErrorIs(t, throttle.admitViewer("pubdash1", "viewer1", 1), ErrTooManyViewers)
+	})
+}
+
+func TestThrottleLimits(t *testing.T) {
+	pd := &PublicDashboardServiceImpl{cfg: &setting.Cfg{
+		PublicDashboardsQueryRateLimit:       100,
+		PublicDashboardsMaxConcurrentViewers: 10,
+	}}
+
+	queryRateLimit, maxViewers := pd.throttleLimits(&PublicDashboard{})
+	assert.Equal(t, int64(100), queryRateLimit)
+	assert.Equal(t, int64(10), maxViewers)
+
+	queryRateLimit, maxViewers = pd.throttleLimits(&PublicDashboard{QueryRateLimit: 20, MaxConcurrentViewers: ThrottleUnlimited})
+	assert.Equal(t, int64(20), queryRateLimit)
+	assert.Equal(t, int64(0), maxViewers)
+}
@@ -13,6 +13,16 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	if interval := dto.PublicDashboard.QueryCacheInterval; interval != nil && (*interval < QueryCacheIntervalDisabled || *interval > MaxQueryCacheInterval) {
+		return ErrInvalidQueryCacheInterval.Errorf("ValidateSavePublicDashboard: query cache interval should be between %d and %d", QueryCacheIntervalDisabled, MaxQueryCacheInterval)
+	}
+
+	for _, limit := range []*int64{dto.PublicDashboard.QueryRateLimit, dto.PublicDashboard.MaxConcurrentViewers} {
+		if limit != nil && *limit < ThrottleUnlimited {
+			return ErrInvalidThrottleLimit.Errorf("ValidateSavePublicDashboard: limits should be greater than or equal to %d", ThrottleUnlimited)
+		}
+	}
+
 	return nil
 }
 
@@ -22,6 +22,32 @@ func TestValidatePublicDashboard(t *testing.T) {
 		err := ValidatePublicDashboard(dto)
 		require.Error(t, err)
 	})
+
+	t.Run("Returns error when the query cache interval is out of range", func(t *testing.T) {
+		for _, interval := range []int64{-2, MaxQueryCacheInterval + 1} {
+			dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{QueryCacheInterval: &interval}}
+
+			err := ValidatePublicDashboard(dto)
+			require.ErrorIs(t, err, ErrInvalidQueryCacheInterval)
+		}
+
+		interval := int64(QueryCacheIntervalDisabled)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{QueryCacheInterval: &interval}}
+		require.NoError(t, ValidatePublicDashboard(dto))
+	})
+
+	t.Run("Returns error when the throttle limits are invalid", func(t *testing.T) {
+		invalid := int64(-2)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{QueryRateLimit: &invalid}}
+		require.ErrorIs(t, ValidatePublicDashboard(dto), ErrInvalidThrottleLimit)
+
+		dto = &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{MaxConcurrentViewers: &invalid}}
+		require.ErrorIs(t, ValidatePublicDashboard(dto), ErrInvalidThrottleLimit)
+
+		unlimited := int64(ThrottleUnlimited)
+		dto = &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{QueryRateLimit: &unlimited, MaxConcurrentViewers: &unlimited}}
+		require.NoError(t, ValidatePublicDashboard(dto))
+	})
 }
 
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
@@ -19,6 +19,8 @@ type Service interface {
 	QuotaReached(c *contextmodel.ReqContext, targetSrv TargetSrv) (bool, error)
 	// CheckQuotaReached checks if the quota limitations have been reached for a specific service
 	CheckQuotaReached(ctx context.Context, targetSrv TargetSrv, scopeParams *ScopeParameters) (bool, error)
+	// GetUsage returns the usage reported by the registered services for the scope, even when quotas are disabled
+	GetUsage(ctx context.Context, scopeParams *ScopeParameters) (*Map, error)
 	// DeleteQuotaForUser deletes custom quota limitations for the user
 	DeleteQuotaForUser(ctx context.Context, userID int64) error
 	// DeleteByOrg(ctx context.Context, orgID int64) error
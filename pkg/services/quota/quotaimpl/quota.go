@@ -2,6 +2,7 @@ package quotaimpl
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"golang.org/x/sync/errgroup"
@@ -14,6 +15,8 @@ import (
 )
 
 type serviceDisabled struct {
+	// reporters keeps the usage reporters so that the usage is available when quotas are disabled
+	reporters *service
 }
 
 func (s *serviceDisabled) QuotaReached(c *contextmodel.ReqContext, targetSrv quota.TargetSrv) (bool, error) {
@@ -36,7 +39,15 @@ func (s *serviceDisabled) DeleteQuotaForUser(ctx context.Context, userID int64)
 	return nil
 }
 
+func (s *serviceDisabled) GetUsage(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	return s.reporters.GetUsage(ctx, scopeParams)
+}
+
 func (s *serviceDisabled) RegisterQuotaReporter(e *quota.NewUsageReporter) error {
+	// registering a service twice doesn't fail while quotas are disabled, the first reporter is kept
+	if err := s.reporters.RegisterQuotaReporter(e); err != nil && !errors.Is(err, quota.ErrTargetSrvConflict) {
+		return err
+	}
 	return nil
 }
 
@@ -65,7 +76,7 @@ func ProvideService(db db.DB, cfg *setting.Cfg) quota.Service {
 	}
 
 	if s.IsDisabled() {
-		return &serviceDisabled{}
+		return &serviceDisabled{reporters: &s}
 	}
 
 	return &s
@@ -240,6 +251,10 @@ func (s *service) DeleteQuotaForUser(ctx context.Context, userID int64) error {
 	return s.store.DeleteByUser(c, userID)
 }
 
+func (s *service) GetUsage(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	return s.getUsage(ctx, scopeParams)
+}
+
 func (s *service) RegisterQuotaReporter(e *quota.NewUsageReporter) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
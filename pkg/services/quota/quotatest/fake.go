@@ -36,6 +36,10 @@ func (f *FakeQuotaService) DeleteQuotaForUser(c context.Context, userID int64) e
 	return f.err
 }
 
+func (f *FakeQuotaService) GetUsage(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	return &quota.Map{}, f.err
+}
+
 func (f *FakeQuotaService) RegisterQuotaReporter(e *quota.NewUsageReporter) error {
 	return f.err
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -39,7 +40,7 @@ func (rs *RenderingService) GetRenderUser(ctx context.Context, key string) (*Ren
 
 	if looksLikeJWT(key) && rs.features.IsEnabled(ctx, featuremgmt.FlagRenderAuthJWT) {
 		from = "jwt"
-		renderUser = rs.getRenderUserFromJWT(key)
+		renderUser = rs.getRenderUserFromJWT(ctx, key)
 	} else {
 		from = "cache"
 		renderUser = rs.getRenderUserFromCache(ctx, key)
@@ -52,10 +53,19 @@ func (rs *RenderingService) GetRenderUser(ctx context.Context, key string) (*Ren
 	return renderUser, found
 }
 
-func (rs *RenderingService) getRenderUserFromJWT(key string) *RenderUser {
+func (rs *RenderingService) getRenderUserFromJWT(ctx context.Context, key string) *RenderUser {
 	claims := new(renderJWT)
-	tkn, err := jwt.ParseWithClaims(key, claims, func(_ *jwt.Token) (any, error) {
-		return []byte(rs.Cfg.RendererAuthToken), nil
+	tkn, err := jwt.ParseWithClaims(key, claims, func(t *jwt.Token) (any, error) {
+		// the render keys are signed by the rotated signing keys, not by the renderer token
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("render key without signing key ID")
+		}
+		secret, ok := rs.signingKeys.secret(ctx, kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %s", kid)
+		}
+		return secret, nil
 	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Alg()}))
 
 	if err != nil || !tkn.Valid {
@@ -178,13 +188,18 @@ func (r *longLivedRenderKeyProvider) Dispose(ctx context.Context) {
 
 type jwtRenderKeyProvider struct {
 	log       log.Logger
-	authToken []byte
+	keys      *renderSigningKeys
 	keyExpiry time.Duration
 }
 
-func (j *jwtRenderKeyProvider) get(_ context.Context, opts AuthOpts) (string, error) {
+func (j *jwtRenderKeyProvider) get(ctx context.Context, opts AuthOpts) (string, error) {
+	key, err := j.keys.current(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the key signing the render key: %w", err)
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS512, j.buildJWTClaims(opts))
-	return token.SignedString(j.authToken)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Secret)
 }
 
 func (j *jwtRenderKeyProvider) buildJWTClaims(opts AuthOpts) renderJWT {
@@ -207,3 +222,19 @@ func (j *jwtRenderKeyProvider) afterRequest(_ context.Context, _ AuthOpts, _ str
 func looksLikeJWT(key string) bool {
 	return strings.HasPrefix(key, "eyJ")
 }
+
+// GetSigningKeys returns the keys signing the JWT render keys
+func (rs *RenderingService) GetSigningKeys(ctx context.Context) ([]SigningKey, error) {
+	return rs.signingKeys.get(ctx)
+}
+
+// RotateSigningKey replaces the key signing the JWT render keys, the render keys signed by the previous key stay
+// valid until they expire
+func (rs *RenderingService) RotateSigningKey(ctx context.Context) ([]SigningKey, error) {
+	keys, err := rs.signingKeys.rotate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rs.log.Info("Rotated the key signing the render keys", "id", keys[0].ID)
+	return keys, nil
+}
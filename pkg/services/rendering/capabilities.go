@@ -3,6 +3,7 @@ package rendering
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/Masterminds/semver"
 )
@@ -18,16 +19,40 @@ const (
 	ScalingDownImages CapabilityName = "ScalingDownImages"
 	FullHeightImages  CapabilityName = "FullHeightImages"
 	SvgSanitization   CapabilityName = "SvgSanitization"
+	PDFRendering      CapabilityName = "PDFRendering"
+	CustomViewport    CapabilityName = "CustomViewport"
+	HTTPHeaders       CapabilityName = "HTTPHeaders"
 )
 
 var ErrUnknownCapability = errors.New("unknown capability")
 var ErrInvalidPluginVersion = errors.New("invalid plugin version")
+var ErrCapabilityNotSupported = errors.New("the image renderer doesn't support the rendering option")
+
+// setReportedCapabilities sets the capabilities reported by the remote renderer, versionMutex must be held
+func (rs *RenderingService) setReportedCapabilities(capabilities []CapabilityName) {
+	if capabilities == nil {
+		rs.reportedCapabilities = nil
+		return
+	}
+	rs.reportedCapabilities = make(map[CapabilityName]bool, len(capabilities))
+	for _, c := range capabilities {
+		rs.reportedCapabilities[c] = true
+	}
+}
 
 func (rs *RenderingService) HasCapability(ctx context.Context, capability CapabilityName) (CapabilitySupportRequestResult, error) {
 	if !rs.IsAvailable(ctx) {
 		return CapabilitySupportRequestResult{IsSupported: false, SemverConstraint: ""}, ErrRenderUnavailable
 	}
 
+	// the capabilities reported by the renderer take precedence over the ones of its version
+	rs.versionMutex.RLock()
+	reported := rs.reportedCapabilities
+	rs.versionMutex.RUnlock()
+	if reported != nil {
+		return CapabilitySupportRequestResult{IsSupported: reported[capability]}, nil
+	}
+
 	var semverConstraint string
 	for i := range rs.capabilities {
 		if rs.capabilities[i].name == capability {
@@ -55,3 +80,34 @@ func (rs *RenderingService) HasCapability(ctx context.Context, capability Capabi
 
 	return CapabilitySupportRequestResult{IsSupported: compiledSemverConstraint.Check(compiledImageRendererVersion), SemverConstraint: semverConstraint}, nil
 }
+
+// checkCapabilities returns an ErrCapabilityNotSupported error when the renderer doesn't support an option of the
+// render, instead of ignoring it. The options are allowed when the support is unknown, e.g. when the version of the
+// renderer can't be parsed.
+func (rs *RenderingService) checkCapabilities(ctx context.Context, renderType RenderType, opts Opts) error {
+	var required []CapabilityName
+	if renderType == RenderPDF || opts.Encoding == "pdf" {
+		required = append(required, PDFRendering)
+	}
+	if opts.Height == -1 {
+		required = append(required, FullHeightImages)
+	}
+	if opts.DeviceScaleFactor < 1 {
+		required = append(required, ScalingDownImages)
+	}
+	if (opts.Width > 0 && opts.Width != rs.Cfg.RendererDefaultImageWidth) ||
+		(opts.Height > 0 && opts.Height != rs.Cfg.RendererDefaultImageHeight) {
+		required = append(required, CustomViewport)
+	}
+	if len(opts.Headers) > 0 {
+		required = append(required, HTTPHeaders)
+	}
+
+	for _, capability := range required {
+		result, err := rs.HasCapability(ctx, capability)
+		if err == nil && !result.IsSupported {
+			return fmt.Errorf("%w: %s, image renderer version %s", ErrCapabilityNotSupported, capability, rs.Version())
+		}
+	}
+	return nil
+}
@@ -134,3 +134,44 @@ func TestCapabilities(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckCapabilities(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.RendererUrl = dummyRendererUrl
+	cfg.RendererDefaultImageWidth, cfg.RendererDefaultImageHeight = 1000, 500
+	rs := &RenderingService{
+		Cfg: cfg,
+		log: log.New("test-capabilities-rendering-service"),
+		capabilities: []Capability{
+			{name: PDFRendering, semverConstraint: ">= 3.10.0"},
+			{name: FullHeightImages, semverConstraint: ">= 3.4.0"},
+			{name: ScalingDownImages, semverConstraint: ">= 3.4.0"},
+			{name: CustomViewport, semverConstraint: ">= 1.0.0"},
+			{name: HTTPHeaders, semverConstraint: ">= 2.0.0"},
+		},
+	}
+	ctx := context.Background()
+	defaultOpts := Opts{Width: 1000, Height: 500, DeviceScaleFactor: 1}
+
+	t.Run("should gate the options on the version of the renderers that don't report their capabilities", func(t *testing.T) {
+		rs.version = "3.4.0"
+		require.NoError(t, rs.checkCapabilities(ctx, RenderPNG, Opts{Width: 1000, Height: -1, DeviceScaleFactor: 0.5}))
+		require.ErrorIs(t, rs.checkCapabilities(ctx, RenderPDF, defaultOpts), ErrCapabilityNotSupported)
+		require.ErrorIs(t, rs.checkCapabilities(ctx, RenderPNG, Opts{Width: 1000, Height: 500, DeviceScaleFactor: 1, Encoding: "pdf"}), ErrCapabilityNotSupported)
+
+		// the support is unknown
+		rs.version = "abcd"
+		require.NoError(t, rs.checkCapabilities(ctx, RenderPDF, defaultOpts))
+	})
+
+	t.Run("should gate the options on the capabilities reported by the renderer", func(t *testing.T) {
+		rs.version = "4.0.0"
+		rs.setReportedCapabilities([]CapabilityName{PDFRendering, FullHeightImages})
+		defer rs.setReportedCapabilities(nil)
+
+		require.NoError(t, rs.checkCapabilities(ctx, RenderPDF, Opts{Width: 1000, Height: -1, DeviceScaleFactor: 1}))
+		require.ErrorIs(t, rs.checkCapabilities(ctx, RenderPNG, Opts{Width: 800, Height: 500, DeviceScaleFactor: 1}), ErrCapabilityNotSupported)
+		require.ErrorIs(t, rs.checkCapabilities(ctx, RenderPNG, Opts{Width: 1000, Height: 500, DeviceScaleFactor: 1,
+			Headers: map[string][]string{"Accept-Language": {"en"}}}), ErrCapabilityNotSupported)
+	})
+}
@@ -162,7 +162,7 @@ func (rs *RenderingService) doRequest(ctx context.Context, u *url.URL, headers m
 	rs.log.Debug("calling remote rendering service", "url", u)
 
 	// make request to renderer server
-	resp, err := netClient.Do(req)
+	resp, err := rs.httpClient().Do(req)
 	if err != nil {
 		rs.log.Error("Failed to send request to remote rendering service", "error", err)
 		var urlErr *url.Error
@@ -222,13 +222,20 @@ func (rs *RenderingService) readFileResponse(ctx context.Context, resp *http.Res
 	return nil
 }
 
-func (rs *RenderingService) getRemotePluginVersionWithRetry(callback func(string, error)) {
+// remoteRendererInfo is the response of the version endpoint of the remote renderer
+type remoteRendererInfo struct {
+	Version string
+	// Capabilities are the capabilities reported by the renderer, nil when it doesn't report them
+	Capabilities []CapabilityName
+}
+
+func (rs *RenderingService) getRemotePluginVersionWithRetry(callback func(remoteRendererInfo, error)) {
 	go func() {
 		var err error
 		for try := uint(0); try < remoteVersionFetchRetries; try++ {
-			version, err := rs.getRemotePluginVersion()
+			info, err := rs.getRemoteRendererInfo()
 			if err == nil {
-				callback(version, err)
+				callback(info, err)
 				return
 			}
 			rs.log.Info("Couldn't get remote renderer version, retrying", "err", err, "try", try)
@@ -236,20 +243,28 @@ func (rs *RenderingService) getRemotePluginVersionWithRetry(callback func(string
 			time.Sleep(remoteVersionFetchInterval)
 		}
 
-		callback("", err)
+		callback(remoteRendererInfo{}, err)
 	}()
 }
 
 func (rs *RenderingService) getRemotePluginVersion() (string, error) {
+	info, err := rs.getRemoteRendererInfo()
+	return info.Version, err
+}
+
+// getRemoteRendererInfo is the handshake with the remote renderer, which returns its version and, in the recent
+// versions, the capabilities it supports
+func (rs *RenderingService) getRemoteRendererInfo() (remoteRendererInfo, error) {
+	var info remoteRendererInfo
 	rendererURL, err := url.Parse(rs.Cfg.RendererUrl + "/version")
 	if err != nil {
-		return "", err
+		return info, err
 	}
 
 	headers := make(map[string][]string)
 	resp, err := rs.doRequest(context.Background(), rendererURL, headers)
 	if err != nil {
-		return "", err
+		return info, err
 	}
 
 	defer func() {
@@ -260,27 +275,26 @@ func (rs *RenderingService) getRemotePluginVersion() (string, error) {
 
 	if resp.StatusCode == http.StatusNotFound {
 		// Old versions of the renderer lacked the version endpoint
-		return "1.0.0", nil
+		info.Version = "1.0.0"
+		return info, nil
 	} else if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("remote rendering request to get version failed, status code: %d, status: %s", resp.StatusCode,
+		return info, fmt.Errorf("remote rendering request to get version failed, status code: %d, status: %s", resp.StatusCode,
 			resp.Status)
 	}
 
-	var info struct {
-		Version string
-	}
 	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
-		return "", err
+		return remoteRendererInfo{}, err
 	}
-	return info.Version, nil
+	return info, nil
 }
 
 func (rs *RenderingService) refreshRemotePluginVersion() {
-	newVersion, err := rs.getRemotePluginVersion()
+	info, err := rs.getRemoteRendererInfo()
 	if err != nil {
 		rs.log.Info("Failed to refresh remote plugin version", "err", err)
 		return
 	}
+	newVersion := info.Version
 
 	if newVersion == "" {
 		// the image-renderer could have been temporary unavailable - skip updating the version
@@ -289,11 +303,11 @@ func (rs *RenderingService) refreshRemotePluginVersion() {
 	}
 
 	currentVersion := rs.Version()
+	rs.versionMutex.Lock()
+	defer rs.versionMutex.Unlock()
 	if currentVersion != newVersion {
-		rs.versionMutex.Lock()
-		defer rs.versionMutex.Unlock()
-
 		rs.log.Info("Updating remote plugin version", "currentVersion", currentVersion, "newVersion", newVersion)
 		rs.version = newVersion
 	}
+	rs.setReportedCapabilities(info.Capabilities)
 }
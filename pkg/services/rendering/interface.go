@@ -13,6 +13,7 @@ import (
 var ErrTimeout = errors.New("timeout error - you can set timeout in seconds with &timeout url parameter")
 var ErrConcurrentLimitReached = errors.New("rendering concurrent limit reached")
 var ErrRenderUnavailable = errors.New("rendering plugin not available")
+var ErrShuttingDown = errors.New("rendering is not available while Grafana is shutting down")
 var ErrServerTimeout = errutil.NewBase(errutil.StatusUnknown, "rendering.serverTimeout", errutil.WithPublicMessage("error trying to connect to image-renderer service"))
 
 type RenderType string
@@ -55,6 +56,11 @@ type Opts struct {
 	DeviceScaleFactor float64
 	Headers           map[string][]string
 	Theme             models.Theme
+	// Resizable allows serving the render by resizing a recent larger render of the same panel
+	// when resize_cache_ttl is set. Only renders of an absolute time range are resized.
+	Resizable bool
+	// Priority is the class of the render in the render queue, when queue_concurrency is set
+	Priority Priority
 }
 
 type ErrorOpts struct {
@@ -129,4 +135,6 @@ type Service interface {
 	HasCapability(ctx context.Context, capability CapabilityName) (CapabilitySupportRequestResult, error)
 	CreateRenderingSession(ctx context.Context, authOpts AuthOpts, sessionOpts SessionOpts) (Session, error)
 	SanitizeSVG(ctx context.Context, req *SanitizeSVGRequest) (*SanitizeSVGResponse, error)
+	GetSigningKeys(ctx context.Context) ([]SigningKey, error)
+	RotateSigningKey(ctx context.Context) ([]SigningKey, error)
 }
@@ -65,6 +65,21 @@ func (mr *MockServiceMockRecorder) GetRenderUser(ctx, key interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRenderUser", reflect.TypeOf((*MockService)(nil).GetRenderUser), ctx, key)
 }
 
+// GetSigningKeys mocks base method.
+func (m *MockService) GetSigningKeys(ctx context.Context) ([]SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSigningKeys", ctx)
+	ret0, _ := ret[0].([]SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSigningKeys indicates an expected call of GetSigningKeys.
+func (mr *MockServiceMockRecorder) GetSigningKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSigningKeys", reflect.TypeOf((*MockService)(nil).GetSigningKeys), ctx)
+}
+
 // HasCapability mocks base method.
 func (m *MockService) HasCapability(ctx context.Context, capability CapabilityName) (CapabilitySupportRequestResult, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +154,21 @@ func (mr *MockServiceMockRecorder) RenderErrorImage(theme, error interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderErrorImage", reflect.TypeOf((*MockService)(nil).RenderErrorImage), theme, error)
 }
 
+// RotateSigningKey mocks base method.
+func (m *MockService) RotateSigningKey(ctx context.Context) ([]SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSigningKey", ctx)
+	ret0, _ := ret[0].([]SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSigningKey indicates an expected call of RotateSigningKey.
+func (mr *MockServiceMockRecorder) RotateSigningKey(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSigningKey", reflect.TypeOf((*MockService)(nil).RotateSigningKey), ctx)
+}
+
 // SanitizeSVG mocks base method.
 func (m *MockService) SanitizeSVG(ctx context.Context, req *SanitizeSVGRequest) (*SanitizeSVGResponse, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,161 @@
+package rendering
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+)
+
+// Priority is the class of a render in the render queue
+type Priority int
+
+const (
+	// PriorityInteractive is the priority of the renders requested by users, the default
+	PriorityInteractive Priority = iota
+	// PriorityAlerting is the priority of the alert screenshots, they're served first
+	PriorityAlerting
+	// PriorityBackground is the priority of the renders nobody waits for, such as thumbnails, they're served last
+	PriorityBackground
+)
+
+// priorities are the priorities in the order they're served
+var priorities = []Priority{PriorityAlerting, PriorityInteractive, PriorityBackground}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityAlerting:
+		return "alerting"
+	case PriorityBackground:
+		return "background"
+	default:
+		return "interactive"
+	}
+}
+
+type queueWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+type queueClass struct {
+	// concurrency is the maximum number of renders of the class in progress
+	concurrency int
+	running     int
+	waiting     []*queueWaiter
+}
+
+// renderQueue limits the number of renders in progress. The renders over the limit wait for a slot, which is
+// given to the waiting render of the highest priority whose class isn't at its concurrency.
+type renderQueue struct {
+	mu          sync.Mutex
+	concurrency int
+	maxWaiting  int
+	running     int
+	classes     map[Priority]*queueClass
+}
+
+// newRenderQueue returns a queue of the given total concurrency, with the concurrencies of the priorities.
+// The priorities without a concurrency can use all the slots.
+func newRenderQueue(concurrency int, classConcurrency map[Priority]int, maxWaiting int) *renderQueue {
+	q := &renderQueue{
+		concurrency: concurrency,
+		maxWaiting:  maxWaiting,
+		classes:     make(map[Priority]*queueClass, len(priorities)),
+	}
+	for _, p := range priorities {
+		c := classConcurrency[p]
+		if c <= 0 || c > concurrency {
+			c = concurrency
+		}
+		q.classes[p] = &queueClass{concurrency: c}
+	}
+	return q
+}
+
+// acquire waits for a slot for a render of the priority. It returns ErrConcurrentLimitReached when too many renders
+// of the priority are waiting already. The returned function releases the slot.
+func (q *renderQueue) acquire(ctx context.Context, p Priority) (func(), error) {
+	c, ok := q.classes[p]
+	if !ok {
+		p, c = PriorityInteractive, q.classes[PriorityInteractive]
+	}
+	release := func() { q.release(p) }
+
+	q.mu.Lock()
+	if len(c.waiting) == 0 && q.running < q.concurrency && c.running < c.concurrency {
+		q.start(p, c)
+		q.mu.Unlock()
+		metrics.MRenderingQueueWaitDuration.WithLabelValues(p.String()).Observe(0)
+		return release, nil
+	}
+	if len(c.waiting) >= q.maxWaiting {
+		q.mu.Unlock()
+		metrics.MRenderingQueueRejectedTotal.WithLabelValues(p.String()).Inc()
+		return nil, ErrConcurrentLimitReached
+	}
+	w := &queueWaiter{ready: make(chan struct{})}
+	c.waiting = append(c.waiting, w)
+	metrics.MRenderingQueueWaiting.WithLabelValues(p.String()).Set(float64(len(c.waiting)))
+	q.mu.Unlock()
+
+	start := time.Now()
+	defer func() {
+		metrics.MRenderingQueueWaitDuration.WithLabelValues(p.String()).Observe(time.Since(start).Seconds())
+	}()
+
+	select {
+	case <-w.ready:
+		return release, nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if w.granted {
+			// the slot was given while the context was canceled
+			q.finish(p, c)
+			return nil, ctx.Err()
+		}
+		for i, waiting := range c.waiting {
+			if waiting == w {
+				c.waiting = append(c.waiting[:i], c.waiting[i+1:]...)
+				break
+			}
+		}
+		metrics.MRenderingQueueWaiting.WithLabelValues(p.String()).Set(float64(len(c.waiting)))
+		return nil, ctx.Err()
+	}
+}
+
+func (q *renderQueue) release(p Priority) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.finish(p, q.classes[p])
+}
+
+// start counts a render of the class in progress, the lock must be held
+func (q *renderQueue) start(p Priority, c *queueClass) {
+	q.running++
+	c.running++
+	metrics.MRenderingQueueRunning.WithLabelValues(p.String()).Set(float64(c.running))
+}
+
+// finish frees the slot of a render of the class and gives the free slots to the waiting renders, the lock
+// must be held
+func (q *renderQueue) finish(p Priority, c *queueClass) {
+	q.running--
+	c.running--
+	metrics.MRenderingQueueRunning.WithLabelValues(p.String()).Set(float64(c.running))
+
+	for _, priority := range priorities {
+		class := q.classes[priority]
+		for len(class.waiting) > 0 && q.running < q.concurrency && class.running < class.concurrency {
+			w := class.waiting[0]
+			class.waiting = class.waiting[1:]
+			w.granted = true
+			q.start(priority, class)
+			close(w.ready)
+		}
+		metrics.MRenderingQueueWaiting.WithLabelValues(priority.String()).Set(float64(len(class.waiting)))
+	}
+}
@@ -0,0 +1,91 @@
+package rendering
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderQueue(t *testing.T) {
+	ctx := context.Background()
+	q := newRenderQueue(2, map[Priority]int{PriorityBackground: 1}, 1)
+
+	// acquireAsync waits for a slot in the background, the channel receives the release function once it's given
+	acquireAsync := func(p Priority) chan func() {
+		acquired := make(chan func(), 1)
+		go func() {
+			release, err := q.acquire(ctx, p)
+			if err == nil {
+				acquired <- release
+			}
+		}()
+		return acquired
+	}
+	waiting := func(p Priority) func() bool {
+		return func() bool {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			return len(q.classes[p].waiting) == 1
+		}
+	}
+
+	releaseBackground, err := q.acquire(ctx, PriorityBackground)
+	require.NoError(t, err)
+	releaseInteractive, err := q.acquire(ctx, PriorityInteractive)
+	require.NoError(t, err)
+
+	t.Run("should serve the waiting renders by priority", func(t *testing.T) {
+		interactive := acquireAsync(PriorityInteractive)
+		require.Eventually(t, waiting(PriorityInteractive), time.Second, time.Millisecond)
+		alerting := acquireAsync(PriorityAlerting)
+		require.Eventually(t, waiting(PriorityAlerting), time.Second, time.Millisecond)
+
+		releaseInteractive()
+		select {
+		case releaseInteractive = <-alerting:
+		case <-time.After(time.Second):
+			require.Fail(t, "the alerting render didn't get the slot")
+		}
+		assert.Empty(t, interactive)
+
+		releaseInteractive()
+		select {
+		case releaseInteractive = <-interactive:
+		case <-time.After(time.Second):
+			require.Fail(t, "the interactive render didn't get the slot")
+		}
+	})
+
+	var interactive chan func()
+	t.Run("should reject the renders when too many are waiting", func(t *testing.T) {
+		interactive = acquireAsync(PriorityInteractive)
+		require.Eventually(t, waiting(PriorityInteractive), time.Second, time.Millisecond)
+		_, err := q.acquire(ctx, PriorityInteractive)
+		assert.ErrorIs(t, err, ErrConcurrentLimitReached)
+	})
+
+	t.Run("should keep the background renders to their concurrency", func(t *testing.T) {
+		releaseInteractive()
+		select {
+		case release := <-interactive:
+			release()
+		case <-time.After(time.Second):
+			require.Fail(t, "the interactive render didn't get the slot")
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := q.acquire(timeoutCtx, PriorityBackground)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		assert.Empty(t, q.classes[PriorityBackground].waiting)
+		assert.Equal(t, 1, q.running)
+	})
+
+	releaseBackground()
+}
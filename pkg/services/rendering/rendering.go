@@ -38,7 +38,20 @@ type RenderingService struct {
 	version           string
 	versionMutex      sync.RWMutex
 	capabilities      []Capability
-	pluginAvailable   bool
+	// reportedCapabilities are the capabilities reported by the remote renderer, guarded by versionMutex. The
+	// capabilities are resolved from the version of the renderers that don't report them.
+	reportedCapabilities map[CapabilityName]bool
+	pluginAvailable      bool
+	resizeCache          *resizeCache
+	// queue limits the renders in progress by priority when queue_concurrency is set
+	queue *renderQueue
+	// tlsClient is the client of the remote renderer when a CA or a client certificate is set
+	tlsClient *rendererTLSClient
+	// signingKeys sign the JWT render keys
+	signingKeys *renderSigningKeys
+	// draining is set when Grafana shuts down, new renders are rejected while the renders in inFlight finish
+	draining atomic.Bool
+	inFlight sync.WaitGroup
 
 	perRequestRenderKeyProvider renderKeyProvider
 	Cfg                         *setting.Cfg
@@ -96,11 +109,12 @@ func ProvideService(cfg *setting.Cfg, features *featuremgmt.FeatureManager, remo
 		domain = "localhost"
 	}
 
+	signingKeys := newRenderSigningKeys(remoteCache, cfg.RendererSigningKeyRotationInterval)
 	var renderKeyProvider renderKeyProvider
 	if features.IsEnabledGlobally(featuremgmt.FlagRenderAuthJWT) {
 		renderKeyProvider = &jwtRenderKeyProvider{
 			log:       logger,
-			authToken: []byte(cfg.RendererAuthToken),
+			keys:      signingKeys,
 			keyExpiry: cfg.RendererRenderKeyLifeTime,
 		}
 	} else {
@@ -111,6 +125,11 @@ func ProvideService(cfg *setting.Cfg, features *featuremgmt.FeatureManager, remo
 		}
 	}
 
+	tlsClient, err := newRendererTLSClient(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	_, exists := rm.Renderer(context.Background())
 
 	s := &RenderingService{
@@ -128,6 +147,18 @@ func ProvideService(cfg *setting.Cfg, features *featuremgmt.FeatureManager, remo
 				name:             SvgSanitization,
 				semverConstraint: ">= 3.5.0",
 			},
+			{
+				name:             PDFRendering,
+				semverConstraint: ">= 3.10.0",
+			},
+			{
+				name:             CustomViewport,
+				semverConstraint: ">= 1.0.0",
+			},
+			{
+				name:             HTTPHeaders,
+				semverConstraint: ">= 2.0.0",
+			},
 		},
 		Cfg:                   cfg,
 		features:              features,
@@ -137,6 +168,19 @@ func ProvideService(cfg *setting.Cfg, features *featuremgmt.FeatureManager, remo
 		domain:                domain,
 		sanitizeURL:           sanitizeURL,
 		pluginAvailable:       exists,
+		tlsClient:             tlsClient,
+		signingKeys:           signingKeys,
+	}
+
+	if cfg.RendererResizeCacheTTL > 0 {
+		s.resizeCache = newResizeCache(cfg.RendererResizeCacheTTL)
+	}
+	if cfg.RendererQueueConcurrency > 0 {
+		s.queue = newRenderQueue(cfg.RendererQueueConcurrency, map[Priority]int{
+			PriorityAlerting:    cfg.RendererQueueAlertingConcurrency,
+			PriorityInteractive: cfg.RendererQueueInteractiveConcurrency,
+			PriorityBackground:  cfg.RendererQueueBackgroundConcurrency,
+		}, cfg.RendererQueueMaxWaiting)
 	}
 
 	gob.Register(&RenderUser{})
@@ -153,17 +197,18 @@ func (rs *RenderingService) Run(ctx context.Context) error {
 	if rs.remoteAvailable() {
 		rs.log = rs.log.New("renderer", "http")
 
-		rs.getRemotePluginVersionWithRetry(func(version string, err error) {
+		rs.getRemotePluginVersionWithRetry(func(info remoteRendererInfo, err error) {
 			if err != nil {
 				rs.log.Info("Couldn't get remote renderer version", "err", err)
 			}
 
-			rs.log.Info("Backend rendering via external http server", "version", version)
+			rs.log.Info("Backend rendering via external http server", "version", info.Version, "capabilities", info.Capabilities)
 
 			rs.versionMutex.Lock()
 			defer rs.versionMutex.Unlock()
 
-			rs.version = version
+			rs.version = info.Version
+			rs.setReportedCapabilities(info.Capabilities)
 		})
 		rs.renderAction = rs.renderViaHTTP
 		rs.renderCSVAction = rs.renderCSVViaHTTP
@@ -178,6 +223,7 @@ func (rs *RenderingService) Run(ctx context.Context) error {
 			case <-ctx.Done():
 				rs.log.Debug("Grafana is shutting down - stopping image-renderer version refresh")
 				refreshTicker.Stop()
+				rs.drain()
 				return nil
 			}
 		}
@@ -194,6 +240,7 @@ func (rs *RenderingService) Run(ctx context.Context) error {
 		rs.renderCSVAction = rs.renderCSVViaPlugin
 		rs.sanitizeSVGAction = rs.sanitizeSVGViaPlugin
 		<-ctx.Done()
+		rs.drain()
 
 		return nil
 	}
@@ -206,6 +253,40 @@ func (rs *RenderingService) Run(ctx context.Context) error {
 	return nil
 }
 
+// drain rejects the new renders and waits for the renders in progress, for at most renderer_drain_timeout
+func (rs *RenderingService) drain() {
+	rs.draining.Store(true)
+
+	done := make(chan struct{})
+	go func() {
+		rs.inFlight.Wait()
+		close(done)
+	}()
+
+	inProgress := atomic.LoadInt32(&rs.inProgressCount)
+	if inProgress > 0 {
+		rs.log.Info("Waiting for the renders in progress", "count", inProgress, "timeout", rs.Cfg.RendererDrainTimeout)
+	}
+	select {
+	case <-done:
+	case <-time.After(rs.Cfg.RendererDrainTimeout):
+		rs.log.Warn("Timed out waiting for the renders in progress", "count", atomic.LoadInt32(&rs.inProgressCount))
+	}
+}
+
+// begin registers a render in progress, it returns false once Grafana is shutting down
+func (rs *RenderingService) begin() bool {
+	if rs.draining.Load() {
+		return false
+	}
+	rs.inFlight.Add(1)
+	if rs.draining.Load() {
+		rs.inFlight.Done()
+		return false
+	}
+	return true
+}
+
 func (rs *RenderingService) remoteAvailable() bool {
 	return rs.Cfg.RendererUrl != ""
 }
@@ -266,20 +347,21 @@ func (rs *RenderingService) Render(ctx context.Context, renderType RenderType, o
 }
 
 func (rs *RenderingService) render(ctx context.Context, renderType RenderType, opts Opts, renderKeyProvider renderKeyProvider) (*RenderResult, error) {
-	if int(atomic.LoadInt32(&rs.inProgressCount)) > opts.ConcurrentLimit {
-		rs.log.Warn("Could not render image, hit the currency limit", "concurrencyLimit", opts.ConcurrentLimit, "path", opts.Path)
-		if opts.ErrorConcurrentLimitReached {
-			return nil, ErrConcurrentLimitReached
-		}
+	if math.IsInf(opts.DeviceScaleFactor, 0) || math.IsNaN(opts.DeviceScaleFactor) || opts.DeviceScaleFactor == 0 {
+		opts.DeviceScaleFactor = 1
+	}
 
-		theme := models.ThemeDark
-		if opts.Theme != "" {
-			theme = opts.Theme
+	resizable := rs.resizeCache != nil && opts.Resizable && renderType == RenderPNG && opts.Encoding == "" && hasAbsoluteTimeRange(opts.Path)
+	if resizable {
+		if result, ok := rs.renderFromResizeCache(opts); ok {
+			return result, nil
 		}
-		filePath := fmt.Sprintf("public/img/rendering_limit_%s.png", theme)
-		return &RenderResult{
-			FilePath: filepath.Join(rs.Cfg.HomePath, filePath),
-		}, nil
+	}
+
+	// the queue replaces the concurrent limits of the callers
+	if rs.queue == nil && int(atomic.LoadInt32(&rs.inProgressCount)) > opts.ConcurrentLimit {
+		rs.log.Warn("Could not render image, hit the currency limit", "concurrencyLimit", opts.ConcurrentLimit, "path", opts.Path)
+		return rs.concurrentLimitReached(opts)
 	}
 
 	if !rs.IsAvailable(ctx) {
@@ -292,10 +374,28 @@ func (rs *RenderingService) render(ctx context.Context, renderType RenderType, o
 		return rs.renderUnavailableImage(), nil
 	}
 
-	rs.log.Info("Rendering", "path", opts.Path)
-	if math.IsInf(opts.DeviceScaleFactor, 0) || math.IsNaN(opts.DeviceScaleFactor) || opts.DeviceScaleFactor == 0 {
-		opts.DeviceScaleFactor = 1
+	if err := rs.checkCapabilities(ctx, renderType, opts); err != nil {
+		return nil, err
 	}
+
+	if rs.queue != nil {
+		release, err := rs.queue.acquire(ctx, opts.Priority)
+		if errors.Is(err, ErrConcurrentLimitReached) {
+			rs.log.Warn("Could not render image, the render queue is full", "priority", opts.Priority, "path", opts.Path)
+			return rs.concurrentLimitReached(opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to wait in the render queue: %w", err)
+		}
+		defer release()
+	}
+
+	if !rs.begin() {
+		return nil, ErrShuttingDown
+	}
+	defer rs.inFlight.Done()
+
+	rs.log.Info("Rendering", "path", opts.Path)
 	renderKey, err := renderKeyProvider.get(ctx, opts.AuthOpts)
 	if err != nil {
 		return nil, err
@@ -308,7 +408,53 @@ func (rs *RenderingService) render(ctx context.Context, renderType RenderType, o
 	}()
 
 	metrics.MRenderingQueue.Set(float64(atomic.AddInt32(&rs.inProgressCount, 1)))
-	return rs.renderAction(ctx, renderType, renderKey, opts)
+	result, err := rs.renderAction(ctx, renderType, renderKey, opts)
+	if err == nil && resizable {
+		rs.resizeCache.set(opts, result.FilePath)
+	}
+	return result, err
+}
+
+// concurrentLimitReached returns the rendering limit image, or ErrConcurrentLimitReached when the options ask for errors
+func (rs *RenderingService) concurrentLimitReached(opts Opts) (*RenderResult, error) {
+	if opts.ErrorConcurrentLimitReached {
+		return nil, ErrConcurrentLimitReached
+	}
+
+	theme := models.ThemeDark
+	if opts.Theme != "" {
+		theme = opts.Theme
+	}
+	filePath := fmt.Sprintf("public/img/rendering_limit_%s.png", theme)
+	return &RenderResult{
+		FilePath: filepath.Join(rs.Cfg.HomePath, filePath),
+	}, nil
+}
+
+// renderFromResizeCache resizes a recent larger render of the same panel instead of rendering it again
+func (rs *RenderingService) renderFromResizeCache(opts Opts) (*RenderResult, bool) {
+	entry, ok := rs.resizeCache.get(opts)
+	if !ok {
+		return nil, false
+	}
+
+	width, height := pixelSize(opts)
+	if entry.width == width && entry.height == height {
+		return &RenderResult{FilePath: entry.filePath}, true
+	}
+
+	filePath, err := rs.getNewFilePath(RenderPNG)
+	if err != nil {
+		rs.log.Warn("Failed to resize cached render", "path", opts.Path, "error", err)
+		return nil, false
+	}
+	if err := resizePNG(entry.filePath, filePath, width, height); err != nil {
+		rs.log.Warn("Failed to resize cached render", "path", opts.Path, "error", err)
+		return nil, false
+	}
+
+	rs.log.Debug("Resized cached render", "path", opts.Path, "width", width, "height", height)
+	return &RenderResult{FilePath: filePath}, true
 }
 
 func (rs *RenderingService) RenderCSV(ctx context.Context, opts CSVOpts, session Session) (*RenderCSVResult, error) {
@@ -353,6 +499,11 @@ func (rs *RenderingService) renderCSV(ctx context.Context, opts CSVOpts, renderK
 		return nil, ErrRenderUnavailable
 	}
 
+	if !rs.begin() {
+		return nil, ErrShuttingDown
+	}
+	defer rs.inFlight.Done()
+
 	rs.log.Info("Rendering", "path", opts.Path)
 	renderKey, err := renderKeyProvider.get(ctx, opts.AuthOpts)
 	if err != nil {
@@ -175,6 +175,50 @@ func TestRenderLimitImageError(t *testing.T) {
 	assert.Nil(t, result)
 }
 
+func TestRenderDrain(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	rs := &RenderingService{
+		Cfg:                         &setting.Cfg{RendererUrl: "http://localhost:8081/render", RendererDrainTimeout: time.Minute},
+		log:                         log.New("test"),
+		perRequestRenderKeyProvider: fakeRenderKeyProvider{},
+	}
+	rs.renderAction = func(ctx context.Context, renderType RenderType, renderKey string, opts Opts) (*RenderResult, error) {
+		close(started)
+		<-release
+		return &RenderResult{FilePath: "rendered.png"}, nil
+	}
+	opts := Opts{ConcurrentLimit: 1}
+
+	rendered := make(chan *RenderResult)
+	go func() {
+		result, err := rs.Render(context.Background(), RenderPNG, opts, nil)
+		assert.NoError(t, err)
+		rendered <- result
+	}()
+	<-started
+
+	drained := make(chan struct{})
+	go func() {
+		rs.drain()
+		close(drained)
+	}()
+	require.Eventually(t, rs.draining.Load, time.Second, 10*time.Millisecond)
+
+	result, err := rs.Render(context.Background(), RenderPNG, opts, nil)
+	assert.ErrorIs(t, err, ErrShuttingDown)
+	assert.Nil(t, result)
+
+	select {
+	case <-drained:
+		t.Fatal("drain should wait for the render in progress")
+	default:
+	}
+	close(release)
+	assert.Equal(t, "rendered.png", (<-rendered).FilePath)
+	<-drained
+}
+
 func TestRenderingServiceGetRemotePluginVersion(t *testing.T) {
 	cfg := setting.NewCfg()
 	rs := &RenderingService{
@@ -198,6 +242,22 @@ func TestRenderingServiceGetRemotePluginVersion(t *testing.T) {
 		require.Equal(t, "2.7.1828", version)
 	})
 
+	t.Run("When renderer reports its capabilities should return them", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte("{\"version\":\"4.0.0\",\"capabilities\":[\"PDFRendering\",\"HTTPHeaders\"]}"))
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		rs.Cfg.RendererUrl = server.URL + "/render"
+		info, err := rs.getRemoteRendererInfo()
+
+		require.NoError(t, err)
+		require.Equal(t, remoteRendererInfo{Version: "4.0.0", Capabilities: []CapabilityName{PDFRendering, HTTPHeaders}}, info)
+	})
+
 	t.Run("When renderer responds with 404 should assume a valid but old version", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
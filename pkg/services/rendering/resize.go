@@ -0,0 +1,213 @@
+package rendering
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// aspectRatioTolerance is how much the aspect ratio of a cached render may differ from the
+// requested one. Panels lay out differently at other aspect ratios, so they can't be re-used.
+const aspectRatioTolerance = 0.01
+
+// sizeQueryParams are the query parameters of the render path that only change the size of the image
+var sizeQueryParams = []string{"width", "height", "scale"}
+
+type resizeCacheEntry struct {
+	filePath string
+	width    int
+	height   int
+	expires  time.Time
+}
+
+// resizeCache remembers recent PNG renders so that smaller renders of the same panel can be
+// produced by resizing the cached image instead of rendering it again.
+type resizeCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]resizeCacheEntry
+}
+
+func newResizeCache(ttl time.Duration) *resizeCache {
+	return &resizeCache{
+		ttl:     ttl,
+		entries: make(map[string]resizeCacheEntry),
+	}
+}
+
+func resizeCacheKey(opts Opts) string {
+	renderPath := opts.Path
+	if p, rawQuery, found := strings.Cut(opts.Path, "?"); found {
+		if query, err := url.ParseQuery(rawQuery); err == nil {
+			for _, param := range sizeQueryParams {
+				query.Del(param)
+			}
+			// Encode sorts the parameters so equivalent paths get the same key
+			renderPath = p + "?" + query.Encode()
+		}
+	}
+
+	return fmt.Sprintf("%d/%d/%s/%s/%s/%s/%s", opts.OrgID, opts.UserID, opts.OrgRole, opts.Theme, opts.Timezone,
+		strings.Join(opts.Headers["Accept-Language"], ","), renderPath)
+}
+
+// hasAbsoluteTimeRange returns true when the render path sets a time range that doesn't depend on
+// the time of the render. Resizing an older render of a relative time range would show stale data.
+func hasAbsoluteTimeRange(renderPath string) bool {
+	_, rawQuery, found := strings.Cut(renderPath, "?")
+	if !found {
+		return false
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return false
+	}
+
+	from, to := query.Get("from"), query.Get("to")
+	if from == "" || to == "" {
+		// the render uses the time range of the dashboard, which may be relative
+		return false
+	}
+	return !strings.Contains(from, "now") && !strings.Contains(to, "now")
+}
+
+// pixelSize returns the size in pixels of the image rendered with the given options
+func pixelSize(opts Opts) (int, int) {
+	scale := opts.DeviceScaleFactor
+	if scale <= 0 {
+		scale = 1
+	}
+	return int(math.Round(float64(opts.Width) * scale)), int(math.Round(float64(opts.Height) * scale))
+}
+
+// get returns a cached render that can be resized to the requested options, i.e. one that
+// is at least as large and has the same aspect ratio.
+func (c *resizeCache) get(opts Opts) (resizeCacheEntry, bool) {
+	width, height := pixelSize(opts)
+	if width <= 0 || height <= 0 {
+		return resizeCacheEntry{}, false
+	}
+
+	key := resizeCacheKey(opts)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok || entry.width < width || entry.height < height {
+		return resizeCacheEntry{}, false
+	}
+	requested := float64(width) / float64(height)
+	cached := float64(entry.width) / float64(entry.height)
+	if math.Abs(requested-cached)/cached > aspectRatioTolerance {
+		return resizeCacheEntry{}, false
+	}
+
+	// the cleanup service may have removed the file already
+	if _, err := os.Stat(entry.filePath); err != nil {
+		return resizeCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// set caches the render unless a larger render of the same panel is already cached
+func (c *resizeCache) set(opts Opts, filePath string) {
+	width, height := pixelSize(opts)
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	key := resizeCacheKey(opts)
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.entries[key]; ok && now.Before(existing.expires) && existing.width*existing.height > width*height {
+		return
+	}
+	c.entries[key] = resizeCacheEntry{filePath: filePath, width: width, height: height, expires: now.Add(c.ttl)}
+
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// resizePNG writes a copy of the PNG at srcPath scaled down to width x height to dstPath
+func resizePNG(srcPath string, dstPath string, width int, height int) error {
+	// nolint:gosec
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	img, err := png.Decode(src)
+	if err != nil {
+		return fmt.Errorf("failed to decode cached render: %w", err)
+	}
+
+	// nolint:gosec
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(dst, scaleDown(img, width, height)); err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("failed to encode resized render: %w", err)
+	}
+	return dst.Close()
+}
+
+// scaleDown resizes the image by averaging the source pixels that cover each destination pixel,
+// which keeps text and thin lines readable when shrinking.
+func scaleDown(src image.Image, width int, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	xRatio := float64(bounds.Dx()) / float64(width)
+	yRatio := float64(bounds.Dy()) / float64(height)
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + int(float64(y)*yRatio)
+		y1 := max(bounds.Min.Y+int(float64(y+1)*yRatio), y0+1)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + int(float64(x)*xRatio)
+			x1 := max(bounds.Min.X+int(float64(x+1)*xRatio), x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			if a == 0 {
+				continue
+			}
+			// RGBA returns alpha-premultiplied values, NRGBA stores them non-premultiplied
+			dst.Pix[i+0] = uint8(r * 0xff / a)
+			dst.Pix[i+1] = uint8(g * 0xff / a)
+			dst.Pix[i+2] = uint8(b * 0xff / a)
+			dst.Pix[i+3] = uint8((a / n) >> 8)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,120 @@
+package rendering
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeRenderKeyProvider struct{}
+
+func (fakeRenderKeyProvider) get(_ context.Context, _ AuthOpts) (string, error)    { return "key", nil }
+func (fakeRenderKeyProvider) afterRequest(_ context.Context, _ AuthOpts, _ string) {}
+
+func TestRenderResizeCache(t *testing.T) {
+	imagesDir := t.TempDir()
+	renders := 0
+	rs := &RenderingService{
+		Cfg:                         &setting.Cfg{ImagesDir: imagesDir, RendererUrl: "http://localhost:8081/render"},
+		log:                         log.New("test"),
+		perRequestRenderKeyProvider: fakeRenderKeyProvider{},
+		resizeCache:                 newResizeCache(time.Minute),
+	}
+	rs.renderAction = func(ctx context.Context, renderType RenderType, renderKey string, opts Opts) (*RenderResult, error) {
+		renders++
+		width, height := pixelSize(opts)
+		img := image.NewNRGBA(image.Rect(0, 0, width, height))
+		for i := range img.Pix {
+			img.Pix[i] = 0xff
+		}
+		filePath, err := rs.getNewFilePath(renderType)
+		require.NoError(t, err)
+		f, err := os.Create(filePath)
+		require.NoError(t, err)
+		require.NoError(t, png.Encode(f, img))
+		require.NoError(t, f.Close())
+		return &RenderResult{FilePath: filePath}, nil
+	}
+
+	render := func(t *testing.T, path string, width, height int, scale float64) image.Image {
+		t.Helper()
+		result, err := rs.Render(context.Background(), RenderPNG, Opts{
+			AuthOpts:          AuthOpts{OrgID: 1, UserID: 1},
+			Path:              path,
+			Width:             width,
+			Height:            height,
+			DeviceScaleFactor: scale,
+			Resizable:         true,
+		}, nil)
+		require.NoError(t, err)
+		f, err := os.Open(result.FilePath)
+		require.NoError(t, err)
+		defer func() { _ = f.Close() }()
+		img, err := png.Decode(f)
+		require.NoError(t, err)
+		return img
+	}
+
+	img := render(t, "d-solo/abc/dash?from=1700000000000&to=1700003600000&panelId=1&width=1000&height=500", 1000, 500, 2)
+	assert.Equal(t, image.Pt(2000, 1000), img.Bounds().Size())
+	assert.Equal(t, 1, renders)
+
+	t.Run("should resize a larger render of the same panel", func(t *testing.T) {
+		img := render(t, "d-solo/abc/dash?from=1700000000000&to=1700003600000&width=500&height=250&panelId=1", 500, 250, 1)
+		assert.Equal(t, image.Pt(500, 250), img.Bounds().Size())
+		assert.Equal(t, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, color.NRGBAModel.Convert(img.At(10, 10)))
+
+		img = render(t, "d-solo/abc/dash?from=1700000000000&to=1700003600000&panelId=1&width=1000&height=500&scale=0.5", 1000, 500, 0.5)
+		assert.Equal(t, image.Pt(500, 250), img.Bounds().Size())
+		assert.Equal(t, 1, renders)
+	})
+
+	t.Run("should render again for another aspect ratio, a larger size or another panel", func(t *testing.T) {
+		render(t, "d-solo/abc/dash?from=1700000000000&to=1700003600000&panelId=1&width=500&height=500", 500, 500, 1)
+		assert.Equal(t, 2, renders)
+		render(t, "d-solo/abc/dash?from=1700000000000&to=1700003600000&panelId=1&width=2000&height=1000", 2000, 1000, 2)
+		assert.Equal(t, 3, renders)
+		render(t, "d-solo/abc/dash?from=1700000000000&to=1700003600000&panelId=2&width=500&height=250", 500, 250, 1)
+		assert.Equal(t, 4, renders)
+	})
+
+	t.Run("should not resize renders of a relative time range", func(t *testing.T) {
+		render(t, "d-solo/abc/dash?from=now-6h&to=now&panelId=3&width=1000&height=500", 1000, 500, 1)
+		render(t, "d-solo/abc/dash?from=now-6h&to=now&panelId=3&width=500&height=250", 500, 250, 1)
+		assert.Equal(t, 6, renders)
+		render(t, "d-solo/abc/dash?panelId=3&width=500&height=250", 500, 250, 1)
+		assert.Equal(t, 7, renders)
+	})
+
+	t.Run("should not resize renders that don't allow it", func(t *testing.T) {
+		_, err := rs.Render(context.Background(), RenderPNG, Opts{
+			AuthOpts: AuthOpts{OrgID: 1, UserID: 1},
+			Path:     "d-solo/abc/dash?from=1700000000000&to=1700003600000&panelId=1&width=500&height=250",
+			Width:    500,
+			Height:   250,
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 8, renders)
+	})
+
+	t.Run("should render again once the cached file is gone", func(t *testing.T) {
+		files, err := filepath.Glob(filepath.Join(imagesDir, "*.png"))
+		require.NoError(t, err)
+		for _, f := range files {
+			require.NoError(t, os.Remove(f))
+		}
+		render(t, "d-solo/abc/dash?from=1700000000000&to=1700003600000&panelId=1&width=500&height=250", 500, 250, 1)
+		assert.Equal(t, 9, renders)
+	})
+}
@@ -0,0 +1,169 @@
+package rendering
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	renderSigningKeysCacheKey = "render-signing-keys"
+	// renderSigningKeysReloadInterval is how often the keys rotated by other instances are loaded
+	renderSigningKeysReloadInterval = time.Minute
+	// renderSigningKeysMinReloadInterval is how often the keys are loaded at most to find an unknown key
+	renderSigningKeysMinReloadInterval = 5 * time.Second
+)
+
+type renderSigningKey struct {
+	ID      string    `json:"id"`
+	Secret  []byte    `json:"secret"`
+	Created time.Time `json:"created"`
+}
+
+// SigningKey is a key signing the render keys of the renderer, without its secret
+type SigningKey struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	// Current is set for the key signing the new render keys, the previous key only verifies them
+	Current bool `json:"current"`
+}
+
+// renderSigningKeys are the keys signing the JWT render keys, shared by the instances through the remote cache.
+// The current key signs the new render keys, the previous one still verifies the render keys it signed before
+// the rotation. The current key is rotated after the rotation interval, or through the admin API.
+type renderSigningKeys struct {
+	cache            *remotecache.RemoteCache
+	rotationInterval time.Duration
+
+	mu sync.Mutex
+	// keys are the current key, then the previous one
+	keys   []renderSigningKey
+	loaded time.Time
+}
+
+func newRenderSigningKeys(cache *remotecache.RemoteCache, rotationInterval time.Duration) *renderSigningKeys {
+	return &renderSigningKeys{cache: cache, rotationInterval: rotationInterval}
+}
+
+// current returns the key signing the new render keys
+func (k *renderSigningKeys) current(ctx context.Context) (renderSigningKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if time.Since(k.loaded) > renderSigningKeysReloadInterval {
+		if err := k.load(ctx); err != nil {
+			return renderSigningKey{}, err
+		}
+	}
+	if len(k.keys) == 0 || time.Since(k.keys[0].Created) > k.rotationInterval {
+		if err := k.rotateLocked(ctx); err != nil {
+			return renderSigningKey{}, err
+		}
+	}
+	return k.keys[0], nil
+}
+
+// secret returns the secret of the key, the keys are loaded again once when it's unknown
+func (k *renderSigningKeys) secret(ctx context.Context, id string) ([]byte, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	lookup := func() ([]byte, bool) {
+		for _, key := range k.keys {
+			if key.ID == id {
+				return key.Secret, true
+			}
+		}
+		return nil, false
+	}
+	if secret, ok := lookup(); ok {
+		return secret, true
+	}
+	// the key may have been rotated by another instance, the unknown keys don't load them more than once in a while
+	if time.Since(k.loaded) < renderSigningKeysMinReloadInterval {
+		return nil, false
+	}
+	if err := k.load(ctx); err != nil {
+		return nil, false
+	}
+	return lookup()
+}
+
+// rotate replaces the current key by a new one, the current key becomes the previous one
+func (k *renderSigningKeys) rotate(ctx context.Context) ([]SigningKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := k.load(ctx); err != nil {
+		return nil, err
+	}
+	if err := k.rotateLocked(ctx); err != nil {
+		return nil, err
+	}
+	return k.list(), nil
+}
+
+// get returns the keys without their secrets
+func (k *renderSigningKeys) get(ctx context.Context) ([]SigningKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := k.load(ctx); err != nil {
+		return nil, err
+	}
+	return k.list(), nil
+}
+
+func (k *renderSigningKeys) list() []SigningKey {
+	keys := make([]SigningKey, 0, len(k.keys))
+	for i, key := range k.keys {
+		keys = append(keys, SigningKey{ID: key.ID, Created: key.Created, Current: i == 0})
+	}
+	return keys
+}
+
+func (k *renderSigningKeys) load(ctx context.Context) error {
+	data, err := k.cache.Get(ctx, renderSigningKeysCacheKey)
+	if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		k.keys, k.loaded = nil, time.Now()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var keys []renderSigningKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	k.keys, k.loaded = keys, time.Now()
+	return nil
+}
+
+func (k *renderSigningKeys) rotateLocked(ctx context.Context) error {
+	secret := make([]byte, 64)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+
+	keys := []renderSigningKey{{ID: util.GenerateShortUID(), Secret: secret, Created: time.Now()}}
+	if len(k.keys) > 0 {
+		keys = append(keys, k.keys[0])
+	}
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	// the keys are kept as long as they're rotated, and expire when no instance uses them anymore
+	if err := k.cache.Set(ctx, renderSigningKeysCacheKey, data, 2*k.rotationInterval); err != nil {
+		return err
+	}
+	k.keys, k.loaded = keys, time.Now()
+	return nil
+}
@@ -0,0 +1,75 @@
+package rendering
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationRenderSigningKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	cache := remotecache.NewFakeStore(t)
+	rs := &RenderingService{
+		Cfg:         &setting.Cfg{RendererAuthToken: "renderer-token"},
+		log:         log.New("test"),
+		features:    featuremgmt.WithManager(featuremgmt.FlagRenderAuthJWT),
+		signingKeys: newRenderSigningKeys(cache, time.Hour),
+	}
+	provider := &jwtRenderKeyProvider{log: rs.log, keys: rs.signingKeys, keyExpiry: time.Minute}
+	opts := AuthOpts{OrgID: 1, UserID: 2, OrgRole: org.RoleViewer}
+
+	renderKey, err := provider.get(ctx, opts)
+	require.NoError(t, err)
+	renderUser, ok := rs.GetRenderUser(ctx, renderKey)
+	require.True(t, ok)
+	assert.Equal(t, &RenderUser{OrgID: 1, UserID: 2, OrgRole: "Viewer"}, renderUser)
+
+	t.Run("should not accept the render keys signed by the renderer token", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS512, provider.buildJWTClaims(opts))
+		signed, err := token.SignedString([]byte(rs.Cfg.RendererAuthToken))
+		require.NoError(t, err)
+		_, ok := rs.GetRenderUser(ctx, signed)
+		assert.False(t, ok)
+	})
+
+	t.Run("should verify the render keys of the previous key after a rotation", func(t *testing.T) {
+		keys, err := rs.RotateSigningKey(ctx)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.True(t, keys[0].Current)
+		assert.False(t, keys[1].Current)
+
+		_, ok := rs.GetRenderUser(ctx, renderKey)
+		assert.True(t, ok)
+
+		// another instance loads the rotated keys from the remote cache
+		other := newRenderSigningKeys(cache, time.Hour)
+		current, err := other.current(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, keys[0].ID, current.ID)
+
+		_, err = rs.RotateSigningKey(ctx)
+		require.NoError(t, err)
+		_, ok = rs.GetRenderUser(ctx, renderKey)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,132 @@
+package rendering
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// rendererTLSClient is the HTTP client of the remote renderer trusting a private CA or presenting a client
+// certificate. The certificates are loaded again when their files change, so they can be rotated without a restart.
+type rendererTLSClient struct {
+	log            log.Logger
+	caCertPath     string
+	clientCertPath string
+	clientKeyPath  string
+
+	mu       sync.Mutex
+	modTimes []time.Time
+	client   *http.Client
+}
+
+// newRendererTLSClient returns the client of the TLS settings of the renderer, or nil when there are none
+func newRendererTLSClient(cfg *setting.Cfg, logger log.Logger) (*rendererTLSClient, error) {
+	if cfg.RendererCACert == "" && cfg.RendererClientCert == "" && cfg.RendererClientKey == "" {
+		return nil, nil
+	}
+	if (cfg.RendererClientCert == "") != (cfg.RendererClientKey == "") {
+		return nil, errors.New("renderer_client_cert and renderer_client_key must be set together")
+	}
+
+	c := &rendererTLSClient{
+		log:            logger,
+		caCertPath:     cfg.RendererCACert,
+		clientCertPath: cfg.RendererClientCert,
+		clientKeyPath:  cfg.RendererClientKey,
+	}
+	c.modTimes = c.stat()
+	client, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+	return c, nil
+}
+
+// get returns the client, with the certificates loaded again when their files changed. The previous client is
+// kept when the new certificates can't be loaded, e.g. while the files are being replaced.
+func (c *rendererTLSClient) get() *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	modTimes := c.stat()
+	if slices.Equal(modTimes, c.modTimes) {
+		return c.client
+	}
+	client, err := c.load()
+	if err != nil {
+		c.log.Warn("Failed to reload the certificates of the remote renderer", "error", err)
+		return c.client
+	}
+
+	c.log.Info("Reloaded the certificates of the remote renderer")
+	c.client.CloseIdleConnections()
+	c.client = client
+	c.modTimes = modTimes
+	return c.client
+}
+
+// stat returns the modification times of the certificate files, zero for the unset ones or the missing files
+func (c *rendererTLSClient) stat() []time.Time {
+	modTimes := make([]time.Time, 0, 3)
+	for _, path := range []string{c.caCertPath, c.clientCertPath, c.clientKeyPath} {
+		var modTime time.Time
+		if path != "" {
+			if info, err := os.Stat(path); err == nil {
+				modTime = info.ModTime()
+			}
+		}
+		modTimes = append(modTimes, modTime)
+	}
+	return modTimes
+}
+
+func (c *rendererTLSClient) load() (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.caCertPath != "" {
+		// nolint:gosec
+		caCert, err := os.ReadFile(c.caCertPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificate of the renderer: %w", err)
+		}
+		// the private CAs are trusted in addition to the system ones
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no PEM certificate found in %s", c.caCertPath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.clientCertPath != "" {
+		cert, err := tls.LoadX509KeyPair(c.clientCertPath, c.clientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate of the renderer: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := netTransport.Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
+
+// httpClient returns the client of the requests to the remote renderer
+func (rs *RenderingService) httpClient() *http.Client {
+	if rs.tlsClient != nil {
+		return rs.tlsClient.get()
+	}
+	return netClient
+}
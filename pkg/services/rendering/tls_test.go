@@ -0,0 +1,143 @@
+package rendering
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert returns a certificate signed by the parent, or a self-signed CA certificate without a parent
+func newTestCert(t *testing.T, parent *testCert, template *x509.Certificate) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	} else {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func TestRendererTLSClient(t *testing.T) {
+	ca := newTestCert(t, nil, &x509.Certificate{Subject: pkix.Name{CommonName: "renderer CA"}})
+	otherCA := newTestCert(t, nil, &x509.Certificate{Subject: pkix.Name{CommonName: "other CA"}})
+	serverCert := newTestCert(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "renderer"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	clientCert := newTestCert(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "grafana"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	serverKeyPair, err := tls.X509KeyPair(serverCert.certPEM, serverCert.keyPEM)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverKeyPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	cfg := &setting.Cfg{
+		RendererCACert:     filepath.Join(dir, "ca.pem"),
+		RendererClientCert: filepath.Join(dir, "client.pem"),
+		RendererClientKey:  filepath.Join(dir, "client-key.pem"),
+	}
+	modTime := time.Now()
+	writeFile := func(path string, content []byte) {
+		require.NoError(t, os.WriteFile(path, content, 0600))
+		modTime = modTime.Add(time.Second)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	writeFile(cfg.RendererCACert, otherCA.certPEM)
+	writeFile(cfg.RendererClientCert, clientCert.certPEM)
+	writeFile(cfg.RendererClientKey, clientCert.keyPEM)
+
+	c, err := newRendererTLSClient(cfg, log.New("test"))
+	require.NoError(t, err)
+	get := func() error {
+		resp, err := c.get().Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	t.Run("should not trust the renderer signed by another CA", func(t *testing.T) {
+		assert.Error(t, get())
+	})
+
+	t.Run("should reload the CA bundle when it changes", func(t *testing.T) {
+		writeFile(cfg.RendererCACert, ca.certPEM)
+		assert.NoError(t, get())
+	})
+
+	t.Run("should keep the previous certificates when the new ones are invalid", func(t *testing.T) {
+		writeFile(cfg.RendererCACert, []byte("invalid"))
+		assert.NoError(t, get())
+	})
+
+	t.Run("should require the client certificate with its key", func(t *testing.T) {
+		_, err := newRendererTLSClient(&setting.Cfg{RendererClientCert: cfg.RendererClientCert}, log.New("test"))
+		assert.Error(t, err)
+
+		c, err := newRendererTLSClient(&setting.Cfg{}, log.New("test"))
+		require.NoError(t, err)
+		assert.Nil(t, c)
+	})
+}
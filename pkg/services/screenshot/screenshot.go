@@ -123,6 +123,7 @@ func (s *HeadlessScreenshotService) Take(ctx context.Context, opts ScreenshotOpt
 		Height:          opts.Height,
 		Theme:           opts.Theme,
 		ConcurrentLimit: s.cfg.AlertingRenderLimit,
+		Priority:        rendering.PriorityAlerting,
 		Path:            u.String(),
 	}
 
@@ -55,6 +55,7 @@ func TestHeadlessScreenshotService(t *testing.T) {
 		Theme:           DefaultTheme,
 		Path:            "d-solo/foo/bar?from=now-6h&orgId=2&panelId=4&to=now-2h",
 		ConcurrentLimit: cfg.AlertingRenderLimit,
+		Priority:        rendering.PriorityAlerting,
 	}
 
 	opts.From = "now-6h"
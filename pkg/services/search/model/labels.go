@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxLabelNameLength is the length of the name column of the dashboard labels
+	MaxLabelNameLength = 50
+	// MaxLabelValueLength is the length of the value column of the dashboard labels
+	MaxLabelValueLength = 190
+)
+
+var labelNameRegexp = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+
+type LabelOperator string
+
+const (
+	LabelEquals    LabelOperator = "="
+	LabelNotEquals LabelOperator = "!="
+	LabelExists    LabelOperator = "exists"
+	LabelNotExists LabelOperator = "!exists"
+)
+
+// LabelSelector matches the dashboards and folders by label, in the syntax of the Kubernetes
+// equality based selectors: name=value, name!=value, name and !name.
+type LabelSelector struct {
+	Name     string
+	Operator LabelOperator
+	Value    string
+}
+
+// ValidateLabelName returns an error when the name isn't a valid label name
+func ValidateLabelName(name string) error {
+	if len(name) > MaxLabelNameLength || !labelNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid label name %q, names are up to %d letters, digits, '.', '_', '-' or '/' starting and ending with a letter or digit", name, MaxLabelNameLength)
+	}
+	return nil
+}
+
+// ValidateLabels returns an error when a name or a value of the labels is invalid
+func ValidateLabels(labels map[string]string) error {
+	for name, value := range labels {
+		if err := ValidateLabelName(name); err != nil {
+			return err
+		}
+		if len(value) > MaxLabelValueLength {
+			return fmt.Errorf("the value of label %q exceeds %d characters", name, MaxLabelValueLength)
+		}
+	}
+	return nil
+}
+
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	s = strings.TrimSpace(s)
+	var selector LabelSelector
+	switch {
+	case strings.Contains(s, "!="):
+		name, value, _ := strings.Cut(s, "!=")
+		selector = LabelSelector{Name: strings.TrimSpace(name), Operator: LabelNotEquals, Value: strings.TrimSpace(value)}
+	case strings.Contains(s, "="):
+		name, value, _ := strings.Cut(s, "=")
+		selector = LabelSelector{Name: strings.TrimSpace(name), Operator: LabelEquals, Value: strings.TrimSpace(strings.TrimPrefix(value, "="))}
+	case strings.HasPrefix(s, "!"):
+		selector = LabelSelector{Name: strings.TrimSpace(strings.TrimPrefix(s, "!")), Operator: LabelNotExists}
+	default:
+		selector = LabelSelector{Name: s, Operator: LabelExists}
+	}
+
+	if err := ValidateLabelName(selector.Name); err != nil {
+		return LabelSelector{}, fmt.Errorf("invalid label selector %q: %w", s, err)
+	}
+	return selector, nil
+}
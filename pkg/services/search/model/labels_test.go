@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	for s, expected := range map[string]LabelSelector{
+		"env=prod":          {Name: "env", Operator: LabelEquals, Value: "prod"},
+		"env==prod":         {Name: "env", Operator: LabelEquals, Value: "prod"},
+		" team != db ":      {Name: "team", Operator: LabelNotEquals, Value: "db"},
+		"env=":              {Name: "env", Operator: LabelEquals},
+		"example.com/owner": {Name: "example.com/owner", Operator: LabelExists},
+		"!deprecated":       {Name: "deprecated", Operator: LabelNotExists},
+	} {
+		selector, err := ParseLabelSelector(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, selector, s)
+	}
+
+	for _, s := range []string{"", "=prod", "-env=prod", "env name=prod", "!"} {
+		_, err := ParseLabelSelector(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -36,6 +36,19 @@ type FilterLeftJoin interface {
 	LeftJoin() string
 }
 
+// TempTable holds values too many to be passed as parameters of the
+// query, they are inserted in a temporary table with a single uid column.
+type TempTable struct {
+	Name   string
+	Values []any
+}
+
+// FilterTempTables returns the temporary tables joined by the filter,
+// they must be created in the session of the query before running it.
+type FilterTempTables interface {
+	TempTables() []TempTable
+}
+
 type FilterSelect interface {
 	Select() string
 }
@@ -95,3 +108,45 @@ func (s HitList) Less(i, j int) bool {
 
 	return strings.ToLower(s[i].Title) < strings.ToLower(s[j].Title)
 }
+
+// FacetValue is a value of a search facet with the number of search results that have it
+type FacetValue struct {
+	Value string `json:"value"`
+	// Title is the title of the folders, it's empty for the root folder
+	Title string `json:"title,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// TotalsMode is how the total number of results of a search is computed
+type TotalsMode string
+
+const (
+	// TotalsNone doesn't compute the total
+	TotalsNone TotalsMode = "none"
+	// TotalsExact counts all the results
+	TotalsExact TotalsMode = "exact"
+	// TotalsEstimated counts the results up to a maximum, above which the total is a lower bound
+	TotalsEstimated TotalsMode = "estimated"
+)
+
+// TotalRelation tells if the total of the results is exact or a lower bound
+type TotalRelation string
+
+const (
+	TotalRelationEqual        TotalRelation = "eq"
+	TotalRelationGreaterEqual TotalRelation = "gte"
+)
+
+// Total is the number of results of a search
+type Total struct {
+	Value    int64         `json:"value"`
+	Relation TotalRelation `json:"relation"`
+}
+
+// Facets are the most frequent tags, folders, data sources and panel types of the search results
+type Facets struct {
+	Tags        []*FacetValue `json:"tags"`
+	Folders     []*FacetValue `json:"folders"`
+	Datasources []*FacetValue `json:"datasources"`
+	PanelTypes  []*FacetValue `json:"panelTypes"`
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
 	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 	"github.com/grafana/grafana/pkg/services/star"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -31,6 +32,7 @@ func ProvideService(cfg *setting.Cfg, sqlstore db.DB, starService star.Service,
 type Query struct {
 	Title         string
 	Tags          []string
+	Labels        []model.LabelSelector
 	OrgId         int64
 	SignedInUser  *user.SignedInUser
 	Limit         int64
@@ -48,6 +50,11 @@ type Query struct {
 
 type Service interface {
 	SearchHandler(context.Context, *Query) (model.HitList, error)
+	// FacetsHandler returns the most frequent tags, folders, data sources and panel types of the
+	// results of the query, up to the limit of the query per facet.
+	FacetsHandler(context.Context, *Query) (*model.Facets, error)
+	// TotalHandler returns the total number of results of the query, computed as requested by the mode.
+	TotalHandler(context.Context, *Query, model.TotalsMode) (*model.Total, error)
 	SortOptions() []model.SortOption
 }
 
@@ -80,21 +87,7 @@ func (s *SearchService) SearchHandler(ctx context.Context, query *Query) (model.
 		}
 	}
 
-	metrics.MFolderIDsServiceCount.WithLabelValues(metrics.Search).Inc()
-	dashboardQuery := dashboards.FindPersistedDashboardsQuery{
-		Title:         query.Title,
-		SignedInUser:  query.SignedInUser,
-		DashboardUIDs: query.DashboardUIDs,
-		DashboardIds:  query.DashboardIds,
-		Type:          query.Type,
-		FolderIds:     query.FolderIds, // nolint:staticcheck
-		FolderUIDs:    query.FolderUIDs,
-		Tags:          query.Tags,
-		Limit:         query.Limit,
-		Page:          query.Page,
-		Permission:    query.Permission,
-	}
-
+	dashboardQuery := dashboardsQuery(query)
 	if sortOpt, exists := s.sortOptions[query.Sort]; exists {
 		dashboardQuery.Sort = sortOpt
 	}
@@ -140,3 +133,85 @@ func sortedHits(unsorted model.HitList) model.HitList {
 
 	return hits
 }
+
+func (s *SearchService) FacetsHandler(ctx context.Context, query *Query) (*model.Facets, error) {
+	dashboardQuery, ok, err := s.filteredDashboardsQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &model.Facets{Tags: []*model.FacetValue{}, Folders: []*model.FacetValue{}, Datasources: []*model.FacetValue{}, PanelTypes: []*model.FacetValue{}}, nil
+	}
+
+	return s.dashboardService.GetSearchFacets(ctx, &dashboardQuery)
+}
+
+// estimatedTotalMax is the number of results counted by the estimated totals
+const estimatedTotalMax = 10000
+
+func (s *SearchService) TotalHandler(ctx context.Context, query *Query, mode model.TotalsMode) (*model.Total, error) {
+	dashboardQuery, ok, err := s.filteredDashboardsQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &model.Total{Relation: model.TotalRelationEqual}, nil
+	}
+
+	var max int64
+	if mode == model.TotalsEstimated {
+		// counts one more result than the maximum to tell if there are more
+		max = estimatedTotalMax + 1
+	}
+	total, err := s.dashboardService.CountSearchResults(ctx, &dashboardQuery, max)
+	if err != nil {
+		return nil, err
+	}
+
+	if max > 0 && total >= max {
+		return &model.Total{Value: estimatedTotalMax, Relation: model.TotalRelationGreaterEqual}, nil
+	}
+	return &model.Total{Value: total, Relation: model.TotalRelationEqual}, nil
+}
+
+// filteredDashboardsQuery returns the dashboards query of the search, including all of its filters, or false
+// if the search can't have any results.
+func (s *SearchService) filteredDashboardsQuery(ctx context.Context, query *Query) (dashboards.FindPersistedDashboardsQuery, bool, error) {
+	dashboardQuery := dashboardsQuery(query)
+	if !query.IsStarred {
+		return dashboardQuery, true, nil
+	}
+
+	starred, err := s.starService.GetByUser(ctx, &star.GetUserStarsQuery{UserID: query.SignedInUser.UserID})
+	if err != nil {
+		return dashboardQuery, false, err
+	}
+	if len(starred.UserStars) == 0 {
+		return dashboardQuery, false, nil
+	}
+	// the starred dashboards are an additional filter, the IDs and UIDs of the query still apply
+	ids := make([]int64, 0, len(starred.UserStars))
+	for id := range starred.UserStars {
+		ids = append(ids, id)
+	}
+	dashboardQuery.Filters = append(dashboardQuery.Filters, searchstore.DashboardIDFilter{IDs: ids})
+	return dashboardQuery, true, nil
+}
+
+func dashboardsQuery(query *Query) dashboards.FindPersistedDashboardsQuery {
+	metrics.MFolderIDsServiceCount.WithLabelValues(metrics.Search).Inc()
+	return dashboards.FindPersistedDashboardsQuery{
+		Title:         query.Title,
+		SignedInUser:  query.SignedInUser,
+		DashboardUIDs: query.DashboardUIDs,
+		DashboardIds:  query.DashboardIds,
+		Type:          query.Type,
+		FolderIds:     query.FolderIds, // nolint:staticcheck
+		FolderUIDs:    query.FolderUIDs,
+		Tags:          query.Tags,
+		Labels:        query.Labels,
+		Limit:         query.Limit,
+		Page:          query.Page,
+		Permission:    query.Permission,
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 	"github.com/grafana/grafana/pkg/services/star"
 	"github.com/grafana/grafana/pkg/services/star/startest"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -92,3 +93,55 @@ func TestSearch_StarredResults(t *testing.T) {
 	assert.Equal(t, "A", hits[0].Title)
 	assert.Equal(t, "C", hits[1].Title)
 }
+
+func TestSearch_StarredFacets(t *testing.T) {
+	ss := startest.NewStarServiceFake()
+	ds := dashboards.NewFakeDashboardService(t)
+	ds.On("GetSearchFacets", mock.Anything, mock.MatchedBy(func(q *dashboards.FindPersistedDashboardsQuery) bool {
+		// the UIDs of the query are kept, the starred dashboards are filtered by ID
+		return len(q.DashboardUIDs) == 1 && len(q.Filters) == 1 &&
+			assert.ElementsMatch(t, []int64{1, 3}, q.Filters[0].(searchstore.DashboardIDFilter).IDs)
+	})).Return(&model.Facets{Tags: []*model.FacetValue{{Value: "prod", Count: 1}}}, nil)
+	ss.ExpectedUserStars = &star.GetUserStarsResult{UserStars: map[int64]bool{1: true, 3: true}}
+	svc := &SearchService{
+		starService:      ss,
+		dashboardService: ds,
+	}
+
+	facets, err := svc.FacetsHandler(context.Background(), &Query{
+		IsStarred:     true,
+		DashboardUIDs: []string{"a"},
+		SignedInUser:  &user.SignedInUser{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.FacetValue{{Value: "prod", Count: 1}}, facets.Tags)
+
+	ss.ExpectedUserStars = &star.GetUserStarsResult{UserStars: map[int64]bool{}}
+	facets, err = svc.FacetsHandler(context.Background(), &Query{IsStarred: true, SignedInUser: &user.SignedInUser{}})
+	require.NoError(t, err)
+	assert.Empty(t, facets.Tags)
+}
+
+func TestSearch_Total(t *testing.T) {
+	ds := dashboards.NewFakeDashboardService(t)
+	svc := &SearchService{
+		starService:      startest.NewStarServiceFake(),
+		dashboardService: ds,
+	}
+
+	ds.On("CountSearchResults", mock.Anything, mock.Anything, int64(0)).Return(int64(12345), nil).Once()
+	total, err := svc.TotalHandler(context.Background(), &Query{SignedInUser: &user.SignedInUser{}}, model.TotalsExact)
+	require.NoError(t, err)
+	assert.Equal(t, &model.Total{Value: 12345, Relation: model.TotalRelationEqual}, total)
+
+	// the estimated totals stop counting above the maximum
+	ds.On("CountSearchResults", mock.Anything, mock.Anything, int64(estimatedTotalMax+1)).Return(int64(estimatedTotalMax+1), nil).Once()
+	total, err = svc.TotalHandler(context.Background(), &Query{SignedInUser: &user.SignedInUser{}}, model.TotalsEstimated)
+	require.NoError(t, err)
+	assert.Equal(t, &model.Total{Value: estimatedTotalMax, Relation: model.TotalRelationGreaterEqual}, total)
+
+	ds.On("CountSearchResults", mock.Anything, mock.Anything, int64(estimatedTotalMax+1)).Return(int64(42), nil).Once()
+	total, err = svc.TotalHandler(context.Background(), &Query{SignedInUser: &user.SignedInUser{}}, model.TotalsEstimated)
+	require.NoError(t, err)
+	assert.Equal(t, &model.Total{Value: 42, Relation: model.TotalRelationEqual}, total)
+}
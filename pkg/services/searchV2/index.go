@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/store/entity"
 	kdash "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
+	"github.com/grafana/grafana/pkg/services/warmup"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -104,6 +105,8 @@ type searchIndex struct {
 	tracer                  tracing.Tracer
 	features                featuremgmt.FeatureToggles
 	settings                setting.SearchSettings
+	// warmupTask reports the progress of the initial indexing
+	warmupTask *warmup.Task
 }
 
 func newSearchIndex(dashLoader dashboardLoader, evStore eventStore, extender DocumentExtender, folderIDs folderUIDLookup, tracer tracing.Tracer, features featuremgmt.FeatureToggles, settings setting.SearchSettings) *searchIndex {
@@ -197,6 +200,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 	var lastEventID int64
 	lastEvent, err := i.eventStore.GetLastEvent(initialSetupCtx)
 	if err != nil {
+		i.warmupTask.Finish(err)
 		initialSetupSpan.End()
 		return err
 	}
@@ -205,6 +209,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 	}
 
 	err = i.buildInitialIndexes(initialSetupCtx, orgIDs)
+	i.warmupTask.Finish(err)
 	if err != nil {
 		initialSetupSpan.End()
 		return err
@@ -310,7 +315,8 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 func (i *searchIndex) buildInitialIndexes(ctx context.Context, orgIDs []int64) error {
 	started := time.Now()
 	i.logger.Info("Start building in-memory indexes")
-	for _, orgID := range orgIDs {
+	for n, orgID := range orgIDs {
+		i.warmupTask.SetProgress(n, len(orgIDs))
 		err := i.buildInitialIndex(ctx, orgID)
 		if err != nil {
 			return fmt.Errorf("can't build initial dashboard search index for org %d: %w", orgID, err)
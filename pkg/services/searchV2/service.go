@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/warmup"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -75,6 +76,7 @@ type StandardSearchService struct {
 	extender       DashboardIndexExtender
 	reIndexCh      chan struct{}
 	features       featuremgmt.FeatureToggles
+	warmup         *warmup.Service
 }
 
 func (s *StandardSearchService) IsReady(ctx context.Context, orgId int64) IsSearchReadyResponse {
@@ -83,7 +85,7 @@ func (s *StandardSearchService) IsReady(ctx context.Context, orgId int64) IsSear
 
 func ProvideService(cfg *setting.Cfg, sql db.DB, entityEventStore store.EntityEventsService,
 	ac accesscontrol.Service, tracer tracing.Tracer, features featuremgmt.FeatureToggles, orgService org.Service,
-	userService user.Service, folderService folder.Service) SearchService {
+	userService user.Service, folderService folder.Service, warmupService *warmup.Service) SearchService {
 	extender := &NoopExtender{}
 	logger := log.New("searchV2")
 	s := &StandardSearchService{
@@ -111,6 +113,10 @@ func ProvideService(cfg *setting.Cfg, sql db.DB, entityEventStore store.EntityEv
 		orgService:  orgService,
 		userService: userService,
 		features:    features,
+		warmup:      warmupService,
+	}
+	if warmupService != nil && !s.IsDisabled() {
+		s.dashboardIndex.warmupTask = warmupService.Track("search_index")
 	}
 	return s
 }
@@ -120,6 +126,13 @@ func (s *StandardSearchService) IsDisabled() bool {
 }
 
 func (s *StandardSearchService) Run(ctx context.Context) error {
+	if s.warmup != nil {
+		// the initial index is built once the plugins are loaded and the data sources are provisioned
+		if err := s.warmup.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
 	orgQuery := &org.SearchOrgsQuery{}
 	result, err := s.orgService.Search(ctx, orgQuery)
 	if err != nil {
@@ -0,0 +1,196 @@
+package sharelink
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(s.accessControl)
+	canShare := authorize(accesscontrol.EvalPermission(dashboards.ActionDashboardsWrite,
+		dashboards.ScopeDashboardsProvider.GetResourceScopeUID(accesscontrol.Parameter(":uid"))))
+
+	routeRegister.Group("/api/dashboards/uid/:uid/share-links", func(r routing.RouteRegister) {
+		r.Post("/", canShare, routing.Wrap(s.createHandler))
+		r.Get("/", canShare, routing.Wrap(s.listHandler))
+		r.Delete("/:linkUid", canShare, routing.Wrap(s.revokeHandler))
+	}, middleware.ReqSignedInNoAnonymous)
+	routeRegister.Get("/share-links/:token", s.openHandler)
+}
+
+type CreateShareLinkCommand struct {
+	// Duration of the link, e.g. 24h. Defaults to and can't exceed share_link_max_duration.
+	Duration string `json:"duration"`
+	// From and To pin the time range the dashboard is opened with, e.g. now-6h and now
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// swagger:model
+type ShareLinkDTO struct {
+	*Link
+	// URL opens the dashboard with the link, it's only returned when the link is created
+	URL string `json:"url"`
+}
+
+// swagger:route POST /dashboards/uid/{uid}/share-links dashboards createShareLink
+//
+// Create a temporary link to view the dashboard without an account.
+//
+// The viewers of the link can only view the dashboard and query the data sources it uses, until the
+// link expires or is revoked.
+//
+// Responses:
+// 200: createShareLinkResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) createHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := CreateShareLinkCommand{}
+	if c.Req.ContentLength != 0 {
+		if err := web.Bind(c.Req, &cmd); err != nil {
+			return response.Error(http.StatusBadRequest, "bad request data", err)
+		}
+	}
+	duration := s.cfg.ShareLinkMaxDuration
+	if cmd.Duration != "" {
+		var err error
+		if duration, err = gtime.ParseDuration(cmd.Duration); err != nil {
+			return response.Error(http.StatusBadRequest, "Invalid duration", err)
+		}
+	}
+
+	link, token, err := s.Create(c.Req.Context(), &CreateLinkCommand{
+		OrgID:          c.SignedInUser.GetOrgID(),
+		DashboardUID:   web.Params(c.Req)[":uid"],
+		From:           cmd.From,
+		To:             cmd.To,
+		Duration:       duration,
+		CreatedBy:      c.SignedInUser.UserID,
+		CreatedByLogin: c.SignedInUser.GetLogin(),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create share link", err)
+	}
+	return response.JSON(http.StatusOK, ShareLinkDTO{Link: link, URL: strings.TrimSuffix(s.cfg.AppURL, "/") + "/share-links/" + token})
+}
+
+// swagger:route GET /dashboards/uid/{uid}/share-links dashboards getShareLinks
+//
+// Get the share links of the dashboard, most recent first, including the expired and revoked ones.
+//
+// Responses:
+// 200: getShareLinksResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) listHandler(c *contextmodel.ReqContext) response.Response {
+	links, err := s.List(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get share links", err)
+	}
+	return response.JSON(http.StatusOK, links)
+}
+
+// swagger:route DELETE /dashboards/uid/{uid}/share-links/{link_uid} dashboards revokeShareLink
+//
+// Revoke a share link.
+//
+// The viewers who opened the link lose access within a minute.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) revokeHandler(c *contextmodel.ReqContext) response.Response {
+	if err := s.Revoke(c.Req.Context(), &RevokeLinkCommand{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		DashboardUID: web.Params(c.Req)[":uid"],
+		UID:          web.Params(c.Req)[":linkUid"],
+		RevokedBy:    c.SignedInUser.UserID,
+	}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to revoke share link", err)
+	}
+	return response.Success("Share link revoked")
+}
+
+// openHandler stores the token of the link in a cookie, authenticating the requests of the browser,
+// and redirects to the dashboard
+func (s *Service) openHandler(c *contextmodel.ReqContext) {
+	ctx := c.Req.Context()
+	token := web.Params(c.Req)[":token"]
+	link, err := s.Open(ctx, token)
+	if err != nil {
+		c.Handle(s.cfg, http.StatusUnauthorized, "The share link is expired or revoked", err)
+		return
+	}
+	dash, err := s.getDashboard(ctx, link.OrgID, link.DashboardUID)
+	if err != nil {
+		c.Handle(s.cfg, http.StatusNotFound, "Dashboard not found", err)
+		return
+	}
+
+	cookies.WriteCookie(c.Resp, CookieName, token, int(link.Expires.Sub(s.now()).Seconds()), nil)
+	query := url.Values{"orgId": {strconv.FormatInt(link.OrgID, 10)}}
+	if link.From != "" {
+		query.Set("from", link.From)
+	}
+	if link.To != "" {
+		query.Set("to", link.To)
+	}
+	c.Redirect(dashboards.GetDashboardURL(dash.UID, dash.Slug) + "?" + query.Encode())
+}
+
+// swagger:parameters createShareLink
+type CreateShareLinkParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	Body CreateShareLinkCommand `json:"body"`
+}
+
+// swagger:parameters getShareLinks
+type GetShareLinksParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters revokeShareLink
+type RevokeShareLinkParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:path
+	// required:true
+	LinkUID string `json:"link_uid"`
+}
+
+// swagger:response createShareLinkResponse
+type CreateShareLinkResponse struct {
+	// in:body
+	Body ShareLinkDTO `json:"body"`
+}
+
+// swagger:response getShareLinksResponse
+type GetShareLinksResponse struct {
+	// in:body
+	Body []*Link `json:"body"`
+}
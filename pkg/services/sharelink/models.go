@@ -0,0 +1,61 @@
+package sharelink
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrLinkNotFound      = errutil.NotFound("sharelink.not-found", errutil.WithPublicMessage("Share link not found"))
+	ErrLinkExpired       = errutil.Unauthorized("sharelink.expired", errutil.WithPublicMessage("The share link is expired or revoked"))
+	ErrDashboardNotFound = errutil.NotFound("sharelink.dashboard-not-found", errutil.WithPublicMessage("Dashboard not found"))
+	ErrInvalidDuration   = errutil.BadRequest("sharelink.invalid-duration", errutil.WithPublicMessage("Invalid share link duration"))
+)
+
+// Link is a signed, expiring link to view a dashboard without an account. The viewers of the link
+// are authenticated with an identity that can only read the dashboard and query its data sources.
+type Link struct {
+	ID           int64  `json:"id" xorm:"pk autoincr 'id'"`
+	UID          string `json:"uid" xorm:"uid"`
+	OrgID        int64  `json:"orgId" xorm:"org_id"`
+	DashboardUID string `json:"dashboardUid" xorm:"dashboard_uid"`
+	// From and To pin the time range the dashboard is opened with, if set
+	From           string     `json:"from,omitempty" xorm:"time_from"`
+	To             string     `json:"to,omitempty" xorm:"time_to"`
+	CreatedBy      int64      `json:"createdBy"`
+	CreatedByLogin string     `json:"createdByLogin"`
+	Created        time.Time  `json:"created"`
+	Expires        time.Time  `json:"expires"`
+	Revoked        *time.Time `json:"revoked,omitempty"`
+	RevokedBy      int64      `json:"revokedBy,omitempty"`
+	// LastOpened and OpenCount record the use of the link
+	LastOpened *time.Time `json:"lastOpened,omitempty"`
+	OpenCount  int64      `json:"openCount"`
+}
+
+func (Link) TableName() string {
+	return "share_link"
+}
+
+// IsActive returns whether the link can be used at the time
+func (l *Link) IsActive(now time.Time) bool {
+	return l.Revoked == nil && now.Before(l.Expires)
+}
+
+type CreateLinkCommand struct {
+	OrgID          int64
+	DashboardUID   string
+	From           string
+	To             string
+	Duration       time.Duration
+	CreatedBy      int64
+	CreatedByLogin string
+}
+
+type RevokeLinkCommand struct {
+	OrgID        int64
+	DashboardUID string
+	UID          string
+	RevokedBy    int64
+}
@@ -0,0 +1,291 @@
+package sharelink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// CookieName is the cookie holding the token of the share link opened in the browser
+	CookieName = "grafana_share_link"
+
+	// ClientName is the authn client authenticating the viewers of share links
+	ClientName = "auth.client.share-link"
+	// AuthModule is what the viewers of share links are authenticated by
+	AuthModule = "share_link"
+
+	// clientPriority runs the client after the sessions, so that signed in users stay themselves,
+	// and before anonymous access
+	clientPriority = 70
+
+	// linkCheckInterval is how often the link of a token is checked to still be active, so a link can
+	// be used at most this long after it was revoked
+	linkCheckInterval = time.Minute
+)
+
+var _ authn.Client = (*Service)(nil)
+
+// Service manages the share links of the dashboards and authenticates their viewers.
+type Service struct {
+	cfg              *setting.Cfg
+	store            db.DB
+	accessControl    accesscontrol.AccessControl
+	dashboardService dashboards.DashboardService
+	cache            *localcache.CacheService
+	log              log.Logger
+	now              func() time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, authnService authn.Service, accessControl accesscontrol.AccessControl,
+	dashboardService dashboards.DashboardService, routeRegister routing.RouteRegister) *Service {
+	s := &Service{
+		cfg:              cfg,
+		store:            sqlStore,
+		accessControl:    accessControl,
+		dashboardService: dashboardService,
+		cache:            localcache.New(linkCheckInterval, 2*linkCheckInterval),
+		log:              log.New("share-link"),
+		now:              time.Now,
+	}
+
+	if !cfg.ShareLinksEnabled {
+		return s
+	}
+
+	authnService.RegisterClient(s)
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+// Create stores a link to the dashboard and returns it with its token
+func (s *Service) Create(ctx context.Context, cmd *CreateLinkCommand) (*Link, string, error) {
+	if cmd.Duration <= 0 || cmd.Duration > s.cfg.ShareLinkMaxDuration {
+		return nil, "", ErrInvalidDuration.Errorf("the duration must be positive and at most %s", s.cfg.ShareLinkMaxDuration)
+	}
+	if _, err := s.getDashboard(ctx, cmd.OrgID, cmd.DashboardUID); err != nil {
+		return nil, "", err
+	}
+
+	now := s.now()
+	link := &Link{
+		UID:            util.GenerateShortUID(),
+		OrgID:          cmd.OrgID,
+		DashboardUID:   cmd.DashboardUID,
+		From:           cmd.From,
+		To:             cmd.To,
+		CreatedBy:      cmd.CreatedBy,
+		CreatedByLogin: cmd.CreatedByLogin,
+		Created:        now,
+		Expires:        now.Add(cmd.Duration).Truncate(time.Second),
+	}
+	token, err := s.token(link)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(link)
+		return err
+	}); err != nil {
+		return nil, "", err
+	}
+
+	s.log.FromContext(ctx).Info("Created share link", "uid", link.UID, "dashboardUid", link.DashboardUID, "createdBy", link.CreatedBy, "expires", link.Expires)
+	return link, token, nil
+}
+
+// List returns the links of the dashboard, most recent first
+func (s *Service) List(ctx context.Context, orgID int64, dashboardUID string) ([]*Link, error) {
+	result := make([]*Link, 0)
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND dashboard_uid = ?", orgID, dashboardUID).Desc("created", "id").Find(&result)
+	})
+	return result, err
+}
+
+// Revoke ends the use of the link, the viewers who opened it lose access within linkCheckInterval
+func (s *Service) Revoke(ctx context.Context, cmd *RevokeLinkCommand) error {
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var link Link
+		found, err := sess.Where("org_id = ? AND dashboard_uid = ? AND uid = ?", cmd.OrgID, cmd.DashboardUID, cmd.UID).Get(&link)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrLinkNotFound.Errorf("share link %s not found", cmd.UID)
+		}
+		if link.Revoked != nil {
+			return nil
+		}
+		revoked := s.now()
+		_, err = sess.ID(link.ID).Cols("revoked", "revoked_by").Update(&Link{Revoked: &revoked, RevokedBy: cmd.RevokedBy})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cache.Delete(linkCacheKey(cmd.UID))
+	s.log.FromContext(ctx).Info("Revoked share link", "uid", cmd.UID, "dashboardUid", cmd.DashboardUID, "revokedBy", cmd.RevokedBy)
+	return nil
+}
+
+// Open returns the active link of the token and records that it was opened
+func (s *Service) Open(ctx context.Context, token string) (*Link, error) {
+	link, err := s.getActiveLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	opened := s.now()
+	if err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE share_link SET open_count = open_count + 1, last_opened = ? WHERE id = ?", opened, link.ID)
+		return err
+	}); err != nil {
+		// the link opens when its use can't be recorded, the requests of its viewers are logged
+		s.log.FromContext(ctx).Warn("Failed to record the opening of the share link", "uid", link.UID, "error", err)
+	}
+
+	s.log.FromContext(ctx).Info("Opened share link", "uid", link.UID, "dashboardUid", link.DashboardUID)
+	return link, nil
+}
+
+func (s *Service) Name() string {
+	return ClientName
+}
+
+func (s *Service) Priority() uint {
+	return clientPriority
+}
+
+// Test returns whether the request has the cookie of an active link. The cookies of expired or revoked links
+// are cleared and left to the other clients, so that the browsers holding them aren't rejected until they expire.
+func (s *Service) Test(ctx context.Context, r *authn.Request) bool {
+	if r.HTTPRequest == nil {
+		return false
+	}
+	cookie, err := r.HTTPRequest.Cookie(CookieName)
+	if err != nil {
+		return false
+	}
+	if _, err := s.getAuthorization(ctx, cookie.Value); errors.Is(err, ErrLinkExpired) || errors.Is(err, ErrDashboardNotFound) {
+		s.log.FromContext(ctx).Debug("Ignoring share link cookie", "error", err)
+		if r.Resp != nil {
+			cookies.DeleteCookie(r.Resp, CookieName, nil)
+		}
+		return false
+	}
+	return true
+}
+
+// Authenticate returns the identity of the viewers of the link of the cookie, which can only read the
+// dashboard and query the data sources it uses
+func (s *Service) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	cookie, err := r.HTTPRequest.Cookie(CookieName)
+	if err != nil {
+		return nil, ErrLinkExpired.Errorf("no share link cookie")
+	}
+	auth, err := s.getAuthorization(ctx, cookie.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authn.Identity{
+		ID:              authn.AnonymousNamespaceID,
+		Login:           "share-link-" + auth.link.UID,
+		Name:            "Share link",
+		OrgID:           auth.link.OrgID,
+		OrgRoles:        map[int64]org.RoleType{auth.link.OrgID: org.RoleNone},
+		Permissions:     map[int64]map[string][]string{auth.link.OrgID: auth.permissions},
+		AuthenticatedBy: AuthModule,
+		LastSeenAt:      s.now(),
+	}, nil
+}
+
+// authorization is the link of a token and the permissions of its viewers
+type authorization struct {
+	link        *Link
+	permissions map[string][]string
+}
+
+// getAuthorization returns the authorization of the token, it's only checked once per linkCheckInterval
+func (s *Service) getAuthorization(ctx context.Context, token string) (*authorization, error) {
+	c, err := verifyToken(s.cfg.SecretKey, token, s.now())
+	if err != nil {
+		return nil, ErrLinkExpired.Errorf("invalid share link token: %w", err)
+	}
+	if cached, ok := s.cache.Get(linkCacheKey(c.UID)); ok {
+		return cached.(*authorization), nil
+	}
+
+	link, err := s.getActiveLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	dash, err := s.getDashboard(ctx, link.OrgID, link.DashboardUID)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboardScope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dash.UID)
+	datasourceScopes := []string{}
+	for _, uid := range dash.GetDatasourceUIDs() {
+		datasourceScopes = append(datasourceScopes, datasources.ScopeProvider.GetResourceScopeUID(uid))
+	}
+	auth := &authorization{link: link, permissions: map[string][]string{
+		dashboards.ActionDashboardsRead: {dashboardScope},
+		datasources.ActionQuery:         datasourceScopes,
+	}}
+	s.cache.Set(linkCacheKey(link.UID), auth, linkCheckInterval)
+	return auth, nil
+}
+
+// getActiveLink returns the link of the token if it's neither expired nor revoked
+func (s *Service) getActiveLink(ctx context.Context, token string) (*Link, error) {
+	c, err := verifyToken(s.cfg.SecretKey, token, s.now())
+	if err != nil {
+		return nil, ErrLinkExpired.Errorf("invalid share link token: %w", err)
+	}
+
+	var link Link
+	var found bool
+	if err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		found, err = sess.Where("uid = ? AND org_id = ?", c.UID, c.OrgID).Get(&link)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if !found || !link.IsActive(s.now()) {
+		return nil, ErrLinkExpired.Errorf("share link %s is expired or revoked", c.UID)
+	}
+	return &link, nil
+}
+
+func (s *Service) getDashboard(ctx context.Context, orgID int64, uid string) (*dashboards.Dashboard, error) {
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: orgID, UID: uid})
+	if errors.Is(err, dashboards.ErrDashboardNotFound) || (err == nil && dash.IsFolder) {
+		return nil, ErrDashboardNotFound.Errorf("dashboard %s not found", uid)
+	}
+	return dash, err
+}
+
+func (s *Service) token(link *Link) (string, error) {
+	return signToken(s.cfg.SecretKey, claims{UID: link.UID, OrgID: link.OrgID, Expires: link.Expires.Unix()})
+}
+
+func linkCacheKey(uid string) string {
+	return fmt.Sprintf("share-link-%s", uid)
+}
@@ -0,0 +1,141 @@
+package sharelink
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestToken(t *testing.T) {
+	now := time.Now()
+	token, err := signToken("secret", claims{UID: "abc", OrgID: 1, Expires: now.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+
+	c, err := verifyToken("secret", token, now)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", c.UID)
+
+	_, err = verifyToken("other", token, now)
+	assert.ErrorIs(t, err, errInvalidToken)
+	_, err = verifyToken("secret", token, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, errInvalidToken)
+	_, err = verifyToken("secret", strings.Replace(token, ".", "x.", 1), now)
+	assert.ErrorIs(t, err, errInvalidToken)
+}
+
+func TestIntegrationShareLinks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	dashSvc := dashboards.NewFakeDashboardService(t)
+	dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{
+		OrgID: 1, UID: "abc", Slug: "prod", Data: simplejson.NewFromAny(map[string]any{
+			"panels": []any{map[string]any{"id": 1, "datasource": map[string]any{"uid": "prom"}}},
+		}),
+	}, nil).Maybe()
+
+	cfg := setting.NewCfg()
+	cfg.SecretKey = "secret"
+	cfg.ShareLinksEnabled = true
+	cfg.ShareLinkMaxDuration = 24 * time.Hour
+	s := ProvideService(cfg, db.InitTestDB(t), &authntest.FakeService{}, actest.FakeAccessControl{}, dashSvc, routing.NewRouteRegister())
+	start := time.Now().Truncate(time.Second)
+	now := start
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	newRequest := func(token string) (*authn.Request, *httptest.ResponseRecorder) {
+		req, err := http.NewRequest(http.MethodGet, "/api/dashboards/uid/abc", nil)
+		require.NoError(t, err)
+		req.AddCookie(&http.Cookie{Name: CookieName, Value: token})
+		rec := httptest.NewRecorder()
+		return &authn.Request{HTTPRequest: req, Resp: web.NewResponseWriter(http.MethodGet, rec)}, rec
+	}
+	authenticate := func(token string) (*authn.Identity, error) {
+		r, _ := newRequest(token)
+		require.True(t, s.Test(ctx, r))
+		return s.Authenticate(ctx, r)
+	}
+	// assertIgnored checks that the cookie of the token is cleared and left to the other clients
+	assertIgnored := func(t *testing.T, token string) {
+		t.Helper()
+		r, rec := newRequest(token)
+		assert.False(t, s.Test(ctx, r))
+		assert.Contains(t, rec.Header().Get("Set-Cookie"), CookieName+"=;")
+	}
+
+	t.Run("should reject a duration over the maximum", func(t *testing.T) {
+		_, _, err := s.Create(ctx, &CreateLinkCommand{OrgID: 1, DashboardUID: "abc", Duration: 48 * time.Hour})
+		assert.ErrorIs(t, err, ErrInvalidDuration)
+	})
+
+	link, token, err := s.Create(ctx, &CreateLinkCommand{OrgID: 1, DashboardUID: "abc", From: "now-6h", To: "now", Duration: time.Hour, CreatedBy: 2})
+	require.NoError(t, err)
+
+	t.Run("should only allow the viewers to read the dashboard and query its data sources", func(t *testing.T) {
+		identity, err := authenticate(token)
+		require.NoError(t, err)
+		assert.Equal(t, authn.AnonymousNamespaceID, identity.ID)
+		assert.Equal(t, org.RoleNone, identity.GetOrgRole())
+		assert.Equal(t, map[string][]string{
+			dashboards.ActionDashboardsRead: {"dashboards:uid:abc"},
+			datasources.ActionQuery:         {"datasources:uid:prom"},
+		}, identity.GetPermissions())
+	})
+
+	t.Run("should record the openings of the link", func(t *testing.T) {
+		_, err := s.Open(ctx, token)
+		require.NoError(t, err)
+		links, err := s.List(ctx, 1, "abc")
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		assert.Equal(t, int64(1), links[0].OpenCount)
+		assert.Equal(t, "now-6h", links[0].From)
+	})
+
+	t.Run("should reject the link once revoked", func(t *testing.T) {
+		require.NoError(t, s.Revoke(ctx, &RevokeLinkCommand{OrgID: 1, DashboardUID: "abc", UID: link.UID, RevokedBy: 2}))
+		assertIgnored(t, token)
+		_, err := s.Open(ctx, token)
+		assert.ErrorIs(t, err, ErrLinkExpired)
+
+		err = s.Revoke(ctx, &RevokeLinkCommand{OrgID: 1, DashboardUID: "other", UID: link.UID})
+		assert.ErrorIs(t, err, ErrLinkNotFound)
+	})
+
+	t.Run("should reject the link once expired", func(t *testing.T) {
+		_, token, err := s.Create(ctx, &CreateLinkCommand{OrgID: 1, DashboardUID: "abc", Duration: time.Hour})
+		require.NoError(t, err)
+		_, err = authenticate(token)
+		require.NoError(t, err)
+
+		now = start.Add(2 * time.Hour)
+		assertIgnored(t, token)
+		assertIgnored(t, "invalid")
+	})
+}
@@ -0,0 +1,36 @@
+package sharelink
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/signedtoken"
+)
+
+var errInvalidToken = errors.New("invalid share link token")
+
+// claims are signed into the token of the link and its cookie, the link itself is checked on each
+// request so that revoking it ends its use
+type claims struct {
+	UID     string `json:"uid"`
+	OrgID   int64  `json:"org"`
+	Expires int64  `json:"exp"`
+}
+
+// tokenPurpose scopes the signing key of the share link tokens
+const tokenPurpose = "share-link"
+
+func signToken(secretKey string, c claims) (string, error) {
+	return signedtoken.Sign(secretKey, tokenPurpose, c)
+}
+
+func verifyToken(secretKey string, token string, now time.Time) (*claims, error) {
+	var c claims
+	if err := signedtoken.Verify(secretKey, tokenPurpose, token, &c); err != nil {
+		return nil, errInvalidToken
+	}
+	if now.Unix() >= c.Expires {
+		return nil, errInvalidToken
+	}
+	return &c, nil
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addAccessRequestMigrations(mg *Migrator) {
+	accessRequestV1 := Table{
+		Name: "access_request",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "user_login", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "reason", Type: DB_Text, Nullable: false},
+			{Name: "status", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "permission", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "reviewer_id", Type: DB_BigInt, Nullable: false},
+			{Name: "reviewer_login", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "reviewed", Type: DB_DateTime, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "dashboard_uid", "status"}},
+			{Cols: []string{"org_id", "user_id"}},
+		},
+	}
+
+	mg.AddMigration("create access_request table", NewAddTableMigration(accessRequestV1))
+	mg.AddMigration("add index access_request.org_id-dashboard_uid-status", NewAddIndexMigration(accessRequestV1, accessRequestV1.Indices[0]))
+	mg.AddMigration("add index access_request.org_id-user_id", NewAddIndexMigration(accessRequestV1, accessRequestV1.Indices[1]))
+}
@@ -183,6 +183,33 @@ func addAnnotationMig(mg *Migrator) {
 	mg.AddMigration("Increase tags column to length 4096", NewRawSQLMigration("").
 		Postgres("ALTER TABLE annotation ALTER COLUMN tags TYPE VARCHAR(4096);").
 		Mysql("ALTER TABLE annotation MODIFY tags VARCHAR(4096);"))
+
+	//
+	// Daily rollups of old annotations
+	//
+	rollupTable := Table{
+		Name: "annotation_rollup",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: DB_BigInt, Nullable: false},
+			{Name: "panel_id", Type: DB_BigInt, Nullable: false},
+			{Name: "epoch", Type: DB_BigInt, Nullable: false},
+			{Name: "epoch_end", Type: DB_BigInt, Nullable: false},
+			{Name: "count", Type: DB_BigInt, Nullable: false},
+			{Name: "tags", Type: DB_Text, Nullable: true},
+			{Name: "created", Type: DB_BigInt, Nullable: false},
+			{Name: "updated", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "alert_id", "dashboard_id", "panel_id", "epoch"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "epoch_end", "epoch"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create annotation_rollup table", NewAddTableMigration(rollupTable))
+	addTableIndicesMigrations(mg, "v1", rollupTable)
 }
 
 type AddMakeRegionSingleRowMigration struct {
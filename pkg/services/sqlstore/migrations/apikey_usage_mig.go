@@ -0,0 +1,22 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addAPIKeyUsageMigrations(mg *Migrator) {
+	apiKeyUsageV1 := Table{
+		Name: "api_key_usage",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "api_key_id", Type: DB_BigInt, Nullable: false},
+			{Name: "endpoint", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "call_count", Type: DB_BigInt, Nullable: false},
+			{Name: "last_used", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"api_key_id", "endpoint"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create api_key_usage table", NewAddTableMigration(apiKeyUsageV1))
+	mg.AddMigration("add unique index api_key_usage.api_key_id-endpoint", NewAddIndexMigration(apiKeyUsageV1, apiKeyUsageV1.Indices[0]))
+}
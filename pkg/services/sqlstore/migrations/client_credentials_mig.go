@@ -0,0 +1,31 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addClientCredentialsMigrations(mg *Migrator) {
+	clientCredentialsV1 := Table{
+		Name: "oauth_client_credentials",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "service_account_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "client_id", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "secret_hash", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "last_used", Type: DB_DateTime, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"client_id"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "service_account_id"}},
+		},
+	}
+
+	mg.AddMigration("create oauth_client_credentials table", NewAddTableMigration(clientCredentialsV1))
+	mg.AddMigration("add unique index oauth_client_credentials.client_id", NewAddIndexMigration(clientCredentialsV1, clientCredentialsV1.Indices[0]))
+	mg.AddMigration("add index oauth_client_credentials.org_id-service_account_id", NewAddIndexMigration(clientCredentialsV1, clientCredentialsV1.Indices[1]))
+
+	mg.AddMigration("add jwt_subject to oauth_client_credentials", NewAddColumnMigration(clientCredentialsV1, &Column{
+		Name: "jwt_subject", Type: DB_NVarchar, Length: 190, Nullable: true,
+	}))
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDashboardACLAuditMigrations(mg *Migrator) {
+	dashboardACLAuditV1 := Table{
+		Name: "dashboard_acl_audit",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "is_folder", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "actor_id", Type: DB_BigInt, Nullable: false},
+			{Name: "actor_login", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "previous_items", Type: DB_Text, Nullable: false},
+			{Name: "new_items", Type: DB_Text, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "dashboard_uid", "created"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_acl_audit table", NewAddTableMigration(dashboardACLAuditV1))
+	mg.AddMigration("add index dashboard_acl_audit.org_id-dashboard_uid-created", NewAddIndexMigration(dashboardACLAuditV1, dashboardACLAuditV1.Indices[0]))
+}
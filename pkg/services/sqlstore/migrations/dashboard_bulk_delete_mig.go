@@ -0,0 +1,54 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDashboardBulkDeleteMigrations(mg *Migrator) {
+	jobV1 := Table{
+		Name: "dashboard_bulk_delete_job",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created_by", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "status", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "total", Type: DB_Int, Nullable: false},
+			{Name: "deleted", Type: DB_Int, Nullable: false},
+			{Name: "restored", Type: DB_Int, Nullable: false},
+			{Name: "failures", Type: DB_Text, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+			{Name: "undo_expires", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"undo_expires"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_bulk_delete_job table", NewAddTableMigration(jobV1))
+	mg.AddMigration("add unique index dashboard_bulk_delete_job.org_id-uid", NewAddIndexMigration(jobV1, jobV1.Indices[0]))
+	mg.AddMigration("add index dashboard_bulk_delete_job.undo_expires", NewAddIndexMigration(jobV1, jobV1.Indices[1]))
+
+	trashV1 := Table{
+		Name: "dashboard_trash",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "job_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "folder_uid", Type: DB_NVarchar, Length: 40, Nullable: true},
+			{Name: "title", Type: DB_NVarchar, Length: 189, Nullable: false},
+			{Name: "data", Type: DB_MediumText, Nullable: false},
+			{Name: "deleted", Type: DB_DateTime, Nullable: false},
+			{Name: "expires", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "job_uid"}},
+			{Cols: []string{"expires"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_trash table", NewAddTableMigration(trashV1))
+	mg.AddMigration("add index dashboard_trash.org_id-job_uid", NewAddIndexMigration(trashV1, trashV1.Indices[0]))
+	mg.AddMigration("add index dashboard_trash.expires", NewAddIndexMigration(trashV1, trashV1.Indices[1]))
+}
@@ -234,4 +234,9 @@ func addDashboardMigration(mg *Migrator) {
 	mg.AddMigration("Add isPublic for dashboard", NewAddColumnMigration(dashboardV2, &Column{
 		Name: "is_public", Type: DB_Bool, Nullable: false, Default: "0",
 	}))
+
+	// term index for the autocompletion of the tags
+	mg.AddMigration("Add index for term in dashboard_tag", NewAddIndexMigration(dashboardTagV1, &Index{
+		Cols: []string{"term"}, Type: IndexType,
+	}))
 }
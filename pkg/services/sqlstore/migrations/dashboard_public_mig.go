@@ -96,4 +96,25 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add query_cache_interval column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "query_cache_interval",
+		Type:     DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}))
+
+	mg.AddMigration("add query_rate_limit column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "query_rate_limit",
+		Type:     DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}))
+
+	mg.AddMigration("add max_concurrent_viewers column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "max_concurrent_viewers",
+		Type:     DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}))
 }
@@ -0,0 +1,31 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addImpersonationMigrations(mg *Migrator) {
+	impersonationEventV1 := Table{
+		Name: "impersonation_event",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "impersonation_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "impersonator_id", Type: DB_BigInt, Nullable: false},
+			{Name: "impersonator_login", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "user_login", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "method", Type: DB_NVarchar, Length: 10, Nullable: false},
+			{Name: "path", Type: DB_NVarchar, Length: 2048, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"impersonation_uid"}},
+			{Cols: []string{"impersonator_id", "created"}},
+			{Cols: []string{"user_id", "created"}},
+		},
+	}
+
+	mg.AddMigration("create impersonation_event table", NewAddTableMigration(impersonationEventV1))
+	mg.AddMigration("add index impersonation_event.impersonation_uid", NewAddIndexMigration(impersonationEventV1, impersonationEventV1.Indices[0]))
+	mg.AddMigration("add index impersonation_event.impersonator_id-created", NewAddIndexMigration(impersonationEventV1, impersonationEventV1.Indices[1]))
+	mg.AddMigration("add index impersonation_event.user_id-created", NewAddIndexMigration(impersonationEventV1, impersonationEventV1.Indices[2]))
+}
@@ -117,6 +117,29 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 	ualert.AddRuleNotificationSettingsColumns(mg)
 
 	accesscontrol.AddAlertingScopeRemovalMigration(mg)
+
+	dashboardFolderMigrations.AddDashboardPanelTypeMigrations(mg)
+
+	addDashboardACLAuditMigrations(mg)
+
+	addUserNotificationMigrations(mg)
+
+	addImpersonationMigrations(mg)
+
+	dashboardFolderMigrations.AddDashboardLabelMigrations(mg)
+
+	dashboardFolderMigrations.AddDashboardDatasourceMigrations(mg)
+
+	addAccessRequestMigrations(mg)
+
+	addShareLinkMigrations(mg)
+	addAPIKeyUsageMigrations(mg)
+	addClientCredentialsMigrations(mg)
+
+	dashboardFolderMigrations.AddDashboardChangeMigrations(mg)
+	addDashboardBulkDeleteMigrations(mg)
+
+	dashboardFolderMigrations.AddFolderReadOnlyMigrations(mg)
 }
 
 func addStarMigrations(mg *Migrator) {
@@ -134,4 +157,29 @@ func addStarMigrations(mg *Migrator) {
 
 	mg.AddMigration("create star table", NewAddTableMigration(starV1))
 	mg.AddMigration("add unique index star.user_id_dashboard_id", NewAddIndexMigration(starV1, starV1.Indices[0]))
+
+	mg.AddMigration("add position column to star", NewAddColumnMigration(starV1, &Column{
+		Name: "position", Type: DB_Int, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add collection_id column to star", NewAddColumnMigration(starV1, &Column{
+		Name: "collection_id", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	starCollectionV1 := Table{
+		Name: "star_collection",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "position", Type: DB_Int, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "user_id", "name"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create star_collection table", NewAddTableMigration(starCollectionV1))
+	mg.AddMigration("add unique index star_collection.org_id_user_id_name", NewAddIndexMigration(starCollectionV1, starCollectionV1.Indices[0]))
 }
@@ -0,0 +1,33 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addShareLinkMigrations(mg *Migrator) {
+	shareLinkV1 := Table{
+		Name: "share_link",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "time_from", Type: DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "time_to", Type: DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_by_login", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "expires", Type: DB_DateTime, Nullable: false},
+			{Name: "revoked", Type: DB_DateTime, Nullable: true},
+			{Name: "revoked_by", Type: DB_BigInt, Nullable: false},
+			{Name: "last_opened", Type: DB_DateTime, Nullable: true},
+			{Name: "open_count", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "dashboard_uid"}},
+		},
+	}
+
+	mg.AddMigration("create share_link table", NewAddTableMigration(shareLinkV1))
+	mg.AddMigration("add unique index share_link.uid", NewAddIndexMigration(shareLinkV1, shareLinkV1.Indices[0]))
+	mg.AddMigration("add index share_link.org_id-dashboard_uid", NewAddIndexMigration(shareLinkV1, shareLinkV1.Indices[1]))
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addUserNotificationMigrations(mg *Migrator) {
+	userNotificationV1 := Table{
+		Name: "user_notification",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "text", Type: DB_Text, Nullable: false},
+			{Name: "url", Type: DB_NVarchar, Length: 2048, Nullable: false},
+			{Name: "is_read", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "user_id", "created"}},
+			{Cols: []string{"created"}},
+		},
+	}
+
+	mg.AddMigration("create user_notification table", NewAddTableMigration(userNotificationV1))
+	mg.AddMigration("add index user_notification.org_id-user_id-created", NewAddIndexMigration(userNotificationV1, userNotificationV1.Indices[0]))
+	mg.AddMigration("add index user_notification.created", NewAddIndexMigration(userNotificationV1, userNotificationV1.Indices[1]))
+}
@@ -13,12 +13,17 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/search/model"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 )
 
 // maximum possible capacity for recursive queries array: one query for folder and one for dashboard actions
 const maximumRecursiveQueries = 2
 
+// maxInlinedUIDs is the number of UIDs of the self-contained permissions above which the filters allowed to
+// use temporary tables join the UIDs from a temporary table instead of passing them as query parameters
+const maxInlinedUIDs = 500
+
 type clause struct {
 	string
 	params []any
@@ -34,6 +39,10 @@ type accessControlDashboardPermissionFilter struct {
 	// any recursive CTE queries (if supported)
 	recQueries                   []clause
 	recursiveQueriesAreSupported bool
+
+	// the UIDs joined from temporary tables (if allowed)
+	tempTables      []model.TempTable
+	allowTempTables bool
 }
 
 type PermissionsFilter interface {
@@ -49,6 +58,30 @@ type PermissionsFilter interface {
 // The filter is configured to use the new permissions filter (without subqueries) if the feature flag is enabled
 // The filter is configured to use the old permissions filter (with subqueries) if the feature flag is disabled
 func NewAccessControlDashboardPermissionFilter(user identity.Requester, permissionLevel dashboardaccess.PermissionType, queryType string, features featuremgmt.FeatureToggles, recursiveQueriesAreSupported bool) PermissionsFilter {
+	folderActions, dashboardActions := filterActions(permissionLevel, queryType)
+	return newFilter(user, folderActions, dashboardActions, features, recursiveQueriesAreSupported, false)
+}
+
+func newFilter(user identity.Requester, folderActions, dashboardActions []string, features featuremgmt.FeatureToggles, recursiveQueriesAreSupported, allowTempTables bool) PermissionsFilter {
+	var f PermissionsFilter
+	if features.IsEnabledGlobally(featuremgmt.FlagPermissionsFilterRemoveSubquery) {
+		f = &accessControlDashboardPermissionFilterNoFolderSubquery{
+			accessControlDashboardPermissionFilter: accessControlDashboardPermissionFilter{
+				user: user, folderActions: folderActions, dashboardActions: dashboardActions, features: features,
+				recursiveQueriesAreSupported: recursiveQueriesAreSupported, allowTempTables: allowTempTables,
+			},
+		}
+	} else {
+		f = &accessControlDashboardPermissionFilter{user: user, folderActions: folderActions, dashboardActions: dashboardActions, features: features,
+			recursiveQueriesAreSupported: recursiveQueriesAreSupported, allowTempTables: allowTempTables,
+		}
+	}
+	f.buildClauses()
+	return f
+}
+
+// filterActions returns the folder and dashboard actions required by the permission level for the query type
+func filterActions(permissionLevel dashboardaccess.PermissionType, queryType string) ([]string, []string) {
 	needEdit := permissionLevel > dashboardaccess.PERMISSION_VIEW
 
 	var folderActions []string
@@ -85,22 +118,7 @@ func NewAccessControlDashboardPermissionFilter(user identity.Requester, permissi
 			dashboardActions = append(dashboardActions, dashboards.ActionDashboardsWrite)
 		}
 	}
-
-	var f PermissionsFilter
-	if features.IsEnabledGlobally(featuremgmt.FlagPermissionsFilterRemoveSubquery) {
-		f = &accessControlDashboardPermissionFilterNoFolderSubquery{
-			accessControlDashboardPermissionFilter: accessControlDashboardPermissionFilter{
-				user: user, folderActions: folderActions, dashboardActions: dashboardActions, features: features,
-				recursiveQueriesAreSupported: recursiveQueriesAreSupported,
-			},
-		}
-	} else {
-		f = &accessControlDashboardPermissionFilter{user: user, folderActions: folderActions, dashboardActions: dashboardActions, features: features,
-			recursiveQueriesAreSupported: recursiveQueriesAreSupported,
-		}
-	}
-	f.buildClauses()
-	return f
+	return folderActions, dashboardActions
 }
 
 func (f *accessControlDashboardPermissionFilter) LeftJoin() string {
@@ -138,6 +156,10 @@ func (f *accessControlDashboardPermissionFilter) buildClauses() {
 
 	permSelector := strings.Builder{}
 	var permSelectorArgs []any
+	// hasPermSelector is false when none of the folders of the self-contained permissions are allowed
+	hasPermSelector := true
+	// the nested folders are matched by repeating the selector of the folders for each level
+	nestedWithoutRecursion := f.features.IsEnabledGlobally(featuremgmt.FlagNestedFolders) && !f.recursiveQueriesAreSupported
 
 	// useSelfContainedPermissions is true if the user's permissions are stored and set from the JWT token
 	// currently it's used for the extended JWT module (when the user is authenticated via a JWT token generated by Grafana)
@@ -164,11 +186,13 @@ func (f *accessControlDashboardPermissionFilter) buildClauses() {
 			} else {
 				actions := parseStringSliceFromInterfaceSlice(toCheck)
 
-				args = getAllowedUIDs(actions, f.user, dashboards.ScopeDashboardsPrefix)
+				uids := getAllowedUIDs(actions, f.user, dashboards.ScopeDashboardsPrefix)
 
 				// Only add the IN clause if we have any dashboards to check
-				if len(args) > 0 {
-					builder.WriteString("(dashboard.uid IN (?" + strings.Repeat(", ?", len(args)-1) + "")
+				if len(uids) > 0 {
+					selector, selectorArgs := f.uidsSelector(uids, true)
+					builder.WriteString("(dashboard.uid IN " + selector)
+					args = selectorArgs
 					builder.WriteString(") AND NOT dashboard.is_folder)")
 				} else {
 					builder.WriteString("(1 = 0)")
@@ -193,20 +217,17 @@ func (f *accessControlDashboardPermissionFilter) buildClauses() {
 			} else {
 				actions := parseStringSliceFromInterfaceSlice(toCheck)
 
-				permSelectorArgs = getAllowedUIDs(actions, f.user, dashboards.ScopeFoldersPrefix)
-
-				// Only add the IN clause if we have any folders to check
-				if len(permSelectorArgs) > 0 {
-					permSelector.WriteString("(?" + strings.Repeat(", ?", len(permSelectorArgs)-1) + "")
-				} else {
-					permSelector.WriteString("(")
-				}
+				uids := getAllowedUIDs(actions, f.user, dashboards.ScopeFoldersPrefix)
+				hasPermSelector = len(uids) > 0
+				selector, selectorArgs := f.uidsSelector(uids, !nestedWithoutRecursion)
+				permSelector.WriteString(selector)
+				permSelectorArgs = selectorArgs
 			}
 			permSelector.WriteRune(')')
 
 			switch f.features.IsEnabledGlobally(featuremgmt.FlagNestedFolders) {
 			case true:
-				if len(permSelectorArgs) > 0 {
+				if hasPermSelector {
 					switch f.recursiveQueriesAreSupported {
 					case true:
 						builder.WriteString("(dashboard.folder_id IN (SELECT d.id FROM dashboard as d ")
@@ -226,7 +247,7 @@ func (f *accessControlDashboardPermissionFilter) buildClauses() {
 				}
 			default:
 				builder.WriteString("(dashboard.folder_id IN (SELECT d.id FROM dashboard as d ")
-				if len(permSelectorArgs) > 0 {
+				if hasPermSelector {
 					builder.WriteString("WHERE d.org_id = ? AND d.uid IN ")
 					args = append(args, orgID)
 					builder.WriteString(permSelector.String())
@@ -249,6 +270,7 @@ func (f *accessControlDashboardPermissionFilter) buildClauses() {
 	// recycle and reuse
 	permSelector.Reset()
 	permSelectorArgs = permSelectorArgs[:0]
+	hasPermSelector = true
 
 	if len(f.folderActions) > 0 {
 		if len(f.dashboardActions) > 0 {
@@ -272,20 +294,18 @@ func (f *accessControlDashboardPermissionFilter) buildClauses() {
 			} else {
 				actions := parseStringSliceFromInterfaceSlice(toCheck)
 
-				permSelectorArgs = getAllowedUIDs(actions, f.user, dashboards.ScopeFoldersPrefix)
-
-				if len(permSelectorArgs) > 0 {
-					permSelector.WriteString("(?" + strings.Repeat(", ?", len(permSelectorArgs)-1) + "")
-				} else {
-					permSelector.WriteString("(")
-				}
+				uids := getAllowedUIDs(actions, f.user, dashboards.ScopeFoldersPrefix)
+				hasPermSelector = len(uids) > 0
+				selector, selectorArgs := f.uidsSelector(uids, !nestedWithoutRecursion)
+				permSelector.WriteString(selector)
+				permSelectorArgs = selectorArgs
 			}
 
 			permSelector.WriteRune(')')
 
 			switch f.features.IsEnabledGlobally(featuremgmt.FlagNestedFolders) {
 			case true:
-				if len(permSelectorArgs) > 0 {
+				if hasPermSelector {
 					switch f.recursiveQueriesAreSupported {
 					case true:
 						recQueryName := fmt.Sprintf("RecQry%d", len(f.recQueries))
@@ -303,7 +323,7 @@ func (f *accessControlDashboardPermissionFilter) buildClauses() {
 					builder.WriteString("(1 = 0")
 				}
 			default:
-				if len(permSelectorArgs) > 0 {
+				if hasPermSelector {
 					builder.WriteString("(dashboard.uid IN ")
 					builder.WriteString(permSelector.String())
 					args = append(args, permSelectorArgs...)
@@ -322,6 +342,26 @@ func (f *accessControlDashboardPermissionFilter) buildClauses() {
 	f.where = clause{string: builder.String(), params: args}
 }
 
+// TempTables returns the temporary tables of the UIDs too many to be inlined in the query
+func (f *accessControlDashboardPermissionFilter) TempTables() []model.TempTable {
+	return f.tempTables
+}
+
+// uidsSelector returns the opening of the list of the UIDs and its parameters, the list is closed by the caller.
+// The UIDs are joined from a temporary table when there are too many of them, if the list is only used once in
+// the query as a temporary table can't be referenced several times by MySQL.
+func (f *accessControlDashboardPermissionFilter) uidsSelector(uids []any, usedOnce bool) (string, []any) {
+	if f.allowTempTables && usedOnce && len(uids) > maxInlinedUIDs {
+		name := fmt.Sprintf("search_filter_uids_%d", len(f.tempTables))
+		f.tempTables = append(f.tempTables, model.TempTable{Name: name, Values: uids})
+		return "(SELECT uid FROM " + name, nil
+	}
+	if len(uids) == 0 {
+		return "(", nil
+	}
+	return "(?" + strings.Repeat(", ?", len(uids)-1), uids
+}
+
 // With returns:
 // - a with clause for fetching folders with inherited permissions if nested folders are enabled or an empty string
 func (f *accessControlDashboardPermissionFilter) With() (string, []any) {
@@ -49,6 +49,10 @@ func (f *accessControlDashboardPermissionFilterNoFolderSubquery) buildClauses()
 
 	permSelector := strings.Builder{}
 	var permSelectorArgs []any
+	// hasPermSelector is false when none of the folders of the self-contained permissions are allowed
+	hasPermSelector := true
+	// the nested folders are matched by repeating the selector of the folders for each level
+	nestedWithoutRecursion := f.features.IsEnabledGlobally(featuremgmt.FlagNestedFolders) && !f.recursiveQueriesAreSupported
 
 	// useSelfContainedPermissions is true if the user's permissions are stored and set from the JWT token
 	// currently it's used for the extended JWT module (when the user is authenticated via a JWT token generated by Grafana)
@@ -75,11 +79,13 @@ func (f *accessControlDashboardPermissionFilterNoFolderSubquery) buildClauses()
 			} else {
 				actions := parseStringSliceFromInterfaceSlice(toCheck)
 
-				args = getAllowedUIDs(actions, f.user, dashboards.ScopeDashboardsPrefix)
+				uids := getAllowedUIDs(actions, f.user, dashboards.ScopeDashboardsPrefix)
 
 				// Only add the IN clause if we have any dashboards to check
-				if len(args) > 0 {
-					builder.WriteString("(dashboard.uid IN (?" + strings.Repeat(", ?", len(args)-1) + "")
+				if len(uids) > 0 {
+					selector, selectorArgs := f.uidsSelector(uids, true)
+					builder.WriteString("(dashboard.uid IN " + selector)
+					args = selectorArgs
 					builder.WriteString(") AND NOT dashboard.is_folder)")
 				} else {
 					builder.WriteString("(1 = 0)")
@@ -104,21 +110,18 @@ func (f *accessControlDashboardPermissionFilterNoFolderSubquery) buildClauses()
 			} else {
 				actions := parseStringSliceFromInterfaceSlice(toCheck)
 
-				permSelectorArgs = getAllowedUIDs(actions, f.user, dashboards.ScopeFoldersPrefix)
-
-				// Only add the IN clause if we have any folders to check
-				if len(permSelectorArgs) > 0 {
-					permSelector.WriteString("(?" + strings.Repeat(", ?", len(permSelectorArgs)-1) + "")
-				} else {
-					permSelector.WriteString("(")
-				}
+				uids := getAllowedUIDs(actions, f.user, dashboards.ScopeFoldersPrefix)
+				hasPermSelector = len(uids) > 0
+				selector, selectorArgs := f.uidsSelector(uids, !nestedWithoutRecursion)
+				permSelector.WriteString(selector)
+				permSelectorArgs = selectorArgs
 			}
 
 			permSelector.WriteRune(')')
 
 			switch f.features.IsEnabledGlobally(featuremgmt.FlagNestedFolders) {
 			case true:
-				if len(permSelectorArgs) > 0 {
+				if hasPermSelector {
 					switch f.recursiveQueriesAreSupported {
 					case true:
 						recQueryName := fmt.Sprintf("RecQry%d", len(f.recQueries))
@@ -137,7 +140,7 @@ func (f *accessControlDashboardPermissionFilterNoFolderSubquery) buildClauses()
 				}
 			default:
 				builder.WriteString("(")
-				if len(permSelectorArgs) > 0 {
+				if hasPermSelector {
 					builder.WriteString("folder.uid IN ")
 					builder.WriteString(permSelector.String())
 					args = append(args, permSelectorArgs...)
@@ -160,6 +163,7 @@ func (f *accessControlDashboardPermissionFilterNoFolderSubquery) buildClauses()
 	// recycle and reuse
 	permSelector.Reset()
 	permSelectorArgs = permSelectorArgs[:0]
+	hasPermSelector = true
 
 	if len(f.folderActions) > 0 {
 		if len(f.dashboardActions) > 0 {
@@ -183,19 +187,17 @@ func (f *accessControlDashboardPermissionFilterNoFolderSubquery) buildClauses()
 			} else {
 				actions := parseStringSliceFromInterfaceSlice(toCheck)
 
-				permSelectorArgs = getAllowedUIDs(actions, f.user, dashboards.ScopeFoldersPrefix)
-
-				if len(permSelectorArgs) > 0 {
-					permSelector.WriteString("(?" + strings.Repeat(", ?", len(permSelectorArgs)-1) + "")
-				} else {
-					permSelector.WriteString("(")
-				}
+				uids := getAllowedUIDs(actions, f.user, dashboards.ScopeFoldersPrefix)
+				hasPermSelector = len(uids) > 0
+				selector, selectorArgs := f.uidsSelector(uids, !nestedWithoutRecursion)
+				permSelector.WriteString(selector)
+				permSelectorArgs = selectorArgs
 			}
 			permSelector.WriteRune(')')
 
 			switch f.features.IsEnabledGlobally(featuremgmt.FlagNestedFolders) {
 			case true:
-				if len(permSelectorArgs) > 0 {
+				if hasPermSelector {
 					switch f.recursiveQueriesAreSupported {
 					case true:
 						recQueryName := fmt.Sprintf("RecQry%d", len(f.recQueries))
@@ -213,7 +215,7 @@ func (f *accessControlDashboardPermissionFilterNoFolderSubquery) buildClauses()
 					builder.WriteString("(1 = 0")
 				}
 			default:
-				if len(permSelectorArgs) > 0 {
+				if hasPermSelector {
 					builder.WriteString("(dashboard.uid IN ")
 					builder.WriteString(permSelector.String())
 					args = append(args, permSelectorArgs...)
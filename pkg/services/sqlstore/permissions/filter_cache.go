@@ -0,0 +1,99 @@
+package permissions
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+const filterCacheTTL = 10 * time.Minute
+
+// FilterCache caches the permission filters compiled for the users, so that the filter of a user with many
+// grants isn't compiled again for each search.
+//
+// The filters are cached by a fingerprint of everything they are compiled from: the org, roles and teams of the
+// user, and the scopes the user has for the actions of the filter. A change of the permissions of the user
+// results in another fingerprint, which invalidates the cached filter. The grants of the roles are not part of
+// the fingerprint, they are read from the permission table by the query itself.
+//
+// The filters of the cache join the UIDs of the large sets of self-contained permissions from temporary
+// tables, see model.FilterTempTables.
+type FilterCache struct {
+	cache *localcache.CacheService
+}
+
+func NewFilterCache() *FilterCache {
+	return &FilterCache{cache: localcache.New(filterCacheTTL, 2*filterCacheTTL)}
+}
+
+// Get returns the filter of the user for the permission level and query type, compiling it if it isn't cached
+func (c *FilterCache) Get(user identity.Requester, permissionLevel dashboardaccess.PermissionType, queryType string, features featuremgmt.FeatureToggles, recursiveQueriesAreSupported bool) PermissionsFilter {
+	folderActions, dashboardActions := filterActions(permissionLevel, queryType)
+	if user == nil || user.IsNil() {
+		return newFilter(user, folderActions, dashboardActions, features, recursiveQueriesAreSupported, true)
+	}
+
+	key := filterFingerprint(user, folderActions, dashboardActions, features, recursiveQueriesAreSupported)
+	if cached, ok := c.cache.Get(key); ok {
+		return cached.(PermissionsFilter)
+	}
+
+	f := newFilter(user, folderActions, dashboardActions, features, recursiveQueriesAreSupported, true)
+	c.cache.Set(key, f, filterCacheTTL)
+	return f
+}
+
+func filterFingerprint(user identity.Requester, folderActions, dashboardActions []string, features featuremgmt.FeatureToggles, recursiveQueriesAreSupported bool) string {
+	h := sha256.New()
+	namespace, id := user.GetNamespacedID()
+	writeString(h, namespace)
+	writeString(h, id)
+	writeString(h, strconv.FormatInt(user.GetOrgID(), 10))
+	writeString(h, user.GetAuthenticatedBy())
+	roles := accesscontrol.GetOrgRoles(user)
+	writeString(h, strconv.Itoa(len(roles)))
+	for _, role := range roles {
+		writeString(h, role)
+	}
+	teams := slices.Clone(user.GetTeams())
+	slices.Sort(teams)
+	writeString(h, strconv.Itoa(len(teams)))
+	for _, team := range teams {
+		writeString(h, strconv.FormatInt(team, 10))
+	}
+	writeString(h, strconv.FormatBool(recursiveQueriesAreSupported))
+	writeString(h, strconv.FormatBool(features.IsEnabledGlobally(featuremgmt.FlagNestedFolders)))
+	writeString(h, strconv.FormatBool(features.IsEnabledGlobally(featuremgmt.FlagPermissionsFilterRemoveSubquery)))
+
+	permissions := user.GetPermissions()
+	writeString(h, strconv.FormatBool(len(permissions) == 0))
+	for _, actions := range [][]string{folderActions, dashboardActions} {
+		writeString(h, strconv.Itoa(len(actions)))
+		for _, action := range actions {
+			writeString(h, action)
+			scopes := slices.Clone(permissions[action])
+			slices.Sort(scopes)
+			writeString(h, strconv.Itoa(len(scopes)))
+			for _, scope := range scopes {
+				writeString(h, scope)
+			}
+		}
+	}
+	return "permissions-filter-" + hex.EncodeToString(h.Sum(nil))
+}
+
+// writeString writes the string prefixed by its length, so that the fingerprints of different values can't collide
+func writeString(h hash.Hash, s string) {
+	_ = binary.Write(h, binary.LittleEndian, uint32(len(s)))
+	_, _ = h.Write([]byte(s))
+}
@@ -0,0 +1,58 @@
+package permissions
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestFilterCache(t *testing.T) {
+	features := featuremgmt.WithFeatures()
+	newUser := func(scopes ...string) *user.SignedInUser {
+		return &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer, AuthenticatedBy: login.ExtendedJWTModule,
+			Permissions: map[int64]map[string][]string{1: {dashboards.ActionDashboardsRead: scopes}}}
+	}
+
+	t.Run("should reuse the filter until the permissions of the user change", func(t *testing.T) {
+		cache := NewFilterCache()
+		f := cache.Get(newUser("dashboards:uid:a"), dashboardaccess.PERMISSION_VIEW, searchstore.TypeDashboard, features, true)
+		assert.Same(t, f, cache.Get(newUser("dashboards:uid:a"), dashboardaccess.PERMISSION_VIEW, searchstore.TypeDashboard, features, true))
+		assert.NotSame(t, f, cache.Get(newUser("dashboards:uid:a"), dashboardaccess.PERMISSION_EDIT, searchstore.TypeDashboard, features, true))
+
+		changed := cache.Get(newUser("dashboards:uid:a", "dashboards:uid:b"), dashboardaccess.PERMISSION_VIEW, searchstore.TypeDashboard, features, true)
+		assert.NotSame(t, f, changed)
+		_, params := changed.Where()
+		assert.Len(t, params, 2)
+	})
+
+	t.Run("should join the UIDs of large sets of permissions from temporary tables", func(t *testing.T) {
+		scopes := make([]string, 0, maxInlinedUIDs+1)
+		for i := 0; i <= maxInlinedUIDs; i++ {
+			scopes = append(scopes, fmt.Sprintf("dashboards:uid:%d", i))
+		}
+
+		f := NewFilterCache().Get(newUser(scopes...), dashboardaccess.PERMISSION_VIEW, searchstore.TypeDashboard, features, true)
+		where, params := f.Where()
+		assert.Contains(t, where, "SELECT uid FROM search_filter_uids_0")
+		assert.Empty(t, params)
+		tables := f.(model.FilterTempTables).TempTables()
+		require.Len(t, tables, 1)
+		assert.Len(t, tables[0].Values, maxInlinedUIDs+1)
+
+		// the filters created without the cache can't rely on the temporary tables
+		f = NewAccessControlDashboardPermissionFilter(newUser(scopes...), dashboardaccess.PERMISSION_VIEW, searchstore.TypeDashboard, features, true)
+		_, params = f.Where()
+		assert.Len(t, params, maxInlinedUIDs+1)
+	})
+}
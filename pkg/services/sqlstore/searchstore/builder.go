@@ -53,10 +53,59 @@ func (b *Builder) ToSQL(limit, page int64) (string, []any) {
 	return b.sql.String(), b.params
 }
 
-func (b *Builder) buildSelect() {
-	var recQuery string
-	var recQueryParams []any
+// Facet is a property of the dashboards counted by ToFacetSQL.
+type Facet struct {
+	// Column is the counted column, it's selected as facet_value.
+	Column string
+	// Join is the table of the column and its join condition with the dashboard table, if the column
+	// isn't a column of the dashboard table.
+	Join string
+}
+
+// ToFacetSQL builds the SQL query counting the dashboards matching the filters per value of the facet,
+// most frequent values first, and returns it as a string, together with the SQL parameters.
+// The values are selected as facet_value and their number of dashboards as facet_count.
+func (b *Builder) ToFacetSQL(facet Facet, limit int64) (string, []any) {
+	b.params = make([]any, 0)
+	b.sql = bytes.Buffer{}
+
+	b.sql.WriteString(fmt.Sprintf("SELECT %s AS facet_value, COUNT(DISTINCT dashboard.id) AS facet_count FROM ( ", facet.Column))
+	b.applyFilters()
+	b.sql.WriteString(`) AS ids
+		INNER JOIN dashboard ON ids.id = dashboard.id`)
+	if facet.Join != "" {
+		b.sql.WriteString(fmt.Sprintf(`
+		INNER JOIN %s`, facet.Join))
+	}
+	b.sql.WriteString(fmt.Sprintf(`
+	GROUP BY %s ORDER BY facet_count DESC, facet_value`, facet.Column))
+	b.sql.WriteString(b.Dialect.LimitOffset(limit, 0))
+
+	b.prependWith()
+
+	return b.sql.String(), b.params
+}
+
+// ToCountSQL builds the SQL query counting the dashboards matching the filters and returns it as a string,
+// together with the SQL parameters. It counts at most max dashboards if max is positive.
+// The number of dashboards is selected as total.
+func (b *Builder) ToCountSQL(max int64) (string, []any) {
+	b.params = make([]any, 0)
+	b.sql = bytes.Buffer{}
 
+	b.sql.WriteString("SELECT COUNT(*) AS total FROM ( ")
+	b.applyFilters()
+	if max > 0 {
+		b.sql.WriteString(b.Dialect.LimitOffset(max, 0))
+	}
+	b.sql.WriteString(") AS ids")
+
+	b.prependWith()
+
+	return b.sql.String(), b.params
+}
+
+func (b *Builder) buildSelect() {
 	b.sql.WriteString(
 		`SELECT
 			dashboard.id,
@@ -82,25 +131,33 @@ func (b *Builder) buildSelect() {
 		if f, ok := f.(model.FilterSelect); ok {
 			b.sql.WriteString(fmt.Sprintf(", %s", f.Select()))
 		}
+	}
 
+	b.sql.WriteString(` FROM `)
+
+	b.prependWith()
+}
+
+// prependWith prepends the recursive queries of the filters to the query
+func (b *Builder) prependWith() {
+	var recQuery string
+	var recQueryParams []any
+	for _, f := range b.Filters {
 		if f, ok := f.(model.FilterWith); ok {
 			recQuery, recQueryParams = f.With()
 		}
 	}
 
-	b.sql.WriteString(` FROM `)
-
 	if recQuery == "" {
 		return
 	}
 
-	// prepend recursive queries
 	var bf bytes.Buffer
 	bf.WriteString(recQuery)
 	bf.WriteString(b.sql.String())
 
 	b.sql = bf
-	b.params = append(recQueryParams, b.params...)
+	b.params = append(append([]any{}, recQueryParams...), b.params...)
 }
 
 func (b *Builder) applyFilters() (ordering string) {
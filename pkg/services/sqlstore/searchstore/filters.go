@@ -3,6 +3,7 @@ package searchstore
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/search/model"
@@ -50,6 +51,15 @@ func (f TitleFilter) Where() (string, []any) {
 	return fmt.Sprintf("dashboard.title %s ?", f.Dialect.LikeStr()), []any{"%" + f.Title + "%"}
 }
 
+// UpdatedBeforeFilter matches the dashboards and folders last updated before Before
+type UpdatedBeforeFilter struct {
+	Before time.Time
+}
+
+func (f UpdatedBeforeFilter) Where() (string, []any) {
+	return "dashboard.updated < ?", []any{f.Before}
+}
+
 type FolderFilter struct {
 	IDs []int64
 }
@@ -147,6 +157,35 @@ func (f TagsFilter) Where() (string, []any) {
 	return `dashboard_tag.term IN (?` + strings.Repeat(",?", len(f.Tags)-1) + `)`, params
 }
 
+// LabelsFilter matches the dashboards and folders whose labels match all the selectors
+type LabelsFilter struct {
+	Selectors []model.LabelSelector
+}
+
+func (f LabelsFilter) Where() (string, []any) {
+	const exists = `EXISTS (SELECT 1 FROM dashboard_label WHERE dashboard_label.dashboard_id = dashboard.id AND dashboard_label.name = ?`
+	clauses := make([]string, 0, len(f.Selectors))
+	params := make([]any, 0, 2*len(f.Selectors))
+	for _, selector := range f.Selectors {
+		switch selector.Operator {
+		case model.LabelEquals:
+			clauses = append(clauses, exists+` AND dashboard_label.value = ?)`)
+			params = append(params, selector.Name, selector.Value)
+		case model.LabelNotEquals:
+			// like the Kubernetes selectors, the dashboards without the label match
+			clauses = append(clauses, `NOT `+exists+` AND dashboard_label.value = ?)`)
+			params = append(params, selector.Name, selector.Value)
+		case model.LabelExists:
+			clauses = append(clauses, exists+`)`)
+			params = append(params, selector.Name)
+		case model.LabelNotExists:
+			clauses = append(clauses, `NOT `+exists+`)`)
+			params = append(params, selector.Name)
+		}
+	}
+	return strings.Join(clauses, " AND "), params
+}
+
 type TitleSorter struct {
 	Descending bool
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -257,6 +258,168 @@ func (api *API) UnstarDashboardByUID(c *contextmodel.ReqContext) response.Respon
 	return response.Success("Dashboard unstarred")
 }
 
+// StarLayout is the order of the starred dashboards of the user in the organization, and their collections
+type StarLayout struct {
+	// Dashboards are the UIDs of the starred dashboards that aren't in a collection, in order
+	Dashboards  []string         `json:"dashboards"`
+	Collections []StarCollection `json:"collections"`
+}
+
+type StarCollection struct {
+	Name string `json:"name"`
+	// Dashboards are the UIDs of the starred dashboards of the collection, in order
+	Dashboards []string `json:"dashboards"`
+}
+
+// swagger:route GET /user/stars/layout signed_in_user getStarLayout
+//
+// Get the order and collections of the starred dashboards.
+//
+// Responses:
+// 200: getStarLayoutResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (api *API) GetStarLayout(c *contextmodel.ReqContext) response.Response {
+	namespace, identifier := c.SignedInUser.GetNamespacedID()
+	if namespace != identity.NamespaceUser && namespace != identity.NamespaceServiceAccount {
+		return response.Error(http.StatusBadRequest, "Only users and service accounts can star dashboards", nil)
+	}
+	userID, err := identity.IntIdentifier(namespace, identifier)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Invalid user ID", err)
+	}
+
+	layout, uids, err := api.getStarLayout(c.Req.Context(), userID, c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get the starred dashboards", err)
+	}
+
+	result := StarLayout{Dashboards: []string{}, Collections: make([]StarCollection, len(layout.Collections))}
+	collections := make(map[int64]int, len(layout.Collections))
+	for i, collection := range layout.Collections {
+		result.Collections[i] = StarCollection{Name: collection.Name, Dashboards: []string{}}
+		collections[collection.ID] = i
+	}
+	for _, s := range layout.Stars {
+		uid, ok := uids[s.DashboardID]
+		if !ok {
+			continue
+		}
+		if i, ok := collections[s.CollectionID]; ok {
+			result.Collections[i].Dashboards = append(result.Collections[i].Dashboards, uid)
+		} else {
+			result.Dashboards = append(result.Dashboards, uid)
+		}
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route PUT /user/stars/layout signed_in_user updateStarLayout
+//
+// Order the starred dashboards and group them into collections.
+//
+// Replaces the order of the starred dashboards and their collections. The starred dashboards left out of the
+// layout are added after the listed ones, outside of the collections.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (api *API) UpdateStarLayout(c *contextmodel.ReqContext) response.Response {
+	form := StarLayout{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	namespace, identifier := c.SignedInUser.GetNamespacedID()
+	if namespace != identity.NamespaceUser && namespace != identity.NamespaceServiceAccount {
+		return response.Error(http.StatusBadRequest, "Only users and service accounts can star dashboards", nil)
+	}
+	userID, err := identity.IntIdentifier(namespace, identifier)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Invalid user ID", err)
+	}
+
+	ctx := c.Req.Context()
+	orgID := c.SignedInUser.GetOrgID()
+	layout, uids, err := api.getStarLayout(ctx, userID, orgID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get the starred dashboards", err)
+	}
+	ids := make(map[string]int64, len(uids))
+	for id, uid := range uids {
+		ids[uid] = id
+	}
+
+	listed := map[int64]bool{}
+	toIDs := func(dashboardUIDs []string) ([]int64, response.Response) {
+		result := make([]int64, 0, len(dashboardUIDs))
+		for _, uid := range dashboardUIDs {
+			id, ok := ids[uid]
+			if !ok {
+				return nil, response.Error(http.StatusBadRequest, "Dashboard "+uid+" is not starred", nil)
+			}
+			listed[id] = true
+			result = append(result, id)
+		}
+		return result, nil
+	}
+
+	cmd := star.SetStarLayoutCommand{UserID: userID, OrgID: orgID, Collections: make([]star.SetStarCollectionCommand, len(form.Collections))}
+	var rsp response.Response
+	if cmd.DashboardIDs, rsp = toIDs(form.Dashboards); rsp != nil {
+		return rsp
+	}
+	for i, collection := range form.Collections {
+		cmd.Collections[i].Name = collection.Name
+		if cmd.Collections[i].DashboardIDs, rsp = toIDs(collection.Dashboards); rsp != nil {
+			return rsp
+		}
+	}
+	for _, s := range layout.Stars {
+		if _, ok := uids[s.DashboardID]; ok && !listed[s.DashboardID] {
+			cmd.DashboardIDs = append(cmd.DashboardIDs, s.DashboardID)
+			listed[s.DashboardID] = true
+		}
+	}
+
+	if err := api.starService.SetLayout(ctx, &cmd); err != nil {
+		if errors.Is(err, star.ErrInvalidLayout) || errors.Is(err, star.ErrDashboardNotStarred) {
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to update the starred dashboards", err)
+	}
+	return response.Success("Starred dashboards updated")
+}
+
+// getStarLayout returns the layout of the stars of the user, with the UIDs of the starred dashboards of the
+// organization by ID
+func (api *API) getStarLayout(ctx context.Context, userID int64, orgID int64) (*star.StarLayout, map[int64]string, error) {
+	layout, err := api.starService.GetLayout(ctx, &star.GetStarLayoutQuery{UserID: userID, OrgID: orgID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uids := make(map[int64]string, len(layout.Stars))
+	if len(layout.Stars) == 0 {
+		return layout, uids, nil
+	}
+	ids := make([]int64, len(layout.Stars))
+	for i, s := range layout.Stars {
+		ids[i] = s.DashboardID
+	}
+	starredDashboards, err := api.dashboardService.GetDashboards(ctx, &dashboards.GetDashboardsQuery{DashboardIDs: ids, OrgID: orgID})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, dash := range starredDashboards {
+		uids[dash.ID] = dash.UID
+	}
+	return layout, uids, nil
+}
+
 // swagger:parameters starDashboard
 type StarDashboardParams struct {
 	// in:path
@@ -284,3 +447,16 @@ type UnstarDashboardByUIDParams struct {
 	// required:true
 	DashboardUID string `json:"dashboard_uid"`
 }
+
+// swagger:parameters updateStarLayout
+type UpdateStarLayoutParams struct {
+	// in:body
+	// required:true
+	Body StarLayout `json:"body"`
+}
+
+// swagger:response getStarLayoutResponse
+type GetStarLayoutResponse struct {
+	// in: body
+	Body StarLayout `json:"body"`
+}
@@ -1,13 +1,21 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/star"
 	"github.com/grafana/grafana/pkg/services/star/startest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
@@ -82,3 +90,65 @@ func TestStarDashboard(t *testing.T) {
 		})
 	}
 }
+
+type fakeLayoutStarService struct {
+	startest.FakeStarService
+	cmd *star.SetStarLayoutCommand
+}
+
+func (f *fakeLayoutStarService) SetLayout(ctx context.Context, cmd *star.SetStarLayoutCommand) error {
+	f.cmd = cmd
+	return nil
+}
+
+func TestStarLayout(t *testing.T) {
+	starService := &fakeLayoutStarService{FakeStarService: startest.FakeStarService{ExpectedLayout: &star.StarLayout{
+		Stars: []*star.Star{
+			{DashboardID: 1, Position: 1},
+			{DashboardID: 2, Position: 1, CollectionID: 10},
+			{DashboardID: 3, Position: 2},
+			// a dashboard of another organization
+			{DashboardID: 4, Position: 3},
+		},
+		Collections: []*star.StarCollection{{ID: 10, Name: "Payments"}},
+	}}}
+	dashSvc := dashboards.NewFakeDashboardService(t)
+	dashSvc.On("GetDashboards", mock.Anything, mock.Anything).Return([]*dashboards.Dashboard{{ID: 1, UID: "a"}, {ID: 2, UID: "b"}, {ID: 3, UID: "c"}}, nil)
+	api := ProvideApi(starService, dashSvc)
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1}
+
+	t.Run("should return the starred dashboards of the organization in order", func(t *testing.T) {
+		c := &contextmodel.ReqContext{SignedInUser: signedInUser, Context: &web.Context{Req: &http.Request{}}}
+		resp := api.GetStarLayout(c)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var layout StarLayout
+		require.NoError(t, json.Unmarshal(resp.(*response.NormalResponse).Body(), &layout))
+		assert.Equal(t, StarLayout{
+			Dashboards:  []string{"a", "c"},
+			Collections: []StarCollection{{Name: "Payments", Dashboards: []string{"b"}}},
+		}, layout)
+	})
+
+	update := func(body string) response.Response {
+		req := httptest.NewRequest(http.MethodPut, "/api/user/stars/layout", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return api.UpdateStarLayout(&contextmodel.ReqContext{SignedInUser: signedInUser, Context: &web.Context{Req: req}})
+	}
+
+	t.Run("should append the starred dashboards left out of the layout", func(t *testing.T) {
+		resp := update(`{"dashboards": ["c"], "collections": [{"name": "Checkout", "dashboards": ["b"]}]}`)
+		require.Equal(t, http.StatusOK, resp.Status())
+		assert.Equal(t, &star.SetStarLayoutCommand{
+			UserID:       1,
+			OrgID:        1,
+			DashboardIDs: []int64{3, 1},
+			Collections:  []star.SetStarCollectionCommand{{Name: "Checkout", DashboardIDs: []int64{2}}},
+		}, starService.cmd)
+	})
+
+	t.Run("should reject the dashboards that aren't starred", func(t *testing.T) {
+		resp := update(`{"dashboards": ["d"]}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
@@ -1,13 +1,39 @@
 package star
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
-var ErrCommandValidationFailed = errors.New("command missing required fields")
+var (
+	ErrCommandValidationFailed = errors.New("command missing required fields")
+	ErrInvalidLayout           = errors.New("invalid starred dashboards layout")
+	ErrDashboardNotStarred     = errors.New("dashboard is not starred")
+)
+
+// the maximum length of the names of the collections
+const maxCollectionNameLength = 100
 
 type Star struct {
 	ID          int64 `xorm:"pk autoincr 'id'" db:"id"`
 	UserID      int64 `xorm:"user_id" db:"user_id"`
 	DashboardID int64 `xorm:"dashboard_id" db:"dashboard_id"`
+	// Position is the position of the star in the starred dashboards of the user, or in its collection
+	Position int `xorm:"position" db:"position"`
+	// CollectionID is the collection the star is grouped in, 0 when it isn't
+	CollectionID int64 `xorm:"collection_id" db:"collection_id"`
+}
+
+// StarCollection is a named group of the starred dashboards of a user in an organization
+type StarCollection struct {
+	ID       int64     `xorm:"pk autoincr 'id'" db:"id"`
+	OrgID    int64     `xorm:"org_id" db:"org_id"`
+	UserID   int64     `xorm:"user_id" db:"user_id"`
+	Name     string    `xorm:"name" db:"name"`
+	Position int       `xorm:"position" db:"position"`
+	Created  time.Time `xorm:"created" db:"created"`
 }
 
 // ----------------------
@@ -52,3 +78,65 @@ type IsStarredByUserQuery struct {
 type GetUserStarsResult struct {
 	UserStars map[int64]bool
 }
+
+type GetStarLayoutQuery struct {
+	UserID int64
+	OrgID  int64
+}
+
+// StarLayout are the stars of a user ordered by position, and their collections in the organization ordered by
+// position
+type StarLayout struct {
+	Stars       []*Star
+	Collections []*StarCollection
+}
+
+// SetStarLayoutCommand replaces the order of the starred dashboards of the user and their collections in the
+// organization
+type SetStarLayoutCommand struct {
+	UserID int64
+	OrgID  int64
+	// DashboardIDs are the starred dashboards that aren't in a collection, in order
+	DashboardIDs []int64
+	Collections  []SetStarCollectionCommand
+}
+
+type SetStarCollectionCommand struct {
+	Name         string
+	DashboardIDs []int64
+}
+
+func (cmd *SetStarLayoutCommand) Validate() error {
+	if cmd.UserID == 0 || cmd.OrgID == 0 {
+		return ErrCommandValidationFailed
+	}
+
+	dashboards := make(map[int64]bool, len(cmd.DashboardIDs))
+	names := make(map[string]bool, len(cmd.Collections))
+	checkDashboards := func(ids []int64) error {
+		for _, id := range ids {
+			if dashboards[id] {
+				return fmt.Errorf("%w: dashboard %d is listed more than once", ErrInvalidLayout, id)
+			}
+			dashboards[id] = true
+		}
+		return nil
+	}
+	if err := checkDashboards(cmd.DashboardIDs); err != nil {
+		return err
+	}
+	for _, c := range cmd.Collections {
+		name := strings.TrimSpace(c.Name)
+		if name == "" || len(name) > maxCollectionNameLength {
+			return fmt.Errorf("%w: collection names are between 1 and %d characters", ErrInvalidLayout, maxCollectionNameLength)
+		}
+		if names[name] {
+			return fmt.Errorf("%w: collection %q is listed more than once", ErrInvalidLayout, name)
+		}
+		names[name] = true
+		if err := checkDashboards(c.DashboardIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
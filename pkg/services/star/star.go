@@ -10,4 +10,6 @@ type Service interface {
 	DeleteByUser(context.Context, int64) error
 	IsStarredByUser(context.Context, *IsStarredByUserQuery) (bool, error)
 	GetByUser(context.Context, *GetUserStarsQuery) (*GetUserStarsResult, error)
+	GetLayout(context.Context, *GetStarLayoutQuery) (*StarLayout, error)
+	SetLayout(context.Context, *SetStarLayoutCommand) error
 }
@@ -44,3 +44,14 @@ func (s *Service) GetByUser(ctx context.Context, cmd *star.GetUserStarsQuery) (*
 func (s *Service) DeleteByUser(ctx context.Context, userID int64) error {
 	return s.store.DeleteByUser(ctx, userID)
 }
+
+func (s *Service) GetLayout(ctx context.Context, query *star.GetStarLayoutQuery) (*star.StarLayout, error) {
+	return s.store.GetLayout(ctx, query)
+}
+
+func (s *Service) SetLayout(ctx context.Context, cmd *star.SetStarLayoutCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+	return s.store.SetLayout(ctx, cmd)
+}
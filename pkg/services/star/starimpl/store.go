@@ -12,4 +12,6 @@ type store interface {
 	Delete(context.Context, *star.UnstarDashboardCommand) error
 	DeleteByUser(context.Context, int64) error
 	List(context.Context, *star.GetUserStarsQuery) (*star.GetUserStarsResult, error)
+	GetLayout(context.Context, *star.GetStarLayoutQuery) (*star.StarLayout, error)
+	SetLayout(context.Context, *star.SetStarLayoutCommand) error
 }
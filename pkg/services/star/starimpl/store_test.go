@@ -92,5 +92,44 @@ func testIntegrationUserStarsDataAccess(t *testing.T, fn getStore) {
 			require.NoError(t, err)
 			require.Equal(t, 1, len(res.UserStars))
 		})
+
+		t.Run("SetLayout should order the stars and group them into collections", func(t *testing.T) {
+			ctx := context.Background()
+			for _, id := range []int64{20, 21, 22} {
+				require.NoError(t, starStore.Insert(ctx, &star.StarDashboardCommand{DashboardID: id, UserID: 13}))
+			}
+
+			layout, err := starStore.GetLayout(ctx, &star.GetStarLayoutQuery{UserID: 13, OrgID: 1})
+			require.NoError(t, err)
+			require.Len(t, layout.Stars, 3)
+			require.Equal(t, []int64{20, 21, 22}, []int64{layout.Stars[0].DashboardID, layout.Stars[1].DashboardID, layout.Stars[2].DashboardID})
+
+			err = starStore.SetLayout(ctx, &star.SetStarLayoutCommand{UserID: 13, OrgID: 1, DashboardIDs: []int64{22}, Collections: []star.SetStarCollectionCommand{
+				{Name: "Payments", DashboardIDs: []int64{21, 20}},
+			}})
+			require.NoError(t, err)
+			layout, err = starStore.GetLayout(ctx, &star.GetStarLayoutQuery{UserID: 13, OrgID: 1})
+			require.NoError(t, err)
+			require.Len(t, layout.Collections, 1)
+			require.Equal(t, "Payments", layout.Collections[0].Name)
+			collectionID := layout.Collections[0].ID
+			positions := map[int64][2]int64{}
+			for _, s := range layout.Stars {
+				positions[s.DashboardID] = [2]int64{s.CollectionID, int64(s.Position)}
+			}
+			require.Equal(t, map[int64][2]int64{22: {0, 1}, 21: {collectionID, 1}, 20: {collectionID, 2}}, positions)
+
+			err = starStore.SetLayout(ctx, &star.SetStarLayoutCommand{UserID: 13, OrgID: 1, DashboardIDs: []int64{10}})
+			require.ErrorIs(t, err, star.ErrDashboardNotStarred)
+
+			err = starStore.SetLayout(ctx, &star.SetStarLayoutCommand{UserID: 13, OrgID: 1, DashboardIDs: []int64{20, 21, 22}})
+			require.NoError(t, err)
+			layout, err = starStore.GetLayout(ctx, &star.GetStarLayoutQuery{UserID: 13, OrgID: 1})
+			require.NoError(t, err)
+			require.Empty(t, layout.Collections)
+			for _, s := range layout.Stars {
+				require.Zero(t, s.CollectionID)
+			}
+		})
 	})
 }
@@ -2,6 +2,9 @@ package starimpl
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/star"
@@ -29,9 +32,15 @@ func (s *sqlStore) Get(ctx context.Context, query *star.IsStarredByUserQuery) (b
 
 func (s *sqlStore) Insert(ctx context.Context, cmd *star.StarDashboardCommand) error {
 	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		// new stars are added after the existing ones
+		var position int
+		if _, err := sess.SQL("SELECT COALESCE(MAX(position), 0) FROM star WHERE user_id = ?", cmd.UserID).Get(&position); err != nil {
+			return err
+		}
 		entity := star.Star{
 			UserID:      cmd.UserID,
 			DashboardID: cmd.DashboardID,
+			Position:    position + 1,
 		}
 
 		_, err := sess.Insert(&entity)
@@ -54,7 +63,10 @@ func (s *sqlStore) Delete(ctx context.Context, cmd *star.UnstarDashboardCommand)
 func (s *sqlStore) DeleteByUser(ctx context.Context, userID int64) error {
 	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		var rawSQL = "DELETE FROM star WHERE user_id = ?"
-		_, err := sess.Exec(rawSQL, userID)
+		if _, err := sess.Exec(rawSQL, userID); err != nil {
+			return err
+		}
+		_, err := sess.Exec("DELETE FROM star_collection WHERE user_id = ?", userID)
 		return err
 	})
 }
@@ -71,3 +83,64 @@ func (s *sqlStore) List(ctx context.Context, query *star.GetUserStarsQuery) (*st
 	})
 	return &star.GetUserStarsResult{UserStars: userStars}, err
 }
+
+func (s *sqlStore) GetLayout(ctx context.Context, query *star.GetStarLayoutQuery) (*star.StarLayout, error) {
+	layout := &star.StarLayout{Stars: make([]*star.Star, 0), Collections: make([]*star.StarCollection, 0)}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if err := sess.Where("user_id = ?", query.UserID).Asc("position", "id").Find(&layout.Stars); err != nil {
+			return err
+		}
+		return sess.Where("org_id = ? AND user_id = ?", query.OrgID, query.UserID).Asc("position", "id").Find(&layout.Collections)
+	})
+	return layout, err
+}
+
+func (s *sqlStore) SetLayout(ctx context.Context, cmd *star.SetStarLayoutCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		// the stars of the replaced collections are ungrouped, unless they're listed in the new ones
+		if _, err := sess.Exec("UPDATE star SET collection_id = 0 WHERE user_id = ? AND collection_id IN (SELECT id FROM star_collection WHERE org_id = ? AND user_id = ?)",
+			cmd.UserID, cmd.OrgID, cmd.UserID); err != nil {
+			return err
+		}
+		if _, err := sess.Exec("DELETE FROM star_collection WHERE org_id = ? AND user_id = ?", cmd.OrgID, cmd.UserID); err != nil {
+			return err
+		}
+
+		setPositions := func(collectionID int64, dashboardIDs []int64) error {
+			for i, dashboardID := range dashboardIDs {
+				res, err := sess.Exec("UPDATE star SET position = ?, collection_id = ? WHERE user_id = ? AND dashboard_id = ?",
+					i+1, collectionID, cmd.UserID, dashboardID)
+				if err != nil {
+					return err
+				}
+				if affected, err := res.RowsAffected(); err != nil {
+					return err
+				} else if affected == 0 {
+					return fmt.Errorf("%w: %d", star.ErrDashboardNotStarred, dashboardID)
+				}
+			}
+			return nil
+		}
+
+		if err := setPositions(0, cmd.DashboardIDs); err != nil {
+			return err
+		}
+		now := time.Now()
+		for i, c := range cmd.Collections {
+			collection := &star.StarCollection{
+				OrgID:    cmd.OrgID,
+				UserID:   cmd.UserID,
+				Name:     strings.TrimSpace(c.Name),
+				Position: i + 1,
+				Created:  now,
+			}
+			if _, err := sess.Insert(collection); err != nil {
+				return err
+			}
+			if err := setPositions(collection.ID, c.DashboardIDs); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -10,6 +10,7 @@ type FakeStarService struct {
 	ExpectedStars     *star.Star
 	ExpectedError     error
 	ExpectedUserStars *star.GetUserStarsResult
+	ExpectedLayout    *star.StarLayout
 }
 
 func NewStarServiceFake() *FakeStarService {
@@ -35,3 +36,11 @@ func (f *FakeStarService) DeleteByUser(ctx context.Context, userID int64) error
 func (f *FakeStarService) GetByUser(ctx context.Context, query *star.GetUserStarsQuery) (*star.GetUserStarsResult, error) {
 	return f.ExpectedUserStars, f.ExpectedError
 }
+
+func (f *FakeStarService) GetLayout(ctx context.Context, query *star.GetStarLayoutQuery) (*star.StarLayout, error) {
+	return f.ExpectedLayout, f.ExpectedError
+}
+
+func (f *FakeStarService) SetLayout(ctx context.Context, cmd *star.SetStarLayoutCommand) error {
+	return f.ExpectedError
+}
@@ -110,6 +110,26 @@ type AdminStats struct {
 
 type GetAdminStatsQuery struct{}
 
+// OrgStats is the resource usage of a single organization
+type OrgStats struct {
+	OrgID       int64 `json:"orgId" xorm:"-"`
+	Dashboards  int64 `json:"dashboards"`
+	Folders     int64 `json:"folders"`
+	Alerts      int64 `json:"alerts"`
+	Datasources int64 `json:"datasources"`
+	Users       int64 `json:"users"`
+	APIKeys     int64 `json:"apiKeys" xorm:"api_keys"`
+	Snapshots   int64 `json:"snapshots"`
+	// Storage footprint in bytes of the dashboard JSON, its version history and the snapshots
+	DashboardBytes        int64 `json:"dashboardBytes"`
+	DashboardVersionBytes int64 `json:"dashboardVersionBytes"`
+	SnapshotBytes         int64 `json:"snapshotBytes"`
+}
+
+type GetOrgStatsQuery struct {
+	OrgID int64
+}
+
 type SystemUserCountStats struct {
 	Count int64
 }
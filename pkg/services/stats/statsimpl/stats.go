@@ -7,8 +7,13 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 	"github.com/grafana/grafana/pkg/services/stats"
 	"github.com/grafana/grafana/pkg/setting"
@@ -17,13 +22,14 @@ import (
 const activeUserTimeLimit = time.Hour * 24 * 30
 const dailyActiveUserTimeLimit = time.Hour * 24
 
-func ProvideService(cfg *setting.Cfg, db db.DB) stats.Service {
-	return &sqlStatsService{cfg: cfg, db: db}
+func ProvideService(cfg *setting.Cfg, db db.DB, quotaService quota.Service) stats.Service {
+	return &sqlStatsService{cfg: cfg, db: db, quotaService: quotaService}
 }
 
 type sqlStatsService struct {
-	db  db.DB
-	cfg *setting.Cfg
+	db           db.DB
+	cfg          *setting.Cfg
+	quotaService quota.Service
 }
 
 func (ss *sqlStatsService) GetAlertNotifiersUsageStats(ctx context.Context, query *stats.GetAlertNotifierUsageStatsQuery) (result []*stats.NotifierUsageStats, err error) {
@@ -228,6 +234,67 @@ func (ss *sqlStatsService) GetAdminStats(ctx context.Context, query *stats.GetAd
 	return result, err
 }
 
+func (ss *sqlStatsService) GetOrgStats(ctx context.Context, query *stats.GetOrgStatsQuery) (*stats.OrgStats, error) {
+	// the resources that have a quota are counted the same way as their quota usage
+	usage, err := ss.quotaService.GetUsage(ctx, &quota.ScopeParameters{OrgID: query.OrgID})
+	if err != nil {
+		return nil, err
+	}
+	used := func(srv quota.TargetSrv, target quota.Target) int64 {
+		tag, err := quota.NewTag(srv, target, quota.OrgScope)
+		if err != nil {
+			return 0
+		}
+		count, _ := usage.Get(tag)
+		return count
+	}
+
+	result := &stats.OrgStats{}
+	dialect := ss.db.GetDialect()
+	err = ss.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		sb := &db.SQLBuilder{}
+		sb.Write("SELECT ")
+		sb.Write(`(SELECT COUNT(id) FROM `+dialect.Quote("dashboard")+` WHERE org_id = ? AND is_folder = `+dialect.BooleanStr(true)+`) AS folders,`, query.OrgID)
+		if !ss.IsUnifiedAlertingEnabled() {
+			sb.Write(`(SELECT COUNT(id) FROM `+dialect.Quote("alert")+` WHERE org_id = ?) AS alerts,`, query.OrgID)
+		}
+		sb.Write(`(SELECT COUNT(id) FROM `+dialect.Quote("dashboard_snapshot")+` WHERE org_id = ?) AS snapshots,`, query.OrgID)
+		sb.Write(`(SELECT COALESCE(SUM(`+byteLength(dialect, "data")+`), 0) FROM `+dialect.Quote("dashboard")+` WHERE org_id = ?) AS dashboard_bytes,`, query.OrgID)
+		sb.Write(`(SELECT COALESCE(SUM(`+byteLength(dialect, "data")+`), 0) FROM `+dialect.Quote("dashboard_version")+` WHERE dashboard_id IN (SELECT id FROM `+
+			dialect.Quote("dashboard")+` WHERE org_id = ?)) AS dashboard_version_bytes,`, query.OrgID)
+		sb.Write(`(SELECT COALESCE(SUM(`+byteLength(dialect, "dashboard_encrypted")+`), 0) FROM `+dialect.Quote("dashboard_snapshot")+` WHERE org_id = ?) AS snapshot_bytes`, query.OrgID)
+
+		_, err := dbSession.SQL(sb.GetSQLString(), sb.GetParams()...).Get(result)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.OrgID = query.OrgID
+	result.Dashboards = used(dashboards.QuotaTargetSrv, dashboards.QuotaTarget)
+	result.Datasources = used(datasources.QuotaTargetSrv, datasources.QuotaTarget)
+	result.Users = used(quota.TargetSrv(org.QuotaTargetSrv), quota.Target(org.OrgUserQuotaTarget))
+	result.APIKeys = used(apikey.QuotaTargetSrv, apikey.QuotaTarget)
+	if ss.IsUnifiedAlertingEnabled() {
+		result.Alerts = used(ngmodels.QuotaTargetSrv, ngmodels.QuotaTarget)
+	}
+	return result, nil
+}
+
+// byteLength returns the SQL expression of the size in bytes of the column, LENGTH counts
+// characters on Postgres and on SQLite text columns.
+func byteLength(dialect migrator.Dialect, column string) string {
+	switch dialect.DriverName() {
+	case migrator.Postgres:
+		return "OCTET_LENGTH(" + column + ")"
+	case migrator.SQLite:
+		return "LENGTH(CAST(" + column + " AS BLOB))"
+	default:
+		return "LENGTH(" + column + ")"
+	}
+}
+
 func (ss *sqlStatsService) GetSystemUserCountStats(ctx context.Context, query *stats.GetSystemUserCountStatsQuery) (result *stats.SystemUserCountStats, err error) {
 	err = ss.db.WithDbSession(ctx, func(sess *db.Session) error {
 		var rawSQL = `SELECT COUNT(id) AS Count FROM ` + ss.db.GetDialect().Quote("user")
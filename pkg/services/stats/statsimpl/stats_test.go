@@ -4,14 +4,17 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/correlations"
 	"github.com/grafana/grafana/pkg/services/correlations/correlationstest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgimpl"
+	"github.com/grafana/grafana/pkg/services/quota/quotaimpl"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/stats"
@@ -31,7 +34,8 @@ func TestIntegrationStatsDataAccess(t *testing.T) {
 		t.Skip("skipping integration test")
 	}
 	db := sqlstore.InitTestDB(t)
-	statsService := &sqlStatsService{db: db}
+	quotaService := quotaimpl.ProvideService(db, db.Cfg)
+	statsService := &sqlStatsService{db: db, quotaService: quotaService}
 	populateDB(t, db)
 
 	t.Run("Get system stats should not results in error", func(t *testing.T) {
@@ -79,6 +83,46 @@ func TestIntegrationStatsDataAccess(t *testing.T) {
 		_, err := statsService.GetAdminStats(context.Background(), &query)
 		assert.NoError(t, err)
 	})
+
+	t.Run("Get org stats should only count the resources of the org", func(t *testing.T) {
+		// registers the usage reporter of the org users
+		orgService, err := orgimpl.ProvideService(db, db.Cfg, quotaService)
+		require.NoError(t, err)
+		o, err := orgService.GetByName(context.Background(), &org.GetOrgByNameQuery{Name: "Org #0"})
+		require.NoError(t, err)
+
+		result, err := statsService.GetOrgStats(context.Background(), &stats.GetOrgStatsQuery{OrgID: o.ID})
+		require.NoError(t, err)
+		assert.Equal(t, o.ID, result.OrgID)
+		assert.Equal(t, int64(3), result.Users)
+		assert.Equal(t, int64(0), result.Dashboards)
+		assert.Equal(t, int64(0), result.APIKeys)
+		assert.Equal(t, int64(0), result.DashboardBytes)
+
+		o, err = orgService.GetByName(context.Background(), &org.GetOrgByNameQuery{Name: "Org #2"})
+		require.NoError(t, err)
+		result, err = statsService.GetOrgStats(context.Background(), &stats.GetOrgStatsQuery{OrgID: o.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Users)
+	})
+
+	t.Run("Get org stats should count the storage in bytes", func(t *testing.T) {
+		dash := dashboards.NewDashboard("café")
+		dash.OrgID = 42
+		dash.UID = "bytes"
+		dash.Created, dash.Updated = time.Now(), time.Now()
+		data, err := dash.Data.Encode()
+		require.NoError(t, err)
+		err = db.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+			_, err := sess.Insert(dash)
+			return err
+		})
+		require.NoError(t, err)
+
+		result, err := statsService.GetOrgStats(context.Background(), &stats.GetOrgStatsQuery{OrgID: 42})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), result.DashboardBytes)
+	})
 }
 
 func populateDB(t *testing.T, sqlStore *sqlstore.SQLStore) {
@@ -152,7 +196,7 @@ func TestIntegration_GetAdminStats(t *testing.T) {
 		t.Skip("skipping integration test")
 	}
 	db := sqlstore.InitTestDB(t)
-	statsService := ProvideService(&setting.Cfg{}, db)
+	statsService := ProvideService(&setting.Cfg{}, db, quotatest.New(false, nil))
 
 	query := stats.GetAdminStatsQuery{}
 	_, err := statsService.GetAdminStats(context.Background(), &query)
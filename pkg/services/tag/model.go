@@ -58,3 +58,35 @@ func JoinTagPairs(tags []*Tag) []string {
 
 	return tagPairs
 }
+
+// Usage is the number of dashboards, annotations and alert rules using a tag.
+// Alert rules use their labels as tags, in the key:value form of the annotation tags.
+type Usage struct {
+	Tag         string `json:"tag"`
+	Dashboards  int64  `json:"dashboards"`
+	Annotations int64  `json:"annotations"`
+	AlertRules  int64  `json:"alertRules"`
+	Total       int64  `json:"total"`
+}
+
+// GetUsageQuery returns the tags of an organization starting with Prefix,
+// the most used first. A Limit of 0 returns all the tags.
+type GetUsageQuery struct {
+	OrgID  int64
+	Prefix string
+	Limit  int
+}
+
+// RenameCommand replaces the tag From by To on the dashboards and annotations
+// of an organization. The tags are merged when To is already in use.
+type RenameCommand struct {
+	OrgID int64
+	From  string
+	To    string
+}
+
+// RenameResult is the number of dashboards and annotations whose tags were renamed.
+type RenameResult struct {
+	Dashboards  int64 `json:"dashboards"`
+	Annotations int64 `json:"annotations"`
+}
@@ -6,4 +6,6 @@ import (
 
 type Service interface {
 	EnsureTagsExist(ctx context.Context, tags []*Tag) ([]*Tag, error)
+	GetUsage(ctx context.Context, query *GetUsageQuery) ([]*Usage, error)
+	Rename(ctx context.Context, cmd *RenameCommand) (*RenameResult, error)
 }
@@ -8,4 +8,6 @@ import (
 
 type store interface {
 	EnsureTagsExist(context.Context, []*tag.Tag) ([]*tag.Tag, error)
+	GetUsage(context.Context, *tag.GetUsageQuery) ([]*tag.Usage, error)
+	Rename(context.Context, *tag.RenameCommand) (*tag.RenameResult, error)
 }
@@ -2,7 +2,10 @@ package tagimpl
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -33,3 +36,112 @@ func testIntegrationSavingTags(t *testing.T, fn getStore) {
 	require.Nil(t, err)
 	require.Equal(t, 4, len(tags))
 }
+
+func testIntegrationTagUsageAndRename(t *testing.T, fn getStore) {
+	t.Helper()
+
+	ss := db.InitTestDB(t)
+	store := fn(ss)
+	ctx := context.Background()
+	now := time.Now()
+
+	err := ss.WithDbSession(ctx, func(sess *db.Session) error {
+		for i, tags := range [][]string{{"prod", "db"}, {"production"}, {"prod"}} {
+			data := fmt.Sprintf(`{"title":"dash-%d","tags":["%s"]}`, i, strings.Join(tags, `","`))
+			res, err := sess.Exec(`INSERT INTO dashboard (version, slug, title, data, org_id, created, updated, uid) VALUES (1, ?, ?, ?, 1, ?, ?, ?)`,
+				fmt.Sprintf("dash-%d", i), fmt.Sprintf("dash-%d", i), data, now, now, fmt.Sprintf("dash-%d", i))
+			if err != nil {
+				return err
+			}
+			id, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			for _, term := range tags {
+				if _, err := sess.Exec(`INSERT INTO dashboard_tag (dashboard_id, term) VALUES (?, ?)`, id, term); err != nil {
+					return err
+				}
+			}
+		}
+		// a dashboard of another organization
+		if _, err := sess.Exec(`INSERT INTO dashboard (version, slug, title, data, org_id, created, updated, uid) VALUES (1, 'other', 'other', '{}', 2, ?, ?, 'other')`, now, now); err != nil {
+			return err
+		}
+		if _, err := sess.Exec(`INSERT INTO dashboard_tag (dashboard_id, term) VALUES (4, 'prod')`); err != nil {
+			return err
+		}
+
+		_, err := sess.Exec(`INSERT INTO alert_rule (org_id, title, `+ss.GetDialect().Quote("condition")+`, data, updated, uid, namespace_uid, rule_group, labels) VALUES (1, 'rule', 'A', '[]', ?, 'rule', 'folder', 'group', ?)`,
+			now, `{"env":"prod","team":"db"}`)
+		return err
+	})
+	require.NoError(t, err)
+
+	tags, err := store.EnsureTagsExist(ctx, []*tag.Tag{{Key: "prod"}, {Key: "env", Value: "prod"}})
+	require.NoError(t, err)
+	err = ss.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec(`INSERT INTO annotation (org_id, alert_id, user_id, dashboard_id, panel_id, type, title, text, metric, prev_state, new_state, data, epoch, epoch_end, tags)
+			VALUES (1, 0, 0, 0, 0, '', '', 'deploy', '', '', '', '{}', 1, 1, '["prod","env:prod"]')`)
+		if err != nil {
+			return err
+		}
+		for _, tg := range tags {
+			if _, err := sess.Exec(`INSERT INTO annotation_tag (annotation_id, tag_id) VALUES (1, ?)`, tg.Id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	t.Run("counts the tags of the organization", func(t *testing.T) {
+		usage, err := store.GetUsage(ctx, &tag.GetUsageQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, usage, 5)
+		require.Equal(t, tag.Usage{Tag: "prod", Dashboards: 2, Annotations: 1, Total: 3}, *usage[0])
+		require.Equal(t, tag.Usage{Tag: "env:prod", Annotations: 1, AlertRules: 1, Total: 2}, *usage[1])
+	})
+
+	t.Run("filters the tags by prefix", func(t *testing.T) {
+		usage, err := store.GetUsage(ctx, &tag.GetUsageQuery{OrgID: 1, Prefix: "prod", Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		require.Equal(t, "prod", usage[0].Tag)
+
+		usage, err = store.GetUsage(ctx, &tag.GetUsageQuery{OrgID: 1, Prefix: "team:"})
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		require.Equal(t, tag.Usage{Tag: "team:db", AlertRules: 1, Total: 1}, *usage[0])
+	})
+
+	t.Run("merges a tag into another", func(t *testing.T) {
+		result, err := store.Rename(ctx, &tag.RenameCommand{OrgID: 1, From: "prod", To: "production"})
+		require.NoError(t, err)
+		require.Equal(t, tag.RenameResult{Dashboards: 2, Annotations: 1}, *result)
+
+		usage, err := store.GetUsage(ctx, &tag.GetUsageQuery{OrgID: 1, Prefix: "prod"})
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		require.Equal(t, tag.Usage{Tag: "production", Dashboards: 3, Annotations: 1, Total: 4}, *usage[0])
+
+		err = ss.WithDbSession(ctx, func(sess *db.Session) error {
+			var data string
+			if _, err := sess.SQL(`SELECT data FROM dashboard WHERE slug = 'dash-0'`).Get(&data); err != nil {
+				return err
+			}
+			require.JSONEq(t, `{"title":"dash-0","tags":["production","db"]}`, data)
+
+			var annotationTags string
+			if _, err := sess.SQL(`SELECT tags FROM annotation WHERE id = 1`).Get(&annotationTags); err != nil {
+				return err
+			}
+			require.JSONEq(t, `["production","env:prod"]`, annotationTags)
+
+			var other int64
+			_, err := sess.SQL(`SELECT COUNT(*) FROM dashboard_tag WHERE dashboard_id = 4 AND term = 'prod'`).Get(&other)
+			require.Equal(t, int64(1), other)
+			return err
+		})
+		require.NoError(t, err)
+	})
+}
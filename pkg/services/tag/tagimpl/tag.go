@@ -22,3 +22,11 @@ func ProvideService(db db.DB) *Service {
 func (s *Service) EnsureTagsExist(ctx context.Context, tags []*tag.Tag) ([]*tag.Tag, error) {
 	return s.store.EnsureTagsExist(ctx, tags)
 }
+
+func (s *Service) GetUsage(ctx context.Context, query *tag.GetUsageQuery) ([]*tag.Usage, error) {
+	return s.store.GetUsage(ctx, query)
+}
+
+func (s *Service) Rename(ctx context.Context, cmd *tag.RenameCommand) (*tag.RenameResult, error) {
+	return s.store.Rename(ctx, cmd)
+}
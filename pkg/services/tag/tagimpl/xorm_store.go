@@ -2,7 +2,13 @@ package tagimpl
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/tag"
 )
@@ -48,3 +54,233 @@ func (s *sqlStore) innerGetTag(sess *db.Session, tagElement *tag.Tag) (bool, err
 	tagElement.Id = existingTag.Id
 	return true, nil
 }
+
+type termCount struct {
+	Term  string `xorm:"term"`
+	Count int64  `xorm:"count"`
+}
+
+// GetUsage counts the tags of the dashboard_tag and annotation_tag tables and the labels of the alert rules,
+// the prefix filters the terms and the keys of the tags in SQL before the full tags are matched.
+func (s *sqlStore) GetUsage(ctx context.Context, query *tag.GetUsageQuery) ([]*tag.Usage, error) {
+	usage := map[string]*tag.Usage{}
+	get := func(t string) *tag.Usage {
+		u, ok := usage[t]
+		if !ok {
+			u = &tag.Usage{Tag: t}
+			usage[t] = u
+		}
+		return u
+	}
+
+	dialect := s.db.GetDialect()
+	keyPrefix, _, _ := strings.Cut(query.Prefix, ":")
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var dashboardTags []termCount
+		sql := `SELECT dashboard_tag.term, COUNT(*) AS count FROM dashboard_tag
+			INNER JOIN dashboard ON dashboard.id = dashboard_tag.dashboard_id
+			WHERE dashboard.org_id = ? AND dashboard_tag.term ` + dialect.LikeStr() + ` ? GROUP BY dashboard_tag.term`
+		if err := sess.SQL(sql, query.OrgID, query.Prefix+"%").Find(&dashboardTags); err != nil {
+			return err
+		}
+		for _, t := range dashboardTags {
+			get(t.Term).Dashboards += t.Count
+		}
+
+		var annotationTags []struct {
+			Key   string `xorm:"key"`
+			Value string `xorm:"value"`
+			Count int64  `xorm:"count"`
+		}
+		tagKey, tagValue := "tag."+dialect.Quote("key"), "tag."+dialect.Quote("value")
+		sql = `SELECT ` + tagKey + `, ` + tagValue + `, COUNT(*) AS count FROM tag
+			INNER JOIN annotation_tag ON annotation_tag.tag_id = tag.id
+			INNER JOIN annotation ON annotation.id = annotation_tag.annotation_id
+			WHERE annotation.org_id = ? AND ` + tagKey + ` ` + dialect.LikeStr() + ` ? GROUP BY ` + tagKey + `, ` + tagValue
+		if err := sess.SQL(sql, query.OrgID, keyPrefix+"%").Find(&annotationTags); err != nil {
+			return err
+		}
+		for _, t := range annotationTags {
+			get(tag.JoinTagPairs([]*tag.Tag{{Key: t.Key, Value: t.Value}})[0]).Annotations += t.Count
+		}
+
+		var rules []string
+		if err := sess.SQL(`SELECT labels FROM alert_rule WHERE org_id = ?`, query.OrgID).Find(&rules); err != nil {
+			return err
+		}
+		for _, encoded := range rules {
+			if encoded == "" {
+				continue
+			}
+			labels := map[string]string{}
+			if err := json.Unmarshal([]byte(encoded), &labels); err != nil {
+				continue
+			}
+			for k, v := range labels {
+				if strings.HasPrefix(k, keyPrefix) {
+					get(k + ":" + v).AlertRules++
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*tag.Usage, 0, len(usage))
+	for _, u := range usage {
+		if !strings.HasPrefix(u.Tag, query.Prefix) {
+			continue
+		}
+		u.Total = u.Dashboards + u.Annotations + u.AlertRules
+		result = append(result, u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	if query.Limit > 0 && len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+	return result, nil
+}
+
+// Rename replaces the tag on the dashboards and annotations in a single transaction. The tags of the
+// dashboard JSON are updated with the dashboard_tag rows and the version of the dashboards is incremented,
+// so that the dashboards opened before the rename can't be saved with the previous tags.
+// The labels of the alert rules aren't renamed, since they route the notifications of the rules.
+func (s *sqlStore) Rename(ctx context.Context, cmd *tag.RenameCommand) (*tag.RenameResult, error) {
+	result := &tag.RenameResult{}
+	err := s.db.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if result.Dashboards, err = s.renameDashboardTags(ctx, cmd); err != nil {
+			return err
+		}
+		result.Annotations, err = s.renameAnnotationTags(ctx, cmd)
+		return err
+	})
+	return result, err
+}
+
+func (s *sqlStore) renameDashboardTags(ctx context.Context, cmd *tag.RenameCommand) (int64, error) {
+	var affected int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var dashes []struct {
+			ID      int64  `xorm:"id"`
+			Data    string `xorm:"data"`
+			Version int    `xorm:"version"`
+		}
+		err := sess.SQL(`SELECT dashboard.id, dashboard.data, dashboard.version FROM dashboard
+			INNER JOIN dashboard_tag ON dashboard_tag.dashboard_id = dashboard.id
+			WHERE dashboard.org_id = ? AND dashboard_tag.term = ?`, cmd.OrgID, cmd.From).Find(&dashes)
+		if err != nil {
+			return err
+		}
+
+		for _, dash := range dashes {
+			data, err := simplejson.NewJson([]byte(dash.Data))
+			if err != nil {
+				return fmt.Errorf("failed to parse dashboard %d: %w", dash.ID, err)
+			}
+			tags := renameTerms(data.Get("tags").MustStringArray(), cmd.From, cmd.To)
+			data.Set("tags", tags)
+			encoded, err := data.Encode()
+			if err != nil {
+				return err
+			}
+			if _, err := sess.Exec(`UPDATE dashboard SET data = ?, version = ?, updated = ? WHERE id = ?`,
+				encoded, dash.Version+1, time.Now(), dash.ID); err != nil {
+				return err
+			}
+
+			if _, err := sess.Exec(`DELETE FROM dashboard_tag WHERE dashboard_id = ? AND term = ?`, dash.ID, cmd.From); err != nil {
+				return err
+			}
+			exists, err := sess.Table("dashboard_tag").Where("dashboard_id = ? AND term = ?", dash.ID, cmd.To).Exist()
+			if err != nil {
+				return err
+			}
+			if !exists {
+				if _, err := sess.Exec(`INSERT INTO dashboard_tag (dashboard_id, term) VALUES (?, ?)`, dash.ID, cmd.To); err != nil {
+					return err
+				}
+			}
+			affected++
+		}
+		return nil
+	})
+	return affected, err
+}
+
+func (s *sqlStore) renameAnnotationTags(ctx context.Context, cmd *tag.RenameCommand) (int64, error) {
+	from, to := tag.ParseTagPairs([]string{cmd.From}), tag.ParseTagPairs([]string{cmd.To})
+	if len(from) != 1 || len(to) != 1 {
+		return 0, nil
+	}
+
+	var affected int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := s.innerGetTag(sess, from[0])
+		if err != nil || !exists {
+			return err
+		}
+
+		var items []struct {
+			ID   int64    `xorm:"id"`
+			Tags []string `xorm:"tags"`
+		}
+		err = sess.SQL(`SELECT annotation.id, annotation.tags FROM annotation
+			INNER JOIN annotation_tag ON annotation_tag.annotation_id = annotation.id
+			WHERE annotation.org_id = ? AND annotation_tag.tag_id = ?`, cmd.OrgID, from[0].Id).Find(&items)
+		if err != nil || len(items) == 0 {
+			return err
+		}
+		if _, err := s.EnsureTagsExist(ctx, to); err != nil {
+			return err
+		}
+
+		fromTag, toTag := tag.JoinTagPairs(from)[0], tag.JoinTagPairs(to)[0]
+		for _, item := range items {
+			item.Tags = renameTerms(item.Tags, fromTag, toTag)
+			if _, err := sess.Table("annotation").Where("id = ?", item.ID).Cols("tags").Update(&item); err != nil {
+				return err
+			}
+
+			if _, err := sess.Exec(`DELETE FROM annotation_tag WHERE annotation_id = ? AND tag_id = ?`, item.ID, from[0].Id); err != nil {
+				return err
+			}
+			exists, err := sess.Table("annotation_tag").Where("annotation_id = ? AND tag_id = ?", item.ID, to[0].Id).Exist()
+			if err != nil {
+				return err
+			}
+			if !exists {
+				if _, err := sess.Exec(`INSERT INTO annotation_tag (annotation_id, tag_id) VALUES (?, ?)`, item.ID, to[0].Id); err != nil {
+					return err
+				}
+			}
+			affected++
+		}
+		return nil
+	})
+	return affected, err
+}
+
+// renameTerms replaces from by to in terms, without duplicating to when it's already there
+func renameTerms(terms []string, from, to string) []string {
+	renamed := make([]string, 0, len(terms))
+	seen := map[string]struct{}{}
+	for _, t := range terms {
+		if t == from {
+			t = to
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		renamed = append(renamed, t)
+	}
+	return renamed
+}
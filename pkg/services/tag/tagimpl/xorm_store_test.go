@@ -14,3 +14,12 @@ func TestIntegrationXormSavingTags(t *testing.T) {
 		return &sqlStore{db: ss}
 	})
 }
+
+func TestIntegrationXormTagUsageAndRename(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testIntegrationTagUsageAndRename(t, func(ss db.DB) store {
+		return &sqlStore{db: ss}
+	})
+}
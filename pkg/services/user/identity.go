@@ -35,6 +35,9 @@ type SignedInUser struct {
 	// IDToken is a signed token representing the identity that can be forwarded to plugins and external services.
 	// Will only be set when featuremgmt.FlagIdForwarding is enabled.
 	IDToken string `json:"-" xorm:"-"`
+	// ImpersonatorID and ImpersonatorLogin identify the Grafana admin that is acting as this user.
+	ImpersonatorID    int64  `json:"-" xorm:"-"`
+	ImpersonatorLogin string `json:"-" xorm:"-"`
 }
 
 func (u *SignedInUser) ShouldUpdateLastSeenAt() bool {
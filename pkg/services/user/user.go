@@ -20,6 +20,8 @@ type Service interface {
 	SetUsingOrg(context.Context, *SetUsingOrgCommand) error
 	GetSignedInUserWithCacheCtx(context.Context, *GetSignedInUserQuery) (*SignedInUser, error)
 	GetSignedInUser(context.Context, *GetSignedInUserQuery) (*SignedInUser, error)
+	// Deprecated: NewAnonymousSignedInUser only applies the anonymous settings of the configuration file,
+	// the anonymous authn client applies the per-org anonymous settings as well.
 	NewAnonymousSignedInUser(context.Context) (*SignedInUser, error)
 	Search(context.Context, *SearchUsersQuery) (*SearchUserQueryResult, error)
 	Disable(context.Context, *DisableUserCommand) error
@@ -335,6 +335,7 @@ func (s *Service) GetSignedInUser(ctx context.Context, query *user.GetSignedInUs
 	return signedInUser, err
 }
 
+// Deprecated: use the anonymous authn client, which applies the per-org anonymous settings.
 func (s *Service) NewAnonymousSignedInUser(ctx context.Context) (*user.SignedInUser, error) {
 	if !s.cfg.AnonymousEnabled {
 		return nil, fmt.Errorf("anonymous access is disabled")
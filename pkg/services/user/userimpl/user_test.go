@@ -144,14 +144,14 @@ func TestUserService(t *testing.T) {
 		t.Run("should error when anonymous access is disabled", func(t *testing.T) {
 			userService.cfg = setting.NewCfg()
 			userService.cfg.AnonymousEnabled = false
-			_, err := userService.NewAnonymousSignedInUser(context.Background())
+			_, err := userService.NewAnonymousSignedInUser(context.Background()) // nolint:staticcheck
 			require.Error(t, err)
 		})
 
 		t.Run("should return user when anonymous access is enabled and org is not set", func(t *testing.T) {
 			userService.cfg = setting.NewCfg()
 			userService.cfg.AnonymousEnabled = true
-			u, err := userService.NewAnonymousSignedInUser(context.Background())
+			u, err := userService.NewAnonymousSignedInUser(context.Background()) // nolint:staticcheck
 			require.NoError(t, err)
 			require.Equal(t, true, u.IsAnonymous)
 			require.Equal(t, int64(0), u.UserID)
@@ -165,7 +165,7 @@ func TestUserService(t *testing.T) {
 			userService.cfg.AnonymousOrgName = "anonymous"
 			userService.cfg.AnonymousOrgRole = "anonymous"
 			orgService.ExpectedOrg = &org.Org{Name: "anonymous", ID: 123}
-			u, err := userService.NewAnonymousSignedInUser(context.Background())
+			u, err := userService.NewAnonymousSignedInUser(context.Background()) // nolint:staticcheck
 			require.NoError(t, err)
 			require.Equal(t, true, u.IsAnonymous)
 			require.Equal(t, int64(0), u.UserID)
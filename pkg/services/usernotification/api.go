@@ -0,0 +1,109 @@
+package usernotification
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *UserNotificationService) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Group("/api/user/notifications", func(r routing.RouteRegister) {
+		r.Get("/", routing.Wrap(s.searchHandler))
+		r.Post("/read", routing.Wrap(s.markAllAsReadHandler))
+		r.Post("/:id/read", routing.Wrap(s.markAsReadHandler))
+	}, middleware.ReqSignedInNoAnonymous)
+}
+
+// swagger:route GET /user/notifications signed_in_user getUserNotifications
+//
+// Get the notifications of the signed in user, most recent first.
+//
+// Responses:
+// 200: getUserNotificationsResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *UserNotificationService) searchHandler(c *contextmodel.ReqContext) response.Response {
+	result, err := s.Search(c.Req.Context(), &SearchNotificationsQuery{
+		OrgID:      c.SignedInUser.GetOrgID(),
+		UserID:     c.SignedInUser.UserID,
+		OnlyUnread: c.QueryBool("unread"),
+		Limit:      c.QueryInt("limit"),
+		Page:       c.QueryInt("page"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get notifications", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /user/notifications/{notification_id}/read signed_in_user markUserNotificationAsRead
+//
+// Mark a notification of the signed in user as read.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *UserNotificationService) markAsReadHandler(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+	if err := s.MarkAsRead(c.Req.Context(), &MarkAsReadCommand{OrgID: c.SignedInUser.GetOrgID(), UserID: c.SignedInUser.UserID, ID: id}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to mark notification as read", err)
+	}
+	return response.Success("Notification marked as read")
+}
+
+// swagger:route POST /user/notifications/read signed_in_user markAllUserNotificationsAsRead
+//
+// Mark all the notifications of the signed in user as read.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *UserNotificationService) markAllAsReadHandler(c *contextmodel.ReqContext) response.Response {
+	if err := s.MarkAsRead(c.Req.Context(), &MarkAsReadCommand{OrgID: c.SignedInUser.GetOrgID(), UserID: c.SignedInUser.UserID}); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to mark notifications as read", err)
+	}
+	return response.Success("Notifications marked as read")
+}
+
+// swagger:parameters getUserNotifications
+type GetUserNotificationsParams struct {
+	// Only return the notifications that haven't been read
+	// in:query
+	// required:false
+	Unread bool `json:"unread"`
+	// Maximum number of notifications to return
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+	// Page index, starting at 1
+	// in:query
+	// required:false
+	// default:1
+	Page int `json:"page"`
+}
+
+// swagger:parameters markUserNotificationAsRead
+type MarkUserNotificationAsReadParams struct {
+	// in:path
+	// required:true
+	NotificationID int64 `json:"notification_id"`
+}
+
+// swagger:response getUserNotificationsResponse
+type GetUserNotificationsResponse struct {
+	// in:body
+	Body []*Notification `json:"body"`
+}
@@ -0,0 +1,52 @@
+package usernotification
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var ErrNotificationNotFound = errutil.NotFound("usernotification.not-found", errutil.WithPublicMessage("Notification not found"))
+
+// Notification is a message shown to a user in Grafana, e.g. about a change of the permissions
+// of a dashboard they own or an unusual sign-in to their account.
+type Notification struct {
+	ID     int64 `json:"id" xorm:"pk autoincr 'id'"`
+	OrgID  int64 `json:"orgId" xorm:"org_id"`
+	UserID int64 `json:"userId" xorm:"user_id"`
+	// Kind identifies the source of the notification, e.g. "permissions.changed"
+	Kind    string    `json:"kind"`
+	Title   string    `json:"title"`
+	Text    string    `json:"text"`
+	URL     string    `json:"url" xorm:"url"`
+	Read    bool      `json:"read" xorm:"is_read"`
+	Created time.Time `json:"created"`
+}
+
+func (Notification) TableName() string {
+	return "user_notification"
+}
+
+type CreateNotificationCommand struct {
+	OrgID  int64
+	UserID int64
+	Kind   string
+	Title  string
+	Text   string
+	URL    string
+}
+
+type SearchNotificationsQuery struct {
+	OrgID      int64
+	UserID     int64
+	OnlyUnread bool
+	Limit      int
+	Page       int
+}
+
+type MarkAsReadCommand struct {
+	OrgID  int64
+	UserID int64
+	// ID of the notification to mark as read, all the notifications of the user are marked as read if 0
+	ID int64
+}
@@ -0,0 +1,110 @@
+package usernotification
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// Service stores the notifications shown to the users in Grafana.
+type Service interface {
+	Create(ctx context.Context, cmd *CreateNotificationCommand) error
+	Search(ctx context.Context, query *SearchNotificationsQuery) ([]*Notification, error)
+	MarkAsRead(ctx context.Context, cmd *MarkAsReadCommand) error
+	// DeleteExpired deletes the notifications older than the retention and returns how many were deleted
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// Retention is how long the notifications are kept
+const Retention = 30 * 24 * time.Hour
+
+type UserNotificationService struct {
+	store db.DB
+	log   log.Logger
+	now   func() time.Time
+}
+
+var _ Service = (*UserNotificationService)(nil)
+
+func ProvideService(sqlStore db.DB, routeRegister routing.RouteRegister) *UserNotificationService {
+	s := &UserNotificationService{
+		store: sqlStore,
+		log:   log.New("user-notification"),
+		now:   time.Now,
+	}
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+func (s *UserNotificationService) Create(ctx context.Context, cmd *CreateNotificationCommand) error {
+	return s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(&Notification{
+			OrgID:   cmd.OrgID,
+			UserID:  cmd.UserID,
+			Kind:    cmd.Kind,
+			Title:   cmd.Title,
+			Text:    cmd.Text,
+			URL:     cmd.URL,
+			Created: s.now(),
+		})
+		return err
+	})
+}
+
+func (s *UserNotificationService) Search(ctx context.Context, query *SearchNotificationsQuery) ([]*Notification, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	offset := 0
+	if query.Page > 1 {
+		offset = (query.Page - 1) * limit
+	}
+
+	result := make([]*Notification, 0)
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id = ? AND user_id = ?", query.OrgID, query.UserID)
+		if query.OnlyUnread {
+			sess.And("is_read = ?", s.store.GetDialect().BooleanStr(false))
+		}
+		return sess.Desc("created", "id").Limit(limit, offset).Find(&result)
+	})
+	return result, err
+}
+
+func (s *UserNotificationService) MarkAsRead(ctx context.Context, cmd *MarkAsReadCommand) error {
+	return s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		if cmd.ID != 0 {
+			exists, err := sess.Where("id = ? AND org_id = ? AND user_id = ?", cmd.ID, cmd.OrgID, cmd.UserID).Exist(&Notification{})
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return ErrNotificationNotFound.Errorf("notification %d not found", cmd.ID)
+			}
+		}
+
+		sess.Table("user_notification").Where("org_id = ? AND user_id = ?", cmd.OrgID, cmd.UserID)
+		if cmd.ID != 0 {
+			sess.And("id = ?", cmd.ID)
+		}
+		_, err := sess.Cols("is_read").Update(&Notification{Read: true})
+		return err
+	})
+}
+
+func (s *UserNotificationService) DeleteExpired(ctx context.Context) (int64, error) {
+	var affected int64
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM user_notification WHERE created < ?", s.now().Add(-Retention))
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
@@ -0,0 +1,85 @@
+package usernotification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationUserNotifications(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	s := ProvideService(db.InitTestDB(t), routing.NewRouteRegister())
+	start := time.Now().Truncate(time.Second)
+	now := start
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, s.Create(ctx, &CreateNotificationCommand{OrgID: 1, UserID: 1, Kind: "test", Title: "first"}))
+	now = start.Add(time.Hour)
+	require.NoError(t, s.Create(ctx, &CreateNotificationCommand{OrgID: 1, UserID: 1, Kind: "test", Title: "second"}))
+	require.NoError(t, s.Create(ctx, &CreateNotificationCommand{OrgID: 1, UserID: 2, Kind: "test", Title: "other user"}))
+
+	t.Run("should return the notifications of the user most recent first", func(t *testing.T) {
+		result, err := s.Search(ctx, &SearchNotificationsQuery{OrgID: 1, UserID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "second", result[0].Title)
+		assert.Equal(t, "first", result[1].Title)
+
+		result, err = s.Search(ctx, &SearchNotificationsQuery{OrgID: 1, UserID: 1, Limit: 1, Page: 2})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "first", result[0].Title)
+	})
+
+	t.Run("should mark the notifications as read", func(t *testing.T) {
+		result, err := s.Search(ctx, &SearchNotificationsQuery{OrgID: 1, UserID: 1, OnlyUnread: true})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		require.NoError(t, s.MarkAsRead(ctx, &MarkAsReadCommand{OrgID: 1, UserID: 1, ID: result[0].ID}))
+		unread, err := s.Search(ctx, &SearchNotificationsQuery{OrgID: 1, UserID: 1, OnlyUnread: true})
+		require.NoError(t, err)
+		require.Len(t, unread, 1)
+		assert.Equal(t, result[1].ID, unread[0].ID)
+
+		// the notifications of other users can't be marked as read
+		err = s.MarkAsRead(ctx, &MarkAsReadCommand{OrgID: 1, UserID: 2, ID: unread[0].ID})
+		assert.ErrorIs(t, err, ErrNotificationNotFound)
+
+		require.NoError(t, s.MarkAsRead(ctx, &MarkAsReadCommand{OrgID: 1, UserID: 1}))
+		unread, err = s.Search(ctx, &SearchNotificationsQuery{OrgID: 1, UserID: 1, OnlyUnread: true})
+		require.NoError(t, err)
+		assert.Empty(t, unread)
+
+		unread, err = s.Search(ctx, &SearchNotificationsQuery{OrgID: 1, UserID: 2, OnlyUnread: true})
+		require.NoError(t, err)
+		assert.Len(t, unread, 1)
+	})
+
+	t.Run("should delete the notifications older than the retention", func(t *testing.T) {
+		now = start.Add(Retention + 30*time.Minute)
+		deleted, err := s.DeleteExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		result, err := s.Search(ctx, &SearchNotificationsQuery{OrgID: 1, UserID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "second", result[0].Title)
+	})
+}
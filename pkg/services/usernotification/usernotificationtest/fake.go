@@ -0,0 +1,32 @@
+package usernotificationtest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/usernotification"
+)
+
+type FakeService struct {
+	Created       []*usernotification.CreateNotificationCommand
+	ExpectedList  []*usernotification.Notification
+	ExpectedError error
+}
+
+var _ usernotification.Service = (*FakeService)(nil)
+
+func (f *FakeService) Create(ctx context.Context, cmd *usernotification.CreateNotificationCommand) error {
+	f.Created = append(f.Created, cmd)
+	return f.ExpectedError
+}
+
+func (f *FakeService) Search(ctx context.Context, query *usernotification.SearchNotificationsQuery) ([]*usernotification.Notification, error) {
+	return f.ExpectedList, f.ExpectedError
+}
+
+func (f *FakeService) MarkAsRead(ctx context.Context, cmd *usernotification.MarkAsReadCommand) error {
+	return f.ExpectedError
+}
+
+func (f *FakeService) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, f.ExpectedError
+}
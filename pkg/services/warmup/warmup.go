@@ -0,0 +1,223 @@
+package warmup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type State string
+
+const (
+	StatePending State = "pending"
+	StateRunning State = "running"
+	StateDone    State = "done"
+	StateFailed  State = "failed"
+)
+
+// TaskStatus is the progress of a warm-up task
+type TaskStatus struct {
+	Name  string `json:"name"`
+	State State  `json:"state"`
+	// Done and Total count the steps of the task, for example the plugin sources, when known
+	Done     int        `json:"done"`
+	Total    int        `json:"total"`
+	Error    string     `json:"error,omitempty"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+}
+
+// Status is the progress of the warm-up of the instance
+type Status struct {
+	// Ready is true once all the tasks are done
+	Ready bool         `json:"ready"`
+	Tasks []TaskStatus `json:"tasks"`
+}
+
+// Task reports the progress of a warm-up task. The methods of a nil Task do nothing.
+type Task struct {
+	s      *Service
+	status TaskStatus
+}
+
+type step struct {
+	task *Task
+	fn   func(ctx context.Context, task *Task) error
+}
+
+// Service runs the expensive startup steps, such as the plugin loading and the provisioning, and tracks the
+// progress of the tasks that prevent the instance from serving traffic normally, such as the initial search index.
+// When deferred_warmup is enabled the steps run after the HTTP server has started, otherwise they run while
+// Grafana starts.
+type Service struct {
+	cfg *setting.Cfg
+	log log.Logger
+
+	mu    sync.Mutex
+	tasks []*Task
+	steps []step
+	// done is closed once the steps are done
+	done chan struct{}
+	err  error
+}
+
+func ProvideService(cfg *setting.Cfg) *Service {
+	s := &Service{
+		cfg:  cfg,
+		log:  log.New("warmup"),
+		done: make(chan struct{}),
+	}
+	if !cfg.DeferredWarmup {
+		close(s.done)
+	}
+	return s
+}
+
+// IsDisabled returns true when the steps run while Grafana starts
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.DeferredWarmup
+}
+
+// Run runs the steps in the order they were registered, Grafana stops when one of them fails
+func (s *Service) Run(ctx context.Context) error {
+	s.mu.Lock()
+	steps := s.steps
+	s.steps = nil
+	s.mu.Unlock()
+
+	start := time.Now()
+	s.log.Info("Warming up", "tasks", len(steps))
+	err := s.runSteps(ctx, steps)
+
+	s.mu.Lock()
+	s.err = err
+	close(s.done)
+	s.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	s.log.Info("Warm-up complete", "duration", time.Since(start))
+	return nil
+}
+
+func (s *Service) runSteps(ctx context.Context, steps []step) error {
+	for _, st := range steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		st.task.start()
+		err := st.fn(ctx, st.task)
+		st.task.Finish(err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Defer registers an expensive startup step. With deferred_warmup the step runs after the HTTP server has
+// started, otherwise it runs right away and its error is returned.
+func (s *Service) Defer(ctx context.Context, name string, fn func(ctx context.Context, task *Task) error) error {
+	task := s.Track(name)
+	if !s.cfg.DeferredWarmup {
+		return s.runSteps(ctx, []step{{task: task, fn: fn}})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.steps = append(s.steps, step{task: task, fn: fn})
+	return nil
+}
+
+// Track registers a task running outside of the warm-up steps, the instance isn't ready until it's finished
+func (s *Service) Track(name string) *Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task := &Task{s: s, status: TaskStatus{Name: name, State: StatePending}}
+	s.tasks = append(s.tasks, task)
+	return task
+}
+
+// Wait blocks until the warm-up steps are done and returns the error of the failed step, if any
+func (s *Service) Wait(ctx context.Context) error {
+	select {
+	case <-s.done:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Status returns the progress of the tasks
+func (s *Service) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := Status{Ready: true, Tasks: make([]TaskStatus, 0, len(s.tasks))}
+	for _, task := range s.tasks {
+		if task.status.State != StateDone {
+			status.Ready = false
+		}
+		status.Tasks = append(status.Tasks, task.status)
+	}
+	return status
+}
+
+func (t *Task) start() {
+	if t == nil {
+		return
+	}
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+
+	now := time.Now()
+	t.status.State = StateRunning
+	t.status.Started = &now
+}
+
+// SetProgress sets the number of steps of the task that are done, out of total
+func (t *Task) SetProgress(done, total int) {
+	if t == nil {
+		return
+	}
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+
+	if t.status.State == StatePending {
+		now := time.Now()
+		t.status.State = StateRunning
+		t.status.Started = &now
+	}
+	t.status.Done, t.status.Total = done, total
+}
+
+// Finish marks the task as done, or as failed when err isn't nil
+func (t *Task) Finish(err error) {
+	if t == nil {
+		return
+	}
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+
+	now := time.Now()
+	if t.status.Started == nil {
+		t.status.Started = &now
+	}
+	t.status.Finished = &now
+	if err != nil {
+		t.status.State = StateFailed
+		t.status.Error = err.Error()
+		return
+	}
+	t.status.State = StateDone
+	if t.status.Total > 0 {
+		t.status.Done = t.status.Total
+	}
+}
@@ -0,0 +1,91 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should run the steps right away when not deferred", func(t *testing.T) {
+		s := ProvideService(setting.NewCfg())
+		ran := false
+		err := s.Defer(ctx, "plugins", func(ctx context.Context, task *Task) error {
+			ran = true
+			return nil
+		})
+		require.NoError(t, err)
+		assert.True(t, ran)
+		assert.True(t, s.IsDisabled())
+		require.NoError(t, s.Wait(ctx))
+		assert.True(t, s.Status().Ready)
+	})
+
+	t.Run("should run the steps in order in Run when deferred", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DeferredWarmup = true
+		s := ProvideService(cfg)
+
+		var ran []string
+		for _, name := range []string{"plugins", "provisioning"} {
+			name := name
+			require.NoError(t, s.Defer(ctx, name, func(ctx context.Context, task *Task) error {
+				task.SetProgress(0, 2)
+				ran = append(ran, name)
+				return nil
+			}))
+		}
+		tracked := s.Track("search_index")
+
+		assert.Empty(t, ran)
+		status := s.Status()
+		assert.False(t, status.Ready)
+		assert.Equal(t, StatePending, status.Tasks[0].State)
+
+		require.NoError(t, s.Run(ctx))
+		require.NoError(t, s.Wait(ctx))
+		assert.Equal(t, []string{"plugins", "provisioning"}, ran)
+
+		status = s.Status()
+		assert.False(t, status.Ready)
+		assert.Equal(t, StateDone, status.Tasks[0].State)
+		assert.Equal(t, 2, status.Tasks[0].Done)
+
+		tracked.Finish(nil)
+		assert.True(t, s.Status().Ready)
+	})
+
+	t.Run("should stop at the first failed step", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DeferredWarmup = true
+		s := ProvideService(cfg)
+
+		failure := errors.New("failure")
+		require.NoError(t, s.Defer(ctx, "plugins", func(ctx context.Context, task *Task) error { return failure }))
+		require.NoError(t, s.Defer(ctx, "provisioning", func(ctx context.Context, task *Task) error {
+			t.Fatal("should not run")
+			return nil
+		}))
+
+		require.ErrorIs(t, s.Run(ctx), failure)
+		require.ErrorIs(t, s.Wait(ctx), failure)
+
+		status := s.Status()
+		assert.Equal(t, StateFailed, status.Tasks[0].State)
+		assert.Equal(t, "failure", status.Tasks[0].Error)
+		assert.Equal(t, StatePending, status.Tasks[1].State)
+	})
+
+	t.Run("should ignore the progress of nil tasks", func(t *testing.T) {
+		var task *Task
+		task.SetProgress(1, 2)
+		task.Finish(nil)
+	})
+}
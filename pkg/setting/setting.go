@@ -112,6 +112,13 @@ type Cfg struct {
 	EnableGzip       bool
 	EnforceDomain    bool
 	MinTLSVersion    string
+	// DeferredWarmup loads the plugins and runs the provisioning after the HTTP server has started
+	DeferredWarmup bool
+
+	// HTTP compression settings, only used when EnableGzip is set
+	CompressionAlgorithms    []string
+	CompressionMinSize       int
+	CompressionExcludedPaths []string
 
 	// Security settings
 	SecretKey             string
@@ -147,11 +154,27 @@ type Cfg struct {
 	RendererUrl                    string
 	RendererCallbackUrl            string
 	RendererAuthToken              string
+	RendererCACert                 string
+	RendererClientCert             string
+	RendererClientKey              string
 	RendererConcurrentRequestLimit int
 	RendererRenderKeyLifeTime      time.Duration
-	RendererDefaultImageWidth      int
-	RendererDefaultImageHeight     int
-	RendererDefaultImageScale      float64
+	// RendererSigningKeyRotationInterval is how often the key signing the JWT render keys is rotated
+	RendererSigningKeyRotationInterval time.Duration
+	RendererDefaultImageWidth          int
+	RendererDefaultImageHeight         int
+	RendererDefaultImageScale          float64
+	RendererMaxImageWidth              int
+	RendererMaxImageHeight             int
+	RendererMaxImageScale              float64
+	RendererResizeCacheTTL             time.Duration
+	RendererDrainTimeout               time.Duration
+	// RendererQueueConcurrency enables the render queue with a total concurrency, 0 disables it
+	RendererQueueConcurrency            int
+	RendererQueueAlertingConcurrency    int
+	RendererQueueInteractiveConcurrency int
+	RendererQueueBackgroundConcurrency  int
+	RendererQueueMaxWaiting             int
 
 	// Security
 	DisableInitAdminCreation          bool
@@ -178,8 +201,14 @@ type Cfg struct {
 	DisableFrontendSandboxForPlugins []string
 	DisableGravatar                  bool
 	DataProxyWhiteList               map[string]bool
+	// EgressAllowedHosts and EgressDeniedHosts are the host patterns of the webhooks and notifiers
+	// Grafana is allowed or denied to send requests to.
+	EgressAllowedHosts []string
+	EgressDeniedHosts  []string
 
 	TempDataLifetime time.Duration
+	// RenderedImagesLifetime is the retention of the rendered images served from /public/img/attachments
+	RenderedImagesLifetime time.Duration
 
 	// Plugins
 	PluginsEnableAlpha               bool
@@ -219,6 +248,20 @@ type Cfg struct {
 	DashboardVersionsToKeep  int
 	MinRefreshInterval       string
 	DefaultHomeDashboardPath string
+	// SensitiveDashboardPaths are the paths in the dashboard JSON that are encrypted at rest
+	SensitiveDashboardPaths []string
+	// DashboardMaxJSONSize is the maximum size in bytes of the JSON of a saved dashboard, 0 means unlimited
+	DashboardMaxJSONSize int64
+	// DashboardMaxPanels is the maximum number of panels of a saved dashboard, rows included, 0 means unlimited
+	DashboardMaxPanels int
+	// DashboardMaxPayloadSize is the maximum size in bytes of the request body of the dashboard save API, 0 means unlimited
+	DashboardMaxPayloadSize int64
+	// DashboardExportRate is the maximum number of dashboards per second of each export stream, 0 means unlimited
+	DashboardExportRate int
+	// DashboardChangeFeedRetention is how long the changes of the change feed of the dashboards are kept
+	DashboardChangeFeedRetention time.Duration
+	// DashboardBulkDeleteUndoWindow is how long the dashboards deleted by a bulk deletion can be restored
+	DashboardBulkDeleteUndoWindow time.Duration
 
 	// Auth
 	LoginCookieName               string
@@ -240,6 +283,12 @@ type Cfg struct {
 	IDResponseHeaderEnabled       bool
 	IDResponseHeaderPrefix        string
 	IDResponseHeaderNamespaces    map[string]struct{}
+	ImpersonationEnabled          bool
+	ImpersonationMaxDuration      time.Duration
+	ShareLinksEnabled             bool
+	ShareLinkMaxDuration          time.Duration
+	ClientCredentialsEnabled      bool
+	ClientCredentialsTokenTTL     time.Duration
 	// Not documented & not supported
 	// stand in until a more complete solution is implemented
 	AuthConfigUIAdminAccess bool
@@ -313,6 +362,8 @@ type Cfg struct {
 	AnonymousOrgRole     string
 	AnonymousHideVersion bool
 	AnonymousDeviceLimit int64
+	// AnonymousAllowOrgOverride allows the anonymous access settings to be changed per organization
+	AnonymousAllowOrgOverride bool
 
 	DateFormats DateFormats
 
@@ -423,6 +474,10 @@ type Cfg struct {
 	ExpressionsEnabled bool
 
 	ImageUploadProvider string
+	// ImageUploadLocalSignedURLs signs the URLs of the images stored by the local provider, the signed URLs
+	// expire after ImageUploadLocalSignedURLExpiration
+	ImageUploadLocalSignedURLs          bool
+	ImageUploadLocalSignedURLExpiration time.Duration
 
 	// LiveMaxConnections is a maximum number of WebSocket connections to
 	// Grafana Live ws endpoint (per Grafana server instance). 0 disables
@@ -459,6 +514,12 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	OrgLifecycle OrgLifecycleSettings
+
+	LoginAnomaly LoginAnomalySettings
+
+	ConcurrencyLimits ConcurrencyLimitsSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -500,6 +561,17 @@ type Cfg struct {
 
 	// Public dashboards
 	PublicDashboardsEnabled bool
+	// PublicDashboardsQueryCacheInterval is how long the query results of the public dashboards are served from
+	// the cache before they're queried again, 0 disables the cache
+	PublicDashboardsQueryCacheInterval   time.Duration
+	PublicDashboardsQueryCacheMaxEntries int
+	// PublicDashboardsQueryRateLimit is the number of queries per minute of each public dashboard, 0 for no limit
+	PublicDashboardsQueryRateLimit int64
+	// PublicDashboardsMaxConcurrentViewers is the number of viewers of each public dashboard, 0 for no limit
+	PublicDashboardsMaxConcurrentViewers int64
+
+	// Permission change notifications
+	PermissionChangeNotifications PermissionChangeNotificationsSettings
 
 	// Feature Management Settings
 	FeatureManagement FeatureMgmtSettings
@@ -709,9 +781,20 @@ func (cfg *Cfg) readAnnotationSettings() error {
 			maxAge = 0
 		}
 
+		rollupAfter, err := gtime.ParseDuration(section.Key("rollup_after").MustString(""))
+		if err != nil {
+			rollupAfter = 0
+		}
+		rollupMaxAge, err := gtime.ParseDuration(section.Key("rollup_max_age").MustString(""))
+		if err != nil {
+			rollupMaxAge = 0
+		}
+
 		return AnnotationCleanupSettings{
-			MaxAge:   maxAge,
-			MaxCount: section.Key("max_annotations_to_keep").MustInt64(0),
+			MaxAge:       maxAge,
+			MaxCount:     section.Key("max_annotations_to_keep").MustInt64(0),
+			RollupAfter:  rollupAfter,
+			RollupMaxAge: rollupMaxAge,
 		}
 	}
 
@@ -741,6 +824,10 @@ func (cfg *Cfg) readExpressionsSettings() {
 type AnnotationCleanupSettings struct {
 	MaxAge   time.Duration
 	MaxCount int64
+	// RollupAfter is the age after which annotations are replaced by daily rollups, 0 disables the rollups
+	RollupAfter time.Duration
+	// RollupMaxAge is the age after which rollups are deleted, 0 keeps them
+	RollupMaxAge time.Duration
 }
 
 func EnvKey(sectionName string, keyName string) string {
@@ -1097,6 +1184,21 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	cfg.DashboardVersionsToKeep = dashboards.Key("versions_to_keep").MustInt(20)
 	cfg.MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	cfg.SensitiveDashboardPaths = util.SplitString(dashboards.Key("sensitive_json_paths").MustString(""))
+	cfg.DashboardMaxJSONSize = dashboards.Key("max_json_size").MustInt64(52428800)
+	cfg.DashboardMaxPanels = dashboards.Key("max_panels").MustInt(0)
+	cfg.DashboardMaxPayloadSize = dashboards.Key("max_payload_size").MustInt64(104857600)
+	cfg.DashboardExportRate = dashboards.Key("export_rate_limit").MustInt(500)
+	changeFeedRetention, retentionErr := gtime.ParseDuration(valueAsString(dashboards, "change_feed_retention", "7d"))
+	if retentionErr != nil {
+		return fmt.Errorf("invalid dashboards change_feed_retention: %w", retentionErr)
+	}
+	cfg.DashboardChangeFeedRetention = changeFeedRetention
+	undoWindow, undoWindowErr := gtime.ParseDuration(valueAsString(dashboards, "bulk_delete_undo_window", "1d"))
+	if undoWindowErr != nil {
+		return fmt.Errorf("invalid dashboards bulk_delete_undo_window: %w", undoWindowErr)
+	}
+	cfg.DashboardBulkDeleteUndoWindow = undoWindow
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
@@ -1116,6 +1218,7 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	}
 
 	cfg.TempDataLifetime = iniFile.Section("paths").Key("temp_data_lifetime").MustDuration(time.Second * 3600 * 24)
+	cfg.RenderedImagesLifetime = iniFile.Section("paths").Key("rendered_images_lifetime").MustDuration(cfg.TempDataLifetime)
 	cfg.MetricsEndpointEnabled = iniFile.Section("metrics").Key("enabled").MustBool(true)
 	cfg.MetricsEndpointBasicAuthUsername = valueAsString(iniFile.Section("metrics"), "basic_auth_username", "")
 	cfg.MetricsEndpointBasicAuthPassword = valueAsString(iniFile.Section("metrics"), "basic_auth_password", "")
@@ -1215,6 +1318,9 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 
 	cfg.Storage = readStorageSettings(iniFile)
 	cfg.Search = readSearchSettings(iniFile)
+	cfg.OrgLifecycle = readOrgLifecycleSettings(iniFile)
+	cfg.LoginAnomaly = readLoginAnomalySettings(iniFile)
+	cfg.ConcurrencyLimits = readConcurrencyLimitsSettings(iniFile)
 
 	var err error
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
@@ -1239,6 +1345,9 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 
 	imageUploadingSection := iniFile.Section("external_image_storage")
 	cfg.ImageUploadProvider = valueAsString(imageUploadingSection, "provider", "")
+	localImageUploadingSection := iniFile.Section("external_image_storage.local")
+	cfg.ImageUploadLocalSignedURLs = localImageUploadingSection.Key("enable_signed_urls").MustBool(false)
+	cfg.ImageUploadLocalSignedURLExpiration = localImageUploadingSection.Key("signed_url_expiration").MustDuration(7 * 24 * time.Hour)
 
 	enterprise := iniFile.Section("enterprise")
 	cfg.EnterpriseLicensePath = valueAsString(enterprise, "license_path", filepath.Join(cfg.DataPath, "license.jwt"))
@@ -1284,6 +1393,7 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 
 	cfg.readFeatureManagementConfig()
 	cfg.readPublicDashboardsSettings()
+	cfg.PermissionChangeNotifications = readPermissionChangeNotificationsSettings(iniFile)
 
 	return nil
 }
@@ -1519,6 +1629,8 @@ func readSecuritySettings(iniFile *ini.File, cfg *Cfg) error {
 	for _, hostAndIP := range util.SplitString(securityStr) {
 		cfg.DataProxyWhiteList[hostAndIP] = true
 	}
+	cfg.EgressAllowedHosts = util.SplitString(valueAsString(security, "egress_allowed_hosts", ""))
+	cfg.EgressDeniedHosts = util.SplitString(valueAsString(security, "egress_denied_hosts", ""))
 
 	// admin
 	cfg.DisableInitAdminCreation = security.Key("disable_initial_admin_creation").MustBool(false)
@@ -1595,6 +1707,16 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 		cfg.IDResponseHeaderNamespaces[namespace] = struct{}{}
 	}
 
+	// Impersonation
+	cfg.ImpersonationEnabled = auth.Key("impersonation_enabled").MustBool(false)
+	cfg.ImpersonationMaxDuration = auth.Key("impersonation_max_duration").MustDuration(time.Hour)
+
+	// Share links
+	cfg.ShareLinksEnabled = auth.Key("share_links_enabled").MustBool(false)
+	cfg.ShareLinkMaxDuration = auth.Key("share_link_max_duration").MustDuration(7 * 24 * time.Hour)
+	cfg.ClientCredentialsEnabled = auth.Key("client_credentials_enabled").MustBool(false)
+	cfg.ClientCredentialsTokenTTL = auth.Key("client_credentials_token_ttl").MustDuration(time.Hour)
+
 	// anonymous access
 	anonSection := iniFile.Section("auth.anonymous")
 	cfg.AnonymousEnabled = anonSection.Key("enabled").MustBool(false)
@@ -1602,6 +1724,7 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	cfg.AnonymousOrgRole = valueAsString(anonSection, "org_role", "")
 	cfg.AnonymousHideVersion = anonSection.Key("hide_version").MustBool(false)
 	cfg.AnonymousDeviceLimit = anonSection.Key("device_limit").MustInt64(0)
+	cfg.AnonymousAllowOrgOverride = anonSection.Key("allow_org_override").MustBool(false)
 
 	// basic auth
 	authBasic := iniFile.Section("auth.basic")
@@ -1735,6 +1858,9 @@ func (cfg *Cfg) readRenderingSettings(iniFile *ini.File) error {
 	cfg.RendererUrl = valueAsString(renderSec, "server_url", "")
 	cfg.RendererCallbackUrl = valueAsString(renderSec, "callback_url", "")
 	cfg.RendererAuthToken = valueAsString(renderSec, "renderer_token", "-")
+	cfg.RendererCACert = valueAsString(renderSec, "renderer_ca_cert", "")
+	cfg.RendererClientCert = valueAsString(renderSec, "renderer_client_cert", "")
+	cfg.RendererClientKey = valueAsString(renderSec, "renderer_client_key", "")
 
 	if cfg.RendererCallbackUrl == "" {
 		cfg.RendererCallbackUrl = AppUrl
@@ -1752,9 +1878,20 @@ func (cfg *Cfg) readRenderingSettings(iniFile *ini.File) error {
 
 	cfg.RendererConcurrentRequestLimit = renderSec.Key("concurrent_render_request_limit").MustInt(30)
 	cfg.RendererRenderKeyLifeTime = renderSec.Key("render_key_lifetime").MustDuration(5 * time.Minute)
+	cfg.RendererSigningKeyRotationInterval = renderSec.Key("signing_key_rotation_interval").MustDuration(24 * time.Hour)
 	cfg.RendererDefaultImageWidth = renderSec.Key("default_image_width").MustInt(1000)
 	cfg.RendererDefaultImageHeight = renderSec.Key("default_image_height").MustInt(500)
 	cfg.RendererDefaultImageScale = renderSec.Key("default_image_scale").MustFloat64(1)
+	cfg.RendererMaxImageWidth = renderSec.Key("max_image_width").MustInt(0)
+	cfg.RendererMaxImageHeight = renderSec.Key("max_image_height").MustInt(0)
+	cfg.RendererMaxImageScale = renderSec.Key("max_image_scale").MustFloat64(0)
+	cfg.RendererResizeCacheTTL = renderSec.Key("resize_cache_ttl").MustDuration(0)
+	cfg.RendererDrainTimeout = renderSec.Key("drain_timeout").MustDuration(30 * time.Second)
+	cfg.RendererQueueConcurrency = renderSec.Key("queue_concurrency").MustInt(0)
+	cfg.RendererQueueAlertingConcurrency = renderSec.Key("queue_alerting_concurrency").MustInt(0)
+	cfg.RendererQueueInteractiveConcurrency = renderSec.Key("queue_interactive_concurrency").MustInt(0)
+	cfg.RendererQueueBackgroundConcurrency = renderSec.Key("queue_background_concurrency").MustInt(0)
+	cfg.RendererQueueMaxWaiting = renderSec.Key("queue_max_waiting").MustInt(100)
 	cfg.ImagesDir = filepath.Join(cfg.DataPath, "png")
 	cfg.CSVsDir = filepath.Join(cfg.DataPath, "csv")
 	cfg.PDFsDir = filepath.Join(cfg.DataPath, "pdf")
@@ -1911,6 +2048,14 @@ func (cfg *Cfg) readServerSettings(iniFile *ini.File) error {
 	cfg.RouterLogging = server.Key("router_logging").MustBool(false)
 
 	cfg.EnableGzip = server.Key("enable_gzip").MustBool(false)
+	cfg.CompressionAlgorithms = util.SplitString(server.Key("compression_algorithms").MustString("gzip"))
+	for _, algorithm := range cfg.CompressionAlgorithms {
+		if algorithm != "gzip" && algorithm != "br" && algorithm != "zstd" {
+			return fmt.Errorf("invalid compression algorithm %q, allowed values are gzip, br and zstd", algorithm)
+		}
+	}
+	cfg.CompressionMinSize = server.Key("compression_min_size").MustInt(0)
+	cfg.CompressionExcludedPaths = util.SplitString(server.Key("compression_excluded_paths").MustString(""))
 	cfg.EnforceDomain = server.Key("enforce_domain").MustBool(false)
 	staticRoot := valueAsString(server, "static_root_path", "")
 	cfg.StaticRootPath = makeAbsolute(staticRoot, cfg.HomePath)
@@ -1928,6 +2073,7 @@ func (cfg *Cfg) readServerSettings(iniFile *ini.File) error {
 	}
 
 	cfg.ReadTimeout = server.Key("read_timeout").MustDuration(0)
+	cfg.DeferredWarmup = server.Key("deferred_warmup").MustBool(false)
 
 	headersSection := cfg.Raw.Section("server.custom_response_headers")
 	keys := headersSection.Keys()
@@ -2031,4 +2177,8 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 func (cfg *Cfg) readPublicDashboardsSettings() {
 	publicDashboards := cfg.Raw.Section("public_dashboards")
 	cfg.PublicDashboardsEnabled = publicDashboards.Key("enabled").MustBool(true)
+	cfg.PublicDashboardsQueryCacheInterval = publicDashboards.Key("query_cache_interval").MustDuration(0)
+	cfg.PublicDashboardsQueryCacheMaxEntries = publicDashboards.Key("query_cache_max_entries").MustInt(1000)
+	cfg.PublicDashboardsQueryRateLimit = publicDashboards.Key("query_rate_limit").MustInt64(0)
+	cfg.PublicDashboardsMaxConcurrentViewers = publicDashboards.Key("max_concurrent_viewers").MustInt64(0)
 }
@@ -0,0 +1,26 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// ConcurrencyLimitsSettings are the default concurrency limits of the subsystems,
+// which can be changed at runtime through the admin API. 0 means unlimited.
+type ConcurrencyLimitsSettings struct {
+	DataSourceProxy int
+	AlertEvaluation int
+	// ReloadInterval is how often the limits changed by other instances are loaded from the database
+	ReloadInterval time.Duration
+}
+
+func readConcurrencyLimitsSettings(iniFile *ini.File) ConcurrencyLimitsSettings {
+	s := ConcurrencyLimitsSettings{}
+
+	section := iniFile.Section("concurrency_limits")
+	s.DataSourceProxy = section.Key("datasource_proxy").MustInt(0)
+	s.AlertEvaluation = section.Key("alert_evaluation").MustInt(0)
+	s.ReloadInterval = section.Key("reload_interval").MustDuration(30 * time.Second)
+	return s
+}
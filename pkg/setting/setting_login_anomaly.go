@@ -0,0 +1,32 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// LoginAnomalySettings configures the detection of unusual sign-ins
+type LoginAnomalySettings struct {
+	Enabled bool
+	// DefaultSensitivity is used by organizations that haven't configured their own
+	DefaultSensitivity string
+	WebhookURL         string
+	WebhookTimeout     time.Duration
+	// NotifyUser and NotifyOrgAdmins send in-app notifications to the user and to the admins of the organization
+	NotifyUser      bool
+	NotifyOrgAdmins bool
+}
+
+func readLoginAnomalySettings(iniFile *ini.File) LoginAnomalySettings {
+	s := LoginAnomalySettings{}
+
+	section := iniFile.Section("login_anomaly")
+	s.Enabled = section.Key("enabled").MustBool(false)
+	s.DefaultSensitivity = section.Key("default_sensitivity").In("medium", []string{"off", "low", "medium", "high"})
+	s.WebhookURL = section.Key("webhook_url").MustString("")
+	s.WebhookTimeout = section.Key("webhook_timeout").MustDuration(10 * time.Second)
+	s.NotifyUser = section.Key("notify_user").MustBool(true)
+	s.NotifyOrgAdmins = section.Key("notify_org_admins").MustBool(false)
+	return s
+}
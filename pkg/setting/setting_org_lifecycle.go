@@ -0,0 +1,25 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// OrgLifecycleSettings configures the hooks that are executed when an organization is created
+type OrgLifecycleSettings struct {
+	// TemplateFile is a YAML file with the folders, teams and data sources to create in new organizations
+	TemplateFile   string
+	WebhookURL     string
+	WebhookTimeout time.Duration
+}
+
+func readOrgLifecycleSettings(iniFile *ini.File) OrgLifecycleSettings {
+	s := OrgLifecycleSettings{}
+
+	section := iniFile.Section("org_lifecycle")
+	s.TemplateFile = section.Key("template_file").MustString("")
+	s.WebhookURL = section.Key("webhook_url").MustString("")
+	s.WebhookTimeout = section.Key("webhook_timeout").MustDuration(10 * time.Second)
+	return s
+}
@@ -0,0 +1,30 @@
+package setting
+
+import (
+	"gopkg.in/ini.v1"
+)
+
+// PermissionChangeNotificationsSettings configures the notifications sent when the permissions
+// of a dashboard or folder change.
+type PermissionChangeNotificationsSettings struct {
+	// WebhookURL receives the permissions before and after each change when set
+	WebhookURL string
+	// Email notifies the owners of the dashboard or folder by email
+	Email bool
+	// InApp notifies the owners of the dashboard or folder in Grafana
+	InApp bool
+}
+
+// Enabled returns true when the permission changes have to be computed for at least one notification
+func (s PermissionChangeNotificationsSettings) Enabled() bool {
+	return s.WebhookURL != "" || s.Email || s.InApp
+}
+
+func readPermissionChangeNotificationsSettings(iniFile *ini.File) PermissionChangeNotificationsSettings {
+	section := iniFile.Section("permission_change_notifications")
+	return PermissionChangeNotificationsSettings{
+		WebhookURL: section.Key("webhook_url").MustString(""),
+		Email:      section.Key("email").MustBool(false),
+		InApp:      section.Key("in_app").MustBool(false),
+	}
+}
@@ -8,6 +8,11 @@ type OrgQuota struct {
 	AlertRule  int64 `target:"alert_rule"`
 }
 
+// FolderQuota limits the resources stored directly in a folder, it isn't a target of the quota service
+type FolderQuota struct {
+	AlertRule int64
+}
+
 type UserQuota struct {
 	Org int64 `target:"org_user"`
 }
@@ -28,9 +33,18 @@ type QuotaSettings struct {
 	Enabled bool
 	Org     OrgQuota
 	User    UserQuota
+	Folder  FolderQuota
 	Global  GlobalQuota
 }
 
+// FolderAlertRuleLimit returns the maximum number of alert rules in a folder, or -1 when quotas are disabled
+func (q QuotaSettings) FolderAlertRuleLimit() int64 {
+	if !q.Enabled {
+		return -1
+	}
+	return q.Folder.AlertRule
+}
+
 func (cfg *Cfg) readQuotaSettings() {
 	// set global defaults.
 	quota := cfg.Raw.Section("quota")
@@ -50,6 +64,11 @@ func (cfg *Cfg) readQuotaSettings() {
 		Org: quota.Key("user_org").MustInt64(10),
 	}
 
+	// per Folder limits
+	cfg.Quota.Folder = FolderQuota{
+		AlertRule: quota.Key("folder_alert_rule").MustInt64(-1),
+	}
+
 	// Global Limits
 	cfg.Quota.Global = GlobalQuota{
 		User:         quota.Key("global_user").MustInt64(-1),
@@ -24,6 +24,15 @@ const (
 	windows = "windows"
 )
 
+func TestMain(m *testing.M) {
+	// The configurations are loaded with ../../ as home path, logging to a file would write
+	// data/log/grafana.log in the repository.
+	if err := os.Setenv("GF_LOG_MODE", "console"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
 func TestLoadingSettings(t *testing.T) {
 	skipStaticRootValidation = true
 
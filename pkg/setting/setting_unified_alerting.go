@@ -53,6 +53,8 @@ const (
 	screenshotsDefaultCaptureTimeout        = 10 * time.Second
 	screenshotsMaxCaptureTimeout            = 30 * time.Second
 	screenshotsDefaultMaxConcurrent         = 5
+	screenshotsDefaultCacheTTL              = time.Minute
+	screenshotsMaxCacheTTL                  = time.Hour
 	screenshotsDefaultUploadImageStorage    = false
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
@@ -119,6 +121,8 @@ type UnifiedAlertingScreenshotSettings struct {
 	CaptureTimeout             time.Duration
 	MaxConcurrentScreenshots   int64
 	UploadExternalImageStorage bool
+	// CacheTTL is how long the screenshot of a panel is re-used by the notifications of all the alert rules of the panel
+	CacheTTL time.Duration
 }
 
 type UnifiedAlertingReservedLabelSettings struct {
@@ -383,6 +387,10 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfgScreenshots.MaxConcurrentScreenshots = screenshots.Key("max_concurrent_screenshots").MustInt64(screenshotsDefaultMaxConcurrent)
 	uaCfgScreenshots.UploadExternalImageStorage = screenshots.Key("upload_external_image_storage").MustBool(screenshotsDefaultUploadImageStorage)
+	uaCfgScreenshots.CacheTTL = screenshots.Key("cache_ttl").MustDuration(screenshotsDefaultCacheTTL)
+	if uaCfgScreenshots.CacheTTL <= 0 || uaCfgScreenshots.CacheTTL > screenshotsMaxCacheTTL {
+		return fmt.Errorf("value of setting 'cache_ttl' must be greater than 0 and cannot exceed %s", screenshotsMaxCacheTTL)
+	}
 	uaCfg.Screenshots = uaCfgScreenshots
 
 	reservedLabels := iniFile.Section("unified_alerting.reserved_labels")
@@ -0,0 +1,42 @@
+package signedtoken
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClaims struct {
+	UID     string `json:"uid"`
+	Expires int64  `json:"exp"`
+}
+
+func TestSignedToken(t *testing.T) {
+	token, err := Sign("secret", "test", testClaims{UID: "abc", Expires: 42})
+	require.NoError(t, err)
+
+	t.Run("should decode the claims of a valid token", func(t *testing.T) {
+		var c testClaims
+		require.NoError(t, Verify("secret", "test", token, &c))
+		assert.Equal(t, testClaims{UID: "abc", Expires: 42}, c)
+	})
+
+	t.Run("should reject the tokens of another secret key or purpose", func(t *testing.T) {
+		var c testClaims
+		assert.ErrorIs(t, Verify("other", "test", token, &c), ErrInvalidToken)
+		assert.ErrorIs(t, Verify("secret", "other", token, &c), ErrInvalidToken)
+	})
+
+	t.Run("should reject the tampered or malformed tokens", func(t *testing.T) {
+		_, signature, _ := strings.Cut(token, ".")
+		tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"uid":"def","exp":42}`)) + "." + signature
+
+		var c testClaims
+		assert.ErrorIs(t, Verify("secret", "test", tampered, &c), ErrInvalidToken)
+		assert.ErrorIs(t, Verify("secret", "test", strings.Replace(token, ".", "", 1), &c), ErrInvalidToken)
+		assert.ErrorIs(t, Verify("secret", "test", token+"!", &c), ErrInvalidToken)
+	})
+}